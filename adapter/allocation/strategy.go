@@ -0,0 +1,106 @@
+package allocation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// Allocation strategy names
+const (
+	StrategyNearest   = "nearest"
+	StrategyMostStock = "most_stock"
+	StrategySplit     = "split"
+)
+
+// NewStrategy creates the allocation strategy with the given name, defaulting to split
+func NewStrategy(name string) (repo.IAllocationStrategy, error) {
+	switch name {
+	case StrategyNearest:
+		return NearestStrategy{}, nil
+	case StrategyMostStock:
+		return MostStockStrategy{}, nil
+	case StrategySplit, "":
+		return SplitStrategy{}, nil
+	}
+	return nil, fmt.Errorf("unknown allocation strategy %q", name)
+}
+
+// NearestStrategy fulfills an item from the single nearest warehouse holding enough stock.
+// Without a destination it behaves like MostStockStrategy.
+type NearestStrategy struct{}
+
+// Allocate picks the nearest warehouse that can cover the whole quantity
+func (NearestStrategy) Allocate(ctx context.Context, request model.AllocationRequest) ([]model.StockAllocation, error) {
+	for _, candidate := range rank(request) {
+		if candidate.Available >= request.Quantity {
+			return []model.StockAllocation{{WarehouseID: candidate.Warehouse.ID, Quantity: request.Quantity}}, nil
+		}
+	}
+	return nil, model.ErrProductInsufficientStock
+}
+
+// MostStockStrategy fulfills an item from the single warehouse holding the most stock
+type MostStockStrategy struct{}
+
+// Allocate picks the warehouse with the most stock when it can cover the whole quantity
+func (MostStockStrategy) Allocate(ctx context.Context, request model.AllocationRequest) ([]model.StockAllocation, error) {
+	ranked := rank(model.AllocationRequest{Quantity: request.Quantity, Candidates: request.Candidates})
+	if len(ranked) == 0 || ranked[0].Available < request.Quantity {
+		return nil, model.ErrProductInsufficientStock
+	}
+	return []model.StockAllocation{{WarehouseID: ranked[0].Warehouse.ID, Quantity: request.Quantity}}, nil
+}
+
+// SplitStrategy spreads an item over as many warehouses as needed, nearest first,
+// or those with the most stock first without a destination
+type SplitStrategy struct{}
+
+// Allocate takes stock from the ranked warehouses until the quantity is covered
+func (SplitStrategy) Allocate(ctx context.Context, request model.AllocationRequest) ([]model.StockAllocation, error) {
+	var allocations []model.StockAllocation
+	remaining := request.Quantity
+	for _, candidate := range rank(request) {
+		if remaining == 0 {
+			break
+		}
+		quantity := min(candidate.Available, remaining)
+		allocations = append(allocations, model.StockAllocation{WarehouseID: candidate.Warehouse.ID, Quantity: quantity})
+		remaining -= quantity
+	}
+
+	if remaining > 0 {
+		return nil, model.ErrProductInsufficientStock
+	}
+	return allocations, nil
+}
+
+// rank orders the active candidates with stock by distance to the destination, or by stock when
+// there is no destination. Ties are broken by warehouse code so allocations are deterministic.
+func rank(request model.AllocationRequest) []model.WarehouseStock {
+	ranked := make([]model.WarehouseStock, 0, len(request.Candidates))
+	for _, candidate := range request.Candidates {
+		if candidate.Warehouse != nil && candidate.Warehouse.Active && candidate.Available > 0 {
+			ranked = append(ranked, candidate)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if request.Destination != nil {
+			da := a.Warehouse.Location.DistanceTo(*request.Destination)
+			db := b.Warehouse.Location.DistanceTo(*request.Destination)
+			if da != db {
+				return da < db
+			}
+		} else if a.Available != b.Available {
+			return a.Available > b.Available
+		}
+		return a.Warehouse.Code < b.Warehouse.Code
+	})
+
+	return ranked
+}
@@ -0,0 +1,95 @@
+package allocation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+var (
+	saoPaulo = &model.Warehouse{ID: "sp", Code: "SP", Active: true, Location: model.Location{Latitude: -23.55, Longitude: -46.63}}
+	rio      = &model.Warehouse{ID: "rj", Code: "RJ", Active: true, Location: model.Location{Latitude: -22.91, Longitude: -43.17}}
+	recife   = &model.Warehouse{ID: "pe", Code: "PE", Active: true, Location: model.Location{Latitude: -8.05, Longitude: -34.88}}
+	closed   = &model.Warehouse{ID: "off", Code: "OFF", Active: false, Location: model.Location{Latitude: -22.90, Longitude: -43.20}}
+
+	// Near Rio de Janeiro
+	destination = &model.Location{Latitude: -22.95, Longitude: -43.20}
+)
+
+func request(quantity int, destination *model.Location) model.AllocationRequest {
+	return model.AllocationRequest{
+		Quantity:    quantity,
+		Destination: destination,
+		Candidates: []model.WarehouseStock{
+			{Warehouse: saoPaulo, Available: 10},
+			{Warehouse: rio, Available: 3},
+			{Warehouse: recife, Available: 20},
+			{Warehouse: closed, Available: 100},
+		},
+	}
+}
+
+func TestNearestStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	allocations, err := NearestStrategy{}.Allocate(ctx, request(2, destination))
+	assert.NoError(t, err)
+	assert.Equal(t, []model.StockAllocation{{WarehouseID: "rj", Quantity: 2}}, allocations)
+
+	// Rio cannot cover it, the next nearest can
+	allocations, err = NearestStrategy{}.Allocate(ctx, request(5, destination))
+	assert.NoError(t, err)
+	assert.Equal(t, []model.StockAllocation{{WarehouseID: "sp", Quantity: 5}}, allocations)
+
+	// Without a destination the warehouse with the most stock wins
+	allocations, err = NearestStrategy{}.Allocate(ctx, request(5, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []model.StockAllocation{{WarehouseID: "pe", Quantity: 5}}, allocations)
+
+	// No single active warehouse can cover it
+	_, err = NearestStrategy{}.Allocate(ctx, request(25, destination))
+	assert.ErrorIs(t, err, model.ErrProductInsufficientStock)
+}
+
+func TestMostStockStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	allocations, err := MostStockStrategy{}.Allocate(ctx, request(5, destination))
+	assert.NoError(t, err)
+	assert.Equal(t, []model.StockAllocation{{WarehouseID: "pe", Quantity: 5}}, allocations)
+
+	_, err = MostStockStrategy{}.Allocate(ctx, request(21, nil))
+	assert.ErrorIs(t, err, model.ErrProductInsufficientStock)
+}
+
+func TestSplitStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	allocations, err := SplitStrategy{}.Allocate(ctx, request(15, destination))
+	assert.NoError(t, err)
+	assert.Equal(t, []model.StockAllocation{
+		{WarehouseID: "rj", Quantity: 3},
+		{WarehouseID: "sp", Quantity: 10},
+		{WarehouseID: "pe", Quantity: 2},
+	}, allocations)
+
+	// Inactive warehouses are never allocated from
+	_, err = SplitStrategy{}.Allocate(ctx, request(34, destination))
+	assert.ErrorIs(t, err, model.ErrProductInsufficientStock)
+}
+
+func TestNewStrategy(t *testing.T) {
+	strategy, err := NewStrategy("")
+	assert.NoError(t, err)
+	assert.IsType(t, SplitStrategy{}, strategy)
+
+	strategy, err = NewStrategy(StrategyNearest)
+	assert.NoError(t, err)
+	assert.IsType(t, NearestStrategy{}, strategy)
+
+	_, err = NewStrategy("random")
+	assert.Error(t, err)
+}
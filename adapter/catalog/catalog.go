@@ -0,0 +1,50 @@
+package catalog
+
+import (
+	"io"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// Column names of product files, which are also the NDJSON field names
+const (
+	ColumnSKU         = "sku"
+	ColumnName        = "name"
+	ColumnDescription = "description"
+	ColumnPrice       = "price"
+	ColumnStock       = "stock"
+)
+
+// columns is the column order of exported CSV files
+var columns = []string{ColumnSKU, ColumnName, ColumnDescription, ColumnPrice, ColumnStock}
+
+// NewReader creates a reader of product files in the given format
+func NewReader(format model.ProductFileFormat, r io.Reader) (repo.IProductRowReader, error) {
+	switch format {
+	case model.ProductFileFormatCSV:
+		return NewCSVReader(r), nil
+	case model.ProductFileFormatNDJSON:
+		return NewNDJSONReader(r), nil
+	}
+	return nil, model.ErrProductFileFormatInvalid
+}
+
+// NewWriter creates a writer of product files in the given format
+func NewWriter(format model.ProductFileFormat, w io.Writer) (repo.IProductRowWriter, error) {
+	switch format {
+	case model.ProductFileFormatCSV:
+		return NewCSVWriter(w), nil
+	case model.ProductFileFormatNDJSON:
+		return NewNDJSONWriter(w), nil
+	}
+	return nil, model.ErrProductFileFormatInvalid
+}
+
+// ContentType returns the media type of a product file format
+func ContentType(format model.ProductFileFormat) string {
+	if format == model.ProductFileFormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv"
+}
@@ -0,0 +1,98 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+func readAll(t *testing.T, reader repo.IProductRowReader) []*model.ProductRow {
+	var rows []*model.ProductRow
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return rows
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	input := "Name,Price,Stock,SKU,Notes\n" +
+		"Mug,12.5,10,,ignored\n" +
+		"\"Shirt, blue\",30,,SHIRT-M,\n" +
+		"Pen,cheap,1,,\n" +
+		"Cap,10\n"
+
+	rows := readAll(t, NewCSVReader(strings.NewReader(input)))
+	require.Len(t, rows, 4)
+
+	assert.Equal(t, model.ProductRow{Line: 2, Name: "Mug", Price: 12.5, Stock: 10}, *rows[0])
+	assert.Equal(t, model.ProductRow{Line: 3, SKU: "SHIRT-M", Name: "Shirt, blue", Price: 30}, *rows[1])
+	assert.Equal(t, 4, rows[2].Line)
+	assert.EqualError(t, rows[2].Err, `invalid price "cheap"`)
+	assert.Equal(t, 5, rows[3].Line)
+	assert.ErrorIs(t, rows[3].Err, csv.ErrFieldCount)
+}
+
+func TestCSVReaderRequiresColumns(t *testing.T) {
+	_, err := NewCSVReader(strings.NewReader("name,stock\nMug,1\n")).Read()
+	assert.EqualError(t, err, "CSV header has no price column")
+}
+
+func TestNDJSONReader(t *testing.T) {
+	input := `{"name":"Mug","price":12.5,"stock":10}` + "\n" +
+		"\n" +
+		`{"sku":"SHIRT-M","name":"Shirt","price":"30"}` + "\n" +
+		`{"name":` + "\n"
+
+	rows := readAll(t, NewNDJSONReader(strings.NewReader(input)))
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, model.ProductRow{Line: 1, Name: "Mug", Price: 12.5, Stock: 10}, *rows[0])
+	assert.Equal(t, model.ProductRow{Line: 3, SKU: "SHIRT-M", Name: "Shirt", Price: 30}, *rows[1])
+	assert.Equal(t, 4, rows[2].Line)
+	assert.Error(t, rows[2].Err)
+}
+
+func TestWritersRoundTrip(t *testing.T) {
+	written := []model.ProductRow{
+		{Name: "Mug", Description: "Ceramic", Price: 12.5, Stock: 10},
+		{SKU: "SHIRT-M", Name: "Shirt, blue", Price: 30, Stock: 2},
+	}
+
+	for _, format := range []model.ProductFileFormat{model.ProductFileFormatCSV, model.ProductFileFormatNDJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := NewWriter(format, &buf)
+			require.NoError(t, err)
+			for _, row := range written {
+				require.NoError(t, writer.Write(row))
+			}
+			require.NoError(t, writer.Flush())
+
+			reader, err := NewReader(format, &buf)
+			require.NoError(t, err)
+			rows := readAll(t, reader)
+			require.Len(t, rows, len(written))
+			for i, row := range rows {
+				row.Line = 0
+				assert.Equal(t, written[i], *row)
+			}
+		})
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewReader("xml", strings.NewReader(""))
+	assert.ErrorIs(t, err, model.ErrProductFileFormatInvalid)
+}
@@ -0,0 +1,143 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// CSVReader reads product rows from a CSV file whose first line names the columns.
+// Columns may come in any order and unknown columns are ignored; name and price are required.
+type CSVReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+// NewCSVReader creates a new CSV product reader
+func NewCSVReader(r io.Reader) *CSVReader {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+	return &CSVReader{reader: reader}
+}
+
+// Read returns the next row, or io.EOF after the last one
+func (r *CSVReader) Read() (*model.ProductRow, error) {
+	if r.columns == nil {
+		if err := r.readHeader(); err != nil {
+			return nil, err
+		}
+	}
+
+	record, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return &model.ProductRow{Line: parseErr.StartLine, Err: parseErr.Err}, nil
+		}
+		return nil, err
+	}
+	line, _ := r.reader.FieldPos(0)
+
+	row := &model.ProductRow{
+		Line:        line,
+		SKU:         r.field(record, ColumnSKU),
+		Name:        r.field(record, ColumnName),
+		Description: r.field(record, ColumnDescription),
+	}
+	row.Price, row.Stock, row.Err = parseNumbers(r.field(record, ColumnPrice), r.field(record, ColumnStock))
+	return row, nil
+}
+
+func (r *CSVReader) readHeader() error {
+	header, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	r.columns = make(map[string]int, len(header))
+	for i, column := range header {
+		r.columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, required := range []string{ColumnName, ColumnPrice} {
+		if _, ok := r.columns[required]; !ok {
+			return model.NewDomainError(model.CodeValidationError, fmt.Sprintf("CSV header has no %s column", required), http.StatusBadRequest)
+		}
+	}
+	return nil
+}
+
+func (r *CSVReader) field(record []string, column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// parseNumbers parses the price and the stock of a row; an empty stock is zero
+func parseNumbers(price, stock string) (float64, int, error) {
+	parsedPrice, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid price %q", price)
+	}
+	if stock == "" {
+		return parsedPrice, 0, nil
+	}
+	parsedStock, err := strconv.Atoi(stock)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid stock %q", stock)
+	}
+	return parsedPrice, parsedStock, nil
+}
+
+// CSVWriter writes product rows to a CSV file with a header line
+type CSVWriter struct {
+	writer      *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSVWriter creates a new CSV product writer
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{writer: csv.NewWriter(w)}
+}
+
+// Write writes a row, preceded by the header on the first call
+func (w *CSVWriter) Write(row model.ProductRow) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.writer.Write([]string{
+		row.SKU,
+		row.Name,
+		row.Description,
+		strconv.FormatFloat(row.Price, 'f', -1, 64),
+		strconv.Itoa(row.Stock),
+	})
+}
+
+// Flush writes any buffered rows, and the header of a file without rows
+func (w *CSVWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *CSVWriter) writeHeader() error {
+	if w.wroteHeader {
+		return nil
+	}
+	w.wroteHeader = true
+	return w.writer.Write(columns)
+}
@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// maxNDJSONLineSize bounds the size of a single NDJSON line
+const maxNDJSONLineSize = 1 << 20
+
+// ndjsonRow is a product row in an NDJSON file
+type ndjsonRow struct {
+	SKU         string      `json:"sku,omitempty"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Price       json.Number `json:"price"`
+	Stock       json.Number `json:"stock"`
+}
+
+// NDJSONReader reads product rows from a file with one JSON object per line; blank lines are skipped
+type NDJSONReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewNDJSONReader creates a new NDJSON product reader
+func NewNDJSONReader(r io.Reader) *NDJSONReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+	return &NDJSONReader{scanner: scanner}
+}
+
+// Read returns the next row, or io.EOF after the last one
+func (r *NDJSONReader) Read() (*model.ProductRow, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var parsed ndjsonRow
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		if err := decoder.Decode(&parsed); err != nil {
+			return &model.ProductRow{Line: r.line, Err: fmt.Errorf("invalid JSON: %v", err)}, nil
+		}
+
+		row := &model.ProductRow{
+			Line:        r.line,
+			SKU:         parsed.SKU,
+			Name:        parsed.Name,
+			Description: parsed.Description,
+		}
+		row.Price, row.Stock, row.Err = parseNumbers(parsed.Price.String(), parsed.Stock.String())
+		return row, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read NDJSON line %d: %w", r.line+1, err)
+	}
+	return nil, io.EOF
+}
+
+// NDJSONWriter writes product rows as one JSON object per line
+type NDJSONWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+// NewNDJSONWriter creates a new NDJSON product writer
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	buffer := bufio.NewWriter(w)
+	return &NDJSONWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}
+}
+
+// Write writes a row
+func (w *NDJSONWriter) Write(row model.ProductRow) error {
+	return w.encoder.Encode(ndjsonRow{
+		SKU:         row.SKU,
+		Name:        row.Name,
+		Description: row.Description,
+		Price:       json.Number(strconv.FormatFloat(row.Price, 'f', -1, 64)),
+		Stock:       json.Number(strconv.Itoa(row.Stock)),
+	})
+}
+
+// Flush writes any buffered rows
+func (w *NDJSONWriter) Flush() error {
+	return w.buffer.Flush()
+}
@@ -8,14 +8,22 @@ package dependency
 
 import (
 	"context"
+	"time"
 
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/allocation"
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/notifier"
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/password"
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/payment"
 	"cactus-golang-hexagonal-microservice-boilerplate/adapter/repository"
 	"cactus-golang-hexagonal-microservice-boilerplate/adapter/repository/dynamodb"
 	"cactus-golang-hexagonal-microservice-boilerplate/adapter/repository/mongo"
 	"cactus-golang-hexagonal-microservice-boilerplate/adapter/repository/postgre"
 	"cactus-golang-hexagonal-microservice-boilerplate/adapter/repository/redis"
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/shipping"
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/token"
 	"cactus-golang-hexagonal-microservice-boilerplate/config"
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
 )
 
@@ -30,7 +38,60 @@ func WithUserService() ServiceOption {
 			txFactory := repository.NewTransactionFactory(map[repository.StoreType]any{
 				repository.PostgreSQLStore: c.PostgreSQL,
 			})
-			s.UserService = service.NewUserService(userRepo, txFactory, eventBus)
+			s.UserService = service.NewUserService(userRepo, txFactory, providePasswordHasher(), provideRefreshSessionStore(c), eventBus)
+		}
+	}
+}
+
+// WithAuthService returns an option to initialize the Auth service, storing sessions and failed
+// logins in Redis.
+// It must be applied after the User service option.
+func WithAuthService() ServiceOption {
+	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
+		cfg := config.GlobalConfig.Auth
+		if s.AuthService == nil && cfg != nil && cfg.Enabled && c.Redis != nil && s.UserService != nil {
+			redisClient, err := redis.NewClientFromConfig(config.GlobalConfig.Redis)
+			if err != nil {
+				// Protected endpoints answer 503 until Redis is reachable
+				return
+			}
+			signer, err := token.NewJWTSigner(cfg.Secret, cfg.Issuer)
+			if err != nil {
+				panic("Failed to initialize token signer: " + err.Error())
+			}
+			if lockoutCfg := config.GlobalConfig.Lockout; lockoutCfg != nil && lockoutCfg.Enabled {
+				s.LockoutService = service.NewLockoutService(s.UserService, redis.NewLoginAttemptStore(redisClient), provideLockoutPolicy(lockoutCfg), eventBus)
+			}
+			accessTTL, refreshTTL := provideTokenTTLs(cfg)
+			s.AuthService = service.NewAuthService(s.UserService, signer, redis.NewRefreshSessionStore(redisClient), s.LockoutService, accessTTL, refreshTTL)
+		}
+	}
+}
+
+// WithAPIKeyService returns an option to initialize the API key service when authentication is enabled
+func WithAPIKeyService() ServiceOption {
+	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
+		cfg := config.GlobalConfig.Auth
+		if s.APIKeyService == nil && cfg != nil && cfg.Enabled && c.PostgreSQL != nil {
+			s.APIKeyService = service.NewAPIKeyService(postgre.NewAPIKeyRepository(c.PostgreSQL.DB), eventBus)
+		}
+	}
+}
+
+// WithAccountService returns an option to initialize email verification and password reset and
+// subscribe it to created users. It must be applied after the User service option.
+func WithAccountService() ServiceOption {
+	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
+		if s.AccountService == nil && c.PostgreSQL != nil && s.UserService != nil {
+			var limiter repo.IRateLimiter
+			if c.Redis != nil {
+				if redisClient, err := redis.NewClientFromConfig(config.GlobalConfig.Redis); err == nil {
+					limiter = redis.NewRateLimiter(redisClient)
+				}
+			}
+			tokenRepo := postgre.NewUserTokenRepository(c.PostgreSQL.DB)
+			s.AccountService = service.NewAccountService(s.UserService, tokenRepo, provideNotifier(), limiter, provideAccountPolicy())
+			eventBus.Subscribe(service.NewAccountEventHandler(s.AccountService))
 		}
 	}
 }
@@ -42,22 +103,149 @@ func WithProductService() ServiceOption {
 			// Get or create MongoDB client
 			if mongoClient, ok := c.MongoDB.Client.(*mongo.Client); ok {
 				productRepo := mongo.NewProductRepository(mongoClient)
-				s.ProductService = service.NewProductService(productRepo, eventBus)
+				categoryRepo := mongo.NewCategoryRepository(mongoClient)
+				movementRepo := mongo.NewStockMovementRepository(mongoClient)
+				priceRepo := mongo.NewPriceHistoryRepository(mongoClient)
+				warehouseRepo := mongo.NewWarehouseRepository(mongoClient)
+				s.ProductService = service.NewProductService(productRepo, categoryRepo, movementRepo, priceRepo, warehouseRepo, provideAllocationStrategy(), eventBus)
 			}
 		}
 	}
 }
 
-// WithOrderService returns an option to initialize the Order service
+// WithCategoryService returns an option to initialize the Category service.
+// It must be applied after the Product service option.
+func WithCategoryService() ServiceOption {
+	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
+		if s.CategoryService == nil && c.MongoDB != nil && s.ProductService != nil {
+			if mongoClient, ok := c.MongoDB.Client.(*mongo.Client); ok {
+				categoryRepo := mongo.NewCategoryRepository(mongoClient)
+				s.CategoryService = service.NewCategoryService(categoryRepo, s.ProductService, eventBus)
+			}
+		}
+	}
+}
+
+// WithWarehouseService returns an option to initialize the Warehouse service
+func WithWarehouseService() ServiceOption {
+	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
+		if s.WarehouseService == nil && c.MongoDB != nil {
+			if mongoClient, ok := c.MongoDB.Client.(*mongo.Client); ok {
+				warehouseRepo := mongo.NewWarehouseRepository(mongoClient)
+				s.WarehouseService = service.NewWarehouseService(warehouseRepo, eventBus)
+			}
+		}
+	}
+}
+
+// WithReservationService returns an option to initialize the Reservation service, holding stock in Redis.
+// It must be applied after the Product service option and before the Order service option.
+func WithReservationService() ServiceOption {
+	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
+		if s.ReservationService == nil && c.Redis != nil && s.ProductService != nil {
+			redisClient, err := redis.NewClientFromConfig(config.GlobalConfig.Redis)
+			if err != nil {
+				// Orders fall back to reserving stock when they are created
+				return
+			}
+			s.ReservationService = service.NewReservationService(redis.NewStockHoldStore(redisClient), s.ProductService, provideHoldTTL(), eventBus)
+		}
+	}
+}
+
+// WithOrganizationService returns an option to initialize the Organization service
+func WithOrganizationService() ServiceOption {
+	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
+		if s.OrganizationService == nil && c.PostgreSQL != nil {
+			organizationRepo := postgre.NewOrganizationRepository(c.PostgreSQL.DB)
+			userRepo := postgre.NewUserRepository(c.PostgreSQL.DB)
+			orderRepo := postgre.NewOrderRepository(c.PostgreSQL.DB)
+			s.OrganizationService = service.NewOrganizationService(organizationRepo, userRepo, orderRepo, provideNotifier(), eventBus)
+			eventBus.Subscribe(service.NewOrganizationEventHandler(s.OrganizationService))
+		}
+	}
+}
+
+// WithUserAddressService returns an option to initialize the UserAddress service
+func WithUserAddressService() ServiceOption {
+	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
+		if s.UserAddressService == nil && c.PostgreSQL != nil {
+			addressRepo := postgre.NewUserAddressRepository(c.PostgreSQL.DB)
+			userRepo := postgre.NewUserRepository(c.PostgreSQL.DB)
+			s.UserAddressService = service.NewUserAddressService(addressRepo, userRepo, eventBus)
+		}
+	}
+}
+
+// WithOrderService returns an option to initialize the Order service.
+// It must be applied after the Product and Reservation service options for orders to reserve stock.
 func WithOrderService() ServiceOption {
 	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
 		if s.OrderService == nil && c.PostgreSQL != nil {
 			orderRepo := postgre.NewOrderRepository(c.PostgreSQL.DB)
 			userRepo := postgre.NewUserRepository(c.PostgreSQL.DB)
+			organizationRepo := postgre.NewOrganizationRepository(c.PostgreSQL.DB)
+			addressRepo := postgre.NewUserAddressRepository(c.PostgreSQL.DB)
+			shipmentRepo := postgre.NewShipmentRepository(c.PostgreSQL.DB)
 			txFactory := repository.NewTransactionFactory(map[repository.StoreType]any{
 				repository.PostgreSQLStore: c.PostgreSQL,
 			})
-			s.OrderService = service.NewOrderService(orderRepo, userRepo, txFactory, eventBus)
+			s.OrderService = service.NewOrderService(orderRepo, userRepo, organizationRepo, addressRepo, shipmentRepo, s.ProductService, s.ReservationService, txFactory, eventBus)
+		}
+	}
+}
+
+// WithPaymentService returns an option to initialize the Payment service and subscribe it to
+// cancelled orders. It must be applied after the Order service option.
+func WithPaymentService() ServiceOption {
+	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
+		if s.PaymentService == nil && c.PostgreSQL != nil && s.OrderService != nil {
+			paymentRepo := postgre.NewPaymentRepository(c.PostgreSQL.DB)
+			orderRepo := postgre.NewOrderRepository(c.PostgreSQL.DB)
+			s.PaymentService = service.NewPaymentService(paymentRepo, orderRepo, s.OrderService, providePaymentGateway(), eventBus)
+			eventBus.Subscribe(service.NewPaymentEventHandler(s.PaymentService))
+		}
+	}
+}
+
+// WithReturnService returns an option to initialize the Return service.
+// It must be applied after the Product and Payment service options.
+func WithReturnService() ServiceOption {
+	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
+		if s.ReturnService == nil && c.PostgreSQL != nil && s.ProductService != nil {
+			returnRepo := postgre.NewReturnRepository(c.PostgreSQL.DB)
+			refundRepo := postgre.NewRefundRepository(c.PostgreSQL.DB)
+			orderRepo := postgre.NewOrderRepository(c.PostgreSQL.DB)
+			s.ReturnService = service.NewReturnService(returnRepo, refundRepo, orderRepo, s.ProductService, s.PaymentService, eventBus)
+		}
+	}
+}
+
+// WithShipmentService returns an option to initialize the Shipment service
+func WithShipmentService() ServiceOption {
+	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
+		if s.ShipmentService == nil && c.PostgreSQL != nil {
+			shipmentRepo := postgre.NewShipmentRepository(c.PostgreSQL.DB)
+			orderRepo := postgre.NewOrderRepository(c.PostgreSQL.DB)
+			s.ShipmentService = service.NewShipmentService(shipmentRepo, orderRepo, shipping.NewStaticRateTable(nil), eventBus)
+		}
+	}
+}
+
+// WithInvoiceService returns an option to initialize the Invoice service and subscribe it to
+// delivered orders and refunded returns. It must be applied after the Product service option.
+func WithInvoiceService() ServiceOption {
+	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
+		if s.InvoiceService == nil && c.PostgreSQL != nil {
+			invoiceRepo := postgre.NewInvoiceRepository(c.PostgreSQL.DB)
+			orderRepo := postgre.NewOrderRepository(c.PostgreSQL.DB)
+			returnRepo := postgre.NewReturnRepository(c.PostgreSQL.DB)
+			var taxRate float64
+			if config.GlobalConfig.Invoice != nil {
+				taxRate = config.GlobalConfig.Invoice.TaxRate
+			}
+			s.InvoiceService = service.NewInvoiceService(invoiceRepo, orderRepo, returnRepo, s.ProductService, taxRate, eventBus)
+			eventBus.Subscribe(service.NewInvoiceEventHandler(s.InvoiceService))
 		}
 	}
 }
@@ -71,7 +259,7 @@ func WithCachedUserService() ServiceOption {
 			txFactory := repository.NewTransactionFactory(map[repository.StoreType]any{
 				repository.PostgreSQLStore: c.PostgreSQL,
 			})
-			baseService := service.NewUserService(userRepo, txFactory, eventBus)
+			baseService := service.NewUserService(userRepo, txFactory, providePasswordHasher(), provideRefreshSessionStore(c), eventBus)
 
 			// Create Redis client and enhanced cache
 			redisClient, err := redis.NewClientFromConfig(config.GlobalConfig.Redis)
@@ -88,6 +276,29 @@ func WithCachedUserService() ServiceOption {
 	}
 }
 
+// WithCachedAPIKeyService returns an option to initialize the API key service with Redis caching
+// when authentication is enabled
+func WithCachedAPIKeyService() ServiceOption {
+	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
+		cfg := config.GlobalConfig.Auth
+		if s.APIKeyService == nil && cfg != nil && cfg.Enabled && c.PostgreSQL != nil && c.Redis != nil {
+			baseService := service.NewAPIKeyService(postgre.NewAPIKeyRepository(c.PostgreSQL.DB), eventBus)
+
+			// Create Redis client and enhanced cache
+			redisClient, err := redis.NewClientFromConfig(config.GlobalConfig.Redis)
+			if err != nil {
+				// Fall back to base service without caching
+				s.APIKeyService = baseService
+				return
+			}
+			cache := redis.NewEnhancedCache(redisClient, redis.DefaultCacheOptions())
+
+			// Wrap with caching
+			s.APIKeyService = service.NewCachedAPIKeyService(baseService, cache)
+		}
+	}
+}
+
 // WithCachedProductService returns an option to initialize the Product service with Redis caching
 func WithCachedProductService() ServiceOption {
 	return func(s *service.Services, eventBus event.EventBus, c *repository.ClientContainer) {
@@ -95,7 +306,11 @@ func WithCachedProductService() ServiceOption {
 			if mongoClient, ok := c.MongoDB.Client.(*mongo.Client); ok {
 				// Create base product service
 				productRepo := mongo.NewProductRepository(mongoClient)
-				baseService := service.NewProductService(productRepo, eventBus)
+				categoryRepo := mongo.NewCategoryRepository(mongoClient)
+				movementRepo := mongo.NewStockMovementRepository(mongoClient)
+				priceRepo := mongo.NewPriceHistoryRepository(mongoClient)
+				warehouseRepo := mongo.NewWarehouseRepository(mongoClient)
+				baseService := service.NewProductService(productRepo, categoryRepo, movementRepo, priceRepo, warehouseRepo, provideAllocationStrategy(), eventBus)
 
 				// Create Redis client and enhanced cache
 				redisClient, err := redis.NewClientFromConfig(config.GlobalConfig.Redis)
@@ -218,6 +433,10 @@ func ProvideMongoDB() (*repository.MongoDB, error) {
 		return nil, err
 	}
 
+	if err := mongo.EnsureIndexes(context.Background(), client); err != nil {
+		return nil, err
+	}
+
 	return &repository.MongoDB{
 		Client:   client,
 		Database: config.GlobalConfig.MongoDB.Database,
@@ -248,6 +467,189 @@ func ProvideRedis() (*repository.Redis, error) {
 	return &repository.Redis{DB: client}, nil
 }
 
+// providePaymentGateway creates the payment gateway configured for the application
+func providePaymentGateway() repo.IPaymentGateway {
+	cfg := config.GlobalConfig.Payment
+	if cfg == nil {
+		cfg = &config.PaymentConfig{}
+	}
+
+	gateway, err := payment.NewGateway(cfg.Provider, cfg.WebhookSecret)
+	if err != nil {
+		panic("Failed to initialize payment gateway: " + err.Error())
+	}
+	return gateway
+}
+
+// provideAllocationStrategy creates the warehouse allocation strategy configured for the application
+func provideAllocationStrategy() repo.IAllocationStrategy {
+	var name string
+	if config.GlobalConfig.Inventory != nil {
+		name = config.GlobalConfig.Inventory.AllocationStrategy
+	}
+
+	strategy, err := allocation.NewStrategy(name)
+	if err != nil {
+		panic("Failed to initialize allocation strategy: " + err.Error())
+	}
+	return strategy
+}
+
+// providePasswordHasher creates the password hasher configured for the application
+func providePasswordHasher() repo.IPasswordHasher {
+	cfg := config.GlobalConfig.Password
+	if cfg == nil {
+		cfg = &config.PasswordConfig{}
+	}
+
+	hasher, err := password.NewHasher(cfg.Algorithm, password.Argon2Params{
+		Memory:      cfg.Argon2Memory,
+		Iterations:  cfg.Argon2Iterations,
+		Parallelism: cfg.Argon2Parallelism,
+	}, cfg.BcryptCost)
+	if err != nil {
+		panic("Failed to initialize password hasher: " + err.Error())
+	}
+	return hasher
+}
+
+// Token lifetimes used when auth.access_ttl or auth.refresh_ttl are not configured
+const (
+	defaultAccessTTL  = 15 * time.Minute
+	defaultRefreshTTL = 7 * 24 * time.Hour
+)
+
+// provideRefreshSessionStore returns the Redis store of refresh sessions, or nil when authentication
+// is disabled or Redis is not available
+func provideRefreshSessionStore(c *repository.ClientContainer) repo.IRefreshSessionStore {
+	cfg := config.GlobalConfig.Auth
+	if cfg == nil || !cfg.Enabled || c.Redis == nil {
+		return nil
+	}
+	redisClient, err := redis.NewClientFromConfig(config.GlobalConfig.Redis)
+	if err != nil {
+		return nil
+	}
+	return redis.NewRefreshSessionStore(redisClient)
+}
+
+// provideTokenTTLs returns how long access and refresh tokens last
+func provideTokenTTLs(cfg *config.AuthConfig) (time.Duration, time.Duration) {
+	accessTTL, refreshTTL := defaultAccessTTL, defaultRefreshTTL
+	if ttl := config.GetDuration(cfg.AccessTTL); ttl > 0 {
+		accessTTL = ttl
+	}
+	if ttl := config.GetDuration(cfg.RefreshTTL); ttl > 0 {
+		refreshTTL = ttl
+	}
+	return accessTTL, refreshTTL
+}
+
+// Lockout policy used when the lockout settings are not configured
+const (
+	defaultMaxAccountFailures = 5
+	defaultMaxIPFailures      = 20
+	defaultFailureWindow      = 15 * time.Minute
+	defaultLockoutDuration    = 15 * time.Minute
+	defaultLoginDelayBase     = 250 * time.Millisecond
+	defaultMaxLoginDelay      = 4 * time.Second
+)
+
+// provideLockoutPolicy returns when failed logins lock out an account or a client address
+func provideLockoutPolicy(cfg *config.LockoutConfig) service.LockoutPolicy {
+	policy := service.LockoutPolicy{
+		MaxAccountFailures: defaultMaxAccountFailures,
+		MaxIPFailures:      defaultMaxIPFailures,
+		FailureWindow:      defaultFailureWindow,
+		Duration:           defaultLockoutDuration,
+		DelayBase:          defaultLoginDelayBase,
+		MaxDelay:           defaultMaxLoginDelay,
+	}
+
+	if cfg.MaxAccountFailures > 0 {
+		policy.MaxAccountFailures = cfg.MaxAccountFailures
+	}
+	if cfg.MaxIPFailures > 0 {
+		policy.MaxIPFailures = cfg.MaxIPFailures
+	}
+	if window := config.GetDuration(cfg.FailureWindow); window > 0 {
+		policy.FailureWindow = window
+	}
+	if duration := config.GetDuration(cfg.Duration); duration > 0 {
+		policy.Duration = duration
+	}
+	if delay := config.GetDuration(cfg.DelayBase); delay > 0 {
+		policy.DelayBase = delay
+	}
+	if delay := config.GetDuration(cfg.MaxDelay); delay > 0 {
+		policy.MaxDelay = delay
+	}
+	return policy
+}
+
+// Account policy used when the account settings are not configured
+const (
+	defaultVerificationTTL  = 24 * time.Hour
+	defaultPasswordResetTTL = time.Hour
+	defaultEmailRateLimit   = 3
+	defaultEmailRateWindow  = time.Hour
+)
+
+// provideAccountPolicy returns how long emailed tokens last and how often they may be sent
+func provideAccountPolicy() service.AccountPolicy {
+	policy := service.AccountPolicy{
+		VerificationTTL:  defaultVerificationTTL,
+		PasswordResetTTL: defaultPasswordResetTTL,
+		EmailRateLimit:   defaultEmailRateLimit,
+		EmailRateWindow:  defaultEmailRateWindow,
+	}
+
+	cfg := config.GlobalConfig.Account
+	if cfg == nil {
+		return policy
+	}
+	if ttl := config.GetDuration(cfg.VerificationTTL); ttl > 0 {
+		policy.VerificationTTL = ttl
+	}
+	if ttl := config.GetDuration(cfg.PasswordResetTTL); ttl > 0 {
+		policy.PasswordResetTTL = ttl
+	}
+	if cfg.EmailRateLimit > 0 {
+		policy.EmailRateLimit = cfg.EmailRateLimit
+	}
+	if window := config.GetDuration(cfg.EmailRateWindow); window > 0 {
+		policy.EmailRateWindow = window
+	}
+	return policy
+}
+
+// provideNotifier creates the notifier sending emails to users
+func provideNotifier() repo.INotifier {
+	cfg := config.GlobalConfig.Notifier
+	if cfg == nil {
+		cfg = &config.NotifierConfig{}
+	}
+
+	n, err := notifier.NewNotifier(cfg.Driver, cfg.FilePath)
+	if err != nil {
+		panic("Failed to initialize notifier: " + err.Error())
+	}
+	return n
+}
+
+// defaultHoldTTL is used when inventory.hold_ttl is not configured
+const defaultHoldTTL = 15 * time.Minute
+
+// provideHoldTTL returns how long stock holds last when no TTL is requested
+func provideHoldTTL() time.Duration {
+	if config.GlobalConfig.Inventory != nil {
+		if ttl := config.GetDuration(config.GlobalConfig.Inventory.HoldTTL); ttl > 0 {
+			return ttl
+		}
+	}
+	return defaultHoldTTL
+}
+
 // provideEventBus creates and configures the event bus
 func provideEventBus() *event.InMemoryEventBus {
 	eventBus := event.NewInMemoryEventBus()
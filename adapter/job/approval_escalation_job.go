@@ -0,0 +1,85 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+const (
+	// ApprovalEscalationJobName is the name of the order approval escalation job
+	ApprovalEscalationJobName = "approval_escalation"
+	// DefaultApprovalEscalationBatchSize is the batch size used when none is configured
+	DefaultApprovalEscalationBatchSize = 100
+)
+
+// ApprovalEscalationJob escalates orders that awaited approval for longer than escalateAfter to
+// the admins of their organization
+type ApprovalEscalationJob struct {
+	orderService  service.IOrderService
+	escalateAfter time.Duration
+	batchSize     int
+}
+
+// NewApprovalEscalationJob creates a new order approval escalation job
+func NewApprovalEscalationJob(orderService service.IOrderService, escalateAfter time.Duration, batchSize int) *ApprovalEscalationJob {
+	if batchSize <= 0 {
+		batchSize = DefaultApprovalEscalationBatchSize
+	}
+	return &ApprovalEscalationJob{
+		orderService:  orderService,
+		escalateAfter: escalateAfter,
+		batchSize:     batchSize,
+	}
+}
+
+// Name returns the job name
+func (j *ApprovalEscalationJob) Name() string {
+	return ApprovalEscalationJobName
+}
+
+// Run escalates overdue approvals in batches.
+// Orders are escalated through the order service so that domain events are published.
+func (j *ApprovalEscalationJob) Run(ctx context.Context) error {
+	cutoff := time.Now().Add(-j.escalateAfter)
+	escalated, failed := 0, 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Orders that failed to escalate are still listed, so skip past them
+		orders, err := j.orderService.ListAwaitingApprovalBefore(ctx, cutoff, failed, j.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, order := range orders {
+			if _, err := j.orderService.EscalateApproval(ctx, order.ID); err != nil {
+				failed++
+				log.Logger.Warn("Failed to escalate order approval",
+					zap.String("order_id", order.ID),
+					zap.Error(err),
+				)
+				continue
+			}
+			escalated++
+		}
+
+		if len(orders) < j.batchSize {
+			break
+		}
+	}
+
+	log.Logger.Info("Overdue order approvals processed",
+		zap.Time("cutoff", cutoff),
+		zap.Int("escalated", escalated),
+		zap.Int("failed", failed),
+	)
+	return nil
+}
@@ -30,6 +30,7 @@ func NewAuditConsumerHandler(auditService service.IAuditService) *AuditConsumerH
 // AuditEventMessage represents the audit event message from Kafka
 type AuditEventMessage struct {
 	ID         string                 `json:"id"`
+	TenantID   string                 `json:"tenant_id,omitempty"`
 	EventName  string                 `json:"event_name"`
 	EntityType string                 `json:"entity_type"`
 	EntityID   string                 `json:"entity_id"`
@@ -59,6 +60,7 @@ func (h *AuditConsumerHandler) HandleMessage(ctx context.Context, topic string,
 	// Create audit log entry
 	auditLog := &model.AuditLog{
 		ID:         msg.ID,
+		TenantID:   msg.TenantID,
 		EntityType: msg.EntityType,
 		EntityID:   msg.EntityID,
 		Action:     msg.Action,
@@ -9,6 +9,7 @@ import (
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
 	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
 )
 
@@ -20,24 +21,45 @@ type Job interface {
 	Run(ctx context.Context) error
 }
 
+// Locker provides mutual exclusion between scheduler instances
+type Locker interface {
+	// TryLock tries to acquire the lock without waiting and returns a function to release it
+	TryLock(ctx context.Context, key string, ttl time.Duration) (unlock func(), acquired bool, err error)
+}
+
 // Scheduler manages scheduled jobs
 type Scheduler struct {
 	cron     *cron.Cron
 	jobs     map[string]Job
 	jobSpecs map[string]string
+	locker   Locker
 	mu       sync.RWMutex
 }
 
+// SchedulerOption configures a Scheduler
+type SchedulerOption func(*Scheduler)
+
+// WithLocker makes every job run hold a lock, so only one instance runs a job at a time
+func WithLocker(locker Locker) SchedulerOption {
+	return func(s *Scheduler) {
+		s.locker = locker
+	}
+}
+
 // DefaultJobTimeout is the default timeout for job execution
 const DefaultJobTimeout = 5 * time.Minute
 
 // NewScheduler creates a new job scheduler
-func NewScheduler() *Scheduler {
-	return &Scheduler{
+func NewScheduler(opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
 		cron:     cron.New(cron.WithSeconds()),
 		jobs:     make(map[string]Job),
 		jobSpecs: make(map[string]string),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // AddJob adds a job to the scheduler
@@ -50,37 +72,61 @@ func (s *Scheduler) AddJob(spec string, job Job) error {
 	}
 
 	_, err := s.cron.AddFunc(spec, func() {
-		ctx, cancel := context.WithTimeout(context.Background(), DefaultJobTimeout)
+		// Jobs work across tenants, the repositories refuse contexts that do not say so
+		ctx, cancel := context.WithTimeout(model.ContextWithoutTenant(context.Background()), DefaultJobTimeout)
 		defer cancel()
 
-		start := time.Now()
-		log.Logger.Info("Starting job",
-			zap.String("job", job.Name()),
-			zap.String("spec", spec),
-		)
+		s.run(ctx, spec, job)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to add job %s: %w", job.Name(), err)
+	}
+
+	s.jobs[job.Name()] = job
+	s.jobSpecs[job.Name()] = spec
+	return nil
+}
 
-		if err := job.Run(ctx); err != nil {
-			log.Logger.Error("Job failed",
+// run executes one run of the job, holding the job lock when a locker is set
+func (s *Scheduler) run(ctx context.Context, spec string, job Job) {
+	if s.locker != nil {
+		unlock, acquired, err := s.locker.TryLock(ctx, "job:"+job.Name(), DefaultJobTimeout)
+		if err != nil {
+			log.Logger.Error("Failed to acquire job lock",
 				zap.String("job", job.Name()),
 				zap.Error(err),
-				zap.Duration("duration", time.Since(start)),
 			)
 			return
 		}
+		if !acquired {
+			log.Logger.Debug("Job is running on another instance, skipping",
+				zap.String("job", job.Name()),
+			)
+			return
+		}
+		defer unlock()
+	}
 
-		log.Logger.Info("Job completed",
+	start := time.Now()
+	log.Logger.Info("Starting job",
+		zap.String("job", job.Name()),
+		zap.String("spec", spec),
+	)
+
+	if err := job.Run(ctx); err != nil {
+		log.Logger.Error("Job failed",
 			zap.String("job", job.Name()),
+			zap.Error(err),
 			zap.Duration("duration", time.Since(start)),
 		)
-	})
-
-	if err != nil {
-		return fmt.Errorf("failed to add job %s: %w", job.Name(), err)
+		return
 	}
 
-	s.jobs[job.Name()] = job
-	s.jobSpecs[job.Name()] = spec
-	return nil
+	log.Logger.Info("Job completed",
+		zap.String("job", job.Name()),
+		zap.Duration("duration", time.Since(start)),
+	)
 }
 
 // RemoveJob removes a job from the scheduler
@@ -0,0 +1,67 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingJob struct {
+	runs int
+}
+
+func (j *countingJob) Name() string { return "counting" }
+
+func (j *countingJob) Run(context.Context) error {
+	j.runs++
+	return nil
+}
+
+type fakeLocker struct {
+	acquired bool
+	err      error
+	keys     []string
+	unlocked int
+}
+
+func (l *fakeLocker) TryLock(_ context.Context, key string, _ time.Duration) (func(), bool, error) {
+	l.keys = append(l.keys, key)
+	if l.err != nil || !l.acquired {
+		return nil, false, l.err
+	}
+	return func() { l.unlocked++ }, true, nil
+}
+
+func TestSchedulerRunLocking(t *testing.T) {
+	tests := []struct {
+		name         string
+		locker       *fakeLocker
+		wantRuns     int
+		wantUnlocked int
+	}{
+		{name: "runs while holding the lock", locker: &fakeLocker{acquired: true}, wantRuns: 1, wantUnlocked: 1},
+		{name: "skips when another instance holds the lock", locker: &fakeLocker{}, wantRuns: 0},
+		{name: "skips when the lock cannot be checked", locker: &fakeLocker{err: errors.New("redis unavailable")}, wantRuns: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &countingJob{}
+			NewScheduler(WithLocker(tt.locker)).run(context.Background(), "@every 1m", job)
+
+			assert.Equal(t, tt.wantRuns, job.runs)
+			assert.Equal(t, tt.wantUnlocked, tt.locker.unlocked)
+			assert.Equal(t, []string{"job:counting"}, tt.locker.keys)
+		})
+	}
+
+	t.Run("runs without a locker", func(t *testing.T) {
+		job := &countingJob{}
+		NewScheduler().run(context.Background(), "@every 1m", job)
+
+		assert.Equal(t, 1, job.runs)
+	})
+}
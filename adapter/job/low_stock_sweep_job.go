@@ -0,0 +1,75 @@
+package job
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+const (
+	// LowStockSweepJobName is the name of the low-stock sweep job
+	LowStockSweepJobName = "low_stock_sweep"
+	// DefaultLowStockSweepBatchSize is the batch size used when none is configured
+	DefaultLowStockSweepBatchSize = 100
+)
+
+// LowStockSweepJob reports every product with a SKU at or below its low-stock threshold
+type LowStockSweepJob struct {
+	productService service.IProductService
+	batchSize      int
+}
+
+// NewLowStockSweepJob creates a new low-stock sweep job
+func NewLowStockSweepJob(productService service.IProductService, batchSize int) *LowStockSweepJob {
+	if batchSize <= 0 {
+		batchSize = DefaultLowStockSweepBatchSize
+	}
+	return &LowStockSweepJob{
+		productService: productService,
+		batchSize:      batchSize,
+	}
+}
+
+// Name returns the job name
+func (j *LowStockSweepJob) Name() string {
+	return LowStockSweepJobName
+}
+
+// Run sweeps low-stock products in batches. The product service publishes a product.stock_low
+// event for each low SKU.
+func (j *LowStockSweepJob) Run(ctx context.Context) error {
+	low := 0
+
+	for offset := 0; ; offset += j.batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		products, err := j.productService.SweepLowStock(ctx, offset, j.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, product := range products {
+			for sku, stock := range product.LowStockLevels() {
+				log.Logger.Warn("Low stock",
+					zap.String("product_id", product.ID),
+					zap.String("sku", sku),
+					zap.Int("stock", stock),
+					zap.Int("threshold", product.LowStockThreshold),
+				)
+			}
+		}
+		low += len(products)
+
+		if len(products) < j.batchSize {
+			break
+		}
+	}
+
+	log.Logger.Info("Low stock sweep finished", zap.Int("low_stock_products", low))
+	return nil
+}
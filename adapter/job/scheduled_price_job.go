@@ -0,0 +1,74 @@
+package job
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+const (
+	// ScheduledPriceJobName is the name of the scheduled price job
+	ScheduledPriceJobName = "scheduled_price"
+	// DefaultScheduledPriceBatchSize is the batch size used when none is configured
+	DefaultScheduledPriceBatchSize = 100
+)
+
+// ScheduledPriceJob applies scheduled price changes once they take effect
+type ScheduledPriceJob struct {
+	productService service.IProductService
+	batchSize      int
+}
+
+// NewScheduledPriceJob creates a new scheduled price job
+func NewScheduledPriceJob(productService service.IProductService, batchSize int) *ScheduledPriceJob {
+	if batchSize <= 0 {
+		batchSize = DefaultScheduledPriceBatchSize
+	}
+	return &ScheduledPriceJob{
+		productService: productService,
+		batchSize:      batchSize,
+	}
+}
+
+// Name returns the job name
+func (j *ScheduledPriceJob) Name() string {
+	return ScheduledPriceJobName
+}
+
+// Run applies due price changes in batches. A batch with failures ends the run, the failed
+// changes stay scheduled and are retried by the next run.
+func (j *ScheduledPriceJob) Run(ctx context.Context) error {
+	applied := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		products, err := j.productService.ApplyScheduledPrices(ctx, j.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, product := range products {
+			log.Logger.Info("Scheduled price applied",
+				zap.String("product_id", product.ID),
+				zap.Float64("price", product.Price),
+				zap.Int("price_version", product.PriceVersion),
+			)
+		}
+		applied += len(products)
+
+		if len(products) < j.batchSize {
+			break
+		}
+	}
+
+	if applied > 0 {
+		log.Logger.Info("Scheduled prices applied", zap.Int("applied", applied))
+	}
+	return nil
+}
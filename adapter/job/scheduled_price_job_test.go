@@ -0,0 +1,80 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
+)
+
+// fakePriceService returns the due price changes in batches, applying a batch removes it
+type fakePriceService struct {
+	service.IProductService
+
+	due      int
+	applyErr error
+	limits   []int
+}
+
+func (f *fakePriceService) ApplyScheduledPrices(_ context.Context, limit int) ([]*model.Product, error) {
+	if f.applyErr != nil {
+		return nil, f.applyErr
+	}
+	f.limits = append(f.limits, limit)
+
+	n := min(limit, f.due)
+	f.due -= n
+	products := make([]*model.Product, n)
+	for i := range products {
+		products[i] = &model.Product{ID: "p", Price: 10, PriceVersion: 2}
+	}
+	return products, nil
+}
+
+func TestScheduledPriceJob(t *testing.T) {
+	tests := []struct {
+		name       string
+		due        int
+		batchSize  int
+		applyErr   error
+		wantErr    error
+		wantLimits []int
+	}{
+		{name: "applies batches until a short one", due: 5, batchSize: 2, wantLimits: []int{2, 2, 2}},
+		{name: "checks once more after a full batch", due: 4, batchSize: 2, wantLimits: []int{2, 2, 2}},
+		{name: "nothing due", batchSize: 2, wantLimits: []int{2}},
+		{name: "uses the default batch size", due: 1, wantLimits: []int{DefaultScheduledPriceBatchSize}},
+		{name: "returns apply errors", batchSize: 2, applyErr: errors.New("database unavailable"), wantErr: errors.New("database unavailable")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products := &fakePriceService{due: tt.due, applyErr: tt.applyErr}
+			job := NewScheduledPriceJob(products, tt.batchSize)
+
+			err := job.Run(context.Background())
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Zero(t, products.due)
+			assert.Equal(t, tt.wantLimits, products.limits)
+		})
+	}
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		products := &fakePriceService{due: 3}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.ErrorIs(t, NewScheduledPriceJob(products, 1).Run(ctx), context.Canceled)
+		assert.Empty(t, products.limits)
+	})
+}
@@ -0,0 +1,84 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+const (
+	// StaleOrderCancelJobName is the name of the stale order cancellation job
+	StaleOrderCancelJobName = "stale_order_cancel"
+	// DefaultStaleOrderBatchSize is the batch size used when none is configured
+	DefaultStaleOrderBatchSize = 100
+)
+
+// StaleOrderCancelJob cancels pending orders that were not confirmed within the TTL
+type StaleOrderCancelJob struct {
+	orderService service.IOrderService
+	pendingTTL   time.Duration
+	batchSize    int
+}
+
+// NewStaleOrderCancelJob creates a new stale order cancellation job
+func NewStaleOrderCancelJob(orderService service.IOrderService, pendingTTL time.Duration, batchSize int) *StaleOrderCancelJob {
+	if batchSize <= 0 {
+		batchSize = DefaultStaleOrderBatchSize
+	}
+	return &StaleOrderCancelJob{
+		orderService: orderService,
+		pendingTTL:   pendingTTL,
+		batchSize:    batchSize,
+	}
+}
+
+// Name returns the job name
+func (j *StaleOrderCancelJob) Name() string {
+	return StaleOrderCancelJobName
+}
+
+// Run cancels stale pending orders in batches.
+// Orders are cancelled through the order service so that domain events are published.
+func (j *StaleOrderCancelJob) Run(ctx context.Context) error {
+	cutoff := time.Now().Add(-j.pendingTTL)
+	cancelled, failed := 0, 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Orders that failed to cancel are still pending, so skip past them
+		orders, err := j.orderService.ListPendingBefore(ctx, cutoff, failed, j.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, order := range orders {
+			if err := j.orderService.Cancel(ctx, order.ID); err != nil {
+				failed++
+				log.Logger.Warn("Failed to cancel stale order",
+					zap.String("order_id", order.ID),
+					zap.Error(err),
+				)
+				continue
+			}
+			cancelled++
+		}
+
+		if len(orders) < j.batchSize {
+			break
+		}
+	}
+
+	log.Logger.Info("Stale pending orders processed",
+		zap.Time("cutoff", cutoff),
+		zap.Int("cancelled", cancelled),
+		zap.Int("failed", failed),
+	)
+	return nil
+}
@@ -0,0 +1,96 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
+)
+
+// fakeOrderService keeps pending orders in memory, cancelling an order removes it from the list
+type fakeOrderService struct {
+	service.IOrderService
+
+	pending   []string
+	failing   map[string]bool
+	listErr   error
+	offsets   []int
+	before    time.Time
+	cancelled []string
+}
+
+func (f *fakeOrderService) ListPendingBefore(_ context.Context, before time.Time, offset, limit int) ([]*model.Order, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	f.offsets = append(f.offsets, offset)
+	f.before = before
+
+	orders := []*model.Order{}
+	for i := offset; i < len(f.pending) && i < offset+limit; i++ {
+		orders = append(orders, &model.Order{ID: f.pending[i], Status: model.OrderStatusPending})
+	}
+	return orders, nil
+}
+
+func (f *fakeOrderService) Cancel(_ context.Context, id string) error {
+	if f.failing[id] {
+		return model.ErrOrderInvalidStatus
+	}
+	f.pending = slices.DeleteFunc(f.pending, func(p string) bool { return p == id })
+	f.cancelled = append(f.cancelled, id)
+	return nil
+}
+
+func TestStaleOrderCancelJob(t *testing.T) {
+	t.Run("skips past orders that failed to cancel", func(t *testing.T) {
+		orders := &fakeOrderService{
+			pending: []string{"o1", "o2", "o3", "o4", "o5"},
+			failing: map[string]bool{"o2": true, "o4": true},
+		}
+		job := NewStaleOrderCancelJob(orders, 30*time.Minute, 2)
+
+		start := time.Now()
+		require.NoError(t, job.Run(context.Background()))
+
+		assert.Equal(t, []string{"o1", "o3", "o5"}, orders.cancelled)
+		assert.Equal(t, []string{"o2", "o4"}, orders.pending)
+		assert.Equal(t, []int{0, 1, 2}, orders.offsets)
+		assert.WithinDuration(t, start.Add(-30*time.Minute), orders.before, time.Second)
+	})
+
+	t.Run("stops after a short batch", func(t *testing.T) {
+		orders := &fakeOrderService{pending: []string{"o1"}}
+		job := NewStaleOrderCancelJob(orders, time.Minute, 0)
+
+		require.NoError(t, job.Run(context.Background()))
+
+		assert.Equal(t, []string{"o1"}, orders.cancelled)
+		assert.Equal(t, []int{0}, orders.offsets)
+	})
+
+	t.Run("returns list errors", func(t *testing.T) {
+		listErr := errors.New("database unavailable")
+		job := NewStaleOrderCancelJob(&fakeOrderService{listErr: listErr}, time.Minute, 10)
+
+		assert.ErrorIs(t, job.Run(context.Background()), listErr)
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		orders := &fakeOrderService{pending: []string{"o1"}}
+		job := NewStaleOrderCancelJob(orders, time.Minute, 10)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.ErrorIs(t, job.Run(ctx), context.Canceled)
+		assert.Empty(t, orders.cancelled)
+	})
+}
@@ -0,0 +1,65 @@
+package job
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+const (
+	// StockHoldReleaseJobName is the name of the expired stock hold release job
+	StockHoldReleaseJobName = "stock_hold_release"
+	// DefaultStockHoldReleaseBatchSize is the batch size used when none is configured
+	DefaultStockHoldReleaseBatchSize = 100
+)
+
+// StockHoldReleaseJob deletes expired stock holds and publishes their release
+type StockHoldReleaseJob struct {
+	reservationService service.IReservationService
+	batchSize          int
+}
+
+// NewStockHoldReleaseJob creates a new expired stock hold release job
+func NewStockHoldReleaseJob(reservationService service.IReservationService, batchSize int) *StockHoldReleaseJob {
+	if batchSize <= 0 {
+		batchSize = DefaultStockHoldReleaseBatchSize
+	}
+	return &StockHoldReleaseJob{
+		reservationService: reservationService,
+		batchSize:          batchSize,
+	}
+}
+
+// Name returns the job name
+func (j *StockHoldReleaseJob) Name() string {
+	return StockHoldReleaseJobName
+}
+
+// Run releases expired holds in batches until none is left
+func (j *StockHoldReleaseJob) Run(ctx context.Context) error {
+	released := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := j.reservationService.ReleaseExpired(ctx, j.batchSize)
+		if err != nil {
+			return err
+		}
+		released += n
+
+		if n < j.batchSize {
+			break
+		}
+	}
+
+	if released > 0 {
+		log.Logger.Info("Expired stock holds released", zap.Int("released", released))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package job
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+const (
+	// StockReconciliationJobName is the name of the stock reconciliation job
+	StockReconciliationJobName = "stock_reconciliation"
+	// DefaultStockReconciliationBatchSize is the batch size used when none is configured
+	DefaultStockReconciliationBatchSize = 100
+)
+
+// StockReconciliationJob recomputes product stock from the stock ledger and flags drift
+type StockReconciliationJob struct {
+	productService service.IProductService
+	batchSize      int
+}
+
+// NewStockReconciliationJob creates a new stock reconciliation job
+func NewStockReconciliationJob(productService service.IProductService, batchSize int) *StockReconciliationJob {
+	if batchSize <= 0 {
+		batchSize = DefaultStockReconciliationBatchSize
+	}
+	return &StockReconciliationJob{
+		productService: productService,
+		batchSize:      batchSize,
+	}
+}
+
+// Name returns the job name
+func (j *StockReconciliationJob) Name() string {
+	return StockReconciliationJobName
+}
+
+// Run checks every product in batches. Drift is reported through the product service,
+// which publishes a product.stock_drift_detected event for each mismatch.
+func (j *StockReconciliationJob) Run(ctx context.Context) error {
+	checked, drifted := 0, 0
+
+	for offset := 0; ; offset += j.batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		drifts, n, err := j.productService.ReconcileStock(ctx, offset, j.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, drift := range drifts {
+			log.Logger.Warn("Stock drift detected",
+				zap.String("product_id", drift.ProductID),
+				zap.String("sku", drift.SKU),
+				zap.Int("stock", drift.Stock),
+				zap.Int("ledger_stock", drift.LedgerStock),
+			)
+		}
+		checked += n
+		drifted += len(drifts)
+
+		if n < j.batchSize {
+			break
+		}
+	}
+
+	log.Logger.Info("Stock reconciliation finished",
+		zap.Int("checked", checked),
+		zap.Int("drifted", drifted),
+	)
+	return nil
+}
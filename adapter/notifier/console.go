@@ -0,0 +1,31 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// ConsoleNotifier prints notifications instead of sending them, for local development
+type ConsoleNotifier struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewConsoleNotifier creates a notifier printing to out
+func NewConsoleNotifier(out io.Writer) *ConsoleNotifier {
+	return &ConsoleNotifier{out: out}
+}
+
+// Notify prints the notification
+func (n *ConsoleNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	_, err := fmt.Fprintf(n.out, "--- notification ---\nTo: %s\nSubject: %s\n\n%s\n--------------------\n",
+		notification.To, notification.Subject, notification.Body)
+	return err
+}
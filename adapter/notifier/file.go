@@ -0,0 +1,61 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// fileRecord is a notification as written to the file, one JSON object per line
+type fileRecord struct {
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// FileNotifier appends notifications to a file instead of sending them, so local setups and
+// tests can read the tokens they carry
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileNotifier creates a notifier appending to the file at path, creating its directory
+func NewFileNotifier(path string) (*FileNotifier, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return &FileNotifier{path: path}, nil
+}
+
+// Notify appends the notification to the file
+func (n *FileNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	line, err := json.Marshal(fileRecord{
+		To:      notification.To,
+		Subject: notification.Subject,
+		Body:    notification.Body,
+		SentAt:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
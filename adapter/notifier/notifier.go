@@ -0,0 +1,26 @@
+package notifier
+
+import (
+	"fmt"
+	"os"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// Notifier driver names
+const (
+	DriverConsole = "console"
+	DriverFile    = "file"
+)
+
+// NewNotifier creates the notifier using the named driver, defaulting to console
+func NewNotifier(driver, filePath string) (repo.INotifier, error) {
+	switch driver {
+	case DriverConsole, "":
+		return NewConsoleNotifier(os.Stdout), nil
+	case DriverFile:
+		return NewFileNotifier(filePath)
+	default:
+		return nil, fmt.Errorf("unknown notifier driver %q", driver)
+	}
+}
@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+var testNotification = &model.Notification{To: "a@example.com", Subject: "Verify your email", Body: "token: abc"}
+
+func TestConsoleNotifier(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, NewConsoleNotifier(&out).Notify(context.Background(), testNotification))
+
+	assert.Contains(t, out.String(), "To: a@example.com")
+	assert.Contains(t, out.String(), "token: abc")
+}
+
+func TestFileNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "notifications.log")
+	n, err := NewFileNotifier(path)
+	require.NoError(t, err)
+
+	require.NoError(t, n.Notify(context.Background(), testNotification))
+	require.NoError(t, n.Notify(context.Background(), testNotification))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var record fileRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "a@example.com", record.To)
+	assert.Equal(t, "token: abc", record.Body)
+}
+
+func TestNewNotifier(t *testing.T) {
+	n, err := NewNotifier("", "")
+	require.NoError(t, err)
+	assert.IsType(t, &ConsoleNotifier{}, n)
+
+	_, err = NewNotifier("smtp", "")
+	assert.Error(t, err)
+}
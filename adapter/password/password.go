@@ -0,0 +1,82 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// Hashing algorithm names
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// ErrUnknownHash is returned when a hash was not made by any supported algorithm
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// algorithm is a single hashing algorithm with fixed settings
+type algorithm interface {
+	repo.IPasswordHasher
+	// Owns reports whether the hash was made by the algorithm, whatever its settings
+	Owns(hash string) bool
+}
+
+// Hasher hashes with the configured algorithm and verifies hashes of every supported algorithm,
+// so passwords hashed before a change of algorithm keep working until they are rehashed.
+// Legacy plaintext passwords verify too, and always need a rehash.
+type Hasher struct {
+	current    algorithm
+	algorithms []algorithm
+}
+
+// NewHasher creates the hasher using the named algorithm, defaulting to argon2id
+func NewHasher(name string, argon2Params Argon2Params, bcryptCost int) (*Hasher, error) {
+	argon2id := NewArgon2idHasher(argon2Params)
+	bcrypt := NewBcryptHasher(bcryptCost)
+
+	hasher := &Hasher{algorithms: []algorithm{argon2id, bcrypt, plaintextHasher{}}}
+	switch name {
+	case AlgorithmArgon2id, "":
+		hasher.current = argon2id
+	case AlgorithmBcrypt:
+		hasher.current = bcrypt
+	default:
+		return nil, fmt.Errorf("unknown password hashing algorithm %q", name)
+	}
+	return hasher, nil
+}
+
+// Hash hashes a password with the configured algorithm
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Verify reports whether the password matches a hash of any supported algorithm
+func (h *Hasher) Verify(hash, password string) (bool, error) {
+	for _, a := range h.algorithms {
+		if a.Owns(hash) {
+			return a.Verify(hash, password)
+		}
+	}
+	return false, ErrUnknownHash
+}
+
+// NeedsRehash reports whether the hash was made by another algorithm or with other settings
+func (h *Hasher) NeedsRehash(hash string) bool {
+	return !h.current.Owns(hash) || h.current.NeedsRehash(hash)
+}
+
+// hashPrefix returns the "$id$" prefix of a modular crypt format hash
+func hashPrefix(hash string) string {
+	if !strings.HasPrefix(hash, "$") {
+		return ""
+	}
+	end := strings.Index(hash[1:], "$")
+	if end < 0 {
+		return ""
+	}
+	return hash[:end+2]
+}
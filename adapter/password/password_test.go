@@ -0,0 +1,105 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lowArgon2Params keeps the tests fast
+var lowArgon2Params = Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func TestHasher_HashAndVerify(t *testing.T) {
+	for _, name := range []string{AlgorithmArgon2id, AlgorithmBcrypt} {
+		t.Run(name, func(t *testing.T) {
+			hasher, err := NewHasher(name, lowArgon2Params, 4)
+			require.NoError(t, err)
+
+			hash, err := hasher.Hash("s3cret-pass")
+			require.NoError(t, err)
+			assert.NotContains(t, hash, "s3cret-pass")
+			assert.False(t, hasher.NeedsRehash(hash))
+
+			ok, err := hasher.Verify(hash, "s3cret-pass")
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = hasher.Verify(hash, "wrong-pass")
+			assert.NoError(t, err)
+			assert.False(t, ok)
+
+			// Salts are random
+			other, err := hasher.Hash("s3cret-pass")
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, other)
+		})
+	}
+}
+
+func TestHasher_Rehash(t *testing.T) {
+	bcryptHasher, err := NewHasher(AlgorithmBcrypt, lowArgon2Params, 4)
+	require.NoError(t, err)
+	legacy, err := bcryptHasher.Hash("s3cret-pass")
+	require.NoError(t, err)
+
+	// A bcrypt hash still verifies after switching to argon2id, but must be upgraded
+	hasher, err := NewHasher(AlgorithmArgon2id, lowArgon2Params, 4)
+	require.NoError(t, err)
+	ok, err := hasher.Verify(legacy, "s3cret-pass")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, hasher.NeedsRehash(legacy))
+
+	// Stronger argon2id parameters also require a rehash
+	weak, err := hasher.Hash("s3cret-pass")
+	require.NoError(t, err)
+	stronger, err := NewHasher(AlgorithmArgon2id, Argon2Params{Memory: 2048, Iterations: 2, Parallelism: 1}, 4)
+	require.NoError(t, err)
+	ok, err = stronger.Verify(weak, "s3cret-pass")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, stronger.NeedsRehash(weak))
+
+	// So does a higher bcrypt cost
+	costlier, err := NewHasher(AlgorithmBcrypt, lowArgon2Params, 5)
+	require.NoError(t, err)
+	assert.True(t, costlier.NeedsRehash(legacy))
+}
+
+func TestHasher_LegacyPlaintext(t *testing.T) {
+	hasher, err := NewHasher(AlgorithmArgon2id, lowArgon2Params, 4)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		stored   string
+		password string
+		want     bool
+		wantErr  error
+	}{
+		{name: "matching plaintext", stored: "s3cret-pass", password: "s3cret-pass", want: true},
+		{name: "wrong password", stored: "s3cret-pass", password: "s3cret-pas"},
+		{name: "prefix of the password", stored: "s3cret", password: "s3cret-pass"},
+		{name: "empty stored password never matches", stored: "", password: "", wantErr: ErrUnknownHash},
+		{name: "unknown crypt format", stored: "$1$salt$digest", password: "$1$salt$digest", wantErr: ErrUnknownHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := hasher.Verify(tt.stored, tt.password)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, ok)
+			assert.True(t, hasher.NeedsRehash(tt.stored))
+		})
+	}
+}
+
+func TestHasher_UnknownAlgorithm(t *testing.T) {
+	_, err := NewHasher("md5", lowArgon2Params, 4)
+	assert.Error(t, err)
+}
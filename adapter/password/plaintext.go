@@ -0,0 +1,35 @@
+package password
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+)
+
+// plaintextHasher verifies the passwords stored in plaintext before passwords were hashed.
+// It never hashes: such passwords need a rehash, so the next login replaces them with a hash
+// of the current algorithm.
+type plaintextHasher struct{}
+
+// Hash refuses to store a password in plaintext
+func (plaintextHasher) Hash(string) (string, error) {
+	return "", errors.New("passwords are not stored in plaintext")
+}
+
+// Verify compares the digests of the stored and given passwords in constant time,
+// so neither their content nor their length leaks through timing
+func (plaintextHasher) Verify(hash, password string) (bool, error) {
+	stored, given := sha256.Sum256([]byte(hash)), sha256.Sum256([]byte(password))
+	return subtle.ConstantTimeCompare(stored[:], given[:]) == 1, nil
+}
+
+// NeedsRehash always reports true, a plaintext password must be hashed
+func (plaintextHasher) NeedsRehash(string) bool {
+	return true
+}
+
+// Owns reports whether the hash is a legacy plaintext password: not empty and not in the
+// modular crypt format of the hashing algorithms
+func (plaintextHasher) Owns(hash string) bool {
+	return hash != "" && hashPrefix(hash) == ""
+}
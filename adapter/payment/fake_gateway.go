@@ -0,0 +1,128 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+const (
+	// FakeRefPrefix prefixes every reference issued by the fake gateway
+	FakeRefPrefix = "fake_"
+	// FakeDeclinedCents makes authorizations for amounts ending in .13 fail, like provider test cards
+	FakeDeclinedCents = 13
+	// signaturePrefix is the optional scheme prefix of the webhook signature header
+	signaturePrefix = "sha256="
+)
+
+// fakeEventStatuses maps fake provider event types to payment statuses
+var fakeEventStatuses = map[string]model.PaymentStatus{
+	"payment.authorized": model.PaymentStatusAuthorized,
+	"payment.captured":   model.PaymentStatusCaptured,
+	"payment.voided":     model.PaymentStatusVoided,
+	"payment.refunded":   model.PaymentStatusRefunded,
+	"payment.failed":     model.PaymentStatusFailed,
+}
+
+// FakeWebhookPayload is the webhook body sent by the fake provider
+type FakeWebhookPayload struct {
+	ID        string  `json:"id"`
+	Type      string  `json:"type"`
+	Reference string  `json:"reference"`
+	Amount    float64 `json:"amount,omitempty"`
+	Reason    string  `json:"reason,omitempty"`
+}
+
+// FakeGateway is a deterministic IPaymentGateway for local development and tests.
+// It never calls an external service: references are derived from the payment ID
+// and the outcome of an authorization depends only on the amount.
+type FakeGateway struct {
+	webhookSecret []byte
+}
+
+// NewFakeGateway creates a new fake payment gateway
+func NewFakeGateway(webhookSecret string) *FakeGateway {
+	return &FakeGateway{webhookSecret: []byte(webhookSecret)}
+}
+
+// Authorize returns a reference derived from the payment ID, declining amounts ending in .13
+func (g *FakeGateway) Authorize(ctx context.Context, paymentID string, amount float64) (string, error) {
+	if int64(math.Round(amount*100))%100 == FakeDeclinedCents {
+		return "", model.ErrPaymentDeclined
+	}
+
+	sum := sha256.Sum256([]byte(paymentID))
+	return FakeRefPrefix + hex.EncodeToString(sum[:8]), nil
+}
+
+// Capture captures an authorization issued by the fake gateway
+func (g *FakeGateway) Capture(ctx context.Context, gatewayRef string, amount float64, idempotencyKey string) error {
+	return checkRef(gatewayRef)
+}
+
+// Void voids an authorization issued by the fake gateway
+func (g *FakeGateway) Void(ctx context.Context, gatewayRef string, idempotencyKey string) error {
+	return checkRef(gatewayRef)
+}
+
+// Refund refunds a payment captured by the fake gateway
+func (g *FakeGateway) Refund(ctx context.Context, gatewayRef string, amount float64, idempotencyKey string) error {
+	return checkRef(gatewayRef)
+}
+
+// ParseWebhook verifies the HMAC-SHA256 signature of the payload and decodes it.
+// Without a webhook secret every webhook is rejected.
+func (g *FakeGateway) ParseWebhook(payload []byte, signature string) (*model.PaymentWebhookEvent, error) {
+	if len(g.webhookSecret) == 0 {
+		return nil, model.ErrPaymentWebhookSignatureInvalid
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil || !hmac.Equal(expected, g.sign(payload)) {
+		return nil, model.ErrPaymentWebhookSignatureInvalid
+	}
+
+	var body FakeWebhookPayload
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, model.ErrPaymentWebhookPayloadInvalid
+	}
+
+	status, ok := fakeEventStatuses[body.Type]
+	if !ok || body.Reference == "" {
+		return nil, model.ErrPaymentWebhookPayloadInvalid
+	}
+
+	return &model.PaymentWebhookEvent{
+		EventID:    body.ID,
+		GatewayRef: body.Reference,
+		Status:     status,
+		Amount:     body.Amount,
+		Reason:     body.Reason,
+	}, nil
+}
+
+// Sign returns the signature header value for a webhook payload
+func (g *FakeGateway) Sign(payload []byte) string {
+	return signaturePrefix + hex.EncodeToString(g.sign(payload))
+}
+
+func (g *FakeGateway) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, g.webhookSecret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// checkRef ensures the reference was issued by the fake gateway
+func checkRef(gatewayRef string) error {
+	if !strings.HasPrefix(gatewayRef, FakeRefPrefix) {
+		return fmt.Errorf("unknown fake gateway reference: %s", gatewayRef)
+	}
+	return nil
+}
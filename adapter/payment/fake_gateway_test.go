@@ -0,0 +1,77 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+func TestFakeGateway_Authorize(t *testing.T) {
+	gateway := NewFakeGateway("secret")
+
+	// References are deterministic per payment
+	ref1, err := gateway.Authorize(context.Background(), "payment-1", 10.50)
+	assert.NoError(t, err)
+	ref2, err := gateway.Authorize(context.Background(), "payment-1", 10.50)
+	assert.NoError(t, err)
+	assert.Equal(t, ref1, ref2)
+	assert.Contains(t, ref1, FakeRefPrefix)
+
+	// Amounts ending in .13 are declined
+	_, err = gateway.Authorize(context.Background(), "payment-2", 20.13)
+	assert.ErrorIs(t, err, model.ErrPaymentDeclined)
+}
+
+func TestFakeGateway_ParseWebhook(t *testing.T) {
+	gateway := NewFakeGateway("secret")
+	payload := []byte(`{"id":"evt_1","type":"payment.captured","reference":"fake_abc"}`)
+
+	// Valid signature
+	evt, err := gateway.ParseWebhook(payload, gateway.Sign(payload))
+	assert.NoError(t, err)
+	assert.Equal(t, "evt_1", evt.EventID)
+	assert.Equal(t, "fake_abc", evt.GatewayRef)
+	assert.Equal(t, model.PaymentStatusCaptured, evt.Status)
+
+	// Signature from another secret
+	other := NewFakeGateway("other")
+	_, err = gateway.ParseWebhook(payload, other.Sign(payload))
+	assert.ErrorIs(t, err, model.ErrPaymentWebhookSignatureInvalid)
+
+	// Tampered payload
+	_, err = gateway.ParseWebhook([]byte(`{"id":"evt_1","type":"payment.voided","reference":"fake_abc"}`), gateway.Sign(payload))
+	assert.ErrorIs(t, err, model.ErrPaymentWebhookSignatureInvalid)
+
+	// Unknown event type
+	unknown := []byte(`{"id":"evt_2","type":"payment.unknown","reference":"fake_abc"}`)
+	_, err = gateway.ParseWebhook(unknown, gateway.Sign(unknown))
+	assert.ErrorIs(t, err, model.ErrPaymentWebhookPayloadInvalid)
+}
+
+func TestFakeGateway_ParseWebhookWithoutSecret(t *testing.T) {
+	gateway := NewFakeGateway("")
+	payload := []byte(`{"id":"evt_1","type":"payment.captured","reference":"fake_abc"}`)
+
+	// An empty key would let anyone compute a valid signature
+	_, err := gateway.ParseWebhook(payload, gateway.Sign(payload))
+	assert.ErrorIs(t, err, model.ErrPaymentWebhookSignatureInvalid)
+}
+
+func TestNewGateway(t *testing.T) {
+	gateway, err := NewGateway(ProviderFake, "secret")
+	assert.NoError(t, err)
+	assert.IsType(t, &FakeGateway{}, gateway)
+
+	gateway, err = NewGateway("", "secret")
+	assert.NoError(t, err)
+	assert.IsType(t, &FakeGateway{}, gateway)
+
+	_, err = NewGateway(ProviderFake, "")
+	assert.ErrorIs(t, err, ErrWebhookSecretRequired)
+
+	_, err = NewGateway("acme-pay", "secret")
+	assert.Error(t, err)
+}
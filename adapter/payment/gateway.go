@@ -0,0 +1,28 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// ProviderFake is the name of the fake payment provider
+const ProviderFake = "fake"
+
+// ErrWebhookSecretRequired is returned when a gateway is created without a webhook secret
+var ErrWebhookSecretRequired = errors.New("payment webhook secret is required")
+
+// NewGateway creates the gateway of the named provider, defaulting to the fake one.
+// A webhook secret is required so that webhooks cannot be forged.
+func NewGateway(provider, webhookSecret string) (repo.IPaymentGateway, error) {
+	if webhookSecret == "" {
+		return nil, ErrWebhookSecretRequired
+	}
+
+	switch provider {
+	case ProviderFake, "":
+		return NewFakeGateway(webhookSecret), nil
+	}
+	return nil, fmt.Errorf("unknown payment provider %q", provider)
+}
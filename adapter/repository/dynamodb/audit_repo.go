@@ -29,6 +29,7 @@ func NewAuditRepository(client *Client) repo.IAuditRepo {
 // auditLogItem represents the DynamoDB item structure
 type auditLogItem struct {
 	ID         string                 `dynamodbav:"id"`
+	TenantID   string                 `dynamodbav:"tenant_id,omitempty"`
 	EntityType string                 `dynamodbav:"entity_type"`
 	EntityID   string                 `dynamodbav:"entity_id"`
 	Action     string                 `dynamodbav:"action"`
@@ -37,10 +38,75 @@ type auditLogItem struct {
 	UserID     *int                   `dynamodbav:"user_id,omitempty"`
 }
 
+// toModel converts item to domain model
+func (i *auditLogItem) toModel() *model.AuditLog {
+	timestamp, _ := time.Parse(time.RFC3339, i.Timestamp)
+	return &model.AuditLog{
+		ID:         i.ID,
+		TenantID:   i.TenantID,
+		EntityType: i.EntityType,
+		EntityID:   i.EntityID,
+		Action:     i.Action,
+		Payload:    i.Payload,
+		Timestamp:  timestamp,
+		UserID:     i.UserID,
+	}
+}
+
+// inTenant reports whether the item is visible to the tenant of ctx, see tenantCondition
+func (i *auditLogItem) inTenant(ctx context.Context) bool {
+	tenantID := model.TenantFromContext(ctx)
+	switch tenantID {
+	case "":
+		return model.SeesAllTenants(ctx)
+	case model.DefaultTenantID:
+		return i.TenantID == "" || i.TenantID == tenantID
+	default:
+		return i.TenantID == tenantID
+	}
+}
+
+// requireTenant returns model.ErrTenantMissing when ctx has no tenant and did not opt out with
+// model.ContextWithoutTenant
+func requireTenant(ctx context.Context) error {
+	if model.TenantFromContext(ctx) == "" && !model.SeesAllTenants(ctx) {
+		return model.ErrTenantMissing
+	}
+	return nil
+}
+
+// tenantCondition adds to condition, which may be empty, the filter restricting items to the tenant
+// of ctx and its value to values. Contexts without a tenant fail with model.ErrTenantMissing unless
+// they opted out with model.ContextWithoutTenant, as scheduled jobs do, and see every tenant. Items
+// stored before tenancy have no tenant and belong to the default one.
+func tenantCondition(ctx context.Context, condition string, values map[string]types.AttributeValue) (*string, error) {
+	if err := requireTenant(ctx); err != nil {
+		return nil, err
+	}
+	tenantID := model.TenantFromContext(ctx)
+	if tenantID == "" {
+		if condition == "" {
+			return nil, nil
+		}
+		return aws.String(condition), nil
+	}
+
+	values[":tid"] = &types.AttributeValueMemberS{Value: tenantID}
+	tenant := "tenant_id = :tid"
+	if tenantID == model.DefaultTenantID {
+		tenant = "(attribute_not_exists(tenant_id) OR tenant_id = :tid)"
+	}
+	if condition == "" {
+		return aws.String(tenant), nil
+	}
+	return aws.String(condition + " AND " + tenant), nil
+}
+
 // Create saves a new audit log entry
 func (r *AuditRepository) Create(ctx context.Context, audit *model.AuditLog) error {
 	item := auditLogItem{
 		ID:         audit.ID,
+		TenantID:   audit.TenantID,
 		EntityType: audit.EntityType,
 		EntityID:   audit.EntityID,
 		Action:     audit.Action,
@@ -68,6 +134,9 @@ func (r *AuditRepository) Create(ctx context.Context, audit *model.AuditLog) err
 
 // GetByID retrieves an audit log by its ID
 func (r *AuditRepository) GetByID(ctx context.Context, id string) (*model.AuditLog, error) {
+	if err := requireTenant(ctx); err != nil {
+		return nil, err
+	}
 	tableName := r.client.GetTableName(auditLogsTable)
 
 	result, err := r.client.DB.GetItem(ctx, &dynamodb.GetItemInput{
@@ -88,18 +157,11 @@ func (r *AuditRepository) GetByID(ctx context.Context, id string) (*model.AuditL
 	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal audit log: %w", err)
 	}
+	if !item.inTenant(ctx) {
+		return nil, nil
+	}
 
-	timestamp, _ := time.Parse(time.RFC3339, item.Timestamp)
-
-	return &model.AuditLog{
-		ID:         item.ID,
-		EntityType: item.EntityType,
-		EntityID:   item.EntityID,
-		Action:     item.Action,
-		Payload:    item.Payload,
-		Timestamp:  timestamp,
-		UserID:     item.UserID,
-	}, nil
+	return item.toModel(), nil
 }
 
 // FindByEntityType retrieves audit logs by entity type with pagination
@@ -116,6 +178,11 @@ func (r *AuditRepository) FindByEntityType(ctx context.Context, entityType strin
 		ScanIndexForward: aws.Bool(false), // Descending order by timestamp
 		Limit:            aws.Int32(int32(limit)),
 	}
+	filter, err := tenantCondition(ctx, "", input.ExpressionAttributeValues)
+	if err != nil {
+		return nil, "", err
+	}
+	input.FilterExpression = filter
 
 	// Handle pagination
 	if lastKey != "" {
@@ -136,16 +203,7 @@ func (r *AuditRepository) FindByEntityType(ctx context.Context, entityType strin
 			continue
 		}
 
-		timestamp, _ := time.Parse(time.RFC3339, logItem.Timestamp)
-		audits = append(audits, &model.AuditLog{
-			ID:         logItem.ID,
-			EntityType: logItem.EntityType,
-			EntityID:   logItem.EntityID,
-			Action:     logItem.Action,
-			Payload:    logItem.Payload,
-			Timestamp:  timestamp,
-			UserID:     logItem.UserID,
-		})
+		audits = append(audits, logItem.toModel())
 	}
 
 	var nextKey string
@@ -162,13 +220,18 @@ func (r *AuditRepository) FindByEntityType(ctx context.Context, entityType strin
 func (r *AuditRepository) FindByEntityID(ctx context.Context, entityType, entityID string) ([]*model.AuditLog, error) {
 	tableName := r.client.GetTableName(auditLogsTable)
 
+	values := map[string]types.AttributeValue{
+		":et":  &types.AttributeValueMemberS{Value: entityType},
+		":eid": &types.AttributeValueMemberS{Value: entityID},
+	}
+	filter, err := tenantCondition(ctx, "entity_type = :et AND entity_id = :eid", values)
+	if err != nil {
+		return nil, err
+	}
 	input := &dynamodb.ScanInput{
-		TableName:        aws.String(tableName),
-		FilterExpression: aws.String("entity_type = :et AND entity_id = :eid"),
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":et":  &types.AttributeValueMemberS{Value: entityType},
-			":eid": &types.AttributeValueMemberS{Value: entityID},
-		},
+		TableName:                 aws.String(tableName),
+		FilterExpression:          filter,
+		ExpressionAttributeValues: values,
 	}
 
 	result, err := r.client.DB.Scan(ctx, input)
@@ -183,16 +246,7 @@ func (r *AuditRepository) FindByEntityID(ctx context.Context, entityType, entity
 			continue
 		}
 
-		timestamp, _ := time.Parse(time.RFC3339, logItem.Timestamp)
-		audits = append(audits, &model.AuditLog{
-			ID:         logItem.ID,
-			EntityType: logItem.EntityType,
-			EntityID:   logItem.EntityID,
-			Action:     logItem.Action,
-			Payload:    logItem.Payload,
-			Timestamp:  timestamp,
-			UserID:     logItem.UserID,
-		})
+		audits = append(audits, logItem.toModel())
 	}
 
 	return audits, nil
@@ -0,0 +1,267 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+const categoriesCollection = "categories"
+
+// CategoryRepository implements ICategoryRepo using MongoDB
+type CategoryRepository struct {
+	client *Client
+}
+
+// NewCategoryRepository creates a new category repository
+func NewCategoryRepository(client *Client) repo.ICategoryRepo {
+	return &CategoryRepository{client: client}
+}
+
+// categoryDocument represents the MongoDB document
+type categoryDocument struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID   string             `bson:"tenant_id,omitempty"`
+	Name       string             `bson:"name"`
+	ParentID   string             `bson:"parent_id,omitempty"`
+	Path       string             `bson:"path"`
+	Version    int                `bson:"version"`
+	MovingFrom string             `bson:"moving_from,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at"`
+}
+
+// toModel converts document to domain model
+func (d *categoryDocument) toModel() *model.Category {
+	// Categories stored before tenancy belong to the default tenant
+	tenantID := d.TenantID
+	if tenantID == "" {
+		tenantID = model.DefaultTenantID
+	}
+
+	return &model.Category{
+		ID:         d.ID.Hex(),
+		TenantID:   tenantID,
+		Name:       d.Name,
+		ParentID:   d.ParentID,
+		Path:       d.Path,
+		Version:    d.Version,
+		MovingFrom: d.MovingFrom,
+		CreatedAt:  d.CreatedAt,
+		UpdatedAt:  d.UpdatedAt,
+	}
+}
+
+func (r *CategoryRepository) collection() *mongo.Collection {
+	return r.client.GetCollection(categoriesCollection)
+}
+
+// Create creates a new category, in the tenant of ctx unless the category has one
+func (r *CategoryRepository) Create(ctx context.Context, category *model.Category) (*model.Category, error) {
+	doc := &categoryDocument{
+		ID:        primitive.NewObjectID(),
+		TenantID:  category.TenantID,
+		Name:      category.Name,
+		ParentID:  category.ParentID,
+		Path:      category.Path,
+		Version:   category.Version,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if doc.Version == 0 {
+		doc.Version = 1
+	}
+	if doc.TenantID == "" {
+		doc.TenantID = model.TenantForCreate(ctx)
+	}
+
+	if _, err := r.collection().InsertOne(ctx, doc); err != nil {
+		return nil, fmt.Errorf("failed to insert category: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+// Update updates a category if it is still at the version it was read with.
+// On success the category's version is incremented; otherwise model.ErrVersionConflict is returned.
+func (r *CategoryRepository) Update(ctx context.Context, category *model.Category) error {
+	oid, err := primitive.ObjectIDFromHex(category.ID)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %w", err)
+	}
+
+	updatedAt := time.Now()
+	filter := tenantFilter(ctx, bson.M{"_id": oid, "version": category.Version})
+	update := bson.M{
+		"$set": bson.M{
+			"name":        category.Name,
+			"parent_id":   category.ParentID,
+			"path":        category.Path,
+			"moving_from": category.MovingFrom,
+			"updated_at":  updatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := r.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return model.ErrVersionConflict
+	}
+
+	category.Version++
+	category.UpdatedAt = updatedAt
+	return nil
+}
+
+// MoveSubtree completes the pending move of the category. Descendants are rewritten from
+// category.MovingFrom to the category's subtree path, including the pending moves they have
+// themselves, and MovingFrom is cleared last, so running it again after a failure is safe.
+func (r *CategoryRepository) MoveSubtree(ctx context.Context, category *model.Category) error {
+	oid, err := primitive.ObjectIDFromHex(category.ID)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %w", err)
+	}
+
+	oldPrefix, newPrefix := category.MovingFrom, category.SubtreePath()
+	if oldPrefix != "" && oldPrefix != newPrefix {
+		filter := tenantFilter(ctx, bson.M{"path": subtreeFilter(oldPrefix)})
+		update := bson.A{
+			bson.M{"$set": bson.M{
+				"path": replacePrefix("$path", oldPrefix, newPrefix),
+				"moving_from": bson.M{"$cond": bson.A{
+					bson.M{"$eq": bson.A{bson.M{"$indexOfCP": bson.A{bson.M{"$ifNull": bson.A{"$moving_from", ""}}, oldPrefix}}, 0}},
+					replacePrefix("$moving_from", oldPrefix, newPrefix),
+					"$moving_from",
+				}},
+				"updated_at": time.Now(),
+			}},
+		}
+
+		if _, err := r.collection().UpdateMany(ctx, filter, update); err != nil {
+			return fmt.Errorf("failed to move category subtree: %w", err)
+		}
+	}
+
+	// Only clear the move that was completed, a newer one is left for its own caller
+	filter := tenantFilter(ctx, bson.M{"_id": oid, "moving_from": category.MovingFrom})
+	if _, err := r.collection().UpdateOne(ctx, filter, bson.M{"$unset": bson.M{"moving_from": ""}}); err != nil {
+		return fmt.Errorf("failed to complete category move: %w", err)
+	}
+
+	category.MovingFrom = ""
+	return nil
+}
+
+// replacePrefix builds an expression replacing oldPrefix at the start of field with newPrefix
+func replacePrefix(field, oldPrefix, newPrefix string) bson.M {
+	return bson.M{"$concat": bson.A{
+		newPrefix,
+		bson.M{"$substrCP": bson.A{field, len(oldPrefix), bson.M{"$strLenCP": field}}},
+	}}
+}
+
+// Delete deletes a category by ID
+func (r *CategoryRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %w", err)
+	}
+
+	result, err := r.collection().DeleteOne(ctx, tenantFilter(ctx, bson.M{"_id": oid}))
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return model.ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// GetByID retrieves a category by ID
+func (r *CategoryRepository) GetByID(ctx context.Context, id string) (*model.Category, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		// IDs that are not ObjectIDs cannot match any category
+		return nil, nil
+	}
+
+	var doc categoryDocument
+	err = r.collection().FindOne(ctx, tenantFilter(ctx, bson.M{"_id": oid})).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find category: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+// GetByIDs retrieves the categories with the given IDs, skipping unknown IDs
+func (r *CategoryRepository) GetByIDs(ctx context.Context, ids []string) ([]*model.Category, error) {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	if len(oids) == 0 {
+		return nil, nil
+	}
+
+	return r.find(ctx, bson.M{"_id": bson.M{"$in": oids}})
+}
+
+// List retrieves all categories ordered by path
+func (r *CategoryRepository) List(ctx context.Context) ([]*model.Category, error) {
+	return r.find(ctx, bson.M{})
+}
+
+// ListSubtree retrieves the categories whose path starts with the given prefix
+func (r *CategoryRepository) ListSubtree(ctx context.Context, pathPrefix string) ([]*model.Category, error) {
+	return r.find(ctx, bson.M{"path": subtreeFilter(pathPrefix)})
+}
+
+func (r *CategoryRepository) find(ctx context.Context, filter bson.M) ([]*model.Category, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "path", Value: 1}, {Key: "name", Value: 1}})
+
+	cursor, err := r.collection().Find(ctx, tenantFilter(ctx, filter), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find categories: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var categories []*model.Category
+	for cursor.Next(ctx) {
+		var doc categoryDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode category: %w", err)
+		}
+		categories = append(categories, doc.toModel())
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// subtreeFilter matches paths starting with prefix; an anchored regex can use the path index
+func subtreeFilter(prefix string) bson.M {
+	return bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}
+}
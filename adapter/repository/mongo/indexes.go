@@ -0,0 +1,87 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureIndexes creates the indexes used by the product, category, stock movement and warehouse repositories
+func EnsureIndexes(ctx context.Context, client *Client) error {
+	productIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().
+				SetName("products_text").
+				SetWeights(bson.D{{Key: "name", Value: 10}, {Key: "description", Value: 2}}),
+		},
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "variants.sku", Value: 1}},
+			Options: options.Index().
+				SetName("products_tenant_variant_sku").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"variants.sku": bson.M{"$exists": true}}),
+		},
+		{Keys: bson.D{{Key: "category_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "price", Value: 1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+	}
+	if _, err := client.GetCollection(productsCollection).Indexes().CreateMany(ctx, productIndexes); err != nil {
+		return fmt.Errorf("failed to create product indexes: %w", err)
+	}
+
+	categoryIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "path", Value: 1}, {Key: "name", Value: 1}}},
+	}
+	if err := dropIndex(ctx, client.GetCollection(categoriesCollection), "path_1_name_1"); err != nil {
+		return fmt.Errorf("failed to drop category index: %w", err)
+	}
+	if _, err := client.GetCollection(categoriesCollection).Indexes().CreateMany(ctx, categoryIndexes); err != nil {
+		return fmt.Errorf("failed to create category indexes: %w", err)
+	}
+
+	stockMovementIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	}
+	if _, err := client.GetCollection(stockMovementsCollection).Indexes().CreateMany(ctx, stockMovementIndexes); err != nil {
+		return fmt.Errorf("failed to create stock movement indexes: %w", err)
+	}
+
+	priceHistoryIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "effective_from", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "effective_from", Value: 1}}},
+	}
+	if _, err := client.GetCollection(priceHistoryCollection).Indexes().CreateMany(ctx, priceHistoryIndexes); err != nil {
+		return fmt.Errorf("failed to create price history indexes: %w", err)
+	}
+
+	// Codes are unique within a tenant, replacing the former index on code alone
+	warehouseIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "code", Value: 1}},
+			Options: options.Index().SetName("warehouses_tenant_code").SetUnique(true),
+		},
+	}
+	if err := dropIndex(ctx, client.GetCollection(warehousesCollection), "code_1"); err != nil {
+		return fmt.Errorf("failed to drop warehouse index: %w", err)
+	}
+	if _, err := client.GetCollection(warehousesCollection).Indexes().CreateMany(ctx, warehouseIndexes); err != nil {
+		return fmt.Errorf("failed to create warehouse indexes: %w", err)
+	}
+
+	return nil
+}
+
+// dropIndex drops an index replaced by a newer one, ignoring indexes and collections that do not exist
+func dropIndex(ctx context.Context, collection *mongo.Collection, name string) error {
+	_, err := collection.Indexes().DropOne(ctx, name)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Name == "IndexNotFound" || cmdErr.Name == "NamespaceNotFound") {
+		return nil
+	}
+	return err
+}
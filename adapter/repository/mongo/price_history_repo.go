@@ -0,0 +1,201 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+const priceHistoryCollection = "price_history"
+
+// PriceHistoryRepository implements IPriceHistoryRepo using MongoDB
+type PriceHistoryRepository struct {
+	client *Client
+}
+
+// NewPriceHistoryRepository creates a new price history repository
+func NewPriceHistoryRepository(client *Client) repo.IPriceHistoryRepo {
+	return &PriceHistoryRepository{client: client}
+}
+
+// priceEntryDocument represents the MongoDB document
+type priceEntryDocument struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID      string             `bson:"tenant_id,omitempty"`
+	ProductID     string             `bson:"product_id"`
+	SKU           string             `bson:"sku,omitempty"`
+	Version       int                `bson:"version"`
+	Price         float64            `bson:"price"`
+	Status        string             `bson:"status"`
+	EffectiveFrom time.Time          `bson:"effective_from"`
+	EffectiveTo   *time.Time         `bson:"effective_to,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at"`
+}
+
+// toModel converts document to domain model
+func (d *priceEntryDocument) toModel() *model.PriceEntry {
+	// Entries stored before tenancy belong to the default tenant
+	tenantID := d.TenantID
+	if tenantID == "" {
+		tenantID = model.DefaultTenantID
+	}
+
+	return &model.PriceEntry{
+		ID:            d.ID.Hex(),
+		TenantID:      tenantID,
+		ProductID:     d.ProductID,
+		SKU:           d.SKU,
+		Version:       d.Version,
+		Price:         d.Price,
+		Status:        model.PriceEntryStatus(d.Status),
+		EffectiveFrom: d.EffectiveFrom,
+		EffectiveTo:   d.EffectiveTo,
+		CreatedAt:     d.CreatedAt,
+	}
+}
+
+func (r *PriceHistoryRepository) collection() *mongo.Collection {
+	return r.client.GetCollection(priceHistoryCollection)
+}
+
+// Append adds an entry, in the tenant of ctx unless the entry has one
+func (r *PriceHistoryRepository) Append(ctx context.Context, entry *model.PriceEntry) error {
+	doc := &priceEntryDocument{
+		ID:            primitive.NewObjectID(),
+		TenantID:      entry.TenantID,
+		ProductID:     entry.ProductID,
+		SKU:           entry.SKU,
+		Version:       entry.Version,
+		Price:         entry.Price,
+		Status:        string(entry.Status),
+		EffectiveFrom: entry.EffectiveFrom,
+		EffectiveTo:   entry.EffectiveTo,
+		CreatedAt:     entry.CreatedAt,
+	}
+	if doc.TenantID == "" {
+		doc.TenantID = model.TenantForCreate(ctx)
+	}
+
+	if _, err := r.collection().InsertOne(ctx, doc); err != nil {
+		return fmt.Errorf("failed to insert price entry: %w", err)
+	}
+
+	entry.ID = doc.ID.Hex()
+	return nil
+}
+
+// Transition stores the status, version and window of an entry if its stored status is still from
+func (r *PriceHistoryRepository) Transition(ctx context.Context, entry *model.PriceEntry, from model.PriceEntryStatus) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(entry.ID)
+	if err != nil {
+		return false, fmt.Errorf("invalid price entry ID: %w", err)
+	}
+
+	set := bson.M{
+		"status":         string(entry.Status),
+		"version":        entry.Version,
+		"effective_from": entry.EffectiveFrom,
+	}
+	update := bson.M{"$set": set}
+	if entry.EffectiveTo != nil {
+		set["effective_to"] = entry.EffectiveTo
+	} else {
+		update["$unset"] = bson.M{"effective_to": ""}
+	}
+
+	result, err := r.collection().UpdateOne(ctx, tenantFilter(ctx, bson.M{"_id": oid, "status": string(from)}), update)
+	if err != nil {
+		return false, fmt.Errorf("failed to update price entry: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// Supersede closes at the given time the window of the active entries of a product's SKU, except exceptID
+func (r *PriceHistoryRepository) Supersede(ctx context.Context, productID, sku, exceptID string, at time.Time) error {
+	filter := tenantFilter(ctx, bson.M{"product_id": productID, "status": string(model.PriceEntryActive)})
+	// Product prices are stored without SKU, null also matches the missing field
+	if sku == "" {
+		filter["sku"] = nil
+	} else {
+		filter["sku"] = sku
+	}
+	if exceptID != "" {
+		oid, err := primitive.ObjectIDFromHex(exceptID)
+		if err != nil {
+			return fmt.Errorf("invalid price entry ID: %w", err)
+		}
+		filter["_id"] = bson.M{"$ne": oid}
+	}
+
+	update := bson.M{"$set": bson.M{
+		"status":       string(model.PriceEntrySuperseded),
+		"effective_to": at,
+	}}
+	if _, err := r.collection().UpdateMany(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to supersede price entries: %w", err)
+	}
+	return nil
+}
+
+// ListByProductID retrieves the entries of a product, latest effective first, with pagination
+func (r *PriceHistoryRepository) ListByProductID(ctx context.Context, productID string, offset, limit int) ([]*model.PriceEntry, int64, error) {
+	filter := tenantFilter(ctx, bson.M{"product_id": productID})
+
+	total, err := r.collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count price entries: %w", err)
+	}
+
+	opts := options.Find().
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "effective_from", Value: -1}, {Key: "_id", Value: -1}})
+
+	entries, err := r.find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// ListDue retrieves scheduled entries taking effect before the given time, earliest first
+func (r *PriceHistoryRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*model.PriceEntry, error) {
+	filter := tenantFilter(ctx, bson.M{
+		"status":         string(model.PriceEntryScheduled),
+		"effective_from": bson.M{"$lte": before},
+	})
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "effective_from", Value: 1}, {Key: "_id", Value: 1}})
+
+	return r.find(ctx, filter, opts)
+}
+
+func (r *PriceHistoryRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*model.PriceEntry, error) {
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find price entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*model.PriceEntry
+	for cursor.Next(ctx) {
+		var doc priceEntryDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode price entry: %w", err)
+		}
+		entries = append(entries, doc.toModel())
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate price entries: %w", err)
+	}
+	return entries, nil
+}
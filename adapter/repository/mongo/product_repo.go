@@ -2,6 +2,7 @@ package mongo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -29,36 +30,83 @@ func NewProductRepository(client *Client) repo.IProductRepo {
 // productDocument represents the MongoDB document
 type productDocument struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID    string             `bson:"tenant_id,omitempty"`
 	Name        string             `bson:"name"`
 	Description string             `bson:"description"`
 	Price       float64            `bson:"price"`
+	PriceVer    int                `bson:"price_version,omitempty"`
 	Stock       int                `bson:"stock"`
+	LowStock    int                `bson:"low_stock_threshold,omitempty"`
+	CategoryIDs []string           `bson:"category_ids,omitempty"`
+	Variants    []variantDocument  `bson:"variants,omitempty"`
+	Warehouses  map[string]int     `bson:"warehouse_stock,omitempty"`
+	Version     int                `bson:"version"`
 	CreatedAt   time.Time          `bson:"created_at"`
 	UpdatedAt   time.Time          `bson:"updated_at"`
 	DeletedAt   *time.Time         `bson:"deleted_at,omitempty"`
 }
 
+// variantDocument represents a product variant embedded in the product document
+type variantDocument struct {
+	SKU        string            `bson:"sku"`
+	Attributes map[string]string `bson:"attributes,omitempty"`
+	Price      float64           `bson:"price"`
+	Stock      int               `bson:"stock"`
+	Warehouses map[string]int    `bson:"warehouse_stock,omitempty"`
+}
+
 // toModel converts document to domain model
 func (d *productDocument) toModel() *model.Product {
+	var variants []model.Variant
+	for _, v := range d.Variants {
+		variants = append(variants, model.Variant{
+			SKU:            v.SKU,
+			Attributes:     v.Attributes,
+			Price:          v.Price,
+			Stock:          v.Stock,
+			WarehouseStock: v.Warehouses,
+		})
+	}
+
+	// Products stored before tenancy belong to the default tenant
+	tenantID := d.TenantID
+	if tenantID == "" {
+		tenantID = model.DefaultTenantID
+	}
+
 	return &model.Product{
-		ID:          d.ID.Hex(),
-		Name:        d.Name,
-		Description: d.Description,
-		Price:       d.Price,
-		Stock:       d.Stock,
-		CreatedAt:   d.CreatedAt,
-		UpdatedAt:   d.UpdatedAt,
-		DeletedAt:   d.DeletedAt,
+		ID:                d.ID.Hex(),
+		TenantID:          tenantID,
+		Name:              d.Name,
+		Description:       d.Description,
+		Price:             d.Price,
+		PriceVersion:      d.PriceVer,
+		Stock:             d.Stock,
+		LowStockThreshold: d.LowStock,
+		CategoryIDs:       d.CategoryIDs,
+		Variants:          variants,
+		WarehouseStock:    d.Warehouses,
+		Version:           d.Version,
+		CreatedAt:         d.CreatedAt,
+		UpdatedAt:         d.UpdatedAt,
+		DeletedAt:         d.DeletedAt,
 	}
 }
 
 // toDocument converts domain model to document
 func toProductDocument(p *model.Product) (*productDocument, error) {
 	doc := &productDocument{
+		TenantID:    p.TenantID,
 		Name:        p.Name,
 		Description: p.Description,
 		Price:       p.Price,
+		PriceVer:    p.PriceVersion,
 		Stock:       p.Stock,
+		LowStock:    p.LowStockThreshold,
+		CategoryIDs: p.CategoryIDs,
+		Variants:    toVariantDocuments(p.Variants),
+		Warehouses:  p.WarehouseStock,
+		Version:     p.Version,
 		CreatedAt:   p.CreatedAt,
 		UpdatedAt:   p.UpdatedAt,
 		DeletedAt:   p.DeletedAt,
@@ -75,56 +123,244 @@ func toProductDocument(p *model.Product) (*productDocument, error) {
 	return doc, nil
 }
 
+func toVariantDocuments(variants []model.Variant) []variantDocument {
+	docs := make([]variantDocument, len(variants))
+	for i, v := range variants {
+		docs[i] = variantDocument{
+			SKU:        v.SKU,
+			Attributes: v.Attributes,
+			Price:      v.Price,
+			Stock:      v.Stock,
+			Warehouses: v.WarehouseStock,
+		}
+	}
+	return docs
+}
+
+// versionFilter matches a document version, treating documents written before versioning as version zero
+func versionFilter(version int) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{nil, 0}}
+	}
+	return version
+}
+
 func (r *ProductRepository) collection() *mongo.Collection {
 	return r.client.GetCollection(productsCollection)
 }
 
-// Create creates a new product
+// Create creates a new product, in the tenant of ctx unless the product has one
 func (r *ProductRepository) Create(ctx context.Context, product *model.Product) (*model.Product, error) {
 	doc, err := toProductDocument(product)
 	if err != nil {
 		return nil, err
 	}
+	if doc.TenantID == "" {
+		doc.TenantID = model.TenantForCreate(ctx)
+	}
 
 	doc.ID = primitive.NewObjectID()
+	if doc.Version == 0 {
+		doc.Version = 1
+	}
 	doc.CreatedAt = time.Now()
 	doc.UpdatedAt = time.Now()
 
 	_, err = r.collection().InsertOne(ctx, doc)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, model.ErrVariantSKUExists
+		}
 		return nil, fmt.Errorf("failed to insert product: %w", err)
 	}
 
 	return doc.toModel(), nil
 }
 
-// Update updates an existing product
+// Update updates an existing product if it is still at the version it was read with.
+// On success the product's version is incremented; otherwise model.ErrVersionConflict is returned.
 func (r *ProductRepository) Update(ctx context.Context, product *model.Product) error {
 	oid, err := primitive.ObjectIDFromHex(product.ID)
 	if err != nil {
 		return fmt.Errorf("invalid product ID: %w", err)
 	}
 
-	filter := bson.M{"_id": oid, "deleted_at": nil}
-	update := bson.M{
-		"$set": bson.M{
-			"name":        product.Name,
-			"description": product.Description,
-			"price":       product.Price,
-			"stock":       product.Stock,
-			"updated_at":  time.Now(),
-		},
-	}
+	updatedAt := time.Now()
+	filter, update := productUpdate(ctx, oid, product, updatedAt)
 
 	result, err := r.collection().UpdateOne(ctx, filter, update)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return model.ErrVariantSKUExists
+		}
 		return fmt.Errorf("failed to update product: %w", err)
 	}
 
 	if result.MatchedCount == 0 {
-		return model.ErrProductNotFound
+		return model.ErrVersionConflict
 	}
 
+	product.Version++
+	product.UpdatedAt = updatedAt
+	return nil
+}
+
+// productUpdate builds the filter and update storing a product if still at the version it was read with
+func productUpdate(ctx context.Context, oid primitive.ObjectID, product *model.Product, updatedAt time.Time) (bson.M, bson.M) {
+	filter := tenantFilter(ctx, bson.M{"_id": oid, "deleted_at": nil, "version": versionFilter(product.Version)})
+	set := bson.M{
+		"name":                product.Name,
+		"description":         product.Description,
+		"price":               product.Price,
+		"price_version":       product.PriceVersion,
+		"stock":               product.Stock,
+		"low_stock_threshold": product.LowStockThreshold,
+		"category_ids":        product.CategoryIDs,
+		"variants":            toVariantDocuments(product.Variants),
+		"updated_at":          updatedAt,
+	}
+	update := bson.M{
+		"$set": set,
+		"$inc": bson.M{"version": 1},
+	}
+	// A null field could not be incremented into later, so drop it instead
+	if product.WarehouseStock != nil {
+		set["warehouse_stock"] = product.WarehouseStock
+	} else {
+		update["$unset"] = bson.M{"warehouse_stock": ""}
+	}
+	return filter, update
+}
+
+// BulkSave inserts the products without ID and updates the others if still at the version they were
+// read with, in a single unordered bulk write. It returns the error of each product that could not
+// be saved, indexed like products: model.ErrVariantSKUExists on a duplicate SKU and
+// model.ErrVersionConflict on a stale version.
+func (r *ProductRepository) BulkSave(ctx context.Context, products []*model.Product) ([]error, error) {
+	saveErrs := make([]error, len(products))
+	if len(products) == 0 {
+		return saveErrs, nil
+	}
+
+	now := time.Now()
+	// Every update of this save stamps the documents with saveRef, telling its writes apart from others
+	saveRef := primitive.NewObjectID().Hex()
+	writes := make([]mongo.WriteModel, len(products))
+	oids := make([]primitive.ObjectID, len(products))
+	for i, product := range products {
+		if product.ID == "" {
+			doc, err := toProductDocument(product)
+			if err != nil {
+				return nil, err
+			}
+			doc.ID = primitive.NewObjectID()
+			if doc.TenantID == "" {
+				doc.TenantID = model.TenantForCreate(ctx)
+			}
+			if doc.Version == 0 {
+				doc.Version = 1
+			}
+			doc.CreatedAt = now
+			doc.UpdatedAt = now
+			oids[i] = doc.ID
+			writes[i] = mongo.NewInsertOneModel().SetDocument(doc)
+			continue
+		}
+
+		oid, err := primitive.ObjectIDFromHex(product.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid product ID: %w", err)
+		}
+		oids[i] = oid
+		filter, update := productUpdate(ctx, oid, product, now)
+		update["$set"].(bson.M)["save_ref"] = saveRef
+		writes[i] = mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update)
+	}
+
+	result, err := r.collection().BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	var bulkErr mongo.BulkWriteException
+	if err != nil && !errors.As(err, &bulkErr) {
+		return nil, fmt.Errorf("failed to bulk write products: %w", err)
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.HasErrorCode(11000) {
+			saveErrs[writeErr.Index] = model.ErrVariantSKUExists
+		} else {
+			saveErrs[writeErr.Index] = fmt.Errorf("failed to save product: %s", writeErr.Message)
+		}
+	}
+	if bulkErr.WriteConcernError != nil {
+		return nil, fmt.Errorf("failed to bulk write products: %w", err)
+	}
+
+	// Updates that matched nothing lost a version race, find them by the save reference they lack
+	updates := 0
+	for i, product := range products {
+		if product.ID != "" && saveErrs[i] == nil {
+			updates++
+		}
+	}
+	if result != nil && result.MatchedCount < int64(updates) {
+		if err := r.markVersionConflicts(ctx, products, oids, saveRef, saveErrs); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, product := range products {
+		if saveErrs[i] != nil {
+			continue
+		}
+		if product.ID == "" {
+			product.ID = oids[i].Hex()
+			if product.Version == 0 {
+				product.Version = 1
+			}
+			product.CreatedAt = now
+		} else {
+			product.Version++
+		}
+		product.UpdatedAt = now
+	}
+	return saveErrs, nil
+}
+
+// markVersionConflicts sets model.ErrVersionConflict for the updated products not stamped with saveRef.
+// The stored version cannot tell, as stock changes increment it too.
+func (r *ProductRepository) markVersionConflicts(ctx context.Context, products []*model.Product, oids []primitive.ObjectID, saveRef string, saveErrs []error) error {
+	var ids []primitive.ObjectID
+	for i, product := range products {
+		if product.ID != "" && saveErrs[i] == nil {
+			ids = append(ids, oids[i])
+		}
+	}
+
+	filter := tenantFilter(ctx, bson.M{"_id": bson.M{"$in": ids}, "save_ref": saveRef})
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("failed to find products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	saved := make(map[primitive.ObjectID]bool, len(ids))
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("failed to decode product: %w", err)
+		}
+		saved[doc.ID] = true
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to iterate products: %w", err)
+	}
+
+	for i, product := range products {
+		if product.ID != "" && saveErrs[i] == nil && !saved[oids[i]] {
+			saveErrs[i] = model.ErrVersionConflict
+		}
+	}
 	return nil
 }
 
@@ -136,7 +372,7 @@ func (r *ProductRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	now := time.Now()
-	filter := bson.M{"_id": oid, "deleted_at": nil}
+	filter := tenantFilter(ctx, bson.M{"_id": oid, "deleted_at": nil})
 	update := bson.M{
 		"$set": bson.M{
 			"deleted_at": now,
@@ -163,7 +399,7 @@ func (r *ProductRepository) GetByID(ctx context.Context, id string) (*model.Prod
 		return nil, fmt.Errorf("invalid product ID: %w", err)
 	}
 
-	filter := bson.M{"_id": oid, "deleted_at": nil}
+	filter := tenantFilter(ctx, bson.M{"_id": oid, "deleted_at": nil})
 
 	var doc productDocument
 	err = r.collection().FindOne(ctx, filter).Decode(&doc)
@@ -177,9 +413,25 @@ func (r *ProductRepository) GetByID(ctx context.Context, id string) (*model.Prod
 	return doc.toModel(), nil
 }
 
+// GetBySKU retrieves the product owning the variant with the given SKU
+func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*model.Product, error) {
+	filter := tenantFilter(ctx, bson.M{"variants.sku": sku, "deleted_at": nil})
+
+	var doc productDocument
+	err := r.collection().FindOne(ctx, filter).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find product: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
 // GetByName retrieves a product by name
 func (r *ProductRepository) GetByName(ctx context.Context, name string) (*model.Product, error) {
-	filter := bson.M{"name": name, "deleted_at": nil}
+	filter := tenantFilter(ctx, bson.M{"name": name, "deleted_at": nil})
 
 	var doc productDocument
 	err := r.collection().FindOne(ctx, filter).Decode(&doc)
@@ -195,8 +447,131 @@ func (r *ProductRepository) GetByName(ctx context.Context, name string) (*model.
 
 // List retrieves products with pagination
 func (r *ProductRepository) List(ctx context.Context, offset, limit int) ([]*model.Product, int64, error) {
-	filter := bson.M{"deleted_at": nil}
+	return r.list(ctx, bson.M{"deleted_at": nil}, offset, limit)
+}
+
+// ListByCategoryIDs retrieves products assigned to any of the categories with pagination
+func (r *ProductRepository) ListByCategoryIDs(ctx context.Context, categoryIDs []string, offset, limit int) ([]*model.Product, int64, error) {
+	return r.list(ctx, bson.M{"deleted_at": nil, "category_ids": bson.M{"$in": categoryIDs}}, offset, limit)
+}
+
+// ReplaceCategory reassigns products from a category to its replacement, or just unassigns them
+// when replacementID is empty. It returns the IDs of the products changed.
+func (r *ProductRepository) ReplaceCategory(ctx context.Context, categoryID, replacementID string) ([]string, error) {
+	filter := tenantFilter(ctx, bson.M{"category_ids": categoryID})
+
+	cursor, err := r.collection().Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find products by category: %w", err)
+	}
+	var docs []productDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to find products by category: %w", err)
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(docs))
+	oids := make([]primitive.ObjectID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID.Hex()
+		oids[i] = doc.ID
+	}
+
+	// $addToSet and $pull cannot target the same field in one update, so use a pipeline
+	categoryIDs := bson.M{"$setDifference": bson.A{"$category_ids", bson.A{categoryID}}}
+	if replacementID != "" {
+		categoryIDs = bson.M{"$setUnion": bson.A{categoryIDs, bson.A{replacementID}}}
+	}
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"category_ids": categoryIDs,
+			"version":      bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$version", 0}}, 1}},
+			"updated_at":   time.Now(),
+		}},
+	}
+
+	if _, err := r.collection().UpdateMany(ctx, tenantFilter(ctx, bson.M{"_id": bson.M{"$in": oids}}), update); err != nil {
+		return nil, fmt.Errorf("failed to replace product category: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ListByNamesOrSKUs retrieves the products with any of the names or owning a variant with any of the SKUs
+func (r *ProductRepository) ListByNamesOrSKUs(ctx context.Context, names, skus []string) ([]*model.Product, error) {
+	or := bson.A{bson.M{"name": bson.M{"$in": names}}}
+	if len(skus) > 0 {
+		or = append(or, bson.M{"variants.sku": bson.M{"$in": skus}})
+	}
+
+	cursor, err := r.collection().Find(ctx, tenantFilter(ctx, bson.M{"deleted_at": nil, "$or": or}), options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var products []*model.Product
+	for cursor.Next(ctx) {
+		var doc productDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode product: %w", err)
+		}
+		products = append(products, doc.toModel())
+	}
+	return products, nil
+}
+
+// Stream calls fn for every product, in creation order, until fn returns an error.
+// Products are read through a cursor, so memory use does not grow with the catalog.
+func (r *ProductRepository) Stream(ctx context.Context, fn func(*model.Product) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection().Find(ctx, tenantFilter(ctx, bson.M{"deleted_at": nil}), opts)
+	if err != nil {
+		return fmt.Errorf("failed to find products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc productDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("failed to decode product: %w", err)
+		}
+		if err := fn(doc.toModel()); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to read products: %w", err)
+	}
+	return nil
+}
+
+// ListLowStock retrieves products with a low-stock threshold and a SKU at or below it, with pagination.
+// The SKUs are the variants, or the product itself when it has none.
+func (r *ProductRepository) ListLowStock(ctx context.Context, offset, limit int) ([]*model.Product, int64, error) {
+	variants := bson.M{"$ifNull": bson.A{"$variants", bson.A{}}}
+	filter := bson.M{
+		"deleted_at":          nil,
+		"low_stock_threshold": bson.M{"$gt": 0},
+		"$expr": bson.M{"$or": bson.A{
+			bson.M{"$and": bson.A{
+				bson.M{"$eq": bson.A{bson.M{"$size": variants}, 0}},
+				bson.M{"$lte": bson.A{"$stock", "$low_stock_threshold"}},
+			}},
+			bson.M{"$anyElementTrue": bson.A{bson.M{"$map": bson.M{
+				"input": variants,
+				"as":    "variant",
+				"in":    bson.M{"$lte": bson.A{"$$variant.stock", "$low_stock_threshold"}},
+			}}}},
+		}},
+	}
+	return r.list(ctx, filter, offset, limit)
+}
 
+func (r *ProductRepository) list(ctx context.Context, filter bson.M, offset, limit int) ([]*model.Product, int64, error) {
+	filter = tenantFilter(ctx, filter)
 	// Get total count
 	total, err := r.collection().CountDocuments(ctx, filter)
 	if err != nil {
@@ -227,27 +602,114 @@ func (r *ProductRepository) List(ctx context.Context, offset, limit int) ([]*mod
 	return products, total, nil
 }
 
-// UpdateStock updates product stock
-func (r *ProductRepository) UpdateStock(ctx context.Context, id string, quantity int) error {
+// UpdateStock updates the stock of a variant, or of the product when sku is empty, and the stock
+// held at the warehouse when warehouseID is not empty.
+// The product stock always changes too, as it holds the total of its variants.
+// A decrement is only applied while every stock it changes stays at or above zero, otherwise
+// it fails with model.ErrProductStockNegative.
+func (r *ProductRepository) UpdateStock(ctx context.Context, id, sku, warehouseID string, quantity int) error {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return fmt.Errorf("invalid product ID: %w", err)
 	}
 
-	filter := bson.M{"_id": oid, "deleted_at": nil}
+	filter := tenantFilter(ctx, bson.M{"_id": oid, "deleted_at": nil})
+	if sku != "" {
+		filter["variants.sku"] = sku
+	}
+	// The floor guard is applied like in ReserveStock, on a copy so the filter can tell a missing product apart
+	guarded := bson.M{}
+	for key, value := range filter {
+		guarded[key] = value
+	}
+
+	inc := bson.M{"stock": quantity, "version": 1}
+	if sku != "" {
+		inc["variants.$.stock"] = quantity
+		if warehouseID != "" {
+			inc["variants.$.warehouse_stock."+warehouseID] = quantity
+		}
+	} else if warehouseID != "" {
+		inc["warehouse_stock."+warehouseID] = quantity
+	}
+	if quantity < 0 {
+		guarded["stock"] = bson.M{"$gte": -quantity}
+		if sku != "" {
+			match := bson.M{"sku": sku, "stock": bson.M{"$gte": -quantity}}
+			if warehouseID != "" {
+				match["warehouse_stock."+warehouseID] = bson.M{"$gte": -quantity}
+			}
+			delete(guarded, "variants.sku")
+			guarded["variants"] = bson.M{"$elemMatch": match}
+		} else if warehouseID != "" {
+			guarded["warehouse_stock."+warehouseID] = bson.M{"$gte": -quantity}
+		}
+	}
 	update := bson.M{
-		"$inc": bson.M{"stock": quantity},
+		"$inc": inc,
 		"$set": bson.M{"updated_at": time.Now()},
 	}
 
-	result, err := r.collection().UpdateOne(ctx, filter, update)
+	result, err := r.collection().UpdateOne(ctx, guarded, update)
 	if err != nil {
 		return fmt.Errorf("failed to update stock: %w", err)
 	}
 
 	if result.MatchedCount == 0 {
+		if quantity < 0 {
+			count, err := r.collection().CountDocuments(ctx, filter)
+			if err != nil {
+				return fmt.Errorf("failed to update stock: %w", err)
+			}
+			if count > 0 {
+				return model.ErrProductStockNegative
+			}
+		}
+		if sku != "" {
+			return model.ErrVariantNotFound
+		}
 		return model.ErrProductNotFound
 	}
 
 	return nil
 }
+
+// ReserveStock atomically decrements the stock of a variant, or of the product when sku is empty,
+// taking it from the warehouse when warehouseID is not empty.
+// It fails with model.ErrProductInsufficientStock when not enough is left.
+func (r *ProductRepository) ReserveStock(ctx context.Context, id, sku, warehouseID string, quantity int) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid product ID: %w", err)
+	}
+
+	filter := tenantFilter(ctx, bson.M{"_id": oid, "deleted_at": nil, "stock": bson.M{"$gte": quantity}})
+	inc := bson.M{"stock": -quantity, "version": 1}
+	if sku != "" {
+		match := bson.M{"sku": sku, "stock": bson.M{"$gte": quantity}}
+		inc["variants.$.stock"] = -quantity
+		if warehouseID != "" {
+			match["warehouse_stock."+warehouseID] = bson.M{"$gte": quantity}
+			inc["variants.$.warehouse_stock."+warehouseID] = -quantity
+		}
+		filter["variants"] = bson.M{"$elemMatch": match}
+	} else if warehouseID != "" {
+		filter["warehouse_stock."+warehouseID] = bson.M{"$gte": quantity}
+		inc["warehouse_stock."+warehouseID] = -quantity
+	}
+	update := bson.M{
+		"$inc": inc,
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to reserve stock: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return model.ErrProductInsufficientStock
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// priceFacetBoundaries are the lower bounds of the price range facet buckets
+var priceFacetBoundaries = bson.A{0, 25, 50, 100, 250, 500, 1000}
+
+const priceFacetOverflow = "1000+"
+
+// searchDocument is the output of the search aggregation's $facet stage
+type searchDocument struct {
+	Results      []productDocument `bson:"results"`
+	Total        []facetBucket     `bson:"total"`
+	Categories   []facetBucket     `bson:"categories"`
+	PriceRanges  []facetBucket     `bson:"price_ranges"`
+	Availability []facetBucket     `bson:"availability"`
+}
+
+type facetBucket struct {
+	ID    interface{} `bson:"_id"`
+	Count int64       `bson:"count"`
+}
+
+// Search finds products matching the criteria in a single aggregation that also computes the facet counts
+func (r *ProductRepository) Search(ctx context.Context, criteria model.ProductSearchCriteria) (*model.ProductSearchResult, error) {
+	match := tenantFilter(ctx, bson.M{"deleted_at": nil})
+	if criteria.Query != "" {
+		match["$text"] = bson.M{"$search": criteria.Query}
+	}
+	price := bson.M{}
+	if criteria.MinPrice > 0 {
+		price["$gte"] = criteria.MinPrice
+	}
+	if criteria.MaxPrice > 0 {
+		price["$lte"] = criteria.MaxPrice
+	}
+	if len(price) > 0 {
+		match["price"] = price
+	}
+	if criteria.InStockOnly {
+		match["stock"] = bson.M{"$gt": 0}
+	}
+	if len(criteria.CategoryIDs) > 0 {
+		match["category_ids"] = bson.M{"$in": criteria.CategoryIDs}
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: match}}}
+	if criteria.Query != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"results": bson.A{
+			bson.M{"$sort": searchSort(criteria.Sort)},
+			bson.M{"$skip": criteria.Offset},
+			bson.M{"$limit": criteria.Limit},
+		},
+		"total": bson.A{
+			bson.M{"$group": bson.M{"_id": nil, "count": bson.M{"$sum": 1}}},
+		},
+		"categories": bson.A{
+			bson.M{"$unwind": "$category_ids"},
+			bson.M{"$sortByCount": "$category_ids"},
+		},
+		"price_ranges": bson.A{
+			bson.M{"$bucket": bson.M{
+				"groupBy":    "$price",
+				"boundaries": priceFacetBoundaries,
+				"default":    priceFacetOverflow,
+				"output":     bson.M{"count": bson.M{"$sum": 1}},
+			}},
+		},
+		"availability": bson.A{
+			bson.M{"$group": bson.M{
+				"_id":   bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$stock", 0}}, "in_stock", "out_of_stock"}},
+				"count": bson.M{"$sum": 1},
+			}},
+			bson.M{"$sort": bson.M{"_id": 1}},
+		},
+	}}})
+
+	cursor, err := r.collection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var doc searchDocument
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode product search: %w", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+
+	result := &model.ProductSearchResult{
+		Products: make([]*model.Product, len(doc.Results)),
+		Facets: model.ProductFacets{
+			Categories:   toFacetCounts(doc.Categories, facetValue),
+			PriceRanges:  toFacetCounts(doc.PriceRanges, priceRangeLabel),
+			Availability: toFacetCounts(doc.Availability, facetValue),
+		},
+	}
+	for i := range doc.Results {
+		result.Products[i] = doc.Results[i].toModel()
+	}
+	if len(doc.Total) > 0 {
+		result.Total = doc.Total[0].Count
+	}
+
+	return result, nil
+}
+
+// searchSort returns the sort stage for an ordering; ties are broken by ID to keep pages stable
+func searchSort(sort model.ProductSort) bson.D {
+	switch sort {
+	case model.ProductSortRelevance:
+		return bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}
+	case model.ProductSortPriceAsc:
+		return bson.D{{Key: "price", Value: 1}, {Key: "_id", Value: 1}}
+	case model.ProductSortPriceDesc:
+		return bson.D{{Key: "price", Value: -1}, {Key: "_id", Value: 1}}
+	default:
+		return bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}
+	}
+}
+
+func toFacetCounts(buckets []facetBucket, label func(interface{}) string) []model.FacetCount {
+	counts := make([]model.FacetCount, len(buckets))
+	for i, bucket := range buckets {
+		counts[i] = model.FacetCount{Value: label(bucket.ID), Count: bucket.Count}
+	}
+	return counts
+}
+
+func facetValue(id interface{}) string {
+	return fmt.Sprint(id)
+}
+
+// priceRangeLabel turns a $bucket lower boundary into a "lower-upper" label
+func priceRangeLabel(id interface{}) string {
+	for i, boundary := range priceFacetBoundaries {
+		if fmt.Sprint(boundary) == fmt.Sprint(id) && i+1 < len(priceFacetBoundaries) {
+			return fmt.Sprintf("%v-%v", boundary, priceFacetBoundaries[i+1])
+		}
+	}
+	return fmt.Sprint(id)
+}
@@ -0,0 +1,191 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+const stockMovementsCollection = "stock_movements"
+
+// StockMovementRepository implements IStockMovementRepo using MongoDB.
+// Movements are only ever inserted, never updated or deleted.
+type StockMovementRepository struct {
+	client *Client
+}
+
+// NewStockMovementRepository creates a new stock movement repository
+func NewStockMovementRepository(client *Client) repo.IStockMovementRepo {
+	return &StockMovementRepository{client: client}
+}
+
+// stockMovementDocument represents the MongoDB document
+type stockMovementDocument struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID    string             `bson:"tenant_id,omitempty"`
+	ProductID   string             `bson:"product_id"`
+	SKU         string             `bson:"sku"`
+	WarehouseID string             `bson:"warehouse_id,omitempty"`
+	Quantity    int                `bson:"quantity"`
+	Reason      string             `bson:"reason"`
+	ReferenceID string             `bson:"reference_id,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at"`
+}
+
+// toModel converts document to domain model
+func (d *stockMovementDocument) toModel() *model.StockMovement {
+	// Movements stored before tenancy belong to the default tenant
+	tenantID := d.TenantID
+	if tenantID == "" {
+		tenantID = model.DefaultTenantID
+	}
+
+	return &model.StockMovement{
+		ID:          d.ID.Hex(),
+		TenantID:    tenantID,
+		ProductID:   d.ProductID,
+		SKU:         d.SKU,
+		WarehouseID: d.WarehouseID,
+		Quantity:    d.Quantity,
+		Reason:      model.StockMovementReason(d.Reason),
+		ReferenceID: d.ReferenceID,
+		CreatedAt:   d.CreatedAt,
+	}
+}
+
+func (r *StockMovementRepository) collection() *mongo.Collection {
+	return r.client.GetCollection(stockMovementsCollection)
+}
+
+// Append adds movements to the ledger, in the tenant of ctx for movements without one
+func (r *StockMovementRepository) Append(ctx context.Context, movements ...*model.StockMovement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(movements))
+	for _, movement := range movements {
+		doc := &stockMovementDocument{
+			ID:          primitive.NewObjectID(),
+			TenantID:    movement.TenantID,
+			ProductID:   movement.ProductID,
+			SKU:         movement.SKU,
+			WarehouseID: movement.WarehouseID,
+			Quantity:    movement.Quantity,
+			Reason:      string(movement.Reason),
+			ReferenceID: movement.ReferenceID,
+			CreatedAt:   movement.CreatedAt,
+		}
+		if doc.TenantID == "" {
+			doc.TenantID = model.TenantForCreate(ctx)
+		}
+		movement.ID = doc.ID.Hex()
+		docs = append(docs, doc)
+	}
+
+	if _, err := r.collection().InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to insert stock movements: %w", err)
+	}
+
+	return nil
+}
+
+// ListByProductID retrieves the movements of a product, newest first, with pagination
+func (r *StockMovementRepository) ListByProductID(ctx context.Context, productID string, offset, limit int) ([]*model.StockMovement, int64, error) {
+	filter := tenantFilter(ctx, bson.M{"product_id": productID})
+
+	total, err := r.collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count stock movements: %w", err)
+	}
+
+	opts := options.Find().
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find stock movements: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var movements []*model.StockMovement
+	for cursor.Next(ctx) {
+		var doc stockMovementDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, 0, fmt.Errorf("failed to decode stock movement: %w", err)
+		}
+		movements = append(movements, doc.toModel())
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate stock movements: %w", err)
+	}
+
+	return movements, total, nil
+}
+
+// SumByProductIDs totals the movement quantities of the products by product ID and SKU
+func (r *StockMovementRepository) SumByProductIDs(ctx context.Context, productIDs []string) (map[string]map[string]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: tenantFilter(ctx, bson.M{"product_id": bson.M{"$in": productIDs}})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"product_id": "$product_id", "sku": "$sku"},
+			"quantity": bson.M{"$sum": "$quantity"},
+		}}},
+	}
+
+	cursor, err := r.collection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum stock movements: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		ID struct {
+			ProductID string `bson:"product_id"`
+			SKU       string `bson:"sku"`
+		} `bson:"_id"`
+		Quantity int `bson:"quantity"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode stock movement totals: %w", err)
+	}
+
+	totals := make(map[string]map[string]int, len(productIDs))
+	for _, row := range rows {
+		if totals[row.ID.ProductID] == nil {
+			totals[row.ID.ProductID] = make(map[string]int)
+		}
+		totals[row.ID.ProductID][row.ID.SKU] = row.Quantity
+	}
+
+	return totals, nil
+}
+
+// ProductIDsWithReason returns which of the products have at least one movement with the reason
+func (r *StockMovementRepository) ProductIDsWithReason(ctx context.Context, productIDs []string, reason model.StockMovementReason) (map[string]bool, error) {
+	filter := tenantFilter(ctx, bson.M{"product_id": bson.M{"$in": productIDs}, "reason": string(reason)})
+
+	ids, err := r.collection().Distinct(ctx, "product_id", filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stock movements: %w", err)
+	}
+
+	found := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if productID, ok := id.(string); ok {
+			found[productID] = true
+		}
+	}
+
+	return found, nil
+}
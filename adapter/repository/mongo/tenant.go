@@ -0,0 +1,27 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// tenantFilter restricts filter to the tenant of ctx, see model.TenantFromContext. Contexts without
+// a tenant fail closed and match nothing, unless they opted out with model.ContextWithoutTenant, as
+// scheduled jobs do, and see every tenant. Documents stored before tenancy have no tenant and
+// belong to the default one.
+func tenantFilter(ctx context.Context, filter bson.M) bson.M {
+	switch tenantID := model.TenantFromContext(ctx); tenantID {
+	case "":
+		if !model.SeesAllTenants(ctx) {
+			filter["tenant_id"] = bson.M{"$in": bson.A{}}
+		}
+	case model.DefaultTenantID:
+		filter["tenant_id"] = bson.M{"$in": bson.A{nil, tenantID}}
+	default:
+		filter["tenant_id"] = tenantID
+	}
+	return filter
+}
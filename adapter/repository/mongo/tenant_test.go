@@ -0,0 +1,46 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+func TestTenantFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want bson.M
+	}{
+		{
+			name: "restricts to the tenant",
+			ctx:  model.ContextWithTenant(context.Background(), "acme"),
+			want: bson.M{"_id": "p1", "tenant_id": "acme"},
+		},
+		{
+			name: "default tenant includes documents stored before tenancy",
+			ctx:  model.ContextWithTenant(context.Background(), model.DefaultTenantID),
+			want: bson.M{"_id": "p1", "tenant_id": bson.M{"$in": bson.A{nil, model.DefaultTenantID}}},
+		},
+		{
+			name: "jobs see every tenant",
+			ctx:  model.ContextWithoutTenant(context.Background()),
+			want: bson.M{"_id": "p1"},
+		},
+		{
+			name: "contexts without a tenant match nothing",
+			ctx:  context.Background(),
+			want: bson.M{"_id": "p1", "tenant_id": bson.M{"$in": bson.A{}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tenantFilter(tt.ctx, bson.M{"_id": "p1"}))
+		})
+	}
+}
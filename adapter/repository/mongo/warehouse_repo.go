@@ -0,0 +1,178 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+const warehousesCollection = "warehouses"
+
+// WarehouseRepository implements IWarehouseRepo using MongoDB
+type WarehouseRepository struct {
+	client *Client
+}
+
+// NewWarehouseRepository creates a new warehouse repository
+func NewWarehouseRepository(client *Client) repo.IWarehouseRepo {
+	return &WarehouseRepository{client: client}
+}
+
+// warehouseDocument represents the MongoDB document
+type warehouseDocument struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID  string             `bson:"tenant_id,omitempty"`
+	Code      string             `bson:"code"`
+	Name      string             `bson:"name"`
+	Latitude  float64            `bson:"latitude"`
+	Longitude float64            `bson:"longitude"`
+	Active    bool               `bson:"active"`
+	Version   int                `bson:"version"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+// toModel converts document to domain model
+func (d *warehouseDocument) toModel() *model.Warehouse {
+	// Warehouses stored before tenancy belong to the default tenant
+	tenantID := d.TenantID
+	if tenantID == "" {
+		tenantID = model.DefaultTenantID
+	}
+
+	return &model.Warehouse{
+		ID:        d.ID.Hex(),
+		TenantID:  tenantID,
+		Code:      d.Code,
+		Name:      d.Name,
+		Location:  model.Location{Latitude: d.Latitude, Longitude: d.Longitude},
+		Active:    d.Active,
+		Version:   d.Version,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
+
+func (r *WarehouseRepository) collection() *mongo.Collection {
+	return r.client.GetCollection(warehousesCollection)
+}
+
+// Create creates a new warehouse, in the tenant of ctx unless the warehouse has one. Codes are
+// unique within a tenant.
+func (r *WarehouseRepository) Create(ctx context.Context, warehouse *model.Warehouse) (*model.Warehouse, error) {
+	doc := &warehouseDocument{
+		ID:        primitive.NewObjectID(),
+		TenantID:  warehouse.TenantID,
+		Code:      warehouse.Code,
+		Name:      warehouse.Name,
+		Latitude:  warehouse.Location.Latitude,
+		Longitude: warehouse.Location.Longitude,
+		Active:    warehouse.Active,
+		Version:   warehouse.Version,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if doc.Version == 0 {
+		doc.Version = 1
+	}
+	if doc.TenantID == "" {
+		doc.TenantID = model.TenantForCreate(ctx)
+	}
+
+	if _, err := r.collection().InsertOne(ctx, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, model.ErrWarehouseCodeExists
+		}
+		return nil, fmt.Errorf("failed to insert warehouse: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+// Update updates a warehouse if it is still at the version it was read with.
+// On success the warehouse's version is incremented; otherwise model.ErrVersionConflict is returned.
+func (r *WarehouseRepository) Update(ctx context.Context, warehouse *model.Warehouse) error {
+	oid, err := primitive.ObjectIDFromHex(warehouse.ID)
+	if err != nil {
+		return fmt.Errorf("invalid warehouse ID: %w", err)
+	}
+
+	updatedAt := time.Now()
+	filter := tenantFilter(ctx, bson.M{"_id": oid, "version": warehouse.Version})
+	update := bson.M{
+		"$set": bson.M{
+			"name":       warehouse.Name,
+			"latitude":   warehouse.Location.Latitude,
+			"longitude":  warehouse.Location.Longitude,
+			"active":     warehouse.Active,
+			"updated_at": updatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := r.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update warehouse: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return model.ErrVersionConflict
+	}
+
+	warehouse.Version++
+	warehouse.UpdatedAt = updatedAt
+	return nil
+}
+
+// GetByID retrieves a warehouse by ID
+func (r *WarehouseRepository) GetByID(ctx context.Context, id string) (*model.Warehouse, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		// IDs that are not ObjectIDs cannot match any warehouse
+		return nil, nil
+	}
+
+	var doc warehouseDocument
+	err = r.collection().FindOne(ctx, tenantFilter(ctx, bson.M{"_id": oid})).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find warehouse: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+// List retrieves all warehouses ordered by code
+func (r *WarehouseRepository) List(ctx context.Context) ([]*model.Warehouse, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "code", Value: 1}})
+
+	cursor, err := r.collection().Find(ctx, tenantFilter(ctx, bson.M{}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find warehouses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var warehouses []*model.Warehouse
+	for cursor.Next(ctx) {
+		var doc warehouseDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode warehouse: %w", err)
+		}
+		warehouses = append(warehouses, doc.toModel())
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate warehouses: %w", err)
+	}
+
+	return warehouses, nil
+}
@@ -0,0 +1,187 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// APIKeyRepository implements IAPIKeyRepo using PostgreSQL
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *gorm.DB) repo.IAPIKeyRepo {
+	return &APIKeyRepository{db: db}
+}
+
+// apiKeyEntity represents the database entity
+type apiKeyEntity struct {
+	ID         string     `gorm:"primaryKey;type:uuid"`
+	TenantID   string     `gorm:"not null;default:'default';index"`
+	Name       string     `gorm:"not null"`
+	Prefix     string     `gorm:"uniqueIndex;not null"`
+	Hash       string     `gorm:"not null"`
+	Scopes     string     `gorm:"type:text;not null"` // comma separated
+	CreatedBy  string     `gorm:"not null;default:''"`
+	ExpiresAt  *time.Time `gorm:"index"`
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	Version    int       `gorm:"not null;default:1"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+func (apiKeyEntity) TableName() string {
+	return "api_keys"
+}
+
+// toModel converts entity to domain model
+func (e *apiKeyEntity) toModel() *model.APIKey {
+	return &model.APIKey{
+		ID:         e.ID,
+		TenantID:   e.TenantID,
+		Name:       e.Name,
+		Prefix:     e.Prefix,
+		Hash:       e.Hash,
+		Scopes:     splitList[model.Permission](e.Scopes),
+		CreatedBy:  e.CreatedBy,
+		ExpiresAt:  e.ExpiresAt,
+		LastUsedAt: e.LastUsedAt,
+		RevokedAt:  e.RevokedAt,
+		Version:    e.Version,
+		CreatedAt:  e.CreatedAt,
+		UpdatedAt:  e.UpdatedAt,
+	}
+}
+
+// toAPIKeyEntity converts domain model to entity
+func toAPIKeyEntity(k *model.APIKey) *apiKeyEntity {
+	return &apiKeyEntity{
+		ID:         k.ID,
+		TenantID:   k.TenantID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Hash:       k.Hash,
+		Scopes:     joinList(k.Scopes),
+		CreatedBy:  k.CreatedBy,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		Version:    k.Version,
+		CreatedAt:  k.CreatedAt,
+		UpdatedAt:  k.UpdatedAt,
+	}
+}
+
+func (r *APIKeyRepository) getDB(ctx context.Context, tx repo.Transaction) *gorm.DB {
+	if tx != nil {
+		if gormTx, ok := tx.GetTx().(*gorm.DB); ok {
+			return gormTx.WithContext(ctx)
+		}
+	}
+	return r.db.WithContext(ctx)
+}
+
+// Create creates a new API key in the tenant of ctx unless the key names one
+func (r *APIKeyRepository) Create(ctx context.Context, tx repo.Transaction, key *model.APIKey) (*model.APIKey, error) {
+	if key.TenantID == "" {
+		key.TenantID = model.TenantForCreate(ctx)
+	}
+	entity := toAPIKeyEntity(key)
+	db := r.getDB(ctx, tx)
+
+	if err := db.Create(entity).Error; err != nil {
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
+
+// Update updates an existing API key if it is still at the version it was read with.
+// On success the key's version is incremented; otherwise model.ErrVersionConflict is returned.
+func (r *APIKeyRepository) Update(ctx context.Context, tx repo.Transaction, key *model.APIKey) error {
+	db := r.getDB(ctx, tx)
+
+	updatedAt := time.Now()
+	result := db.Model(&apiKeyEntity{}).Scopes(tenantScope(ctx)).
+		Where("id = ? AND version = ?", key.ID, key.Version).
+		Updates(map[string]interface{}{
+			"name":       key.Name,
+			"prefix":     key.Prefix,
+			"hash":       key.Hash,
+			"scopes":     joinList(key.Scopes),
+			"expires_at": key.ExpiresAt,
+			"revoked_at": key.RevokedAt,
+			"version":    key.Version + 1,
+			"updated_at": updatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrVersionConflict
+	}
+
+	key.Version++
+	key.UpdatedAt = updatedAt
+	return nil
+}
+
+// GetByID retrieves an API key by ID
+func (r *APIKeyRepository) GetByID(ctx context.Context, tx repo.Transaction, id string) (*model.APIKey, error) {
+	return r.first(r.getDB(ctx, tx).Scopes(tenantScope(ctx)).Where("id = ?", id))
+}
+
+// GetByPrefix retrieves an API key by its lookup prefix in any tenant: the key is looked up
+// before the request is bound to a tenant, which it then names, see model.APIKey.Principal
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, tx repo.Transaction, prefix string) (*model.APIKey, error) {
+	return r.first(r.getDB(ctx, tx).Where("prefix = ?", prefix))
+}
+
+// List retrieves API keys with pagination, newest first
+func (r *APIKeyRepository) List(ctx context.Context, tx repo.Transaction, offset, limit int) ([]*model.APIKey, int64, error) {
+	var entities []apiKeyEntity
+	var total int64
+	db := r.getDB(ctx, tx)
+
+	if err := db.Model(&apiKeyEntity{}).Scopes(tenantScope(ctx)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := db.Scopes(tenantScope(ctx)).Order("created_at DESC").Offset(offset).Limit(limit).Find(&entities).Error; err != nil {
+		return nil, 0, err
+	}
+
+	keys := make([]*model.APIKey, len(entities))
+	for i := range entities {
+		keys[i] = entities[i].toModel()
+	}
+
+	return keys, total, nil
+}
+
+// TouchLastUsed records when an API key was last used, without changing its version. Like
+// GetByPrefix it runs before the request is bound to the tenant of the key, so it is not scoped.
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, tx repo.Transaction, id string, at time.Time) error {
+	db := r.getDB(ctx, tx)
+	return db.Model(&apiKeyEntity{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+}
+
+// first returns the first API key matching the query, or nil
+func (r *APIKeyRepository) first(query *gorm.DB) (*model.APIKey, error) {
+	var entity apiKeyEntity
+	if err := query.First(&entity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return entity.toModel(), nil
+}
@@ -0,0 +1,243 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// invoiceSeries maps invoice kinds to their numbering series prefix
+var invoiceSeries = map[model.InvoiceKind]string{
+	model.InvoiceKindInvoice:    "INV",
+	model.InvoiceKindCreditNote: "CN",
+}
+
+// InvoiceRepository implements IInvoiceRepo using PostgreSQL
+type InvoiceRepository struct {
+	db *gorm.DB
+}
+
+// NewInvoiceRepository creates a new invoice repository
+func NewInvoiceRepository(db *gorm.DB) repo.IInvoiceRepo {
+	return &InvoiceRepository{db: db}
+}
+
+// invoiceEntity represents the database entity
+type invoiceEntity struct {
+	ID                string              `gorm:"primaryKey;type:uuid"`
+	TenantID          string              `gorm:"not null;default:'default';uniqueIndex:idx_invoices_tenant_number"`
+	Number            string              `gorm:"not null;uniqueIndex:idx_invoices_tenant_number"`
+	Kind              string              `gorm:"not null;default:'invoice'"`
+	OrderID           string              `gorm:"type:uuid;not null;index"`
+	UserID            string              `gorm:"type:uuid;not null"`
+	ReturnID          *string             `gorm:"type:uuid"`
+	OriginalInvoiceID *string             `gorm:"type:uuid"`
+	Subtotal          float64             `gorm:"type:decimal(10,2);not null"`
+	TaxTotal          float64             `gorm:"type:decimal(10,2);not null"`
+	Total             float64             `gorm:"type:decimal(10,2);not null"`
+	IssuedAt          time.Time           `gorm:"not null"`
+	CreatedAt         time.Time           `gorm:"autoCreateTime"`
+	Lines             []invoiceLineEntity `gorm:"foreignKey:InvoiceID"`
+}
+
+func (invoiceEntity) TableName() string {
+	return "invoices"
+}
+
+// invoiceLineEntity represents the invoice line database entity
+type invoiceLineEntity struct {
+	ID          string  `gorm:"primaryKey;type:uuid"`
+	InvoiceID   string  `gorm:"type:uuid;not null;index"`
+	ProductID   string  `gorm:"not null"`
+	SKU         string  `gorm:"not null;default:''"`
+	Description string  `gorm:"type:text;not null"`
+	Quantity    int     `gorm:"not null"`
+	UnitPrice   float64 `gorm:"type:decimal(10,2);not null"`
+	TaxRate     float64 `gorm:"type:decimal(6,4);not null;default:0"`
+	Subtotal    float64 `gorm:"type:decimal(10,2);not null"`
+	Tax         float64 `gorm:"type:decimal(10,2);not null"`
+	Total       float64 `gorm:"type:decimal(10,2);not null"`
+}
+
+func (invoiceLineEntity) TableName() string {
+	return "invoice_lines"
+}
+
+// toModel converts entity to domain model
+func (e *invoiceEntity) toModel() *model.Invoice {
+	lines := make([]model.InvoiceLine, len(e.Lines))
+	for i, line := range e.Lines {
+		lines[i] = model.InvoiceLine{
+			ID:          line.ID,
+			InvoiceID:   line.InvoiceID,
+			ProductID:   line.ProductID,
+			SKU:         line.SKU,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			TaxRate:     line.TaxRate,
+			Subtotal:    line.Subtotal,
+			Tax:         line.Tax,
+			Total:       line.Total,
+		}
+	}
+
+	invoice := &model.Invoice{
+		ID:        e.ID,
+		TenantID:  e.TenantID,
+		Number:    e.Number,
+		Kind:      model.InvoiceKind(e.Kind),
+		OrderID:   e.OrderID,
+		UserID:    e.UserID,
+		Lines:     lines,
+		Subtotal:  e.Subtotal,
+		TaxTotal:  e.TaxTotal,
+		Total:     e.Total,
+		IssuedAt:  e.IssuedAt,
+		CreatedAt: e.CreatedAt,
+	}
+	if e.ReturnID != nil {
+		invoice.ReturnID = *e.ReturnID
+	}
+	if e.OriginalInvoiceID != nil {
+		invoice.OriginalInvoiceID = *e.OriginalInvoiceID
+	}
+	return invoice
+}
+
+// toInvoiceEntity converts domain model to entity
+func toInvoiceEntity(i *model.Invoice) *invoiceEntity {
+	lines := make([]invoiceLineEntity, len(i.Lines))
+	for idx, line := range i.Lines {
+		lines[idx] = invoiceLineEntity{
+			ID:          line.ID,
+			InvoiceID:   line.InvoiceID,
+			ProductID:   line.ProductID,
+			SKU:         line.SKU,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			TaxRate:     line.TaxRate,
+			Subtotal:    line.Subtotal,
+			Tax:         line.Tax,
+			Total:       line.Total,
+		}
+	}
+
+	entity := &invoiceEntity{
+		ID:        i.ID,
+		TenantID:  i.TenantID,
+		Number:    i.Number,
+		Kind:      string(i.Kind),
+		OrderID:   i.OrderID,
+		UserID:    i.UserID,
+		Subtotal:  i.Subtotal,
+		TaxTotal:  i.TaxTotal,
+		Total:     i.Total,
+		IssuedAt:  i.IssuedAt,
+		CreatedAt: i.CreatedAt,
+		Lines:     lines,
+	}
+	if i.ReturnID != "" {
+		entity.ReturnID = &i.ReturnID
+	}
+	if i.OriginalInvoiceID != "" {
+		entity.OriginalInvoiceID = &i.OriginalInvoiceID
+	}
+	return entity
+}
+
+func (r *InvoiceRepository) getDB(ctx context.Context, tx repo.Transaction) *gorm.DB {
+	if tx != nil {
+		if gormTx, ok := tx.GetTx().(*gorm.DB); ok {
+			return gormTx.WithContext(ctx)
+		}
+	}
+	return r.db.WithContext(ctx)
+}
+
+// Create assigns the next number of the invoice's tenant, series and year and stores the invoice with
+// its lines. Numbering and insert share one transaction: the sequence row stays locked until commit, so
+// concurrent issuers wait for each other, and a failed insert rolls the number back, leaving no gaps.
+func (r *InvoiceRepository) Create(ctx context.Context, tx repo.Transaction, invoice *model.Invoice) (*model.Invoice, error) {
+	series, ok := invoiceSeries[invoice.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown invoice kind: %s", invoice.Kind)
+	}
+	year := invoice.IssuedAt.UTC().Year()
+	if invoice.TenantID == "" {
+		invoice.TenantID = model.TenantForCreate(ctx)
+	}
+
+	entity := toInvoiceEntity(invoice)
+	err := r.getDB(ctx, tx).Transaction(func(db *gorm.DB) error {
+		var number int
+		if err := db.Raw(
+			`INSERT INTO invoice_sequences (tenant_id, series, year, last_number) VALUES (?, ?, ?, 1)
+			ON CONFLICT (tenant_id, series, year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+			RETURNING last_number`, entity.TenantID, series, year,
+		).Scan(&number).Error; err != nil {
+			return err
+		}
+
+		entity.Number = fmt.Sprintf("%s-%d-%06d", series, year, number)
+		return db.Create(entity).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.Number = entity.Number
+	return entity.toModel(), nil
+}
+
+// GetByOrderID retrieves the invoice of an order with lines
+func (r *InvoiceRepository) GetByOrderID(ctx context.Context, tx repo.Transaction, orderID string) (*model.Invoice, error) {
+	return r.getOne(ctx, tx, "order_id = ? AND kind = ?", orderID, string(model.InvoiceKindInvoice))
+}
+
+// GetByReturnID retrieves the credit note of a return with lines
+func (r *InvoiceRepository) GetByReturnID(ctx context.Context, tx repo.Transaction, returnID string) (*model.Invoice, error) {
+	return r.getOne(ctx, tx, "return_id = ?", returnID)
+}
+
+// ListCreditNotesByOrderID retrieves the credit notes of an order with lines, oldest first
+func (r *InvoiceRepository) ListCreditNotesByOrderID(ctx context.Context, tx repo.Transaction, orderID string) ([]*model.Invoice, error) {
+	var entities []invoiceEntity
+	db := r.getDB(ctx, tx)
+
+	if err := db.Preload("Lines").Scopes(tenantScope(ctx)).
+		Where("order_id = ? AND kind = ?", orderID, string(model.InvoiceKindCreditNote)).
+		Order("issued_at ASC").Find(&entities).Error; err != nil {
+		return nil, err
+	}
+
+	invoices := make([]*model.Invoice, len(entities))
+	for i, e := range entities {
+		invoices[i] = e.toModel()
+	}
+
+	return invoices, nil
+}
+
+// getOne retrieves the first invoice matching the condition, or nil if there is none
+func (r *InvoiceRepository) getOne(ctx context.Context, tx repo.Transaction, query string, args ...interface{}) (*model.Invoice, error) {
+	var entity invoiceEntity
+	db := r.getDB(ctx, tx)
+
+	err := db.Preload("Lines").Scopes(tenantScope(ctx)).Where(query, args...).First(&entity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
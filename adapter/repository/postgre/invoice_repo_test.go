@@ -0,0 +1,143 @@
+package postgre
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+func TestInvoiceRepositoryNumbering(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping PostgreSQL container test in short mode")
+	}
+
+	db := GetTestDB(t, SetupPostgreSQLContainer(t))
+	migrateInvoices(t, db.DB)
+	invoices := NewInvoiceRepository(db.DB)
+	ctx := model.ContextWithTenant(context.Background(), "acme")
+
+	issue := func(kind model.InvoiceKind, year int) (*model.Invoice, error) {
+		return invoices.Create(ctx, nil, testInvoice(t, kind, "acme", year))
+	}
+
+	t.Run("numbers each series and year in sequence", func(t *testing.T) {
+		tests := []struct {
+			kind model.InvoiceKind
+			year int
+			want string
+		}{
+			{model.InvoiceKindInvoice, 2030, "INV-2030-000001"},
+			{model.InvoiceKindInvoice, 2030, "INV-2030-000002"},
+			{model.InvoiceKindCreditNote, 2030, "CN-2030-000001"},
+			{model.InvoiceKindInvoice, 2031, "INV-2031-000001"},
+			{model.InvoiceKindInvoice, 2030, "INV-2030-000003"},
+		}
+		for _, tt := range tests {
+			created, err := issue(tt.kind, tt.year)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, created.Number)
+
+			found, err := invoices.GetByOrderID(ctx, nil, created.OrderID)
+			require.NoError(t, err)
+			if tt.kind == model.InvoiceKindInvoice {
+				require.NotNil(t, found)
+				assert.Equal(t, tt.want, found.Number)
+				assert.Len(t, found.Lines, 1)
+			}
+		}
+	})
+
+	t.Run("a failed insert leaves no gap", func(t *testing.T) {
+		first, err := issue(model.InvoiceKindInvoice, 2032)
+		require.NoError(t, err)
+
+		duplicate := *first
+		_, err = invoices.Create(ctx, nil, &duplicate)
+		require.Error(t, err)
+
+		next, err := issue(model.InvoiceKindInvoice, 2032)
+		require.NoError(t, err)
+		assert.Equal(t, "INV-2032-000002", next.Number)
+	})
+
+	t.Run("concurrent issuers get distinct consecutive numbers", func(t *testing.T) {
+		const issuers = 20
+
+		var wg sync.WaitGroup
+		numbers := make(chan string, issuers)
+		for i := 0; i < issuers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				created, err := issue(model.InvoiceKindInvoice, 2033)
+				if assert.NoError(t, err) {
+					numbers <- created.Number
+				}
+			}()
+		}
+		wg.Wait()
+		close(numbers)
+
+		seen := make(map[string]bool, issuers)
+		for number := range numbers {
+			seen[number] = true
+		}
+		require.Len(t, seen, issuers)
+		for n := 1; n <= issuers; n++ {
+			assert.Contains(t, seen, fmt.Sprintf("INV-2033-%06d", n))
+		}
+	})
+
+	t.Run("each tenant numbers its own invoices", func(t *testing.T) {
+		globex := model.ContextWithTenant(context.Background(), "globex")
+		for _, want := range []string{"INV-2030-000001", "INV-2030-000002"} {
+			created, err := invoices.Create(globex, nil, testInvoice(t, model.InvoiceKindInvoice, "globex", 2030))
+			require.NoError(t, err)
+			assert.Equal(t, want, created.Number)
+			assert.Equal(t, "globex", created.TenantID)
+		}
+
+		next, err := issue(model.InvoiceKindInvoice, 2030)
+		require.NoError(t, err)
+		assert.Equal(t, "INV-2030-000004", next.Number)
+	})
+}
+
+// migrateInvoices creates the invoice tables and the numbering sequences of init-postgres.sql
+func migrateInvoices(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	require.NoError(t, db.AutoMigrate(&invoiceEntity{}, &invoiceLineEntity{}))
+	require.NoError(t, db.Exec(`CREATE TABLE invoice_sequences (
+		tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+		series VARCHAR(10) NOT NULL,
+		year INTEGER NOT NULL,
+		last_number INTEGER NOT NULL,
+		PRIMARY KEY (tenant_id, series, year)
+	)`).Error)
+}
+
+// testInvoice returns an invoice of the kind for a delivered order of the tenant, issued in year
+func testInvoice(t *testing.T, kind model.InvoiceKind, tenantID string, year int) *model.Invoice {
+	t.Helper()
+	order := &model.Order{
+		ID:       uuid.New().String(),
+		TenantID: tenantID,
+		UserID:   uuid.New().String(),
+		Status:   model.OrderStatusDelivered,
+		Items:    []model.OrderItem{{ProductID: "p1", Quantity: 1, Price: 10}},
+	}
+	invoice, err := model.NewInvoice(order, nil, 0)
+	require.NoError(t, err)
+	invoice.Kind = kind
+	invoice.IssuedAt = time.Date(year, time.March, 1, 12, 0, 0, 0, time.UTC)
+	return invoice
+}
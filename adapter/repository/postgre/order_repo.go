@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
@@ -23,14 +24,27 @@ func NewOrderRepository(db *gorm.DB) repo.IOrderRepo {
 
 // orderEntity represents the database entity
 type orderEntity struct {
-	ID        string            `gorm:"primaryKey;type:uuid"`
-	UserID    string            `gorm:"type:uuid;not null;index"`
-	Total     float64           `gorm:"type:decimal(10,2);not null;default:0"`
-	Status    string            `gorm:"not null;default:'pending'"`
-	CreatedAt time.Time         `gorm:"autoCreateTime"`
-	UpdatedAt time.Time         `gorm:"autoUpdateTime"`
-	DeletedAt *time.Time        `gorm:"index"`
-	Items     []orderItemEntity `gorm:"foreignKey:OrderID"`
+	ID                  string  `gorm:"primaryKey;type:uuid"`
+	TenantID            string  `gorm:"not null;default:'default';index"`
+	UserID              string  `gorm:"type:uuid;not null;index"`
+	OrganizationID      string  `gorm:"not null;default:'';index"`
+	Total               float64 `gorm:"type:decimal(10,2);not null;default:0"`
+	Status              string  `gorm:"not null;default:'pending'"`
+	ShipLatitude        *float64
+	ShipLongitude       *float64
+	ShippingAddress     addressColumns `gorm:"embedded;embeddedPrefix:shipping_"`
+	BillingAddress      addressColumns `gorm:"embedded;embeddedPrefix:billing_"`
+	ApprovalRequired    bool           `gorm:"not null;default:false"`
+	ApprovalDecision    string         `gorm:"not null;default:''"`
+	ApprovalDecidedBy   string         `gorm:"not null;default:''"`
+	ApprovalDecidedAt   *time.Time
+	ApprovalReason      string `gorm:"not null;default:''"`
+	ApprovalEscalatedAt *time.Time
+	Version             int               `gorm:"not null;default:1"`
+	CreatedAt           time.Time         `gorm:"autoCreateTime"`
+	UpdatedAt           time.Time         `gorm:"autoUpdateTime"`
+	DeletedAt           *time.Time        `gorm:"index"`
+	Items               []orderItemEntity `gorm:"foreignKey:OrderID"`
 }
 
 func (orderEntity) TableName() string {
@@ -39,41 +53,87 @@ func (orderEntity) TableName() string {
 
 // orderItemEntity represents the order item database entity
 type orderItemEntity struct {
-	ID        string    `gorm:"primaryKey;type:uuid"`
-	OrderID   string    `gorm:"type:uuid;not null;index"`
-	ProductID string    `gorm:"not null;index"`
-	Quantity  int       `gorm:"not null;default:1"`
-	Price     float64   `gorm:"type:decimal(10,2);not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
+	ID           string                      `gorm:"primaryKey;type:uuid"`
+	OrderID      string                      `gorm:"type:uuid;not null;index"`
+	ProductID    string                      `gorm:"not null;index"`
+	SKU          string                      `gorm:"not null;default:''"`
+	Quantity     int                         `gorm:"not null;default:1"`
+	Price        float64                     `gorm:"type:decimal(10,2);not null"`
+	PriceVersion int                         `gorm:"not null;default:0"`
+	CreatedAt    time.Time                   `gorm:"autoCreateTime"`
+	Allocations  []orderItemAllocationEntity `gorm:"foreignKey:OrderItemID"`
 }
 
 func (orderItemEntity) TableName() string {
 	return "order_items"
 }
 
+// orderItemAllocationEntity represents the warehouse allocation of an order item
+type orderItemAllocationEntity struct {
+	ID          string `gorm:"primaryKey;type:uuid"`
+	OrderItemID string `gorm:"type:uuid;not null;index"`
+	WarehouseID string `gorm:"not null;default:''"`
+	Quantity    int    `gorm:"not null"`
+}
+
+func (orderItemAllocationEntity) TableName() string {
+	return "order_item_allocations"
+}
+
 // toModel converts entity to domain model
 func (e *orderEntity) toModel() *model.Order {
 	items := make([]model.OrderItem, len(e.Items))
 	for i, item := range e.Items {
 		items[i] = model.OrderItem{
-			ID:        item.ID,
-			OrderID:   item.OrderID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-			CreatedAt: item.CreatedAt,
+			ID:           item.ID,
+			OrderID:      item.OrderID,
+			ProductID:    item.ProductID,
+			SKU:          item.SKU,
+			Quantity:     item.Quantity,
+			Price:        item.Price,
+			PriceVersion: item.PriceVersion,
+			CreatedAt:    item.CreatedAt,
+		}
+		for _, allocation := range item.Allocations {
+			items[i].Allocations = append(items[i].Allocations, model.StockAllocation{
+				WarehouseID: allocation.WarehouseID,
+				Quantity:    allocation.Quantity,
+			})
+		}
+	}
+
+	var shipTo *model.Location
+	if e.ShipLatitude != nil && e.ShipLongitude != nil {
+		shipTo = &model.Location{Latitude: *e.ShipLatitude, Longitude: *e.ShipLongitude}
+	}
+
+	var approval *model.OrderApproval
+	if e.ApprovalRequired {
+		approval = &model.OrderApproval{
+			Decision:    model.ApprovalDecision(e.ApprovalDecision),
+			DecidedBy:   e.ApprovalDecidedBy,
+			DecidedAt:   e.ApprovalDecidedAt,
+			Reason:      e.ApprovalReason,
+			EscalatedAt: e.ApprovalEscalatedAt,
 		}
 	}
 
 	return &model.Order{
-		ID:        e.ID,
-		UserID:    e.UserID,
-		Items:     items,
-		Total:     e.Total,
-		Status:    model.OrderStatus(e.Status),
-		CreatedAt: e.CreatedAt,
-		UpdatedAt: e.UpdatedAt,
-		DeletedAt: e.DeletedAt,
+		ID:              e.ID,
+		TenantID:        e.TenantID,
+		UserID:          e.UserID,
+		OrganizationID:  e.OrganizationID,
+		Items:           items,
+		Total:           e.Total,
+		Status:          model.OrderStatus(e.Status),
+		ShipTo:          shipTo,
+		ShippingAddress: e.ShippingAddress.toAddress(),
+		BillingAddress:  e.BillingAddress.toAddress(),
+		Approval:        approval,
+		Version:         e.Version,
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+		DeletedAt:       e.DeletedAt,
 	}
 }
 
@@ -82,25 +142,54 @@ func toOrderEntity(o *model.Order) *orderEntity {
 	items := make([]orderItemEntity, len(o.Items))
 	for i, item := range o.Items {
 		items[i] = orderItemEntity{
-			ID:        item.ID,
-			OrderID:   item.OrderID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-			CreatedAt: item.CreatedAt,
+			ID:           item.ID,
+			OrderID:      item.OrderID,
+			ProductID:    item.ProductID,
+			SKU:          item.SKU,
+			Quantity:     item.Quantity,
+			Price:        item.Price,
+			PriceVersion: item.PriceVersion,
+			CreatedAt:    item.CreatedAt,
+		}
+		for _, allocation := range item.Allocations {
+			items[i].Allocations = append(items[i].Allocations, orderItemAllocationEntity{
+				ID:          uuid.New().String(),
+				OrderItemID: item.ID,
+				WarehouseID: allocation.WarehouseID,
+				Quantity:    allocation.Quantity,
+			})
 		}
 	}
 
-	return &orderEntity{
-		ID:        o.ID,
-		UserID:    o.UserID,
-		Total:     o.Total,
-		Status:    string(o.Status),
-		CreatedAt: o.CreatedAt,
-		UpdatedAt: o.UpdatedAt,
-		DeletedAt: o.DeletedAt,
-		Items:     items,
+	entity := &orderEntity{
+		ID:              o.ID,
+		TenantID:        o.TenantID,
+		UserID:          o.UserID,
+		OrganizationID:  o.OrganizationID,
+		Total:           o.Total,
+		Status:          string(o.Status),
+		ShippingAddress: toAddressColumns(o.ShippingAddress),
+		BillingAddress:  toAddressColumns(o.BillingAddress),
+		Version:         o.Version,
+		CreatedAt:       o.CreatedAt,
+		UpdatedAt:       o.UpdatedAt,
+		DeletedAt:       o.DeletedAt,
+		Items:           items,
 	}
+	if o.ShipTo != nil {
+		entity.ShipLatitude = &o.ShipTo.Latitude
+		entity.ShipLongitude = &o.ShipTo.Longitude
+	}
+	if o.Approval != nil {
+		entity.ApprovalRequired = true
+		entity.ApprovalDecision = string(o.Approval.Decision)
+		entity.ApprovalDecidedBy = o.Approval.DecidedBy
+		entity.ApprovalDecidedAt = o.Approval.DecidedAt
+		entity.ApprovalReason = o.Approval.Reason
+		entity.ApprovalEscalatedAt = o.Approval.EscalatedAt
+	}
+
+	return entity
 }
 
 func (r *OrderRepository) getDB(ctx context.Context, tx repo.Transaction) *gorm.DB {
@@ -114,6 +203,9 @@ func (r *OrderRepository) getDB(ctx context.Context, tx repo.Transaction) *gorm.
 
 // Create creates a new order with items
 func (r *OrderRepository) Create(ctx context.Context, tx repo.Transaction, order *model.Order) (*model.Order, error) {
+	if order.TenantID == "" {
+		order.TenantID = model.TenantForCreate(ctx)
+	}
 	entity := toOrderEntity(order)
 	db := r.getDB(ctx, tx)
 
@@ -124,12 +216,41 @@ func (r *OrderRepository) Create(ctx context.Context, tx repo.Transaction, order
 	return entity.toModel(), nil
 }
 
-// Update updates an existing order
+// Update updates an existing order if it is still at the version it was read with.
+// On success the order's version is incremented; otherwise model.ErrVersionConflict is returned.
 func (r *OrderRepository) Update(ctx context.Context, tx repo.Transaction, order *model.Order) error {
-	entity := toOrderEntity(order)
 	db := r.getDB(ctx, tx)
 
-	return db.Save(entity).Error
+	updatedAt := time.Now()
+	updates := map[string]interface{}{
+		"user_id":    order.UserID,
+		"total":      order.Total,
+		"status":     string(order.Status),
+		"version":    order.Version + 1,
+		"updated_at": updatedAt,
+	}
+	if order.Approval != nil {
+		updates["approval_required"] = true
+		updates["approval_decision"] = string(order.Approval.Decision)
+		updates["approval_decided_by"] = order.Approval.DecidedBy
+		updates["approval_decided_at"] = order.Approval.DecidedAt
+		updates["approval_reason"] = order.Approval.Reason
+		updates["approval_escalated_at"] = order.Approval.EscalatedAt
+	}
+
+	result := db.Model(&orderEntity{}).Scopes(tenantScope(ctx)).
+		Where("id = ? AND version = ? AND deleted_at IS NULL", order.ID, order.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrVersionConflict
+	}
+
+	order.Version++
+	order.UpdatedAt = updatedAt
+	return nil
 }
 
 // Delete soft deletes an order by ID
@@ -137,7 +258,7 @@ func (r *OrderRepository) Delete(ctx context.Context, tx repo.Transaction, id st
 	db := r.getDB(ctx, tx)
 
 	now := time.Now()
-	return db.Model(&orderEntity{}).Where("id = ?", id).Update("deleted_at", now).Error
+	return db.Model(&orderEntity{}).Scopes(tenantScope(ctx)).Where("id = ?", id).Update("deleted_at", now).Error
 }
 
 // GetByID retrieves an order by ID with items
@@ -145,7 +266,7 @@ func (r *OrderRepository) GetByID(ctx context.Context, tx repo.Transaction, id s
 	var entity orderEntity
 	db := r.getDB(ctx, tx)
 
-	err := db.Preload("Items").Where("id = ? AND deleted_at IS NULL", id).First(&entity).Error
+	err := db.Preload("Items.Allocations").Scopes(tenantScope(ctx)).Where("id = ? AND deleted_at IS NULL", id).First(&entity).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -163,12 +284,37 @@ func (r *OrderRepository) GetByUserID(ctx context.Context, tx repo.Transaction,
 	db := r.getDB(ctx, tx)
 
 	// Get total count
-	if err := db.Model(&orderEntity{}).Where("user_id = ? AND deleted_at IS NULL", userID).Count(&total).Error; err != nil {
+	if err := db.Model(&orderEntity{}).Scopes(tenantScope(ctx)).Where("user_id = ? AND deleted_at IS NULL", userID).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get paginated results with items
-	if err := db.Preload("Items").Where("user_id = ? AND deleted_at IS NULL", userID).
+	if err := db.Preload("Items.Allocations").Scopes(tenantScope(ctx)).Where("user_id = ? AND deleted_at IS NULL", userID).
+		Order("created_at DESC").Offset(offset).Limit(limit).Find(&entities).Error; err != nil {
+		return nil, 0, err
+	}
+
+	orders := make([]*model.Order, len(entities))
+	for i, e := range entities {
+		orders[i] = e.toModel()
+	}
+
+	return orders, total, nil
+}
+
+// GetByOrganizationID retrieves the orders placed on behalf of an organization with pagination
+func (r *OrderRepository) GetByOrganizationID(ctx context.Context, tx repo.Transaction, organizationID string, offset, limit int) ([]*model.Order, int64, error) {
+	var entities []orderEntity
+	var total int64
+	db := r.getDB(ctx, tx)
+
+	// Get total count
+	if err := db.Model(&orderEntity{}).Scopes(tenantScope(ctx)).Where("organization_id = ? AND deleted_at IS NULL", organizationID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated results with items
+	if err := db.Preload("Items.Allocations").Scopes(tenantScope(ctx)).Where("organization_id = ? AND deleted_at IS NULL", organizationID).
 		Order("created_at DESC").Offset(offset).Limit(limit).Find(&entities).Error; err != nil {
 		return nil, 0, err
 	}
@@ -188,12 +334,12 @@ func (r *OrderRepository) List(ctx context.Context, tx repo.Transaction, offset,
 	db := r.getDB(ctx, tx)
 
 	// Get total count
-	if err := db.Model(&orderEntity{}).Where("deleted_at IS NULL").Count(&total).Error; err != nil {
+	if err := db.Model(&orderEntity{}).Scopes(tenantScope(ctx)).Where("deleted_at IS NULL").Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get paginated results with items
-	if err := db.Preload("Items").Where("deleted_at IS NULL").
+	if err := db.Preload("Items.Allocations").Scopes(tenantScope(ctx)).Where("deleted_at IS NULL").
 		Order("created_at DESC").Offset(offset).Limit(limit).Find(&entities).Error; err != nil {
 		return nil, 0, err
 	}
@@ -206,12 +352,102 @@ func (r *OrderRepository) List(ctx context.Context, tx repo.Transaction, offset,
 	return orders, total, nil
 }
 
-// UpdateStatus updates the order status
-func (r *OrderRepository) UpdateStatus(ctx context.Context, tx repo.Transaction, id string, status model.OrderStatus) error {
+// UpdateStatus updates the order status if the order is still at the given version.
+// Returns model.ErrVersionConflict when the order was modified concurrently.
+func (r *OrderRepository) UpdateStatus(ctx context.Context, tx repo.Transaction, id string, status model.OrderStatus, version int) error {
 	db := r.getDB(ctx, tx)
 
-	return db.Model(&orderEntity{}).Where("id = ?", id).Updates(map[string]interface{}{
-		"status":     string(status),
-		"updated_at": time.Now(),
-	}).Error
+	result := db.Model(&orderEntity{}).Scopes(tenantScope(ctx)).
+		Where("id = ? AND version = ? AND deleted_at IS NULL", id, version).
+		Updates(map[string]interface{}{
+			"status":     string(status),
+			"version":    version + 1,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// SaveAllocations replaces the warehouse allocations of the order items. Allocations have no tenant
+// of their own, so the order must be in the tenant of ctx and the items its own.
+func (r *OrderRepository) SaveAllocations(ctx context.Context, tx repo.Transaction, order *model.Order) error {
+	itemIDs := make([]string, len(order.Items))
+	var allocations []orderItemAllocationEntity
+	for i, item := range order.Items {
+		itemIDs[i] = item.ID
+		for _, allocation := range item.Allocations {
+			allocations = append(allocations, orderItemAllocationEntity{
+				ID:          uuid.New().String(),
+				OrderItemID: item.ID,
+				WarehouseID: allocation.WarehouseID,
+				Quantity:    allocation.Quantity,
+			})
+		}
+	}
+
+	return r.getDB(ctx, tx).Transaction(func(db *gorm.DB) error {
+		var orders, owned int64
+		if err := db.Model(&orderEntity{}).Scopes(tenantScope(ctx)).Where("id = ?", order.ID).Count(&orders).Error; err != nil {
+			return err
+		}
+		if err := db.Model(&orderItemEntity{}).Where("order_id = ? AND id IN ?", order.ID, itemIDs).Count(&owned).Error; err != nil {
+			return err
+		}
+		if orders == 0 || int(owned) != len(itemIDs) {
+			return model.ErrOrderNotFound
+		}
+
+		if err := db.Where("order_item_id IN ?", itemIDs).Delete(&orderItemAllocationEntity{}).Error; err != nil {
+			return err
+		}
+		if len(allocations) == 0 {
+			return nil
+		}
+		return db.Create(&allocations).Error
+	})
+}
+
+// ListByStatusBefore retrieves orders in the given status created, or approved, before the cutoff, oldest first
+func (r *OrderRepository) ListByStatusBefore(ctx context.Context, tx repo.Transaction, status model.OrderStatus, before time.Time, offset, limit int) ([]*model.Order, error) {
+	var entities []orderEntity
+	db := r.getDB(ctx, tx)
+
+	if err := db.Preload("Items.Allocations").Scopes(tenantScope(ctx)).
+		Where("status = ? AND COALESCE(approval_decided_at, created_at) < ? AND deleted_at IS NULL", string(status), before).
+		Order("created_at ASC, id ASC").Offset(offset).Limit(limit).Find(&entities).Error; err != nil {
+		return nil, err
+	}
+
+	orders := make([]*model.Order, len(entities))
+	for i, e := range entities {
+		orders[i] = e.toModel()
+	}
+
+	return orders, nil
+}
+
+// ListAwaitingApprovalBefore retrieves orders awaiting approval, not yet escalated, created before
+// the cutoff, oldest first
+func (r *OrderRepository) ListAwaitingApprovalBefore(ctx context.Context, tx repo.Transaction, before time.Time, offset, limit int) ([]*model.Order, error) {
+	var entities []orderEntity
+	db := r.getDB(ctx, tx)
+
+	if err := db.Preload("Items.Allocations").Scopes(tenantScope(ctx)).
+		Where("status = ? AND approval_escalated_at IS NULL AND created_at < ? AND deleted_at IS NULL", string(model.OrderStatusAwaitingApproval), before).
+		Order("created_at ASC, id ASC").Offset(offset).Limit(limit).Find(&entities).Error; err != nil {
+		return nil, err
+	}
+
+	orders := make([]*model.Order, len(entities))
+	for i, e := range entities {
+		orders[i] = e.toModel()
+	}
+
+	return orders, nil
 }
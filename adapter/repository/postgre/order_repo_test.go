@@ -0,0 +1,103 @@
+package postgre
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+func TestOrderRepositoryListByStatusBefore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping PostgreSQL container test in short mode")
+	}
+
+	db := GetTestDB(t, SetupPostgreSQLContainer(t))
+	require.NoError(t, db.DB.AutoMigrate(&orderEntity{}, &orderItemEntity{}, &orderItemAllocationEntity{}))
+	orders := NewOrderRepository(db.DB)
+	// The jobs listing orders work across tenants
+	ctx := model.ContextWithoutTenant(context.Background())
+
+	now := time.Now()
+	cutoff := now.Add(-30 * time.Minute)
+
+	create := func(status model.OrderStatus, createdAt time.Time, approvedAt *time.Time) *model.Order {
+		order, err := model.NewOrder(uuid.New().String(), "", []model.OrderItem{{ProductID: "p1", Quantity: 1, Price: 10}})
+		require.NoError(t, err)
+		order.Status = status
+		order.CreatedAt = createdAt
+		if approvedAt != nil {
+			order.Approval = &model.OrderApproval{Decision: model.ApprovalDecisionApproved, DecidedAt: approvedAt}
+		}
+		created, err := orders.Create(ctx, nil, order)
+		require.NoError(t, err)
+		return created
+	}
+
+	oldest := create(model.OrderStatusPending, now.Add(-3*time.Hour), nil)
+	older := create(model.OrderStatusPending, now.Add(-2*time.Hour), nil)
+	create(model.OrderStatusPending, now.Add(-time.Minute), nil)
+	create(model.OrderStatusConfirmed, now.Add(-3*time.Hour), nil)
+	recentlyApproved := now.Add(-time.Minute)
+	create(model.OrderStatusPending, now.Add(-4*time.Hour), &recentlyApproved)
+
+	t.Run("lists stale orders in the status, oldest first", func(t *testing.T) {
+		found, err := orders.ListByStatusBefore(ctx, nil, model.OrderStatusPending, cutoff, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		assert.Equal(t, oldest.ID, found[0].ID)
+		assert.Equal(t, older.ID, found[1].ID)
+		assert.Len(t, found[0].Items, 1)
+	})
+
+	t.Run("pages with offset and limit", func(t *testing.T) {
+		found, err := orders.ListByStatusBefore(ctx, nil, model.OrderStatusPending, cutoff, 1, 1)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, older.ID, found[0].ID)
+	})
+
+	t.Run("skips deleted orders", func(t *testing.T) {
+		require.NoError(t, orders.Delete(ctx, nil, oldest.ID))
+
+		found, err := orders.ListByStatusBefore(ctx, nil, model.OrderStatusPending, cutoff, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, older.ID, found[0].ID)
+	})
+}
+
+func TestOrderRepositoryVersionCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping PostgreSQL container test in short mode")
+	}
+
+	db := GetTestDB(t, SetupPostgreSQLContainer(t))
+	require.NoError(t, db.DB.AutoMigrate(&orderEntity{}, &orderItemEntity{}, &orderItemAllocationEntity{}))
+	orders := NewOrderRepository(db.DB)
+	ctx := model.ContextWithTenant(context.Background(), model.DefaultTenantID)
+
+	order, err := model.NewOrder(uuid.New().String(), "", []model.OrderItem{{ProductID: "p1", Quantity: 1, Price: 10}})
+	require.NoError(t, err)
+	order, err = orders.Create(ctx, nil, order)
+	require.NoError(t, err)
+
+	require.NoError(t, orders.UpdateStatus(ctx, nil, order.ID, model.OrderStatusConfirmed, order.Version))
+	assert.ErrorIs(t, orders.UpdateStatus(ctx, nil, order.ID, model.OrderStatusCancelled, order.Version), model.ErrVersionConflict)
+
+	stored, err := orders.GetByID(ctx, nil, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusConfirmed, stored.Status)
+	assert.Equal(t, order.Version+1, stored.Version)
+
+	stale := *order
+	assert.ErrorIs(t, orders.Update(ctx, nil, &stale), model.ErrVersionConflict)
+
+	require.NoError(t, orders.Update(ctx, nil, stored))
+	assert.Equal(t, order.Version+2, stored.Version)
+}
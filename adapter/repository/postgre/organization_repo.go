@@ -0,0 +1,302 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
+)
+
+// OrganizationRepository implements IOrganizationRepo using PostgreSQL
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+// NewOrganizationRepository creates a new organization repository
+func NewOrganizationRepository(db *gorm.DB) repo.IOrganizationRepo {
+	return &OrganizationRepository{db: db}
+}
+
+// organizationEntity represents the database entity
+type organizationEntity struct {
+	ID                string                      `gorm:"primaryKey;type:uuid"`
+	TenantID          string                      `gorm:"not null;default:'default';index"`
+	Name              string                      `gorm:"not null"`
+	ApprovalThreshold *float64                    `gorm:"type:decimal(10,2)"`
+	Version           int                         `gorm:"not null;default:1"`
+	CreatedAt         time.Time                   `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time                   `gorm:"autoUpdateTime"`
+	Members           []organizationMemberEntity  `gorm:"foreignKey:OrganizationID"`
+	Addresses         []organizationAddressEntity `gorm:"foreignKey:OrganizationID"`
+}
+
+func (organizationEntity) TableName() string {
+	return "organizations"
+}
+
+// organizationMemberEntity represents the membership of a user in an organization
+type organizationMemberEntity struct {
+	OrganizationID string    `gorm:"primaryKey;type:uuid"`
+	UserID         string    `gorm:"primaryKey;type:uuid;index"`
+	Role           string    `gorm:"not null"`
+	JoinedAt       time.Time `gorm:"not null"`
+	SpendingLimit  *float64  `gorm:"type:decimal(10,2)"`
+}
+
+func (organizationMemberEntity) TableName() string {
+	return "organization_members"
+}
+
+// organizationAddressEntity represents an address shared by the members of an organization
+type organizationAddressEntity struct {
+	ID             string `gorm:"primaryKey;type:uuid"`
+	OrganizationID string `gorm:"type:uuid;not null;index"`
+	Label          string `gorm:"not null;default:''"`
+	Line1          string `gorm:"not null"`
+	Line2          string `gorm:"not null;default:''"`
+	City           string `gorm:"not null"`
+	Region         string `gorm:"not null;default:''"`
+	PostalCode     string `gorm:"not null"`
+	Country        string `gorm:"type:char(2);not null"`
+}
+
+func (organizationAddressEntity) TableName() string {
+	return "organization_addresses"
+}
+
+// toModel converts entity to domain model
+func (e *organizationEntity) toModel() *model.Organization {
+	members := make([]model.OrganizationMember, len(e.Members))
+	for i, m := range e.Members {
+		members[i] = model.OrganizationMember{
+			UserID:        m.UserID,
+			Role:          model.OrganizationRole(m.Role),
+			JoinedAt:      m.JoinedAt,
+			SpendingLimit: m.SpendingLimit,
+		}
+	}
+
+	addresses := make([]model.OrganizationAddress, len(e.Addresses))
+	for i, a := range e.Addresses {
+		addresses[i] = model.OrganizationAddress{
+			ID:    a.ID,
+			Label: a.Label,
+			Address: vo.Address{
+				Line1:      a.Line1,
+				Line2:      a.Line2,
+				City:       a.City,
+				Region:     a.Region,
+				PostalCode: a.PostalCode,
+				Country:    a.Country,
+			},
+		}
+	}
+
+	return &model.Organization{
+		ID:                e.ID,
+		TenantID:          e.TenantID,
+		Name:              e.Name,
+		Members:           members,
+		Addresses:         addresses,
+		ApprovalThreshold: e.ApprovalThreshold,
+		Version:           e.Version,
+		CreatedAt:         e.CreatedAt,
+		UpdatedAt:         e.UpdatedAt,
+	}
+}
+
+// toOrganizationEntity converts domain model to entity
+func toOrganizationEntity(o *model.Organization) *organizationEntity {
+	return &organizationEntity{
+		ID:                o.ID,
+		TenantID:          o.TenantID,
+		Name:              o.Name,
+		ApprovalThreshold: o.ApprovalThreshold,
+		Version:           o.Version,
+		CreatedAt:         o.CreatedAt,
+		UpdatedAt:         o.UpdatedAt,
+		Members:           toOrganizationMemberEntities(o),
+		Addresses:         toOrganizationAddressEntities(o),
+	}
+}
+
+func toOrganizationMemberEntities(o *model.Organization) []organizationMemberEntity {
+	members := make([]organizationMemberEntity, len(o.Members))
+	for i, m := range o.Members {
+		members[i] = organizationMemberEntity{
+			OrganizationID: o.ID,
+			UserID:         m.UserID,
+			Role:           string(m.Role),
+			JoinedAt:       m.JoinedAt,
+			SpendingLimit:  m.SpendingLimit,
+		}
+	}
+	return members
+}
+
+func toOrganizationAddressEntities(o *model.Organization) []organizationAddressEntity {
+	addresses := make([]organizationAddressEntity, len(o.Addresses))
+	for i, a := range o.Addresses {
+		addresses[i] = organizationAddressEntity{
+			ID:             a.ID,
+			OrganizationID: o.ID,
+			Label:          a.Label,
+			Line1:          a.Line1,
+			Line2:          a.Line2,
+			City:           a.City,
+			Region:         a.Region,
+			PostalCode:     a.PostalCode,
+			Country:        a.Country,
+		}
+	}
+	return addresses
+}
+
+func (r *OrganizationRepository) getDB(ctx context.Context, tx repo.Transaction) *gorm.DB {
+	if tx != nil {
+		if gormTx, ok := tx.GetTx().(*gorm.DB); ok {
+			return gormTx.WithContext(ctx)
+		}
+	}
+	return r.db.WithContext(ctx)
+}
+
+// Create creates a new organization with its members and addresses
+func (r *OrganizationRepository) Create(ctx context.Context, tx repo.Transaction, org *model.Organization) (*model.Organization, error) {
+	if org.TenantID == "" {
+		org.TenantID = model.TenantForCreate(ctx)
+	}
+	entity := toOrganizationEntity(org)
+	db := r.getDB(ctx, tx)
+
+	if err := db.Create(entity).Error; err != nil {
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
+
+// Update updates an existing organization if it is still at the version it was read with, and
+// replaces its members and addresses. On success the organization's version is incremented;
+// otherwise model.ErrVersionConflict is returned.
+func (r *OrganizationRepository) Update(ctx context.Context, tx repo.Transaction, org *model.Organization) error {
+	members := toOrganizationMemberEntities(org)
+	addresses := toOrganizationAddressEntities(org)
+	updatedAt := time.Now()
+
+	err := r.getDB(ctx, tx).Transaction(func(db *gorm.DB) error {
+		result := db.Model(&organizationEntity{}).Scopes(tenantScope(ctx)).
+			Where("id = ? AND version = ?", org.ID, org.Version).
+			Updates(map[string]interface{}{
+				"name":               org.Name,
+				"approval_threshold": org.ApprovalThreshold,
+				"version":            org.Version + 1,
+				"updated_at":         updatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrVersionConflict
+		}
+
+		if err := db.Where("organization_id = ?", org.ID).Delete(&organizationMemberEntity{}).Error; err != nil {
+			return err
+		}
+		if err := db.Where("organization_id = ?", org.ID).Delete(&organizationAddressEntity{}).Error; err != nil {
+			return err
+		}
+		if len(members) > 0 {
+			if err := db.Create(&members).Error; err != nil {
+				return err
+			}
+		}
+		if len(addresses) > 0 {
+			return db.Create(&addresses).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	org.Version++
+	org.UpdatedAt = updatedAt
+	return nil
+}
+
+// Delete deletes an organization by ID with its members and addresses
+func (r *OrganizationRepository) Delete(ctx context.Context, tx repo.Transaction, id string) error {
+	return r.getDB(ctx, tx).Transaction(func(db *gorm.DB) error {
+		result := db.Scopes(tenantScope(ctx)).Where("id = ?", id).Delete(&organizationEntity{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if err := db.Where("organization_id = ?", id).Delete(&organizationMemberEntity{}).Error; err != nil {
+			return err
+		}
+		return db.Where("organization_id = ?", id).Delete(&organizationAddressEntity{}).Error
+	})
+}
+
+// GetByID retrieves an organization by ID with its members and addresses
+func (r *OrganizationRepository) GetByID(ctx context.Context, tx repo.Transaction, id string) (*model.Organization, error) {
+	var entity organizationEntity
+	db := r.getDB(ctx, tx)
+
+	err := db.Preload("Members").Preload("Addresses").Scopes(tenantScope(ctx)).Where("id = ?", id).First(&entity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
+
+// List retrieves organizations with pagination, sorted by name
+func (r *OrganizationRepository) List(ctx context.Context, tx repo.Transaction, offset, limit int) ([]*model.Organization, int64, error) {
+	var entities []organizationEntity
+	var total int64
+	db := r.getDB(ctx, tx)
+
+	if err := db.Model(&organizationEntity{}).Scopes(tenantScope(ctx)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := db.Preload("Members").Preload("Addresses").Scopes(tenantScope(ctx)).
+		Order("name ASC, id ASC").Offset(offset).Limit(limit).Find(&entities).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return toOrganizationModels(entities), total, nil
+}
+
+// ListByUserID retrieves the organizations a user is a member of, sorted by name
+func (r *OrganizationRepository) ListByUserID(ctx context.Context, tx repo.Transaction, userID string) ([]*model.Organization, error) {
+	var entities []organizationEntity
+	db := r.getDB(ctx, tx)
+
+	memberships := db.Model(&organizationMemberEntity{}).Select("organization_id").Where("user_id = ?", userID)
+	if err := db.Preload("Members").Preload("Addresses").Scopes(tenantScope(ctx)).Where("id IN (?)", memberships).
+		Order("name ASC, id ASC").Find(&entities).Error; err != nil {
+		return nil, err
+	}
+
+	return toOrganizationModels(entities), nil
+}
+
+func toOrganizationModels(entities []organizationEntity) []*model.Organization {
+	orgs := make([]*model.Organization, len(entities))
+	for i := range entities {
+		orgs[i] = entities[i].toModel()
+	}
+	return orgs
+}
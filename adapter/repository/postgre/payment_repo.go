@@ -0,0 +1,223 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// PaymentRepository implements IPaymentRepo using PostgreSQL
+type PaymentRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentRepository creates a new payment repository
+func NewPaymentRepository(db *gorm.DB) repo.IPaymentRepo {
+	return &PaymentRepository{db: db}
+}
+
+// paymentEntity represents the database entity
+type paymentEntity struct {
+	ID               string    `gorm:"primaryKey;type:uuid"`
+	OrderID          string    `gorm:"type:uuid;not null;index;uniqueIndex:idx_payments_order_active,where:status IN ('pending'\\, 'authorized'\\, 'captured')"`
+	Amount           float64   `gorm:"type:decimal(10,2);not null"`
+	RefundedAmount   float64   `gorm:"type:decimal(10,2);not null;default:0"`
+	Status           string    `gorm:"not null;default:'pending'"`
+	GatewayRef       string    `gorm:"index"`
+	FailureReason    string    `gorm:"type:text"`
+	PendingOperation string    `gorm:"not null;default:''"`
+	Version          int       `gorm:"not null;default:1"`
+	CreatedAt        time.Time `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime"`
+}
+
+func (paymentEntity) TableName() string {
+	return "payments"
+}
+
+// paymentWebhookEventEntity records a gateway webhook event applied to a payment
+type paymentWebhookEventEntity struct {
+	EventID   string    `gorm:"primaryKey"`
+	PaymentID string    `gorm:"type:uuid;not null;index"`
+	AppliedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (paymentWebhookEventEntity) TableName() string {
+	return "payment_webhook_events"
+}
+
+// toModel converts entity to domain model
+func (e *paymentEntity) toModel() *model.Payment {
+	return &model.Payment{
+		ID:               e.ID,
+		OrderID:          e.OrderID,
+		Amount:           e.Amount,
+		RefundedAmount:   e.RefundedAmount,
+		Status:           model.PaymentStatus(e.Status),
+		GatewayRef:       e.GatewayRef,
+		FailureReason:    e.FailureReason,
+		PendingOperation: e.PendingOperation,
+		Version:          e.Version,
+		CreatedAt:        e.CreatedAt,
+		UpdatedAt:        e.UpdatedAt,
+	}
+}
+
+// toPaymentEntity converts domain model to entity
+func toPaymentEntity(p *model.Payment) *paymentEntity {
+	return &paymentEntity{
+		ID:               p.ID,
+		OrderID:          p.OrderID,
+		Amount:           p.Amount,
+		RefundedAmount:   p.RefundedAmount,
+		Status:           string(p.Status),
+		GatewayRef:       p.GatewayRef,
+		FailureReason:    p.FailureReason,
+		PendingOperation: p.PendingOperation,
+		Version:          p.Version,
+		CreatedAt:        p.CreatedAt,
+		UpdatedAt:        p.UpdatedAt,
+	}
+}
+
+func (r *PaymentRepository) getDB(ctx context.Context, tx repo.Transaction) *gorm.DB {
+	if tx != nil {
+		if gormTx, ok := tx.GetTx().(*gorm.DB); ok {
+			return gormTx.WithContext(ctx)
+		}
+	}
+	return r.db.WithContext(ctx)
+}
+
+// Create creates a new payment. It fails with model.ErrPaymentAlreadyExists when the order already
+// has an active payment, which the unique index idx_payments_order_active enforces.
+func (r *PaymentRepository) Create(ctx context.Context, tx repo.Transaction, payment *model.Payment) (*model.Payment, error) {
+	entity := toPaymentEntity(payment)
+	db := r.getDB(ctx, tx)
+
+	if err := db.Create(entity).Error; err != nil {
+		if isUniqueViolation(err, "idx_payments_order_active") {
+			return nil, model.ErrPaymentAlreadyExists
+		}
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
+
+// Update updates an existing payment if it is still at the version it was read with.
+// On success the payment's version is incremented; otherwise model.ErrVersionConflict is returned.
+func (r *PaymentRepository) Update(ctx context.Context, tx repo.Transaction, payment *model.Payment) error {
+	return r.update(r.getDB(ctx, tx), payment)
+}
+
+// ApplyWebhookEvent updates the payment and records the gateway event ID in one transaction.
+// It returns false, leaving the payment unchanged, when the event was already applied.
+func (r *PaymentRepository) ApplyWebhookEvent(ctx context.Context, tx repo.Transaction, eventID string, payment *model.Payment) (bool, error) {
+	applied := false
+	err := r.getDB(ctx, tx).Transaction(func(db *gorm.DB) error {
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&paymentWebhookEventEntity{EventID: eventID, PaymentID: payment.ID})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		applied = true
+		return r.update(db, payment)
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+// update runs the version-checked update of the payment
+func (r *PaymentRepository) update(db *gorm.DB, payment *model.Payment) error {
+	updatedAt := time.Now()
+	result := db.Model(&paymentEntity{}).
+		Where("id = ? AND version = ?", payment.ID, payment.Version).
+		Updates(map[string]interface{}{
+			"refunded_amount":   payment.RefundedAmount,
+			"status":            string(payment.Status),
+			"gateway_ref":       payment.GatewayRef,
+			"failure_reason":    payment.FailureReason,
+			"pending_operation": payment.PendingOperation,
+			"version":           payment.Version + 1,
+			"updated_at":        updatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrVersionConflict
+	}
+
+	payment.Version++
+	payment.UpdatedAt = updatedAt
+	return nil
+}
+
+// GetByID retrieves a payment by ID
+func (r *PaymentRepository) GetByID(ctx context.Context, tx repo.Transaction, id string) (*model.Payment, error) {
+	var entity paymentEntity
+	db := r.getDB(ctx, tx)
+
+	err := db.Where("id = ?", id).First(&entity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
+
+// GetByGatewayRef retrieves a payment by the gateway transaction reference
+func (r *PaymentRepository) GetByGatewayRef(ctx context.Context, tx repo.Transaction, gatewayRef string) (*model.Payment, error) {
+	var entity paymentEntity
+	db := r.getDB(ctx, tx)
+
+	err := db.Where("gateway_ref = ?", gatewayRef).First(&entity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
+
+// ListByOrderID retrieves all payments for an order, newest first
+func (r *PaymentRepository) ListByOrderID(ctx context.Context, tx repo.Transaction, orderID string) ([]*model.Payment, error) {
+	var entities []paymentEntity
+	db := r.getDB(ctx, tx)
+
+	if err := db.Where("order_id = ?", orderID).Order("created_at DESC").Find(&entities).Error; err != nil {
+		return nil, err
+	}
+
+	payments := make([]*model.Payment, len(entities))
+	for i, e := range entities {
+		payments[i] = e.toModel()
+	}
+
+	return payments, nil
+}
+
+// uniqueViolation is the PostgreSQL error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+// isUniqueViolation reports whether err is a violation of the unique index or constraint
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == constraint
+}
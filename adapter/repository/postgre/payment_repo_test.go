@@ -0,0 +1,41 @@
+package postgre
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+func TestPaymentRepositoryOneActivePaymentPerOrder(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping PostgreSQL container test in short mode")
+	}
+
+	db := GetTestDB(t, SetupPostgreSQLContainer(t))
+	require.NoError(t, db.DB.AutoMigrate(&paymentEntity{}))
+	payments := NewPaymentRepository(db.DB)
+	ctx := context.Background()
+	orderID := uuid.New().String()
+
+	first, err := model.NewPayment(orderID, 100)
+	require.NoError(t, err)
+	_, err = payments.Create(ctx, nil, first)
+	require.NoError(t, err)
+
+	// A concurrent authorization that missed the first payment cannot store a second one
+	second, err := model.NewPayment(orderID, 100)
+	require.NoError(t, err)
+	_, err = payments.Create(ctx, nil, second)
+	assert.ErrorIs(t, err, model.ErrPaymentAlreadyExists)
+
+	// Once the first payment failed the order can be paid again
+	require.NoError(t, first.Fail("declined"))
+	require.NoError(t, payments.Update(ctx, nil, first))
+	_, err = payments.Create(ctx, nil, second)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,285 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// ReturnRepository implements IReturnRepo using PostgreSQL
+type ReturnRepository struct {
+	db *gorm.DB
+}
+
+// NewReturnRepository creates a new return request repository
+func NewReturnRepository(db *gorm.DB) repo.IReturnRepo {
+	return &ReturnRepository{db: db}
+}
+
+// returnEntity represents the database entity
+type returnEntity struct {
+	ID           string             `gorm:"primaryKey;type:uuid"`
+	OrderID      string             `gorm:"type:uuid;not null;index"`
+	Reason       string             `gorm:"type:text"`
+	Status       string             `gorm:"not null;default:'requested'"`
+	RejectReason string             `gorm:"type:text"`
+	RefundAmount float64            `gorm:"type:decimal(10,2);not null;default:0"`
+	Version      int                `gorm:"not null;default:1"`
+	CreatedAt    time.Time          `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time          `gorm:"autoUpdateTime"`
+	Items        []returnItemEntity `gorm:"foreignKey:ReturnID"`
+}
+
+func (returnEntity) TableName() string {
+	return "return_requests"
+}
+
+// returnItemEntity represents the return item database entity
+type returnItemEntity struct {
+	ID          string  `gorm:"primaryKey;type:uuid"`
+	ReturnID    string  `gorm:"type:uuid;not null;index"`
+	OrderItemID string  `gorm:"type:uuid;not null;index"`
+	ProductID   string  `gorm:"not null"`
+	SKU         string  `gorm:"not null;default:''"`
+	Quantity    int     `gorm:"not null"`
+	Price       float64 `gorm:"type:decimal(10,2);not null"`
+}
+
+func (returnItemEntity) TableName() string {
+	return "return_items"
+}
+
+// toModel converts entity to domain model
+func (e *returnEntity) toModel() *model.ReturnRequest {
+	items := make([]model.ReturnItem, len(e.Items))
+	for i, item := range e.Items {
+		items[i] = model.ReturnItem{
+			ID:          item.ID,
+			ReturnID:    item.ReturnID,
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			SKU:         item.SKU,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+		}
+	}
+
+	return &model.ReturnRequest{
+		ID:           e.ID,
+		OrderID:      e.OrderID,
+		Items:        items,
+		Reason:       e.Reason,
+		Status:       model.ReturnStatus(e.Status),
+		RejectReason: e.RejectReason,
+		RefundAmount: e.RefundAmount,
+		Version:      e.Version,
+		CreatedAt:    e.CreatedAt,
+		UpdatedAt:    e.UpdatedAt,
+	}
+}
+
+// toReturnEntity converts domain model to entity
+func toReturnEntity(r *model.ReturnRequest) *returnEntity {
+	items := make([]returnItemEntity, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = returnItemEntity{
+			ID:          item.ID,
+			ReturnID:    item.ReturnID,
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			SKU:         item.SKU,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+		}
+	}
+
+	return &returnEntity{
+		ID:           r.ID,
+		OrderID:      r.OrderID,
+		Reason:       r.Reason,
+		Status:       string(r.Status),
+		RejectReason: r.RejectReason,
+		RefundAmount: r.RefundAmount,
+		Version:      r.Version,
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+		Items:        items,
+	}
+}
+
+func (r *ReturnRepository) getDB(ctx context.Context, tx repo.Transaction) *gorm.DB {
+	if tx != nil {
+		if gormTx, ok := tx.GetTx().(*gorm.DB); ok {
+			return gormTx.WithContext(ctx)
+		}
+	}
+	return r.db.WithContext(ctx)
+}
+
+// Create creates a new return request with items
+func (r *ReturnRepository) Create(ctx context.Context, tx repo.Transaction, rma *model.ReturnRequest) (*model.ReturnRequest, error) {
+	entity := toReturnEntity(rma)
+	db := r.getDB(ctx, tx)
+
+	if err := db.Create(entity).Error; err != nil {
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
+
+// Update updates the status of a return request if it is still at the version it was read with.
+// On success the return's version is incremented; otherwise model.ErrVersionConflict is returned.
+func (r *ReturnRepository) Update(ctx context.Context, tx repo.Transaction, rma *model.ReturnRequest) error {
+	db := r.getDB(ctx, tx)
+
+	updatedAt := time.Now()
+	result := db.Model(&returnEntity{}).
+		Where("id = ? AND version = ?", rma.ID, rma.Version).
+		Updates(map[string]interface{}{
+			"status":        string(rma.Status),
+			"reject_reason": rma.RejectReason,
+			"version":       rma.Version + 1,
+			"updated_at":    updatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrVersionConflict
+	}
+
+	rma.Version++
+	rma.UpdatedAt = updatedAt
+	return nil
+}
+
+// GetByID retrieves a return request by ID with items
+func (r *ReturnRepository) GetByID(ctx context.Context, tx repo.Transaction, id string) (*model.ReturnRequest, error) {
+	var entity returnEntity
+	db := r.getDB(ctx, tx)
+
+	err := db.Preload("Items").Where("id = ?", id).First(&entity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
+
+// ListByOrderID retrieves all return requests for an order with items, newest first
+func (r *ReturnRepository) ListByOrderID(ctx context.Context, tx repo.Transaction, orderID string) ([]*model.ReturnRequest, error) {
+	var entities []returnEntity
+	db := r.getDB(ctx, tx)
+
+	if err := db.Preload("Items").Where("order_id = ?", orderID).Order("created_at DESC").Find(&entities).Error; err != nil {
+		return nil, err
+	}
+
+	returns := make([]*model.ReturnRequest, len(entities))
+	for i, e := range entities {
+		returns[i] = e.toModel()
+	}
+
+	return returns, nil
+}
+
+// RefundRepository implements IRefundRepo using PostgreSQL
+type RefundRepository struct {
+	db *gorm.DB
+}
+
+// NewRefundRepository creates a new refund repository
+func NewRefundRepository(db *gorm.DB) repo.IRefundRepo {
+	return &RefundRepository{db: db}
+}
+
+// refundEntity represents the database entity
+type refundEntity struct {
+	ID        string    `gorm:"primaryKey;type:uuid"`
+	ReturnID  string    `gorm:"type:uuid;not null;uniqueIndex"`
+	OrderID   string    `gorm:"type:uuid;not null;index"`
+	PaymentID *string   `gorm:"type:uuid"`
+	Amount    float64   `gorm:"type:decimal(10,2);not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (refundEntity) TableName() string {
+	return "refunds"
+}
+
+// toModel converts entity to domain model
+func (e *refundEntity) toModel() *model.Refund {
+	refund := &model.Refund{
+		ID:        e.ID,
+		ReturnID:  e.ReturnID,
+		OrderID:   e.OrderID,
+		Amount:    e.Amount,
+		CreatedAt: e.CreatedAt,
+	}
+	if e.PaymentID != nil {
+		refund.PaymentID = *e.PaymentID
+	}
+	return refund
+}
+
+// toRefundEntity converts domain model to entity
+func toRefundEntity(r *model.Refund) *refundEntity {
+	entity := &refundEntity{
+		ID:        r.ID,
+		ReturnID:  r.ReturnID,
+		OrderID:   r.OrderID,
+		Amount:    r.Amount,
+		CreatedAt: r.CreatedAt,
+	}
+	if r.PaymentID != "" {
+		entity.PaymentID = &r.PaymentID
+	}
+	return entity
+}
+
+func (r *RefundRepository) getDB(ctx context.Context, tx repo.Transaction) *gorm.DB {
+	if tx != nil {
+		if gormTx, ok := tx.GetTx().(*gorm.DB); ok {
+			return gormTx.WithContext(ctx)
+		}
+	}
+	return r.db.WithContext(ctx)
+}
+
+// Create records a refund
+func (r *RefundRepository) Create(ctx context.Context, tx repo.Transaction, refund *model.Refund) (*model.Refund, error) {
+	entity := toRefundEntity(refund)
+	db := r.getDB(ctx, tx)
+
+	if err := db.Create(entity).Error; err != nil {
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
+
+// ListByOrderID retrieves all refunds for an order, newest first
+func (r *RefundRepository) ListByOrderID(ctx context.Context, tx repo.Transaction, orderID string) ([]*model.Refund, error) {
+	var entities []refundEntity
+	db := r.getDB(ctx, tx)
+
+	if err := db.Where("order_id = ?", orderID).Order("created_at DESC").Find(&entities).Error; err != nil {
+		return nil, err
+	}
+
+	refunds := make([]*model.Refund, len(entities))
+	for i, e := range entities {
+		refunds[i] = e.toModel()
+	}
+
+	return refunds, nil
+}
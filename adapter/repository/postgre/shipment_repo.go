@@ -0,0 +1,215 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// ShipmentRepository implements IShipmentRepo using PostgreSQL
+type ShipmentRepository struct {
+	db *gorm.DB
+}
+
+// NewShipmentRepository creates a new shipment repository
+func NewShipmentRepository(db *gorm.DB) repo.IShipmentRepo {
+	return &ShipmentRepository{db: db}
+}
+
+// shipmentEntity represents the database entity
+type shipmentEntity struct {
+	ID             string               `gorm:"primaryKey;type:uuid"`
+	OrderID        string               `gorm:"type:uuid;not null;index"`
+	Carrier        string               `gorm:"not null"`
+	Service        string               `gorm:"not null;default:''"`
+	TrackingNumber string               `gorm:"index"`
+	Cost           float64              `gorm:"type:decimal(10,2);not null;default:0"`
+	Status         string               `gorm:"not null;default:'pending'"`
+	ShippedAt      *time.Time           `gorm:"type:timestamptz"`
+	DeliveredAt    *time.Time           `gorm:"type:timestamptz"`
+	Version        int                  `gorm:"not null;default:1"`
+	CreatedAt      time.Time            `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time            `gorm:"autoUpdateTime"`
+	Items          []shipmentItemEntity `gorm:"foreignKey:ShipmentID"`
+}
+
+func (shipmentEntity) TableName() string {
+	return "shipments"
+}
+
+// shipmentItemEntity represents the shipment item database entity
+type shipmentItemEntity struct {
+	ID          string `gorm:"primaryKey;type:uuid"`
+	ShipmentID  string `gorm:"type:uuid;not null;index"`
+	OrderItemID string `gorm:"type:uuid;not null;index"`
+	ProductID   string `gorm:"not null"`
+	Quantity    int    `gorm:"not null"`
+	Status      string `gorm:"not null;default:'pending'"`
+}
+
+func (shipmentItemEntity) TableName() string {
+	return "shipment_items"
+}
+
+// toModel converts entity to domain model
+func (e *shipmentEntity) toModel() *model.Shipment {
+	items := make([]model.ShipmentItem, len(e.Items))
+	for i, item := range e.Items {
+		items[i] = model.ShipmentItem{
+			ID:          item.ID,
+			ShipmentID:  item.ShipmentID,
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+			Status:      model.FulfillmentStatus(item.Status),
+		}
+	}
+
+	return &model.Shipment{
+		ID:             e.ID,
+		OrderID:        e.OrderID,
+		Carrier:        e.Carrier,
+		Service:        e.Service,
+		TrackingNumber: e.TrackingNumber,
+		Cost:           e.Cost,
+		Status:         model.ShipmentStatus(e.Status),
+		Items:          items,
+		ShippedAt:      e.ShippedAt,
+		DeliveredAt:    e.DeliveredAt,
+		Version:        e.Version,
+		CreatedAt:      e.CreatedAt,
+		UpdatedAt:      e.UpdatedAt,
+	}
+}
+
+// toShipmentEntity converts domain model to entity
+func toShipmentEntity(s *model.Shipment) *shipmentEntity {
+	items := make([]shipmentItemEntity, len(s.Items))
+	for i, item := range s.Items {
+		items[i] = shipmentItemEntity{
+			ID:          item.ID,
+			ShipmentID:  item.ShipmentID,
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+			Status:      string(item.Status),
+		}
+	}
+
+	return &shipmentEntity{
+		ID:             s.ID,
+		OrderID:        s.OrderID,
+		Carrier:        s.Carrier,
+		Service:        s.Service,
+		TrackingNumber: s.TrackingNumber,
+		Cost:           s.Cost,
+		Status:         string(s.Status),
+		ShippedAt:      s.ShippedAt,
+		DeliveredAt:    s.DeliveredAt,
+		Version:        s.Version,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
+		Items:          items,
+	}
+}
+
+func (r *ShipmentRepository) getDB(ctx context.Context, tx repo.Transaction) *gorm.DB {
+	if tx != nil {
+		if gormTx, ok := tx.GetTx().(*gorm.DB); ok {
+			return gormTx.WithContext(ctx)
+		}
+	}
+	return r.db.WithContext(ctx)
+}
+
+// Create creates a new shipment with items
+func (r *ShipmentRepository) Create(ctx context.Context, tx repo.Transaction, shipment *model.Shipment) (*model.Shipment, error) {
+	entity := toShipmentEntity(shipment)
+	db := r.getDB(ctx, tx)
+
+	if err := db.Create(entity).Error; err != nil {
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
+
+// Update updates a shipment and the status of its items if it is still at the version it was read with.
+// On success the shipment's version is incremented; otherwise model.ErrVersionConflict is returned.
+func (r *ShipmentRepository) Update(ctx context.Context, tx repo.Transaction, shipment *model.Shipment) error {
+	updatedAt := time.Now()
+
+	err := r.getDB(ctx, tx).Transaction(func(db *gorm.DB) error {
+		result := db.Model(&shipmentEntity{}).
+			Where("id = ? AND version = ?", shipment.ID, shipment.Version).
+			Updates(map[string]interface{}{
+				"tracking_number": shipment.TrackingNumber,
+				"status":          string(shipment.Status),
+				"shipped_at":      shipment.ShippedAt,
+				"delivered_at":    shipment.DeliveredAt,
+				"version":         shipment.Version + 1,
+				"updated_at":      updatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrVersionConflict
+		}
+
+		for _, item := range shipment.Items {
+			if err := db.Model(&shipmentItemEntity{}).
+				Where("id = ?", item.ID).
+				Update("status", string(item.Status)).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	shipment.Version++
+	shipment.UpdatedAt = updatedAt
+	return nil
+}
+
+// GetByID retrieves a shipment by ID with items
+func (r *ShipmentRepository) GetByID(ctx context.Context, tx repo.Transaction, id string) (*model.Shipment, error) {
+	var entity shipmentEntity
+	db := r.getDB(ctx, tx)
+
+	err := db.Preload("Items").Where("id = ?", id).First(&entity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
+
+// ListByOrderID retrieves all shipments for an order with items, oldest first
+func (r *ShipmentRepository) ListByOrderID(ctx context.Context, tx repo.Transaction, orderID string) ([]*model.Shipment, error) {
+	var entities []shipmentEntity
+	db := r.getDB(ctx, tx)
+
+	if err := db.Preload("Items").Where("order_id = ?", orderID).Order("created_at ASC").Find(&entities).Error; err != nil {
+		return nil, err
+	}
+
+	shipments := make([]*model.Shipment, len(entities))
+	for i, e := range entities {
+		shipments[i] = e.toModel()
+	}
+
+	return shipments, nil
+}
@@ -0,0 +1,24 @@
+package postgre
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// tenantScope restricts a query to the tenant of ctx, see model.TenantFromContext. Contexts
+// without a tenant fail closed with model.ErrTenantMissing, unless they opted out with
+// model.ContextWithoutTenant, as scheduled jobs do, and see every tenant.
+func tenantScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if tenantID := model.TenantFromContext(ctx); tenantID != "" {
+			return db.Where("tenant_id = ?", tenantID)
+		}
+		if !model.SeesAllTenants(ctx) {
+			_ = db.AddError(model.ErrTenantMissing)
+		}
+		return db
+	}
+}
@@ -0,0 +1,206 @@
+package postgre
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+func TestUserRepositoryTenantScoping(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping PostgreSQL container test in short mode")
+	}
+
+	db := GetTestDB(t, SetupPostgreSQLContainer(t))
+	require.NoError(t, db.DB.AutoMigrate(&userEntity{}))
+	users := NewUserRepository(db.DB)
+
+	acme := model.ContextWithTenant(context.Background(), "acme")
+	globex := model.ContextWithTenant(context.Background(), "globex")
+
+	// The same email can sign up in each tenant
+	acmeUser, err := model.NewUser("jane@example.com", "Jane", "hash")
+	require.NoError(t, err)
+	acmeUser, err = users.Create(acme, nil, acmeUser)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", acmeUser.TenantID)
+
+	globexUser, err := model.NewUser("jane@example.com", "Jane", "hash")
+	require.NoError(t, err)
+	globexUser, err = users.Create(globex, nil, globexUser)
+	require.NoError(t, err)
+	assert.Equal(t, "globex", globexUser.TenantID)
+
+	t.Run("reads stay in the tenant", func(t *testing.T) {
+		found, err := users.GetByID(globex, nil, acmeUser.ID)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+
+		found, err = users.GetByEmail(globex, nil, "jane@example.com")
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, globexUser.ID, found.ID)
+
+		list, total, err := users.List(acme, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, list, 1)
+		assert.Equal(t, acmeUser.ID, list[0].ID)
+	})
+
+	t.Run("writes stay in the tenant", func(t *testing.T) {
+		acmeUser.Name = "Mallory"
+		assert.ErrorIs(t, users.Update(globex, nil, acmeUser), model.ErrVersionConflict)
+		require.NoError(t, users.Delete(globex, nil, acmeUser.ID))
+
+		found, err := users.GetByID(acme, nil, acmeUser.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, "Jane", found.Name)
+	})
+
+	t.Run("jobs see every tenant", func(t *testing.T) {
+		_, total, err := users.List(model.ContextWithoutTenant(context.Background()), nil, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("contexts without a tenant fail closed", func(t *testing.T) {
+		_, err := users.GetByID(context.Background(), nil, acmeUser.ID)
+		assert.ErrorIs(t, err, model.ErrTenantMissing)
+		_, _, err = users.List(context.Background(), nil, 0, 10)
+		assert.ErrorIs(t, err, model.ErrTenantMissing)
+	})
+}
+
+func TestOrderRepositoryTenantScoping(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping PostgreSQL container test in short mode")
+	}
+
+	db := GetTestDB(t, SetupPostgreSQLContainer(t))
+	require.NoError(t, db.DB.AutoMigrate(&orderEntity{}, &orderItemEntity{}, &orderItemAllocationEntity{}))
+	orders := NewOrderRepository(db.DB)
+
+	acme := model.ContextWithTenant(context.Background(), "acme")
+	globex := model.ContextWithTenant(context.Background(), "globex")
+
+	order, err := model.NewOrder(uuid.New().String(), "", []model.OrderItem{{ProductID: "p1", Quantity: 2, Price: 10}})
+	require.NoError(t, err)
+	order, err = orders.Create(acme, nil, order)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", order.TenantID)
+
+	t.Run("reads stay in the tenant", func(t *testing.T) {
+		found, err := orders.GetByID(globex, nil, order.ID)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+
+		_, total, err := orders.List(globex, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Zero(t, total)
+		_, total, err = orders.GetByUserID(globex, nil, order.UserID, 0, 10)
+		require.NoError(t, err)
+		assert.Zero(t, total)
+	})
+
+	t.Run("writes stay in the tenant", func(t *testing.T) {
+		assert.ErrorIs(t, orders.UpdateStatus(globex, nil, order.ID, model.OrderStatusCancelled, order.Version), model.ErrVersionConflict)
+		assert.ErrorIs(t, orders.Update(globex, nil, order), model.ErrVersionConflict)
+
+		order.Items[0].Allocations = []model.StockAllocation{{WarehouseID: "w1", Quantity: 2}}
+		assert.ErrorIs(t, orders.SaveAllocations(globex, nil, order), model.ErrOrderNotFound)
+
+		found, err := orders.GetByID(acme, nil, order.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, model.OrderStatusPending, found.Status)
+		assert.Empty(t, found.Items[0].Allocations)
+
+		require.NoError(t, orders.SaveAllocations(acme, nil, order))
+	})
+
+	t.Run("contexts without a tenant fail closed", func(t *testing.T) {
+		_, err := orders.GetByID(context.Background(), nil, order.ID)
+		assert.ErrorIs(t, err, model.ErrTenantMissing)
+		assert.ErrorIs(t, orders.SaveAllocations(context.Background(), nil, order), model.ErrTenantMissing)
+	})
+}
+
+func TestInvoiceRepositoryTenantScoping(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping PostgreSQL container test in short mode")
+	}
+
+	db := GetTestDB(t, SetupPostgreSQLContainer(t))
+	migrateInvoices(t, db.DB)
+	invoices := NewInvoiceRepository(db.DB)
+
+	acme := model.ContextWithTenant(context.Background(), "acme")
+	globex := model.ContextWithTenant(context.Background(), "globex")
+
+	invoice, err := invoices.Create(acme, nil, testInvoice(t, model.InvoiceKindInvoice, "acme", 2031))
+	require.NoError(t, err)
+
+	found, err := invoices.GetByOrderID(globex, nil, invoice.OrderID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	found, err = invoices.GetByOrderID(acme, nil, invoice.OrderID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "acme", found.TenantID)
+
+	_, err = invoices.GetByOrderID(context.Background(), nil, invoice.OrderID)
+	assert.ErrorIs(t, err, model.ErrTenantMissing)
+}
+
+func TestAPIKeyRepositoryTenantScoping(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping PostgreSQL container test in short mode")
+	}
+
+	db := GetTestDB(t, SetupPostgreSQLContainer(t))
+	require.NoError(t, db.DB.AutoMigrate(&apiKeyEntity{}))
+	keys := NewAPIKeyRepository(db.DB)
+
+	acme := model.ContextWithTenant(context.Background(), "acme")
+	globex := model.ContextWithTenant(context.Background(), "globex")
+
+	key, _, err := model.NewAPIKey("ci", []model.Permission{model.PermissionOrdersRead}, nil, "u1")
+	require.NoError(t, err)
+	key, err = keys.Create(acme, nil, key)
+	require.NoError(t, err)
+
+	t.Run("reads stay in the tenant", func(t *testing.T) {
+		found, err := keys.GetByID(globex, nil, key.ID)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+
+		_, total, err := keys.List(globex, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Zero(t, total)
+	})
+
+	t.Run("writes stay in the tenant", func(t *testing.T) {
+		key.Name = "stolen"
+		assert.ErrorIs(t, keys.Update(globex, nil, key), model.ErrVersionConflict)
+
+		found, err := keys.GetByID(acme, nil, key.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, "ci", found.Name)
+	})
+
+	t.Run("keys are looked up by prefix before the tenant is known", func(t *testing.T) {
+		found, err := keys.GetByPrefix(context.Background(), nil, key.Prefix)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, "acme", found.TenantID)
+	})
+}
@@ -0,0 +1,244 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
+)
+
+// UserAddressRepository implements IUserAddressRepo using PostgreSQL
+type UserAddressRepository struct {
+	db *gorm.DB
+}
+
+// NewUserAddressRepository creates a new address book repository
+func NewUserAddressRepository(db *gorm.DB) repo.IUserAddressRepo {
+	return &UserAddressRepository{db: db}
+}
+
+// addressColumns holds the columns of a postal address, embedded in the entities storing one
+type addressColumns struct {
+	Line1      string `gorm:"not null;default:''"`
+	Line2      string `gorm:"not null;default:''"`
+	City       string `gorm:"not null;default:''"`
+	Region     string `gorm:"not null;default:''"`
+	PostalCode string `gorm:"not null;default:''"`
+	Country    string `gorm:"not null;default:''"`
+}
+
+// toAddressColumns converts an address to its columns, left empty for a nil address
+func toAddressColumns(a *vo.Address) addressColumns {
+	if a == nil {
+		return addressColumns{}
+	}
+	return addressColumns{
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+	}
+}
+
+// toAddress converts the columns to an address, nil when they are empty
+func (c addressColumns) toAddress() *vo.Address {
+	if c == (addressColumns{}) {
+		return nil
+	}
+	return &vo.Address{
+		Line1:      c.Line1,
+		Line2:      c.Line2,
+		City:       c.City,
+		Region:     c.Region,
+		PostalCode: c.PostalCode,
+		Country:    c.Country,
+	}
+}
+
+// userAddressEntity represents the database entity
+type userAddressEntity struct {
+	ID              string         `gorm:"primaryKey;type:uuid"`
+	TenantID        string         `gorm:"not null;default:'default';index"`
+	UserID          string         `gorm:"type:uuid;not null;index"`
+	Label           string         `gorm:"not null;default:''"`
+	Address         addressColumns `gorm:"embedded"`
+	DefaultShipping bool           `gorm:"not null;default:false"`
+	DefaultBilling  bool           `gorm:"not null;default:false"`
+	Version         int            `gorm:"not null;default:1"`
+	CreatedAt       time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time      `gorm:"autoUpdateTime"`
+}
+
+func (userAddressEntity) TableName() string {
+	return "user_addresses"
+}
+
+// toModel converts entity to domain model
+func (e *userAddressEntity) toModel() *model.UserAddress {
+	a := &model.UserAddress{
+		ID:              e.ID,
+		TenantID:        e.TenantID,
+		UserID:          e.UserID,
+		Label:           e.Label,
+		DefaultShipping: e.DefaultShipping,
+		DefaultBilling:  e.DefaultBilling,
+		Version:         e.Version,
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+	}
+	if address := e.Address.toAddress(); address != nil {
+		a.Address = *address
+	}
+	return a
+}
+
+// toUserAddressEntity converts domain model to entity
+func toUserAddressEntity(a *model.UserAddress) *userAddressEntity {
+	return &userAddressEntity{
+		ID:              a.ID,
+		TenantID:        a.TenantID,
+		UserID:          a.UserID,
+		Label:           a.Label,
+		Address:         toAddressColumns(&a.Address),
+		DefaultShipping: a.DefaultShipping,
+		DefaultBilling:  a.DefaultBilling,
+		Version:         a.Version,
+		CreatedAt:       a.CreatedAt,
+		UpdatedAt:       a.UpdatedAt,
+	}
+}
+
+func (r *UserAddressRepository) getDB(ctx context.Context, tx repo.Transaction) *gorm.DB {
+	if tx != nil {
+		if gormTx, ok := tx.GetTx().(*gorm.DB); ok {
+			return gormTx.WithContext(ctx)
+		}
+	}
+	return r.db.WithContext(ctx)
+}
+
+// Create creates a new address. A default address takes the flag over from the other addresses of the user.
+func (r *UserAddressRepository) Create(ctx context.Context, tx repo.Transaction, address *model.UserAddress) (*model.UserAddress, error) {
+	if address.TenantID == "" {
+		address.TenantID = model.TenantForCreate(ctx)
+	}
+	entity := toUserAddressEntity(address)
+
+	err := r.getDB(ctx, tx).Transaction(func(db *gorm.DB) error {
+		if err := r.clearDefaults(ctx, db, address); err != nil {
+			return err
+		}
+		return db.Create(entity).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
+
+// Update updates an existing address if it is still at the version it was read with. A default
+// address takes the flag over from the other addresses of the user. On success the address's
+// version is incremented; otherwise model.ErrVersionConflict is returned.
+func (r *UserAddressRepository) Update(ctx context.Context, tx repo.Transaction, address *model.UserAddress) error {
+	columns := toAddressColumns(&address.Address)
+	updatedAt := time.Now()
+
+	err := r.getDB(ctx, tx).Transaction(func(db *gorm.DB) error {
+		result := db.Model(&userAddressEntity{}).Scopes(tenantScope(ctx)).
+			Where("id = ? AND version = ?", address.ID, address.Version).
+			Updates(map[string]interface{}{
+				"label":            address.Label,
+				"line1":            columns.Line1,
+				"line2":            columns.Line2,
+				"city":             columns.City,
+				"region":           columns.Region,
+				"postal_code":      columns.PostalCode,
+				"country":          columns.Country,
+				"default_shipping": address.DefaultShipping,
+				"default_billing":  address.DefaultBilling,
+				"version":          address.Version + 1,
+				"updated_at":       updatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrVersionConflict
+		}
+
+		return r.clearDefaults(ctx, db, address)
+	})
+	if err != nil {
+		return err
+	}
+
+	address.Version++
+	address.UpdatedAt = updatedAt
+	return nil
+}
+
+// clearDefaults unsets the default flags the address holds on the other addresses of its user
+func (r *UserAddressRepository) clearDefaults(ctx context.Context, db *gorm.DB, address *model.UserAddress) error {
+	others := func() *gorm.DB {
+		return db.Model(&userAddressEntity{}).Scopes(tenantScope(ctx)).
+			Where("user_id = ? AND id <> ?", address.UserID, address.ID)
+	}
+
+	if address.DefaultShipping {
+		if err := others().Update("default_shipping", false).Error; err != nil {
+			return err
+		}
+	}
+	if address.DefaultBilling {
+		if err := others().Update("default_billing", false).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete deletes an address by ID
+func (r *UserAddressRepository) Delete(ctx context.Context, tx repo.Transaction, id string) error {
+	return r.getDB(ctx, tx).Scopes(tenantScope(ctx)).Where("id = ?", id).Delete(&userAddressEntity{}).Error
+}
+
+// GetByID retrieves an address by ID
+func (r *UserAddressRepository) GetByID(ctx context.Context, tx repo.Transaction, id string) (*model.UserAddress, error) {
+	var entity userAddressEntity
+	db := r.getDB(ctx, tx)
+
+	err := db.Scopes(tenantScope(ctx)).Where("id = ?", id).First(&entity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return entity.toModel(), nil
+}
+
+// ListByUserID retrieves the addresses of a user, oldest first
+func (r *UserAddressRepository) ListByUserID(ctx context.Context, tx repo.Transaction, userID string) ([]*model.UserAddress, error) {
+	var entities []userAddressEntity
+	db := r.getDB(ctx, tx)
+
+	if err := db.Scopes(tenantScope(ctx)).Where("user_id = ?", userID).
+		Order("created_at ASC, id ASC").Find(&entities).Error; err != nil {
+		return nil, err
+	}
+
+	addresses := make([]*model.UserAddress, len(entities))
+	for i := range entities {
+		addresses[i] = entities[i].toModel()
+	}
+	return addresses, nil
+}
@@ -0,0 +1,81 @@
+package postgre
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
+)
+
+func TestUserAddressRepositoryDefaults(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping PostgreSQL container test in short mode")
+	}
+
+	db := GetTestDB(t, SetupPostgreSQLContainer(t))
+	require.NoError(t, db.DB.AutoMigrate(&userAddressEntity{}))
+	addresses := NewUserAddressRepository(db.DB)
+	ctx := model.ContextWithTenant(context.Background(), model.DefaultTenantID)
+	userID := uuid.New().String()
+	otherID := uuid.New().String()
+
+	create := func(userID, label string, defaultShipping, defaultBilling bool) *model.UserAddress {
+		address, err := vo.NewAddress(label+" 1", "", "São Paulo", "SP", "01305-000", "BR")
+		require.NoError(t, err)
+		entry, err := model.NewUserAddress(userID, label, address, defaultShipping, defaultBilling)
+		require.NoError(t, err)
+		entry, err = addresses.Create(ctx, nil, entry)
+		require.NoError(t, err)
+		return entry
+	}
+	defaults := func(userID string) (shipping, billing []string) {
+		list, err := addresses.ListByUserID(ctx, nil, userID)
+		require.NoError(t, err)
+		for _, entry := range list {
+			if entry.DefaultShipping {
+				shipping = append(shipping, entry.Label)
+			}
+			if entry.DefaultBilling {
+				billing = append(billing, entry.Label)
+			}
+		}
+		return shipping, billing
+	}
+
+	home := create(userID, "Home", true, true)
+	other := create(otherID, "Other", true, true)
+
+	// A new default takes the flag over from the other addresses of the user only
+	create(userID, "Office", true, false)
+	shipping, billing := defaults(userID)
+	assert.Equal(t, []string{"Office"}, shipping)
+	assert.Equal(t, []string{"Home"}, billing)
+	shipping, billing = defaults(otherID)
+	assert.Equal(t, []string{"Other"}, shipping)
+	assert.Equal(t, []string{"Other"}, billing)
+
+	// An update takes it back, and a stale one changes nothing
+	stored, err := addresses.GetByID(ctx, nil, home.ID)
+	require.NoError(t, err)
+	stale := *stored
+	stored.DefaultShipping = true
+	require.NoError(t, addresses.Update(ctx, nil, stored))
+	shipping, _ = defaults(userID)
+	assert.Equal(t, []string{"Home"}, shipping)
+
+	stale.DefaultShipping = false
+	assert.ErrorIs(t, addresses.Update(ctx, nil, &stale), model.ErrVersionConflict)
+	shipping, _ = defaults(userID)
+	assert.Equal(t, []string{"Home"}, shipping)
+
+	// Other tenants do not see the addresses
+	acme := model.ContextWithTenant(context.Background(), "acme")
+	found, err := addresses.GetByID(acme, nil, other.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
@@ -3,6 +3,7 @@ package postgre
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -23,13 +24,19 @@ func NewUserRepository(db *gorm.DB) repo.IUserRepo {
 
 // userEntity represents the database entity
 type userEntity struct {
-	ID        string     `gorm:"primaryKey;type:uuid"`
-	Email     string     `gorm:"uniqueIndex;not null"`
-	Name      string     `gorm:"not null"`
-	Password  string     `gorm:"not null"`
-	CreatedAt time.Time  `gorm:"autoCreateTime"`
-	UpdatedAt time.Time  `gorm:"autoUpdateTime"`
-	DeletedAt *time.Time `gorm:"index"`
+	ID       string `gorm:"primaryKey;type:uuid"`
+	TenantID string `gorm:"uniqueIndex:idx_users_tenant_email;not null;default:'default'"`
+	Email    string `gorm:"uniqueIndex:idx_users_tenant_email;not null"`
+	Name     string `gorm:"not null"`
+	Password string `gorm:"not null"`
+	// Roles and Permissions are comma separated
+	Roles           string `gorm:"type:text;not null;default:'customer'"`
+	Permissions     string `gorm:"type:text;not null;default:''"`
+	EmailVerifiedAt *time.Time
+	Version         int        `gorm:"not null;default:1"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime"`
+	DeletedAt       *time.Time `gorm:"index"`
 }
 
 func (userEntity) TableName() string {
@@ -39,29 +46,61 @@ func (userEntity) TableName() string {
 // toModel converts entity to domain model
 func (e *userEntity) toModel() *model.User {
 	return &model.User{
-		ID:        e.ID,
-		Email:     e.Email,
-		Name:      e.Name,
-		Password:  e.Password,
-		CreatedAt: e.CreatedAt,
-		UpdatedAt: e.UpdatedAt,
-		DeletedAt: e.DeletedAt,
+		ID:              e.ID,
+		TenantID:        e.TenantID,
+		Email:           e.Email,
+		Name:            e.Name,
+		Password:        e.Password,
+		Roles:           splitList[model.Role](e.Roles),
+		Permissions:     splitList[model.Permission](e.Permissions),
+		EmailVerifiedAt: e.EmailVerifiedAt,
+		Version:         e.Version,
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+		DeletedAt:       e.DeletedAt,
 	}
 }
 
 // toEntity converts domain model to entity
 func toUserEntity(u *model.User) *userEntity {
 	return &userEntity{
-		ID:        u.ID,
-		Email:     u.Email,
-		Name:      u.Name,
-		Password:  u.Password,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
-		DeletedAt: u.DeletedAt,
+		ID:              u.ID,
+		TenantID:        u.TenantID,
+		Email:           u.Email,
+		Name:            u.Name,
+		Password:        u.Password,
+		Roles:           joinList(u.Roles),
+		Permissions:     joinList(u.Permissions),
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		Version:         u.Version,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+		DeletedAt:       u.DeletedAt,
 	}
 }
 
+// joinList stores a list of names as a comma separated column
+func joinList[T ~string](values []T) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = string(v)
+	}
+	return strings.Join(parts, ",")
+}
+
+// splitList reads a comma separated column written by joinList
+func splitList[T ~string](value string) []T {
+	if value == "" {
+		return nil
+	}
+	parts := strings.Split(value, ",")
+	values := make([]T, len(parts))
+	for i, part := range parts {
+		values[i] = T(part)
+	}
+	return values
+}
+
 func (r *UserRepository) getDB(ctx context.Context, tx repo.Transaction) *gorm.DB {
 	if tx != nil {
 		if gormTx, ok := tx.GetTx().(*gorm.DB); ok {
@@ -71,8 +110,11 @@ func (r *UserRepository) getDB(ctx context.Context, tx repo.Transaction) *gorm.D
 	return r.db.WithContext(ctx)
 }
 
-// Create creates a new user
+// Create creates a new user, in the tenant of ctx unless the user has one
 func (r *UserRepository) Create(ctx context.Context, tx repo.Transaction, user *model.User) (*model.User, error) {
+	if user.TenantID == "" {
+		user.TenantID = model.TenantForCreate(ctx)
+	}
 	entity := toUserEntity(user)
 	db := r.getDB(ctx, tx)
 
@@ -83,12 +125,34 @@ func (r *UserRepository) Create(ctx context.Context, tx repo.Transaction, user *
 	return entity.toModel(), nil
 }
 
-// Update updates an existing user
+// Update updates an existing user if it is still at the version it was read with.
+// On success the user's version is incremented; otherwise model.ErrVersionConflict is returned.
 func (r *UserRepository) Update(ctx context.Context, tx repo.Transaction, user *model.User) error {
-	entity := toUserEntity(user)
 	db := r.getDB(ctx, tx)
 
-	return db.Save(entity).Error
+	updatedAt := time.Now()
+	result := db.Model(&userEntity{}).Scopes(tenantScope(ctx)).
+		Where("id = ? AND version = ? AND deleted_at IS NULL", user.ID, user.Version).
+		Updates(map[string]interface{}{
+			"email":             user.Email,
+			"name":              user.Name,
+			"password":          user.Password,
+			"roles":             joinList(user.Roles),
+			"permissions":       joinList(user.Permissions),
+			"email_verified_at": user.EmailVerifiedAt,
+			"version":           user.Version + 1,
+			"updated_at":        updatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrVersionConflict
+	}
+
+	user.Version++
+	user.UpdatedAt = updatedAt
+	return nil
 }
 
 // Delete soft deletes a user by ID
@@ -96,7 +160,7 @@ func (r *UserRepository) Delete(ctx context.Context, tx repo.Transaction, id str
 	db := r.getDB(ctx, tx)
 
 	now := time.Now()
-	return db.Model(&userEntity{}).Where("id = ?", id).Update("deleted_at", now).Error
+	return db.Model(&userEntity{}).Scopes(tenantScope(ctx)).Where("id = ?", id).Update("deleted_at", now).Error
 }
 
 // GetByID retrieves a user by ID
@@ -104,7 +168,7 @@ func (r *UserRepository) GetByID(ctx context.Context, tx repo.Transaction, id st
 	var entity userEntity
 	db := r.getDB(ctx, tx)
 
-	err := db.Where("id = ? AND deleted_at IS NULL", id).First(&entity).Error
+	err := db.Scopes(tenantScope(ctx)).Where("id = ? AND deleted_at IS NULL", id).First(&entity).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -120,7 +184,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, tx repo.Transaction, em
 	var entity userEntity
 	db := r.getDB(ctx, tx)
 
-	err := db.Where("email = ? AND deleted_at IS NULL", email).First(&entity).Error
+	err := db.Scopes(tenantScope(ctx)).Where("email = ? AND deleted_at IS NULL", email).First(&entity).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -138,12 +202,12 @@ func (r *UserRepository) List(ctx context.Context, tx repo.Transaction, offset,
 	db := r.getDB(ctx, tx)
 
 	// Get total count
-	if err := db.Model(&userEntity{}).Where("deleted_at IS NULL").Count(&total).Error; err != nil {
+	if err := db.Model(&userEntity{}).Scopes(tenantScope(ctx)).Where("deleted_at IS NULL").Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get paginated results
-	if err := db.Where("deleted_at IS NULL").Offset(offset).Limit(limit).Find(&entities).Error; err != nil {
+	if err := db.Scopes(tenantScope(ctx)).Where("deleted_at IS NULL").Offset(offset).Limit(limit).Find(&entities).Error; err != nil {
 		return nil, 0, err
 	}
 
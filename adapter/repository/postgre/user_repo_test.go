@@ -0,0 +1,44 @@
+package postgre
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+func TestUserRepositoryVersionCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping PostgreSQL container test in short mode")
+	}
+
+	db := GetTestDB(t, SetupPostgreSQLContainer(t))
+	require.NoError(t, db.DB.AutoMigrate(&userEntity{}))
+	users := NewUserRepository(db.DB)
+	ctx := model.ContextWithTenant(context.Background(), model.DefaultTenantID)
+
+	user, err := model.NewUser("jane@example.com", "Jane", "hash")
+	require.NoError(t, err)
+	user, err = users.Create(ctx, nil, user)
+	require.NoError(t, err)
+
+	first, err := users.GetByID(ctx, nil, user.ID)
+	require.NoError(t, err)
+	second, err := users.GetByID(ctx, nil, user.ID)
+	require.NoError(t, err)
+
+	first.Name = "Jane Doe"
+	require.NoError(t, users.Update(ctx, nil, first))
+	assert.Equal(t, user.Version+1, first.Version)
+
+	second.Name = "Mallory"
+	assert.ErrorIs(t, users.Update(ctx, nil, second), model.ErrVersionConflict)
+
+	stored, err := users.GetByID(ctx, nil, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe", stored.Name)
+	assert.Equal(t, first.Version, stored.Version)
+}
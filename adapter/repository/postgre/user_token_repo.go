@@ -0,0 +1,108 @@
+package postgre
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// UserTokenRepository implements IUserTokenRepo using PostgreSQL
+type UserTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewUserTokenRepository creates a new user token repository
+func NewUserTokenRepository(db *gorm.DB) repo.IUserTokenRepo {
+	return &UserTokenRepository{db: db}
+}
+
+// userTokenEntity represents the database entity
+type userTokenEntity struct {
+	ID        string    `gorm:"primaryKey;type:uuid"`
+	TenantID  string    `gorm:"not null;default:'default';index"`
+	UserID    string    `gorm:"type:uuid;not null;index:idx_user_tokens_user_id"`
+	Purpose   string    `gorm:"not null;index:idx_user_tokens_user_id"`
+	Hash      string    `gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (userTokenEntity) TableName() string {
+	return "user_tokens"
+}
+
+// toModel converts entity to domain model
+func (e *userTokenEntity) toModel() *model.UserToken {
+	return &model.UserToken{
+		ID:        e.ID,
+		TenantID:  e.TenantID,
+		UserID:    e.UserID,
+		Purpose:   model.UserTokenPurpose(e.Purpose),
+		Hash:      e.Hash,
+		ExpiresAt: e.ExpiresAt,
+		UsedAt:    e.UsedAt,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// toUserTokenEntity converts domain model to entity
+func toUserTokenEntity(t *model.UserToken) *userTokenEntity {
+	return &userTokenEntity{
+		ID:        t.ID,
+		TenantID:  t.TenantID,
+		UserID:    t.UserID,
+		Purpose:   string(t.Purpose),
+		Hash:      t.Hash,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func (r *UserTokenRepository) getDB(ctx context.Context, tx repo.Transaction) *gorm.DB {
+	if tx != nil {
+		if gormTx, ok := tx.GetTx().(*gorm.DB); ok {
+			return gormTx.WithContext(ctx)
+		}
+	}
+	return r.db.WithContext(ctx)
+}
+
+// Create creates a new user token in the tenant of ctx unless the token names one
+func (r *UserTokenRepository) Create(ctx context.Context, tx repo.Transaction, token *model.UserToken) error {
+	if token.TenantID == "" {
+		token.TenantID = model.TenantForCreate(ctx)
+	}
+	return r.getDB(ctx, tx).Create(toUserTokenEntity(token)).Error
+}
+
+// Consume marks a usable token as used in a single statement, so concurrent requests cannot
+// both use it, and returns it; nil when no unused and unexpired token matches
+func (r *UserTokenRepository) Consume(ctx context.Context, tx repo.Transaction, purpose model.UserTokenPurpose, hash string, at time.Time) (*model.UserToken, error) {
+	var entities []userTokenEntity
+	result := r.getDB(ctx, tx).Model(&entities).
+		Clauses(clause.Returning{}).
+		Scopes(tenantScope(ctx)).
+		Where("purpose = ? AND hash = ? AND used_at IS NULL AND expires_at > ?", string(purpose), hash, at).
+		Update("used_at", at)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(entities) == 0 {
+		return nil, nil
+	}
+	return entities[0].toModel(), nil
+}
+
+// RevokeByUser marks every unused token of a user with the given purpose as used
+func (r *UserTokenRepository) RevokeByUser(ctx context.Context, tx repo.Transaction, userID string, purpose model.UserTokenPurpose, at time.Time) error {
+	return r.getDB(ctx, tx).Model(&userTokenEntity{}).Scopes(tenantScope(ctx)).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, string(purpose)).
+		Update("used_at", at).Error
+}
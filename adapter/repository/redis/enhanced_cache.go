@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 	"sync"
 	"time"
 
@@ -216,31 +217,76 @@ func (c *EnhancedCache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
-// DeleteWithPattern removes values matching a pattern from the cache
+// DeleteWithPattern removes values matching a pattern from the cache.
+// Keys are found with SCAN, so Redis keeps serving other clients meanwhile.
 func (c *EnhancedCache) DeleteWithPattern(ctx context.Context, pattern string) error {
-	// Find all keys matching the pattern
-	keys, err := c.client.Client.Keys(ctx, pattern).Result()
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Client.Scan(ctx, cursor, pattern, 1000).Result()
+		if err != nil {
+			return apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to scan keys with pattern: %s", pattern)
+		}
+
+		// Delete the matching keys of this batch
+		if len(keys) > 0 {
+			if err := c.client.Client.Del(ctx, keys...).Err(); err != nil {
+				return apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to delete keys with pattern: %s", pattern)
+			}
+
+			// Remove from tracked keys if enabled
+			if c.options.EnableKeyTracking {
+				c.keysMutex.Lock()
+				for _, key := range keys {
+					delete(c.trackedKeys, key)
+				}
+				c.keysMutex.Unlock()
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// Increment adds one to the counter stored under key and returns its new value.
+// With a ttl above zero, a new counter expires after ttl.
+func (c *EnhancedCache) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	value, err := c.client.Client.Incr(ctx, key).Result()
 	if err != nil {
-		return apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to get keys with pattern: %s", pattern)
+		return 0, apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to increment counter: %s", key)
 	}
 
-	// Delete all matching keys
-	if len(keys) > 0 {
-		if err := c.client.Client.Del(ctx, keys...).Err(); err != nil {
-			return apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to delete keys with pattern: %s", pattern)
+	if value == 1 && ttl > 0 {
+		if err := c.client.Client.Expire(ctx, key, ttl).Err(); err != nil {
+			return 0, apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to expire counter: %s", key)
 		}
+	}
 
-		// Remove from tracked keys if enabled
-		if c.options.EnableKeyTracking {
-			c.keysMutex.Lock()
-			for _, key := range keys {
-				delete(c.trackedKeys, key)
-			}
-			c.keysMutex.Unlock()
+	return value, nil
+}
+
+// Counters returns the counters stored under keys by Increment, zero for missing ones
+func (c *EnhancedCache) Counters(ctx context.Context, keys ...string) ([]int64, error) {
+	values, err := c.client.Client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to get counters")
+	}
+
+	counters := make([]int64, len(values))
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		counters[i], err = strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, apperrors.Wrapf(err, apperrors.ErrorTypeSystem, "invalid counter: %s", keys[i])
 		}
 	}
 
-	return nil
+	return counters, nil
 }
 
 // WithLock executes a function with a distributed lock
@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnhancedCacheCounters(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	cache := NewEnhancedCache(client, DefaultCacheOptions())
+	ctx := context.Background()
+
+	value, err := cache.Increment(ctx, "counter:a", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), value)
+
+	value, err = cache.Increment(ctx, "counter:a", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), value)
+
+	ttl, err := client.Client.TTL(ctx, "counter:a").Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+
+	// Without a ttl the counter never expires
+	_, err = cache.Increment(ctx, "counter:b", 0)
+	require.NoError(t, err)
+	ttl, err = client.Client.TTL(ctx, "counter:b").Result()
+	require.NoError(t, err)
+	assert.Less(t, ttl, time.Duration(0))
+
+	counters, err := cache.Counters(ctx, "counter:a", "counter:missing", "counter:b")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 0, 1}, counters)
+}
+
+func TestEnhancedCacheDeleteWithPattern(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	cache := NewEnhancedCache(client, DefaultCacheOptions())
+	ctx := context.Background()
+
+	for _, key := range []string{"product:t1:a", "product:t1:b", "product:t2:a"} {
+		require.NoError(t, cache.Set(ctx, key, key, time.Minute))
+	}
+
+	require.NoError(t, cache.DeleteWithPattern(ctx, "product:t1:*"))
+
+	keys, err := client.Client.Keys(ctx, "product:*").Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"product:t2:a"}, keys)
+}
@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	apperrors "cactus-golang-hexagonal-microservice-boilerplate/util/errors"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+const lockKeyPrefix = "lock:"
+
+// releaseLockScript deletes the lock only if it is still held by the caller's token
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// DistributedLock provides a token based mutual exclusion lock shared by all instances
+type DistributedLock struct {
+	client *RedisClient
+}
+
+// NewDistributedLock creates a new distributed lock backed by Redis
+func NewDistributedLock(client *RedisClient) *DistributedLock {
+	return &DistributedLock{client: client}
+}
+
+// TryLock tries to acquire the lock once without waiting.
+// The returned unlock function releases the lock only if it is still owned by this caller.
+func (l *DistributedLock) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	lockKey := lockKeyPrefix + key
+	token := uuid.New().String()
+
+	acquired, err := l.client.Client.SetNX(ctx, lockKey, token, ttl).Result()
+	if err != nil {
+		return nil, false, apperrors.Wrapf(err, apperrors.ErrorTypeSystem, "failed to acquire lock: %s", lockKey)
+	}
+	if !acquired {
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		// Use a fresh context so the lock is released even if the caller's context expired
+		releaseCtx, cancel := context.WithTimeout(context.Background(), l.client.opts.WriteTimeout)
+		defer cancel()
+
+		if err := releaseLockScript.Run(releaseCtx, l.client.Client, []string{lockKey}, token).Err(); err != nil {
+			log.SugaredLogger.Warnf("Failed to release lock %s: %v", lockKey, err)
+		}
+	}
+
+	return unlock, true, nil
+}
@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistributedLock(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	lock := NewDistributedLock(client)
+	ctx := context.Background()
+
+	t.Run("only one caller holds the lock", func(t *testing.T) {
+		unlock, acquired, err := lock.TryLock(ctx, "job:a", time.Minute)
+		require.NoError(t, err)
+		require.True(t, acquired)
+
+		_, acquired, err = lock.TryLock(ctx, "job:a", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+
+		unlock()
+
+		unlock, acquired, err = lock.TryLock(ctx, "job:a", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+		unlock()
+	})
+
+	t.Run("a stale unlock leaves the next owner's lock alone", func(t *testing.T) {
+		staleUnlock, acquired, err := lock.TryLock(ctx, "job:b", time.Minute)
+		require.NoError(t, err)
+		require.True(t, acquired)
+
+		// The lock expires while its first owner still runs
+		require.NoError(t, client.Client.Del(ctx, lockKeyPrefix+"job:b").Err())
+
+		unlock, acquired, err := lock.TryLock(ctx, "job:b", time.Minute)
+		require.NoError(t, err)
+		require.True(t, acquired)
+		defer unlock()
+
+		staleUnlock()
+
+		_, acquired, err = lock.TryLock(ctx, "job:b", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+	})
+}
@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	apperrors "cactus-golang-hexagonal-microservice-boilerplate/util/errors"
+)
+
+// Login attempt keys:
+//   - login:failures:<key> counter of failed logins, expiring with the failure window
+//   - login:lock:<key> set while the key is locked out, expiring with the lockout
+const (
+	loginFailuresKeyPrefix = "login:failures:"
+	loginLockKeyPrefix     = "login:lock:"
+)
+
+// LoginAttemptStore implements ILoginAttemptStore using Redis
+type LoginAttemptStore struct {
+	client *RedisClient
+}
+
+// NewLoginAttemptStore creates a new Redis backed login attempt store
+func NewLoginAttemptStore(client *RedisClient) repo.ILoginAttemptStore {
+	return &LoginAttemptStore{client: client}
+}
+
+// RecordFailure counts a failed login, the window starts with the first failure
+func (s *LoginAttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	count, err := countAttemptScript.Run(ctx, s.client.Client, []string{loginFailuresKeyPrefix + key}, window.Milliseconds()).Int()
+	if err != nil {
+		return 0, apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to record failed login: %s", key)
+	}
+	return count, nil
+}
+
+// Lock locks the key out for duration and resets its failures
+func (s *LoginAttemptStore) Lock(ctx context.Context, key string, duration time.Duration) error {
+	_, err := s.client.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, loginLockKeyPrefix+key, 1, duration)
+		pipe.Del(ctx, loginFailuresKeyPrefix+key)
+		return nil
+	})
+	if err != nil {
+		return apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to lock out: %s", key)
+	}
+	return nil
+}
+
+// LockedFor returns the remaining lockout of the key, zero when it is not locked out
+func (s *LoginAttemptStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.client.Client.PTTL(ctx, loginLockKeyPrefix+key).Result()
+	if err != nil {
+		return 0, apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to read lockout: %s", key)
+	}
+	// Missing keys report a negative TTL
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// Clear resets the failures of the key and lifts its lockout
+func (s *LoginAttemptStore) Clear(ctx context.Context, key string) error {
+	if err := s.client.Client.Del(ctx, loginFailuresKeyPrefix+key, loginLockKeyPrefix+key).Err(); err != nil {
+		return apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to clear login attempts: %s", key)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginAttemptStore(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	store := NewLoginAttemptStore(client)
+	ctx := context.Background()
+
+	t.Run("counts failures", func(t *testing.T) {
+		for want := 1; want <= 3; want++ {
+			count, err := store.RecordFailure(ctx, "account:a@example.com", time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, want, count)
+		}
+	})
+
+	t.Run("locks out and resets failures", func(t *testing.T) {
+		lockedFor, err := store.LockedFor(ctx, "account:a@example.com")
+		require.NoError(t, err)
+		assert.Zero(t, lockedFor)
+
+		require.NoError(t, store.Lock(ctx, "account:a@example.com", time.Minute))
+
+		lockedFor, err = store.LockedFor(ctx, "account:a@example.com")
+		require.NoError(t, err)
+		assert.Greater(t, lockedFor, 50*time.Second)
+
+		count, err := store.RecordFailure(ctx, "account:a@example.com", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("clear lifts the lockout", func(t *testing.T) {
+		require.NoError(t, store.Clear(ctx, "account:a@example.com"))
+
+		lockedFor, err := store.LockedFor(ctx, "account:a@example.com")
+		require.NoError(t, err)
+		assert.Zero(t, lockedFor)
+	})
+}
@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	apperrors "cactus-golang-hexagonal-microservice-boilerplate/util/errors"
+)
+
+// Rate limit keys:
+//   - ratelimit:<key> counter of the current window, expiring with it
+const rateLimitKeyPrefix = "ratelimit:"
+
+// countAttemptScript counts an attempt, starting the window with the first one.
+// KEYS: counter. ARGV: window (ms). Returns the attempts in the current window.
+var countAttemptScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// RateLimiter implements IRateLimiter with fixed windows kept in Redis
+type RateLimiter struct {
+	client *RedisClient
+}
+
+// NewRateLimiter creates a new Redis backed rate limiter
+func NewRateLimiter(client *RedisClient) repo.IRateLimiter {
+	return &RateLimiter{client: client}
+}
+
+// Allow counts an attempt for the key and reports whether it stays within limit attempts per window
+func (l *RateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	count, err := countAttemptScript.Run(ctx, l.client.Client, []string{rateLimitKeyPrefix + key}, window.Milliseconds()).Int()
+	if err != nil {
+		return false, apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to count attempt: %s", key)
+	}
+	return count <= limit, nil
+}
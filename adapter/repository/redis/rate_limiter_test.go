@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	limiter := NewRateLimiter(client)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		allowed, err := limiter.Allow(ctx, "email:a@example.com", 2, time.Hour)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+
+	allowed, err := limiter.Allow(ctx, "email:a@example.com", 2, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	// Keys are counted apart
+	allowed, err = limiter.Allow(ctx, "email:b@example.com", 2, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
@@ -0,0 +1,135 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	apperrors "cactus-golang-hexagonal-microservice-boilerplate/util/errors"
+)
+
+// Refresh session keys:
+//   - auth:session:<session_id> hash with user_id and token_id, expiring with the session
+//   - auth:user-sessions:<user_id> sorted set of the user's session IDs scored by expiry,
+//     expiring with the last of them
+const (
+	refreshSessionKeyPrefix = "auth:session:"
+	userSessionsKeyPrefix   = "auth:user-sessions:"
+)
+
+// trackSession is shared by the scripts below: it scores a session of the user set by its expiry,
+// drops the sessions that expired and makes the set expire with its last session.
+// It expects user_key, session_id, expires_at and now to be set.
+const trackSession = `
+local function track_session(user_key, session_id, expires_at, now)
+	redis.call("ZADD", user_key, expires_at, session_id)
+	redis.call("ZREMRANGEBYSCORE", user_key, "-inf", now)
+	local last = redis.call("ZRANGE", user_key, -1, -1, "WITHSCORES")
+	if last[2] then
+		redis.call("PEXPIREAT", user_key, last[2])
+	end
+end
+`
+
+// saveSessionScript stores a session and tracks it for its user.
+// KEYS: session, user sessions. ARGV: session ID, user ID, token ID, expires at, now (unix ms).
+var saveSessionScript = redis.NewScript(trackSession + `
+redis.call("HSET", KEYS[1], "user_id", ARGV[2], "token_id", ARGV[3])
+redis.call("PEXPIREAT", KEYS[1], ARGV[4])
+track_session(KEYS[2], ARGV[1], ARGV[4], ARGV[5])
+return 1
+`)
+
+// rotateSessionScript replaces the current token of a session if it is still the expected one.
+// KEYS: session. ARGV: expected token ID, next token ID, expires at, now (unix ms), session ID,
+// user sessions key prefix.
+// Returns 1 when rotated, 0 when the session is gone or holds another token.
+var rotateSessionScript = redis.NewScript(trackSession + `
+if redis.call("HGET", KEYS[1], "token_id") ~= ARGV[1] then
+	return 0
+end
+redis.call("HSET", KEYS[1], "token_id", ARGV[2])
+redis.call("PEXPIREAT", KEYS[1], ARGV[3])
+local user_id = redis.call("HGET", KEYS[1], "user_id")
+if user_id then
+	track_session(ARGV[6] .. user_id, ARGV[5], ARGV[3], ARGV[4])
+end
+return 1
+`)
+
+// revokeSessionScript deletes a session and stops tracking it for its user.
+// KEYS: session. ARGV: session ID, user sessions key prefix.
+var revokeSessionScript = redis.NewScript(`
+local user_id = redis.call("HGET", KEYS[1], "user_id")
+redis.call("DEL", KEYS[1])
+if user_id then
+	redis.call("ZREM", ARGV[2] .. user_id, ARGV[1])
+end
+return 1
+`)
+
+// revokeUserSessionsScript deletes every session of a user.
+// KEYS: user sessions. ARGV: session key prefix.
+// Returns the number of sessions tracked for the user.
+var revokeUserSessionsScript = redis.NewScript(`
+local sessions = redis.call("ZRANGE", KEYS[1], 0, -1)
+for _, session_id in ipairs(sessions) do
+	redis.call("DEL", ARGV[1] .. session_id)
+end
+redis.call("DEL", KEYS[1])
+return #sessions
+`)
+
+// RefreshSessionStore implements IRefreshSessionStore using Redis
+type RefreshSessionStore struct {
+	client *RedisClient
+}
+
+// NewRefreshSessionStore creates a new Redis backed refresh session store
+func NewRefreshSessionStore(client *RedisClient) repo.IRefreshSessionStore {
+	return &RefreshSessionStore{client: client}
+}
+
+// Save stores a session until it expires
+func (s *RefreshSessionStore) Save(ctx context.Context, session *model.RefreshSession) error {
+	keys := []string{refreshSessionKeyPrefix + session.ID, userSessionsKeyPrefix + session.UserID}
+	err := saveSessionScript.Run(ctx, s.client.Client, keys,
+		session.ID, session.UserID, session.TokenID, session.ExpiresAt.UnixMilli(), time.Now().UnixMilli()).Err()
+	if err != nil {
+		return apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to save refresh session: %s", session.ID)
+	}
+	return nil
+}
+
+// Rotate atomically replaces the current token of a session if it is still tokenID
+func (s *RefreshSessionStore) Rotate(ctx context.Context, sessionID, tokenID, nextTokenID string, expiresAt time.Time) (bool, error) {
+	rotated, err := rotateSessionScript.Run(ctx, s.client.Client, []string{refreshSessionKeyPrefix + sessionID},
+		tokenID, nextTokenID, expiresAt.UnixMilli(), time.Now().UnixMilli(), sessionID, userSessionsKeyPrefix).Int()
+	if err != nil {
+		return false, apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to rotate refresh session: %s", sessionID)
+	}
+	return rotated == 1, nil
+}
+
+// Revoke deletes a session
+func (s *RefreshSessionStore) Revoke(ctx context.Context, sessionID string) error {
+	err := revokeSessionScript.Run(ctx, s.client.Client, []string{refreshSessionKeyPrefix + sessionID},
+		sessionID, userSessionsKeyPrefix).Err()
+	if err != nil {
+		return apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to revoke refresh session: %s", sessionID)
+	}
+	return nil
+}
+
+// RevokeByUser deletes every session of a user
+func (s *RefreshSessionStore) RevokeByUser(ctx context.Context, userID string) error {
+	err := revokeUserSessionsScript.Run(ctx, s.client.Client, []string{userSessionsKeyPrefix + userID},
+		refreshSessionKeyPrefix).Err()
+	if err != nil {
+		return apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to revoke refresh sessions of user: %s", userID)
+	}
+	return nil
+}
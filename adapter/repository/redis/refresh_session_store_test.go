@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+func TestRefreshSessionStore(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	store := NewRefreshSessionStore(client)
+	ctx := context.Background()
+
+	session := &model.RefreshSession{ID: "session-1", UserID: "user-1", TokenID: "token-1", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, store.Save(ctx, session))
+
+	t.Run("rotates the current token only", func(t *testing.T) {
+		rotated, err := store.Rotate(ctx, "session-1", "token-1", "token-2", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.True(t, rotated)
+
+		// The replaced token cannot be used again
+		rotated, err = store.Rotate(ctx, "session-1", "token-1", "token-3", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.False(t, rotated)
+	})
+
+	t.Run("revoked sessions cannot rotate", func(t *testing.T) {
+		require.NoError(t, store.Revoke(ctx, "session-1"))
+
+		rotated, err := store.Rotate(ctx, "session-1", "token-2", "token-3", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.False(t, rotated)
+	})
+}
+
+func TestRefreshSessionStoreRevokeByUser(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	store := NewRefreshSessionStore(client)
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(time.Hour)
+	require.NoError(t, store.Save(ctx, &model.RefreshSession{ID: "session-a", UserID: "user-1", TokenID: "token-a", ExpiresAt: expiresAt}))
+	require.NoError(t, store.Save(ctx, &model.RefreshSession{ID: "session-b", UserID: "user-1", TokenID: "token-b", ExpiresAt: expiresAt}))
+	require.NoError(t, store.Save(ctx, &model.RefreshSession{ID: "session-c", UserID: "user-2", TokenID: "token-c", ExpiresAt: expiresAt}))
+
+	// A rotated session is still revoked with its user
+	rotated, err := store.Rotate(ctx, "session-b", "token-b", "token-b2", expiresAt.Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, rotated)
+
+	require.NoError(t, store.RevokeByUser(ctx, "user-1"))
+
+	for _, session := range []struct{ id, token string }{{"session-a", "token-a"}, {"session-b", "token-b2"}} {
+		rotated, err := store.Rotate(ctx, session.id, session.token, "next", expiresAt)
+		require.NoError(t, err)
+		assert.False(t, rotated, session.id)
+	}
+
+	// Other users keep their sessions
+	rotated, err = store.Rotate(ctx, "session-c", "token-c", "token-c2", expiresAt)
+	require.NoError(t, err)
+	assert.True(t, rotated)
+}
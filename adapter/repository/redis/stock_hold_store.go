@@ -0,0 +1,274 @@
+package redis
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	apperrors "cactus-golang-hexagonal-microservice-boilerplate/util/errors"
+)
+
+// Stock hold keys, where a hold is identified by <tenant>:<key> since callers choose the keys:
+//   - stock_hold:hold:<tenant>:<key> hash with expires_at, created_at and one item:<product_id>:<sku> field per item
+//   - stock_hold:held:<product_id>:<sku> hash of held quantities by hold
+//   - stock_hold:expiry:<product_id>:<sku> sorted set of holds scored by expiry
+//   - stock_hold:expiry sorted set of all holds scored by expiry, used to delete expired holds
+const (
+	stockHoldKeyPrefix       = "stock_hold:hold:"
+	stockHoldHeldPrefix      = "stock_hold:held:"
+	stockHoldSKUExpiryPrefix = "stock_hold:expiry:"
+	stockHoldExpiryKey       = "stock_hold:expiry"
+	stockHoldItemField       = "item:"
+)
+
+// purgeExpiredHolds is shared by the scripts below: it drops the expired holds of one SKU
+// and returns the quantity still held
+const purgeExpiredHolds = `
+local function held(held_key, expiry_key, now)
+	local expired = redis.call("ZRANGEBYSCORE", expiry_key, "-inf", now)
+	for _, member in ipairs(expired) do
+		redis.call("HDEL", held_key, member)
+	end
+	redis.call("ZREMRANGEBYSCORE", expiry_key, "-inf", now)
+	local total = 0
+	for _, qty in ipairs(redis.call("HVALS", held_key)) do
+		total = total + tonumber(qty)
+	end
+	return total
+end
+`
+
+// createHoldScript stores a hold only if every item is covered by its stock minus the active holds.
+// KEYS: hold, expiry index, then held and expiry keys per item.
+// ARGV: hold ID, now, expires at, created at, then field, quantity and stock per item.
+// Returns 0 on success, -1 when the key has an active hold, or the index of the first uncovered item.
+var createHoldScript = redis.NewScript(purgeExpiredHolds + `
+local expires_at = redis.call("HGET", KEYS[1], "expires_at")
+if expires_at and tonumber(expires_at) > tonumber(ARGV[2]) then
+	return -1
+end
+
+local n = (#KEYS - 2) / 2
+for i = 1, n do
+	local base = 4 + 3 * (i - 1)
+	local available = tonumber(ARGV[base + 3]) - held(KEYS[1 + 2 * i], KEYS[2 + 2 * i], ARGV[2])
+	if available < tonumber(ARGV[base + 2]) then
+		return i
+	end
+end
+
+redis.call("DEL", KEYS[1])
+for i = 1, n do
+	local base = 4 + 3 * (i - 1)
+	redis.call("HSET", KEYS[1 + 2 * i], ARGV[1], ARGV[base + 2])
+	redis.call("ZADD", KEYS[2 + 2 * i], ARGV[3], ARGV[1])
+	redis.call("HSET", KEYS[1], ARGV[base + 1], ARGV[base + 2])
+end
+redis.call("HSET", KEYS[1], "expires_at", ARGV[3], "created_at", ARGV[4])
+redis.call("ZADD", KEYS[2], ARGV[3], ARGV[1])
+return 0
+`)
+
+// heldQuantitiesScript returns the quantity held for each SKU.
+// KEYS: held and expiry keys per SKU. ARGV: now.
+var heldQuantitiesScript = redis.NewScript(purgeExpiredHolds + `
+local result = {}
+for i = 1, #KEYS / 2 do
+	result[i] = held(KEYS[2 * i - 1], KEYS[2 * i], ARGV[1])
+end
+return result
+`)
+
+// deleteHoldScript removes a hold with its per SKU entries and returns its fields.
+// KEYS: hold, expiry index. ARGV: hold ID, held prefix, SKU expiry prefix, item field prefix
+// and, optionally, a time the hold must have expired by to be removed.
+var deleteHoldScript = redis.NewScript(`
+if ARGV[5] then
+	local expires_at = redis.call("HGET", KEYS[1], "expires_at")
+	if expires_at and tonumber(expires_at) > tonumber(ARGV[5]) then
+		return {}
+	end
+end
+
+local fields = redis.call("HGETALL", KEYS[1])
+local prefix_len = string.len(ARGV[4])
+for i = 1, #fields, 2 do
+	if string.sub(fields[i], 1, prefix_len) == ARGV[4] then
+		local item = string.sub(fields[i], prefix_len + 1)
+		redis.call("HDEL", ARGV[2] .. item, ARGV[1])
+		redis.call("ZREM", ARGV[3] .. item, ARGV[1])
+	end
+end
+redis.call("DEL", KEYS[1])
+redis.call("ZREM", KEYS[2], ARGV[1])
+return fields
+`)
+
+// StockHoldStore implements IStockHoldStore with Lua scripts, so checking availability
+// and taking a hold happen atomically across instances. Holds belong to the tenant of ctx.
+type StockHoldStore struct {
+	client *RedisClient
+}
+
+// NewStockHoldStore creates a new Redis backed stock hold store
+func NewStockHoldStore(client *RedisClient) repo.IStockHoldStore {
+	return &StockHoldStore{client: client}
+}
+
+// Create stores the hold if the stock minus the active holds covers every item
+func (s *StockHoldStore) Create(ctx context.Context, hold *model.StockHold, stock []int) error {
+	id := stockHoldID(model.TenantFromContext(ctx), hold.Key)
+	keys := []string{stockHoldKeyPrefix + id, stockHoldExpiryKey}
+	args := []interface{}{id, time.Now().UnixMilli(), hold.ExpiresAt.UnixMilli(), hold.CreatedAt.UnixMilli()}
+	for i, item := range hold.Items {
+		suffix := stockHoldItemSuffix(item.ProductID, item.SKU)
+		keys = append(keys, stockHoldHeldPrefix+suffix, stockHoldSKUExpiryPrefix+suffix)
+		args = append(args, stockHoldItemField+suffix, item.Quantity, stock[i])
+	}
+
+	result, err := createHoldScript.Run(ctx, s.client.Client, keys, args...).Int()
+	if err != nil {
+		return apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to create stock hold: %s", hold.Key)
+	}
+
+	switch {
+	case result < 0:
+		return model.ErrStockHoldExists
+	case result > 0:
+		return model.ErrProductInsufficientStock
+	}
+	return nil
+}
+
+// Get retrieves a hold by key
+func (s *StockHoldStore) Get(ctx context.Context, key string) (*model.StockHold, error) {
+	fields, err := s.client.Client.HGetAll(ctx, stockHoldKeyPrefix+stockHoldID(model.TenantFromContext(ctx), key)).Result()
+	if err != nil {
+		return nil, apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to get stock hold: %s", key)
+	}
+	return toStockHold(key, fields)
+}
+
+// Delete removes a hold and returns it
+func (s *StockHoldStore) Delete(ctx context.Context, key string) (*model.StockHold, error) {
+	return s.delete(ctx, key)
+}
+
+// DeleteExpired removes a hold only if it expired before the given time
+func (s *StockHoldStore) DeleteExpired(ctx context.Context, key string, before time.Time) (*model.StockHold, error) {
+	return s.delete(ctx, key, before.UnixMilli())
+}
+
+func (s *StockHoldStore) delete(ctx context.Context, key string, expiredBefore ...interface{}) (*model.StockHold, error) {
+	id := stockHoldID(model.TenantFromContext(ctx), key)
+	keys := []string{stockHoldKeyPrefix + id, stockHoldExpiryKey}
+	args := append([]interface{}{id, stockHoldHeldPrefix, stockHoldSKUExpiryPrefix, stockHoldItemField}, expiredBefore...)
+	values, err := deleteHoldScript.Run(ctx, s.client.Client, keys, args...).StringSlice()
+	if err != nil {
+		return nil, apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to delete stock hold: %s", key)
+	}
+
+	fields := make(map[string]string, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		fields[values[i]] = values[i+1]
+	}
+	return toStockHold(key, fields)
+}
+
+// HeldQuantities totals the active holds on a product by SKU
+func (s *StockHoldStore) HeldQuantities(ctx context.Context, productID string, skus []string) (map[string]int, error) {
+	if len(skus) == 0 {
+		return map[string]int{}, nil
+	}
+
+	keys := make([]string, 0, 2*len(skus))
+	for _, sku := range skus {
+		suffix := stockHoldItemSuffix(productID, sku)
+		keys = append(keys, stockHoldHeldPrefix+suffix, stockHoldSKUExpiryPrefix+suffix)
+	}
+
+	values, err := heldQuantitiesScript.Run(ctx, s.client.Client, keys, time.Now().UnixMilli()).Int64Slice()
+	if err != nil {
+		return nil, apperrors.Wrapf(err, apperrors.ErrorTypePersistence, "failed to get held stock of product: %s", productID)
+	}
+
+	held := make(map[string]int, len(skus))
+	for i, sku := range skus {
+		held[sku] = int(values[i])
+	}
+	return held, nil
+}
+
+// ListExpired retrieves the holds that expired before the given time, in every tenant
+func (s *StockHoldStore) ListExpired(ctx context.Context, before time.Time, limit int) ([]model.StockHoldRef, error) {
+	ids, err := s.client.Client.ZRangeByScore(ctx, stockHoldExpiryKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(before.UnixMilli(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.ErrorTypePersistence, "failed to list expired stock holds")
+	}
+
+	refs := make([]model.StockHoldRef, len(ids))
+	for i, id := range ids {
+		// Tenant IDs never contain a colon, so the key is everything after the first one
+		refs[i].TenantID, refs[i].Key, _ = strings.Cut(id, ":")
+	}
+	return refs, nil
+}
+
+// stockHoldID identifies a hold across tenants, as each tenant chooses its own keys
+func stockHoldID(tenantID, key string) string {
+	return tenantID + ":" + key
+}
+
+// stockHoldItemSuffix identifies the SKU of a product in stock hold keys.
+// Product IDs never contain a colon, so the SKU is everything after the first one.
+func stockHoldItemSuffix(productID, sku string) string {
+	return productID + ":" + sku
+}
+
+// toStockHold converts the fields of a hold hash to a domain model, nil when the hash is empty
+func toStockHold(key string, fields map[string]string) (*model.StockHold, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	hold := &model.StockHold{Key: key}
+	for field, value := range fields {
+		switch {
+		case field == "expires_at" || field == "created_at":
+			ms, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, apperrors.Wrapf(err, apperrors.ErrorTypeSystem, "invalid stock hold field %s: %s", field, key)
+			}
+			if field == "expires_at" {
+				hold.ExpiresAt = time.UnixMilli(ms)
+			} else {
+				hold.CreatedAt = time.UnixMilli(ms)
+			}
+		case strings.HasPrefix(field, stockHoldItemField):
+			quantity, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, apperrors.Wrapf(err, apperrors.ErrorTypeSystem, "invalid stock hold quantity: %s", key)
+			}
+			productID, sku, _ := strings.Cut(strings.TrimPrefix(field, stockHoldItemField), ":")
+			hold.Items = append(hold.Items, model.StockHoldItem{ProductID: productID, SKU: sku, Quantity: quantity})
+		}
+	}
+
+	sort.Slice(hold.Items, func(i, j int) bool {
+		if hold.Items[i].ProductID != hold.Items[j].ProductID {
+			return hold.Items[i].ProductID < hold.Items[j].ProductID
+		}
+		return hold.Items[i].SKU < hold.Items[j].SKU
+	})
+	return hold, nil
+}
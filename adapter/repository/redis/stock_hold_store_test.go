@@ -0,0 +1,135 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+func TestStockHoldStore(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	store := NewStockHoldStore(client)
+	ctx := context.Background()
+
+	newHold := func(key string, quantity int, ttl time.Duration) *model.StockHold {
+		hold, err := model.NewStockHold(key, []model.StockHoldItem{{ProductID: "p1", SKU: "TSHIRT-M", Quantity: quantity}}, ttl)
+		require.NoError(t, err)
+		return hold
+	}
+
+	t.Run("holds stock while it is available", func(t *testing.T) {
+		require.NoError(t, store.Create(ctx, newHold("cart:1", 3, time.Minute), []int{5}))
+		require.NoError(t, store.Create(ctx, newHold("cart:2", 2, time.Minute), []int{5}))
+
+		err := store.Create(ctx, newHold("cart:3", 1, time.Minute), []int{5})
+		assert.ErrorIs(t, err, model.ErrProductInsufficientStock)
+
+		held, err := store.HeldQuantities(ctx, "p1", []string{"TSHIRT-M", "TSHIRT-L"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"TSHIRT-M": 5, "TSHIRT-L": 0}, held)
+	})
+
+	t.Run("rejects a second active hold for the same key", func(t *testing.T) {
+		err := store.Create(ctx, newHold("cart:1", 1, time.Minute), []int{100})
+		assert.ErrorIs(t, err, model.ErrStockHoldExists)
+	})
+
+	t.Run("deletes a hold and frees its stock", func(t *testing.T) {
+		hold, err := store.Delete(ctx, "cart:1")
+		require.NoError(t, err)
+		require.NotNil(t, hold)
+		assert.Equal(t, []model.StockHoldItem{{ProductID: "p1", SKU: "TSHIRT-M", Quantity: 3}}, hold.Items)
+
+		held, err := store.HeldQuantities(ctx, "p1", []string{"TSHIRT-M"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, held["TSHIRT-M"])
+
+		hold, err = store.Delete(ctx, "cart:1")
+		require.NoError(t, err)
+		assert.Nil(t, hold)
+	})
+
+	t.Run("expired holds stop holding stock", func(t *testing.T) {
+		expired := newHold("cart:4", 2, time.Minute)
+		expired.ExpiresAt = time.Now().Add(-time.Second)
+		require.NoError(t, store.Create(ctx, expired, []int{5}))
+
+		held, err := store.HeldQuantities(ctx, "p1", []string{"TSHIRT-M"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, held["TSHIRT-M"])
+
+		refs, err := store.ListExpired(ctx, time.Now(), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []model.StockHoldRef{{Key: "cart:4"}}, refs)
+
+		hold, err := store.Get(ctx, "cart:4")
+		require.NoError(t, err)
+		require.NotNil(t, hold)
+		assert.True(t, hold.Expired(time.Now()))
+
+		hold, err = store.DeleteExpired(ctx, "cart:4", time.Now())
+		require.NoError(t, err)
+		assert.NotNil(t, hold)
+	})
+
+	t.Run("keeps active holds when deleting expired ones", func(t *testing.T) {
+		hold, err := store.DeleteExpired(ctx, "cart:2", time.Now())
+		require.NoError(t, err)
+		assert.Nil(t, hold)
+
+		hold, err = store.Get(ctx, "cart:2")
+		require.NoError(t, err)
+		assert.NotNil(t, hold)
+	})
+}
+
+func TestStockHoldStoreTenantScoping(t *testing.T) {
+	client := GetRedisClient(t, SetupRedisContainer(t))
+	store := NewStockHoldStore(client)
+	acme := model.ContextWithTenant(context.Background(), "acme")
+	globex := model.ContextWithTenant(context.Background(), "globex")
+
+	newHold := func(quantity int, ttl time.Duration) *model.StockHold {
+		hold, err := model.NewStockHold("cart:1", []model.StockHoldItem{{ProductID: "p1", Quantity: quantity}}, ttl)
+		require.NoError(t, err)
+		return hold
+	}
+
+	require.NoError(t, store.Create(acme, newHold(2, time.Minute), []int{10}))
+
+	// Another tenant can neither see nor release the hold, and can take the same key
+	hold, err := store.Get(globex, "cart:1")
+	require.NoError(t, err)
+	assert.Nil(t, hold)
+
+	hold, err = store.Delete(globex, "cart:1")
+	require.NoError(t, err)
+	assert.Nil(t, hold)
+
+	expired := newHold(3, time.Minute)
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	require.NoError(t, store.Create(globex, expired, []int{10}))
+
+	hold, err = store.Get(acme, "cart:1")
+	require.NoError(t, err)
+	require.NotNil(t, hold)
+	assert.Equal(t, 2, hold.Items[0].Quantity)
+
+	// Expired holds are listed with their tenant and deleted in it
+	refs, err := store.ListExpired(model.ContextWithoutTenant(context.Background()), time.Now(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []model.StockHoldRef{{TenantID: "globex", Key: "cart:1"}}, refs)
+
+	hold, err = store.DeleteExpired(acme, "cart:1", time.Now())
+	require.NoError(t, err)
+	assert.Nil(t, hold)
+
+	hold, err = store.DeleteExpired(globex, "cart:1", time.Now())
+	require.NoError(t, err)
+	assert.NotNil(t, hold)
+}
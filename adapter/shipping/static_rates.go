@@ -0,0 +1,63 @@
+package shipping
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// StaticRate is a row of the static rate table: a base price per parcel plus a price per item
+type StaticRate struct {
+	Carrier       string
+	Service       string
+	Base          float64
+	PerItem       float64
+	EstimatedDays int
+}
+
+// DefaultStaticRates is the rate table used when no other table is configured
+var DefaultStaticRates = []StaticRate{
+	{Carrier: "correios", Service: "pac", Base: 15.00, PerItem: 2.50, EstimatedDays: 8},
+	{Carrier: "correios", Service: "sedex", Base: 25.00, PerItem: 4.00, EstimatedDays: 3},
+	{Carrier: "jadlog", Service: "package", Base: 18.00, PerItem: 3.00, EstimatedDays: 5},
+	{Carrier: "jadlog", Service: "express", Base: 30.00, PerItem: 5.00, EstimatedDays: 2},
+}
+
+// StaticRateTable is an ICarrierRateProvider backed by a fixed rate table
+type StaticRateTable struct {
+	rates []StaticRate
+}
+
+// NewStaticRateTable creates a rate provider for the given table, falling back to DefaultStaticRates
+func NewStaticRateTable(rates []StaticRate) *StaticRateTable {
+	if len(rates) == 0 {
+		rates = DefaultStaticRates
+	}
+	return &StaticRateTable{rates: rates}
+}
+
+// Quote returns the price of every service in the table for the given number of items, cheapest first
+func (t *StaticRateTable) Quote(ctx context.Context, itemCount int) ([]model.ShippingRate, error) {
+	if itemCount <= 0 {
+		return []model.ShippingRate{}, nil
+	}
+
+	quotes := make([]model.ShippingRate, len(t.rates))
+	for i, rate := range t.rates {
+		amount := rate.Base + rate.PerItem*float64(itemCount)
+		quotes[i] = model.ShippingRate{
+			Carrier:       rate.Carrier,
+			Service:       rate.Service,
+			Amount:        math.Round(amount*100) / 100,
+			EstimatedDays: rate.EstimatedDays,
+		}
+	}
+
+	sort.SliceStable(quotes, func(i, j int) bool {
+		return quotes[i].Amount < quotes[j].Amount
+	})
+
+	return quotes, nil
+}
@@ -0,0 +1,36 @@
+package shipping
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticRateTable_Quote(t *testing.T) {
+	table := NewStaticRateTable([]StaticRate{
+		{Carrier: "fast", Service: "express", Base: 20, PerItem: 5, EstimatedDays: 1},
+		{Carrier: "slow", Service: "ground", Base: 10, PerItem: 1.25, EstimatedDays: 7},
+	})
+
+	rates, err := table.Quote(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Len(t, rates, 2)
+
+	// Cheapest first
+	assert.Equal(t, "slow", rates[0].Carrier)
+	assert.Equal(t, 13.75, rates[0].Amount)
+	assert.Equal(t, "fast", rates[1].Carrier)
+	assert.Equal(t, 35.0, rates[1].Amount)
+
+	// Nothing to ship
+	rates, err = table.Quote(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Empty(t, rates)
+}
+
+func TestNewStaticRateTable_Default(t *testing.T) {
+	rates, err := NewStaticRateTable(nil).Quote(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, rates, len(DefaultStaticRates))
+}
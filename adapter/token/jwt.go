@@ -0,0 +1,95 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// MinSecretLength is the minimum length of an HMAC signing secret
+const MinSecretLength = 32
+
+// jwtClaims is the JWT representation of model.TokenClaims
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Type        model.TokenType    `json:"typ"`
+	TenantID    string             `json:"tid,omitempty"`
+	Email       string             `json:"email,omitempty"`
+	SessionID   string             `json:"sid"`
+	Roles       []model.Role       `json:"roles,omitempty"`
+	Permissions []model.Permission `json:"perms,omitempty"`
+}
+
+// JWTSigner signs tokens as HS256 JWTs
+type JWTSigner struct {
+	secret []byte
+	issuer string
+}
+
+// NewJWTSigner creates a JWT signer, the secret must have at least MinSecretLength bytes
+func NewJWTSigner(secret, issuer string) (*JWTSigner, error) {
+	if len(secret) < MinSecretLength {
+		return nil, fmt.Errorf("JWT secret must have at least %d bytes", MinSecretLength)
+	}
+	return &JWTSigner{secret: []byte(secret), issuer: issuer}, nil
+}
+
+// Sign returns the signed JWT carrying the claims
+func (s *JWTSigner) Sign(claims *model.TokenClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        claims.ID,
+			Issuer:    s.issuer,
+			Subject:   claims.UserID,
+			IssuedAt:  jwt.NewNumericDate(claims.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(claims.ExpiresAt),
+		},
+		Type:        claims.Type,
+		TenantID:    claims.TenantID,
+		Email:       claims.Email,
+		SessionID:   claims.SessionID,
+		Roles:       claims.Roles,
+		Permissions: claims.Permissions,
+	})
+	return token.SignedString(s.secret)
+}
+
+// Parse verifies the signature, issuer and expiry of a JWT and returns its claims
+func (s *JWTSigner) Parse(token string) (*model.TokenClaims, error) {
+	var claims jwtClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return s.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, model.ErrTokenExpired
+		}
+		return nil, model.ErrTokenInvalid
+	}
+	if claims.ID == "" || claims.Subject == "" || claims.SessionID == "" {
+		return nil, model.ErrTokenInvalid
+	}
+
+	result := &model.TokenClaims{
+		ID:          claims.ID,
+		Type:        claims.Type,
+		TenantID:    claims.TenantID,
+		UserID:      claims.Subject,
+		Email:       claims.Email,
+		SessionID:   claims.SessionID,
+		Roles:       claims.Roles,
+		Permissions: claims.Permissions,
+		ExpiresAt:   claims.ExpiresAt.Time,
+	}
+	if claims.IssuedAt != nil {
+		result.IssuedAt = claims.IssuedAt.Time
+	}
+	return result, nil
+}
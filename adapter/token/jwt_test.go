@@ -0,0 +1,79 @@
+package token
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+const testSecret = "test-secret-with-at-least-32-bytes!"
+
+func TestJWTSigner_SignAndParse(t *testing.T) {
+	signer, err := NewJWTSigner(testSecret, "test")
+	require.NoError(t, err)
+
+	principal := model.Principal{
+		TenantID:    "acme",
+		UserID:      "user-1",
+		Email:       "user@example.com",
+		SessionID:   "session-1",
+		Roles:       []model.Role{model.RoleStaff},
+		Permissions: []model.Permission{model.PermissionAuditRead},
+	}
+	claims := model.NewTokenClaims(model.TokenTypeAccess, principal, time.Minute)
+
+	token, err := signer.Sign(claims)
+	require.NoError(t, err)
+
+	parsed, err := signer.Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, claims.ID, parsed.ID)
+	assert.Equal(t, model.TokenTypeAccess, parsed.Type)
+	assert.Equal(t, principal, *parsed.Principal())
+	assert.WithinDuration(t, claims.ExpiresAt, parsed.ExpiresAt, time.Second)
+}
+
+func TestJWTSigner_Rejects(t *testing.T) {
+	signer, err := NewJWTSigner(testSecret, "test")
+	require.NoError(t, err)
+	principal := model.Principal{UserID: "user-1", SessionID: "session-1"}
+
+	// Expired
+	expired, err := signer.Sign(model.NewTokenClaims(model.TokenTypeAccess, principal, -time.Minute))
+	require.NoError(t, err)
+	_, err = signer.Parse(expired)
+	assert.ErrorIs(t, err, model.ErrTokenExpired)
+
+	valid, err := signer.Sign(model.NewTokenClaims(model.TokenTypeAccess, principal, time.Minute))
+	require.NoError(t, err)
+
+	// Tampered payload
+	parts := strings.Split(valid, ".")
+	tampered := parts[0] + "." + parts[1] + "x." + parts[2]
+	_, err = signer.Parse(tampered)
+	assert.ErrorIs(t, err, model.ErrTokenInvalid)
+
+	// Signed with another secret or for another issuer
+	other, err := NewJWTSigner("another-secret-with-at-least-32-bytes", "test")
+	require.NoError(t, err)
+	_, err = other.Parse(valid)
+	assert.ErrorIs(t, err, model.ErrTokenInvalid)
+
+	otherIssuer, err := NewJWTSigner(testSecret, "other")
+	require.NoError(t, err)
+	_, err = otherIssuer.Parse(valid)
+	assert.ErrorIs(t, err, model.ErrTokenInvalid)
+
+	// Unsigned
+	_, err = signer.Parse(parts[0] + "." + parts[1] + ".")
+	assert.ErrorIs(t, err, model.ErrTokenInvalid)
+
+	// Short secrets are refused
+	_, err = NewJWTSigner("short", "test")
+	assert.Error(t, err)
+}
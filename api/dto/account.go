@@ -0,0 +1,17 @@
+package dto
+
+// VerifyEmailReq represents the request to verify a user's email address
+type VerifyEmailReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// PasswordResetReq represents the request to send a password reset token
+type PasswordResetReq struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ConfirmPasswordResetReq represents the request to choose a new password with a reset token
+type ConfirmPasswordResetReq struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
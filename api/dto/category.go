@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// CreateCategoryReq represents the request to create a category
+type CreateCategoryReq struct {
+	Name     string `json:"name" binding:"required,max=255"`
+	ParentID string `json:"parent_id"` // empty creates a root category
+}
+
+// UpdateCategoryReq represents the request to rename and move a category
+type UpdateCategoryReq struct {
+	Name     string `json:"name" binding:"required,max=255"`
+	ParentID string `json:"parent_id"` // empty moves the category to the root
+}
+
+// AssignCategoriesReq represents the request to set the categories of a product
+type AssignCategoriesReq struct {
+	CategoryIDs []string `json:"category_ids" binding:"dive,required"`
+}
+
+// CategoryResp represents the category response
+type CategoryResp struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	Path      string    `json:"path"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CategoryRefResp represents a category within a breadcrumb
+type CategoryRefResp struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
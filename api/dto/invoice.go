@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// InvoiceResp represents an invoice or credit note response
+type InvoiceResp struct {
+	ID                string            `json:"id"`
+	Number            string            `json:"number"`
+	Kind              string            `json:"kind"`
+	OrderID           string            `json:"order_id"`
+	UserID            string            `json:"user_id"`
+	ReturnID          string            `json:"return_id,omitempty"`
+	OriginalInvoiceID string            `json:"original_invoice_id,omitempty"`
+	Lines             []InvoiceLineResp `json:"lines"`
+	Subtotal          float64           `json:"subtotal"`
+	TaxTotal          float64           `json:"tax_total"`
+	Total             float64           `json:"total"`
+	IssuedAt          time.Time         `json:"issued_at"`
+	CreditNotes       []*InvoiceResp    `json:"credit_notes,omitempty"`
+}
+
+// InvoiceLineResp represents an invoice line in the response
+type InvoiceLineResp struct {
+	ProductID   string  `json:"product_id"`
+	SKU         string  `json:"sku,omitempty"`
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	TaxRate     float64 `json:"tax_rate"`
+	Subtotal    float64 `json:"subtotal"`
+	Tax         float64 `json:"tax"`
+	Total       float64 `json:"total"`
+}
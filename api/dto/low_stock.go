@@ -0,0 +1,21 @@
+package dto
+
+// SetLowStockThresholdReq represents the request to set a product's low-stock threshold
+type SetLowStockThresholdReq struct {
+	Threshold int `json:"threshold" binding:"gte=0"` // zero disables low-stock alerts
+}
+
+// LowStockResp represents a product in the low-stock report
+type LowStockResp struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	Threshold int    `json:"threshold"`
+	// SKUs lists the SKUs at or below the threshold, a single entry without SKU for products without variants
+	SKUs []LowStockSKUResp `json:"skus"`
+}
+
+// LowStockSKUResp represents the stock of a low SKU
+type LowStockSKUResp struct {
+	SKU   string `json:"sku,omitempty"`
+	Stock int    `json:"stock"`
+}
@@ -4,20 +4,25 @@ import "time"
 
 // CreateOrderReq represents the request to create an order
 type CreateOrderReq struct {
-	UserID string         `json:"user_id" binding:"required,uuid"`
-	Items  []OrderItemReq `json:"items" binding:"required,min=1,dive"`
+	UserID            string         `json:"user_id" binding:"required,uuid"`
+	OrganizationID    string         `json:"organization_id" binding:"omitempty,uuid"` // optional, orders on behalf of an organization the user buys for
+	Items             []OrderItemReq `json:"items" binding:"required,min=1,dive"`
+	ShipTo            *LocationReq   `json:"ship_to"`                                      // optional, used to pick the nearest warehouses
+	ShippingAddressID string         `json:"shipping_address_id" binding:"omitempty,uuid"` // optional, defaults to the default shipping address of the user
+	BillingAddressID  string         `json:"billing_address_id" binding:"omitempty,uuid"`  // optional, defaults to the default billing address of the user
 }
 
 // OrderItemReq represents an order item in the request
 type OrderItemReq struct {
 	ProductID string  `json:"product_id" binding:"required"`
+	SKU       string  `json:"sku" binding:"max=100"` // ordered variant, required for products with variants
 	Quantity  int     `json:"quantity" binding:"required,gt=0"`
-	Price     float64 `json:"price" binding:"required,gt=0"`
+	Price     float64 `json:"price" binding:"omitempty,gt=0"` // optional, must match the current price when given
 }
 
 // UpdateOrderStatusReq represents the request to update order status
 type UpdateOrderStatusReq struct {
-	Status string `json:"status" binding:"required,oneof=confirmed shipped delivered canceled"`
+	Status string `json:"status" binding:"required,oneof=confirmed canceled"`
 }
 
 // GetOrderReq represents the request to get an order
@@ -30,21 +35,58 @@ type CancelOrderReq struct {
 	ID string `uri:"id" binding:"required,uuid"`
 }
 
+// ApproveOrderReq represents the request to approve an order awaiting approval
+type ApproveOrderReq struct {
+	ApproverID string `json:"approver_id" binding:"omitempty,uuid"` // used only without authentication, the calling user approves otherwise
+}
+
+// RejectOrderReq represents the request to reject an order awaiting approval
+type RejectOrderReq struct {
+	ApproverID string `json:"approver_id" binding:"omitempty,uuid"` // used only without authentication, the calling user rejects otherwise
+	Reason     string `json:"reason" binding:"max=1000"`
+}
+
 // OrderResp represents the order response
 type OrderResp struct {
-	ID        string          `json:"id"`
-	UserID    string          `json:"user_id"`
-	Items     []OrderItemResp `json:"items"`
-	Total     float64         `json:"total"`
-	Status    string          `json:"status"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	ID              string             `json:"id"`
+	UserID          string             `json:"user_id"`
+	OrganizationID  string             `json:"organization_id,omitempty"`
+	Items           []OrderItemResp    `json:"items"`
+	Total           float64            `json:"total"`
+	Status          string             `json:"status"`
+	ShipTo          *LocationResp      `json:"ship_to,omitempty"`
+	ShippingAddress *AddressResp       `json:"shipping_address,omitempty"` // as it was when the order was placed
+	BillingAddress  *AddressResp       `json:"billing_address,omitempty"`  // as it was when the order was placed
+	Approval        *OrderApprovalResp `json:"approval,omitempty"`         // set for organization orders that needed approval
+	Version         int                `json:"version"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+// OrderApprovalResp represents the approval of an organization order
+type OrderApprovalResp struct {
+	Decision    string     `json:"decision,omitempty"` // approved or rejected, empty while the order awaits approval
+	DecidedBy   string     `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	EscalatedAt *time.Time `json:"escalated_at,omitempty"`
 }
 
 // OrderItemResp represents an order item in the response
 type OrderItemResp struct {
 	ID        string  `json:"id"`
 	ProductID string  `json:"product_id"`
+	SKU       string  `json:"sku,omitempty"`
 	Quantity  int     `json:"quantity"`
 	Price     float64 `json:"price"`
+	// PriceVersion is the version of the product price the item was ordered at
+	PriceVersion int `json:"price_version,omitempty"`
+	// Allocations holds the warehouses the item quantity was reserved from
+	Allocations []AllocationResp `json:"allocations,omitempty"`
+}
+
+// AllocationResp represents the quantity of an order item reserved from a warehouse
+type AllocationResp struct {
+	WarehouseID string `json:"warehouse_id"`
+	Quantity    int    `json:"quantity"`
 }
@@ -0,0 +1,80 @@
+package dto
+
+import "time"
+
+// CreateOrganizationReq represents the request to create an organization
+type CreateOrganizationReq struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	AdminUserID string `json:"admin_user_id" binding:"omitempty,uuid"` // defaults to the calling user
+}
+
+// UpdateOrganizationReq represents the request to rename an organization
+type UpdateOrganizationReq struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+// AddOrganizationMemberReq represents the request to add a member to an organization
+type AddOrganizationMemberReq struct {
+	UserID string `json:"user_id" binding:"required,uuid"`
+	Role   string `json:"role" binding:"required,oneof=buyer approver admin"`
+}
+
+// UpdateOrganizationMemberReq represents the request to change the role of a member
+type UpdateOrganizationMemberReq struct {
+	Role string `json:"role" binding:"required,oneof=buyer approver admin"`
+}
+
+// SetApprovalThresholdReq represents the request to set the order total above which the orders of
+// an organization need approval
+type SetApprovalThresholdReq struct {
+	Threshold *float64 `json:"threshold" binding:"omitempty,gte=0"` // null disables approvals
+}
+
+// SetSpendingLimitReq represents the request to set the order total above which the orders of a
+// member need approval
+type SetSpendingLimitReq struct {
+	Limit *float64 `json:"limit" binding:"omitempty,gte=0"` // null falls back to the approval threshold of the organization
+}
+
+// OrganizationAddressReq represents an address shared by the members of an organization
+type OrganizationAddressReq struct {
+	Label      string `json:"label" binding:"max=255"`
+	Line1      string `json:"line1" binding:"required,max=255"`
+	Line2      string `json:"line2" binding:"max=255"`
+	City       string `json:"city" binding:"required,max=255"`
+	Region     string `json:"region" binding:"max=255"`
+	PostalCode string `json:"postal_code" binding:"required,max=20"`
+	Country    string `json:"country" binding:"required,len=2"`
+}
+
+// OrganizationResp represents the organization response
+type OrganizationResp struct {
+	ID                string                    `json:"id"`
+	Name              string                    `json:"name"`
+	Members           []OrganizationMemberResp  `json:"members"`
+	Addresses         []OrganizationAddressResp `json:"addresses"`
+	ApprovalThreshold *float64                  `json:"approval_threshold,omitempty"`
+	Version           int                       `json:"version"`
+	CreatedAt         time.Time                 `json:"created_at"`
+	UpdatedAt         time.Time                 `json:"updated_at"`
+}
+
+// OrganizationMemberResp represents a member of an organization
+type OrganizationMemberResp struct {
+	UserID        string    `json:"user_id"`
+	Role          string    `json:"role"`
+	JoinedAt      time.Time `json:"joined_at"`
+	SpendingLimit *float64  `json:"spending_limit,omitempty"`
+}
+
+// OrganizationAddressResp represents an address shared by the members of an organization
+type OrganizationAddressResp struct {
+	ID         string `json:"id"`
+	Label      string `json:"label,omitempty"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// RefundPaymentReq represents the request to refund a payment
+type RefundPaymentReq struct {
+	Amount float64 `json:"amount" binding:"gte=0"` // zero refunds the remaining amount
+}
+
+// PaymentResp represents the payment response
+type PaymentResp struct {
+	ID             string    `json:"id"`
+	OrderID        string    `json:"order_id"`
+	Amount         float64   `json:"amount"`
+	RefundedAmount float64   `json:"refunded_amount"`
+	Status         string    `json:"status"`
+	GatewayRef     string    `json:"gateway_ref,omitempty"`
+	FailureReason  string    `json:"failure_reason,omitempty"`
+	Version        int       `json:"version"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
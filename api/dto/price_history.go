@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// SchedulePriceReq represents the request to schedule a price change
+type SchedulePriceReq struct {
+	Price         float64   `json:"price" binding:"required,gt=0"`
+	EffectiveFrom time.Time `json:"effective_from" binding:"required"` // must be in the future
+}
+
+// PriceEntryResp represents a price history entry response
+type PriceEntryResp struct {
+	ID            string     `json:"id"`
+	ProductID     string     `json:"product_id"`
+	SKU           string     `json:"sku,omitempty"`     // variant the price is of
+	Version       int        `json:"version,omitempty"` // zero until a scheduled price is applied
+	Price         float64    `json:"price"`
+	Status        string     `json:"status"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
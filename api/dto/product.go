@@ -19,7 +19,25 @@ type UpdateProductReq struct {
 
 // UpdateStockReq represents the request to update product stock
 type UpdateStockReq struct {
-	Quantity int `json:"quantity" binding:"required"`
+	SKU         string `json:"sku"`          // required for products with variants
+	WarehouseID string `json:"warehouse_id"` // optional, adjusts the stock of that warehouse as well
+	Quantity    int    `json:"quantity" binding:"required"`
+	Reason      string `json:"reason" binding:"omitempty,oneof=manual adjustment"` // defaults to manual
+	ReferenceID string `json:"reference_id" binding:"max=100"`
+}
+
+// CreateVariantReq represents the request to add a variant to a product
+type CreateVariantReq struct {
+	SKU        string            `json:"sku" binding:"required,max=100"`
+	Attributes map[string]string `json:"attributes"`
+	Price      float64           `json:"price" binding:"gte=0"` // zero inherits the product price
+	Stock      int               `json:"stock" binding:"gte=0"`
+}
+
+// UpdateVariantReq represents the request to change a variant's attributes and price override
+type UpdateVariantReq struct {
+	Attributes map[string]string `json:"attributes"`
+	Price      float64           `json:"price" binding:"gte=0"` // zero inherits the product price
 }
 
 // GetProductReq represents the request to get a product
@@ -34,11 +52,45 @@ type DeleteProductReq struct {
 
 // ProductResp represents the product response
 type ProductResp struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Stock       int       `json:"stock"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+	// PriceVersion is incremented on every price change, see the price history
+	PriceVersion int      `json:"price_version"`
+	Stock        int      `json:"stock"`
+	CategoryIDs  []string `json:"category_ids,omitempty"`
+	// Breadcrumbs holds, per assigned category, the path from the root category down to it
+	Breadcrumbs [][]CategoryRefResp `json:"breadcrumbs,omitempty"`
+	Variants    []VariantResp       `json:"variants,omitempty"`
+	// WarehouseStock holds the stock per warehouse ID, empty when the product is not stocked per warehouse
+	WarehouseStock map[string]int `json:"warehouse_stock,omitempty"`
+	// LowStockThreshold is the stock level at or below which a SKU is low, zero when alerts are disabled
+	LowStockThreshold int       `json:"low_stock_threshold"`
+	Version           int       `json:"version"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// VariantResp represents a product variant in the response
+type VariantResp struct {
+	SKU        string            `json:"sku"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Price      float64           `json:"price"` // effective price, the override or the product price
+	Stock      int               `json:"stock"`
+	// WarehouseStock holds the variant stock per warehouse ID
+	WarehouseStock map[string]int `json:"warehouse_stock,omitempty"`
+}
+
+// ProductFacetsResp represents the facet counts of a product search
+type ProductFacetsResp struct {
+	Categories   []FacetCountResp `json:"categories"`
+	PriceRanges  []FacetCountResp `json:"price_ranges"`
+	Availability []FacetCountResp `json:"availability"`
+}
+
+// FacetCountResp represents the number of matching products sharing a facet value
+type FacetCountResp struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
 }
@@ -0,0 +1,23 @@
+package dto
+
+// ProductImportResp represents the report of a product import
+type ProductImportResp struct {
+	ReferenceID string                 `json:"reference_id"`
+	DryRun      bool                   `json:"dry_run"`
+	Total       int                    `json:"total"`
+	Created     int                    `json:"created"`
+	Updated     int                    `json:"updated"`
+	Unchanged   int                    `json:"unchanged"`
+	Failed      int                    `json:"failed"`
+	Rows        []ProductImportRowResp `json:"rows"`
+}
+
+// ProductImportRowResp represents the outcome of an imported row
+type ProductImportRowResp struct {
+	Line      int    `json:"line"`
+	SKU       string `json:"sku,omitempty"`
+	Name      string `json:"name"`
+	Action    string `json:"action"` // created, updated, unchanged or failed
+	ProductID string `json:"product_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+// CreateStockHoldReq represents the request to hold stock for a cart or an order
+type CreateStockHoldReq struct {
+	Key        string             `json:"key" binding:"required,max=100"`
+	Items      []StockHoldItemReq `json:"items" binding:"required,min=1,dive"`
+	TTLSeconds int                `json:"ttl_seconds" binding:"gte=0,lte=86400"` // zero uses inventory.hold_ttl
+}
+
+// StockHoldItemReq represents a held item in the request
+type StockHoldItemReq struct {
+	ProductID string `json:"product_id" binding:"required"`
+	SKU       string `json:"sku" binding:"max=100"` // required for products with variants
+	Quantity  int    `json:"quantity" binding:"required,gt=0"`
+}
+
+// StockHoldResp represents a stock hold in the response
+type StockHoldResp struct {
+	Key       string              `json:"key"`
+	Items     []StockHoldItemResp `json:"items"`
+	ExpiresAt time.Time           `json:"expires_at"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// StockHoldItemResp represents a held item in the response
+type StockHoldItemResp struct {
+	ProductID string `json:"product_id"`
+	SKU       string `json:"sku,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
+
+// StockAvailabilityResp represents the stock of a SKU not set aside by active holds
+type StockAvailabilityResp struct {
+	SKU       string `json:"sku,omitempty"`
+	Stock     int    `json:"stock"`
+	Held      int    `json:"held"`
+	Available int    `json:"available"`
+}
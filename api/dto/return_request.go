@@ -0,0 +1,53 @@
+package dto
+
+import "time"
+
+// CreateReturnReq represents the request to return items of a delivered order
+type CreateReturnReq struct {
+	Items  []ReturnItemReq `json:"items" binding:"required,min=1,dive"`
+	Reason string          `json:"reason" binding:"max=500"`
+}
+
+// ReturnItemReq represents an order item quantity being returned
+type ReturnItemReq struct {
+	OrderItemID string `json:"order_item_id" binding:"required,uuid"`
+	Quantity    int    `json:"quantity" binding:"required,gt=0"`
+}
+
+// RejectReturnReq represents the request to reject a return
+type RejectReturnReq struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// ReturnResp represents the return request response
+type ReturnResp struct {
+	ID           string           `json:"id"`
+	OrderID      string           `json:"order_id"`
+	Items        []ReturnItemResp `json:"items"`
+	Reason       string           `json:"reason,omitempty"`
+	Status       string           `json:"status"`
+	RejectReason string           `json:"reject_reason,omitempty"`
+	RefundAmount float64          `json:"refund_amount"`
+	Version      int              `json:"version"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// ReturnItemResp represents a returned item in the response
+type ReturnItemResp struct {
+	ID          string  `json:"id"`
+	OrderItemID string  `json:"order_item_id"`
+	ProductID   string  `json:"product_id"`
+	Quantity    int     `json:"quantity"`
+	Price       float64 `json:"price"`
+}
+
+// RefundResp represents the refund response
+type RefundResp struct {
+	ID        string    `json:"id"`
+	ReturnID  string    `json:"return_id"`
+	OrderID   string    `json:"order_id"`
+	PaymentID string    `json:"payment_id,omitempty"`
+	Amount    float64   `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import "time"
+
+// CreateShipmentReq represents the request to pack order items into a shipment
+type CreateShipmentReq struct {
+	Carrier        string            `json:"carrier" binding:"required,max=100"`
+	Service        string            `json:"service" binding:"max=100"`
+	TrackingNumber string            `json:"tracking_number" binding:"max=255"`
+	Items          []ShipmentItemReq `json:"items" binding:"required,min=1,dive"`
+}
+
+// ShipmentItemReq represents an order item quantity packed in a shipment
+type ShipmentItemReq struct {
+	OrderItemID string `json:"order_item_id" binding:"required,uuid"`
+	Quantity    int    `json:"quantity" binding:"required,gt=0"`
+}
+
+// ShipShipmentReq represents the request to hand a shipment over to the carrier
+type ShipShipmentReq struct {
+	TrackingNumber string `json:"tracking_number" binding:"max=255"`
+}
+
+// DeliverShipmentReq represents the request to mark shipment items as delivered
+type DeliverShipmentReq struct {
+	OrderItemIDs []string `json:"order_item_ids" binding:"omitempty,dive,uuid"` // empty delivers every item
+}
+
+// ShipmentResp represents the shipment response
+type ShipmentResp struct {
+	ID             string             `json:"id"`
+	OrderID        string             `json:"order_id"`
+	Carrier        string             `json:"carrier"`
+	Service        string             `json:"service,omitempty"`
+	TrackingNumber string             `json:"tracking_number,omitempty"`
+	Cost           float64            `json:"cost"`
+	Status         string             `json:"status"`
+	Items          []ShipmentItemResp `json:"items"`
+	ShippedAt      *time.Time         `json:"shipped_at,omitempty"`
+	DeliveredAt    *time.Time         `json:"delivered_at,omitempty"`
+	Version        int                `json:"version"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+// ShipmentItemResp represents a shipped item in the response
+type ShipmentItemResp struct {
+	ID          string `json:"id"`
+	OrderItemID string `json:"order_item_id"`
+	ProductID   string `json:"product_id"`
+	Quantity    int    `json:"quantity"`
+	Status      string `json:"status"`
+}
+
+// ShippingRateResp represents a carrier rate quote
+type ShippingRateResp struct {
+	Carrier       string  `json:"carrier"`
+	Service       string  `json:"service"`
+	Amount        float64 `json:"amount"`
+	EstimatedDays int     `json:"estimated_days"`
+}
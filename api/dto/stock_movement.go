@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+// StockMovementResp represents a stock ledger entry response
+type StockMovementResp struct {
+	ID          string    `json:"id"`
+	ProductID   string    `json:"product_id"`
+	SKU         string    `json:"sku,omitempty"`
+	Quantity    int       `json:"quantity"`
+	Reason      string    `json:"reason"`
+	ReferenceID string    `json:"reference_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -14,6 +14,18 @@ type UpdateUserReq struct {
 	Name string `json:"name" binding:"required"`
 }
 
+// ChangePasswordReq represents the request to change a user's password
+type ChangePasswordReq struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// AssignRolesReq represents the request to replace a user's roles and directly granted permissions
+type AssignRolesReq struct {
+	Roles       []string `json:"roles" binding:"required,min=1"`
+	Permissions []string `json:"permissions"`
+}
+
 // GetUserReq represents the request to get a user
 type GetUserReq struct {
 	ID string `uri:"id" binding:"required,uuid"`
@@ -26,9 +38,14 @@ type DeleteUserReq struct {
 
 // UserResp represents the user response
 type UserResp struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              string     `json:"id"`
+	Email           string     `json:"email"`
+	Name            string     `json:"name"`
+	Roles           []string   `json:"roles"`
+	Permissions     []string   `json:"permissions,omitempty"`
+	EmailVerified   bool       `json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	Version         int        `json:"version"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
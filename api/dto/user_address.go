@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+// UserAddressReq represents an address in the address book of a user
+type UserAddressReq struct {
+	Label           string `json:"label" binding:"max=255"`
+	Line1           string `json:"line1" binding:"required,max=255"`
+	Line2           string `json:"line2" binding:"max=255"`
+	City            string `json:"city" binding:"required,max=255"`
+	Region          string `json:"region" binding:"max=255"`
+	PostalCode      string `json:"postal_code" binding:"required,max=20"`
+	Country         string `json:"country" binding:"required,len=2"`
+	DefaultShipping bool   `json:"default_shipping"`
+	DefaultBilling  bool   `json:"default_billing"`
+}
+
+// UserAddressResp represents an address in the address book of a user
+type UserAddressResp struct {
+	ID              string    `json:"id"`
+	UserID          string    `json:"user_id"`
+	Label           string    `json:"label,omitempty"`
+	Line1           string    `json:"line1"`
+	Line2           string    `json:"line2,omitempty"`
+	City            string    `json:"city"`
+	Region          string    `json:"region,omitempty"`
+	PostalCode      string    `json:"postal_code"`
+	Country         string    `json:"country"`
+	DefaultShipping bool      `json:"default_shipping"`
+	DefaultBilling  bool      `json:"default_billing"`
+	Version         int       `json:"version"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// AddressResp represents the address an order was placed with
+type AddressResp struct {
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// LocationReq represents a geographic location in the request
+type LocationReq struct {
+	Latitude  float64 `json:"latitude" binding:"gte=-90,lte=90"`
+	Longitude float64 `json:"longitude" binding:"gte=-180,lte=180"`
+}
+
+// CreateWarehouseReq represents the request to create a warehouse
+type CreateWarehouseReq struct {
+	Code     string      `json:"code" binding:"required,max=50"`
+	Name     string      `json:"name" binding:"required,max=255"`
+	Location LocationReq `json:"location"`
+}
+
+// UpdateWarehouseReq represents the request to update a warehouse
+type UpdateWarehouseReq struct {
+	Name     string      `json:"name" binding:"required,max=255"`
+	Location LocationReq `json:"location"`
+	Active   *bool       `json:"active" binding:"required"`
+}
+
+// LocationResp represents a geographic location in the response
+type LocationResp struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// WarehouseResp represents a warehouse in the response
+type WarehouseResp struct {
+	ID        string       `json:"id"`
+	Code      string       `json:"code"`
+	Name      string       `json:"name"`
+	Location  LocationResp `json:"location"`
+	Active    bool         `json:"active"`
+	Version   int          `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
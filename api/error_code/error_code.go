@@ -29,6 +29,7 @@ const (
 	UnauthorizedTokenErrorCode         = 20002
 	UnauthorizedTokenTimeoutErrorCode  = 20003
 	UnauthorizedTokenGenerateErrorCode = 20004
+	ForbiddenErrorCode                 = 20005
 
 	CopyErrorErrorCode = 30001
 	JSONErrorErrorCode = 30002
@@ -52,6 +53,7 @@ var (
 	UnauthorizedTokenError    = NewError(UnauthorizedTokenErrorCode, "unauthorized, token invalid")
 	UnauthorizedTokenTimeout  = NewError(UnauthorizedTokenTimeoutErrorCode, "unauthorized, token timeout")
 	UnauthorizedTokenGenerate = NewError(UnauthorizedTokenGenerateErrorCode, "unauthorized, token generate failed")
+	Forbidden                 = NewError(ForbiddenErrorCode, "forbidden, permission denied")
 )
 
 // Internal error code
@@ -160,6 +162,8 @@ func determineHTTPStatusCode(code int) int {
 		UnauthorizedTokenGenerateErrorCode,
 		UnauthorizedTokenTimeoutErrorCode:
 		return http.StatusUnauthorized
+	case ForbiddenErrorCode:
+		return http.StatusForbidden
 	case TooManyRequestsCode:
 		return http.StatusTooManyRequests
 	default:
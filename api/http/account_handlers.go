@@ -0,0 +1,98 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+)
+
+// Account Handlers
+
+// VerifyUserEmail marks a user's email address as verified with the token sent to it
+func VerifyUserEmail(c *gin.Context) {
+	if !accountAvailable(c) {
+		return
+	}
+
+	var req dto.VerifyEmailReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	user, err := services.AccountService.VerifyEmail(c.Request.Context(), c.Param("id"), req.Token)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, user.Version)
+	handle.Success(c, toUserResp(user))
+}
+
+// SendVerificationEmail sends a new verification token to a user's email address
+func SendVerificationEmail(c *gin.Context) {
+	if !accountAvailable(c) {
+		return
+	}
+
+	if err := services.AccountService.SendVerification(c.Request.Context(), c.Param("id")); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "verification email sent"})
+}
+
+// RequestPasswordReset sends a password reset token to the email address. The response is the
+// same whether an account uses the address or not.
+func RequestPasswordReset(c *gin.Context) {
+	if !accountAvailable(c) {
+		return
+	}
+
+	var req dto.PasswordResetReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	if err := services.AccountService.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "if an account uses this email, a password reset token was sent to it"})
+}
+
+// ConfirmPasswordReset replaces a forgotten password using the token sent by RequestPasswordReset
+func ConfirmPasswordReset(c *gin.Context) {
+	if !accountAvailable(c) {
+		return
+	}
+
+	var req dto.ConfirmPasswordResetReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	if err := services.AccountService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "password reset"})
+}
+
+// accountAvailable responds 503 when email verification and password reset are not configured
+func accountAvailable(c *gin.Context) bool {
+	if services.AccountService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Account service not available. PostgreSQL may not be configured."})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// API Key Handlers
+
+// CreateAPIKey creates an API key and returns its plaintext key, shown this once only
+func CreateAPIKey(c *gin.Context) {
+	if !apiKeysAvailable(c) {
+		return
+	}
+
+	var req dto.CreateAPIKeyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	scopes := make([]model.Permission, len(req.Scopes))
+	for i, scope := range req.Scopes {
+		scopes[i] = model.Permission(scope)
+	}
+
+	key, plaintext, err := services.APIKeyService.Create(c.Request.Context(), req.Name, scopes, req.ExpiresAt)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, key.Version)
+	handle.Success(c, toAPIKeyResp(key, plaintext))
+}
+
+// GetAPIKey retrieves an API key by ID
+func GetAPIKey(c *gin.Context) {
+	if !apiKeysAvailable(c) {
+		return
+	}
+
+	key, err := services.APIKeyService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+	if key == nil {
+		handle.Error(c, model.ErrAPIKeyNotFound)
+		return
+	}
+
+	setETag(c, key.Version)
+	handle.Success(c, toAPIKeyResp(key, ""))
+}
+
+// ListAPIKeys lists API keys with pagination
+func ListAPIKeys(c *gin.Context) {
+	if !apiKeysAvailable(c) {
+		return
+	}
+
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	keys, total, err := services.APIKeyService.List(c.Request.Context(), offset, limit)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]*dto.APIKeyResp, len(keys))
+	for i, key := range keys {
+		resp[i] = toAPIKeyResp(key, "")
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": total,
+	})
+}
+
+// RotateAPIKey replaces the secret of an API key and returns the new plaintext key
+func RotateAPIKey(c *gin.Context) {
+	if !apiKeysAvailable(c) {
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	key, plaintext, err := services.APIKeyService.Rotate(c.Request.Context(), c.Param("id"), expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, key.Version)
+	handle.Success(c, toAPIKeyResp(key, plaintext))
+}
+
+// RevokeAPIKey disables an API key for good
+func RevokeAPIKey(c *gin.Context) {
+	if !apiKeysAvailable(c) {
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	key, err := services.APIKeyService.Revoke(c.Request.Context(), c.Param("id"), expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, key.Version)
+	handle.Success(c, toAPIKeyResp(key, ""))
+}
+
+// apiKeysAvailable responds 503 when API keys are not configured
+func apiKeysAvailable(c *gin.Context) bool {
+	if services.APIKeyService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "API keys not available. Authentication or PostgreSQL may not be configured."})
+		return false
+	}
+	return true
+}
+
+func toAPIKeyResp(key *model.APIKey, plaintext string) *dto.APIKeyResp {
+	return &dto.APIKeyResp{
+		ID:         key.ID,
+		Name:       key.Name,
+		Key:        plaintext,
+		Prefix:     key.Prefix,
+		Scopes:     toStrings(key.Scopes),
+		CreatedBy:  key.CreatedBy,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		Version:    key.Version,
+		CreatedAt:  key.CreatedAt,
+		UpdatedAt:  key.UpdatedAt,
+	}
+}
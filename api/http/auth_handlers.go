@@ -0,0 +1,119 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	httpMiddleware "cactus-golang-hexagonal-microservice-boilerplate/api/http/middleware"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// Auth Handlers
+
+// Login verifies a user's credentials and issues an access and a refresh token
+func Login(c *gin.Context) {
+	if !authAvailable(c) {
+		return
+	}
+
+	var req dto.LoginReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	pair, err := services.AuthService.Login(c.Request.Context(), req.Email, req.Password, c.ClientIP())
+	if err != nil {
+		handle.Error(c, httpMiddleware.TokenError(err))
+		return
+	}
+
+	handle.Success(c, toTokenResp(pair))
+}
+
+// RefreshToken exchanges a refresh token for a new token pair, the refresh token cannot be used again
+func RefreshToken(c *gin.Context) {
+	if !authAvailable(c) {
+		return
+	}
+
+	var req dto.RefreshTokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	pair, err := services.AuthService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		handle.Error(c, httpMiddleware.TokenError(err))
+		return
+	}
+
+	handle.Success(c, toTokenResp(pair))
+}
+
+// Logout revokes the session of a refresh token
+func Logout(c *gin.Context) {
+	if !authAvailable(c) {
+		return
+	}
+
+	var req dto.RefreshTokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	if err := services.AuthService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		handle.Error(c, httpMiddleware.TokenError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
+// UnlockUser lifts the login lockout of a user's account
+func UnlockUser(c *gin.Context) {
+	if !lockoutAvailable(c) {
+		return
+	}
+
+	if err := services.LockoutService.Unlock(c.Request.Context(), c.Param("id")); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user unlocked"})
+}
+
+// lockoutAvailable responds 503 when login lockout is not configured
+func lockoutAvailable(c *gin.Context) bool {
+	if services.LockoutService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Login lockout not available. Authentication or Redis may not be configured."})
+		return false
+	}
+	return true
+}
+
+// authAvailable responds 503 when authentication is not configured
+func authAvailable(c *gin.Context) bool {
+	if services.AuthService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Authentication not available. Redis may not be configured."})
+		return false
+	}
+	return true
+}
+
+func toTokenResp(pair *model.TokenPair) *dto.TokenResp {
+	return &dto.TokenResp{
+		AccessToken:      pair.AccessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(time.Until(pair.AccessExpiresAt).Seconds()),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresIn: int(time.Until(pair.RefreshExpiresAt).Seconds()),
+	}
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+// Category Handlers
+
+// CreateCategory creates a category, under a parent when parent_id is given
+func CreateCategory(c *gin.Context) {
+	var req dto.CreateCategoryReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	category, err := services.CategoryService.Create(c.Request.Context(), req.Name, req.ParentID)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, category.Version)
+	handle.Success(c, toCategoryResp(category))
+}
+
+// ListCategories lists the whole category tree, ordered by path
+func ListCategories(c *gin.Context) {
+	categories, err := services.CategoryService.List(c.Request.Context())
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]*dto.CategoryResp, len(categories))
+	for i, category := range categories {
+		resp[i] = toCategoryResp(category)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": len(resp),
+	})
+}
+
+// GetCategory retrieves a category by ID
+func GetCategory(c *gin.Context) {
+	category, err := services.CategoryService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+	if category == nil {
+		handle.Error(c, model.ErrCategoryNotFound)
+		return
+	}
+
+	setETag(c, category.Version)
+	handle.Success(c, toCategoryResp(category))
+}
+
+// UpdateCategory renames a category and moves it, with its subcategories, under parent_id
+func UpdateCategory(c *gin.Context) {
+	var req dto.UpdateCategoryReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	category, err := services.CategoryService.Update(c.Request.Context(), c.Param("id"), req.Name, req.ParentID, expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, category.Version)
+	handle.Success(c, toCategoryResp(category))
+}
+
+// DeleteCategory deletes a category without subcategories, moving its products to the parent
+func DeleteCategory(c *gin.Context) {
+	if err := services.CategoryService.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "category deleted"})
+}
+
+// AssignProductCategories sets the categories a product belongs to
+func AssignProductCategories(c *gin.Context) {
+	var req dto.AssignCategoriesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	product, err := services.ProductService.AssignCategories(c.Request.Context(), c.Param("id"), req.CategoryIDs, expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	respondProduct(c, product)
+}
+
+// attachBreadcrumbs fills in the category breadcrumbs of product responses.
+// Breadcrumbs are informational, so failing to load them is logged and the responses are left without them.
+func attachBreadcrumbs(ctx context.Context, products ...*dto.ProductResp) {
+	if services.CategoryService == nil {
+		return
+	}
+
+	var categoryIDs []string
+	for _, product := range products {
+		categoryIDs = append(categoryIDs, product.CategoryIDs...)
+	}
+	if len(categoryIDs) == 0 {
+		return
+	}
+
+	breadcrumbs, err := services.CategoryService.Breadcrumbs(ctx, categoryIDs)
+	if err != nil {
+		log.SugaredLogger.Errorf("Failed to load category breadcrumbs: %v", err)
+		return
+	}
+
+	for _, product := range products {
+		for _, categoryID := range product.CategoryIDs {
+			trail, ok := breadcrumbs[categoryID]
+			if !ok {
+				continue
+			}
+			refs := make([]dto.CategoryRefResp, len(trail))
+			for i, category := range trail {
+				refs[i] = dto.CategoryRefResp{ID: category.ID, Name: category.Name}
+			}
+			product.Breadcrumbs = append(product.Breadcrumbs, refs)
+		}
+	}
+}
+
+func toCategoryResp(category *model.Category) *dto.CategoryResp {
+	return &dto.CategoryResp{
+		ID:        category.ID,
+		Name:      category.Name,
+		ParentID:  category.ParentID,
+		Path:      category.Path,
+		Version:   category.Version,
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+	}
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// setETag sets the ETag header from the resource version
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion returns the version required by the If-Match header.
+// Zero means the request has no precondition; a tag that cannot match any version yields model.ErrVersionConflict.
+func ifMatchVersion(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	// If-Match uses strong comparison, so weak tags never match
+	tag, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, model.ErrVersionConflict
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, model.ErrVersionConflict
+	}
+
+	return version, nil
+}
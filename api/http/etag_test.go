@@ -0,0 +1,58 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+func TestSetETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	setETag(c, 7)
+
+	assert.Equal(t, `"7"`, w.Header().Get("ETag"))
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		header  string
+		want    int
+		wantErr error
+	}{
+		{name: "no header", header: "", want: 0},
+		{name: "wildcard", header: "*", want: 0},
+		{name: "strong tag", header: `"3"`, want: 3},
+		{name: "surrounding spaces", header: ` "12" `, want: 12},
+		{name: "weak tag", header: `W/"3"`, wantErr: model.ErrVersionConflict},
+		{name: "unquoted tag", header: "3", wantErr: model.ErrVersionConflict},
+		{name: "not a version", header: `"abc"`, wantErr: model.ErrVersionConflict},
+		{name: "zero version", header: `"0"`, wantErr: model.ErrVersionConflict},
+		{name: "negative version", header: `"-1"`, wantErr: model.ErrVersionConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			got, err := ifMatchVersion(c)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
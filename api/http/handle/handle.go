@@ -1,6 +1,8 @@
 package handle
 
 import (
+	stderrors "errors"
+	"math"
 	"net/http"
 	"reflect"
 	"strconv"
@@ -11,6 +13,8 @@ import (
 	"cactus-golang-hexagonal-microservice-boilerplate/api/error_code"
 	"cactus-golang-hexagonal-microservice-boilerplate/api/http/paginate"
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
+	apperrors "cactus-golang-hexagonal-microservice-boilerplate/util/errors"
 	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
 )
 
@@ -84,6 +88,13 @@ func Success(c *gin.Context, data any) {
 
 // Error unified error handling
 func Error(c *gin.Context, err error) {
+	// Tell the client when it may retry, then answer as the domain error
+	var retryErr *model.RetryAfterError
+	if stderrors.As(err, &retryErr) {
+		c.Header("Retry-After", strconv.Itoa(max(1, int(math.Ceil(retryErr.RetryAfter.Seconds())))))
+		err = retryErr.DomainError
+	}
+
 	// Handle domain errors first (most common case for business logic)
 	if domainErr, ok := err.(*model.DomainError); ok {
 		c.JSON(domainErr.HTTPStatus, StandardResponse{
@@ -94,6 +105,17 @@ func Error(c *gin.Context, err error) {
 		return
 	}
 
+	// Handle invalid value objects like domain validation errors
+	var validationErr *vo.ValidationError
+	if stderrors.As(err, &validationErr) {
+		c.JSON(http.StatusBadRequest, StandardResponse{
+			Code:    0,
+			Message: validationErr.Message,
+			Data:    gin.H{"error_code": model.CodeValidationError},
+		})
+		return
+	}
+
 	// Handle API error codes
 	if apiErr, ok := err.(*error_code.Error); ok {
 		c.JSON(apiErr.StatusCode(), StandardResponse{
@@ -105,6 +127,16 @@ func Error(c *gin.Context, err error) {
 		return
 	}
 
+	// Handle permission denials of util/errors
+	var forbidden *apperrors.AppError
+	if stderrors.As(err, &forbidden) && forbidden.Type == apperrors.ErrorTypeForbidden {
+		c.JSON(http.StatusForbidden, StandardResponse{
+			Code:    error_code.ForbiddenErrorCode,
+			Message: forbidden.Message,
+		})
+		return
+	}
+
 	// Handle util/errors AppError
 	if appErr, ok := err.(interface {
 		Error() string
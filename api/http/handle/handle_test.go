@@ -5,11 +5,15 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 
 	"cactus-golang-hexagonal-microservice-boilerplate/api/error_code"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
+	apperrors "cactus-golang-hexagonal-microservice-boilerplate/util/errors"
 )
 
 func init() {
@@ -186,10 +190,11 @@ func TestSuccess(t *testing.T) {
 func TestError(t *testing.T) {
 	// Test scenarios
 	testCases := []struct {
-		name           string
-		err            error
-		expectedStatus int
-		expectedBody   string
+		name               string
+		err                error
+		expectedStatus     int
+		expectedBody       string
+		expectedRetryAfter string
 	}{
 		{
 			name:           "API error",
@@ -197,6 +202,32 @@ func TestError(t *testing.T) {
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   `{"code":10002,"message":"record not found","data":{"details":null}}`,
 		},
+		{
+			name:           "Forbidden error",
+			err:            apperrors.NewForbiddenError("permission orders:write required", nil),
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"code":20005,"message":"permission orders:write required"}`,
+		},
+		{
+			name:           "Value object validation error",
+			err:            vo.ErrAddressCountryInvalid,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code":0,"message":"address country must be an ISO 3166-1 alpha-2 code","data":{"error_code":"VALIDATION_ERROR"}}`,
+		},
+		{
+			name:               "Domain error to retry later",
+			err:                model.NewRetryAfterError(model.ErrLoginThrottled, 1500*time.Millisecond),
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedBody:       `{"code":0,"message":"login failed recently, try again later","data":{"error_code":"LOGIN_THROTTLED"}}`,
+			expectedRetryAfter: "2",
+		},
+		{
+			name:               "Domain error to retry within a second",
+			err:                model.NewRetryAfterError(model.ErrAccountLocked, 10*time.Millisecond),
+			expectedStatus:     http.StatusLocked,
+			expectedBody:       `{"code":0,"message":"account is temporarily locked after too many failed logins","data":{"error_code":"ACCOUNT_LOCKED"}}`,
+			expectedRetryAfter: "1",
+		},
 		{
 			name:           "Generic error",
 			err:            assert.AnError,
@@ -217,6 +248,7 @@ func TestError(t *testing.T) {
 			// Verify results
 			assert.Equal(t, tc.expectedStatus, w.Code)
 			assert.JSONEq(t, tc.expectedBody, w.Body.String())
+			assert.Equal(t, tc.expectedRetryAfter, w.Header().Get("Retry-After"))
 		})
 	}
 }
@@ -8,6 +8,7 @@ import (
 
 	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
 	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	httpMiddleware "cactus-golang-hexagonal-microservice-boilerplate/api/http/middleware"
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
 )
 
@@ -27,6 +28,7 @@ func CreateUser(c *gin.Context) {
 		return
 	}
 
+	setETag(c, user.Version)
 	handle.Success(c, toUserResp(user))
 }
 
@@ -44,6 +46,7 @@ func GetUser(c *gin.Context) {
 		return
 	}
 
+	setETag(c, user.Version)
 	handle.Success(c, toUserResp(user))
 }
 
@@ -57,12 +60,80 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
-	user, err := services.UserService.Update(c.Request.Context(), id, req.Name)
+	expectedVersion, err := ifMatchVersion(c)
 	if err != nil {
 		handle.Error(c, err)
 		return
 	}
 
+	user, err := services.UserService.Update(c.Request.Context(), id, req.Name, expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, user.Version)
+	handle.Success(c, toUserResp(user))
+}
+
+// ChangeUserPassword changes a user's password after verifying the current one
+func ChangeUserPassword(c *gin.Context) {
+	id := c.Param("id")
+
+	var req dto.ChangePasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	user, err := services.UserService.ChangePassword(c.Request.Context(), id, req.CurrentPassword, req.NewPassword, expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, user.Version)
+	handle.Success(c, toUserResp(user))
+}
+
+// AssignUserRoles replaces a user's roles and directly granted permissions
+func AssignUserRoles(c *gin.Context) {
+	id := c.Param("id")
+
+	var req dto.AssignRolesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	roles := make([]model.Role, len(req.Roles))
+	for i, role := range req.Roles {
+		roles[i] = model.Role(role)
+	}
+	permissions := make([]model.Permission, len(req.Permissions))
+	for i, permission := range req.Permissions {
+		permissions[i] = model.Permission(permission)
+	}
+
+	user, err := services.UserService.AssignRoles(c.Request.Context(), id, roles, permissions, expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, user.Version)
 	handle.Success(c, toUserResp(user))
 }
 
@@ -140,6 +211,7 @@ func CreateProduct(c *gin.Context) {
 		return
 	}
 
+	setETag(c, product.Version)
 	handle.Success(c, toProductResp(product))
 }
 
@@ -157,7 +229,11 @@ func GetProduct(c *gin.Context) {
 		return
 	}
 
-	handle.Success(c, toProductResp(product))
+	resp := toProductResp(product)
+	attachBreadcrumbs(c.Request.Context(), resp)
+
+	setETag(c, product.Version)
+	handle.Success(c, resp)
 }
 
 // UpdateProduct updates an existing product
@@ -170,13 +246,23 @@ func UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := services.ProductService.Update(c.Request.Context(), id, req.Name, req.Description, req.Price)
+	expectedVersion, err := ifMatchVersion(c)
 	if err != nil {
 		handle.Error(c, err)
 		return
 	}
 
-	handle.Success(c, toProductResp(product))
+	product, err := services.ProductService.Update(c.Request.Context(), id, req.Name, req.Description, req.Price, expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := toProductResp(product)
+	attachBreadcrumbs(c.Request.Context(), resp)
+
+	setETag(c, product.Version)
+	handle.Success(c, resp)
 }
 
 // DeleteProduct deletes a product
@@ -191,12 +277,21 @@ func DeleteProduct(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "product deleted"})
 }
 
-// ListProducts lists all products with pagination
+// ListProducts lists all products with pagination, or those of a category and its descendants
 func ListProducts(c *gin.Context) {
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	products, total, err := services.ProductService.List(c.Request.Context(), offset, limit)
+	var (
+		products []*model.Product
+		total    int64
+		err      error
+	)
+	if category := c.Query("category"); category != "" {
+		products, total, err = services.ProductService.ListByCategory(c.Request.Context(), category, offset, limit)
+	} else {
+		products, total, err = services.ProductService.List(c.Request.Context(), offset, limit)
+	}
 	if err != nil {
 		handle.Error(c, err)
 		return
@@ -206,6 +301,7 @@ func ListProducts(c *gin.Context) {
 	for i, p := range products {
 		resp[i] = toProductResp(p)
 	}
+	attachBreadcrumbs(c.Request.Context(), resp...)
 
 	c.JSON(http.StatusOK, gin.H{
 		"data":  resp,
@@ -223,7 +319,23 @@ func UpdateProductStock(c *gin.Context) {
 		return
 	}
 
-	if err := services.ProductService.UpdateStock(c.Request.Context(), id, req.Quantity); err != nil {
+	change := model.StockChange{
+		SKU:         req.SKU,
+		WarehouseID: req.WarehouseID,
+		Quantity:    req.Quantity,
+		Reason:      model.StockMovementReasonManual,
+		ReferenceID: req.ReferenceID,
+	}
+	if req.Reason != "" {
+		change.Reason = model.StockMovementReason(req.Reason)
+	}
+	// Orders, returns and imports record their own movements, the API only enters manual changes
+	if !change.Reason.IsManual() {
+		handle.Error(c, model.ErrStockReasonNotManual)
+		return
+	}
+
+	if err := services.ProductService.UpdateStock(c.Request.Context(), id, change); err != nil {
 		handle.Error(c, err)
 		return
 	}
@@ -241,21 +353,35 @@ func CreateOrder(c *gin.Context) {
 		return
 	}
 
+	// Customers place orders for themselves only
+	if err := httpMiddleware.CheckAccess(c, req.UserID, model.PermissionOrdersWrite); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
 	items := make([]model.OrderItem, len(req.Items))
 	for i, item := range req.Items {
 		items[i] = model.OrderItem{
 			ProductID: item.ProductID,
+			SKU:       item.SKU,
 			Quantity:  item.Quantity,
 			Price:     item.Price,
 		}
 	}
 
-	order, err := services.OrderService.Create(c.Request.Context(), req.UserID, items)
+	var shipTo *model.Location
+	if req.ShipTo != nil {
+		location := toLocation(*req.ShipTo)
+		shipTo = &location
+	}
+
+	order, err := services.OrderService.Create(c.Request.Context(), req.UserID, req.OrganizationID, items, shipTo, req.ShippingAddressID, req.BillingAddressID)
 	if err != nil {
 		handle.Error(c, err)
 		return
 	}
 
+	setETag(c, order.Version)
 	handle.Success(c, toOrderResp(order))
 }
 
@@ -272,7 +398,12 @@ func GetOrder(c *gin.Context) {
 		handle.Error(c, model.ErrOrderNotFound)
 		return
 	}
+	if err := httpMiddleware.CheckAccess(c, order.UserID, model.PermissionOrdersRead); err != nil {
+		handle.Error(c, err)
+		return
+	}
 
+	setETag(c, order.Version)
 	handle.Success(c, toOrderResp(order))
 }
 
@@ -308,11 +439,19 @@ func UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
-	if err := services.OrderService.UpdateStatus(c.Request.Context(), id, model.OrderStatus(req.Status)); err != nil {
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
 		handle.Error(c, err)
 		return
 	}
 
+	order, err := services.OrderService.UpdateStatus(c.Request.Context(), id, model.OrderStatus(req.Status), expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, order.Version)
 	c.JSON(http.StatusOK, gin.H{"message": "status updated"})
 }
 
@@ -320,6 +459,20 @@ func UpdateOrderStatus(c *gin.Context) {
 func CancelOrder(c *gin.Context) {
 	id := c.Param("id")
 
+	order, err := services.OrderService.Get(c.Request.Context(), id)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+	if order == nil {
+		handle.Error(c, model.ErrOrderNotFound)
+		return
+	}
+	if err := httpMiddleware.CheckAccess(c, order.UserID, model.PermissionOrdersWrite); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
 	if err := services.OrderService.Cancel(c.Request.Context(), id); err != nil {
 		handle.Error(c, err)
 		return
@@ -332,23 +485,43 @@ func CancelOrder(c *gin.Context) {
 
 func toUserResp(u *model.User) *dto.UserResp {
 	return &dto.UserResp{
-		ID:        u.ID,
-		Email:     u.Email,
-		Name:      u.Name,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:              u.ID,
+		Email:           u.Email,
+		Name:            u.Name,
+		Roles:           toStrings(u.Roles),
+		Permissions:     toStrings(u.Permissions),
+		EmailVerified:   u.IsEmailVerified(),
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		Version:         u.Version,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+	}
+}
+
+// toStrings converts a list of names such as roles to strings
+func toStrings[T ~string](values []T) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = string(v)
 	}
+	return result
 }
 
 func toProductResp(p *model.Product) *dto.ProductResp {
 	return &dto.ProductResp{
-		ID:          p.ID,
-		Name:        p.Name,
-		Description: p.Description,
-		Price:       p.Price,
-		Stock:       p.Stock,
-		CreatedAt:   p.CreatedAt,
-		UpdatedAt:   p.UpdatedAt,
+		ID:                p.ID,
+		Name:              p.Name,
+		Description:       p.Description,
+		Price:             p.Price,
+		PriceVersion:      p.PriceVersion,
+		Stock:             p.Stock,
+		CategoryIDs:       p.CategoryIDs,
+		Variants:          toVariantsResp(p),
+		WarehouseStock:    p.WarehouseStock,
+		LowStockThreshold: p.LowStockThreshold,
+		Version:           p.Version,
+		CreatedAt:         p.CreatedAt,
+		UpdatedAt:         p.UpdatedAt,
 	}
 }
 
@@ -356,22 +529,44 @@ func toOrderResp(o *model.Order) *dto.OrderResp {
 	items := make([]dto.OrderItemResp, len(o.Items))
 	for i, item := range o.Items {
 		items[i] = dto.OrderItemResp{
-			ID:        item.ID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
+			ID:           item.ID,
+			ProductID:    item.ProductID,
+			SKU:          item.SKU,
+			Quantity:     item.Quantity,
+			Price:        item.Price,
+			PriceVersion: item.PriceVersion,
+		}
+		for _, a := range item.Allocations {
+			items[i].Allocations = append(items[i].Allocations, dto.AllocationResp{WarehouseID: a.WarehouseID, Quantity: a.Quantity})
 		}
 	}
 
-	return &dto.OrderResp{
-		ID:        o.ID,
-		UserID:    o.UserID,
-		Items:     items,
-		Total:     o.Total,
-		Status:    string(o.Status),
-		CreatedAt: o.CreatedAt,
-		UpdatedAt: o.UpdatedAt,
+	resp := &dto.OrderResp{
+		ID:             o.ID,
+		UserID:         o.UserID,
+		OrganizationID: o.OrganizationID,
+		Items:          items,
+		Total:          o.Total,
+		Status:         string(o.Status),
+		Version:        o.Version,
+		CreatedAt:      o.CreatedAt,
+		UpdatedAt:      o.UpdatedAt,
+	}
+	if o.ShipTo != nil {
+		resp.ShipTo = toLocationResp(*o.ShipTo)
+	}
+	resp.ShippingAddress = toAddressResp(o.ShippingAddress)
+	resp.BillingAddress = toAddressResp(o.BillingAddress)
+	if o.Approval != nil {
+		resp.Approval = &dto.OrderApprovalResp{
+			Decision:    string(o.Approval.Decision),
+			DecidedBy:   o.Approval.DecidedBy,
+			DecidedAt:   o.Approval.DecidedAt,
+			Reason:      o.Approval.Reason,
+			EscalatedAt: o.Approval.EscalatedAt,
+		}
 	}
+	return resp
 }
 
 // Audit Handlers
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/config"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// invoiceTemplate renders an invoice and its credit notes as a printable HTML document
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money":   formatAmount,
+	"percent": func(v float64) string { return formatAmount(v*100) + "%" },
+}).Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<title>{{.Invoice.Number}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border-bottom: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+td.num, th.num { text-align: right; }
+</style>
+</head>
+<body>
+{{define "document"}}
+<h1>{{if eq .Kind "credit_note"}}Nota de crédito{{else}}Fatura{{end}} {{.Number}}</h1>
+<p>{{if .Issuer}}{{.Issuer}}<br>{{end}}Pedido: {{.OrderID}}<br>Emitida em: {{.IssuedAt.Format "2006-01-02"}}</p>
+<table>
+<tr><th>Descrição</th><th class="num">Qtd.</th><th class="num">Preço unit.</th><th class="num">Imposto</th><th class="num">Líquido</th><th class="num">Total</th></tr>
+{{range .Lines}}<tr><td>{{.Description}}{{if .SKU}} ({{.SKU}}){{end}}</td><td class="num">{{.Quantity}}</td><td class="num">{{money .UnitPrice}}</td><td class="num">{{money .Tax}} ({{percent .TaxRate}})</td><td class="num">{{money .Subtotal}}</td><td class="num">{{money .Total}}</td></tr>
+{{end}}<tr><th colspan="3">Total</th><th class="num">{{money .TaxTotal}}</th><th class="num">{{money .Subtotal}}</th><th class="num">{{money .Total}}</th></tr>
+</table>
+{{end}}
+{{template "document" .Invoice}}
+{{range .CreditNotes}}{{template "document" .}}{{end}}
+</body>
+</html>
+`))
+
+// invoiceDocument is a document rendered by invoiceTemplate
+type invoiceDocument struct {
+	*dto.InvoiceResp
+	Issuer string
+}
+
+// Invoice Handlers
+
+// GetOrderInvoice returns the invoice of an order with its credit notes, as JSON or rendered HTML.
+// HTML is served for "?format=html" or when the client prefers text/html.
+// Customers read the invoices of their own orders only.
+func GetOrderInvoice(c *gin.Context) {
+	orderID := c.Param("id")
+	if err := checkOrderAccess(c, orderID, model.PermissionOrdersRead); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	invoice, err := services.InvoiceService.GetByOrderID(c.Request.Context(), orderID)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+	if invoice == nil {
+		handle.Error(c, model.ErrInvoiceNotFound)
+		return
+	}
+
+	creditNotes, err := services.InvoiceService.ListCreditNotes(c.Request.Context(), orderID)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := toInvoiceResp(invoice)
+	for _, note := range creditNotes {
+		resp.CreditNotes = append(resp.CreditNotes, toInvoiceResp(note))
+	}
+
+	if c.Query("format") != "html" && c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) != binding.MIMEHTML {
+		handle.Success(c, resp)
+		return
+	}
+
+	var issuer string
+	if config.GlobalConfig != nil && config.GlobalConfig.Invoice != nil {
+		issuer = config.GlobalConfig.Invoice.IssuerName
+	}
+
+	notes := make([]invoiceDocument, len(resp.CreditNotes))
+	for i, note := range resp.CreditNotes {
+		notes[i] = invoiceDocument{InvoiceResp: note, Issuer: issuer}
+	}
+
+	var buf bytes.Buffer
+	if err := invoiceTemplate.Execute(&buf, gin.H{
+		"Invoice":     invoiceDocument{InvoiceResp: resp, Issuer: issuer},
+		"CreditNotes": notes,
+	}); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+func toInvoiceResp(i *model.Invoice) *dto.InvoiceResp {
+	lines := make([]dto.InvoiceLineResp, len(i.Lines))
+	for idx, line := range i.Lines {
+		lines[idx] = dto.InvoiceLineResp{
+			ProductID:   line.ProductID,
+			SKU:         line.SKU,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			TaxRate:     line.TaxRate,
+			Subtotal:    line.Subtotal,
+			Tax:         line.Tax,
+			Total:       line.Total,
+		}
+	}
+
+	return &dto.InvoiceResp{
+		ID:                i.ID,
+		Number:            i.Number,
+		Kind:              string(i.Kind),
+		OrderID:           i.OrderID,
+		UserID:            i.UserID,
+		ReturnID:          i.ReturnID,
+		OriginalInvoiceID: i.OriginalInvoiceID,
+		Lines:             lines,
+		Subtotal:          i.Subtotal,
+		TaxTotal:          i.TaxTotal,
+		Total:             i.Total,
+		IssuedAt:          i.IssuedAt,
+	}
+}
+
+// formatAmount formats a monetary amount with two decimals
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// Low Stock Handlers
+
+// SetProductLowStockThreshold sets the stock level at or below which a product is reported as low
+func SetProductLowStockThreshold(c *gin.Context) {
+	var req dto.SetLowStockThresholdReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	product, err := services.ProductService.SetLowStockThreshold(c.Request.Context(), c.Param("id"), req.Threshold, expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	respondProduct(c, product)
+}
+
+// ListLowStockProducts reports the products with a SKU at or below their low-stock threshold
+func ListLowStockProducts(c *gin.Context) {
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	products, total, err := services.ProductService.ListLowStock(c.Request.Context(), offset, limit)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]*dto.LowStockResp, len(products))
+	for i, p := range products {
+		resp[i] = toLowStockResp(p)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": total,
+	})
+}
+
+func toLowStockResp(p *model.Product) *dto.LowStockResp {
+	resp := &dto.LowStockResp{
+		ProductID: p.ID,
+		Name:      p.Name,
+		Threshold: p.LowStockThreshold,
+		SKUs:      []dto.LowStockSKUResp{},
+	}
+	for sku, stock := range p.LowStockLevels() {
+		resp.SKUs = append(resp.SKUs, dto.LowStockSKUResp{SKU: sku, Stock: stock})
+	}
+	sort.Slice(resp.SKUs, func(i, j int) bool { return resp.SKUs[i].SKU < resp.SKUs[j].SKU })
+	return resp
+}
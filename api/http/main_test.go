@@ -25,10 +25,10 @@ func TestMain(m *testing.M) {
 	config.Init("../../config", "config")
 	log.Init()
 
-	// Skip integration tests in short mode
+	// Run only the unit tests in short mode, integration tests skip themselves
 	if testing.Short() {
 		fmt.Println("Skipping integration tests in short mode")
-		os.Exit(0)
+		os.Exit(m.Run())
 		return
 	}
 
@@ -52,7 +52,7 @@ func TestMain(m *testing.M) {
 	repository.Clients = clients
 
 	// Initialize services using dependency injection
-	svcs, err := dependency.InitializeServices(ctx, clients, nil, dependency.WithUserService())
+	svcs, err := dependency.InitializeServices(ctx, clients, nil, dependency.WithUserService(), dependency.WithAuthService())
 	if err != nil {
 		log.SugaredLogger.Fatalf("Failed to initialize services: %v", err)
 	}
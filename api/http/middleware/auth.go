@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/error_code"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
+)
+
+const (
+	// AuthorizationHeader is the header carrying the bearer access token
+	AuthorizationHeader = "Authorization"
+	// APIKeyHeader is the header carrying the API key of service-to-service callers
+	APIKeyHeader = "X-API-Key"
+	// PrincipalKey is the gin context key of the authenticated principal
+	PrincipalKey = "principal"
+
+	bearerScheme = "bearer"
+)
+
+// Strategy authenticates requests carrying one kind of credentials
+type Strategy interface {
+	// Credentials returns the credentials of the request, empty when it carries none of this kind
+	Credentials(c *gin.Context) string
+	// Authenticate verifies the credentials and returns their principal
+	Authenticate(ctx context.Context, credentials string) (*model.Principal, error)
+}
+
+// Auth is a middleware that authenticates requests with the first strategy whose credentials they
+// carry. The principal is stored in the gin context and in the request context,
+// see model.PrincipalFromContext, and users and API keys are bound to their tenant, see Tenant. Nil strategies are skipped; without any, requests get a 503.
+func Auth(strategies ...Strategy) gin.HandlerFunc {
+	available := make([]Strategy, 0, len(strategies))
+	for _, strategy := range strategies {
+		if strategy != nil {
+			available = append(available, strategy)
+		}
+	}
+
+	return func(c *gin.Context) {
+		if len(available) == 0 {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Authentication not available. Redis may not be configured."})
+			return
+		}
+
+		for _, strategy := range available {
+			credentials := strategy.Credentials(c)
+			if credentials == "" {
+				continue
+			}
+
+			principal, err := strategy.Authenticate(c.Request.Context(), credentials)
+			if err != nil {
+				handle.Error(c, TokenError(err))
+				c.Abort()
+				return
+			}
+
+			if err := bindTenant(c, principal); err != nil {
+				handle.Error(c, err)
+				c.Abort()
+				return
+			}
+
+			c.Set(PrincipalKey, principal)
+			c.Request = c.Request.WithContext(model.ContextWithPrincipal(c.Request.Context(), principal))
+			c.Next()
+			return
+		}
+
+		handle.Error(c, error_code.UnauthorizedAuthNotExist)
+		c.Abort()
+	}
+}
+
+// bearerStrategy authenticates "Authorization: Bearer <access token>" headers
+type bearerStrategy struct {
+	authService service.IAuthService
+}
+
+// BearerStrategy returns the strategy of access tokens, or nil without an auth service
+func BearerStrategy(authService service.IAuthService) Strategy {
+	if authService == nil {
+		return nil
+	}
+	return bearerStrategy{authService: authService}
+}
+
+func (s bearerStrategy) Credentials(c *gin.Context) string {
+	return bearerToken(c.GetHeader(AuthorizationHeader))
+}
+
+func (s bearerStrategy) Authenticate(ctx context.Context, token string) (*model.Principal, error) {
+	return s.authService.Authenticate(ctx, token)
+}
+
+// apiKeyStrategy authenticates "X-API-Key: <key>" headers
+type apiKeyStrategy struct {
+	apiKeyService service.IAPIKeyService
+}
+
+// APIKeyStrategy returns the strategy of API keys, or nil without an API key service
+func APIKeyStrategy(apiKeyService service.IAPIKeyService) Strategy {
+	if apiKeyService == nil {
+		return nil
+	}
+	return apiKeyStrategy{apiKeyService: apiKeyService}
+}
+
+func (s apiKeyStrategy) Credentials(c *gin.Context) string {
+	return strings.TrimSpace(c.GetHeader(APIKeyHeader))
+}
+
+func (s apiKeyStrategy) Authenticate(ctx context.Context, key string) (*model.Principal, error) {
+	return s.apiKeyService.Authenticate(ctx, key)
+}
+
+// CurrentPrincipal returns the principal of an authenticated request, or nil
+func CurrentPrincipal(c *gin.Context) *model.Principal {
+	principal, _ := c.Get(PrincipalKey)
+	p, _ := principal.(*model.Principal)
+	return p
+}
+
+// TokenError maps token errors to the UnauthorizedToken* API errors, other errors are returned as is
+func TokenError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrTokenExpired):
+		return error_code.UnauthorizedTokenTimeout
+	case errors.Is(err, model.ErrTokenInvalid), errors.Is(err, model.ErrTokenRevoked):
+		return error_code.UnauthorizedTokenError
+	case errors.Is(err, model.ErrTokenGenerate):
+		return error_code.UnauthorizedTokenGenerate
+	}
+	return err
+}
+
+// bearerToken extracts the token of a "Bearer <token>" header value
+func bearerToken(header string) string {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, bearerScheme) {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
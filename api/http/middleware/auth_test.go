@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/error_code"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
+)
+
+// fakeAuthService accepts the tokens "valid", "customer", "staff" and "acme", a user of the tenant
+// acme, and reports "expired" as expired
+type fakeAuthService struct{}
+
+func (fakeAuthService) Login(context.Context, string, string, string) (*model.TokenPair, error) {
+	return nil, nil
+}
+
+func (fakeAuthService) Refresh(context.Context, string) (*model.TokenPair, error) {
+	return nil, nil
+}
+
+func (fakeAuthService) Logout(context.Context, string) error {
+	return nil
+}
+
+func (fakeAuthService) Authenticate(_ context.Context, token string) (*model.Principal, error) {
+	switch token {
+	case "valid":
+		return &model.Principal{UserID: "user-1", SessionID: "session-1"}, nil
+	case "customer":
+		return &model.Principal{UserID: "customer-1", SessionID: "session-2", Roles: []model.Role{model.RoleCustomer}}, nil
+	case "staff":
+		return &model.Principal{UserID: "staff-1", SessionID: "session-3", Roles: []model.Role{model.RoleStaff}}, nil
+	case "acme":
+		return &model.Principal{TenantID: "acme", UserID: "acme-1", SessionID: "session-4"}, nil
+	case "expired":
+		return nil, model.ErrTokenExpired
+	}
+	return nil, model.ErrTokenInvalid
+}
+
+// fakeAPIKeyService accepts the key "ck_valid_key" of the default tenant and "ck_acme_key" of the
+// tenant acme
+type fakeAPIKeyService struct {
+	service.IAPIKeyService
+}
+
+func (fakeAPIKeyService) Authenticate(_ context.Context, key string) (*model.Principal, error) {
+	switch key {
+	case "ck_valid_key":
+		return &model.Principal{TenantID: model.DefaultTenantID, APIKeyID: "key-1", Permissions: []model.Permission{model.PermissionCatalogRead}}, nil
+	case "ck_acme_key":
+		return &model.Principal{TenantID: "acme", APIKeyID: "key-2", Permissions: []model.Permission{model.PermissionCatalogRead}}, nil
+	}
+	return nil, model.ErrAPIKeyInvalid
+}
+
+func TestAuth(t *testing.T) {
+	engine := gin.New()
+	engine.GET("/me", Auth(BearerStrategy(fakeAuthService{})), func(c *gin.Context) {
+		// The principal is available from both contexts
+		assert.Equal(t, CurrentPrincipal(c), model.PrincipalFromContext(c.Request.Context()))
+		c.String(http.StatusOK, CurrentPrincipal(c).UserID)
+	})
+
+	request := func(authorization string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/me", http.NoBody)
+		if authorization != "" {
+			req.Header.Set(AuthorizationHeader, authorization)
+		}
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("Bearer valid")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "user-1", w.Body.String())
+
+	tests := []struct {
+		authorization string
+		code          int
+	}{
+		{"", error_code.UnauthorizedAuthNotExistErrorCode},
+		{"Basic dXNlcjpwYXNz", error_code.UnauthorizedAuthNotExistErrorCode},
+		{"Bearer expired", error_code.UnauthorizedTokenTimeoutErrorCode},
+		{"Bearer tampered", error_code.UnauthorizedTokenErrorCode},
+	}
+	for _, tt := range tests {
+		w := request(tt.authorization)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, tt.authorization)
+		assert.Contains(t, w.Body.String(), `"code":`+strconv.Itoa(tt.code), tt.authorization)
+	}
+}
+
+func TestAuth_APIKey(t *testing.T) {
+	engine := gin.New()
+	engine.GET("/me", Auth(BearerStrategy(fakeAuthService{}), APIKeyStrategy(fakeAPIKeyService{})), func(c *gin.Context) {
+		principal := CurrentPrincipal(c)
+		c.String(http.StatusOK, principal.UserID+principal.APIKeyID)
+	})
+
+	request := func(headers map[string]string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/me", http.NoBody)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(map[string]string{APIKeyHeader: "ck_valid_key"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "key-1", w.Body.String())
+
+	w = request(map[string]string{APIKeyHeader: "ck_other_key"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// A bearer token is tried first
+	w = request(map[string]string{AuthorizationHeader: "Bearer valid", APIKeyHeader: "ck_valid_key"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "user-1", w.Body.String())
+}
+
+func TestAuth_Unavailable(t *testing.T) {
+	engine := gin.New()
+	engine.GET("/me", Auth(BearerStrategy(nil), APIKeyStrategy(nil)), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/me", http.NoBody)
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/error_code"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	apperrors "cactus-golang-hexagonal-microservice-boilerplate/util/errors"
+)
+
+// Rule is the access rule of a route
+type Rule struct {
+	// Permission required by the route, empty allows any authenticated caller
+	Permission model.Permission
+	// OwnerParam names the path parameter holding the ID of the user owning the resource.
+	// That user is allowed without the permission.
+	OwnerParam string
+}
+
+// Policy is the access policy of a route group
+type Policy struct {
+	Read  Rule // GET and HEAD routes
+	Write Rule // routes of the other methods
+	// Routes overrides the rule of single routes, keyed by method and path relative to the group,
+	// e.g. "GET /:id/orders", or "POST /" for the group itself
+	Routes map[string]Rule
+}
+
+// rule returns the rule of a route
+func (p Policy) rule(method, path string) Rule {
+	if rule, ok := p.Routes[method+" "+path]; ok {
+		return rule
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return p.Read
+	}
+	return p.Write
+}
+
+// Authorize is a middleware that enforces the policy of the route group at basePath. It must run
+// after Auth; denials are answered with an apperrors.ErrorTypeForbidden error.
+func Authorize(basePath string, policy Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := CurrentPrincipal(c)
+		if principal == nil {
+			handle.Error(c, error_code.UnauthorizedAuthNotExist)
+			c.Abort()
+			return
+		}
+
+		path := strings.TrimPrefix(c.FullPath(), basePath)
+		if path == "" {
+			path = "/"
+		}
+		rule := policy.rule(c.Request.Method, path)
+
+		ownerID := ""
+		if rule.OwnerParam != "" {
+			ownerID = c.Param(rule.OwnerParam)
+		}
+		if err := allow(principal, ownerID, rule.Permission); err != nil {
+			handle.Error(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CheckAccess allows the authenticated caller when it is the owner of a resource or has the
+// permission. Handlers use it for ownership only known once the resource is loaded. Without
+// authentication there is no caller and access is allowed.
+func CheckAccess(c *gin.Context, ownerID string, permission model.Permission) error {
+	principal := CurrentPrincipal(c)
+	if principal == nil {
+		return nil
+	}
+	return allow(principal, ownerID, permission)
+}
+
+// allow checks the principal against an owner and a permission
+func allow(principal *model.Principal, ownerID string, permission model.Permission) error {
+	if permission == "" || principal.Can(permission) {
+		return nil
+	}
+	if ownerID != "" && ownerID == principal.UserID {
+		return nil
+	}
+	return apperrors.NewForbiddenError(fmt.Sprintf("permission %s required", permission), nil)
+}
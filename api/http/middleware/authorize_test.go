@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+func TestAuthorize(t *testing.T) {
+	engine := gin.New()
+	users := engine.Group("/api/users")
+	users.Use(Auth(BearerStrategy(fakeAuthService{})), Authorize(users.BasePath(), Policy{
+		Read:  Rule{Permission: model.PermissionUsersRead, OwnerParam: "id"},
+		Write: Rule{Permission: model.PermissionUsersWrite, OwnerParam: "id"},
+		Routes: map[string]Rule{
+			"GET /:id/orders": {Permission: model.PermissionOrdersRead, OwnerParam: "id"},
+			"PUT /:id/roles":  {Permission: model.PermissionUsersManage},
+		},
+	}))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	users.GET("", ok)
+	users.GET("/:id/orders", ok)
+	users.PUT("/:id/roles", ok)
+
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		path   string
+		status int
+	}{
+		{"customer lists own orders", "customer", http.MethodGet, "/api/users/customer-1/orders", http.StatusOK},
+		{"customer lists orders of another user", "customer", http.MethodGet, "/api/users/staff-1/orders", http.StatusForbidden},
+		{"customer lists users", "customer", http.MethodGet, "/api/users", http.StatusForbidden},
+		{"customer changes own roles", "customer", http.MethodPut, "/api/users/customer-1/roles", http.StatusForbidden},
+		{"staff lists orders of a user", "staff", http.MethodGet, "/api/users/customer-1/orders", http.StatusOK},
+		{"staff lists users", "staff", http.MethodGet, "/api/users", http.StatusOK},
+		{"staff changes roles", "staff", http.MethodPut, "/api/users/customer-1/roles", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, http.NoBody)
+			req.Header.Set(AuthorizationHeader, "Bearer "+tt.token)
+			engine.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestCheckAccess(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	// Without authentication there is no caller to check
+	assert.NoError(t, CheckAccess(c, "customer-1", model.PermissionOrdersRead))
+
+	c.Set(PrincipalKey, &model.Principal{UserID: "customer-1", Roles: []model.Role{model.RoleCustomer}})
+	assert.NoError(t, CheckAccess(c, "customer-1", model.PermissionOrdersRead))
+	assert.Error(t, CheckAccess(c, "customer-2", model.PermissionOrdersRead))
+	assert.NoError(t, CheckAccess(c, "customer-2", model.PermissionCatalogRead))
+}
@@ -36,7 +36,7 @@ func ErrorHandlerMiddleware() gin.HandlerFunc {
 			case http.StatusUnauthorized:
 				handle.Error(c, error_code.UnauthorizedTokenError)
 			case http.StatusForbidden:
-				handle.Error(c, error_code.UnauthorizedTokenError.WithMessage("Access forbidden"))
+				handle.Error(c, error_code.Forbidden)
 			case http.StatusTooManyRequests:
 				handle.Error(c, error_code.TooManyRequests)
 			default:
@@ -145,7 +145,7 @@ func handleAppError(c *gin.Context, appErr *errors.AppError) {
 	case errors.ErrorTypeUnauthorized:
 		apiErr = error_code.UnauthorizedTokenError.WithMessage("Unauthorized: %s", appErr.Message)
 	case errors.ErrorTypeForbidden:
-		apiErr = error_code.UnauthorizedTokenError.WithMessage("Access forbidden")
+		apiErr = error_code.Forbidden.WithMessage("Access forbidden: %s", appErr.Message)
 	case errors.ErrorTypeConflict:
 		apiErr = error_code.AccountExist.WithMessage("Conflict: %s", appErr.Message)
 	case errors.ErrorTypePersistence, errors.ErrorTypeSystem:
@@ -174,7 +174,7 @@ func handleHTTPStatusError(c *gin.Context) {
 	case http.StatusUnauthorized:
 		handle.Error(c, error_code.UnauthorizedTokenError)
 	case http.StatusForbidden:
-		handle.Error(c, error_code.UnauthorizedTokenError.WithMessage("Access forbidden"))
+		handle.Error(c, error_code.Forbidden)
 	case http.StatusNotFound:
 		handle.Error(c, error_code.NotFound)
 	case http.StatusTooManyRequests:
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+const (
+	// TenantHeader is the default header naming the tenant of a request
+	TenantHeader = "X-Tenant-ID"
+	// TenantKey is the gin context key of the tenant of the request
+	TenantKey = "tenant"
+
+	// tenantExplicitKey marks requests that named their tenant by header or subdomain
+	tenantExplicitKey = "tenant_explicit"
+)
+
+// Tenant is a middleware that resolves the tenant of each request from the header, then from the
+// subdomain of baseDomain, and otherwise uses the default tenant. Auth then binds authenticated
+// users and API keys to their own tenant. The tenant is stored in the gin context and in the
+// request context, see model.TenantFromContext.
+func Tenant(header, baseDomain string) gin.HandlerFunc {
+	if header == "" {
+		header = TenantHeader
+	}
+
+	return func(c *gin.Context) {
+		tenantID, explicit := resolveTenant(c, header, baseDomain)
+		if err := model.ValidateTenantID(tenantID); err != nil {
+			handle.Error(c, err)
+			c.Abort()
+			return
+		}
+
+		setTenant(c, tenantID)
+		c.Set(tenantExplicitKey, explicit)
+		c.Next()
+	}
+}
+
+// CurrentTenant returns the tenant of the request, empty when Tenant did not run
+func CurrentTenant(c *gin.Context) string {
+	return c.GetString(TenantKey)
+}
+
+// resolveTenant returns the tenant named by the request and whether it named one at all
+func resolveTenant(c *gin.Context, header, baseDomain string) (string, bool) {
+	if tenantID := strings.ToLower(strings.TrimSpace(c.GetHeader(header))); tenantID != "" {
+		return tenantID, true
+	}
+	if tenantID := subdomainTenant(c.Request.Host, baseDomain); tenantID != "" {
+		return tenantID, true
+	}
+	return model.DefaultTenantID, false
+}
+
+// subdomainTenant returns the first label of hosts directly under baseDomain, such as "acme" for
+// "acme.shop.example.com" under "shop.example.com"
+func subdomainTenant(host, baseDomain string) string {
+	if baseDomain == "" {
+		return ""
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	tenantID, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(baseDomain))
+	if !ok || strings.Contains(tenantID, ".") {
+		return ""
+	}
+	return tenantID
+}
+
+// bindTenant scopes the request to the tenant of the principal, the default tenant for principals
+// issued without one. A user or API key whose request explicitly named another tenant gets
+// model.ErrTenantMismatch.
+func bindTenant(c *gin.Context, principal *model.Principal) error {
+	tenantID := principal.TenantID
+	if tenantID == "" {
+		tenantID = model.DefaultTenantID
+	}
+	if tenantID == CurrentTenant(c) {
+		return nil
+	}
+	if c.GetBool(tenantExplicitKey) {
+		return model.ErrTenantMismatch
+	}
+
+	setTenant(c, tenantID)
+	return nil
+}
+
+func setTenant(c *gin.Context, tenantID string) {
+	c.Set(TenantKey, tenantID)
+	c.Request = c.Request.WithContext(model.ContextWithTenant(c.Request.Context(), tenantID))
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+func TestTenant(t *testing.T) {
+	engine := gin.New()
+	engine.Use(Tenant("", "shop.example.com"))
+	engine.GET("/tenant", func(c *gin.Context) {
+		// The tenant is available from both contexts
+		assert.Equal(t, CurrentTenant(c), model.TenantFromContext(c.Request.Context()))
+		c.String(http.StatusOK, CurrentTenant(c))
+	})
+
+	request := func(host, tenant string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "http://"+host+"/tenant", http.NoBody)
+		if tenant != "" {
+			req.Header.Set(TenantHeader, tenant)
+		}
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name   string
+		host   string
+		header string
+		status int
+		tenant string
+	}{
+		{name: "header", host: "localhost:8080", header: "Acme", status: http.StatusOK, tenant: "acme"},
+		{name: "header over subdomain", host: "globex.shop.example.com", header: "acme", status: http.StatusOK, tenant: "acme"},
+		{name: "subdomain", host: "globex.shop.example.com:443", status: http.StatusOK, tenant: "globex"},
+		{name: "nested subdomain", host: "a.globex.shop.example.com", status: http.StatusOK, tenant: model.DefaultTenantID},
+		{name: "other domain", host: "globex.example.org", status: http.StatusOK, tenant: model.DefaultTenantID},
+		{name: "default", host: "localhost", status: http.StatusOK, tenant: model.DefaultTenantID},
+		{name: "invalid", host: "localhost", header: "acme:users", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := request(tt.host, tt.header)
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.Equal(t, tt.tenant, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthBindsTenant(t *testing.T) {
+	engine := gin.New()
+	engine.Use(Tenant("", ""))
+	engine.GET("/tenant", Auth(BearerStrategy(fakeAuthService{}), APIKeyStrategy(fakeAPIKeyService{})), func(c *gin.Context) {
+		assert.Equal(t, CurrentTenant(c), model.TenantFromContext(c.Request.Context()))
+		c.String(http.StatusOK, CurrentTenant(c))
+	})
+
+	request := func(tenant string, headers map[string]string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/tenant", http.NoBody)
+		if tenant != "" {
+			req.Header.Set(TenantHeader, tenant)
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		engine.ServeHTTP(w, req)
+		return w
+	}
+	acmeUser := map[string]string{AuthorizationHeader: "Bearer acme"}
+
+	// Users are scoped to the tenant of their token
+	w := request("", acmeUser)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "acme", w.Body.String())
+
+	w = request("acme", acmeUser)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "acme", w.Body.String())
+
+	// and cannot reach another tenant
+	w = request("globex", acmeUser)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "TENANT_MISMATCH")
+
+	// API keys are scoped to their tenant as well
+	acmeKey := map[string]string{APIKeyHeader: "ck_acme_key"}
+	w = request("", acmeKey)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "acme", w.Body.String())
+
+	w = request("globex", acmeKey)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "TENANT_MISMATCH")
+
+	w = request("globex", map[string]string{APIKeyHeader: "ck_valid_key"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
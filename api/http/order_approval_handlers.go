@@ -0,0 +1,64 @@
+package http
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	httpMiddleware "cactus-golang-hexagonal-microservice-boilerplate/api/http/middleware"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// Order Approval Handlers
+
+// ApproveOrder approves an order awaiting approval on behalf of its organization
+func ApproveOrder(c *gin.Context) {
+	var req dto.ApproveOrderReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		handle.Error(c, err)
+		return
+	}
+
+	decideOrder(c, req.ApproverID, func(approverID string, expectedVersion int) (*model.Order, error) {
+		return services.OrderService.Approve(c.Request.Context(), c.Param("id"), approverID, expectedVersion)
+	})
+}
+
+// RejectOrder rejects an order awaiting approval, which cancels it
+func RejectOrder(c *gin.Context) {
+	var req dto.RejectOrderReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		handle.Error(c, err)
+		return
+	}
+
+	decideOrder(c, req.ApproverID, func(approverID string, expectedVersion int) (*model.Order, error) {
+		return services.OrderService.Reject(c.Request.Context(), c.Param("id"), approverID, req.Reason, expectedVersion)
+	})
+}
+
+// decideOrder runs an approval decision at the If-Match version. The calling user is the approver
+// when authentication is enabled, approverID otherwise.
+func decideOrder(c *gin.Context, approverID string, decide func(approverID string, expectedVersion int) (*model.Order, error)) {
+	if principal := httpMiddleware.CurrentPrincipal(c); principal != nil {
+		approverID = principal.UserID
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	order, err := decide(approverID, expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, order.Version)
+	handle.Success(c, toOrderResp(order))
+}
@@ -0,0 +1,348 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	httpMiddleware "cactus-golang-hexagonal-microservice-boilerplate/api/http/middleware"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
+	apperrors "cactus-golang-hexagonal-microservice-boilerplate/util/errors"
+)
+
+// organizationMemberRoles are the roles of every member of an organization
+var organizationMemberRoles = []model.OrganizationRole{
+	model.OrganizationRoleBuyer,
+	model.OrganizationRoleApprover,
+	model.OrganizationRoleAdmin,
+}
+
+// Organization Handlers
+
+// CreateOrganization creates an organization. Its first admin is the calling user unless a user
+// with the organizations:manage permission names another one.
+func CreateOrganization(c *gin.Context) {
+	if !organizationsAvailable(c) {
+		return
+	}
+
+	var req dto.CreateOrganizationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	adminID := req.AdminUserID
+	if principal := httpMiddleware.CurrentPrincipal(c); adminID == "" && principal != nil {
+		adminID = principal.UserID
+	}
+	if err := httpMiddleware.CheckAccess(c, adminID, model.PermissionOrganizationsManage); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	org, err := services.OrganizationService.Create(c.Request.Context(), req.Name, adminID)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, org.Version)
+	handle.Success(c, toOrganizationResp(org))
+}
+
+// GetOrganization retrieves an organization by ID
+func GetOrganization(c *gin.Context) {
+	org, ok := loadOrganization(c, organizationMemberRoles...)
+	if !ok {
+		return
+	}
+
+	setETag(c, org.Version)
+	handle.Success(c, toOrganizationResp(org))
+}
+
+// ListOrganizations lists organizations with pagination
+func ListOrganizations(c *gin.Context) {
+	if !organizationsAvailable(c) {
+		return
+	}
+
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	orgs, total, err := services.OrganizationService.List(c.Request.Context(), offset, limit)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  toOrganizationsResp(orgs),
+		"total": total,
+	})
+}
+
+// GetUserOrganizations lists the organizations a user is a member of
+func GetUserOrganizations(c *gin.Context) {
+	if !organizationsAvailable(c) {
+		return
+	}
+
+	orgs, err := services.OrganizationService.ListByUserID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  toOrganizationsResp(orgs),
+		"total": len(orgs),
+	})
+}
+
+// UpdateOrganization renames an organization
+func UpdateOrganization(c *gin.Context) {
+	var req dto.UpdateOrganizationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	updateOrganization(c, func(id string, expectedVersion int) (*model.Organization, error) {
+		return services.OrganizationService.Rename(c.Request.Context(), id, req.Name, expectedVersion)
+	})
+}
+
+// DeleteOrganization deletes an organization, its orders are kept
+func DeleteOrganization(c *gin.Context) {
+	if _, ok := loadOrganization(c, model.OrganizationRoleAdmin); !ok {
+		return
+	}
+
+	if err := services.OrganizationService.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "organization deleted"})
+}
+
+// AddOrganizationMember adds a user to an organization
+func AddOrganizationMember(c *gin.Context) {
+	var req dto.AddOrganizationMemberReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	updateOrganization(c, func(id string, expectedVersion int) (*model.Organization, error) {
+		return services.OrganizationService.AddMember(c.Request.Context(), id, req.UserID, model.OrganizationRole(req.Role), expectedVersion)
+	})
+}
+
+// UpdateOrganizationMember changes the role of a member of an organization
+func UpdateOrganizationMember(c *gin.Context) {
+	var req dto.UpdateOrganizationMemberReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	updateOrganization(c, func(id string, expectedVersion int) (*model.Organization, error) {
+		return services.OrganizationService.ChangeMemberRole(c.Request.Context(), id, c.Param("user_id"), model.OrganizationRole(req.Role), expectedVersion)
+	})
+}
+
+// RemoveOrganizationMember removes a member from an organization
+func RemoveOrganizationMember(c *gin.Context) {
+	updateOrganization(c, func(id string, expectedVersion int) (*model.Organization, error) {
+		return services.OrganizationService.RemoveMember(c.Request.Context(), id, c.Param("user_id"), expectedVersion)
+	})
+}
+
+// SetOrganizationApprovalThreshold sets the order total above which the orders of an organization need approval
+func SetOrganizationApprovalThreshold(c *gin.Context) {
+	var req dto.SetApprovalThresholdReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	updateOrganization(c, func(id string, expectedVersion int) (*model.Organization, error) {
+		return services.OrganizationService.SetApprovalThreshold(c.Request.Context(), id, req.Threshold, expectedVersion)
+	})
+}
+
+// SetOrganizationMemberSpendingLimit sets the order total above which the orders of a member need approval
+func SetOrganizationMemberSpendingLimit(c *gin.Context) {
+	var req dto.SetSpendingLimitReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	updateOrganization(c, func(id string, expectedVersion int) (*model.Organization, error) {
+		return services.OrganizationService.SetSpendingLimit(c.Request.Context(), id, c.Param("user_id"), req.Limit, expectedVersion)
+	})
+}
+
+// AddOrganizationAddress adds an address shared by the members of an organization
+func AddOrganizationAddress(c *gin.Context) {
+	var req dto.OrganizationAddressReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	address, err := vo.NewAddress(req.Line1, req.Line2, req.City, req.Region, req.PostalCode, req.Country)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	updateOrganization(c, func(id string, expectedVersion int) (*model.Organization, error) {
+		return services.OrganizationService.AddAddress(c.Request.Context(), id, model.OrganizationAddress{Label: req.Label, Address: address}, expectedVersion)
+	})
+}
+
+// RemoveOrganizationAddress removes a shared address from an organization
+func RemoveOrganizationAddress(c *gin.Context) {
+	updateOrganization(c, func(id string, expectedVersion int) (*model.Organization, error) {
+		return services.OrganizationService.RemoveAddress(c.Request.Context(), id, c.Param("address_id"), expectedVersion)
+	})
+}
+
+// ListOrganizationOrders lists the orders placed on behalf of an organization, visible to its
+// admins and approvers
+func ListOrganizationOrders(c *gin.Context) {
+	if _, ok := loadOrganization(c, model.OrganizationRoleAdmin, model.OrganizationRoleApprover); !ok {
+		return
+	}
+
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	orders, total, err := services.OrganizationService.ListOrders(c.Request.Context(), c.Param("id"), offset, limit)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]*dto.OrderResp, len(orders))
+	for i, o := range orders {
+		resp[i] = toOrderResp(o)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": total,
+	})
+}
+
+// updateOrganization runs a change of the organization, restricted to its admins, at the If-Match version
+func updateOrganization(c *gin.Context, change func(id string, expectedVersion int) (*model.Organization, error)) {
+	if _, ok := loadOrganization(c, model.OrganizationRoleAdmin); !ok {
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	org, err := change(c.Param("id"), expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, org.Version)
+	handle.Success(c, toOrganizationResp(org))
+}
+
+// loadOrganization retrieves the organization of the request and checks that the caller is a
+// member with one of the roles or has the organizations:manage permission. It responds with the
+// error and returns false otherwise.
+func loadOrganization(c *gin.Context, roles ...model.OrganizationRole) (*model.Organization, bool) {
+	if !organizationsAvailable(c) {
+		return nil, false
+	}
+
+	org, err := services.OrganizationService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handle.Error(c, err)
+		return nil, false
+	}
+	if org == nil {
+		handle.Error(c, model.ErrOrganizationNotFound)
+		return nil, false
+	}
+
+	principal := httpMiddleware.CurrentPrincipal(c)
+	if principal != nil && !principal.Can(model.PermissionOrganizationsManage) && !org.HasRole(principal.UserID, roles...) {
+		handle.Error(c, apperrors.NewForbiddenError("organization membership required", nil))
+		return nil, false
+	}
+
+	return org, true
+}
+
+// organizationsAvailable responds 503 when organizations are not configured
+func organizationsAvailable(c *gin.Context) bool {
+	if services.OrganizationService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Organizations not available. PostgreSQL may not be configured."})
+		return false
+	}
+	return true
+}
+
+func toOrganizationsResp(orgs []*model.Organization) []*dto.OrganizationResp {
+	resp := make([]*dto.OrganizationResp, len(orgs))
+	for i, org := range orgs {
+		resp[i] = toOrganizationResp(org)
+	}
+	return resp
+}
+
+func toOrganizationResp(org *model.Organization) *dto.OrganizationResp {
+	members := make([]dto.OrganizationMemberResp, len(org.Members))
+	for i, m := range org.Members {
+		members[i] = dto.OrganizationMemberResp{
+			UserID:        m.UserID,
+			Role:          string(m.Role),
+			JoinedAt:      m.JoinedAt,
+			SpendingLimit: m.SpendingLimit,
+		}
+	}
+
+	addresses := make([]dto.OrganizationAddressResp, len(org.Addresses))
+	for i, a := range org.Addresses {
+		addresses[i] = dto.OrganizationAddressResp{
+			ID:         a.ID,
+			Label:      a.Label,
+			Line1:      a.Line1,
+			Line2:      a.Line2,
+			City:       a.City,
+			Region:     a.Region,
+			PostalCode: a.PostalCode,
+			Country:    a.Country,
+		}
+	}
+
+	return &dto.OrganizationResp{
+		ID:                org.ID,
+		Name:              org.Name,
+		Members:           members,
+		Addresses:         addresses,
+		ApprovalThreshold: org.ApprovalThreshold,
+		Version:           org.Version,
+		CreatedAt:         org.CreatedAt,
+		UpdatedAt:         org.UpdatedAt,
+	}
+}
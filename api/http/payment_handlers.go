@@ -0,0 +1,145 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// PaymentSignatureHeader carries the gateway signature of a payment webhook
+const PaymentSignatureHeader = "X-Payment-Signature"
+
+// Payment Handlers
+
+// AuthorizePayment authorizes a payment for the order total
+func AuthorizePayment(c *gin.Context) {
+	orderID := c.Param("id")
+
+	payment, err := services.PaymentService.Authorize(c.Request.Context(), orderID)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toPaymentResp(payment))
+}
+
+// ListOrderPayments lists the payments of an order
+func ListOrderPayments(c *gin.Context) {
+	orderID := c.Param("id")
+
+	payments, err := services.PaymentService.ListByOrderID(c.Request.Context(), orderID)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]*dto.PaymentResp, len(payments))
+	for i, p := range payments {
+		resp[i] = toPaymentResp(p)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": len(resp),
+	})
+}
+
+// GetPayment retrieves a payment by ID
+func GetPayment(c *gin.Context) {
+	id := c.Param("id")
+
+	payment, err := services.PaymentService.Get(c.Request.Context(), id)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+	if payment == nil {
+		handle.Error(c, model.ErrPaymentNotFound)
+		return
+	}
+
+	handle.Success(c, toPaymentResp(payment))
+}
+
+// CapturePayment captures an authorized payment, confirming its order
+func CapturePayment(c *gin.Context) {
+	id := c.Param("id")
+
+	payment, err := services.PaymentService.Capture(c.Request.Context(), id)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toPaymentResp(payment))
+}
+
+// VoidPayment voids an authorized payment
+func VoidPayment(c *gin.Context) {
+	id := c.Param("id")
+
+	payment, err := services.PaymentService.Void(c.Request.Context(), id)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toPaymentResp(payment))
+}
+
+// RefundPayment refunds part or all of a captured payment
+func RefundPayment(c *gin.Context) {
+	id := c.Param("id")
+
+	var req dto.RefundPaymentReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		handle.Error(c, err)
+		return
+	}
+
+	payment, err := services.PaymentService.Refund(c.Request.Context(), id, req.Amount)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toPaymentResp(payment))
+}
+
+// PaymentWebhook applies a signed payment gateway notification
+func PaymentWebhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		handle.Error(c, model.ErrPaymentWebhookPayloadInvalid)
+		return
+	}
+
+	if err := services.PaymentService.HandleWebhook(c.Request.Context(), payload, c.GetHeader(PaymentSignatureHeader)); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "webhook processed"})
+}
+
+func toPaymentResp(p *model.Payment) *dto.PaymentResp {
+	return &dto.PaymentResp{
+		ID:             p.ID,
+		OrderID:        p.OrderID,
+		Amount:         p.Amount,
+		RefundedAmount: p.RefundedAmount,
+		Status:         string(p.Status),
+		GatewayRef:     p.GatewayRef,
+		FailureReason:  p.FailureReason,
+		Version:        p.Version,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	httpMiddleware "cactus-golang-hexagonal-microservice-boilerplate/api/http/middleware"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// Access policies of the route groups, enforced when authentication is enabled.
+// Routes with an empty rule check access in their handler, see httpMiddleware.CheckAccess.
+
+var userPolicy = httpMiddleware.Policy{
+	Read:  httpMiddleware.Rule{Permission: model.PermissionUsersRead, OwnerParam: "id"},
+	Write: httpMiddleware.Rule{Permission: model.PermissionUsersWrite, OwnerParam: "id"},
+	Routes: map[string]httpMiddleware.Rule{
+		"DELETE /:id":            {Permission: model.PermissionUsersManage, OwnerParam: "id"},
+		"PUT /:id/roles":         {Permission: model.PermissionUsersManage}, // only what the caller holds, never its own, see UserService.AssignRoles
+		"POST /:id/unlock":       {Permission: model.PermissionUsersManage},
+		"GET /:id/orders":        {Permission: model.PermissionOrdersRead, OwnerParam: "id"},
+		"GET /:id/organizations": {Permission: model.PermissionOrganizationsManage, OwnerParam: "id"},
+	},
+}
+
+var organizationPolicy = httpMiddleware.Policy{
+	Read:  httpMiddleware.Rule{Permission: model.PermissionOrganizationsManage},
+	Write: httpMiddleware.Rule{Permission: model.PermissionOrganizationsManage},
+	Routes: map[string]httpMiddleware.Rule{
+		// Any user may open a business account; members act on their own organization
+		// according to their role in it
+		"POST /":                       {},
+		"GET /:id":                     {},
+		"PUT /:id":                     {},
+		"DELETE /:id":                  {},
+		"POST /:id/members":            {},
+		"PUT /:id/members/:user_id":    {},
+		"DELETE /:id/members/:user_id": {},
+		"PUT /:id/members/:user_id/spending-limit": {},
+		"PUT /:id/approval-threshold":              {},
+		"POST /:id/addresses":                      {},
+		"DELETE /:id/addresses/:address_id":        {},
+		"GET /:id/orders":                          {},
+	},
+}
+
+var apiKeyPolicy = httpMiddleware.Policy{
+	Read:  httpMiddleware.Rule{Permission: model.PermissionAPIKeysManage},
+	Write: httpMiddleware.Rule{Permission: model.PermissionAPIKeysManage},
+}
+
+var productPolicy = httpMiddleware.Policy{
+	Read:  httpMiddleware.Rule{Permission: model.PermissionCatalogRead},
+	Write: httpMiddleware.Rule{Permission: model.PermissionCatalogWrite},
+	Routes: map[string]httpMiddleware.Rule{
+		"GET /export":                  {Permission: model.PermissionCatalogWrite},
+		"GET /low-stock":               {Permission: model.PermissionInventoryRead},
+		"PATCH /:id/stock":             {Permission: model.PermissionInventoryWrite},
+		"GET /:id/stock-movements":     {Permission: model.PermissionInventoryRead},
+		"PUT /:id/low-stock-threshold": {Permission: model.PermissionInventoryWrite},
+	},
+}
+
+var categoryPolicy = httpMiddleware.Policy{
+	Read:  httpMiddleware.Rule{Permission: model.PermissionCatalogRead},
+	Write: httpMiddleware.Rule{Permission: model.PermissionCatalogWrite},
+}
+
+var inventoryPolicy = httpMiddleware.Policy{
+	Read:  httpMiddleware.Rule{Permission: model.PermissionInventoryRead},
+	Write: httpMiddleware.Rule{Permission: model.PermissionInventoryWrite},
+}
+
+var orderPolicy = httpMiddleware.Policy{
+	Read:  httpMiddleware.Rule{Permission: model.PermissionOrdersRead},
+	Write: httpMiddleware.Rule{Permission: model.PermissionOrdersWrite},
+	Routes: map[string]httpMiddleware.Rule{
+		// Customers place, view and cancel their own orders, return items of them
+		// and read their invoices
+		"POST /":            {},
+		"GET /:id":          {},
+		"POST /:id/cancel":  {},
+		"POST /:id/returns": {},
+		"GET /:id/returns":  {},
+		"GET /:id/invoice":  {},
+		// Approvers of the organization decide on orders awaiting approval
+		"POST /:id/approve": {},
+		"POST /:id/reject":  {},
+
+		"POST /:id/payments":  {Permission: model.PermissionPaymentsWrite},
+		"GET /:id/payments":   {Permission: model.PermissionPaymentsRead},
+		"GET /:id/refunds":    {Permission: model.PermissionPaymentsRead},
+		"POST /:id/shipments": {Permission: model.PermissionFulfillmentWrite},
+		"GET /:id/shipments":  {Permission: model.PermissionFulfillmentRead},
+	},
+}
+
+var paymentPolicy = httpMiddleware.Policy{
+	Read:  httpMiddleware.Rule{Permission: model.PermissionPaymentsRead},
+	Write: httpMiddleware.Rule{Permission: model.PermissionPaymentsWrite},
+}
+
+var fulfillmentPolicy = httpMiddleware.Policy{
+	Read:  httpMiddleware.Rule{Permission: model.PermissionFulfillmentRead},
+	Write: httpMiddleware.Rule{Permission: model.PermissionFulfillmentWrite},
+}
+
+var auditPolicy = httpMiddleware.Policy{
+	Read:  httpMiddleware.Rule{Permission: model.PermissionAuditRead},
+	Write: httpMiddleware.Rule{Permission: model.PermissionAuditRead},
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// Price History Handlers
+
+// ListPriceHistory lists the price history of a product, scheduled changes included, latest first
+func ListPriceHistory(c *gin.Context) {
+	id := c.Param("id")
+
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	entries, total, err := services.ProductService.ListPriceHistory(c.Request.Context(), id, offset, limit)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]*dto.PriceEntryResp, len(entries))
+	for i, e := range entries {
+		resp[i] = toPriceEntryResp(e)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": total,
+	})
+}
+
+// SchedulePrice schedules a price change of a product, applied by the scheduled price job
+func SchedulePrice(c *gin.Context) {
+	var req dto.SchedulePriceReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	entry, err := services.ProductService.SchedulePrice(c.Request.Context(), c.Param("id"), req.Price, req.EffectiveFrom)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toPriceEntryResp(entry))
+}
+
+func toPriceEntryResp(e *model.PriceEntry) *dto.PriceEntryResp {
+	return &dto.PriceEntryResp{
+		ID:            e.ID,
+		ProductID:     e.ProductID,
+		SKU:           e.SKU,
+		Version:       e.Version,
+		Price:         e.Price,
+		Status:        string(e.Status),
+		EffectiveFrom: e.EffectiveFrom,
+		EffectiveTo:   e.EffectiveTo,
+		CreatedAt:     e.CreatedAt,
+	}
+}
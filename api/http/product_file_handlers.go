@@ -0,0 +1,99 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/catalog"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+// Product Import/Export Handlers
+
+// ImportProducts upserts products from a CSV or NDJSON request body, read row by row
+func ImportProducts(c *gin.Context) {
+	format := productFileFormat(c)
+	reader, err := catalog.NewReader(format, c.Request.Body)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	batchSize, _ := strconv.Atoi(c.DefaultQuery("batch_size", "0"))
+	opts := model.ProductImportOptions{
+		DryRun:      dryRun,
+		BatchSize:   batchSize,
+		ReferenceID: uuid.New().String(),
+	}
+
+	report, err := services.ProductService.Import(c.Request.Context(), reader, opts)
+	if err != nil {
+		if report != nil && report.Total > 0 {
+			log.SugaredLogger.Errorf("Product import %s stopped after %d rows: %v", opts.ReferenceID, report.Total, err)
+		}
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toProductImportResp(report))
+}
+
+// ExportProducts streams the catalog as CSV or NDJSON, one row per variant
+func ExportProducts(c *gin.Context) {
+	format := productFileFormat(c)
+	writer, err := catalog.NewWriter(format, c.Writer)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	c.Header("Content-Type", catalog.ContentType(format))
+	c.Header("Content-Disposition", "attachment; filename=products."+string(format))
+
+	// The status is sent with the first rows, so later errors can only be logged
+	if _, err := services.ProductService.Export(c.Request.Context(), writer); err != nil {
+		log.SugaredLogger.Errorf("Failed to export products: %v", err)
+	}
+}
+
+// productFileFormat returns the format query parameter, falling back to the request content type and then CSV
+func productFileFormat(c *gin.Context) model.ProductFileFormat {
+	if format := c.Query("format"); format != "" {
+		return model.ProductFileFormat(strings.ToLower(format))
+	}
+	if strings.Contains(c.ContentType(), "ndjson") {
+		return model.ProductFileFormatNDJSON
+	}
+	return model.ProductFileFormatCSV
+}
+
+func toProductImportResp(report *model.ProductImportReport) *dto.ProductImportResp {
+	resp := &dto.ProductImportResp{
+		ReferenceID: report.ReferenceID,
+		DryRun:      report.DryRun,
+		Total:       report.Total,
+		Created:     report.Created,
+		Updated:     report.Updated,
+		Unchanged:   report.Unchanged,
+		Failed:      report.Failed,
+		Rows:        make([]dto.ProductImportRowResp, len(report.Rows)),
+	}
+	for i, row := range report.Rows {
+		resp.Rows[i] = dto.ProductImportRowResp{
+			Line:      row.Line,
+			SKU:       row.SKU,
+			Name:      row.Name,
+			Action:    string(row.Action),
+			ProductID: row.ProductID,
+			Error:     row.Error,
+		}
+	}
+	return resp
+}
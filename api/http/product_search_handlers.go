@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// SearchProducts searches products by text, price range, stock and category, returning facet counts
+func SearchProducts(c *gin.Context) {
+	minPrice, err := parsePriceQuery(c, "min_price")
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+	maxPrice, err := parsePriceQuery(c, "max_price")
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+	inStock, _ := strconv.ParseBool(c.DefaultQuery("in_stock", "false"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	criteria := model.ProductSearchCriteria{
+		Query:       c.Query("q"),
+		MinPrice:    minPrice,
+		MaxPrice:    maxPrice,
+		InStockOnly: inStock,
+		Category:    c.Query("category"),
+		Sort:        model.ProductSort(c.Query("sort")),
+		Offset:      offset,
+		Limit:       limit,
+	}
+
+	result, err := services.ProductService.Search(c.Request.Context(), criteria)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]*dto.ProductResp, len(result.Products))
+	for i, p := range result.Products {
+		resp[i] = toProductResp(p)
+	}
+	attachBreadcrumbs(c.Request.Context(), resp...)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   resp,
+		"total":  result.Total,
+		"facets": toProductFacetsResp(result.Facets),
+	})
+}
+
+// parsePriceQuery parses an optional price query parameter, zero when absent
+func parsePriceQuery(c *gin.Context, name string) (float64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, model.ErrProductSearchPriceInvalid
+	}
+	return price, nil
+}
+
+func toProductFacetsResp(f model.ProductFacets) dto.ProductFacetsResp {
+	return dto.ProductFacetsResp{
+		Categories:   toFacetCountsResp(f.Categories),
+		PriceRanges:  toFacetCountsResp(f.PriceRanges),
+		Availability: toFacetCountsResp(f.Availability),
+	}
+}
+
+func toFacetCountsResp(counts []model.FacetCount) []dto.FacetCountResp {
+	resp := make([]dto.FacetCountResp, len(counts))
+	for i, count := range counts {
+		resp[i] = dto.FacetCountResp{Value: count.Value, Count: count.Count}
+	}
+	return resp
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// Reservation Handlers
+
+// CreateStockHold holds stock for a cart or an order until it expires or is released
+func CreateStockHold(c *gin.Context) {
+	if !reservationsAvailable(c) {
+		return
+	}
+
+	var req dto.CreateStockHoldReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	items := make([]model.StockHoldItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = model.StockHoldItem{
+			ProductID: item.ProductID,
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	hold, err := services.ReservationService.Hold(c.Request.Context(), req.Key, items, time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toStockHoldResp(hold))
+}
+
+// GetStockHold retrieves an active stock hold by key
+func GetStockHold(c *gin.Context) {
+	if !reservationsAvailable(c) {
+		return
+	}
+
+	hold, err := services.ReservationService.Get(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+	if hold == nil {
+		handle.Error(c, model.ErrStockHoldNotFound)
+		return
+	}
+
+	handle.Success(c, toStockHoldResp(hold))
+}
+
+// ReleaseStockHold gives up a stock hold, making its stock available again
+func ReleaseStockHold(c *gin.Context) {
+	if !reservationsAvailable(c) {
+		return
+	}
+
+	if err := services.ReservationService.Release(c.Request.Context(), c.Param("key")); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "stock hold released"})
+}
+
+// GetProductAvailability returns, per SKU, the stock not set aside by active holds
+func GetProductAvailability(c *gin.Context) {
+	if !reservationsAvailable(c) {
+		return
+	}
+
+	availability, err := services.ReservationService.Availability(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]dto.StockAvailabilityResp, len(availability))
+	for i, a := range availability {
+		resp[i] = dto.StockAvailabilityResp{
+			SKU:       a.SKU,
+			Stock:     a.Stock,
+			Held:      a.Held,
+			Available: a.Available,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": len(resp),
+	})
+}
+
+// reservationsAvailable responds 503 when stock holds are disabled because Redis is not configured
+func reservationsAvailable(c *gin.Context) bool {
+	if services.ReservationService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Reservation service not available. Redis may not be configured."})
+		return false
+	}
+	return true
+}
+
+func toStockHoldResp(h *model.StockHold) *dto.StockHoldResp {
+	items := make([]dto.StockHoldItemResp, len(h.Items))
+	for i, item := range h.Items {
+		items[i] = dto.StockHoldItemResp{
+			ProductID: item.ProductID,
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	return &dto.StockHoldResp{
+		Key:       h.Key,
+		Items:     items,
+		ExpiresAt: h.ExpiresAt,
+		CreatedAt: h.CreatedAt,
+	}
+}
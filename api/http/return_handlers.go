@@ -0,0 +1,221 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	httpMiddleware "cactus-golang-hexagonal-microservice-boilerplate/api/http/middleware"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// Return Handlers
+
+// CreateReturn opens a return request for items of a delivered order.
+// Customers may return items of their own orders.
+func CreateReturn(c *gin.Context) {
+	orderID := c.Param("id")
+
+	if err := checkOrderAccess(c, orderID, model.PermissionFulfillmentWrite); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	var req dto.CreateReturnReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	items := make([]model.ReturnItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = model.ReturnItem{
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.Quantity,
+		}
+	}
+
+	rma, err := services.ReturnService.Request(c.Request.Context(), orderID, items, req.Reason)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toReturnResp(rma))
+}
+
+// ListOrderReturns lists the return requests of an order.
+// Customers may list the returns of their own orders.
+func ListOrderReturns(c *gin.Context) {
+	orderID := c.Param("id")
+
+	if err := checkOrderAccess(c, orderID, model.PermissionFulfillmentRead); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	returns, err := services.ReturnService.ListByOrderID(c.Request.Context(), orderID)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]*dto.ReturnResp, len(returns))
+	for i, r := range returns {
+		resp[i] = toReturnResp(r)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": len(resp),
+	})
+}
+
+// ListOrderRefunds lists the refunds of an order
+func ListOrderRefunds(c *gin.Context) {
+	orderID := c.Param("id")
+
+	refunds, err := services.ReturnService.ListRefundsByOrderID(c.Request.Context(), orderID)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]*dto.RefundResp, len(refunds))
+	for i, r := range refunds {
+		resp[i] = toRefundResp(r)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": len(resp),
+	})
+}
+
+// GetReturn retrieves a return request by ID
+func GetReturn(c *gin.Context) {
+	id := c.Param("id")
+
+	rma, err := services.ReturnService.Get(c.Request.Context(), id)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+	if rma == nil {
+		handle.Error(c, model.ErrReturnNotFound)
+		return
+	}
+
+	handle.Success(c, toReturnResp(rma))
+}
+
+// ApproveReturn approves a requested return
+func ApproveReturn(c *gin.Context) {
+	id := c.Param("id")
+
+	rma, err := services.ReturnService.Approve(c.Request.Context(), id)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toReturnResp(rma))
+}
+
+// RejectReturn rejects a requested return
+func RejectReturn(c *gin.Context) {
+	id := c.Param("id")
+
+	var req dto.RejectReturnReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		handle.Error(c, err)
+		return
+	}
+
+	rma, err := services.ReturnService.Reject(c.Request.Context(), id, req.Reason)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toReturnResp(rma))
+}
+
+// ReceiveReturn marks the returned goods as received and restocks them
+func ReceiveReturn(c *gin.Context) {
+	id := c.Param("id")
+
+	rma, err := services.ReturnService.Receive(c.Request.Context(), id)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toReturnResp(rma))
+}
+
+// RefundReturn refunds a received return
+func RefundReturn(c *gin.Context) {
+	id := c.Param("id")
+
+	refund, err := services.ReturnService.Refund(c.Request.Context(), id)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toRefundResp(refund))
+}
+
+// checkOrderAccess loads the order and lets its owner, or a principal with the permission, through
+func checkOrderAccess(c *gin.Context, orderID string, permission model.Permission) error {
+	order, err := services.OrderService.Get(c.Request.Context(), orderID)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return model.ErrOrderNotFound
+	}
+	return httpMiddleware.CheckAccess(c, order.UserID, permission)
+}
+
+func toReturnResp(r *model.ReturnRequest) *dto.ReturnResp {
+	items := make([]dto.ReturnItemResp, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = dto.ReturnItemResp{
+			ID:          item.ID,
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+		}
+	}
+
+	return &dto.ReturnResp{
+		ID:           r.ID,
+		OrderID:      r.OrderID,
+		Items:        items,
+		Reason:       r.Reason,
+		Status:       string(r.Status),
+		RejectReason: r.RejectReason,
+		RefundAmount: r.RefundAmount,
+		Version:      r.Version,
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+	}
+}
+
+func toRefundResp(r *model.Refund) *dto.RefundResp {
+	return &dto.RefundResp{
+		ID:        r.ID,
+		ReturnID:  r.ReturnID,
+		OrderID:   r.OrderID,
+		PaymentID: r.PaymentID,
+		Amount:    r.Amount,
+		CreatedAt: r.CreatedAt,
+	}
+}
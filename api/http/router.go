@@ -32,6 +32,8 @@ func NewServerRoute() *gin.Engine {
 
 	router := gin.New()
 
+	trustProxies(router)
+
 	// Register custom validators
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		custom.RegisterValidators(v)
@@ -54,8 +56,8 @@ func NewServerRoute() *gin.Engine {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "X-Tenant-ID", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -66,10 +68,19 @@ func NewServerRoute() *gin.Engine {
 	return router
 }
 
+// trustProxies makes the router believe forwarded headers only from the configured proxies,
+// as the client IP keys login lockouts
+func trustProxies(router *gin.Engine) {
+	if err := router.SetTrustedProxies(config.GlobalConfig.HTTPServer.TrustedProxies); err != nil {
+		panic("invalid http_server.trusted_proxies: " + err.Error())
+	}
+}
+
 // applyMiddleware applies all middleware to the router
 func applyMiddleware(router *gin.Engine) {
 	router.Use(gin.Recovery())
 	router.Use(httpMiddleware.RequestID())
+	router.Use(httpMiddleware.Tenant(tenantResolution()))
 	router.Use(httpMiddleware.Cors())
 	router.Use(httpMiddleware.RequestLogger())
 	router.Use(httpMiddleware.Translations())
@@ -95,35 +106,190 @@ func applyMiddleware(router *gin.Engine) {
 func registerAPIRoutes(router *gin.Engine) {
 	api := router.Group("/api")
 
+	// Auth API
+	auth := api.Group("/auth")
+	auth.POST("/login", Login)
+	auth.POST("/refresh", RefreshToken)
+	auth.POST("/logout", Logout)
+	auth.POST("/password-reset", RequestPasswordReset)
+	auth.POST("/password-reset/confirm", ConfirmPasswordReset)
+
+	// Sign-up, email verification and the payment gateway webhook are public
+	api.POST("/users", CreateUser)
+	api.POST("/users/:id/verify", VerifyUserEmail)
+	api.POST("/payments/webhook", PaymentWebhook)
+
+	// Every other endpoint requires an access token or an API key when authentication is enabled
+	protected := api.Group("")
+	if authEnabled() {
+		protected.Use(httpMiddleware.Auth(
+			httpMiddleware.BearerStrategy(services.AuthService),
+			httpMiddleware.APIKeyStrategy(services.APIKeyService),
+		))
+	}
+
 	// User API
-	users := api.Group("/users")
-	users.POST("", CreateUser)
+	users := protected.Group("/users")
+	authorize(users, userPolicy)
 	users.GET("", ListUsers)
 	users.GET("/:id", GetUser)
 	users.PUT("/:id", UpdateUser)
+	users.PUT("/:id/password", ChangeUserPassword)
 	users.DELETE("/:id", DeleteUser)
+	users.PUT("/:id/roles", AssignUserRoles)
+	users.POST("/:id/unlock", UnlockUser)
+	users.POST("/:id/verification-email", SendVerificationEmail)
 	users.GET("/:id/orders", GetUserOrders)
+	users.GET("/:id/organizations", GetUserOrganizations)
+	users.GET("/:id/addresses", ListUserAddresses)
+	users.POST("/:id/addresses", CreateUserAddress)
+	users.GET("/:id/addresses/:address_id", GetUserAddress)
+	users.PUT("/:id/addresses/:address_id", UpdateUserAddress)
+	users.DELETE("/:id/addresses/:address_id", DeleteUserAddress)
+
+	// API key API
+	apiKeys := protected.Group("/api-keys")
+	authorize(apiKeys, apiKeyPolicy)
+	apiKeys.POST("", CreateAPIKey)
+	apiKeys.GET("", ListAPIKeys)
+	apiKeys.GET("/:id", GetAPIKey)
+	apiKeys.POST("/:id/rotate", RotateAPIKey)
+	apiKeys.DELETE("/:id", RevokeAPIKey)
+
+	// Organization API
+	organizations := protected.Group("/organizations")
+	authorize(organizations, organizationPolicy)
+	organizations.POST("", CreateOrganization)
+	organizations.GET("", ListOrganizations)
+	organizations.GET("/:id", GetOrganization)
+	organizations.PUT("/:id", UpdateOrganization)
+	organizations.DELETE("/:id", DeleteOrganization)
+	organizations.POST("/:id/members", AddOrganizationMember)
+	organizations.PUT("/:id/members/:user_id", UpdateOrganizationMember)
+	organizations.DELETE("/:id/members/:user_id", RemoveOrganizationMember)
+	organizations.PUT("/:id/members/:user_id/spending-limit", SetOrganizationMemberSpendingLimit)
+	organizations.PUT("/:id/approval-threshold", SetOrganizationApprovalThreshold)
+	organizations.POST("/:id/addresses", AddOrganizationAddress)
+	organizations.DELETE("/:id/addresses/:address_id", RemoveOrganizationAddress)
+	organizations.GET("/:id/orders", ListOrganizationOrders)
 
 	// Product API
-	products := api.Group("/products")
+	products := protected.Group("/products")
+	authorize(products, productPolicy)
 	products.POST("", CreateProduct)
 	products.GET("", ListProducts)
+	products.GET("/search", SearchProducts)
+	products.GET("/low-stock", ListLowStockProducts)
+	products.POST("/import", ImportProducts)
+	products.GET("/export", ExportProducts)
 	products.GET("/:id", GetProduct)
 	products.PUT("/:id", UpdateProduct)
 	products.DELETE("/:id", DeleteProduct)
 	products.PATCH("/:id/stock", UpdateProductStock)
+	products.GET("/:id/stock-movements", ListStockMovements)
+	products.GET("/:id/availability", GetProductAvailability)
+	products.PUT("/:id/low-stock-threshold", SetProductLowStockThreshold)
+	products.GET("/:id/price-history", ListPriceHistory)
+	products.POST("/:id/price-schedule", SchedulePrice)
+	products.PUT("/:id/categories", AssignProductCategories)
+	products.GET("/sku/:sku", GetProductBySKU)
+	products.POST("/:id/variants", CreateVariant)
+	products.PUT("/:id/variants/:sku", UpdateVariant)
+	products.DELETE("/:id/variants/:sku", DeleteVariant)
+
+	// Category API
+	categories := protected.Group("/categories")
+	authorize(categories, categoryPolicy)
+	categories.POST("", CreateCategory)
+	categories.GET("", ListCategories)
+	categories.GET("/:id", GetCategory)
+	categories.PUT("/:id", UpdateCategory)
+	categories.DELETE("/:id", DeleteCategory)
+
+	// Warehouse API
+	warehouses := protected.Group("/warehouses")
+	authorize(warehouses, inventoryPolicy)
+	warehouses.POST("", CreateWarehouse)
+	warehouses.GET("", ListWarehouses)
+	warehouses.GET("/:id", GetWarehouse)
+	warehouses.PUT("/:id", UpdateWarehouse)
+
+	// Reservation API
+	reservations := protected.Group("/reservations")
+	authorize(reservations, inventoryPolicy)
+	reservations.POST("", CreateStockHold)
+	reservations.GET("/:key", GetStockHold)
+	reservations.DELETE("/:key", ReleaseStockHold)
 
 	// Order API
-	orders := api.Group("/orders")
+	orders := protected.Group("/orders")
+	authorize(orders, orderPolicy)
 	orders.POST("", CreateOrder)
 	orders.GET("", ListOrders)
 	orders.GET("/:id", GetOrder)
 	orders.PATCH("/:id/status", UpdateOrderStatus)
 	orders.POST("/:id/cancel", CancelOrder)
+	orders.POST("/:id/approve", ApproveOrder)
+	orders.POST("/:id/reject", RejectOrder)
+	orders.POST("/:id/payments", AuthorizePayment)
+	orders.GET("/:id/payments", ListOrderPayments)
+	orders.POST("/:id/returns", CreateReturn)
+	orders.GET("/:id/returns", ListOrderReturns)
+	orders.GET("/:id/refunds", ListOrderRefunds)
+	orders.POST("/:id/shipments", CreateShipment)
+	orders.GET("/:id/shipments", ListOrderShipments)
+	orders.GET("/:id/shipping-rates", QuoteShippingRates)
+	orders.GET("/:id/invoice", GetOrderInvoice)
+
+	// Payment API
+	payments := protected.Group("/payments")
+	authorize(payments, paymentPolicy)
+	payments.GET("/:id", GetPayment)
+	payments.POST("/:id/capture", CapturePayment)
+	payments.POST("/:id/void", VoidPayment)
+	payments.POST("/:id/refund", RefundPayment)
+
+	// Shipment API
+	shipments := protected.Group("/shipments")
+	authorize(shipments, fulfillmentPolicy)
+	shipments.GET("/:id", GetShipment)
+	shipments.POST("/:id/ship", ShipShipment)
+	shipments.POST("/:id/deliver", DeliverShipment)
+
+	// Return API
+	returns := protected.Group("/returns")
+	authorize(returns, fulfillmentPolicy)
+	returns.GET("/:id", GetReturn)
+	returns.POST("/:id/approve", ApproveReturn)
+	returns.POST("/:id/reject", RejectReturn)
+	returns.POST("/:id/receive", ReceiveReturn)
+	returns.POST("/:id/refund", RefundReturn)
 
 	// Audit API
-	audits := api.Group("/audits")
+	audits := protected.Group("/audits")
+	authorize(audits, auditPolicy)
 	audits.GET("", ListAuditLogs)
 	audits.GET("/log/:id", GetAuditLog)
 	audits.GET("/entity/:entity_type/:entity_id", GetEntityAuditLogs)
 }
+
+// authorize enforces the access policy of a route group when authentication is enabled.
+// It must be called before the routes of the group are registered.
+func authorize(group *gin.RouterGroup, policy httpMiddleware.Policy) {
+	if authEnabled() {
+		group.Use(httpMiddleware.Authorize(group.BasePath(), policy))
+	}
+}
+
+// authEnabled reports whether endpoints require an access token
+func authEnabled() bool {
+	return config.GlobalConfig.Auth != nil && config.GlobalConfig.Auth.Enabled
+}
+
+// tenantResolution returns the header and the base domain naming the tenant of requests
+func tenantResolution() (string, string) {
+	if config.GlobalConfig.Tenant == nil {
+		return httpMiddleware.TenantHeader, ""
+	}
+	return config.GlobalConfig.Tenant.Header, config.GlobalConfig.Tenant.BaseDomain
+}
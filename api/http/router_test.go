@@ -0,0 +1,47 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/config"
+)
+
+func TestClientIPTrustedProxies(t *testing.T) {
+	original := config.GlobalConfig.HTTPServer.TrustedProxies
+	defer func() { config.GlobalConfig.HTTPServer.TrustedProxies = original }()
+
+	tests := []struct {
+		name    string
+		proxies []string
+		want    string
+	}{
+		{name: "forwarded header ignored without trusted proxies", want: "10.0.0.1"},
+		{name: "forwarded header used from a trusted proxy", proxies: []string{"10.0.0.0/8"}, want: "203.0.113.7"},
+		{name: "forwarded header ignored from another proxy", proxies: []string{"192.168.0.0/16"}, want: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.GlobalConfig.HTTPServer.TrustedProxies = tt.proxies
+			router := gin.New()
+			trustProxies(router)
+			router.GET("/test/ip", func(c *gin.Context) {
+				c.String(http.StatusOK, c.ClientIP())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test/ip", nil)
+			req.RemoteAddr = "10.0.0.1:4321"
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.want, w.Body.String())
+		})
+	}
+}
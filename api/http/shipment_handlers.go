@@ -0,0 +1,173 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// Shipment Handlers
+
+// CreateShipment packs items of an order into a new shipment
+func CreateShipment(c *gin.Context) {
+	orderID := c.Param("id")
+
+	var req dto.CreateShipmentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	items := make([]model.ShipmentItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = model.ShipmentItem{
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.Quantity,
+		}
+	}
+
+	shipment, err := services.ShipmentService.Create(c.Request.Context(), orderID, req.Carrier, req.Service, req.TrackingNumber, items)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toShipmentResp(shipment))
+}
+
+// ListOrderShipments lists the shipments of an order
+func ListOrderShipments(c *gin.Context) {
+	orderID := c.Param("id")
+
+	shipments, err := services.ShipmentService.ListByOrderID(c.Request.Context(), orderID)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]*dto.ShipmentResp, len(shipments))
+	for i, s := range shipments {
+		resp[i] = toShipmentResp(s)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": len(resp),
+	})
+}
+
+// QuoteShippingRates quotes the carrier rates for the unshipped items of an order
+func QuoteShippingRates(c *gin.Context) {
+	orderID := c.Param("id")
+
+	rates, err := services.ShipmentService.QuoteRates(c.Request.Context(), orderID)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]dto.ShippingRateResp, len(rates))
+	for i, r := range rates {
+		resp[i] = dto.ShippingRateResp{
+			Carrier:       r.Carrier,
+			Service:       r.Service,
+			Amount:        r.Amount,
+			EstimatedDays: r.EstimatedDays,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": len(resp),
+	})
+}
+
+// GetShipment retrieves a shipment by ID
+func GetShipment(c *gin.Context) {
+	id := c.Param("id")
+
+	shipment, err := services.ShipmentService.Get(c.Request.Context(), id)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+	if shipment == nil {
+		handle.Error(c, model.ErrShipmentNotFound)
+		return
+	}
+
+	handle.Success(c, toShipmentResp(shipment))
+}
+
+// ShipShipment hands a shipment over to the carrier
+func ShipShipment(c *gin.Context) {
+	id := c.Param("id")
+
+	var req dto.ShipShipmentReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		handle.Error(c, err)
+		return
+	}
+
+	shipment, err := services.ShipmentService.Ship(c.Request.Context(), id, req.TrackingNumber)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toShipmentResp(shipment))
+}
+
+// DeliverShipment marks some or all items of a shipment as delivered
+func DeliverShipment(c *gin.Context) {
+	id := c.Param("id")
+
+	var req dto.DeliverShipmentReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		handle.Error(c, err)
+		return
+	}
+
+	shipment, err := services.ShipmentService.Deliver(c.Request.Context(), id, req.OrderItemIDs)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	handle.Success(c, toShipmentResp(shipment))
+}
+
+func toShipmentResp(s *model.Shipment) *dto.ShipmentResp {
+	items := make([]dto.ShipmentItemResp, len(s.Items))
+	for i, item := range s.Items {
+		items[i] = dto.ShipmentItemResp{
+			ID:          item.ID,
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+			Status:      string(item.Status),
+		}
+	}
+
+	return &dto.ShipmentResp{
+		ID:             s.ID,
+		OrderID:        s.OrderID,
+		Carrier:        s.Carrier,
+		Service:        s.Service,
+		TrackingNumber: s.TrackingNumber,
+		Cost:           s.Cost,
+		Status:         string(s.Status),
+		Items:          items,
+		ShippedAt:      s.ShippedAt,
+		DeliveredAt:    s.DeliveredAt,
+		Version:        s.Version,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
+	}
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// ListStockMovements lists the stock ledger of a product, newest first
+func ListStockMovements(c *gin.Context) {
+	id := c.Param("id")
+
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	movements, total, err := services.ProductService.ListStockMovements(c.Request.Context(), id, offset, limit)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]*dto.StockMovementResp, len(movements))
+	for i, m := range movements {
+		resp[i] = toStockMovementResp(m)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": total,
+	})
+}
+
+func toStockMovementResp(m *model.StockMovement) *dto.StockMovementResp {
+	return &dto.StockMovementResp{
+		ID:          m.ID,
+		ProductID:   m.ProductID,
+		SKU:         m.SKU,
+		Quantity:    m.Quantity,
+		Reason:      string(m.Reason),
+		ReferenceID: m.ReferenceID,
+		CreatedAt:   m.CreatedAt,
+	}
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
+)
+
+// User Address Handlers
+
+// CreateUserAddress adds an address to the address book of a user
+func CreateUserAddress(c *gin.Context) {
+	if !addressesAvailable(c) {
+		return
+	}
+
+	var req dto.UserAddressReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	address, err := vo.NewAddress(req.Line1, req.Line2, req.City, req.Region, req.PostalCode, req.Country)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	entry, err := services.UserAddressService.Create(c.Request.Context(), c.Param("id"), req.Label, address, req.DefaultShipping, req.DefaultBilling)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, entry.Version)
+	handle.Success(c, toUserAddressResp(entry))
+}
+
+// ListUserAddresses lists the address book of a user
+func ListUserAddresses(c *gin.Context) {
+	if !addressesAvailable(c) {
+		return
+	}
+
+	entries, err := services.UserAddressService.List(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]*dto.UserAddressResp, len(entries))
+	for i, entry := range entries {
+		resp[i] = toUserAddressResp(entry)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": len(resp),
+	})
+}
+
+// GetUserAddress retrieves an address of a user
+func GetUserAddress(c *gin.Context) {
+	if !addressesAvailable(c) {
+		return
+	}
+
+	entry, err := services.UserAddressService.Get(c.Request.Context(), c.Param("id"), c.Param("address_id"))
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, entry.Version)
+	handle.Success(c, toUserAddressResp(entry))
+}
+
+// UpdateUserAddress replaces an address of a user, orders keep the address they were placed with
+func UpdateUserAddress(c *gin.Context) {
+	if !addressesAvailable(c) {
+		return
+	}
+
+	var req dto.UserAddressReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	address, err := vo.NewAddress(req.Line1, req.Line2, req.City, req.Region, req.PostalCode, req.Country)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	entry, err := services.UserAddressService.Update(c.Request.Context(), c.Param("id"), c.Param("address_id"), req.Label, address, req.DefaultShipping, req.DefaultBilling, expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, entry.Version)
+	handle.Success(c, toUserAddressResp(entry))
+}
+
+// DeleteUserAddress removes an address from the address book of a user
+func DeleteUserAddress(c *gin.Context) {
+	if !addressesAvailable(c) {
+		return
+	}
+
+	if err := services.UserAddressService.Delete(c.Request.Context(), c.Param("id"), c.Param("address_id")); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "address deleted"})
+}
+
+// addressesAvailable responds 503 when the address book is not configured
+func addressesAvailable(c *gin.Context) bool {
+	if services.UserAddressService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Address book not available. PostgreSQL may not be configured."})
+		return false
+	}
+	return true
+}
+
+func toUserAddressResp(entry *model.UserAddress) *dto.UserAddressResp {
+	return &dto.UserAddressResp{
+		ID:              entry.ID,
+		UserID:          entry.UserID,
+		Label:           entry.Label,
+		Line1:           entry.Line1,
+		Line2:           entry.Line2,
+		City:            entry.City,
+		Region:          entry.Region,
+		PostalCode:      entry.PostalCode,
+		Country:         entry.Country,
+		DefaultShipping: entry.DefaultShipping,
+		DefaultBilling:  entry.DefaultBilling,
+		Version:         entry.Version,
+		CreatedAt:       entry.CreatedAt,
+		UpdatedAt:       entry.UpdatedAt,
+	}
+}
+
+func toAddressResp(address *vo.Address) *dto.AddressResp {
+	if address == nil {
+		return nil
+	}
+	return &dto.AddressResp{
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
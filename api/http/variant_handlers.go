@@ -0,0 +1,124 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// Variant Handlers
+
+// CreateVariant adds a variant to a product
+func CreateVariant(c *gin.Context) {
+	var req dto.CreateVariantReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	variant := model.Variant{
+		SKU:        req.SKU,
+		Attributes: req.Attributes,
+		Price:      req.Price,
+		Stock:      req.Stock,
+	}
+
+	product, err := services.ProductService.AddVariant(c.Request.Context(), c.Param("id"), variant, expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	respondProduct(c, product)
+}
+
+// UpdateVariant changes the attributes and price override of a variant
+func UpdateVariant(c *gin.Context) {
+	var req dto.UpdateVariantReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	product, err := services.ProductService.UpdateVariant(c.Request.Context(), c.Param("id"), c.Param("sku"), req.Attributes, req.Price, expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	respondProduct(c, product)
+}
+
+// DeleteVariant removes a variant from a product
+func DeleteVariant(c *gin.Context) {
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	product, err := services.ProductService.RemoveVariant(c.Request.Context(), c.Param("id"), c.Param("sku"), expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	respondProduct(c, product)
+}
+
+// GetProductBySKU retrieves the product owning a SKU
+func GetProductBySKU(c *gin.Context) {
+	product, err := services.ProductService.GetBySKU(c.Request.Context(), c.Param("sku"))
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+	if product == nil {
+		handle.Error(c, model.ErrVariantNotFound)
+		return
+	}
+
+	respondProduct(c, product)
+}
+
+// respondProduct writes a product with its breadcrumbs and ETag
+func respondProduct(c *gin.Context, product *model.Product) {
+	resp := toProductResp(product)
+	attachBreadcrumbs(c.Request.Context(), resp)
+
+	setETag(c, product.Version)
+	handle.Success(c, resp)
+}
+
+func toVariantsResp(p *model.Product) []dto.VariantResp {
+	if !p.HasVariants() {
+		return nil
+	}
+
+	resp := make([]dto.VariantResp, len(p.Variants))
+	for i, v := range p.Variants {
+		price, _ := p.PriceOf(v.SKU)
+		resp[i] = dto.VariantResp{
+			SKU:            v.SKU,
+			Attributes:     v.Attributes,
+			Price:          price,
+			Stock:          v.Stock,
+			WarehouseStock: v.WarehouseStock,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/api/dto"
+	"cactus-golang-hexagonal-microservice-boilerplate/api/http/handle"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// Warehouse Handlers
+
+// CreateWarehouse creates a warehouse
+func CreateWarehouse(c *gin.Context) {
+	var req dto.CreateWarehouseReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	warehouse, err := services.WarehouseService.Create(c.Request.Context(), req.Code, req.Name, toLocation(req.Location))
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, warehouse.Version)
+	handle.Success(c, toWarehouseResp(warehouse))
+}
+
+// ListWarehouses lists all warehouses, ordered by code
+func ListWarehouses(c *gin.Context) {
+	warehouses, err := services.WarehouseService.List(c.Request.Context())
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	resp := make([]*dto.WarehouseResp, len(warehouses))
+	for i, warehouse := range warehouses {
+		resp[i] = toWarehouseResp(warehouse)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  resp,
+		"total": len(resp),
+	})
+}
+
+// GetWarehouse retrieves a warehouse by ID
+func GetWarehouse(c *gin.Context) {
+	warehouse, err := services.WarehouseService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+	if warehouse == nil {
+		handle.Error(c, model.ErrWarehouseNotFound)
+		return
+	}
+
+	setETag(c, warehouse.Version)
+	handle.Success(c, toWarehouseResp(warehouse))
+}
+
+// UpdateWarehouse changes the name, location and status of a warehouse
+func UpdateWarehouse(c *gin.Context) {
+	var req dto.UpdateWarehouseReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	warehouse, err := services.WarehouseService.Update(c.Request.Context(), c.Param("id"), req.Name, toLocation(req.Location), *req.Active, expectedVersion)
+	if err != nil {
+		handle.Error(c, err)
+		return
+	}
+
+	setETag(c, warehouse.Version)
+	handle.Success(c, toWarehouseResp(warehouse))
+}
+
+func toLocation(req dto.LocationReq) model.Location {
+	return model.Location{Latitude: req.Latitude, Longitude: req.Longitude}
+}
+
+func toLocationResp(l model.Location) *dto.LocationResp {
+	return &dto.LocationResp{Latitude: l.Latitude, Longitude: l.Longitude}
+}
+
+func toWarehouseResp(w *model.Warehouse) *dto.WarehouseResp {
+	return &dto.WarehouseResp{
+		ID:        w.ID,
+		Code:      w.Code,
+		Name:      w.Name,
+		Location:  *toLocationResp(w.Location),
+		Active:    w.Active,
+		Version:   w.Version,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
 )
 
 // CreateOrderUseCase handles order creation
@@ -29,12 +30,18 @@ func (uc *CreateOrderUseCase) Execute(ctx context.Context, input *CreateOrderInp
 	for i, item := range input.Items {
 		items[i] = model.OrderItem{
 			ProductID: item.ProductID,
+			SKU:       item.SKU,
 			Quantity:  item.Quantity,
 			Price:     item.Price,
 		}
 	}
 
-	order, err := uc.orderService.Create(ctx, input.UserID, items)
+	var shipTo *model.Location
+	if input.ShipTo != nil {
+		shipTo = &model.Location{Latitude: input.ShipTo.Latitude, Longitude: input.ShipTo.Longitude}
+	}
+
+	order, err := uc.orderService.Create(ctx, input.UserID, input.OrganizationID, items, shipTo, input.ShippingAddressID, input.BillingAddressID)
 	if err != nil {
 		return nil, err
 	}
@@ -46,20 +53,39 @@ func toOrderOutput(order *model.Order) *OrderOutput {
 	items := make([]OrderItemOutput, len(order.Items))
 	for i, item := range order.Items {
 		items[i] = OrderItemOutput{
-			ID:        item.ID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
+			ID:           item.ID,
+			ProductID:    item.ProductID,
+			SKU:          item.SKU,
+			Quantity:     item.Quantity,
+			Price:        item.Price,
+			PriceVersion: item.PriceVersion,
 		}
 	}
 
 	return &OrderOutput{
-		ID:        order.ID,
-		UserID:    order.UserID,
-		Items:     items,
-		Total:     order.Total,
-		Status:    string(order.Status),
-		CreatedAt: order.CreatedAt,
-		UpdatedAt: order.UpdatedAt,
+		ID:              order.ID,
+		UserID:          order.UserID,
+		OrganizationID:  order.OrganizationID,
+		Items:           items,
+		Total:           order.Total,
+		Status:          string(order.Status),
+		ShippingAddress: toAddressOutput(order.ShippingAddress),
+		BillingAddress:  toAddressOutput(order.BillingAddress),
+		CreatedAt:       order.CreatedAt,
+		UpdatedAt:       order.UpdatedAt,
+	}
+}
+
+func toAddressOutput(address *vo.Address) *AddressOutput {
+	if address == nil {
+		return nil
+	}
+	return &AddressOutput{
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
 	}
 }
@@ -5,14 +5,25 @@ import "time"
 // OrderItemInput represents an order item in the input
 type OrderItemInput struct {
 	ProductID string  `json:"product_id" validate:"required"`
+	SKU       string  `json:"sku"`
 	Quantity  int     `json:"quantity" validate:"required,gt=0"`
-	Price     float64 `json:"price" validate:"required,gt=0"`
+	Price     float64 `json:"price" validate:"omitempty,gt=0"` // optional, must match the current price when given
+}
+
+// LocationInput represents a geographic location in the input
+type LocationInput struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
 }
 
 // CreateOrderInput represents the input for creating an order
 type CreateOrderInput struct {
-	UserID string           `json:"user_id" validate:"required,uuid"`
-	Items  []OrderItemInput `json:"items" validate:"required,min=1"`
+	UserID            string           `json:"user_id" validate:"required,uuid"`
+	OrganizationID    string           `json:"organization_id" validate:"omitempty,uuid"` // optional, orders on behalf of an organization the user buys for
+	Items             []OrderItemInput `json:"items" validate:"required,min=1"`
+	ShipTo            *LocationInput   `json:"ship_to"`                                       // optional, used to pick the nearest warehouses
+	ShippingAddressID string           `json:"shipping_address_id" validate:"omitempty,uuid"` // optional, defaults to the default shipping address of the user
+	BillingAddressID  string           `json:"billing_address_id" validate:"omitempty,uuid"`  // optional, defaults to the default billing address of the user
 }
 
 // Validate validates the create order input
@@ -30,7 +41,7 @@ func (i *CreateOrderInput) Validate() error {
 		if item.Quantity <= 0 {
 			return ErrInvalidQuantity
 		}
-		if item.Price <= 0 {
+		if item.Price < 0 {
 			return ErrInvalidPrice
 		}
 	}
@@ -52,8 +63,9 @@ func (i *GetOrderInput) Validate() error {
 
 // UpdateStatusInput represents the input for updating order status
 type UpdateStatusInput struct {
-	ID     string `json:"id" validate:"required,uuid"`
-	Status string `json:"status" validate:"required"`
+	ID      string `json:"id" validate:"required,uuid"`
+	Status  string `json:"status" validate:"required"`
+	Version int    `json:"version"` // expected version, zero skips the check
 }
 
 // Validate validates the update status input
@@ -66,8 +78,6 @@ func (i *UpdateStatusInput) Validate() error {
 	}
 	validStatuses := map[string]bool{
 		"confirmed": true,
-		"shipped":   true,
-		"delivered": true,
 		"canceled":  true,
 	}
 	if !validStatuses[i.Status] {
@@ -129,21 +139,36 @@ func (i *GetUserOrdersInput) Validate() error {
 
 // OrderItemOutput represents an order item in the output
 type OrderItemOutput struct {
-	ID        string  `json:"id"`
-	ProductID string  `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
+	ID           string  `json:"id"`
+	ProductID    string  `json:"product_id"`
+	SKU          string  `json:"sku,omitempty"`
+	Quantity     int     `json:"quantity"`
+	Price        float64 `json:"price"`
+	PriceVersion int     `json:"price_version,omitempty"`
+}
+
+// AddressOutput represents the address an order was placed with
+type AddressOutput struct {
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
 }
 
 // OrderOutput represents the output for an order
 type OrderOutput struct {
-	ID        string            `json:"id"`
-	UserID    string            `json:"user_id"`
-	Items     []OrderItemOutput `json:"items"`
-	Total     float64           `json:"total"`
-	Status    string            `json:"status"`
-	CreatedAt time.Time         `json:"created_at"`
-	UpdatedAt time.Time         `json:"updated_at"`
+	ID              string            `json:"id"`
+	UserID          string            `json:"user_id"`
+	OrganizationID  string            `json:"organization_id,omitempty"`
+	Items           []OrderItemOutput `json:"items"`
+	Total           float64           `json:"total"`
+	Status          string            `json:"status"`
+	ShippingAddress *AddressOutput    `json:"shipping_address,omitempty"`
+	BillingAddress  *AddressOutput    `json:"billing_address,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
 }
 
 // ListOrdersOutput represents the output for listing orders
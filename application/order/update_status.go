@@ -26,5 +26,6 @@ func (uc *UpdateStatusUseCase) Execute(ctx context.Context, input *UpdateStatusI
 	}
 
 	status := model.OrderStatus(input.Status)
-	return uc.orderService.UpdateStatus(ctx, input.ID, status)
+	_, err := uc.orderService.UpdateStatus(ctx, input.ID, status, input.Version)
+	return err
 }
@@ -30,6 +30,7 @@ type UpdateProductInput struct {
 	Name        string  `json:"name" validate:"required"`
 	Description string  `json:"description"`
 	Price       float64 `json:"price" validate:"required,gt=0"`
+	Version     int     `json:"version"` // expected version, zero skips the check
 }
 
 // Validate validates the update product input
@@ -74,8 +75,12 @@ func (i *DeleteProductInput) Validate() error {
 
 // UpdateStockInput represents the input for updating product stock
 type UpdateStockInput struct {
-	ID       string `json:"id" validate:"required"`
-	Quantity int    `json:"quantity" validate:"required"`
+	ID          string `json:"id" validate:"required"`
+	SKU         string `json:"sku"`          // required for products with variants
+	WarehouseID string `json:"warehouse_id"` // optional, adjusts the stock of that warehouse as well
+	Quantity    int    `json:"quantity" validate:"required"`
+	Reason      string `json:"reason"` // defaults to manual
+	ReferenceID string `json:"reference_id"`
 }
 
 // Validate validates the update stock input
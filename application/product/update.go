@@ -24,7 +24,7 @@ func (uc *UpdateProductUseCase) Execute(ctx context.Context, input *UpdateProduc
 		return nil, err
 	}
 
-	product, err := uc.productService.Update(ctx, input.ID, input.Name, input.Description, input.Price)
+	product, err := uc.productService.Update(ctx, input.ID, input.Name, input.Description, input.Price, input.Version)
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,7 @@ package product
 import (
 	"context"
 
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
 )
 
@@ -24,5 +25,16 @@ func (uc *UpdateStockUseCase) Execute(ctx context.Context, input *UpdateStockInp
 		return err
 	}
 
-	return uc.productService.UpdateStock(ctx, input.ID, input.Quantity)
+	reason := model.StockMovementReasonManual
+	if input.Reason != "" {
+		reason = model.StockMovementReason(input.Reason)
+	}
+
+	return uc.productService.UpdateStock(ctx, input.ID, model.StockChange{
+		SKU:         input.SKU,
+		WarehouseID: input.WarehouseID,
+		Quantity:    input.Quantity,
+		Reason:      reason,
+		ReferenceID: input.ReferenceID,
+	})
 }
@@ -28,8 +28,9 @@ func (i *CreateUserInput) Validate() error {
 
 // UpdateUserInput represents the input for updating a user
 type UpdateUserInput struct {
-	ID   string `json:"id" validate:"required,uuid"`
-	Name string `json:"name" validate:"required"`
+	ID      string `json:"id" validate:"required,uuid"`
+	Name    string `json:"name" validate:"required"`
+	Version int    `json:"version"` // expected version, zero skips the check
 }
 
 // Validate validates the update user input
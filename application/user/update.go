@@ -24,7 +24,7 @@ func (uc *UpdateUserUseCase) Execute(ctx context.Context, input *UpdateUserInput
 		return nil, err
 	}
 
-	user, err := uc.userService.Update(ctx, input.ID, input.Name)
+	user, err := uc.userService.Update(ctx, input.ID, input.Name, input.Version)
 	if err != nil {
 		return nil, err
 	}
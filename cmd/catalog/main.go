@@ -0,0 +1,235 @@
+// Command catalog imports products from and exports products to CSV or NDJSON files.
+//
+//	catalog import [-tenant id] [-format csv|ndjson] [-dry-run] [-batch-size n] <file|->
+//	catalog export [-tenant id] [-format csv|ndjson] [-o file]
+//
+// It works on the catalog of one tenant, the default one unless -tenant names another. It reads
+// the same configuration as the service and needs MongoDB. Domain events are published to Kafka
+// when it is configured.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/amqp"
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/catalog"
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/dependency"
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/repository"
+	"cactus-golang-hexagonal-microservice-boilerplate/config"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/service"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+const usage = `usage:
+  catalog import [-tenant id] [-format csv|ndjson] [-dry-run] [-batch-size n] <file|->
+  catalog export [-tenant id] [-format csv|ndjson] [-o file]`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(ctx, os.Args[2:])
+	case "export":
+		err = runExport(ctx, os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "catalog:", err)
+		os.Exit(1)
+	}
+}
+
+func runImport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	tenantID := flags.String("tenant", model.DefaultTenantID, "tenant whose catalog to import into")
+	format := flags.String("format", "", "file format, csv or ndjson (default: from the file extension, else csv)")
+	dryRun := flags.Bool("dry-run", false, "validate and report without writing")
+	batchSize := flags.Int("batch-size", service.DefaultProductImportBatchSize, "rows per bulk write")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("import needs one file, or - for standard input")
+	}
+	ctx, err := tenantContext(ctx, *tenantID)
+	if err != nil {
+		return err
+	}
+
+	path := flags.Arg(0)
+	input := io.Reader(os.Stdin)
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	reader, err := catalog.NewReader(fileFormat(*format, path), input)
+	if err != nil {
+		return err
+	}
+
+	productService, cleanup, err := initProductService(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	opts := model.ProductImportOptions{
+		DryRun:      *dryRun,
+		BatchSize:   *batchSize,
+		ReferenceID: uuid.New().String(),
+	}
+	report, importErr := productService.Import(ctx, reader, opts)
+	if report != nil {
+		if err := writeReport(os.Stdout, report); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "import %s: %d rows, %d created, %d updated, %d unchanged, %d failed\n",
+			report.ReferenceID, report.Total, report.Created, report.Updated, report.Unchanged, report.Failed)
+	}
+	if importErr != nil {
+		return importErr
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d rows failed", report.Failed)
+	}
+	return nil
+}
+
+func runExport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	tenantID := flags.String("tenant", model.DefaultTenantID, "tenant whose catalog to export")
+	format := flags.String("format", "", "file format, csv or ndjson (default: from the output extension, else csv)")
+	outputPath := flags.String("o", "-", "output file, - for standard output")
+	_ = flags.Parse(args)
+	ctx, err := tenantContext(ctx, *tenantID)
+	if err != nil {
+		return err
+	}
+
+	output := io.Writer(os.Stdout)
+	if *outputPath != "-" {
+		file, err := os.Create(*outputPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+
+	writer, err := catalog.NewWriter(fileFormat(*format, *outputPath), output)
+	if err != nil {
+		return err
+	}
+
+	productService, cleanup, err := initProductService(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	count, err := productService.Export(ctx, writer)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d products\n", count)
+	return nil
+}
+
+// tenantContext scopes ctx to the tenant, as the repositories refuse contexts without one
+func tenantContext(ctx context.Context, tenantID string) (context.Context, error) {
+	if err := model.ValidateTenantID(tenantID); err != nil {
+		return nil, fmt.Errorf("invalid tenant %q", tenantID)
+	}
+	return model.ContextWithTenant(ctx, tenantID), nil
+}
+
+// writeReport writes the outcome of every row as a table
+func writeReport(w io.Writer, report *model.ProductImportReport) error {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "LINE\tACTION\tSKU\tNAME\tPRODUCT\tERROR")
+	for _, row := range report.Rows {
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\t%s\n", row.Line, row.Action, row.SKU, row.Name, row.ProductID, row.Error)
+	}
+	return table.Flush()
+}
+
+// fileFormat returns the format flag, falling back to the file extension and then CSV
+func fileFormat(flagValue, path string) model.ProductFileFormat {
+	if flagValue != "" {
+		return model.ProductFileFormat(strings.ToLower(flagValue))
+	}
+	if strings.EqualFold(filepath.Ext(path), ".ndjson") || strings.EqualFold(filepath.Ext(path), ".jsonl") {
+		return model.ProductFileFormatNDJSON
+	}
+	return model.ProductFileFormatCSV
+}
+
+// initProductService connects to MongoDB, and to Kafka for audit events when configured
+func initProductService(ctx context.Context) (service.IProductService, func(), error) {
+	config.Init("./config", "config")
+	log.Init()
+
+	clients, err := dependency.InitializeRepositories(dependency.WithMongoDB())
+	if err != nil {
+		return nil, nil, err
+	}
+	repository.Clients = clients
+
+	cleanup := func() {}
+	var eventBus event.EventBus
+	if config.GlobalConfig.Kafka != nil {
+		kafkaProducer, err := amqp.NewKafkaEventBus(&amqp.KafkaConfig{
+			Brokers: config.GlobalConfig.Kafka.Brokers,
+			Topic:   config.GlobalConfig.Kafka.Topics.AuditEvents,
+		})
+		if err != nil {
+			log.SugaredLogger.Warnf("Failed to initialize Kafka producer, events are only logged: %v", err)
+		} else {
+			inMemoryBus := event.NewInMemoryEventBus()
+			inMemoryBus.Subscribe(event.NewKafkaAuditHandler(kafkaProducer, config.GlobalConfig.Kafka.Topics.AuditEvents))
+			eventBus = inMemoryBus
+			cleanup = func() {
+				if err := kafkaProducer.Close(); err != nil {
+					log.SugaredLogger.Errorf("Failed to close Kafka producer: %v", err)
+				}
+			}
+		}
+	}
+
+	services, err := dependency.InitializeServices(ctx, clients, eventBus, dependency.WithProductService())
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if services.ProductService == nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("product service is not available, check the MongoDB configuration")
+	}
+	return services.ProductService, cleanup, nil
+}
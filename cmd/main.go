@@ -13,6 +13,7 @@ import (
 	"cactus-golang-hexagonal-microservice-boilerplate/adapter/job"
 	"cactus-golang-hexagonal-microservice-boilerplate/adapter/repository"
 	"cactus-golang-hexagonal-microservice-boilerplate/adapter/repository/dynamodb"
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/repository/redis"
 	"cactus-golang-hexagonal-microservice-boilerplate/api/middleware"
 	"cactus-golang-hexagonal-microservice-boilerplate/cmd/http_server"
 	"cactus-golang-hexagonal-microservice-boilerplate/config"
@@ -139,15 +140,37 @@ func main() {
 		log.Logger.Info("Redis available - using cached services")
 		serviceOpts = []dependency.ServiceOption{
 			dependency.WithCachedUserService(),
+			dependency.WithAuthService(),
+			dependency.WithCachedAPIKeyService(),
+			dependency.WithAccountService(),
 			dependency.WithCachedProductService(),
+			dependency.WithCategoryService(),
+			dependency.WithWarehouseService(),
+			dependency.WithReservationService(),
+			dependency.WithUserAddressService(),
+			dependency.WithOrganizationService(),
 			dependency.WithOrderService(),
+			dependency.WithPaymentService(),
+			dependency.WithReturnService(),
+			dependency.WithShipmentService(),
+			dependency.WithInvoiceService(),
 		}
 	} else {
 		log.Logger.Info("Redis not available - using regular services")
 		serviceOpts = []dependency.ServiceOption{
 			dependency.WithUserService(),
+			dependency.WithAPIKeyService(),
+			dependency.WithAccountService(),
 			dependency.WithProductService(),
+			dependency.WithCategoryService(),
+			dependency.WithWarehouseService(),
+			dependency.WithUserAddressService(),
+			dependency.WithOrganizationService(),
 			dependency.WithOrderService(),
+			dependency.WithPaymentService(),
+			dependency.WithReturnService(),
+			dependency.WithShipmentService(),
+			dependency.WithInvoiceService(),
 		}
 	}
 	services, err := dependency.InitializeServices(ctx, clients, eventBus, serviceOpts...)
@@ -193,6 +216,81 @@ func main() {
 		log.Logger.Info("DynamoDB not configured, audit service disabled")
 	}
 
+	// Initialize job scheduler, locking job runs through Redis when available
+	var schedulerOpts []job.SchedulerOption
+	if clients.Redis != nil {
+		redisClient, err := redis.NewClientFromConfig(config.GlobalConfig.Redis)
+		if err != nil {
+			log.Logger.Warn("Failed to create Redis client for job locks, jobs will run without locking", zap.Error(err))
+		} else {
+			schedulerOpts = append(schedulerOpts, job.WithLocker(redis.NewDistributedLock(redisClient)))
+		}
+	}
+	scheduler := job.NewScheduler(schedulerOpts...)
+
+	if jobsCfg := config.GlobalConfig.Jobs; jobsCfg != nil && jobsCfg.StaleOrderCancel != nil &&
+		jobsCfg.StaleOrderCancel.Enabled && services.OrderService != nil {
+		staleCfg := jobsCfg.StaleOrderCancel
+		staleOrderJob := job.NewStaleOrderCancelJob(
+			services.OrderService,
+			config.GetDuration(staleCfg.PendingTTL),
+			staleCfg.BatchSize,
+		)
+		if err := scheduler.AddJob(staleCfg.Spec, staleOrderJob); err != nil {
+			log.Logger.Error("Failed to schedule stale order cancellation job", zap.Error(err))
+		}
+	}
+
+	if jobsCfg := config.GlobalConfig.Jobs; jobsCfg != nil && jobsCfg.StockReconciliation != nil &&
+		jobsCfg.StockReconciliation.Enabled && services.ProductService != nil {
+		reconciliationCfg := jobsCfg.StockReconciliation
+		reconciliationJob := job.NewStockReconciliationJob(services.ProductService, reconciliationCfg.BatchSize)
+		if err := scheduler.AddJob(reconciliationCfg.Spec, reconciliationJob); err != nil {
+			log.Logger.Error("Failed to schedule stock reconciliation job", zap.Error(err))
+		}
+	}
+
+	if jobsCfg := config.GlobalConfig.Jobs; jobsCfg != nil && jobsCfg.StockHoldRelease != nil &&
+		jobsCfg.StockHoldRelease.Enabled && services.ReservationService != nil {
+		holdReleaseCfg := jobsCfg.StockHoldRelease
+		holdReleaseJob := job.NewStockHoldReleaseJob(services.ReservationService, holdReleaseCfg.BatchSize)
+		if err := scheduler.AddJob(holdReleaseCfg.Spec, holdReleaseJob); err != nil {
+			log.Logger.Error("Failed to schedule stock hold release job", zap.Error(err))
+		}
+	}
+
+	if jobsCfg := config.GlobalConfig.Jobs; jobsCfg != nil && jobsCfg.LowStockSweep != nil &&
+		jobsCfg.LowStockSweep.Enabled && services.ProductService != nil {
+		lowStockCfg := jobsCfg.LowStockSweep
+		lowStockJob := job.NewLowStockSweepJob(services.ProductService, lowStockCfg.BatchSize)
+		if err := scheduler.AddJob(lowStockCfg.Spec, lowStockJob); err != nil {
+			log.Logger.Error("Failed to schedule low stock sweep job", zap.Error(err))
+		}
+	}
+
+	if jobsCfg := config.GlobalConfig.Jobs; jobsCfg != nil && jobsCfg.ScheduledPrice != nil &&
+		jobsCfg.ScheduledPrice.Enabled && services.ProductService != nil {
+		scheduledPriceCfg := jobsCfg.ScheduledPrice
+		scheduledPriceJob := job.NewScheduledPriceJob(services.ProductService, scheduledPriceCfg.BatchSize)
+		if err := scheduler.AddJob(scheduledPriceCfg.Spec, scheduledPriceJob); err != nil {
+			log.Logger.Error("Failed to schedule scheduled price job", zap.Error(err))
+		}
+	}
+
+	if jobsCfg := config.GlobalConfig.Jobs; jobsCfg != nil && jobsCfg.ApprovalEscalation != nil &&
+		jobsCfg.ApprovalEscalation.Enabled && services.OrderService != nil {
+		escalationCfg := jobsCfg.ApprovalEscalation
+		escalationJob := job.NewApprovalEscalationJob(
+			services.OrderService,
+			config.GetDuration(escalationCfg.EscalateAfter),
+			escalationCfg.BatchSize,
+		)
+		if err := scheduler.AddJob(escalationCfg.Spec, escalationJob); err != nil {
+			log.Logger.Error("Failed to schedule order approval escalation job", zap.Error(err))
+		}
+	}
+	scheduler.Start()
+
 	// Create error channel and HTTP close channel
 	errChan := make(chan error, 1)
 	httpCloseCh := make(chan struct{}, 1)
@@ -216,6 +314,8 @@ func main() {
 	log.Logger.Info("Shutting down server")
 	cancel()
 
+	scheduler.Stop()
+
 	if kafkaConsumer != nil {
 		log.Logger.Info("Stopping Kafka consumer")
 		if err := kafkaConsumer.Stop(); err != nil {
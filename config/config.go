@@ -48,6 +48,16 @@ type Config struct {
 	DynamoDB      *DynamoDBConfig   `yaml:"dynamodb" mapstructure:"dynamodb"`
 	Kafka         *KafkaConfig      `yaml:"kafka" mapstructure:"kafka"`
 	RabbitMQ      *RabbitMQConfig   `yaml:"rabbitmq" mapstructure:"rabbitmq"`
+	Jobs          *JobsConfig       `yaml:"jobs" mapstructure:"jobs"`
+	Payment       *PaymentConfig    `yaml:"payment" mapstructure:"payment"`
+	Invoice       *InvoiceConfig    `yaml:"invoice" mapstructure:"invoice"`
+	Inventory     *InventoryConfig  `yaml:"inventory" mapstructure:"inventory"`
+	Password      *PasswordConfig   `yaml:"password" mapstructure:"password"`
+	Auth          *AuthConfig       `yaml:"auth" mapstructure:"auth"`
+	Lockout       *LockoutConfig    `yaml:"lockout" mapstructure:"lockout"`
+	Account       *AccountConfig    `yaml:"account" mapstructure:"account"`
+	Notifier      *NotifierConfig   `yaml:"notifier" mapstructure:"notifier"`
+	Tenant        *TenantConfig     `yaml:"tenant" mapstructure:"tenant"`
 	MigrationDir  string            `yaml:"migration_dir" mapstructure:"migration_dir"`
 }
 
@@ -64,6 +74,9 @@ type HttpServerConfig struct {
 	MaxPageSize     int    `yaml:"max_page_size" mapstructure:"max_page_size"`
 	ReadTimeout     string `yaml:"read_timeout" mapstructure:"read_timeout"`
 	WriteTimeout    string `yaml:"write_timeout" mapstructure:"write_timeout"`
+	// TrustedProxies lists the proxy IPs or CIDRs whose X-Forwarded-For header gives the client IP.
+	// With none, the client IP is always the address of the connection.
+	TrustedProxies []string `yaml:"trusted_proxies" mapstructure:"trusted_proxies"`
 }
 
 type MetricsConfig struct {
@@ -167,6 +180,111 @@ type RabbitMQConfig struct {
 	Prefetch   int    `yaml:"prefetch" mapstructure:"prefetch"`
 }
 
+type PaymentConfig struct {
+	Provider      string `yaml:"provider" mapstructure:"provider"`
+	WebhookSecret string `yaml:"webhook_secret" mapstructure:"webhook_secret"`
+}
+
+type InvoiceConfig struct {
+	IssuerName string  `yaml:"issuer_name" mapstructure:"issuer_name"`
+	TaxRate    float64 `yaml:"tax_rate" mapstructure:"tax_rate"`
+}
+
+type InventoryConfig struct {
+	AllocationStrategy string `yaml:"allocation_strategy" mapstructure:"allocation_strategy"`
+	HoldTTL            string `yaml:"hold_ttl" mapstructure:"hold_ttl"`
+}
+
+type PasswordConfig struct {
+	Algorithm         string `yaml:"algorithm" mapstructure:"algorithm"`
+	Argon2Memory      uint32 `yaml:"argon2_memory" mapstructure:"argon2_memory"` // KiB
+	Argon2Iterations  uint32 `yaml:"argon2_iterations" mapstructure:"argon2_iterations"`
+	Argon2Parallelism uint8  `yaml:"argon2_parallelism" mapstructure:"argon2_parallelism"`
+	BcryptCost        int    `yaml:"bcrypt_cost" mapstructure:"bcrypt_cost"`
+}
+
+type AuthConfig struct {
+	Enabled    bool   `yaml:"enabled" mapstructure:"enabled"`
+	Secret     string `yaml:"secret" mapstructure:"secret"` // HMAC key signing the tokens, at least 32 bytes
+	Issuer     string `yaml:"issuer" mapstructure:"issuer"`
+	AccessTTL  string `yaml:"access_ttl" mapstructure:"access_ttl"`
+	RefreshTTL string `yaml:"refresh_ttl" mapstructure:"refresh_ttl"`
+}
+
+type LockoutConfig struct {
+	Enabled            bool   `yaml:"enabled" mapstructure:"enabled"`
+	MaxAccountFailures int    `yaml:"max_account_failures" mapstructure:"max_account_failures"` // failed logins per account before it is locked
+	MaxIPFailures      int    `yaml:"max_ip_failures" mapstructure:"max_ip_failures"`           // failed logins per client IP before it is locked
+	FailureWindow      string `yaml:"failure_window" mapstructure:"failure_window"`
+	Duration           string `yaml:"duration" mapstructure:"duration"`
+	DelayBase          string `yaml:"delay_base" mapstructure:"delay_base"` // delay after the first failure, doubled on each further one
+	MaxDelay           string `yaml:"max_delay" mapstructure:"max_delay"`
+}
+
+type AccountConfig struct {
+	VerificationTTL  string `yaml:"verification_ttl" mapstructure:"verification_ttl"`
+	PasswordResetTTL string `yaml:"password_reset_ttl" mapstructure:"password_reset_ttl"`
+	EmailRateLimit   int    `yaml:"email_rate_limit" mapstructure:"email_rate_limit"` // messages per email and window
+	EmailRateWindow  string `yaml:"email_rate_window" mapstructure:"email_rate_window"`
+}
+
+type NotifierConfig struct {
+	Driver   string `yaml:"driver" mapstructure:"driver"` // console or file
+	FilePath string `yaml:"file_path" mapstructure:"file_path"`
+}
+
+type TenantConfig struct {
+	Header     string `yaml:"header" mapstructure:"header"`           // request header naming the tenant
+	BaseDomain string `yaml:"base_domain" mapstructure:"base_domain"` // hosts <tenant>.<base_domain> name their tenant, empty to disable
+}
+
+type JobsConfig struct {
+	StaleOrderCancel    *StaleOrderCancelConfig    `yaml:"stale_order_cancel" mapstructure:"stale_order_cancel"`
+	StockReconciliation *StockReconciliationConfig `yaml:"stock_reconciliation" mapstructure:"stock_reconciliation"`
+	StockHoldRelease    *StockHoldReleaseConfig    `yaml:"stock_hold_release" mapstructure:"stock_hold_release"`
+	LowStockSweep       *LowStockSweepConfig       `yaml:"low_stock_sweep" mapstructure:"low_stock_sweep"`
+	ScheduledPrice      *ScheduledPriceConfig      `yaml:"scheduled_price" mapstructure:"scheduled_price"`
+	ApprovalEscalation  *ApprovalEscalationConfig  `yaml:"approval_escalation" mapstructure:"approval_escalation"`
+}
+
+type StaleOrderCancelConfig struct {
+	Enabled    bool   `yaml:"enabled" mapstructure:"enabled"`
+	Spec       string `yaml:"spec" mapstructure:"spec"`
+	PendingTTL string `yaml:"pending_ttl" mapstructure:"pending_ttl"`
+	BatchSize  int    `yaml:"batch_size" mapstructure:"batch_size"`
+}
+
+type ApprovalEscalationConfig struct {
+	Enabled       bool   `yaml:"enabled" mapstructure:"enabled"`
+	Spec          string `yaml:"spec" mapstructure:"spec"`
+	EscalateAfter string `yaml:"escalate_after" mapstructure:"escalate_after"`
+	BatchSize     int    `yaml:"batch_size" mapstructure:"batch_size"`
+}
+
+type StockReconciliationConfig struct {
+	Enabled   bool   `yaml:"enabled" mapstructure:"enabled"`
+	Spec      string `yaml:"spec" mapstructure:"spec"`
+	BatchSize int    `yaml:"batch_size" mapstructure:"batch_size"`
+}
+
+type StockHoldReleaseConfig struct {
+	Enabled   bool   `yaml:"enabled" mapstructure:"enabled"`
+	Spec      string `yaml:"spec" mapstructure:"spec"`
+	BatchSize int    `yaml:"batch_size" mapstructure:"batch_size"`
+}
+
+type LowStockSweepConfig struct {
+	Enabled   bool   `yaml:"enabled" mapstructure:"enabled"`
+	Spec      string `yaml:"spec" mapstructure:"spec"`
+	BatchSize int    `yaml:"batch_size" mapstructure:"batch_size"`
+}
+
+type ScheduledPriceConfig struct {
+	Enabled   bool   `yaml:"enabled" mapstructure:"enabled"`
+	Spec      string `yaml:"spec" mapstructure:"spec"`
+	BatchSize int    `yaml:"batch_size" mapstructure:"batch_size"`
+}
+
 func Load(configPath string, configFile string) (*Config, error) {
 	var conf *Config
 	vip := viper.New()
@@ -224,6 +342,16 @@ func applyEnvOverrides(conf *Config) {
 	applyDynamoDBEnvOverrides(conf)
 	applyKafkaEnvOverrides(conf)
 	applyRabbitMQEnvOverrides(conf)
+	applyJobsEnvOverrides(conf)
+	applyPaymentEnvOverrides(conf)
+	applyInvoiceEnvOverrides(conf)
+	applyInventoryEnvOverrides(conf)
+	applyPasswordEnvOverrides(conf)
+	applyAuthEnvOverrides(conf)
+	applyLockoutEnvOverrides(conf)
+	applyAccountEnvOverrides(conf)
+	applyNotifierEnvOverrides(conf)
+	applyTenantEnvOverrides(conf)
 	applyLogEnvOverrides(conf)
 
 	// Migration directory
@@ -275,6 +403,9 @@ func applyHTTPServerEnvOverrides(conf *Config) {
 	if writeTimeout := os.Getenv("APP_HTTP_SERVER_WRITE_TIMEOUT"); writeTimeout != "" {
 		conf.HTTPServer.WriteTimeout = writeTimeout
 	}
+	if proxies := os.Getenv("APP_HTTP_SERVER_TRUSTED_PROXIES"); proxies != "" {
+		conf.HTTPServer.TrustedProxies = strings.Split(proxies, ",")
+	}
 }
 
 // applyMetricsServerEnvOverrides applies metrics server related environment variables
@@ -584,6 +715,282 @@ func applyRabbitMQEnvOverrides(conf *Config) {
 	}
 }
 
+// applyJobsEnvOverrides applies scheduled job related environment variables
+func applyJobsEnvOverrides(conf *Config) {
+	if conf.Jobs == nil {
+		return
+	}
+
+	if conf.Jobs.StaleOrderCancel != nil {
+		applyStaleOrderCancelEnvOverrides(conf.Jobs.StaleOrderCancel)
+	}
+	if conf.Jobs.StockReconciliation != nil {
+		applyStockReconciliationEnvOverrides(conf.Jobs.StockReconciliation)
+	}
+	if conf.Jobs.StockHoldRelease != nil {
+		applyStockHoldReleaseEnvOverrides(conf.Jobs.StockHoldRelease)
+	}
+	if conf.Jobs.LowStockSweep != nil {
+		applyLowStockSweepEnvOverrides(conf.Jobs.LowStockSweep)
+	}
+	if conf.Jobs.ScheduledPrice != nil {
+		applyScheduledPriceEnvOverrides(conf.Jobs.ScheduledPrice)
+	}
+	if conf.Jobs.ApprovalEscalation != nil {
+		applyApprovalEscalationEnvOverrides(conf.Jobs.ApprovalEscalation)
+	}
+}
+
+// applyStaleOrderCancelEnvOverrides applies stale order cancellation job environment variables
+func applyStaleOrderCancelEnvOverrides(cfg *StaleOrderCancelConfig) {
+	if enabled := os.Getenv("APP_JOBS_STALE_ORDER_CANCEL_ENABLED"); enabled != "" {
+		cfg.Enabled = enabled == TrueStr
+	}
+	if spec := os.Getenv("APP_JOBS_STALE_ORDER_CANCEL_SPEC"); spec != "" {
+		cfg.Spec = spec
+	}
+	if pendingTTL := os.Getenv("APP_JOBS_STALE_ORDER_CANCEL_PENDING_TTL"); pendingTTL != "" {
+		cfg.PendingTTL = pendingTTL
+	}
+	if batchSize := os.Getenv("APP_JOBS_STALE_ORDER_CANCEL_BATCH_SIZE"); batchSize != "" {
+		if val, err := strconv.Atoi(batchSize); err == nil {
+			cfg.BatchSize = val
+		}
+	}
+}
+
+// applyApprovalEscalationEnvOverrides applies order approval escalation job environment variables
+func applyApprovalEscalationEnvOverrides(cfg *ApprovalEscalationConfig) {
+	if enabled := os.Getenv("APP_JOBS_APPROVAL_ESCALATION_ENABLED"); enabled != "" {
+		cfg.Enabled = enabled == TrueStr
+	}
+	if spec := os.Getenv("APP_JOBS_APPROVAL_ESCALATION_SPEC"); spec != "" {
+		cfg.Spec = spec
+	}
+	if escalateAfter := os.Getenv("APP_JOBS_APPROVAL_ESCALATION_ESCALATE_AFTER"); escalateAfter != "" {
+		cfg.EscalateAfter = escalateAfter
+	}
+	if batchSize := os.Getenv("APP_JOBS_APPROVAL_ESCALATION_BATCH_SIZE"); batchSize != "" {
+		if val, err := strconv.Atoi(batchSize); err == nil {
+			cfg.BatchSize = val
+		}
+	}
+}
+
+// applyStockReconciliationEnvOverrides applies stock reconciliation job environment variables
+func applyStockReconciliationEnvOverrides(cfg *StockReconciliationConfig) {
+	if enabled := os.Getenv("APP_JOBS_STOCK_RECONCILIATION_ENABLED"); enabled != "" {
+		cfg.Enabled = enabled == TrueStr
+	}
+	if spec := os.Getenv("APP_JOBS_STOCK_RECONCILIATION_SPEC"); spec != "" {
+		cfg.Spec = spec
+	}
+	if batchSize := os.Getenv("APP_JOBS_STOCK_RECONCILIATION_BATCH_SIZE"); batchSize != "" {
+		if val, err := strconv.Atoi(batchSize); err == nil {
+			cfg.BatchSize = val
+		}
+	}
+}
+
+// applyStockHoldReleaseEnvOverrides applies expired stock hold release job environment variables
+func applyStockHoldReleaseEnvOverrides(cfg *StockHoldReleaseConfig) {
+	if enabled := os.Getenv("APP_JOBS_STOCK_HOLD_RELEASE_ENABLED"); enabled != "" {
+		cfg.Enabled = enabled == TrueStr
+	}
+	if spec := os.Getenv("APP_JOBS_STOCK_HOLD_RELEASE_SPEC"); spec != "" {
+		cfg.Spec = spec
+	}
+	if batchSize := os.Getenv("APP_JOBS_STOCK_HOLD_RELEASE_BATCH_SIZE"); batchSize != "" {
+		if val, err := strconv.Atoi(batchSize); err == nil {
+			cfg.BatchSize = val
+		}
+	}
+}
+
+// applyLowStockSweepEnvOverrides applies low-stock sweep job environment variables
+func applyLowStockSweepEnvOverrides(cfg *LowStockSweepConfig) {
+	if enabled := os.Getenv("APP_JOBS_LOW_STOCK_SWEEP_ENABLED"); enabled != "" {
+		cfg.Enabled = enabled == TrueStr
+	}
+	if spec := os.Getenv("APP_JOBS_LOW_STOCK_SWEEP_SPEC"); spec != "" {
+		cfg.Spec = spec
+	}
+	if batchSize := os.Getenv("APP_JOBS_LOW_STOCK_SWEEP_BATCH_SIZE"); batchSize != "" {
+		if val, err := strconv.Atoi(batchSize); err == nil {
+			cfg.BatchSize = val
+		}
+	}
+}
+
+// applyScheduledPriceEnvOverrides applies scheduled price job environment variables
+func applyScheduledPriceEnvOverrides(cfg *ScheduledPriceConfig) {
+	if enabled := os.Getenv("APP_JOBS_SCHEDULED_PRICE_ENABLED"); enabled != "" {
+		cfg.Enabled = enabled == TrueStr
+	}
+	if spec := os.Getenv("APP_JOBS_SCHEDULED_PRICE_SPEC"); spec != "" {
+		cfg.Spec = spec
+	}
+	if batchSize := os.Getenv("APP_JOBS_SCHEDULED_PRICE_BATCH_SIZE"); batchSize != "" {
+		if val, err := strconv.Atoi(batchSize); err == nil {
+			cfg.BatchSize = val
+		}
+	}
+}
+
+// applyPaymentEnvOverrides applies payment gateway related environment variables
+func applyPaymentEnvOverrides(conf *Config) {
+	if conf.Payment == nil {
+		return
+	}
+
+	if provider := os.Getenv("APP_PAYMENT_PROVIDER"); provider != "" {
+		conf.Payment.Provider = provider
+	}
+	if secret := os.Getenv("APP_PAYMENT_WEBHOOK_SECRET"); secret != "" {
+		conf.Payment.WebhookSecret = secret
+	}
+}
+
+// applyInvoiceEnvOverrides applies invoicing related environment variables
+func applyInvoiceEnvOverrides(conf *Config) {
+	if conf.Invoice == nil {
+		return
+	}
+
+	if issuer := os.Getenv("APP_INVOICE_ISSUER_NAME"); issuer != "" {
+		conf.Invoice.IssuerName = issuer
+	}
+	if taxRate := os.Getenv("APP_INVOICE_TAX_RATE"); taxRate != "" {
+		if val, err := strconv.ParseFloat(taxRate, 64); err == nil {
+			conf.Invoice.TaxRate = val
+		}
+	}
+}
+
+// applyInventoryEnvOverrides applies inventory related environment variables
+func applyInventoryEnvOverrides(conf *Config) {
+	if conf.Inventory == nil {
+		return
+	}
+
+	if strategy := os.Getenv("APP_INVENTORY_ALLOCATION_STRATEGY"); strategy != "" {
+		conf.Inventory.AllocationStrategy = strategy
+	}
+	if holdTTL := os.Getenv("APP_INVENTORY_HOLD_TTL"); holdTTL != "" {
+		conf.Inventory.HoldTTL = holdTTL
+	}
+}
+
+// applyPasswordEnvOverrides applies password hashing related environment variables
+func applyPasswordEnvOverrides(conf *Config) {
+	if conf.Password == nil {
+		return
+	}
+
+	if algorithm := os.Getenv("APP_PASSWORD_ALGORITHM"); algorithm != "" {
+		conf.Password.Algorithm = algorithm
+	}
+	if bcryptCost := os.Getenv("APP_PASSWORD_BCRYPT_COST"); bcryptCost != "" {
+		if val, err := strconv.Atoi(bcryptCost); err == nil {
+			conf.Password.BcryptCost = val
+		}
+	}
+}
+
+// applyAuthEnvOverrides applies authentication related environment variables
+func applyAuthEnvOverrides(conf *Config) {
+	if conf.Auth == nil {
+		return
+	}
+
+	if enabled := os.Getenv("APP_AUTH_ENABLED"); enabled != "" {
+		conf.Auth.Enabled = enabled == TrueStr
+	}
+	if secret := os.Getenv("APP_AUTH_SECRET"); secret != "" {
+		conf.Auth.Secret = secret
+	}
+	if accessTTL := os.Getenv("APP_AUTH_ACCESS_TTL"); accessTTL != "" {
+		conf.Auth.AccessTTL = accessTTL
+	}
+	if refreshTTL := os.Getenv("APP_AUTH_REFRESH_TTL"); refreshTTL != "" {
+		conf.Auth.RefreshTTL = refreshTTL
+	}
+}
+
+// applyTenantEnvOverrides applies tenant resolution related environment variables
+func applyTenantEnvOverrides(conf *Config) {
+	if conf.Tenant == nil {
+		return
+	}
+
+	if header := os.Getenv("APP_TENANT_HEADER"); header != "" {
+		conf.Tenant.Header = header
+	}
+	if baseDomain := os.Getenv("APP_TENANT_BASE_DOMAIN"); baseDomain != "" {
+		conf.Tenant.BaseDomain = baseDomain
+	}
+}
+
+// applyLockoutEnvOverrides applies login lockout related environment variables
+func applyLockoutEnvOverrides(conf *Config) {
+	if conf.Lockout == nil {
+		return
+	}
+
+	if enabled := os.Getenv("APP_LOCKOUT_ENABLED"); enabled != "" {
+		conf.Lockout.Enabled = enabled == TrueStr
+	}
+	if limit := os.Getenv("APP_LOCKOUT_MAX_ACCOUNT_FAILURES"); limit != "" {
+		if val, err := strconv.Atoi(limit); err == nil {
+			conf.Lockout.MaxAccountFailures = val
+		}
+	}
+	if limit := os.Getenv("APP_LOCKOUT_MAX_IP_FAILURES"); limit != "" {
+		if val, err := strconv.Atoi(limit); err == nil {
+			conf.Lockout.MaxIPFailures = val
+		}
+	}
+	if duration := os.Getenv("APP_LOCKOUT_DURATION"); duration != "" {
+		conf.Lockout.Duration = duration
+	}
+}
+
+// applyAccountEnvOverrides applies email verification and password reset related environment variables
+func applyAccountEnvOverrides(conf *Config) {
+	if conf.Account == nil {
+		return
+	}
+
+	if ttl := os.Getenv("APP_ACCOUNT_VERIFICATION_TTL"); ttl != "" {
+		conf.Account.VerificationTTL = ttl
+	}
+	if ttl := os.Getenv("APP_ACCOUNT_PASSWORD_RESET_TTL"); ttl != "" {
+		conf.Account.PasswordResetTTL = ttl
+	}
+	if limit := os.Getenv("APP_ACCOUNT_EMAIL_RATE_LIMIT"); limit != "" {
+		if val, err := strconv.Atoi(limit); err == nil {
+			conf.Account.EmailRateLimit = val
+		}
+	}
+	if window := os.Getenv("APP_ACCOUNT_EMAIL_RATE_WINDOW"); window != "" {
+		conf.Account.EmailRateWindow = window
+	}
+}
+
+// applyNotifierEnvOverrides applies notifier related environment variables
+func applyNotifierEnvOverrides(conf *Config) {
+	if conf.Notifier == nil {
+		return
+	}
+
+	if driver := os.Getenv("APP_NOTIFIER_DRIVER"); driver != "" {
+		conf.Notifier.Driver = driver
+	}
+	if path := os.Getenv("APP_NOTIFIER_FILE_PATH"); path != "" {
+		conf.Notifier.FilePath = path
+	}
+}
+
 func Init(path, file string) {
 	configPath := flag.String("config-path", path, "path to configuration path")
 	configFile := flag.String("config-file", file, "name of configuration file (without extension)")
@@ -40,6 +40,18 @@ func TestConfigEnvOverrides(t *testing.T) {
 	_ = os.Setenv("APP_POSTGRES_PORT", "5433")
 	_ = os.Setenv("APP_REDIS_HOST", "test-redis-host")
 	_ = os.Setenv("APP_LOG_COMPRESS", "true")
+	_ = os.Setenv("APP_INVOICE_TAX_RATE", "0.2")
+	_ = os.Setenv("APP_INVENTORY_ALLOCATION_STRATEGY", "nearest")
+	_ = os.Setenv("APP_INVENTORY_HOLD_TTL", "5m")
+	_ = os.Setenv("APP_JOBS_LOW_STOCK_SWEEP_SPEC", "0 30 * * * *")
+	_ = os.Setenv("APP_JOBS_SCHEDULED_PRICE_BATCH_SIZE", "50")
+	_ = os.Setenv("APP_JOBS_APPROVAL_ESCALATION_ESCALATE_AFTER", "48h")
+	_ = os.Setenv("APP_PASSWORD_ALGORITHM", "bcrypt")
+	_ = os.Setenv("APP_AUTH_ACCESS_TTL", "5m")
+	_ = os.Setenv("APP_ACCOUNT_EMAIL_RATE_LIMIT", "5")
+	_ = os.Setenv("APP_LOCKOUT_MAX_ACCOUNT_FAILURES", "3")
+	_ = os.Setenv("APP_NOTIFIER_DRIVER", "file")
+	_ = os.Setenv("APP_TENANT_BASE_DOMAIN", "shop.example.com")
 
 	// Load config
 	conf, err := Load("./", "config.yaml")
@@ -55,6 +67,18 @@ func TestConfigEnvOverrides(t *testing.T) {
 		_ = os.Unsetenv("APP_POSTGRES_PORT")
 		_ = os.Unsetenv("APP_REDIS_HOST")
 		_ = os.Unsetenv("APP_LOG_COMPRESS")
+		_ = os.Unsetenv("APP_INVOICE_TAX_RATE")
+		_ = os.Unsetenv("APP_INVENTORY_ALLOCATION_STRATEGY")
+		_ = os.Unsetenv("APP_INVENTORY_HOLD_TTL")
+		_ = os.Unsetenv("APP_JOBS_LOW_STOCK_SWEEP_SPEC")
+		_ = os.Unsetenv("APP_JOBS_SCHEDULED_PRICE_BATCH_SIZE")
+		_ = os.Unsetenv("APP_JOBS_APPROVAL_ESCALATION_ESCALATE_AFTER")
+		_ = os.Unsetenv("APP_PASSWORD_ALGORITHM")
+		_ = os.Unsetenv("APP_AUTH_ACCESS_TTL")
+		_ = os.Unsetenv("APP_ACCOUNT_EMAIL_RATE_LIMIT")
+		_ = os.Unsetenv("APP_LOCKOUT_MAX_ACCOUNT_FAILURES")
+		_ = os.Unsetenv("APP_NOTIFIER_DRIVER")
+		_ = os.Unsetenv("APP_TENANT_BASE_DOMAIN")
 	}()
 
 	// Verify environment variables were applied correctly
@@ -66,6 +90,24 @@ func TestConfigEnvOverrides(t *testing.T) {
 	assert.Equal(t, 5433, conf.Postgre.Port)
 	assert.Equal(t, "test-redis-host", conf.Redis.Host)
 	assert.True(t, conf.Log.Compress)
+	assert.Equal(t, 0.2, conf.Invoice.TaxRate)
+	assert.Equal(t, "nearest", conf.Inventory.AllocationStrategy)
+	assert.Equal(t, "5m", conf.Inventory.HoldTTL)
+	assert.Equal(t, "0 30 * * * *", conf.Jobs.LowStockSweep.Spec)
+	assert.Equal(t, 50, conf.Jobs.ScheduledPrice.BatchSize)
+	assert.Equal(t, "48h", conf.Jobs.ApprovalEscalation.EscalateAfter)
+	assert.Equal(t, 100, conf.Jobs.ApprovalEscalation.BatchSize)
+	assert.Equal(t, "bcrypt", conf.Password.Algorithm)
+	assert.Equal(t, 12, conf.Password.BcryptCost)
+	assert.Equal(t, "5m", conf.Auth.AccessTTL)
+	assert.Equal(t, "168h", conf.Auth.RefreshTTL)
+	assert.Equal(t, 5, conf.Account.EmailRateLimit)
+	assert.Equal(t, 3, conf.Lockout.MaxAccountFailures)
+	assert.Equal(t, 20, conf.Lockout.MaxIPFailures)
+	assert.Equal(t, "1h", conf.Account.PasswordResetTTL)
+	assert.Equal(t, "file", conf.Notifier.Driver)
+	assert.Equal(t, "X-Tenant-ID", conf.Tenant.Header)
+	assert.Equal(t, "shop.example.com", conf.Tenant.BaseDomain)
 }
 
 // TestConfigWatchChanges tests the config file change monitoring feature
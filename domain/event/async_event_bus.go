@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
 	"cactus-golang-hexagonal-microservice-boilerplate/util/errors"
 	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
 
@@ -45,12 +46,20 @@ type AsyncEventBus struct {
 	handlers   []EventHandler
 	store      EventStore
 	mu         sync.RWMutex
-	eventQueue chan Event
+	eventQueue chan queuedEvent
 	workerPool chan struct{} // Semaphore for limiting concurrent workers
 	quit       chan struct{}
 	wg         sync.WaitGroup
 }
 
+// queuedEvent is an event waiting for a worker, with the tenant it was published in, or whether it
+// was published by work that sees every tenant
+type queuedEvent struct {
+	event      Event
+	tenantID   string
+	allTenants bool
+}
+
 // AsyncEventBusConfig holds configuration for AsyncEventBus
 type AsyncEventBusConfig struct {
 	QueueSize     int
@@ -95,7 +104,7 @@ func NewAsyncEventBus(config *AsyncEventBusConfig) *AsyncEventBus {
 	bus := &AsyncEventBus{
 		handlers:   make([]EventHandler, 0),
 		store:      config.EventStore,
-		eventQueue: make(chan Event, config.QueueSize),
+		eventQueue: make(chan queuedEvent, config.QueueSize),
 		workerPool: make(chan struct{}, config.WorkerCount),
 		quit:       make(chan struct{}),
 	}
@@ -113,18 +122,23 @@ func (b *AsyncEventBus) startWorkers(errorCallback func(Event, error)) {
 		defer b.wg.Done()
 		for {
 			select {
-			case event := <-b.eventQueue:
+			case queued := <-b.eventQueue:
 				// Acquire semaphore slot
 				b.workerPool <- struct{}{}
 
 				// Process event in a new goroutine
 				b.wg.Add(1)
-				go func(evt Event) {
+				go func(evt Event, tenantID string, allTenants bool) {
 					defer b.wg.Done()
 					defer func() { <-b.workerPool }() // Release semaphore slot
 
-					// Process the event
+					// Process the event, detached from the request but in its tenant
 					ctx := context.Background()
+					if tenantID != "" {
+						ctx = model.ContextWithTenant(ctx, tenantID)
+					} else if allTenants {
+						ctx = model.ContextWithoutTenant(ctx)
+					}
 					b.mu.RLock()
 					handlers := make([]EventHandler, len(b.handlers))
 					copy(handlers, b.handlers) // Create a copy to avoid holding the lock
@@ -148,7 +162,7 @@ func (b *AsyncEventBus) startWorkers(errorCallback func(Event, error)) {
 							}
 						}
 					}
-				}(event)
+				}(queued.event, queued.tenantID, queued.allTenants)
 
 			case <-b.quit:
 				return
@@ -168,7 +182,7 @@ func (b *AsyncEventBus) Publish(ctx context.Context, event Event) error {
 
 	// Send event to the queue
 	select {
-	case b.eventQueue <- event:
+	case b.eventQueue <- queuedEvent{event: event, tenantID: model.TenantFromContext(ctx), allTenants: model.SeesAllTenants(ctx)}:
 		return nil
 	default:
 		return errors.New(errors.ErrorTypeSystem, "event queue is full")
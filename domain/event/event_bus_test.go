@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
 )
 
 // MockEvent implements the Event interface for testing
@@ -290,3 +292,39 @@ func TestInMemoryEventBus_Publish(t *testing.T) {
 		assert.Empty(t, handler3.handledEvents)
 	})
 }
+
+func TestAsyncEventBus_PublishKeepsTenant(t *testing.T) {
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		wantTenant     string
+		wantAllTenants bool
+	}{
+		{name: "tenant of the request", ctx: model.ContextWithTenant(context.Background(), "acme"), wantTenant: "acme"},
+		{name: "work across tenants", ctx: model.ContextWithoutTenant(context.Background()), wantAllTenants: true},
+		{name: "no tenant", ctx: context.Background()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewAsyncEventBus(nil)
+			defer func() { assert.NoError(t, bus.Close(time.Second)) }()
+
+			handled := make(chan context.Context, 1)
+			bus.Subscribe(NewMockHandler([]string{"test.event"}, func(ctx context.Context, _ Event) error {
+				handled <- ctx
+				return nil
+			}))
+
+			assert.NoError(t, bus.Publish(tt.ctx, MockEvent{name: "test.event", eventID: "event-123"}))
+
+			select {
+			case ctx := <-handled:
+				assert.Equal(t, tt.wantTenant, model.TenantFromContext(ctx))
+				assert.Equal(t, tt.wantAllTenants, model.SeesAllTenants(ctx))
+			case <-time.After(time.Second):
+				t.Fatal("event was not handled")
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
 	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
 )
 
@@ -35,6 +36,7 @@ func NewKafkaAuditHandler(producer KafkaProducer, topic string) *KafkaAuditHandl
 // AuditMessage represents the message sent to Kafka
 type AuditMessage struct {
 	ID         string                 `json:"id"`
+	TenantID   string                 `json:"tenant_id,omitempty"`
 	EventName  string                 `json:"event_name"`
 	EntityType string                 `json:"entity_type"`
 	EntityID   string                 `json:"entity_id"`
@@ -51,6 +53,7 @@ func (h *KafkaAuditHandler) HandleEvent(ctx context.Context, event Event) error
 
 	msg := AuditMessage{
 		ID:         event.EventID(),
+		TenantID:   model.TenantFromContext(ctx),
 		EventName:  event.EventName(),
 		EntityType: entityType,
 		EntityID:   event.AggregateID(),
@@ -98,8 +101,54 @@ func parseEventName(eventName string) (entityType string, action string) {
 		return "user", "created"
 	case "user.updated":
 		return "user", "updated"
+	case "user.password_changed":
+		return "user", "password_changed"
+	case "user.password_reset":
+		return "user", "password_reset"
+	case "user.email_verified":
+		return "user", "email_verified"
+	case "user.roles_changed":
+		return "user", "roles_changed"
 	case "user.deleted":
 		return "user", "deleted"
+	case "user.locked":
+		return "user", "locked"
+	case "user.unlocked":
+		return "user", "unlocked"
+	case "login.ip_locked":
+		return "login", "ip_locked"
+	case "user_address.created":
+		return "user_address", "created"
+	case "user_address.updated":
+		return "user_address", "updated"
+	case "user_address.deleted":
+		return "user_address", "deleted"
+	case "api_key.created":
+		return "api_key", "created"
+	case "api_key.rotated":
+		return "api_key", "rotated"
+	case "api_key.revoked":
+		return "api_key", "revoked"
+	case "organization.created":
+		return "organization", "created"
+	case "organization.updated":
+		return "organization", "updated"
+	case "organization.deleted":
+		return "organization", "deleted"
+	case "organization.member_added":
+		return "organization", "member_added"
+	case "organization.member_role_changed":
+		return "organization", "member_role_changed"
+	case "organization.member_removed":
+		return "organization", "member_removed"
+	case "organization.approval_threshold_set":
+		return "organization", "approval_threshold_set"
+	case "organization.spending_limit_set":
+		return "organization", "spending_limit_set"
+	case "organization.address_added":
+		return "organization", "address_added"
+	case "organization.address_removed":
+		return "organization", "address_removed"
 	case "product.created":
 		return "product", "created"
 	case "product.updated":
@@ -108,12 +157,78 @@ func parseEventName(eventName string) (entityType string, action string) {
 		return "product", "deleted"
 	case "product.stock_updated":
 		return "product", "stock_updated"
+	case "product.categories_assigned":
+		return "product", "categories_assigned"
+	case "product.variant_added":
+		return "product", "variant_added"
+	case "product.variant_updated":
+		return "product", "variant_updated"
+	case "product.variant_removed":
+		return "product", "variant_removed"
+	case "product.stock_drift_detected":
+		return "product", "stock_drift_detected"
+	case "product.low_stock_threshold_set":
+		return "product", "low_stock_threshold_set"
+	case "product.stock_low":
+		return "product", "stock_low"
+	case "product.price_changed":
+		return "product", "price_changed"
+	case "product.price_scheduled":
+		return "product", "price_scheduled"
+	case "category.created":
+		return "category", "created"
+	case "category.updated":
+		return "category", "updated"
+	case "category.moved":
+		return "category", "moved"
+	case "category.deleted":
+		return "category", "deleted"
+	case "warehouse.created":
+		return "warehouse", "created"
+	case "warehouse.updated":
+		return "warehouse", "updated"
+	case "stock_hold.created":
+		return "stock_hold", "created"
+	case "stock_hold.released":
+		return "stock_hold", "released"
 	case "order.created":
 		return "order", "created"
 	case "order.status_changed":
 		return "order", "status_changed"
 	case "order.cancelled":
 		return "order", "canceled"
+	case "order.approval_requested":
+		return "order", "approval_requested"
+	case "order.approved":
+		return "order", "approved"
+	case "order.rejected":
+		return "order", "rejected"
+	case "order.approval_escalated":
+		return "order", "approval_escalated"
+	case "payment.created":
+		return "payment", "created"
+	case "payment.status_changed":
+		return "payment", "status_changed"
+	case "payment.refunded":
+		return "payment", "refunded"
+	case "shipment.created":
+		return "shipment", "created"
+	case "shipment.shipped":
+		return "shipment", "shipped"
+	case "shipment.delivered":
+		return "shipment", "delivered"
+	case "invoice.issued":
+		return "invoice", "issued"
+	case "return.requested":
+		return "return", "requested"
+	case "return.approved":
+		return "return", "approved"
+	case "return.rejected":
+		return "return", "rejected"
+	case "return.received":
+		return "return", "received"
+	case "return.refunded":
+		return "return", "refunded"
 	}
 
 	return entityType, action
@@ -27,6 +27,7 @@ const (
 // AuditLog represents an audit log entry
 type AuditLog struct {
 	ID         string
+	TenantID   string // tenant of the audited change, empty for changes outside any tenant
 	EntityType string
 	EntityID   string
 	Action     string
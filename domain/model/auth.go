@@ -0,0 +1,135 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenType distinguishes access tokens from refresh tokens
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
+// Principal is the authenticated caller of a request, a user or an API key
+type Principal struct {
+	TenantID    string // tenant of the user or API key
+	UserID      string
+	Email       string
+	SessionID   string // login session the access token was issued for
+	APIKeyID    string // set instead of the user fields for API key callers
+	Roles       []Role
+	Permissions []Permission
+}
+
+// NewPrincipal returns the principal of a user in a login session
+func NewPrincipal(user *User, sessionID string) Principal {
+	return Principal{
+		TenantID:    user.TenantID,
+		UserID:      user.ID,
+		Email:       user.Email,
+		SessionID:   sessionID,
+		Roles:       user.Roles,
+		Permissions: user.Permissions,
+	}
+}
+
+// Can reports whether the principal's roles or direct permissions allow permission
+func (p *Principal) Can(permission Permission) bool {
+	return Grants(p.Roles, p.Permissions, permission)
+}
+
+// CanGrant reports whether the principal holds every permission of the roles and every permission,
+// so that it never hands out more access than it has
+func (p *Principal) CanGrant(roles []Role, permissions []Permission) bool {
+	for _, role := range roles {
+		for _, permission := range rolePermissions[role] {
+			if !p.Can(permission) {
+				return false
+			}
+		}
+	}
+	for _, permission := range permissions {
+		if !p.Can(permission) {
+			return false
+		}
+	}
+	return true
+}
+
+// TokenClaims are the claims carried by a signed token
+type TokenClaims struct {
+	ID          string // unique per token
+	Type        TokenType
+	TenantID    string
+	UserID      string
+	Email       string
+	SessionID   string // shared by every token issued for one login
+	Roles       []Role
+	Permissions []Permission
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
+}
+
+// NewTokenClaims creates the claims of a token for a principal, valid for ttl
+func NewTokenClaims(tokenType TokenType, principal Principal, ttl time.Duration) *TokenClaims {
+	now := time.Now()
+	return &TokenClaims{
+		ID:          uuid.New().String(),
+		Type:        tokenType,
+		TenantID:    principal.TenantID,
+		UserID:      principal.UserID,
+		Email:       principal.Email,
+		SessionID:   principal.SessionID,
+		Roles:       principal.Roles,
+		Permissions: principal.Permissions,
+		IssuedAt:    now,
+		ExpiresAt:   now.Add(ttl),
+	}
+}
+
+// Principal returns the principal the token was issued to
+func (c *TokenClaims) Principal() *Principal {
+	return &Principal{
+		TenantID:    c.TenantID,
+		UserID:      c.UserID,
+		Email:       c.Email,
+		SessionID:   c.SessionID,
+		Roles:       c.Roles,
+		Permissions: c.Permissions,
+	}
+}
+
+// TokenPair is the result of a login or a refresh
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// RefreshSession is a login session. Refresh tokens rotate: only the token with TokenID is valid,
+// and each refresh replaces it.
+type RefreshSession struct {
+	ID        string
+	UserID    string
+	TokenID   string
+	ExpiresAt time.Time
+}
+
+type principalContextKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying the principal
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, or nil for anonymous requests
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalContextKey{}).(*Principal)
+	return principal
+}
@@ -0,0 +1,176 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Category domain errors are defined in domain_error.go
+
+const categoryPathSeparator = "/"
+
+// Category represents a node of the catalog tree.
+// The tree is stored as a materialized path: Path lists the IDs of the category's ancestors,
+// root first, so a subtree is every category whose path starts with SubtreePath.
+type Category struct {
+	ID       string // MongoDB ObjectID
+	TenantID string // set from the request context when the category is created
+	Name     string
+	ParentID string // empty for root categories
+	Path     string // e.g. "/rootID/parentID/", "/" for root categories
+	Version  int    // incremented on every update, used for optimistic locking
+	// MovingFrom is the subtree path of a move whose descendants are not rewritten yet, empty otherwise.
+	// It is saved together with the move, so an interrupted move can be completed later.
+	MovingFrom string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	events []DomainEvent
+}
+
+// NewCategory creates a new category under parent, or a root category when parent is nil
+func NewCategory(name string, parent *Category) (*Category, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrCategoryNameRequired
+	}
+
+	category := &Category{
+		Name:      name,
+		Path:      categoryPathSeparator,
+		Version:   1,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if parent != nil {
+		category.ParentID = parent.ID
+		category.Path = parent.SubtreePath()
+	}
+
+	category.recordEvent(CategoryCreatedEvent{
+		Name:     name,
+		ParentID: category.ParentID,
+	})
+
+	return category, nil
+}
+
+// SubtreePath returns the path prefix shared by the category's descendants
+func (c *Category) SubtreePath() string {
+	return c.Path + c.ID + categoryPathSeparator
+}
+
+// AncestorIDs returns the IDs of the category's ancestors, root first
+func (c *Category) AncestorIDs() []string {
+	trimmed := strings.Trim(c.Path, categoryPathSeparator)
+	if trimmed == "" {
+		return nil
+	}
+	return strings.Split(trimmed, categoryPathSeparator)
+}
+
+// Rename changes the category name
+func (c *Category) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrCategoryNameRequired
+	}
+	if name == c.Name {
+		return nil
+	}
+
+	c.Name = name
+	c.UpdatedAt = time.Now()
+
+	c.recordEvent(CategoryUpdatedEvent{
+		ID:   c.ID,
+		Name: name,
+	})
+
+	return nil
+}
+
+// MoveTo moves the category under parent, or to the root when parent is nil.
+// The subtree path before the move is kept in MovingFrom, which descendants must be rewritten from.
+func (c *Category) MoveTo(parent *Category) error {
+	oldSubtree := c.SubtreePath()
+
+	parentID, path := "", categoryPathSeparator
+	if parent != nil {
+		if parent.ID == c.ID || strings.HasPrefix(parent.Path, oldSubtree) {
+			return ErrCategoryParentInvalid
+		}
+		parentID, path = parent.ID, parent.SubtreePath()
+	}
+	if parentID == c.ParentID {
+		return nil
+	}
+
+	oldParentID := c.ParentID
+	c.ParentID = parentID
+	c.Path = path
+	c.MovingFrom = oldSubtree
+	c.UpdatedAt = time.Now()
+
+	c.recordEvent(CategoryMovedEvent{
+		ID:          c.ID,
+		OldParentID: oldParentID,
+		NewParentID: parentID,
+	})
+
+	return nil
+}
+
+// HasPendingMove reports whether the descendants of the category still have to be moved
+func (c *Category) HasPendingMove() bool {
+	return c.MovingFrom != "" && c.MovingFrom != c.SubtreePath()
+}
+
+// MarkDeleted records the category deletion
+func (c *Category) MarkDeleted() {
+	c.recordEvent(CategoryDeletedEvent{
+		ID:       c.ID,
+		ParentID: c.ParentID,
+	})
+}
+
+// Events returns and clears domain events
+func (c *Category) Events() []DomainEvent {
+	events := c.events
+	c.events = nil
+	return events
+}
+
+func (c *Category) recordEvent(event DomainEvent) {
+	c.events = append(c.events, event)
+}
+
+// Category domain events
+type CategoryCreatedEvent struct {
+	Name     string
+	ParentID string
+}
+
+func (e CategoryCreatedEvent) EventName() string { return "category.created" }
+
+type CategoryUpdatedEvent struct {
+	ID   string
+	Name string
+}
+
+func (e CategoryUpdatedEvent) EventName() string { return "category.updated" }
+
+type CategoryMovedEvent struct {
+	ID          string
+	OldParentID string
+	NewParentID string
+}
+
+func (e CategoryMovedEvent) EventName() string { return "category.moved" }
+
+type CategoryDeletedEvent struct {
+	ID       string
+	ParentID string
+}
+
+func (e CategoryDeletedEvent) EventName() string { return "category.deleted" }
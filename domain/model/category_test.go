@@ -0,0 +1,86 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryMoveTo(t *testing.T) {
+	// root
+	// ├── electronics
+	// │   └── phones
+	// │       └── android
+	// └── books
+	root := &Category{ID: "root", Path: "/"}
+	electronics := &Category{ID: "electronics", ParentID: "root", Path: "/root/"}
+	phones := &Category{ID: "phones", ParentID: "electronics", Path: "/root/electronics/"}
+	android := &Category{ID: "android", ParentID: "phones", Path: "/root/electronics/phones/"}
+	books := &Category{ID: "books", ParentID: "root", Path: "/root/"}
+
+	tests := []struct {
+		name           string
+		category       *Category
+		parent         *Category
+		wantErr        error
+		wantPath       string
+		wantMovingFrom string
+	}{
+		{name: "under a sibling", category: phones, parent: books,
+			wantPath: "/root/books/", wantMovingFrom: "/root/electronics/phones/"},
+		{name: "to the root", category: phones,
+			wantPath: "/", wantMovingFrom: "/root/electronics/phones/"},
+		{name: "up to the grandparent", category: android, parent: electronics,
+			wantPath: "/root/electronics/", wantMovingFrom: "/root/electronics/phones/android/"},
+		{name: "under the current parent", category: phones, parent: electronics,
+			wantPath: "/root/electronics/"},
+		{name: "under itself", category: phones, parent: phones, wantErr: ErrCategoryParentInvalid},
+		{name: "under its child", category: electronics, parent: phones, wantErr: ErrCategoryParentInvalid},
+		{name: "under a deeper descendant", category: root, parent: android, wantErr: ErrCategoryParentInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category := *tt.category
+
+			err := category.MoveTo(tt.parent)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.category.Path, category.Path)
+				assert.Empty(t, category.Events())
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantPath, category.Path)
+			assert.Equal(t, tt.wantMovingFrom, category.MovingFrom)
+			if tt.wantMovingFrom == "" {
+				assert.False(t, category.HasPendingMove())
+				assert.Empty(t, category.Events())
+				return
+			}
+			assert.True(t, category.HasPendingMove())
+			assert.Len(t, category.Events(), 1)
+		})
+	}
+}
+
+func TestCategoryAncestorIDs(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "/", want: nil},
+		{path: "/root/", want: []string{"root"}},
+		{path: "/root/electronics/phones/", want: []string{"root", "electronics", "phones"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			category := &Category{ID: "c", Path: tt.path}
+			assert.Equal(t, tt.want, category.AncestorIDs())
+			assert.Equal(t, tt.path+"c/", category.SubtreePath())
+		})
+	}
+}
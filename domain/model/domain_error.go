@@ -1,6 +1,9 @@
 package model
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
 
 // DomainError represents a domain-specific error with HTTP status code mapping
 type DomainError struct {
@@ -23,6 +26,22 @@ func NewDomainError(code, message string, httpStatus int) *DomainError {
 	}
 }
 
+// RetryAfterError is a domain error the request can be retried from once RetryAfter has passed
+type RetryAfterError struct {
+	*DomainError
+	RetryAfter time.Duration
+}
+
+// NewRetryAfterError returns err to be retried after retryAfter
+func NewRetryAfterError(err *DomainError, retryAfter time.Duration) *RetryAfterError {
+	return &RetryAfterError{DomainError: err, RetryAfter: retryAfter}
+}
+
+// Unwrap returns the domain error, so errors.Is matches it
+func (e *RetryAfterError) Unwrap() error {
+	return e.DomainError
+}
+
 // Common validation error codes
 const (
 	CodeValidationError   = "VALIDATION_ERROR"
@@ -30,26 +49,150 @@ const (
 	CodeConflict          = "CONFLICT"
 	CodeInvalidState      = "INVALID_STATE"
 	CodeInsufficientStock = "INSUFFICIENT_STOCK"
+	CodeVersionConflict   = "VERSION_CONFLICT"
+)
+
+// Tenant errors
+var (
+	ErrTenantInvalid  = NewDomainError(CodeValidationError, "tenant ID is invalid", http.StatusBadRequest)
+	ErrTenantMismatch = NewDomainError("TENANT_MISMATCH", "credentials belong to another tenant", http.StatusForbidden)
+	ErrTenantMissing  = NewDomainError("TENANT_MISSING", "no tenant to scope the data to", http.StatusInternalServerError)
+)
+
+// Concurrency errors
+var (
+	ErrVersionConflict = NewDomainError(CodeVersionConflict, "resource was modified by another request", http.StatusConflict)
 )
 
 // User domain errors
 var (
-	ErrUserNotFound         = NewDomainError("USER_NOT_FOUND", "user not found", http.StatusNotFound)
-	ErrUserEmailRequired    = NewDomainError(CodeValidationError, "user email is required", http.StatusBadRequest)
-	ErrUserEmailInvalid     = NewDomainError(CodeValidationError, "user email is invalid", http.StatusBadRequest)
-	ErrUserNameRequired     = NewDomainError(CodeValidationError, "user name is required", http.StatusBadRequest)
-	ErrUserPasswordRequired = NewDomainError(CodeValidationError, "user password is required", http.StatusBadRequest)
-	ErrUserEmailTaken       = NewDomainError("EMAIL_TAKEN", "user email is already taken", http.StatusConflict)
+	ErrUserNotFound             = NewDomainError("USER_NOT_FOUND", "user not found", http.StatusNotFound)
+	ErrUserEmailRequired        = NewDomainError(CodeValidationError, "user email is required", http.StatusBadRequest)
+	ErrUserEmailInvalid         = NewDomainError(CodeValidationError, "user email is invalid", http.StatusBadRequest)
+	ErrUserNameRequired         = NewDomainError(CodeValidationError, "user name is required", http.StatusBadRequest)
+	ErrUserPasswordRequired     = NewDomainError(CodeValidationError, "user password is required", http.StatusBadRequest)
+	ErrUserPasswordTooShort     = NewDomainError(CodeValidationError, "user password must be at least 6 characters", http.StatusBadRequest)
+	ErrUserEmailTaken           = NewDomainError("EMAIL_TAKEN", "user email is already taken", http.StatusConflict)
+	ErrInvalidCredentials       = NewDomainError("INVALID_CREDENTIALS", "invalid email or password", http.StatusUnauthorized)
+	ErrUserRoleRequired         = NewDomainError(CodeValidationError, "user must have at least one role", http.StatusBadRequest)
+	ErrUserRoleInvalid          = NewDomainError(CodeValidationError, "user role is invalid", http.StatusBadRequest)
+	ErrUserPermissionInvalid    = NewDomainError(CodeValidationError, "user permission is invalid", http.StatusBadRequest)
+	ErrUserEmailNotVerified     = NewDomainError("EMAIL_NOT_VERIFIED", "user email is not verified", http.StatusForbidden)
+	ErrUserEmailAlreadyVerified = NewDomainError("EMAIL_ALREADY_VERIFIED", "user email is already verified", http.StatusConflict)
+	ErrUserRoleSelfAssignment   = NewDomainError("ROLE_SELF_ASSIGNMENT", "users cannot change their own roles", http.StatusForbidden)
+	ErrUserRoleGrantForbidden   = NewDomainError("ROLE_GRANT_FORBIDDEN", "roles and permissions can only be granted by a caller holding them", http.StatusForbidden)
+)
+
+// User address domain errors
+var (
+	ErrUserAddressNotFound = NewDomainError("USER_ADDRESS_NOT_FOUND", "address not found", http.StatusNotFound)
+)
+
+// Account domain errors
+var (
+	ErrUserTokenInvalid = NewDomainError("USER_TOKEN_INVALID", "token is invalid, expired or already used", http.StatusBadRequest)
+	ErrTooManyRequests  = NewDomainError("TOO_MANY_REQUESTS", "too many requests, try again later", http.StatusTooManyRequests)
+)
+
+// Authentication domain errors
+var (
+	ErrTokenInvalid         = NewDomainError("TOKEN_INVALID", "token is invalid", http.StatusUnauthorized)
+	ErrTokenExpired         = NewDomainError("TOKEN_EXPIRED", "token has expired", http.StatusUnauthorized)
+	ErrTokenRevoked         = NewDomainError("TOKEN_REVOKED", "token has been revoked", http.StatusUnauthorized)
+	ErrTokenGenerate        = NewDomainError("TOKEN_GENERATE_FAILED", "token could not be generated", http.StatusInternalServerError)
+	ErrAccountLocked        = NewDomainError("ACCOUNT_LOCKED", "account is temporarily locked after too many failed logins", http.StatusLocked)
+	ErrTooManyLoginAttempts = NewDomainError("TOO_MANY_LOGIN_ATTEMPTS", "too many failed logins from this address, try again later", http.StatusTooManyRequests)
+	ErrLoginThrottled       = NewDomainError("LOGIN_THROTTLED", "login failed recently, try again later", http.StatusTooManyRequests)
+)
+
+// API key domain errors
+var (
+	ErrAPIKeyNotFound       = NewDomainError("API_KEY_NOT_FOUND", "API key not found", http.StatusNotFound)
+	ErrAPIKeyNameRequired   = NewDomainError(CodeValidationError, "API key name is required", http.StatusBadRequest)
+	ErrAPIKeyScopesRequired = NewDomainError(CodeValidationError, "API key must have at least one scope", http.StatusBadRequest)
+	ErrAPIKeyScopeInvalid   = NewDomainError(CodeValidationError, "API key scope is invalid", http.StatusBadRequest)
+	ErrAPIKeyScopeForbidden = NewDomainError("API_KEY_SCOPE_FORBIDDEN", "API key scopes must be permissions the caller holds", http.StatusForbidden)
+	ErrAPIKeyExpiryInvalid  = NewDomainError(CodeValidationError, "API key expiry must be in the future", http.StatusBadRequest)
+	ErrAPIKeyRevoked        = NewDomainError("API_KEY_REVOKED", "API key has been revoked", http.StatusConflict)
+	ErrAPIKeyInvalid        = NewDomainError("API_KEY_INVALID", "API key is invalid", http.StatusUnauthorized)
+	ErrAPIKeyExpired        = NewDomainError("API_KEY_EXPIRED", "API key has expired", http.StatusUnauthorized)
+)
+
+// Organization domain errors
+var (
+	ErrOrganizationNotFound        = NewDomainError("ORGANIZATION_NOT_FOUND", "organization not found", http.StatusNotFound)
+	ErrOrganizationNameRequired    = NewDomainError(CodeValidationError, "organization name is required", http.StatusBadRequest)
+	ErrOrganizationAdminRequired   = NewDomainError(CodeValidationError, "organization must have an admin", http.StatusBadRequest)
+	ErrOrganizationRoleInvalid     = NewDomainError(CodeValidationError, "organization role must be one of buyer, approver or admin", http.StatusBadRequest)
+	ErrOrganizationMemberExists    = NewDomainError(CodeConflict, "user is already a member of the organization", http.StatusConflict)
+	ErrOrganizationMemberNotFound  = NewDomainError("ORGANIZATION_MEMBER_NOT_FOUND", "organization member not found", http.StatusNotFound)
+	ErrOrganizationAddressNotFound = NewDomainError("ORGANIZATION_ADDRESS_NOT_FOUND", "organization address not found", http.StatusNotFound)
+	ErrOrganizationLastAdmin       = NewDomainError(CodeInvalidState, "the last admin of an organization cannot be removed or demoted", http.StatusConflict)
+	ErrOrganizationOrderForbidden  = NewDomainError("ORGANIZATION_ORDER_FORBIDDEN", "user cannot order on behalf of the organization", http.StatusForbidden)
+	ErrOrganizationLimitInvalid    = NewDomainError(CodeValidationError, "approval threshold and spending limits cannot be negative", http.StatusBadRequest)
 )
 
 // Product domain errors
 var (
-	ErrProductNotFound          = NewDomainError("PRODUCT_NOT_FOUND", "product not found", http.StatusNotFound)
-	ErrProductNameRequired      = NewDomainError(CodeValidationError, "product name is required", http.StatusBadRequest)
-	ErrProductPriceInvalid      = NewDomainError(CodeValidationError, "product price must be greater than zero", http.StatusBadRequest)
-	ErrProductStockInvalid      = NewDomainError(CodeValidationError, "product stock cannot be negative", http.StatusBadRequest)
-	ErrProductStockNegative     = NewDomainError(CodeValidationError, "product stock cannot be negative", http.StatusBadRequest)
-	ErrProductInsufficientStock = NewDomainError(CodeInsufficientStock, "insufficient product stock", http.StatusConflict)
+	ErrProductNotFound                 = NewDomainError("PRODUCT_NOT_FOUND", "product not found", http.StatusNotFound)
+	ErrProductNameRequired             = NewDomainError(CodeValidationError, "product name is required", http.StatusBadRequest)
+	ErrProductPriceInvalid             = NewDomainError(CodeValidationError, "product price must be greater than zero", http.StatusBadRequest)
+	ErrProductStockInvalid             = NewDomainError(CodeValidationError, "product stock cannot be negative", http.StatusBadRequest)
+	ErrProductStockNegative            = NewDomainError(CodeValidationError, "product stock cannot be negative", http.StatusBadRequest)
+	ErrProductInsufficientStock        = NewDomainError(CodeInsufficientStock, "insufficient product stock", http.StatusConflict)
+	ErrProductLowStockThresholdInvalid = NewDomainError(CodeValidationError, "low stock threshold cannot be negative", http.StatusBadRequest)
+	ErrProductSearchPriceInvalid       = NewDomainError(CodeValidationError, "price range is invalid", http.StatusBadRequest)
+	ErrProductSearchSortInvalid        = NewDomainError(CodeValidationError, "sort must be one of relevance, price_asc, price_desc or newest", http.StatusBadRequest)
+	ErrProductFileFormatInvalid        = NewDomainError(CodeValidationError, "file format must be csv or ndjson", http.StatusBadRequest)
+)
+
+// Price history domain errors
+var (
+	ErrPriceEffectiveFromInvalid = NewDomainError(CodeValidationError, "scheduled price must take effect in the future", http.StatusBadRequest)
+	ErrPriceEntryNotScheduled    = NewDomainError(CodeInvalidState, "price entry is not scheduled", http.StatusConflict)
+)
+
+// Product variant domain errors
+var (
+	ErrVariantNotFound     = NewDomainError("VARIANT_NOT_FOUND", "product variant not found", http.StatusNotFound)
+	ErrVariantSKURequired  = NewDomainError(CodeValidationError, "variant SKU is required", http.StatusBadRequest)
+	ErrVariantSKUExists    = NewDomainError(CodeConflict, "variant SKU already exists", http.StatusConflict)
+	ErrVariantPriceInvalid = NewDomainError(CodeValidationError, "variant price cannot be negative", http.StatusBadRequest)
+	ErrVariantRequired     = NewDomainError(CodeValidationError, "product has variants, a SKU is required", http.StatusBadRequest)
+)
+
+// Stock movement domain errors
+var (
+	ErrStockMovementReasonInvalid = NewDomainError(CodeValidationError, "stock movement reason must be one of initial, order, return, manual, adjustment or import", http.StatusBadRequest)
+	ErrStockReasonNotManual       = NewDomainError(CodeValidationError, "stock changed by hand must have reason manual or adjustment", http.StatusBadRequest)
+)
+
+// Stock hold domain errors
+var (
+	ErrStockHoldNotFound        = NewDomainError("STOCK_HOLD_NOT_FOUND", "stock hold not found", http.StatusNotFound)
+	ErrStockHoldKeyRequired     = NewDomainError(CodeValidationError, "stock hold key is required", http.StatusBadRequest)
+	ErrStockHoldTTLInvalid      = NewDomainError(CodeValidationError, "stock hold TTL must be greater than zero", http.StatusBadRequest)
+	ErrStockHoldItemsRequired   = NewDomainError(CodeValidationError, "stock hold must have at least one item", http.StatusBadRequest)
+	ErrStockHoldItemInvalid     = NewDomainError(CodeValidationError, "stock hold item product is required", http.StatusBadRequest)
+	ErrStockHoldQuantityInvalid = NewDomainError(CodeValidationError, "stock hold quantity must be greater than zero", http.StatusBadRequest)
+	ErrStockHoldExists          = NewDomainError(CodeConflict, "an active stock hold already exists for this key", http.StatusConflict)
+)
+
+// Warehouse domain errors
+var (
+	ErrWarehouseNotFound     = NewDomainError("WAREHOUSE_NOT_FOUND", "warehouse not found", http.StatusNotFound)
+	ErrWarehouseCodeRequired = NewDomainError(CodeValidationError, "warehouse code is required", http.StatusBadRequest)
+	ErrWarehouseNameRequired = NewDomainError(CodeValidationError, "warehouse name is required", http.StatusBadRequest)
+	ErrWarehouseCodeExists   = NewDomainError(CodeConflict, "warehouse code already exists", http.StatusConflict)
+	ErrLocationInvalid       = NewDomainError(CodeValidationError, "latitude must be within [-90, 90] and longitude within [-180, 180]", http.StatusBadRequest)
+)
+
+// Category domain errors
+var (
+	ErrCategoryNotFound      = NewDomainError("CATEGORY_NOT_FOUND", "category not found", http.StatusNotFound)
+	ErrCategoryNameRequired  = NewDomainError(CodeValidationError, "category name is required", http.StatusBadRequest)
+	ErrCategoryParentInvalid = NewDomainError(CodeValidationError, "category cannot be moved under itself or one of its descendants", http.StatusBadRequest)
+	ErrCategoryHasChildren   = NewDomainError(CodeInvalidState, "category has subcategories and cannot be deleted", http.StatusConflict)
 )
 
 // Order domain errors
@@ -57,12 +200,67 @@ var (
 	ErrOrderNotFound         = NewDomainError("ORDER_NOT_FOUND", "order not found", http.StatusNotFound)
 	ErrOrderUserRequired     = NewDomainError(CodeValidationError, "order user is required", http.StatusBadRequest)
 	ErrOrderItemsRequired    = NewDomainError(CodeValidationError, "order must have at least one item", http.StatusBadRequest)
+	ErrOrderItemPriceInvalid = NewDomainError(CodeValidationError, "order item price must be greater than zero", http.StatusBadRequest)
+	ErrOrderItemPriceChanged = NewDomainError(CodeConflict, "order item price does not match the current product price", http.StatusConflict)
 	ErrOrderInvalidStatus    = NewDomainError(CodeInvalidState, "invalid order status transition", http.StatusBadRequest)
 	ErrOrderAlreadyCancelled = NewDomainError(CodeInvalidState, "order is already canceled", http.StatusConflict)
 	ErrOrderCannotCancel     = NewDomainError(CodeInvalidState, "order cannot be canceled in current status", http.StatusConflict)
 	ErrOrderCannotConfirm    = NewDomainError(CodeInvalidState, "order cannot be confirmed in current status", http.StatusConflict)
 	ErrOrderCannotShip       = NewDomainError(CodeInvalidState, "order cannot be shipped in current status", http.StatusConflict)
 	ErrOrderCannotDeliver    = NewDomainError(CodeInvalidState, "order cannot be delivered in current status", http.StatusConflict)
+	ErrOrderNotReturnable    = NewDomainError(CodeInvalidState, "order items can only be returned after delivery", http.StatusConflict)
+	ErrOrderNotShippable     = NewDomainError(CodeInvalidState, "order cannot be shipped in current status", http.StatusConflict)
+)
+
+// Order approval domain errors
+var (
+	ErrOrderNotAwaitingApproval = NewDomainError(CodeInvalidState, "order is not awaiting approval", http.StatusConflict)
+	ErrOrderApprovalEscalated   = NewDomainError(CodeInvalidState, "order approval was already escalated", http.StatusConflict)
+	ErrOrderSelfApproval        = NewDomainError("ORDER_SELF_APPROVAL", "buyers cannot approve their own orders", http.StatusForbidden)
+	ErrOrderApprovalForbidden   = NewDomainError("ORDER_APPROVAL_FORBIDDEN", "only approvers and admins of the organization can decide on its orders", http.StatusForbidden)
+)
+
+// Payment domain errors
+var (
+	ErrPaymentNotFound                = NewDomainError("PAYMENT_NOT_FOUND", "payment not found", http.StatusNotFound)
+	ErrPaymentOrderRequired           = NewDomainError(CodeValidationError, "payment order is required", http.StatusBadRequest)
+	ErrPaymentAmountInvalid           = NewDomainError(CodeValidationError, "payment amount must be greater than zero", http.StatusBadRequest)
+	ErrPaymentRefundAmountInvalid     = NewDomainError(CodeValidationError, "refund amount exceeds the refundable amount", http.StatusBadRequest)
+	ErrPaymentInvalidStatus           = NewDomainError(CodeInvalidState, "invalid payment status transition", http.StatusConflict)
+	ErrPaymentAlreadyExists           = NewDomainError(CodeConflict, "order already has an active payment", http.StatusConflict)
+	ErrPaymentOrderNotPayable         = NewDomainError(CodeInvalidState, "order cannot be paid in current status", http.StatusConflict)
+	ErrPaymentDeclined                = NewDomainError("PAYMENT_DECLINED", "payment was declined by the gateway", http.StatusPaymentRequired)
+	ErrPaymentWebhookSignatureInvalid = NewDomainError("INVALID_SIGNATURE", "payment webhook signature is invalid", http.StatusUnauthorized)
+	ErrPaymentWebhookPayloadInvalid   = NewDomainError(CodeValidationError, "payment webhook payload is invalid", http.StatusBadRequest)
+)
+
+// Shipment domain errors
+var (
+	ErrShipmentNotFound         = NewDomainError("SHIPMENT_NOT_FOUND", "shipment not found", http.StatusNotFound)
+	ErrShipmentCarrierRequired  = NewDomainError(CodeValidationError, "shipment carrier is required", http.StatusBadRequest)
+	ErrShipmentTrackingRequired = NewDomainError(CodeValidationError, "shipment tracking number is required", http.StatusBadRequest)
+	ErrShipmentItemsRequired    = NewDomainError(CodeValidationError, "shipment must have at least one item", http.StatusBadRequest)
+	ErrShipmentItemInvalid      = NewDomainError(CodeValidationError, "shipment item does not belong to the order", http.StatusBadRequest)
+	ErrShipmentQuantityInvalid  = NewDomainError(CodeValidationError, "shipment quantity exceeds the quantity still unshipped", http.StatusBadRequest)
+	ErrShipmentInvalidStatus    = NewDomainError(CodeInvalidState, "invalid shipment status transition", http.StatusConflict)
+	ErrShippingRateNotFound     = NewDomainError("SHIPPING_RATE_NOT_FOUND", "no rate available for the carrier and service", http.StatusBadRequest)
+)
+
+// Return domain errors
+var (
+	ErrReturnNotFound        = NewDomainError("RETURN_NOT_FOUND", "return request not found", http.StatusNotFound)
+	ErrReturnItemsRequired   = NewDomainError(CodeValidationError, "return request must have at least one item", http.StatusBadRequest)
+	ErrReturnItemInvalid     = NewDomainError(CodeValidationError, "return item does not belong to the order", http.StatusBadRequest)
+	ErrReturnQuantityInvalid = NewDomainError(CodeValidationError, "return quantity exceeds the quantity still returnable", http.StatusBadRequest)
+	ErrReturnInvalidStatus   = NewDomainError(CodeInvalidState, "invalid return status transition", http.StatusConflict)
+)
+
+// Invoice domain errors
+var (
+	ErrInvoiceNotFound             = NewDomainError("INVOICE_NOT_FOUND", "invoice not found", http.StatusNotFound)
+	ErrInvoiceOrderNotDelivered    = NewDomainError(CodeInvalidState, "only delivered orders can be invoiced", http.StatusConflict)
+	ErrInvoiceTaxRateInvalid       = NewDomainError(CodeValidationError, "invoice tax rate cannot be negative", http.StatusBadRequest)
+	ErrCreditNoteReturnNotRefunded = NewDomainError(CodeInvalidState, "credit notes can only be issued for refunded returns", http.StatusConflict)
 )
 
 // Audit domain errors
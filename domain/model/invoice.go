@@ -0,0 +1,192 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invoice domain errors are defined in domain_error.go
+
+// InvoiceKind distinguishes invoices from credit notes
+type InvoiceKind string
+
+const (
+	InvoiceKindInvoice    InvoiceKind = "invoice"
+	InvoiceKindCreditNote InvoiceKind = "credit_note"
+)
+
+// Invoice represents a numbered fiscal document for an order, or a credit note for a return.
+// Order prices are tax-inclusive, so the invoice total always matches the amount charged.
+type Invoice struct {
+	ID                string
+	TenantID          string // the tenant of the order
+	Number            string // assigned by the repository from a gap-free sequence per tenant and year
+	Kind              InvoiceKind
+	OrderID           string
+	UserID            string
+	ReturnID          string // set on credit notes only
+	OriginalInvoiceID string // set on credit notes only
+	Lines             []InvoiceLine
+	Subtotal          float64
+	TaxTotal          float64
+	Total             float64
+	IssuedAt          time.Time
+	CreatedAt         time.Time
+
+	events []DomainEvent
+}
+
+// InvoiceLine represents a line item of an invoice
+type InvoiceLine struct {
+	ID          string
+	InvoiceID   string
+	ProductID   string
+	SKU         string // invoiced variant, empty for products without variants
+	Description string
+	Quantity    int
+	UnitPrice   float64 // tax-inclusive unit price
+	TaxRate     float64
+	Subtotal    float64 // net amount
+	Tax         float64
+	Total       float64
+}
+
+// NewInvoice creates an invoice for a delivered order.
+// descriptions maps product IDs to line descriptions; missing products are described by their ID.
+func NewInvoice(order *Order, descriptions map[string]string, taxRate float64) (*Invoice, error) {
+	if !order.IsInvoiceable() {
+		return nil, ErrInvoiceOrderNotDelivered
+	}
+	if taxRate < 0 {
+		return nil, ErrInvoiceTaxRateInvalid
+	}
+
+	invoice := newInvoice(InvoiceKindInvoice, order.ID, order.UserID)
+	invoice.TenantID = order.TenantID
+	for _, item := range order.Items {
+		invoice.addLine(item.ProductID, item.SKU, describe(descriptions, item.ProductID), item.Quantity, item.Price, taxRate)
+	}
+
+	if len(invoice.Lines) == 0 {
+		return nil, ErrOrderItemsRequired
+	}
+
+	invoice.recordEvent(InvoiceIssuedEvent{
+		InvoiceID: invoice.ID,
+		OrderID:   order.ID,
+		Kind:      string(invoice.Kind),
+		Total:     invoice.Total,
+	})
+
+	return invoice, nil
+}
+
+// NewCreditNote creates a credit note crediting the items of a refunded return against the original invoice
+func NewCreditNote(original *Invoice, rma *ReturnRequest) (*Invoice, error) {
+	if original.Kind != InvoiceKindInvoice || original.OrderID != rma.OrderID {
+		return nil, ErrInvoiceNotFound
+	}
+	if rma.Status != ReturnStatusRefunded {
+		return nil, ErrCreditNoteReturnNotRefunded
+	}
+
+	// Variants of a product can be invoiced on separate lines, so lines are matched by product and SKU
+	lines := make(map[invoiceLineKey]InvoiceLine, len(original.Lines))
+	for _, line := range original.Lines {
+		lines[invoiceLineKey{productID: line.ProductID, sku: line.SKU}] = line
+	}
+
+	note := newInvoice(InvoiceKindCreditNote, original.OrderID, original.UserID)
+	note.TenantID = original.TenantID
+	note.ReturnID = rma.ID
+	note.OriginalInvoiceID = original.ID
+	for _, item := range rma.Items {
+		line := lines[invoiceLineKey{productID: item.ProductID, sku: item.SKU}]
+		description := line.Description
+		if description == "" {
+			description = item.ProductID
+		}
+		note.addLine(item.ProductID, item.SKU, description, item.Quantity, item.Price, line.TaxRate)
+	}
+
+	note.recordEvent(InvoiceIssuedEvent{
+		InvoiceID: note.ID,
+		OrderID:   note.OrderID,
+		Kind:      string(note.Kind),
+		Total:     note.Total,
+	})
+
+	return note, nil
+}
+
+func newInvoice(kind InvoiceKind, orderID, userID string) *Invoice {
+	now := time.Now()
+	return &Invoice{
+		ID:        uuid.New().String(),
+		Kind:      kind,
+		OrderID:   orderID,
+		UserID:    userID,
+		IssuedAt:  now,
+		CreatedAt: now,
+	}
+}
+
+// addLine adds a line for a tax-inclusive unit price, splitting it into net amount and tax
+func (i *Invoice) addLine(productID, sku, description string, quantity int, unitPrice, taxRate float64) {
+	total := roundAmount(unitPrice * float64(quantity))
+	subtotal := roundAmount(total / (1 + taxRate))
+	tax := roundAmount(total - subtotal)
+
+	i.Lines = append(i.Lines, InvoiceLine{
+		ID:          uuid.New().String(),
+		InvoiceID:   i.ID,
+		ProductID:   productID,
+		SKU:         sku,
+		Description: description,
+		Quantity:    quantity,
+		UnitPrice:   unitPrice,
+		TaxRate:     taxRate,
+		Subtotal:    subtotal,
+		Tax:         tax,
+		Total:       total,
+	})
+
+	i.Subtotal = roundAmount(i.Subtotal + subtotal)
+	i.TaxTotal = roundAmount(i.TaxTotal + tax)
+	i.Total = roundAmount(i.Total + total)
+}
+
+// Events returns and clears domain events
+func (i *Invoice) Events() []DomainEvent {
+	events := i.events
+	i.events = nil
+	return events
+}
+
+func (i *Invoice) recordEvent(event DomainEvent) {
+	i.events = append(i.events, event)
+}
+
+// invoiceLineKey identifies the invoice line of an ordered product variant
+type invoiceLineKey struct {
+	productID string
+	sku       string
+}
+
+func describe(descriptions map[string]string, productID string) string {
+	if description, ok := descriptions[productID]; ok && description != "" {
+		return description
+	}
+	return productID
+}
+
+// Invoice domain events
+type InvoiceIssuedEvent struct {
+	InvoiceID string
+	OrderID   string
+	Kind      string
+	Total     float64
+}
+
+func (e InvoiceIssuedEvent) EventName() string { return "invoice.issued" }
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCreditNoteMatchesVariantLines(t *testing.T) {
+	original := newInvoice(InvoiceKindInvoice, "order-1", "user-1")
+	original.addLine("product-1", "TSHIRT-S", "T-shirt S", 1, 10, 0.1)
+	original.addLine("product-1", "TSHIRT-L", "T-shirt L", 1, 12, 0.2)
+
+	rma := &ReturnRequest{
+		ID:      "return-1",
+		OrderID: "order-1",
+		Status:  ReturnStatusRefunded,
+		Items: []ReturnItem{
+			{ProductID: "product-1", SKU: "TSHIRT-L", Quantity: 1, Price: 12},
+		},
+	}
+
+	note, err := NewCreditNote(original, rma)
+	require.NoError(t, err)
+	require.Len(t, note.Lines, 1)
+
+	line := note.Lines[0]
+	assert.Equal(t, "TSHIRT-L", line.SKU)
+	assert.Equal(t, "T-shirt L", line.Description)
+	assert.Equal(t, 0.2, line.TaxRate)
+	assert.Equal(t, 12.0, line.Total)
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Lockout errors are defined in domain_error.go
+
+// Lockout domain events, published when failed logins lock an account or a client address
+type UserLockedEvent struct {
+	ID          string
+	Email       string
+	Failures    int
+	LockedUntil time.Time
+}
+
+func (e UserLockedEvent) EventName() string { return "user.locked" }
+
+type UserUnlockedEvent struct {
+	ID         string
+	UnlockedBy string // ID of the user or API key who lifted the lockout
+}
+
+func (e UserUnlockedEvent) EventName() string { return "user.unlocked" }
+
+type LoginIPLockedEvent struct {
+	IP          string
+	Failures    int
+	LockedUntil time.Time
+}
+
+func (e LoginIPLockedEvent) EventName() string { return "login.ip_locked" }
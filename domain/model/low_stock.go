@@ -0,0 +1,81 @@
+package model
+
+import "time"
+
+// SetLowStockThreshold sets the stock level at or below which a SKU of the product is low.
+// Zero disables low-stock alerts.
+func (p *Product) SetLowStockThreshold(threshold int) error {
+	if threshold < 0 {
+		return ErrProductLowStockThresholdInvalid
+	}
+
+	p.LowStockThreshold = threshold
+	p.UpdatedAt = time.Now()
+
+	p.recordEvent(ProductLowStockThresholdSetEvent{
+		ID:        p.ID,
+		Threshold: threshold,
+	})
+
+	return nil
+}
+
+// LowStockLevels returns the stock of the SKUs at or below the low-stock threshold,
+// keyed by the empty SKU for products without variants
+func (p *Product) LowStockLevels() map[string]int {
+	if p.LowStockThreshold <= 0 {
+		return nil
+	}
+
+	low := make(map[string]int)
+	for sku, stock := range p.StockLevels() {
+		if stock <= p.LowStockThreshold {
+			low[sku] = stock
+		}
+	}
+	return low
+}
+
+// RecordLowStock records a low-stock event for every SKU at or below the threshold
+func (p *Product) RecordLowStock() {
+	for sku, stock := range p.LowStockLevels() {
+		p.recordStockLow(sku, stock)
+	}
+}
+
+// checkLowStock records a low-stock event when a stock change crosses the threshold downwards
+func (p *Product) checkLowStock(sku string, oldStock, newStock int) {
+	if p.LowStockThreshold > 0 && oldStock > p.LowStockThreshold && newStock <= p.LowStockThreshold {
+		p.recordStockLow(sku, newStock)
+	}
+}
+
+func (p *Product) recordStockLow(sku string, stock int) {
+	p.recordEvent(ProductStockLowEvent{
+		ProductID: p.ID,
+		SKU:       sku,
+		Stock:     stock,
+		Threshold: p.LowStockThreshold,
+	})
+}
+
+// Low stock domain events
+type ProductLowStockThresholdSetEvent struct {
+	ID        string
+	Threshold int
+}
+
+func (e ProductLowStockThresholdSetEvent) EventName() string {
+	return "product.low_stock_threshold_set"
+}
+
+type ProductStockLowEvent struct {
+	ProductID string
+	SKU       string
+	Stock     int
+	Threshold int
+}
+
+func (e ProductStockLowEvent) EventName() string {
+	return "product.stock_low"
+}
@@ -0,0 +1,8 @@
+package model
+
+// Notification is a message sent to a user, such as an email
+type Notification struct {
+	To      string
+	Subject string
+	Body    string
+}
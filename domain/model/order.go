@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
 )
 
 // Order domain errors are defined in domain_error.go
@@ -12,23 +14,35 @@ import (
 type OrderStatus string
 
 const (
-	OrderStatusPending   OrderStatus = "pending"
-	OrderStatusConfirmed OrderStatus = "confirmed"
-	OrderStatusShipped   OrderStatus = "shipped"
-	OrderStatusDelivered OrderStatus = "delivered"
-	OrderStatusCancelled OrderStatus = "canceled"
+	OrderStatusAwaitingApproval OrderStatus = "awaiting_approval"
+	OrderStatusPending          OrderStatus = "pending"
+	OrderStatusConfirmed        OrderStatus = "confirmed"
+	OrderStatusPartiallyShipped OrderStatus = "partially_shipped"
+	OrderStatusShipped          OrderStatus = "shipped"
+	OrderStatusDelivered        OrderStatus = "delivered"
+	OrderStatusCancelled        OrderStatus = "canceled"
+
+	OrderStatusPartiallyReturned OrderStatus = "partially_returned"
+	OrderStatusReturned          OrderStatus = "returned"
 )
 
 // Order represents an order in the system
 type Order struct {
-	ID        string
-	UserID    string
-	Items     []OrderItem
-	Total     float64
-	Status    OrderStatus
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt *time.Time
+	ID              string
+	TenantID        string // set from the request context when the order is created
+	UserID          string
+	OrganizationID  string // organization the order is placed on behalf of, empty for personal orders
+	Items           []OrderItem
+	Total           float64
+	Status          OrderStatus
+	ShipTo          *Location      // where the order ships to, used to pick the nearest warehouses
+	ShippingAddress *vo.Address    // copied when the order is created, later address book edits do not change it
+	BillingAddress  *vo.Address    // copied when the order is created, like ShippingAddress
+	Approval        *OrderApproval // set for organization orders that needed approval
+	Version         int            // incremented on every update, used for optimistic locking
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       *time.Time
 
 	events []DomainEvent
 }
@@ -38,20 +52,28 @@ type OrderItem struct {
 	ID        string
 	OrderID   string
 	ProductID string
+	SKU       string // ordered variant, empty for products without variants
 	Quantity  int
 	Price     float64
-	CreatedAt time.Time
+	// PriceVersion is the product price version the price was taken from, zero when not priced from the catalog.
+	// It covers variant prices too, so with the SKU it identifies an entry of the price history.
+	PriceVersion int
+	CreatedAt    time.Time
+
+	Allocations []StockAllocation // warehouses the item is fulfilled from, set when stock is reserved
 }
 
-// NewOrder creates a new order with validation
-func NewOrder(userID string, items []OrderItem) (*Order, error) {
+// NewOrder creates a new order with validation. organizationID is empty for personal orders.
+func NewOrder(userID, organizationID string, items []OrderItem) (*Order, error) {
 	orderID := uuid.New().String()
 	order := &Order{
-		ID:        orderID,
-		UserID:    userID,
-		Status:    OrderStatusPending,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:             orderID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Status:         OrderStatusPending,
+		Version:        1,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
 	}
 
 	// Assign IDs to items
@@ -70,9 +92,10 @@ func NewOrder(userID string, items []OrderItem) (*Order, error) {
 	order.calculateTotal()
 
 	order.recordEvent(OrderCreatedEvent{
-		UserID:     userID,
-		ItemCount:  len(items),
-		TotalValue: order.Total,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		ItemCount:      len(items),
+		TotalValue:     order.Total,
 	})
 
 	return order, nil
@@ -88,6 +111,12 @@ func (o *Order) Validate() error {
 		return ErrOrderItemsRequired
 	}
 
+	for _, item := range o.Items {
+		if item.Price <= 0 {
+			return ErrOrderItemPriceInvalid
+		}
+	}
+
 	return nil
 }
 
@@ -118,59 +147,174 @@ func (o *Order) Confirm() error {
 	return nil
 }
 
-// Ship marks the order as shipped
-func (o *Order) Ship() error {
-	if o.Status != OrderStatusConfirmed {
+// Cancel cancels the order. Orders with items that already left the warehouse cannot be cancelled,
+// their items are returned instead once delivered.
+func (o *Order) Cancel() error {
+	if o.Status == OrderStatusCancelled {
+		return ErrOrderAlreadyCancelled
+	}
+
+	if o.IsInTransit() {
+		return ErrOrderCannotCancel
+	}
+
+	if o.IsReturnable() || o.Status == OrderStatusReturned {
 		return ErrOrderInvalidStatus
 	}
 
-	o.Status = OrderStatusShipped
+	oldStatus := o.Status
+	o.Status = OrderStatusCancelled
 	o.UpdatedAt = time.Now()
 
-	o.recordEvent(OrderStatusChangedEvent{
+	o.recordEvent(OrderCancelledEvent{
 		OrderID:   o.ID,
-		OldStatus: string(OrderStatusConfirmed),
-		NewStatus: string(OrderStatusShipped),
+		OldStatus: string(oldStatus),
 	})
 
 	return nil
 }
 
-// Deliver marks the order as delivered
-func (o *Order) Deliver() error {
-	if o.Status != OrderStatusShipped {
-		return ErrOrderInvalidStatus
+// StockAllocated reports whether stock was already reserved from warehouses for the items
+func (o *Order) StockAllocated() bool {
+	for _, item := range o.Items {
+		if len(item.Allocations) > 0 {
+			return true
+		}
 	}
+	return false
+}
+
+// ReturnAllocations splits a returned quantity of the item over the warehouses it was allocated from,
+// skipping the alreadyReturned units taken back by earlier returns. Units beyond the allocations are
+// returned with an empty WarehouseID, as the stock not held at a warehouse they came from.
+func (i OrderItem) ReturnAllocations(alreadyReturned, quantity int) []StockAllocation {
+	var allocations []StockAllocation
+	skip := alreadyReturned
+	for _, allocation := range i.Allocations {
+		if quantity == 0 {
+			break
+		}
+		available := allocation.Quantity
+		if skip >= available {
+			skip -= available
+			continue
+		}
+		available -= skip
+		skip = 0
+
+		taken := min(available, quantity)
+		allocations = append(allocations, StockAllocation{WarehouseID: allocation.WarehouseID, Quantity: taken})
+		quantity -= taken
+	}
+	if quantity > 0 {
+		allocations = append(allocations, StockAllocation{Quantity: quantity})
+	}
+	return allocations
+}
+
+// IsInTransit reports whether some or all items of the order were handed over to a carrier and the
+// order is not delivered yet
+func (o *Order) IsInTransit() bool {
+	return o.Status == OrderStatusPartiallyShipped || o.Status == OrderStatusShipped
+}
 
-	o.Status = OrderStatusDelivered
+// IsShippable reports whether items of the order can still be packed into shipments
+func (o *Order) IsShippable() bool {
+	return o.Status == OrderStatusConfirmed || o.Status == OrderStatusPartiallyShipped
+}
+
+// ApplyShipments derives the order status from the fulfillment status of its shipped items:
+// partially shipped, shipped once every item left the warehouse, delivered once every item arrived.
+func (o *Order) ApplyShipments(shipments []*Shipment) error {
+	if !o.IsShippable() && o.Status != OrderStatusShipped {
+		return ErrOrderNotShippable
+	}
+
+	shipped := make(map[string]int, len(o.Items))
+	delivered := make(map[string]int, len(o.Items))
+	for _, shipment := range shipments {
+		for _, item := range shipment.Items {
+			switch item.Status {
+			case FulfillmentStatusDelivered:
+				delivered[item.OrderItemID] += item.Quantity
+				shipped[item.OrderItemID] += item.Quantity
+			case FulfillmentStatusShipped:
+				shipped[item.OrderItemID] += item.Quantity
+			}
+		}
+	}
+
+	allShipped, allDelivered, anyShipped := true, true, false
+	for _, item := range o.Items {
+		if shipped[item.ID] > 0 {
+			anyShipped = true
+		}
+		if shipped[item.ID] < item.Quantity {
+			allShipped = false
+		}
+		if delivered[item.ID] < item.Quantity {
+			allDelivered = false
+		}
+	}
+
+	newStatus := o.Status
+	switch {
+	case allDelivered:
+		newStatus = OrderStatusDelivered
+	case allShipped:
+		newStatus = OrderStatusShipped
+	case anyShipped:
+		newStatus = OrderStatusPartiallyShipped
+	}
+	if newStatus == o.Status {
+		return nil
+	}
+
+	oldStatus := o.Status
+	o.Status = newStatus
 	o.UpdatedAt = time.Now()
 
 	o.recordEvent(OrderStatusChangedEvent{
 		OrderID:   o.ID,
-		OldStatus: string(OrderStatusShipped),
-		NewStatus: string(OrderStatusDelivered),
+		OldStatus: string(oldStatus),
+		NewStatus: string(newStatus),
 	})
 
 	return nil
 }
 
-// Cancel cancels the order
-func (o *Order) Cancel() error {
-	if o.Status == OrderStatusCancelled {
-		return ErrOrderAlreadyCancelled
+// IsReturnable reports whether items of the order can still be returned
+func (o *Order) IsReturnable() bool {
+	return o.Status == OrderStatusDelivered || o.Status == OrderStatusPartiallyReturned
+}
+
+// IsInvoiceable reports whether the order has been delivered and can be invoiced
+func (o *Order) IsInvoiceable() bool {
+	return o.IsReturnable() || o.Status == OrderStatusReturned
+}
+
+// MarkReturned moves a delivered order to partially or fully returned
+func (o *Order) MarkReturned(fully bool) error {
+	if !o.IsReturnable() {
+		return ErrOrderNotReturnable
 	}
 
-	if o.Status == OrderStatusDelivered {
-		return ErrOrderInvalidStatus
+	newStatus := OrderStatusPartiallyReturned
+	if fully {
+		newStatus = OrderStatusReturned
+	}
+	if newStatus == o.Status {
+		return nil
 	}
 
 	oldStatus := o.Status
-	o.Status = OrderStatusCancelled
+	o.Status = newStatus
 	o.UpdatedAt = time.Now()
 
-	o.recordEvent(OrderCancelledEvent{
+	o.recordEvent(OrderStatusChangedEvent{
 		OrderID:   o.ID,
 		OldStatus: string(oldStatus),
+		NewStatus: string(newStatus),
 	})
 
 	return nil
@@ -199,9 +343,10 @@ func (i *OrderItem) TableName() string {
 
 // Order domain events
 type OrderCreatedEvent struct {
-	UserID     string
-	ItemCount  int
-	TotalValue float64
+	UserID         string
+	OrganizationID string
+	ItemCount      int
+	TotalValue     float64
 }
 
 func (e OrderCreatedEvent) EventName() string { return "order.created" }
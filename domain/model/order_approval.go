@@ -0,0 +1,175 @@
+package model
+
+import "time"
+
+// Order approval domain errors are defined in domain_error.go
+
+// ApprovalDecision is the decision taken on an order awaiting approval
+type ApprovalDecision string
+
+const (
+	ApprovalDecisionApproved ApprovalDecision = "approved"
+	ApprovalDecisionRejected ApprovalDecision = "rejected"
+)
+
+// OrderApproval records the sign-off of an organization order placed above the spending limit of
+// its buyer. It is nil for orders that needed no approval.
+type OrderApproval struct {
+	Decision    ApprovalDecision // empty while the order awaits approval
+	DecidedBy   string           // ID of the approver
+	DecidedAt   *time.Time
+	Reason      string     // given with a rejection
+	EscalatedAt *time.Time // set when the approval was escalated to the organization admins
+}
+
+// RequireApproval holds a new order until an approver signs it off
+func (o *Order) RequireApproval() error {
+	if o.Status != OrderStatusPending || o.Approval != nil {
+		return ErrOrderInvalidStatus
+	}
+
+	o.Status = OrderStatusAwaitingApproval
+	o.Approval = &OrderApproval{}
+	o.UpdatedAt = time.Now()
+
+	o.recordEvent(OrderApprovalRequestedEvent{
+		OrderID:        o.ID,
+		OrganizationID: o.OrganizationID,
+		UserID:         o.UserID,
+		Total:          o.Total,
+	})
+
+	return nil
+}
+
+// IsAwaitingApproval reports whether the order waits for an approver's sign-off
+func (o *Order) IsAwaitingApproval() bool {
+	return o.Status == OrderStatusAwaitingApproval
+}
+
+// Approve signs the order off, it becomes pending and follows the usual flow. Buyers cannot
+// approve their own orders.
+func (o *Order) Approve(approverID string) error {
+	if err := o.decide(approverID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	o.Status = OrderStatusPending
+	o.Approval.Decision = ApprovalDecisionApproved
+	o.Approval.DecidedBy = approverID
+	o.Approval.DecidedAt = &now
+	o.UpdatedAt = now
+
+	o.recordEvent(OrderApprovedEvent{
+		OrderID:        o.ID,
+		OrganizationID: o.OrganizationID,
+		ApproverID:     approverID,
+	})
+	o.recordEvent(OrderStatusChangedEvent{
+		OrderID:   o.ID,
+		OldStatus: string(OrderStatusAwaitingApproval),
+		NewStatus: string(OrderStatusPending),
+	})
+
+	return nil
+}
+
+// Reject turns the order down, which cancels it
+func (o *Order) Reject(approverID, reason string) error {
+	if err := o.decide(approverID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	o.Status = OrderStatusCancelled
+	o.Approval.Decision = ApprovalDecisionRejected
+	o.Approval.DecidedBy = approverID
+	o.Approval.DecidedAt = &now
+	o.Approval.Reason = reason
+	o.UpdatedAt = now
+
+	o.recordEvent(OrderRejectedEvent{
+		OrderID:        o.ID,
+		OrganizationID: o.OrganizationID,
+		ApproverID:     approverID,
+		Reason:         reason,
+	})
+	o.recordEvent(OrderCancelledEvent{
+		OrderID:   o.ID,
+		OldStatus: string(OrderStatusAwaitingApproval),
+	})
+
+	return nil
+}
+
+// EscalateApproval hands an approval that waited too long over to the organization admins.
+// An approval is escalated once.
+func (o *Order) EscalateApproval() error {
+	if !o.IsAwaitingApproval() {
+		return ErrOrderNotAwaitingApproval
+	}
+	if o.Approval.EscalatedAt != nil {
+		return ErrOrderApprovalEscalated
+	}
+
+	now := time.Now()
+	o.Approval.EscalatedAt = &now
+	o.UpdatedAt = now
+
+	o.recordEvent(OrderApprovalEscalatedEvent{
+		OrderID:        o.ID,
+		OrganizationID: o.OrganizationID,
+		UserID:         o.UserID,
+		Total:          o.Total,
+	})
+
+	return nil
+}
+
+// decide checks that the approver can decide on the order
+func (o *Order) decide(approverID string) error {
+	if !o.IsAwaitingApproval() {
+		return ErrOrderNotAwaitingApproval
+	}
+	if approverID == o.UserID {
+		return ErrOrderSelfApproval
+	}
+	return nil
+}
+
+// Order approval domain events
+type OrderApprovalRequestedEvent struct {
+	OrderID        string
+	OrganizationID string
+	UserID         string
+	Total          float64
+}
+
+func (e OrderApprovalRequestedEvent) EventName() string { return "order.approval_requested" }
+
+type OrderApprovedEvent struct {
+	OrderID        string
+	OrganizationID string
+	ApproverID     string
+}
+
+func (e OrderApprovedEvent) EventName() string { return "order.approved" }
+
+type OrderRejectedEvent struct {
+	OrderID        string
+	OrganizationID string
+	ApproverID     string
+	Reason         string
+}
+
+func (e OrderRejectedEvent) EventName() string { return "order.rejected" }
+
+type OrderApprovalEscalatedEvent struct {
+	OrderID        string
+	OrganizationID string
+	UserID         string
+	Total          float64
+}
+
+func (e OrderApprovalEscalatedEvent) EventName() string { return "order.approval_escalated" }
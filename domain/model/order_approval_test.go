@@ -0,0 +1,176 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newApprovalTestOrder(t *testing.T) *Order {
+	t.Helper()
+	order, err := NewOrder("buyer-1", "org-1", []OrderItem{{ProductID: "product-1", Quantity: 2, Price: 50}})
+	require.NoError(t, err)
+	order.Events()
+	return order
+}
+
+func TestOrderRequireApproval(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(o *Order)
+		wantErr error
+	}{
+		{name: "pending order", prepare: func(o *Order) {}},
+		{name: "already awaiting approval", prepare: func(o *Order) { _ = o.RequireApproval() }, wantErr: ErrOrderInvalidStatus},
+		{name: "confirmed order", prepare: func(o *Order) { o.Status = OrderStatusConfirmed }, wantErr: ErrOrderInvalidStatus},
+		{name: "approved order back to pending", prepare: func(o *Order) {
+			_ = o.RequireApproval()
+			_ = o.Approve("approver-1")
+		}, wantErr: ErrOrderInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newApprovalTestOrder(t)
+			tt.prepare(order)
+			order.Events()
+
+			err := order.RequireApproval()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, order.Events())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, OrderStatusAwaitingApproval, order.Status)
+			require.NotNil(t, order.Approval)
+			assert.Empty(t, order.Approval.Decision)
+
+			events := order.Events()
+			require.Len(t, events, 1)
+			assert.Equal(t, "order.approval_requested", events[0].EventName())
+		})
+	}
+}
+
+func TestOrderApprovalDecisions(t *testing.T) {
+	tests := []struct {
+		name         string
+		awaiting     bool
+		approverID   string
+		reject       bool
+		wantErr      error
+		wantStatus   OrderStatus
+		wantDecision ApprovalDecision
+		wantEvents   []string
+	}{
+		{
+			name: "approve", awaiting: true, approverID: "approver-1",
+			wantStatus: OrderStatusPending, wantDecision: ApprovalDecisionApproved,
+			wantEvents: []string{"order.approved", "order.status_changed"},
+		},
+		{
+			name: "reject", awaiting: true, approverID: "approver-1", reject: true,
+			wantStatus: OrderStatusCancelled, wantDecision: ApprovalDecisionRejected,
+			wantEvents: []string{"order.rejected", "order.cancelled"},
+		},
+		{
+			name: "buyer cannot approve own order", awaiting: true, approverID: "buyer-1",
+			wantErr: ErrOrderSelfApproval, wantStatus: OrderStatusAwaitingApproval,
+		},
+		{
+			name: "buyer cannot reject own order", awaiting: true, approverID: "buyer-1", reject: true,
+			wantErr: ErrOrderSelfApproval, wantStatus: OrderStatusAwaitingApproval,
+		},
+		{
+			name: "approve order not awaiting approval", approverID: "approver-1",
+			wantErr: ErrOrderNotAwaitingApproval, wantStatus: OrderStatusPending,
+		},
+		{
+			name: "reject order not awaiting approval", approverID: "approver-1", reject: true,
+			wantErr: ErrOrderNotAwaitingApproval, wantStatus: OrderStatusPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newApprovalTestOrder(t)
+			if tt.awaiting {
+				require.NoError(t, order.RequireApproval())
+				order.Events()
+			}
+
+			var err error
+			if tt.reject {
+				err = order.Reject(tt.approverID, "over budget")
+			} else {
+				err = order.Approve(tt.approverID)
+			}
+
+			assert.Equal(t, tt.wantStatus, order.Status)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, order.Events())
+				if order.Approval != nil {
+					assert.Empty(t, order.Approval.Decision)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDecision, order.Approval.Decision)
+			assert.Equal(t, tt.approverID, order.Approval.DecidedBy)
+			assert.NotNil(t, order.Approval.DecidedAt)
+			if tt.reject {
+				assert.Equal(t, "over budget", order.Approval.Reason)
+			}
+
+			var names []string
+			for _, e := range order.Events() {
+				names = append(names, e.EventName())
+			}
+			assert.Equal(t, tt.wantEvents, names)
+		})
+	}
+}
+
+func TestOrderEscalateApproval(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, o *Order)
+		wantErr error
+	}{
+		{name: "awaiting approval", prepare: func(t *testing.T, o *Order) { require.NoError(t, o.RequireApproval()) }},
+		{name: "already escalated", prepare: func(t *testing.T, o *Order) {
+			require.NoError(t, o.RequireApproval())
+			require.NoError(t, o.EscalateApproval())
+		}, wantErr: ErrOrderApprovalEscalated},
+		{name: "not awaiting approval", prepare: func(t *testing.T, o *Order) {}, wantErr: ErrOrderNotAwaitingApproval},
+		{name: "already decided", prepare: func(t *testing.T, o *Order) {
+			require.NoError(t, o.RequireApproval())
+			require.NoError(t, o.Reject("approver-1", ""))
+		}, wantErr: ErrOrderNotAwaitingApproval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newApprovalTestOrder(t)
+			tt.prepare(t, order)
+			order.Events()
+
+			err := order.EscalateApproval()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, order.Events())
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, order.Approval.EscalatedAt)
+			assert.Equal(t, OrderStatusAwaitingApproval, order.Status)
+
+			events := order.Events()
+			require.Len(t, events, 1)
+			assert.Equal(t, "order.approval_escalated", events[0].EventName())
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderCancel(t *testing.T) {
+	tests := []struct {
+		status  OrderStatus
+		wantErr error
+	}{
+		{status: OrderStatusAwaitingApproval},
+		{status: OrderStatusPending},
+		{status: OrderStatusConfirmed},
+		{status: OrderStatusPartiallyShipped, wantErr: ErrOrderCannotCancel},
+		{status: OrderStatusShipped, wantErr: ErrOrderCannotCancel},
+		{status: OrderStatusDelivered, wantErr: ErrOrderInvalidStatus},
+		{status: OrderStatusPartiallyReturned, wantErr: ErrOrderInvalidStatus},
+		{status: OrderStatusReturned, wantErr: ErrOrderInvalidStatus},
+		{status: OrderStatusCancelled, wantErr: ErrOrderAlreadyCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			order := &Order{ID: "order-1", Status: tt.status}
+
+			err := order.Cancel()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.status, order.Status)
+				assert.Empty(t, order.Events())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, OrderStatusCancelled, order.Status)
+			assert.Equal(t, []DomainEvent{OrderCancelledEvent{OrderID: "order-1", OldStatus: string(tt.status)}}, order.Events())
+		})
+	}
+}
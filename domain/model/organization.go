@@ -0,0 +1,409 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
+)
+
+// Organization domain errors are defined in domain_error.go
+
+// OrganizationRole is the role of a member in an organization
+type OrganizationRole string
+
+const (
+	// OrganizationRoleBuyer places orders on behalf of the organization
+	OrganizationRoleBuyer OrganizationRole = "buyer"
+	// OrganizationRoleApprover approves or rejects the orders of the organization
+	OrganizationRoleApprover OrganizationRole = "approver"
+	// OrganizationRoleAdmin manages the members and addresses, and places orders
+	OrganizationRoleAdmin OrganizationRole = "admin"
+)
+
+// IsValid reports whether the role is a known organization role
+func (r OrganizationRole) IsValid() bool {
+	switch r {
+	case OrganizationRoleBuyer, OrganizationRoleApprover, OrganizationRoleAdmin:
+		return true
+	}
+	return false
+}
+
+// Organization is a business account whose members order on its behalf
+type Organization struct {
+	ID                string
+	TenantID          string // set from the request context when the organization is created
+	Name              string
+	Members           []OrganizationMember
+	Addresses         []OrganizationAddress // shared by the members of the organization
+	ApprovalThreshold *float64              // order total above which orders need approval, nil when they never do
+	Version           int                   // incremented on every update, used for optimistic locking
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+
+	events []DomainEvent
+}
+
+// OrganizationMember is a user belonging to an organization
+type OrganizationMember struct {
+	UserID        string
+	Role          OrganizationRole
+	JoinedAt      time.Time
+	SpendingLimit *float64 // overrides the approval threshold of the organization for this member
+}
+
+// OrganizationAddress is an address shared by the members of an organization
+type OrganizationAddress struct {
+	ID    string
+	Label string // e.g. "Head office"
+	vo.Address
+}
+
+// NewOrganization creates a new organization with adminID as its first admin
+func NewOrganization(name, adminID string) (*Organization, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrOrganizationNameRequired
+	}
+	if adminID == "" {
+		return nil, ErrOrganizationAdminRequired
+	}
+
+	now := time.Now()
+	org := &Organization{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Members:   []OrganizationMember{{UserID: adminID, Role: OrganizationRoleAdmin, JoinedAt: now}},
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	org.recordEvent(OrganizationCreatedEvent{
+		ID:      org.ID,
+		Name:    name,
+		AdminID: adminID,
+	})
+
+	return org, nil
+}
+
+// Rename changes the organization name
+func (o *Organization) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrOrganizationNameRequired
+	}
+
+	o.Name = name
+	o.UpdatedAt = time.Now()
+
+	o.recordEvent(OrganizationUpdatedEvent{
+		ID:   o.ID,
+		Name: name,
+	})
+
+	return nil
+}
+
+// Member returns the membership of a user, or nil when the user is not a member
+func (o *Organization) Member(userID string) *OrganizationMember {
+	for i := range o.Members {
+		if o.Members[i].UserID == userID {
+			return &o.Members[i]
+		}
+	}
+	return nil
+}
+
+// HasRole reports whether the user is a member with one of the roles
+func (o *Organization) HasRole(userID string, roles ...OrganizationRole) bool {
+	member := o.Member(userID)
+	return member != nil && slices.Contains(roles, member.Role)
+}
+
+// CanOrder reports whether the user may place orders on behalf of the organization
+func (o *Organization) CanOrder(userID string) bool {
+	return o.HasRole(userID, OrganizationRoleBuyer, OrganizationRoleAdmin)
+}
+
+// CanApprove reports whether the user may approve or reject the orders of the organization
+func (o *Organization) CanApprove(userID string) bool {
+	return o.HasRole(userID, OrganizationRoleApprover, OrganizationRoleAdmin)
+}
+
+// RequiresApproval reports whether an order of the user for total needs approval. The spending limit
+// of the member applies when set, the approval threshold of the organization otherwise.
+func (o *Organization) RequiresApproval(userID string, total float64) bool {
+	limit := o.ApprovalThreshold
+	if member := o.Member(userID); member != nil && member.SpendingLimit != nil {
+		limit = member.SpendingLimit
+	}
+	return limit != nil && total > *limit
+}
+
+// SetApprovalThreshold sets the order total above which orders need approval, nil disables approvals
+func (o *Organization) SetApprovalThreshold(threshold *float64) error {
+	if threshold != nil && *threshold < 0 {
+		return ErrOrganizationLimitInvalid
+	}
+
+	o.ApprovalThreshold = threshold
+	o.UpdatedAt = time.Now()
+
+	o.recordEvent(OrganizationApprovalThresholdSetEvent{
+		ID:        o.ID,
+		Threshold: threshold,
+	})
+
+	return nil
+}
+
+// SetSpendingLimit sets the order total above which the orders of a member need approval,
+// nil falls back to the approval threshold of the organization
+func (o *Organization) SetSpendingLimit(userID string, limit *float64) error {
+	if limit != nil && *limit < 0 {
+		return ErrOrganizationLimitInvalid
+	}
+	member := o.Member(userID)
+	if member == nil {
+		return ErrOrganizationMemberNotFound
+	}
+
+	member.SpendingLimit = limit
+	o.UpdatedAt = time.Now()
+
+	o.recordEvent(OrganizationSpendingLimitSetEvent{
+		ID:     o.ID,
+		UserID: userID,
+		Limit:  limit,
+	})
+
+	return nil
+}
+
+// AddMember adds a user to the organization
+func (o *Organization) AddMember(userID string, role OrganizationRole) error {
+	if !role.IsValid() {
+		return ErrOrganizationRoleInvalid
+	}
+	if o.Member(userID) != nil {
+		return ErrOrganizationMemberExists
+	}
+
+	now := time.Now()
+	o.Members = append(o.Members, OrganizationMember{UserID: userID, Role: role, JoinedAt: now})
+	o.UpdatedAt = now
+
+	o.recordEvent(OrganizationMemberAddedEvent{
+		ID:     o.ID,
+		UserID: userID,
+		Role:   role,
+	})
+
+	return nil
+}
+
+// ChangeMemberRole changes the role of a member. The last admin cannot be demoted.
+func (o *Organization) ChangeMemberRole(userID string, role OrganizationRole) error {
+	if !role.IsValid() {
+		return ErrOrganizationRoleInvalid
+	}
+	member := o.Member(userID)
+	if member == nil {
+		return ErrOrganizationMemberNotFound
+	}
+	if member.Role == role {
+		return nil
+	}
+	if member.Role == OrganizationRoleAdmin && o.adminCount() == 1 {
+		return ErrOrganizationLastAdmin
+	}
+
+	oldRole := member.Role
+	member.Role = role
+	o.UpdatedAt = time.Now()
+
+	o.recordEvent(OrganizationMemberRoleChangedEvent{
+		ID:      o.ID,
+		UserID:  userID,
+		OldRole: oldRole,
+		NewRole: role,
+	})
+
+	return nil
+}
+
+// RemoveMember removes a member from the organization. The last admin cannot be removed.
+func (o *Organization) RemoveMember(userID string) error {
+	member := o.Member(userID)
+	if member == nil {
+		return ErrOrganizationMemberNotFound
+	}
+	if member.Role == OrganizationRoleAdmin && o.adminCount() == 1 {
+		return ErrOrganizationLastAdmin
+	}
+
+	o.Members = slices.DeleteFunc(o.Members, func(m OrganizationMember) bool {
+		return m.UserID == userID
+	})
+	o.UpdatedAt = time.Now()
+
+	o.recordEvent(OrganizationMemberRemovedEvent{
+		ID:     o.ID,
+		UserID: userID,
+	})
+
+	return nil
+}
+
+// AddAddress adds a shared address and returns it with its new ID
+func (o *Organization) AddAddress(address OrganizationAddress) (*OrganizationAddress, error) {
+	if err := address.Validate(); err != nil {
+		return nil, err
+	}
+
+	address.ID = uuid.New().String()
+	o.Addresses = append(o.Addresses, address)
+	o.UpdatedAt = time.Now()
+
+	o.recordEvent(OrganizationAddressAddedEvent{
+		ID:        o.ID,
+		AddressID: address.ID,
+	})
+
+	return &o.Addresses[len(o.Addresses)-1], nil
+}
+
+// RemoveAddress removes a shared address
+func (o *Organization) RemoveAddress(addressID string) error {
+	index := slices.IndexFunc(o.Addresses, func(a OrganizationAddress) bool {
+		return a.ID == addressID
+	})
+	if index < 0 {
+		return ErrOrganizationAddressNotFound
+	}
+
+	o.Addresses = slices.Delete(o.Addresses, index, index+1)
+	o.UpdatedAt = time.Now()
+
+	o.recordEvent(OrganizationAddressRemovedEvent{
+		ID:        o.ID,
+		AddressID: addressID,
+	})
+
+	return nil
+}
+
+// MarkDeleted records the organization deletion
+func (o *Organization) MarkDeleted() {
+	o.recordEvent(OrganizationDeletedEvent{
+		ID: o.ID,
+	})
+}
+
+// Events returns and clears domain events
+func (o *Organization) Events() []DomainEvent {
+	events := o.events
+	o.events = nil
+	return events
+}
+
+func (o *Organization) recordEvent(event DomainEvent) {
+	o.events = append(o.events, event)
+}
+
+func (o *Organization) adminCount() int {
+	count := 0
+	for _, member := range o.Members {
+		if member.Role == OrganizationRoleAdmin {
+			count++
+		}
+	}
+	return count
+}
+
+// Organization domain events
+type OrganizationCreatedEvent struct {
+	ID      string
+	Name    string
+	AdminID string
+}
+
+func (e OrganizationCreatedEvent) EventName() string { return "organization.created" }
+
+type OrganizationUpdatedEvent struct {
+	ID   string
+	Name string
+}
+
+func (e OrganizationUpdatedEvent) EventName() string { return "organization.updated" }
+
+type OrganizationDeletedEvent struct {
+	ID string
+}
+
+func (e OrganizationDeletedEvent) EventName() string { return "organization.deleted" }
+
+type OrganizationMemberAddedEvent struct {
+	ID     string
+	UserID string
+	Role   OrganizationRole
+}
+
+func (e OrganizationMemberAddedEvent) EventName() string { return "organization.member_added" }
+
+type OrganizationMemberRoleChangedEvent struct {
+	ID      string
+	UserID  string
+	OldRole OrganizationRole
+	NewRole OrganizationRole
+}
+
+func (e OrganizationMemberRoleChangedEvent) EventName() string {
+	return "organization.member_role_changed"
+}
+
+type OrganizationMemberRemovedEvent struct {
+	ID     string
+	UserID string
+}
+
+func (e OrganizationMemberRemovedEvent) EventName() string { return "organization.member_removed" }
+
+type OrganizationApprovalThresholdSetEvent struct {
+	ID        string
+	Threshold *float64
+}
+
+func (e OrganizationApprovalThresholdSetEvent) EventName() string {
+	return "organization.approval_threshold_set"
+}
+
+type OrganizationSpendingLimitSetEvent struct {
+	ID     string
+	UserID string
+	Limit  *float64
+}
+
+func (e OrganizationSpendingLimitSetEvent) EventName() string {
+	return "organization.spending_limit_set"
+}
+
+type OrganizationAddressAddedEvent struct {
+	ID        string
+	AddressID string
+}
+
+func (e OrganizationAddressAddedEvent) EventName() string { return "organization.address_added" }
+
+type OrganizationAddressRemovedEvent struct {
+	ID        string
+	AddressID string
+}
+
+func (e OrganizationAddressRemovedEvent) EventName() string { return "organization.address_removed" }
@@ -0,0 +1,224 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newApprovalTestOrganization(t *testing.T) *Organization {
+	t.Helper()
+	threshold := 1000.0
+	limit := 200.0
+	org := &Organization{ID: "org-1", ApprovalThreshold: &threshold}
+	require.NoError(t, org.AddMember("buyer-1", OrganizationRoleBuyer))
+	require.NoError(t, org.AddMember("buyer-2", OrganizationRoleBuyer))
+	require.NoError(t, org.AddMember("approver-1", OrganizationRoleApprover))
+	require.NoError(t, org.AddMember("admin-1", OrganizationRoleAdmin))
+	org.Member("buyer-2").SpendingLimit = &limit
+	return org
+}
+
+func TestOrganizationRequiresApproval(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		total        float64
+		noThreshold  bool
+		wantApproval bool
+	}{
+		{name: "below the organization threshold", userID: "buyer-1", total: 999},
+		{name: "at the organization threshold", userID: "buyer-1", total: 1000},
+		{name: "above the organization threshold", userID: "buyer-1", total: 1000.01, wantApproval: true},
+		{name: "spending limit overrides the threshold", userID: "buyer-2", total: 500, wantApproval: true},
+		{name: "within the spending limit", userID: "buyer-2", total: 200},
+		{name: "spending limit applies without a threshold", userID: "buyer-2", total: 500, noThreshold: true, wantApproval: true},
+		{name: "no threshold and no spending limit", userID: "buyer-1", total: 1e6, noThreshold: true},
+		{name: "non-member falls back to the threshold", userID: "stranger", total: 1500, wantApproval: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := newApprovalTestOrganization(t)
+			if tt.noThreshold {
+				org.ApprovalThreshold = nil
+			}
+			assert.Equal(t, tt.wantApproval, org.RequiresApproval(tt.userID, tt.total))
+		})
+	}
+}
+
+func TestOrganizationCanApprove(t *testing.T) {
+	tests := []struct {
+		userID string
+		want   bool
+	}{
+		{userID: "approver-1", want: true},
+		{userID: "admin-1", want: true},
+		{userID: "buyer-1", want: false},
+		{userID: "stranger", want: false},
+	}
+
+	org := newApprovalTestOrganization(t)
+	for _, tt := range tests {
+		t.Run(tt.userID, func(t *testing.T) {
+			assert.Equal(t, tt.want, org.CanApprove(tt.userID))
+		})
+	}
+}
+
+func TestOrganizationMembership(t *testing.T) {
+	tests := []struct {
+		name      string
+		change    func(org *Organization) error
+		wantErr   error
+		wantRoles map[string]OrganizationRole // members after the change, nil to skip
+	}{
+		{
+			name:      "adds a buyer",
+			change:    func(org *Organization) error { return org.AddMember("buyer-3", OrganizationRoleBuyer) },
+			wantRoles: map[string]OrganizationRole{"buyer-3": OrganizationRoleBuyer, "admin-1": OrganizationRoleAdmin},
+		},
+		{
+			name:    "rejects an unknown role",
+			change:  func(org *Organization) error { return org.AddMember("buyer-3", "owner") },
+			wantErr: ErrOrganizationRoleInvalid,
+		},
+		{
+			name:    "rejects a member twice",
+			change:  func(org *Organization) error { return org.AddMember("buyer-1", OrganizationRoleApprover) },
+			wantErr: ErrOrganizationMemberExists,
+		},
+		{
+			name:      "promotes a buyer",
+			change:    func(org *Organization) error { return org.ChangeMemberRole("buyer-1", OrganizationRoleAdmin) },
+			wantRoles: map[string]OrganizationRole{"buyer-1": OrganizationRoleAdmin},
+		},
+		{
+			name:    "keeps the last admin",
+			change:  func(org *Organization) error { return org.ChangeMemberRole("admin-1", OrganizationRoleBuyer) },
+			wantErr: ErrOrganizationLastAdmin,
+		},
+		{
+			name: "demotes an admin when another is left",
+			change: func(org *Organization) error {
+				require.NoError(t, org.ChangeMemberRole("approver-1", OrganizationRoleAdmin))
+				return org.ChangeMemberRole("admin-1", OrganizationRoleBuyer)
+			},
+			wantRoles: map[string]OrganizationRole{"admin-1": OrganizationRoleBuyer, "approver-1": OrganizationRoleAdmin},
+		},
+		{
+			name:    "changes the role of members only",
+			change:  func(org *Organization) error { return org.ChangeMemberRole("stranger", OrganizationRoleBuyer) },
+			wantErr: ErrOrganizationMemberNotFound,
+		},
+		{
+			name:      "removes a buyer",
+			change:    func(org *Organization) error { return org.RemoveMember("buyer-1") },
+			wantRoles: map[string]OrganizationRole{"buyer-1": "", "buyer-2": OrganizationRoleBuyer},
+		},
+		{
+			name:    "cannot remove the last admin",
+			change:  func(org *Organization) error { return org.RemoveMember("admin-1") },
+			wantErr: ErrOrganizationLastAdmin,
+		},
+		{
+			name:    "removes members only",
+			change:  func(org *Organization) error { return org.RemoveMember("stranger") },
+			wantErr: ErrOrganizationMemberNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := newApprovalTestOrganization(t)
+			org.Events()
+			before := append([]OrganizationMember(nil), org.Members...)
+
+			err := tt.change(org)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, before, org.Members)
+				assert.Empty(t, org.Events())
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, org.Events())
+			for userID, role := range tt.wantRoles {
+				if role == "" {
+					assert.Nil(t, org.Member(userID), userID)
+					continue
+				}
+				require.NotNil(t, org.Member(userID), userID)
+				assert.Equal(t, role, org.Member(userID).Role, userID)
+			}
+		})
+	}
+}
+
+func TestOrganizationCanOrder(t *testing.T) {
+	tests := []struct {
+		userID string
+		want   bool
+	}{
+		{userID: "buyer-1", want: true},
+		{userID: "admin-1", want: true},
+		{userID: "approver-1", want: false},
+		{userID: "stranger", want: false},
+	}
+
+	org := newApprovalTestOrganization(t)
+	for _, tt := range tests {
+		t.Run(tt.userID, func(t *testing.T) {
+			assert.Equal(t, tt.want, org.CanOrder(tt.userID))
+		})
+	}
+}
+
+func TestOrganizationLimits(t *testing.T) {
+	negative := -1.0
+	zero := 0.0
+	limit := 50.0
+
+	tests := []struct {
+		name    string
+		change  func(org *Organization) error
+		wantErr error
+		userID  string // defaults to buyer-1
+		// wantApproval is whether an order of the user for 500 needs approval afterwards
+		wantApproval bool
+	}{
+		{name: "sets a spending limit", change: func(org *Organization) error { return org.SetSpendingLimit("buyer-1", &limit) }, wantApproval: true},
+		{name: "zero limit approves every order", change: func(org *Organization) error { return org.SetSpendingLimit("buyer-1", &zero) }, wantApproval: true},
+		{name: "rejects a negative spending limit", change: func(org *Organization) error { return org.SetSpendingLimit("buyer-1", &negative) }, wantErr: ErrOrganizationLimitInvalid},
+		{name: "sets limits of members only", change: func(org *Organization) error { return org.SetSpendingLimit("stranger", &limit) }, wantErr: ErrOrganizationMemberNotFound},
+		{name: "clearing the spending limit falls back to the threshold", change: func(org *Organization) error { return org.SetSpendingLimit("buyer-2", nil) }, userID: "buyer-2"},
+		{name: "lowers the threshold", change: func(org *Organization) error { return org.SetApprovalThreshold(&limit) }, wantApproval: true},
+		{name: "rejects a negative threshold", change: func(org *Organization) error { return org.SetApprovalThreshold(&negative) }, wantErr: ErrOrganizationLimitInvalid},
+		{name: "disables approvals", change: func(org *Organization) error { return org.SetApprovalThreshold(nil) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := newApprovalTestOrganization(t)
+			org.Events()
+
+			err := tt.change(org)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, org.Events())
+				assert.Nil(t, org.Member("buyer-1").SpendingLimit)
+				assert.Equal(t, 1000.0, *org.ApprovalThreshold)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, org.Events())
+			userID := tt.userID
+			if userID == "" {
+				userID = "buyer-1"
+			}
+			assert.Equal(t, tt.wantApproval, org.RequiresApproval(userID, 500))
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payment domain errors are defined in domain_error.go
+
+// PaymentStatus represents the status of a payment
+type PaymentStatus string
+
+const (
+	PaymentStatusPending    PaymentStatus = "pending"
+	PaymentStatusAuthorized PaymentStatus = "authorized"
+	PaymentStatusCaptured   PaymentStatus = "captured"
+	PaymentStatusVoided     PaymentStatus = "voided"
+	PaymentStatusRefunded   PaymentStatus = "refunded"
+	PaymentStatusFailed     PaymentStatus = "failed"
+)
+
+// Payment represents a payment for an order
+type Payment struct {
+	ID             string
+	OrderID        string
+	Amount         float64
+	RefundedAmount float64
+	Status         PaymentStatus
+	GatewayRef     string // transaction reference assigned by the payment gateway
+	FailureReason  string
+	// PendingOperation is the "<operation>:<reference>" of a gateway call that failed, reused as its
+	// idempotency key when the operation is retried, since the gateway may have applied it anyway
+	PendingOperation string
+	Version          int // incremented on every update, used for optimistic locking
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+
+	events []DomainEvent
+}
+
+// PaymentWebhookEvent is a gateway notification translated into domain terms
+type PaymentWebhookEvent struct {
+	EventID    string
+	GatewayRef string
+	Status     PaymentStatus
+	Amount     float64 // cumulative refunded amount for refund notifications
+	Reason     string
+}
+
+// NewPayment creates a new pending payment for an order
+func NewPayment(orderID string, amount float64) (*Payment, error) {
+	payment := &Payment{
+		ID:        uuid.New().String(),
+		OrderID:   orderID,
+		Amount:    amount,
+		Status:    PaymentStatusPending,
+		Version:   1,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := payment.Validate(); err != nil {
+		return nil, err
+	}
+
+	payment.recordEvent(PaymentCreatedEvent{
+		PaymentID: payment.ID,
+		OrderID:   orderID,
+		Amount:    amount,
+	})
+
+	return payment, nil
+}
+
+// Validate validates the payment entity
+func (p *Payment) Validate() error {
+	if p.OrderID == "" {
+		return ErrPaymentOrderRequired
+	}
+
+	if p.Amount <= 0 {
+		return ErrPaymentAmountInvalid
+	}
+
+	return nil
+}
+
+// IsActive reports whether the payment still holds or has taken the customer's funds
+func (p *Payment) IsActive() bool {
+	return p.Status == PaymentStatusPending || p.Status == PaymentStatusAuthorized || p.Status == PaymentStatusCaptured
+}
+
+// RefundableAmount returns the captured amount that has not been refunded yet
+func (p *Payment) RefundableAmount() float64 {
+	return roundAmount(p.Amount - p.RefundedAmount)
+}
+
+// Authorize marks the payment as authorized by the gateway
+func (p *Payment) Authorize(gatewayRef string) error {
+	if p.Status != PaymentStatusPending {
+		return ErrPaymentInvalidStatus
+	}
+
+	p.GatewayRef = gatewayRef
+	p.transition(PaymentStatusAuthorized)
+
+	return nil
+}
+
+// Fail marks the payment as failed
+func (p *Payment) Fail(reason string) error {
+	if p.Status != PaymentStatusPending {
+		return ErrPaymentInvalidStatus
+	}
+
+	p.FailureReason = reason
+	p.transition(PaymentStatusFailed)
+
+	return nil
+}
+
+// Capture captures the authorized amount
+func (p *Payment) Capture() error {
+	if p.Status != PaymentStatusAuthorized {
+		return ErrPaymentInvalidStatus
+	}
+
+	p.transition(PaymentStatusCaptured)
+
+	return nil
+}
+
+// Void releases an authorization without capturing it
+func (p *Payment) Void() error {
+	if p.Status != PaymentStatusAuthorized {
+		return ErrPaymentInvalidStatus
+	}
+
+	p.transition(PaymentStatusVoided)
+
+	return nil
+}
+
+// Refund refunds part or all of a captured payment.
+// The payment becomes refunded once the whole amount has been returned.
+func (p *Payment) Refund(amount float64) error {
+	if p.Status != PaymentStatusCaptured {
+		return ErrPaymentInvalidStatus
+	}
+
+	if amount <= 0 || roundAmount(amount) > p.RefundableAmount() {
+		return ErrPaymentRefundAmountInvalid
+	}
+
+	p.RefundedAmount = roundAmount(p.RefundedAmount + amount)
+	p.UpdatedAt = time.Now()
+
+	p.recordEvent(PaymentRefundedEvent{
+		PaymentID:      p.ID,
+		OrderID:        p.OrderID,
+		Amount:         amount,
+		RefundedAmount: p.RefundedAmount,
+	})
+
+	if p.RefundableAmount() == 0 {
+		p.transition(PaymentStatusRefunded)
+	}
+
+	return nil
+}
+
+// transition moves the payment to a new status and records the change
+func (p *Payment) transition(status PaymentStatus) {
+	oldStatus := p.Status
+	p.Status = status
+	p.UpdatedAt = time.Now()
+
+	p.recordEvent(PaymentStatusChangedEvent{
+		PaymentID: p.ID,
+		OrderID:   p.OrderID,
+		OldStatus: string(oldStatus),
+		NewStatus: string(status),
+	})
+}
+
+// Events returns and clears domain events
+func (p *Payment) Events() []DomainEvent {
+	events := p.events
+	p.events = nil
+	return events
+}
+
+func (p *Payment) recordEvent(event DomainEvent) {
+	p.events = append(p.events, event)
+}
+
+// roundAmount rounds a monetary amount to cents
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// Payment domain events
+type PaymentCreatedEvent struct {
+	PaymentID string
+	OrderID   string
+	Amount    float64
+}
+
+func (e PaymentCreatedEvent) EventName() string { return "payment.created" }
+
+type PaymentStatusChangedEvent struct {
+	PaymentID string
+	OrderID   string
+	OldStatus string
+	NewStatus string
+}
+
+func (e PaymentStatusChangedEvent) EventName() string { return "payment.status_changed" }
+
+type PaymentRefundedEvent struct {
+	PaymentID      string
+	OrderID        string
+	Amount         float64
+	RefundedAmount float64
+}
+
+func (e PaymentRefundedEvent) EventName() string { return "payment.refunded" }
@@ -0,0 +1,157 @@
+package model
+
+import (
+	"time"
+)
+
+// PriceEntryStatus represents the status of a price history entry
+type PriceEntryStatus string
+
+const (
+	PriceEntryScheduled  PriceEntryStatus = "scheduled"  // takes effect at EffectiveFrom
+	PriceEntryActive     PriceEntryStatus = "active"     // the current price
+	PriceEntrySuperseded PriceEntryStatus = "superseded" // was effective until EffectiveTo
+)
+
+// PriceEntry is an entry of the price history of a product: a price and the window it was effective in
+type PriceEntry struct {
+	ID            string
+	TenantID      string // tenant of the product
+	ProductID     string
+	SKU           string // variant the price is of, empty for the product price
+	Version       int    // price version of the product, zero until a scheduled price is applied
+	Price         float64
+	Status        PriceEntryStatus
+	EffectiveFrom time.Time
+	EffectiveTo   *time.Time // nil while scheduled or active
+	CreatedAt     time.Time
+}
+
+// Prices returns the price the product is sold at by SKU: the product price under the empty SKU
+// and the price of every variant, its override or else the product price
+func (p *Product) Prices() map[string]float64 {
+	prices := make(map[string]float64, len(p.Variants)+1)
+	prices[""] = p.Price
+	for _, variant := range p.Variants {
+		prices[variant.SKU], _ = p.PriceOf(variant.SKU)
+	}
+	return prices
+}
+
+// CurrentPriceEntries returns the active history entries of the prices that differ from previous,
+// as returned by Prices; with nil previous, of every price
+func (p *Product) CurrentPriceEntries(previous map[string]float64) []*PriceEntry {
+	var entries []*PriceEntry
+	for sku, price := range p.Prices() {
+		if old, ok := previous[sku]; ok && old == price {
+			continue
+		}
+		entries = append(entries, &PriceEntry{
+			TenantID:      p.TenantID,
+			ProductID:     p.ID,
+			SKU:           sku,
+			Version:       p.PriceVersion,
+			Price:         price,
+			Status:        PriceEntryActive,
+			EffectiveFrom: p.UpdatedAt,
+			CreatedAt:     time.Now(),
+		})
+	}
+	return entries
+}
+
+// SchedulePrice creates a price change taking effect at effectiveFrom, which must be in the future
+func (p *Product) SchedulePrice(price float64, effectiveFrom time.Time) (*PriceEntry, error) {
+	if price <= 0 {
+		return nil, ErrProductPriceInvalid
+	}
+	now := time.Now()
+	if !effectiveFrom.After(now) {
+		return nil, ErrPriceEffectiveFromInvalid
+	}
+
+	entry := &PriceEntry{
+		TenantID:      p.TenantID,
+		ProductID:     p.ID,
+		Price:         price,
+		Status:        PriceEntryScheduled,
+		EffectiveFrom: effectiveFrom,
+		CreatedAt:     now,
+	}
+
+	p.recordEvent(ProductPriceScheduledEvent{
+		ProductID:     p.ID,
+		Price:         price,
+		EffectiveFrom: effectiveFrom,
+	})
+
+	return entry, nil
+}
+
+// ApplyScheduledPrice changes the price to a due scheduled entry, which becomes active as of now
+func (p *Product) ApplyScheduledPrice(entry *PriceEntry) error {
+	if entry.Status != PriceEntryScheduled || entry.ProductID != p.ID {
+		return ErrPriceEntryNotScheduled
+	}
+
+	p.changePrice(entry.Price)
+	p.UpdatedAt = time.Now()
+
+	entry.Status = PriceEntryActive
+	entry.Version = p.PriceVersion
+	entry.EffectiveFrom = p.UpdatedAt
+	return nil
+}
+
+// changePrice sets a new price version
+func (p *Product) changePrice(price float64) {
+	oldPrice := p.Price
+	p.Price = price
+	p.PriceVersion++
+
+	p.recordEvent(ProductPriceChangedEvent{
+		ProductID:    p.ID,
+		OldPrice:     oldPrice,
+		NewPrice:     price,
+		PriceVersion: p.PriceVersion,
+	})
+}
+
+// changeVariantPrice sets the price override of a variant. The product price version covers variant
+// prices too, so the version an order item was priced at identifies its price.
+func (p *Product) changeVariantPrice(variant *Variant, price float64) {
+	oldPrice := variant.Price
+	variant.Price = price
+	p.PriceVersion++
+
+	p.recordEvent(ProductPriceChangedEvent{
+		ProductID:    p.ID,
+		SKU:          variant.SKU,
+		OldPrice:     oldPrice,
+		NewPrice:     price,
+		PriceVersion: p.PriceVersion,
+	})
+}
+
+// Price domain events
+type ProductPriceChangedEvent struct {
+	ProductID    string
+	SKU          string // variant whose price override changed, empty for the product price
+	OldPrice     float64
+	NewPrice     float64
+	PriceVersion int
+}
+
+func (e ProductPriceChangedEvent) EventName() string {
+	return "product.price_changed"
+}
+
+type ProductPriceScheduledEvent struct {
+	ProductID     string
+	Price         float64
+	EffectiveFrom time.Time
+}
+
+func (e ProductPriceScheduledEvent) EventName() string {
+	return "product.price_scheduled"
+}
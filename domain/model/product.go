@@ -8,14 +8,21 @@ import (
 
 // Product represents a product in the catalog
 type Product struct {
-	ID          string // MongoDB ObjectID
-	Name        string
-	Description string
-	Price       float64
-	Stock       int
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	DeletedAt   *time.Time
+	ID                string // MongoDB ObjectID
+	TenantID          string // set from the request context when the product is created
+	Name              string
+	Description       string
+	Price             float64
+	PriceVersion      int // incremented on every price change, see PriceEntry
+	Stock             int
+	LowStockThreshold int // SKUs with stock at or below it are low, zero disables alerts
+	CategoryIDs       []string
+	Variants          []Variant      // when present, Stock is the total stock of the variants
+	WarehouseStock    map[string]int // stock by warehouse ID of products without variants
+	Version           int            // incremented on every update, used for optimistic locking
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         *time.Time
 
 	events []DomainEvent
 }
@@ -23,12 +30,14 @@ type Product struct {
 // NewProduct creates a new product with validation
 func NewProduct(name, description string, price float64, stock int) (*Product, error) {
 	product := &Product{
-		Name:        name,
-		Description: description,
-		Price:       price,
-		Stock:       stock,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		Name:         name,
+		Description:  description,
+		Price:        price,
+		PriceVersion: 1,
+		Stock:        stock,
+		Version:      1,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
 	}
 
 	if err := product.Validate(); err != nil {
@@ -58,6 +67,17 @@ func (p *Product) Validate() error {
 		return ErrProductStockNegative
 	}
 
+	seen := make(map[string]struct{}, len(p.Variants))
+	for _, variant := range p.Variants {
+		if err := variant.validate(); err != nil {
+			return err
+		}
+		if _, ok := seen[variant.SKU]; ok {
+			return ErrVariantSKUExists
+		}
+		seen[variant.SKU] = struct{}{}
+	}
+
 	return nil
 }
 
@@ -71,10 +91,13 @@ func (p *Product) Update(name, description string, price float64) error {
 		return ErrProductPriceInvalid
 	}
 
+	oldPrice := p.Price
 	p.Name = name
 	p.Description = description
-	p.Price = price
 	p.UpdatedAt = time.Now()
+	if price != oldPrice {
+		p.changePrice(price)
+	}
 
 	p.recordEvent(ProductUpdatedEvent{
 		ID:    p.ID,
@@ -85,34 +108,126 @@ func (p *Product) Update(name, description string, price float64) error {
 	return nil
 }
 
-// UpdateStock updates the product stock
-func (p *Product) UpdateStock(quantity int) error {
-	newStock := p.Stock + quantity
+// UpdateStock updates the stock of a variant, or of the product itself when it has no variants
+// and sku is empty. A non-empty warehouseID also updates the stock held at that warehouse.
+func (p *Product) UpdateStock(sku, warehouseID string, quantity int) error {
+	variant, err := p.stockVariant(sku)
+	if err != nil {
+		return err
+	}
+
+	oldStock := p.Stock
+	newStock := oldStock + quantity
 	if newStock < 0 {
 		return ErrProductStockNegative
 	}
+	if variant != nil && variant.Stock+quantity < 0 {
+		return ErrProductStockNegative
+	}
+	skuStock := oldStock
+	if variant != nil {
+		skuStock = variant.Stock
+	}
+	if warehouseID != "" {
+		levels := p.warehouseLevels(variant)
+		if (*levels)[warehouseID]+quantity < 0 {
+			return ErrProductStockNegative
+		}
+		if *levels == nil {
+			*levels = make(map[string]int)
+		}
+		(*levels)[warehouseID] += quantity
+	}
+	if variant != nil {
+		variant.Stock += quantity
+	}
 
 	p.Stock = newStock
 	p.UpdatedAt = time.Now()
 
 	p.recordEvent(StockUpdatedEvent{
-		ProductID: p.ID,
-		OldStock:  p.Stock - quantity,
-		NewStock:  newStock,
-		Change:    quantity,
+		ProductID:   p.ID,
+		SKU:         sku,
+		WarehouseID: warehouseID,
+		OldStock:    oldStock,
+		NewStock:    newStock,
+		Change:      quantity,
 	})
+	p.checkLowStock(sku, skuStock, skuStock+quantity)
 
 	return nil
 }
 
-// ReserveStock reserves stock for an order
-func (p *Product) ReserveStock(quantity int) error {
-	if p.Stock < quantity {
+// StockByWarehouse returns the stock by warehouse ID of a variant, or of the product itself when
+// it has no variants and sku is empty
+func (p *Product) StockByWarehouse(sku string) (map[string]int, error) {
+	variant, err := p.stockVariant(sku)
+	if err != nil {
+		return nil, err
+	}
+	return *p.warehouseLevels(variant), nil
+}
+
+// warehouseLevels returns the warehouse stock of the variant, or of the product when variant is nil
+func (p *Product) warehouseLevels(variant *Variant) *map[string]int {
+	if variant != nil {
+		return &variant.WarehouseStock
+	}
+	return &p.WarehouseStock
+}
+
+// AssignCategories replaces the categories the product belongs to, dropping duplicates
+func (p *Product) AssignCategories(categoryIDs []string) {
+	seen := make(map[string]struct{}, len(categoryIDs))
+	assigned := make([]string, 0, len(categoryIDs))
+	for _, id := range categoryIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		assigned = append(assigned, id)
+	}
+
+	p.CategoryIDs = assigned
+	p.UpdatedAt = time.Now()
+
+	p.recordEvent(ProductCategoriesAssignedEvent{
+		ID:          p.ID,
+		CategoryIDs: assigned,
+	})
+}
+
+// ReserveStock reserves stock of a variant for an order, or of the product itself when it has
+// no variants and sku is empty. A non-empty warehouseID takes the stock from that warehouse.
+func (p *Product) ReserveStock(sku, warehouseID string, quantity int) error {
+	variant, err := p.stockVariant(sku)
+	if err != nil {
+		return err
+	}
+
+	// Every check runs before anything changes, so a failed reservation leaves the product untouched
+	skuStock := p.Stock
+	if variant != nil {
+		skuStock = variant.Stock
+	}
+	if skuStock < quantity {
 		return ErrProductInsufficientStock
 	}
+	levels := p.warehouseLevels(variant)
+	if warehouseID != "" && (*levels)[warehouseID] < quantity {
+		return ErrProductInsufficientStock
+	}
+
+	if warehouseID != "" {
+		(*levels)[warehouseID] -= quantity
+	}
+	if variant != nil {
+		variant.Stock -= quantity
+	}
 
 	p.Stock -= quantity
 	p.UpdatedAt = time.Now()
+	p.checkLowStock(sku, skuStock, skuStock-quantity)
 
 	return nil
 }
@@ -161,11 +276,20 @@ type ProductDeletedEvent struct {
 
 func (e ProductDeletedEvent) EventName() string { return "product.deleted" }
 
+type ProductCategoriesAssignedEvent struct {
+	ID          string
+	CategoryIDs []string
+}
+
+func (e ProductCategoriesAssignedEvent) EventName() string { return "product.categories_assigned" }
+
 type StockUpdatedEvent struct {
-	ProductID string
-	OldStock  int
-	NewStock  int
-	Change    int
+	ProductID   string
+	SKU         string // empty for products without variants
+	WarehouseID string // empty for stock not held at a warehouse
+	OldStock    int
+	NewStock    int
+	Change      int
 }
 
 func (e StockUpdatedEvent) EventName() string { return "product.stock_updated" }
@@ -0,0 +1,109 @@
+package model
+
+// ProductFileFormat is a file format products are imported from and exported to
+type ProductFileFormat string
+
+const (
+	ProductFileFormatCSV    ProductFileFormat = "csv"
+	ProductFileFormatNDJSON ProductFileFormat = "ndjson"
+)
+
+// IsValid checks if the format is valid
+func (f ProductFileFormat) IsValid() bool {
+	return f == ProductFileFormatCSV || f == ProductFileFormatNDJSON
+}
+
+// ProductRow is a row of a product file. Rows without SKU describe a product without variants,
+// matched by name; rows with a SKU describe a variant, matched by SKU and otherwise added to the
+// product with the row's name.
+type ProductRow struct {
+	Line        int // line of the row in the file
+	SKU         string
+	Name        string
+	Description string
+	Price       float64 // the product price, or the variant price for rows with a SKU
+	Stock       int
+	Err         error // set when the row could not be parsed
+}
+
+// ProductImportOptions controls an import
+type ProductImportOptions struct {
+	DryRun      bool   // validate and report without writing
+	BatchSize   int    // rows written per bulk write
+	ReferenceID string // recorded on the stock movements of the import
+}
+
+// ProductImportAction is the outcome of an imported row
+type ProductImportAction string
+
+const (
+	ProductImportCreated   ProductImportAction = "created"
+	ProductImportUpdated   ProductImportAction = "updated"
+	ProductImportUnchanged ProductImportAction = "unchanged"
+	ProductImportFailed    ProductImportAction = "failed"
+)
+
+// ProductImportRowResult reports the outcome of one row
+type ProductImportRowResult struct {
+	Line      int
+	SKU       string
+	Name      string
+	Action    ProductImportAction
+	ProductID string // empty for failed rows and for products created by a dry run
+	Error     string
+}
+
+// Fail marks the row failed with an error
+func (r *ProductImportRowResult) Fail(err error) {
+	r.Action = ProductImportFailed
+	r.Error = err.Error()
+}
+
+// ProductImportReport reports the outcome of an import row by row
+type ProductImportReport struct {
+	ReferenceID string
+	DryRun      bool
+	Total       int
+	Created     int
+	Updated     int
+	Unchanged   int
+	Failed      int
+	Rows        []ProductImportRowResult
+}
+
+// Add records the outcome of a row
+func (r *ProductImportReport) Add(result ProductImportRowResult) {
+	r.Total++
+	switch result.Action {
+	case ProductImportCreated:
+		r.Created++
+	case ProductImportUpdated:
+		r.Updated++
+	case ProductImportUnchanged:
+		r.Unchanged++
+	case ProductImportFailed:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, result)
+}
+
+// ExportRows returns the rows describing a product: one per variant, or a single row without SKU
+// for products without variants. Variant rows carry the effective variant price.
+func (p *Product) ExportRows() []ProductRow {
+	if !p.HasVariants() {
+		return []ProductRow{{Name: p.Name, Description: p.Description, Price: p.Price, Stock: p.Stock}}
+	}
+
+	rows := make([]ProductRow, len(p.Variants))
+	for i, variant := range p.Variants {
+		price, _ := p.PriceOf(variant.SKU)
+		rows[i] = ProductRow{
+			SKU:         variant.SKU,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       price,
+			Stock:       variant.Stock,
+		}
+	}
+	return rows
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProductSort defines the ordering of product search results
+type ProductSort string
+
+const (
+	ProductSortRelevance ProductSort = "relevance"
+	ProductSortPriceAsc  ProductSort = "price_asc"
+	ProductSortPriceDesc ProductSort = "price_desc"
+	ProductSortNewest    ProductSort = "newest"
+)
+
+const (
+	defaultProductSearchLimit = 10
+	maxProductSearchLimit     = 100
+)
+
+// ProductSearchCriteria describes a product search
+type ProductSearchCriteria struct {
+	Query       string
+	MinPrice    float64 // zero means no lower bound
+	MaxPrice    float64 // zero means no upper bound
+	InStockOnly bool
+	Category    string   // category ID, matching products in the category or its descendants
+	CategoryIDs []string // the category and its descendants, resolved by the service
+	Sort        ProductSort
+	Offset      int
+	Limit       int
+}
+
+// Normalize validates the criteria and fills in defaults.
+// Relevance sorting needs a query, so searches without one fall back to newest first.
+func (c *ProductSearchCriteria) Normalize() error {
+	c.Query = strings.TrimSpace(c.Query)
+	c.Category = strings.TrimSpace(c.Category)
+
+	if c.MinPrice < 0 || c.MaxPrice < 0 || (c.MaxPrice > 0 && c.MinPrice > c.MaxPrice) {
+		return ErrProductSearchPriceInvalid
+	}
+
+	switch c.Sort {
+	case "":
+		c.Sort = ProductSortRelevance
+	case ProductSortRelevance, ProductSortPriceAsc, ProductSortPriceDesc, ProductSortNewest:
+	default:
+		return ErrProductSearchSortInvalid
+	}
+	if c.Sort == ProductSortRelevance && c.Query == "" {
+		c.Sort = ProductSortNewest
+	}
+
+	if c.Offset < 0 {
+		c.Offset = 0
+	}
+	if c.Limit <= 0 {
+		c.Limit = defaultProductSearchLimit
+	}
+	if c.Limit > maxProductSearchLimit {
+		c.Limit = maxProductSearchLimit
+	}
+
+	return nil
+}
+
+// Key returns a stable identifier of the normalized criteria, used as cache key
+func (c ProductSearchCriteria) Key() string {
+	return fmt.Sprintf("q=%s|min=%g|max=%g|stock=%t|cat=%s|sort=%s|offset=%d|limit=%d",
+		strings.ToLower(c.Query), c.MinPrice, c.MaxPrice, c.InStockOnly, c.Category, c.Sort, c.Offset, c.Limit)
+}
+
+// ProductSearchResult holds a page of matching products with facet counts over all matches
+type ProductSearchResult struct {
+	Products []*Product
+	Total    int64
+	Facets   ProductFacets
+}
+
+// ProductFacets holds the facet counts of a product search
+type ProductFacets struct {
+	Categories   []FacetCount
+	PriceRanges  []FacetCount
+	Availability []FacetCount
+}
+
+// FacetCount is the number of matching products sharing a facet value
+type FacetCount struct {
+	Value string
+	Count int64
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductReserveStock(t *testing.T) {
+	tests := []struct {
+		name           string
+		stock          int
+		warehouseStock int
+		quantity       int
+		wantErr        error
+		wantStock      int
+		wantWarehouse  int
+	}{
+		{name: "reserves from the warehouse", stock: 10, warehouseStock: 4, quantity: 3, wantStock: 7, wantWarehouse: 1},
+		{name: "warehouse short", stock: 10, warehouseStock: 2, quantity: 3, wantErr: ErrProductInsufficientStock, wantStock: 10, wantWarehouse: 2},
+		{name: "product short leaves the warehouse untouched", stock: 2, warehouseStock: 5, quantity: 3, wantErr: ErrProductInsufficientStock, wantStock: 2, wantWarehouse: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product, err := NewProduct("Widget", "", 10, tt.stock)
+			require.NoError(t, err)
+			product.WarehouseStock = map[string]int{"w1": tt.warehouseStock}
+
+			err = product.ReserveStock("", "w1", tt.quantity)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantStock, product.Stock)
+			assert.Equal(t, tt.wantWarehouse, product.WarehouseStock["w1"])
+		})
+	}
+}
+
+func TestProductVariantPriceHistory(t *testing.T) {
+	product, err := NewProduct("Shirt", "", 20, 0)
+	require.NoError(t, err)
+	require.NoError(t, product.AddVariant(Variant{SKU: "SHIRT-S"}))
+	require.NoError(t, product.AddVariant(Variant{SKU: "SHIRT-L", Price: 25}))
+	version := product.PriceVersion
+
+	previous := product.Prices()
+	assert.Equal(t, map[string]float64{"": 20, "SHIRT-S": 20, "SHIRT-L": 25}, previous)
+
+	// Changing an override is a new price version with a history entry for the variant only
+	require.NoError(t, product.UpdateVariant("SHIRT-L", nil, 30))
+	assert.Equal(t, version+1, product.PriceVersion)
+
+	entries := product.CurrentPriceEntries(previous)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "SHIRT-L", entries[0].SKU)
+	assert.Equal(t, 30.0, entries[0].Price)
+	assert.Equal(t, product.PriceVersion, entries[0].Version)
+
+	// Attributes alone leave the price version alone
+	require.NoError(t, product.UpdateVariant("SHIRT-L", map[string]string{"size": "L"}, 30))
+	assert.Equal(t, version+1, product.PriceVersion)
+}
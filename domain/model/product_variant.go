@@ -0,0 +1,195 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Variant is a sellable version of a product, such as a size and color, identified by its SKU
+type Variant struct {
+	SKU            string
+	Attributes     map[string]string
+	Price          float64 // overrides the product price when greater than zero
+	Stock          int
+	WarehouseStock map[string]int // stock by warehouse ID
+}
+
+func (v *Variant) validate() error {
+	if v.SKU == "" {
+		return ErrVariantSKURequired
+	}
+	if v.Price < 0 {
+		return ErrVariantPriceInvalid
+	}
+	if v.Stock < 0 {
+		return ErrProductStockNegative
+	}
+	return nil
+}
+
+// HasVariants reports whether the product is sold through variants
+func (p *Product) HasVariants() bool {
+	return len(p.Variants) > 0
+}
+
+// Variant returns the variant with the given SKU
+func (p *Product) Variant(sku string) (*Variant, bool) {
+	for i := range p.Variants {
+		if p.Variants[i].SKU == sku {
+			return &p.Variants[i], true
+		}
+	}
+	return nil, false
+}
+
+// PriceOf returns the price of a variant, falling back to the product price without an override
+func (p *Product) PriceOf(sku string) (float64, error) {
+	if sku == "" {
+		return p.Price, nil
+	}
+	variant, ok := p.Variant(sku)
+	if !ok {
+		return 0, ErrVariantNotFound
+	}
+	if variant.Price > 0 {
+		return variant.Price, nil
+	}
+	return p.Price, nil
+}
+
+// StockOf returns the stock of a variant, or of the product when it has no variants
+func (p *Product) StockOf(sku string) (int, error) {
+	variant, err := p.stockVariant(sku)
+	if err != nil {
+		return 0, err
+	}
+	if variant == nil {
+		return p.Stock, nil
+	}
+	return variant.Stock, nil
+}
+
+// AddVariant adds a variant. From then on the product stock is the total stock of its variants.
+func (p *Product) AddVariant(variant Variant) error {
+	variant.SKU = strings.TrimSpace(variant.SKU)
+	if err := variant.validate(); err != nil {
+		return err
+	}
+	if _, exists := p.Variant(variant.SKU); exists {
+		return ErrVariantSKUExists
+	}
+
+	p.Variants = append(p.Variants, variant)
+	p.recalculateStock()
+	p.UpdatedAt = time.Now()
+
+	p.recordEvent(ProductVariantAddedEvent{
+		ProductID: p.ID,
+		SKU:       variant.SKU,
+		Price:     variant.Price,
+		Stock:     variant.Stock,
+	})
+
+	return nil
+}
+
+// UpdateVariant changes the attributes and price override of a variant; stock changes go through UpdateStock
+func (p *Product) UpdateVariant(sku string, attributes map[string]string, price float64) error {
+	variant, ok := p.Variant(sku)
+	if !ok {
+		return ErrVariantNotFound
+	}
+	if price < 0 {
+		return ErrVariantPriceInvalid
+	}
+
+	variant.Attributes = attributes
+	if price != variant.Price {
+		p.changeVariantPrice(variant, price)
+	}
+	p.UpdatedAt = time.Now()
+
+	p.recordEvent(ProductVariantUpdatedEvent{
+		ProductID: p.ID,
+		SKU:       sku,
+		Price:     price,
+	})
+
+	return nil
+}
+
+// RemoveVariant removes a variant and its stock
+func (p *Product) RemoveVariant(sku string) error {
+	for i := range p.Variants {
+		if p.Variants[i].SKU != sku {
+			continue
+		}
+
+		p.Variants = append(p.Variants[:i], p.Variants[i+1:]...)
+		p.recalculateStock()
+		p.UpdatedAt = time.Now()
+
+		p.recordEvent(ProductVariantRemovedEvent{
+			ProductID: p.ID,
+			SKU:       sku,
+		})
+		return nil
+	}
+
+	return ErrVariantNotFound
+}
+
+// stockVariant resolves the variant a stock change applies to: nil for products without variants
+func (p *Product) stockVariant(sku string) (*Variant, error) {
+	if !p.HasVariants() {
+		if sku != "" {
+			return nil, ErrVariantNotFound
+		}
+		return nil, nil
+	}
+
+	if sku == "" {
+		return nil, ErrVariantRequired
+	}
+	variant, ok := p.Variant(sku)
+	if !ok {
+		return nil, ErrVariantNotFound
+	}
+	return variant, nil
+}
+
+// recalculateStock sets the product stock to the total stock of its variants.
+// Warehouse stock is then tracked by the variants only.
+func (p *Product) recalculateStock() {
+	p.WarehouseStock = nil
+	total := 0
+	for _, variant := range p.Variants {
+		total += variant.Stock
+	}
+	p.Stock = total
+}
+
+// Product variant domain events
+type ProductVariantAddedEvent struct {
+	ProductID string
+	SKU       string
+	Price     float64
+	Stock     int
+}
+
+func (e ProductVariantAddedEvent) EventName() string { return "product.variant_added" }
+
+type ProductVariantUpdatedEvent struct {
+	ProductID string
+	SKU       string
+	Price     float64
+}
+
+func (e ProductVariantUpdatedEvent) EventName() string { return "product.variant_updated" }
+
+type ProductVariantRemovedEvent struct {
+	ProductID string
+	SKU       string
+}
+
+func (e ProductVariantRemovedEvent) EventName() string { return "product.variant_removed" }
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func variantProduct(t *testing.T) *Product {
+	t.Helper()
+	product, err := NewProduct("Shirt", "", 20, 0)
+	require.NoError(t, err)
+	require.NoError(t, product.AddVariant(Variant{SKU: "SHIRT-S", Stock: 3, WarehouseStock: map[string]int{"w1": 3}}))
+	require.NoError(t, product.AddVariant(Variant{SKU: "SHIRT-L", Stock: 5, Price: 25}))
+	return product
+}
+
+func TestProductAddVariant(t *testing.T) {
+	tests := []struct {
+		name      string
+		variant   Variant
+		wantErr   error
+		wantStock int
+	}{
+		{name: "adds its stock to the product", variant: Variant{SKU: "SHIRT-M", Stock: 2}, wantStock: 10},
+		{name: "trims the SKU", variant: Variant{SKU: "  SHIRT-XL ", Stock: 1}, wantStock: 9},
+		{name: "duplicate SKU", variant: Variant{SKU: "SHIRT-S"}, wantErr: ErrVariantSKUExists, wantStock: 8},
+		{name: "duplicate SKU after trimming", variant: Variant{SKU: " SHIRT-L"}, wantErr: ErrVariantSKUExists, wantStock: 8},
+		{name: "empty SKU", variant: Variant{SKU: "  "}, wantErr: ErrVariantSKURequired, wantStock: 8},
+		{name: "negative price", variant: Variant{SKU: "SHIRT-M", Price: -1}, wantErr: ErrVariantPriceInvalid, wantStock: 8},
+		{name: "negative stock", variant: Variant{SKU: "SHIRT-M", Stock: -1}, wantErr: ErrProductStockNegative, wantStock: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := variantProduct(t)
+			product.Events()
+
+			err := product.AddVariant(tt.variant)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Len(t, product.Variants, 2)
+				assert.Empty(t, product.Events())
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, product.Variants, 3)
+				assert.Len(t, product.Events(), 1)
+			}
+			assert.Equal(t, tt.wantStock, product.Stock)
+		})
+	}
+}
+
+func TestProductValidateRejectsDuplicateSKUs(t *testing.T) {
+	product := variantProduct(t)
+	product.Variants = append(product.Variants, Variant{SKU: "SHIRT-S"})
+
+	assert.ErrorIs(t, product.Validate(), ErrVariantSKUExists)
+}
+
+func TestProductVariantStock(t *testing.T) {
+	tests := []struct {
+		name          string
+		sku           string
+		warehouseID   string
+		quantity      int
+		reserve       bool
+		wantErr       error
+		wantSKUStock  map[string]int
+		wantWarehouse int
+	}{
+		{name: "adds stock to one SKU", sku: "SHIRT-L", quantity: 4,
+			wantSKUStock: map[string]int{"SHIRT-S": 3, "SHIRT-L": 9}, wantWarehouse: 3},
+		{name: "removes stock from a SKU and its warehouse", sku: "SHIRT-S", warehouseID: "w1", quantity: -2,
+			wantSKUStock: map[string]int{"SHIRT-S": 1, "SHIRT-L": 5}, wantWarehouse: 1},
+		{name: "a SKU cannot go below zero with stock left on others", sku: "SHIRT-S", quantity: -4,
+			wantErr: ErrProductStockNegative, wantSKUStock: map[string]int{"SHIRT-S": 3, "SHIRT-L": 5}, wantWarehouse: 3},
+		{name: "a SKU warehouse cannot go below zero", sku: "SHIRT-L", warehouseID: "w1", quantity: -1,
+			wantErr: ErrProductStockNegative, wantSKUStock: map[string]int{"SHIRT-S": 3, "SHIRT-L": 5}, wantWarehouse: 3},
+		{name: "reserves from one SKU", sku: "SHIRT-S", warehouseID: "w1", quantity: 3, reserve: true,
+			wantSKUStock: map[string]int{"SHIRT-S": 0, "SHIRT-L": 5}, wantWarehouse: 0},
+		{name: "a reservation cannot borrow stock of another SKU", sku: "SHIRT-S", quantity: 4, reserve: true,
+			wantErr: ErrProductInsufficientStock, wantSKUStock: map[string]int{"SHIRT-S": 3, "SHIRT-L": 5}, wantWarehouse: 3},
+		{name: "a SKU is required", quantity: 1,
+			wantErr: ErrVariantRequired, wantSKUStock: map[string]int{"SHIRT-S": 3, "SHIRT-L": 5}, wantWarehouse: 3},
+		{name: "unknown SKU", sku: "SHIRT-XS", quantity: 1, reserve: true,
+			wantErr: ErrVariantNotFound, wantSKUStock: map[string]int{"SHIRT-S": 3, "SHIRT-L": 5}, wantWarehouse: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := variantProduct(t)
+
+			var err error
+			if tt.reserve {
+				err = product.ReserveStock(tt.sku, tt.warehouseID, tt.quantity)
+			} else {
+				err = product.UpdateStock(tt.sku, tt.warehouseID, tt.quantity)
+			}
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			total := 0
+			for sku, want := range tt.wantSKUStock {
+				stock, err := product.StockOf(sku)
+				require.NoError(t, err)
+				assert.Equal(t, want, stock, sku)
+				total += want
+			}
+			assert.Equal(t, total, product.Stock)
+
+			levels, err := product.StockByWarehouse("SHIRT-S")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWarehouse, levels["w1"])
+		})
+	}
+}
+
+func TestProductWithoutVariantsRejectsSKU(t *testing.T) {
+	product, err := NewProduct("Mug", "", 8, 2)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, product.UpdateStock("MUG-RED", "", 1), ErrVariantNotFound)
+	_, err = product.StockOf("MUG-RED")
+	assert.ErrorIs(t, err, ErrVariantNotFound)
+	assert.Equal(t, 2, product.Stock)
+}
+
+func TestProductRemoveVariant(t *testing.T) {
+	product := variantProduct(t)
+
+	require.NoError(t, product.RemoveVariant("SHIRT-S"))
+	assert.Equal(t, 5, product.Stock)
+	_, ok := product.Variant("SHIRT-S")
+	assert.False(t, ok)
+
+	// The SKU can be added again once removed
+	require.NoError(t, product.AddVariant(Variant{SKU: "SHIRT-S", Stock: 1}))
+	assert.Equal(t, 6, product.Stock)
+
+	assert.ErrorIs(t, product.RemoveVariant("SHIRT-XS"), ErrVariantNotFound)
+}
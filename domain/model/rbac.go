@@ -0,0 +1,117 @@
+package model
+
+// Role is a named set of permissions granted to a user
+type Role string
+
+const (
+	// RoleAdmin is granted every permission
+	RoleAdmin Role = "admin"
+	// RoleStaff runs the catalog, inventory and order fulfillment
+	RoleStaff Role = "staff"
+	// RoleCustomer browses the catalog and places orders for itself; it is the role of new users
+	RoleCustomer Role = "customer"
+)
+
+// Permission allows an action on a kind of resource, written "resource:action"
+type Permission string
+
+const (
+	PermissionAll                 Permission = "*"
+	PermissionUsersRead           Permission = "users:read"
+	PermissionUsersWrite          Permission = "users:write"
+	PermissionUsersManage         Permission = "users:manage"
+	PermissionCatalogRead         Permission = "catalog:read"
+	PermissionCatalogWrite        Permission = "catalog:write"
+	PermissionInventoryRead       Permission = "inventory:read"
+	PermissionInventoryWrite      Permission = "inventory:write"
+	PermissionOrdersRead          Permission = "orders:read"
+	PermissionOrdersWrite         Permission = "orders:write"
+	PermissionPaymentsRead        Permission = "payments:read"
+	PermissionPaymentsWrite       Permission = "payments:write"
+	PermissionFulfillmentRead     Permission = "fulfillment:read"
+	PermissionFulfillmentWrite    Permission = "fulfillment:write"
+	PermissionAuditRead           Permission = "audit:read"
+	PermissionAPIKeysManage       Permission = "api_keys:manage"
+	PermissionOrganizationsManage Permission = "organizations:manage"
+)
+
+// rolePermissions are the permissions granted by each role
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {PermissionAll},
+	RoleStaff: {
+		PermissionUsersRead,
+		PermissionCatalogRead, PermissionCatalogWrite,
+		PermissionInventoryRead, PermissionInventoryWrite,
+		PermissionOrdersRead, PermissionOrdersWrite,
+		PermissionPaymentsRead,
+		PermissionFulfillmentRead, PermissionFulfillmentWrite,
+	},
+	RoleCustomer: {PermissionCatalogRead},
+}
+
+// knownPermissions are the permissions that can be granted to a user directly
+var knownPermissions = map[Permission]bool{
+	PermissionAll:                 true,
+	PermissionUsersRead:           true,
+	PermissionUsersWrite:          true,
+	PermissionUsersManage:         true,
+	PermissionCatalogRead:         true,
+	PermissionCatalogWrite:        true,
+	PermissionInventoryRead:       true,
+	PermissionInventoryWrite:      true,
+	PermissionOrdersRead:          true,
+	PermissionOrdersWrite:         true,
+	PermissionPaymentsRead:        true,
+	PermissionPaymentsWrite:       true,
+	PermissionFulfillmentRead:     true,
+	PermissionFulfillmentWrite:    true,
+	PermissionAuditRead:           true,
+	PermissionAPIKeysManage:       true,
+	PermissionOrganizationsManage: true,
+}
+
+// IsValid reports whether the role is a known role
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// IsValid reports whether the permission is a known permission
+func (p Permission) IsValid() bool {
+	return knownPermissions[p]
+}
+
+// Grants reports whether the roles, together with the permissions granted directly, allow permission
+func Grants(roles []Role, permissions []Permission, permission Permission) bool {
+	for _, p := range permissions {
+		if p == permission || p == PermissionAll {
+			return true
+		}
+	}
+	for _, role := range roles {
+		for _, p := range rolePermissions[role] {
+			if p == permission || p == PermissionAll {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValidateRoles validates the roles and the directly granted permissions of a user
+func ValidateRoles(roles []Role, permissions []Permission) error {
+	if len(roles) == 0 {
+		return ErrUserRoleRequired
+	}
+	for _, role := range roles {
+		if !role.IsValid() {
+			return ErrUserRoleInvalid
+		}
+	}
+	for _, p := range permissions {
+		if !p.IsValid() {
+			return ErrUserPermissionInvalid
+		}
+	}
+	return nil
+}
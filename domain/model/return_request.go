@@ -0,0 +1,297 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Return domain errors are defined in domain_error.go
+
+// ReturnStatus represents the status of a return request
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "requested"
+	ReturnStatusApproved  ReturnStatus = "approved"
+	ReturnStatusRejected  ReturnStatus = "rejected"
+	ReturnStatusReceived  ReturnStatus = "received"
+	ReturnStatusRefunding ReturnStatus = "refunding" // the refund is being paid out
+	ReturnStatusRefunded  ReturnStatus = "refunded"
+)
+
+// ReturnRequest represents a return merchandise authorization (RMA) for delivered order items
+type ReturnRequest struct {
+	ID           string
+	OrderID      string
+	Items        []ReturnItem
+	Reason       string
+	Status       ReturnStatus
+	RejectReason string
+	RefundAmount float64
+	Version      int // incremented on every update, used for optimistic locking
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	events []DomainEvent
+}
+
+// ReturnItem represents a quantity of an order item being returned
+type ReturnItem struct {
+	ID          string
+	ReturnID    string
+	OrderItemID string
+	ProductID   string
+	SKU         string
+	Quantity    int
+	Price       float64 // unit price paid for the item
+}
+
+// Refund records money returned to the customer for a return request
+type Refund struct {
+	ID        string
+	ReturnID  string
+	OrderID   string
+	PaymentID string // empty when the order was not paid through the payment gateway
+	Amount    float64
+	CreatedAt time.Time
+}
+
+// NewReturnRequest creates a new return request for items of an order.
+// returnedQty holds the quantities already covered by other, non-rejected returns per order item.
+func NewReturnRequest(order *Order, items []ReturnItem, reason string, returnedQty map[string]int) (*ReturnRequest, error) {
+	if !order.IsReturnable() {
+		return nil, ErrOrderNotReturnable
+	}
+
+	if len(items) == 0 {
+		return nil, ErrReturnItemsRequired
+	}
+
+	orderItems := make(map[string]OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		orderItems[item.ID] = item
+	}
+
+	returnID := uuid.New().String()
+	requested := make(map[string]int, len(items))
+	var refundAmount float64
+	for i := range items {
+		orderItem, ok := orderItems[items[i].OrderItemID]
+		if !ok {
+			return nil, ErrReturnItemInvalid
+		}
+		if items[i].Quantity <= 0 {
+			return nil, ErrReturnQuantityInvalid
+		}
+
+		requested[orderItem.ID] += items[i].Quantity
+		if requested[orderItem.ID]+returnedQty[orderItem.ID] > orderItem.Quantity {
+			return nil, ErrReturnQuantityInvalid
+		}
+
+		items[i].ID = uuid.New().String()
+		items[i].ReturnID = returnID
+		items[i].ProductID = orderItem.ProductID
+		items[i].SKU = orderItem.SKU
+		items[i].Price = orderItem.Price
+		refundAmount += orderItem.Price * float64(items[i].Quantity)
+	}
+
+	rma := &ReturnRequest{
+		ID:           returnID,
+		OrderID:      order.ID,
+		Items:        items,
+		Reason:       reason,
+		Status:       ReturnStatusRequested,
+		RefundAmount: roundAmount(refundAmount),
+		Version:      1,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	rma.recordEvent(ReturnRequestedEvent{
+		ReturnID:     rma.ID,
+		OrderID:      order.ID,
+		ItemCount:    len(items),
+		RefundAmount: rma.RefundAmount,
+		Reason:       reason,
+	})
+
+	return rma, nil
+}
+
+// CountsTowardsOrder reports whether the return reserves order item quantities
+func (r *ReturnRequest) CountsTowardsOrder() bool {
+	return r.Status != ReturnStatusRejected
+}
+
+// IsReceived reports whether the returned goods are back in stock
+func (r *ReturnRequest) IsReceived() bool {
+	switch r.Status {
+	case ReturnStatusReceived, ReturnStatusRefunding, ReturnStatusRefunded:
+		return true
+	}
+	return false
+}
+
+// Approve approves the return request
+func (r *ReturnRequest) Approve() error {
+	if r.Status != ReturnStatusRequested {
+		return ErrReturnInvalidStatus
+	}
+
+	r.Status = ReturnStatusApproved
+	r.UpdatedAt = time.Now()
+
+	r.recordEvent(ReturnApprovedEvent{
+		ReturnID: r.ID,
+		OrderID:  r.OrderID,
+	})
+
+	return nil
+}
+
+// Reject rejects the return request
+func (r *ReturnRequest) Reject(reason string) error {
+	if r.Status != ReturnStatusRequested {
+		return ErrReturnInvalidStatus
+	}
+
+	r.Status = ReturnStatusRejected
+	r.RejectReason = reason
+	r.UpdatedAt = time.Now()
+
+	r.recordEvent(ReturnRejectedEvent{
+		ReturnID: r.ID,
+		OrderID:  r.OrderID,
+		Reason:   reason,
+	})
+
+	return nil
+}
+
+// Receive marks the returned goods as received in the warehouse
+func (r *ReturnRequest) Receive() error {
+	if r.Status != ReturnStatusApproved {
+		return ErrReturnInvalidStatus
+	}
+
+	r.Status = ReturnStatusReceived
+	r.UpdatedAt = time.Now()
+
+	r.recordEvent(ReturnReceivedEvent{
+		ReturnID: r.ID,
+		OrderID:  r.OrderID,
+	})
+
+	return nil
+}
+
+// StartRefund claims a received return for refunding, so that it is paid out once
+func (r *ReturnRequest) StartRefund() error {
+	if r.Status != ReturnStatusReceived {
+		return ErrReturnInvalidStatus
+	}
+
+	r.Status = ReturnStatusRefunding
+	r.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// AbortRefund returns a return whose refund could not be paid out to received
+func (r *ReturnRequest) AbortRefund() error {
+	if r.Status != ReturnStatusRefunding {
+		return ErrReturnInvalidStatus
+	}
+
+	r.Status = ReturnStatusReceived
+	r.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Refund marks the return as refunded by the given refund record
+func (r *ReturnRequest) Refund(refundID string) error {
+	if r.Status != ReturnStatusRefunding {
+		return ErrReturnInvalidStatus
+	}
+
+	r.Status = ReturnStatusRefunded
+	r.UpdatedAt = time.Now()
+
+	r.recordEvent(ReturnRefundedEvent{
+		ReturnID: r.ID,
+		OrderID:  r.OrderID,
+		RefundID: refundID,
+		Amount:   r.RefundAmount,
+	})
+
+	return nil
+}
+
+// Events returns and clears domain events
+func (r *ReturnRequest) Events() []DomainEvent {
+	events := r.events
+	r.events = nil
+	return events
+}
+
+func (r *ReturnRequest) recordEvent(event DomainEvent) {
+	r.events = append(r.events, event)
+}
+
+// NewRefund creates a refund record for a return request
+func NewRefund(rma *ReturnRequest, paymentID string) *Refund {
+	return &Refund{
+		ID:        uuid.New().String(),
+		ReturnID:  rma.ID,
+		OrderID:   rma.OrderID,
+		PaymentID: paymentID,
+		Amount:    rma.RefundAmount,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Return domain events
+type ReturnRequestedEvent struct {
+	ReturnID     string
+	OrderID      string
+	ItemCount    int
+	RefundAmount float64
+	Reason       string
+}
+
+func (e ReturnRequestedEvent) EventName() string { return "return.requested" }
+
+type ReturnApprovedEvent struct {
+	ReturnID string
+	OrderID  string
+}
+
+func (e ReturnApprovedEvent) EventName() string { return "return.approved" }
+
+type ReturnRejectedEvent struct {
+	ReturnID string
+	OrderID  string
+	Reason   string
+}
+
+func (e ReturnRejectedEvent) EventName() string { return "return.rejected" }
+
+type ReturnReceivedEvent struct {
+	ReturnID string
+	OrderID  string
+}
+
+func (e ReturnReceivedEvent) EventName() string { return "return.received" }
+
+type ReturnRefundedEvent struct {
+	ReturnID string
+	OrderID  string
+	RefundID string
+	Amount   float64
+}
+
+func (e ReturnRefundedEvent) EventName() string { return "return.refunded" }
@@ -0,0 +1,174 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func deliveredOrder() *Order {
+	return &Order{
+		ID:     "order-1",
+		Status: OrderStatusDelivered,
+		Items: []OrderItem{
+			{ID: "item-1", ProductID: "p1", SKU: "P1-RED", Quantity: 3, Price: 19.99},
+			{ID: "item-2", ProductID: "p2", Quantity: 1, Price: 5.5},
+		},
+	}
+}
+
+func TestNewReturnRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      OrderStatus
+		items       []ReturnItem
+		returnedQty map[string]int
+		wantAmount  float64
+		wantErr     error
+	}{
+		{
+			name:       "refunds the paid price of every returned unit",
+			status:     OrderStatusDelivered,
+			items:      []ReturnItem{{OrderItemID: "item-1", Quantity: 2}, {OrderItemID: "item-2", Quantity: 1}},
+			wantAmount: 45.48,
+		},
+		{
+			name:        "partially returned order returns the rest",
+			status:      OrderStatusPartiallyReturned,
+			items:       []ReturnItem{{OrderItemID: "item-1", Quantity: 1}},
+			returnedQty: map[string]int{"item-1": 2},
+			wantAmount:  19.99,
+		},
+		{
+			name:    "order not delivered yet",
+			status:  OrderStatusShipped,
+			items:   []ReturnItem{{OrderItemID: "item-1", Quantity: 1}},
+			wantErr: ErrOrderNotReturnable,
+		},
+		{
+			name:    "no items",
+			status:  OrderStatusDelivered,
+			wantErr: ErrReturnItemsRequired,
+		},
+		{
+			name:    "item of another order",
+			status:  OrderStatusDelivered,
+			items:   []ReturnItem{{OrderItemID: "item-9", Quantity: 1}},
+			wantErr: ErrReturnItemInvalid,
+		},
+		{
+			name:    "zero quantity",
+			status:  OrderStatusDelivered,
+			items:   []ReturnItem{{OrderItemID: "item-1"}},
+			wantErr: ErrReturnQuantityInvalid,
+		},
+		{
+			name:    "more than ordered across lines",
+			status:  OrderStatusDelivered,
+			items:   []ReturnItem{{OrderItemID: "item-1", Quantity: 2}, {OrderItemID: "item-1", Quantity: 2}},
+			wantErr: ErrReturnQuantityInvalid,
+		},
+		{
+			name:        "more than left after earlier returns",
+			status:      OrderStatusPartiallyReturned,
+			items:       []ReturnItem{{OrderItemID: "item-1", Quantity: 2}},
+			returnedQty: map[string]int{"item-1": 2},
+			wantErr:     ErrReturnQuantityInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := deliveredOrder()
+			order.Status = tt.status
+
+			rma, err := NewReturnRequest(order, tt.items, "damaged", tt.returnedQty)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, ReturnStatusRequested, rma.Status)
+			assert.Equal(t, tt.wantAmount, rma.RefundAmount)
+			assert.Equal(t, "P1-RED", rma.Items[0].SKU)
+		})
+	}
+}
+
+func TestReturnRequestTransitions(t *testing.T) {
+	transitions := map[string]func(*ReturnRequest) error{
+		"approve":      (*ReturnRequest).Approve,
+		"reject":       func(r *ReturnRequest) error { return r.Reject("worn") },
+		"receive":      (*ReturnRequest).Receive,
+		"start refund": (*ReturnRequest).StartRefund,
+		"abort refund": (*ReturnRequest).AbortRefund,
+		"refund":       func(r *ReturnRequest) error { return r.Refund("refund-1") },
+	}
+
+	tests := []struct {
+		from       ReturnStatus
+		transition string
+		want       ReturnStatus // empty when the transition is rejected
+	}{
+		{from: ReturnStatusRequested, transition: "approve", want: ReturnStatusApproved},
+		{from: ReturnStatusRequested, transition: "reject", want: ReturnStatusRejected},
+		{from: ReturnStatusRequested, transition: "receive"},
+		{from: ReturnStatusApproved, transition: "receive", want: ReturnStatusReceived},
+		{from: ReturnStatusApproved, transition: "reject"},
+		{from: ReturnStatusApproved, transition: "start refund"},
+		{from: ReturnStatusRejected, transition: "approve"},
+		{from: ReturnStatusReceived, transition: "start refund", want: ReturnStatusRefunding},
+		{from: ReturnStatusReceived, transition: "refund"},
+		{from: ReturnStatusRefunding, transition: "refund", want: ReturnStatusRefunded},
+		{from: ReturnStatusRefunding, transition: "abort refund", want: ReturnStatusReceived},
+		{from: ReturnStatusRefunding, transition: "start refund"},
+		{from: ReturnStatusRefunded, transition: "start refund"},
+		{from: ReturnStatusRefunded, transition: "abort refund"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" "+tt.transition, func(t *testing.T) {
+			rma := &ReturnRequest{ID: "rma-1", OrderID: "order-1", Status: tt.from, RefundAmount: 10}
+
+			err := transitions[tt.transition](rma)
+			if tt.want == "" {
+				assert.ErrorIs(t, err, ErrReturnInvalidStatus)
+				assert.Equal(t, tt.from, rma.Status)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rma.Status)
+		})
+	}
+}
+
+func TestOrderItemReturnAllocations(t *testing.T) {
+	item := OrderItem{Quantity: 5, Allocations: []StockAllocation{
+		{WarehouseID: "w1", Quantity: 2},
+		{WarehouseID: "w2", Quantity: 3},
+	}}
+
+	tests := []struct {
+		name            string
+		item            OrderItem
+		alreadyReturned int
+		quantity        int
+		want            []StockAllocation
+	}{
+		{name: "first units go back to the first warehouse", item: item, quantity: 1,
+			want: []StockAllocation{{WarehouseID: "w1", Quantity: 1}}},
+		{name: "spans warehouses", item: item, quantity: 4,
+			want: []StockAllocation{{WarehouseID: "w1", Quantity: 2}, {WarehouseID: "w2", Quantity: 2}}},
+		{name: "skips units returned earlier", item: item, alreadyReturned: 3, quantity: 2,
+			want: []StockAllocation{{WarehouseID: "w2", Quantity: 2}}},
+		{name: "without allocations", item: OrderItem{Quantity: 2}, quantity: 2,
+			want: []StockAllocation{{Quantity: 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.item.ReturnAllocations(tt.alreadyReturned, tt.quantity))
+		})
+	}
+}
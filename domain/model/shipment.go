@@ -0,0 +1,247 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shipment domain errors are defined in domain_error.go
+
+// ShipmentStatus represents the status of a shipment
+type ShipmentStatus string
+
+const (
+	ShipmentStatusPending   ShipmentStatus = "pending"
+	ShipmentStatusShipped   ShipmentStatus = "shipped"
+	ShipmentStatusDelivered ShipmentStatus = "delivered"
+)
+
+// FulfillmentStatus represents the fulfillment status of a shipped item
+type FulfillmentStatus string
+
+const (
+	FulfillmentStatusPending   FulfillmentStatus = "pending"
+	FulfillmentStatusShipped   FulfillmentStatus = "shipped"
+	FulfillmentStatusDelivered FulfillmentStatus = "delivered"
+)
+
+// Shipment represents a parcel carrying some or all of the items of an order
+type Shipment struct {
+	ID             string
+	OrderID        string
+	Carrier        string
+	Service        string
+	TrackingNumber string
+	Cost           float64
+	Status         ShipmentStatus
+	Items          []ShipmentItem
+	ShippedAt      *time.Time
+	DeliveredAt    *time.Time
+	Version        int // incremented on every update, used for optimistic locking
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	events []DomainEvent
+}
+
+// ShipmentItem represents a quantity of an order item packed in a shipment
+type ShipmentItem struct {
+	ID          string
+	ShipmentID  string
+	OrderItemID string
+	ProductID   string
+	Quantity    int
+	Status      FulfillmentStatus
+}
+
+// ShippingRate represents a carrier price quote
+type ShippingRate struct {
+	Carrier       string
+	Service       string
+	Amount        float64
+	EstimatedDays int
+}
+
+// NewShipment creates a new pending shipment for items of an order.
+// allocatedQty holds the quantities already packed in other shipments per order item.
+func NewShipment(order *Order, carrier, service, trackingNumber string, items []ShipmentItem, allocatedQty map[string]int) (*Shipment, error) {
+	if !order.IsShippable() {
+		return nil, ErrOrderNotShippable
+	}
+
+	if carrier == "" {
+		return nil, ErrShipmentCarrierRequired
+	}
+
+	if len(items) == 0 {
+		return nil, ErrShipmentItemsRequired
+	}
+
+	orderItems := make(map[string]OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		orderItems[item.ID] = item
+	}
+
+	shipmentID := uuid.New().String()
+	packed := make(map[string]int, len(items))
+	for i := range items {
+		orderItem, ok := orderItems[items[i].OrderItemID]
+		if !ok {
+			return nil, ErrShipmentItemInvalid
+		}
+		if items[i].Quantity <= 0 {
+			return nil, ErrShipmentQuantityInvalid
+		}
+
+		packed[orderItem.ID] += items[i].Quantity
+		if packed[orderItem.ID]+allocatedQty[orderItem.ID] > orderItem.Quantity {
+			return nil, ErrShipmentQuantityInvalid
+		}
+
+		items[i].ID = uuid.New().String()
+		items[i].ShipmentID = shipmentID
+		items[i].ProductID = orderItem.ProductID
+		items[i].Status = FulfillmentStatusPending
+	}
+
+	shipment := &Shipment{
+		ID:             shipmentID,
+		OrderID:        order.ID,
+		Carrier:        carrier,
+		Service:        service,
+		TrackingNumber: trackingNumber,
+		Status:         ShipmentStatusPending,
+		Items:          items,
+		Version:        1,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	shipment.recordEvent(ShipmentCreatedEvent{
+		ShipmentID: shipment.ID,
+		OrderID:    order.ID,
+		Carrier:    carrier,
+		ItemCount:  len(items),
+	})
+
+	return shipment, nil
+}
+
+// HasShipped reports whether the shipment was handed over to the carrier
+func (s *Shipment) HasShipped() bool {
+	return s.Status != ShipmentStatusPending
+}
+
+// Ship hands the shipment over to the carrier. A tracking number is required,
+// either set at creation or given here.
+func (s *Shipment) Ship(trackingNumber string) error {
+	if s.Status != ShipmentStatusPending {
+		return ErrShipmentInvalidStatus
+	}
+
+	if trackingNumber != "" {
+		s.TrackingNumber = trackingNumber
+	}
+	if s.TrackingNumber == "" {
+		return ErrShipmentTrackingRequired
+	}
+
+	now := time.Now()
+	for i := range s.Items {
+		s.Items[i].Status = FulfillmentStatusShipped
+	}
+	s.Status = ShipmentStatusShipped
+	s.ShippedAt = &now
+	s.UpdatedAt = now
+
+	s.recordEvent(ShipmentShippedEvent{
+		ShipmentID:     s.ID,
+		OrderID:        s.OrderID,
+		Carrier:        s.Carrier,
+		TrackingNumber: s.TrackingNumber,
+	})
+
+	return nil
+}
+
+// Deliver marks the given order items of the shipment as delivered, or all of them when none are given.
+// The shipment is delivered once every item is.
+func (s *Shipment) Deliver(orderItemIDs ...string) error {
+	if s.Status != ShipmentStatusShipped {
+		return ErrShipmentInvalidStatus
+	}
+
+	selected := make(map[string]bool, len(orderItemIDs))
+	for _, id := range orderItemIDs {
+		selected[id] = true
+	}
+
+	matched := 0
+	for i := range s.Items {
+		if len(selected) > 0 && !selected[s.Items[i].OrderItemID] {
+			continue
+		}
+		s.Items[i].Status = FulfillmentStatusDelivered
+		matched++
+	}
+	if len(selected) > 0 && matched != len(selected) {
+		return ErrShipmentItemInvalid
+	}
+
+	now := time.Now()
+	s.UpdatedAt = now
+
+	for _, item := range s.Items {
+		if item.Status != FulfillmentStatusDelivered {
+			return nil
+		}
+	}
+
+	s.Status = ShipmentStatusDelivered
+	s.DeliveredAt = &now
+
+	s.recordEvent(ShipmentDeliveredEvent{
+		ShipmentID: s.ID,
+		OrderID:    s.OrderID,
+	})
+
+	return nil
+}
+
+// Events returns and clears domain events
+func (s *Shipment) Events() []DomainEvent {
+	events := s.events
+	s.events = nil
+	return events
+}
+
+func (s *Shipment) recordEvent(event DomainEvent) {
+	s.events = append(s.events, event)
+}
+
+// Shipment domain events
+type ShipmentCreatedEvent struct {
+	ShipmentID string
+	OrderID    string
+	Carrier    string
+	ItemCount  int
+}
+
+func (e ShipmentCreatedEvent) EventName() string { return "shipment.created" }
+
+type ShipmentShippedEvent struct {
+	ShipmentID     string
+	OrderID        string
+	Carrier        string
+	TrackingNumber string
+}
+
+func (e ShipmentShippedEvent) EventName() string { return "shipment.shipped" }
+
+type ShipmentDeliveredEvent struct {
+	ShipmentID string
+	OrderID    string
+}
+
+func (e ShipmentDeliveredEvent) EventName() string { return "shipment.delivered" }
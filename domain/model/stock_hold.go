@@ -0,0 +1,147 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Stock hold domain errors are defined in domain_error.go
+
+// orderHoldKeyPrefix prefixes the key of the hold taken for an order
+const orderHoldKeyPrefix = "order:"
+
+// StockHoldReleaseReason tells why a stock hold was removed
+type StockHoldReleaseReason string
+
+const (
+	StockHoldReleased  StockHoldReleaseReason = "released"  // given up by the cart or the order
+	StockHoldExpired   StockHoldReleaseReason = "expired"   // not committed before its TTL
+	StockHoldCommitted StockHoldReleaseReason = "committed" // converted into a permanent stock decrement
+)
+
+// StockHoldItem is a quantity of a product, or of one of its variants, set aside by a hold
+type StockHoldItem struct {
+	ProductID string
+	SKU       string // required for products with variants
+	Quantity  int
+}
+
+// StockHold sets stock aside for a cart or an order without decrementing it,
+// until it expires, is released or is committed
+type StockHold struct {
+	Key       string // cart or order reference, unique among active holds
+	Items     []StockHoldItem
+	ExpiresAt time.Time
+	CreatedAt time.Time
+
+	events []DomainEvent
+}
+
+// StockHoldRef identifies a hold across tenants: each tenant chooses its own keys
+type StockHoldRef struct {
+	TenantID string
+	Key      string
+}
+
+// StockAvailability is the stock of a SKU that is not set aside by active holds
+type StockAvailability struct {
+	SKU       string
+	Stock     int
+	Held      int
+	Available int
+}
+
+// OrderHoldKey returns the key of the stock hold taken for an order
+func OrderHoldKey(orderID string) string {
+	return orderHoldKeyPrefix + orderID
+}
+
+// NewStockHold creates a hold expiring after ttl. Items of the same SKU are merged.
+func NewStockHold(key string, items []StockHoldItem, ttl time.Duration) (*StockHold, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, ErrStockHoldKeyRequired
+	}
+	if ttl <= 0 {
+		return nil, ErrStockHoldTTLInvalid
+	}
+	if len(items) == 0 {
+		return nil, ErrStockHoldItemsRequired
+	}
+
+	merged := make([]StockHoldItem, 0, len(items))
+	index := make(map[StockHoldItem]int, len(items))
+	for _, item := range items {
+		if item.ProductID == "" {
+			return nil, ErrStockHoldItemInvalid
+		}
+		if item.Quantity <= 0 {
+			return nil, ErrStockHoldQuantityInvalid
+		}
+		id := StockHoldItem{ProductID: item.ProductID, SKU: item.SKU}
+		if i, ok := index[id]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[id] = len(merged)
+		merged = append(merged, item)
+	}
+
+	now := time.Now()
+	hold := &StockHold{
+		Key:       key,
+		Items:     merged,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+
+	hold.recordEvent(StockHoldCreatedEvent{
+		Key:       hold.Key,
+		Items:     hold.Items,
+		ExpiresAt: hold.ExpiresAt,
+	})
+
+	return hold, nil
+}
+
+// Expired reports whether the hold no longer sets stock aside at the given time
+func (h *StockHold) Expired(now time.Time) bool {
+	return !now.Before(h.ExpiresAt)
+}
+
+// Release records that the hold was removed for the given reason
+func (h *StockHold) Release(reason StockHoldReleaseReason) {
+	h.recordEvent(StockHoldReleasedEvent{
+		Key:    h.Key,
+		Items:  h.Items,
+		Reason: reason,
+	})
+}
+
+// Events returns and clears domain events
+func (h *StockHold) Events() []DomainEvent {
+	events := h.events
+	h.events = nil
+	return events
+}
+
+func (h *StockHold) recordEvent(event DomainEvent) {
+	h.events = append(h.events, event)
+}
+
+// Stock hold domain events
+type StockHoldCreatedEvent struct {
+	Key       string
+	Items     []StockHoldItem
+	ExpiresAt time.Time
+}
+
+func (e StockHoldCreatedEvent) EventName() string { return "stock_hold.created" }
+
+type StockHoldReleasedEvent struct {
+	Key    string
+	Items  []StockHoldItem
+	Reason StockHoldReleaseReason
+}
+
+func (e StockHoldReleasedEvent) EventName() string { return "stock_hold.released" }
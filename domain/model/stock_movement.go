@@ -0,0 +1,137 @@
+package model
+
+import (
+	"time"
+)
+
+// StockMovementReason explains why the stock of a product changed
+type StockMovementReason string
+
+const (
+	StockMovementReasonInitial    StockMovementReason = "initial" // opening stock of a product or variant
+	StockMovementReasonOrder      StockMovementReason = "order"
+	StockMovementReasonReturn     StockMovementReason = "return"
+	StockMovementReasonManual     StockMovementReason = "manual"
+	StockMovementReasonAdjustment StockMovementReason = "adjustment" // correction after a stock count
+	StockMovementReasonImport     StockMovementReason = "import"
+)
+
+// IsValid checks if the reason is valid
+func (r StockMovementReason) IsValid() bool {
+	switch r {
+	case StockMovementReasonInitial, StockMovementReasonOrder, StockMovementReasonReturn,
+		StockMovementReasonManual, StockMovementReasonAdjustment, StockMovementReasonImport:
+		return true
+	}
+	return false
+}
+
+// IsManual reports whether the reason can be given to a stock change entered by hand.
+// The other reasons are recorded by the orders, returns and imports causing the change.
+func (r StockMovementReason) IsManual() bool {
+	return r == StockMovementReasonManual || r == StockMovementReasonAdjustment
+}
+
+// StockChange describes a change to the stock of a product or one of its variants
+type StockChange struct {
+	SKU         string // empty for products without variants
+	WarehouseID string // empty for stock not held at a warehouse
+	Quantity    int
+	Reason      StockMovementReason
+	ReferenceID string // the order, return or import causing the change
+}
+
+// Validate validates the stock change
+func (c StockChange) Validate() error {
+	if !c.Reason.IsValid() {
+		return ErrStockMovementReasonInvalid
+	}
+	return nil
+}
+
+// StockMovement is an entry of the append-only stock ledger.
+// The stock of a product always equals the sum of the quantities of its movements.
+type StockMovement struct {
+	ID          string
+	TenantID    string // tenant of the product
+	ProductID   string
+	SKU         string // empty for the stock of products without variants
+	WarehouseID string
+	Quantity    int // positive when stock is added, negative when taken
+	Reason      StockMovementReason
+	ReferenceID string
+	CreatedAt   time.Time
+}
+
+// NewStockMovement creates a ledger entry for a stock change of the product, whose quantity is signed
+func NewStockMovement(product *Product, change StockChange) *StockMovement {
+	return &StockMovement{
+		TenantID:    product.TenantID,
+		ProductID:   product.ID,
+		SKU:         change.SKU,
+		WarehouseID: change.WarehouseID,
+		Quantity:    change.Quantity,
+		Reason:      change.Reason,
+		ReferenceID: change.ReferenceID,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// StockLevels returns the stock by SKU, keyed by the empty SKU for products without variants
+func (p *Product) StockLevels() map[string]int {
+	if !p.HasVariants() {
+		return map[string]int{"": p.Stock}
+	}
+	levels := make(map[string]int, len(p.Variants))
+	for _, variant := range p.Variants {
+		levels[variant.SKU] = variant.Stock
+	}
+	return levels
+}
+
+// StockDrift is a mismatch between the stock of a product and the total of its ledger
+type StockDrift struct {
+	ProductID   string
+	SKU         string
+	Stock       int
+	LedgerStock int
+}
+
+// DetectStockDrift compares the stock of a product with ledger totals by SKU
+func (p *Product) DetectStockDrift(ledger map[string]int) []StockDrift {
+	var drifts []StockDrift
+	levels := p.StockLevels()
+	for sku, stock := range levels {
+		if ledger[sku] != stock {
+			drifts = append(drifts, StockDrift{ProductID: p.ID, SKU: sku, Stock: stock, LedgerStock: ledger[sku]})
+		}
+	}
+	// Ledger stock left under SKUs the product no longer has
+	for sku, ledgerStock := range ledger {
+		if _, ok := levels[sku]; !ok && ledgerStock != 0 {
+			drifts = append(drifts, StockDrift{ProductID: p.ID, SKU: sku, LedgerStock: ledgerStock})
+		}
+	}
+	return drifts
+}
+
+// RecordStockDrift records a drift event for each mismatch found by reconciliation
+func (p *Product) RecordStockDrift(drifts []StockDrift) {
+	for _, drift := range drifts {
+		p.recordEvent(StockDriftDetectedEvent{
+			ProductID:   p.ID,
+			SKU:         drift.SKU,
+			Stock:       drift.Stock,
+			LedgerStock: drift.LedgerStock,
+		})
+	}
+}
+
+type StockDriftDetectedEvent struct {
+	ProductID   string
+	SKU         string
+	Stock       int
+	LedgerStock int
+}
+
+func (e StockDriftDetectedEvent) EventName() string { return "product.stock_drift_detected" }
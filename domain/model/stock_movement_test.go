@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockMovementReason(t *testing.T) {
+	tests := []struct {
+		reason     StockMovementReason
+		wantValid  bool
+		wantManual bool
+	}{
+		{reason: StockMovementReasonManual, wantValid: true, wantManual: true},
+		{reason: StockMovementReasonAdjustment, wantValid: true, wantManual: true},
+		{reason: StockMovementReasonInitial, wantValid: true},
+		{reason: StockMovementReasonOrder, wantValid: true},
+		{reason: StockMovementReasonReturn, wantValid: true},
+		{reason: StockMovementReasonImport, wantValid: true},
+		{reason: "gift"},
+		{reason: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.reason), func(t *testing.T) {
+			assert.Equal(t, tt.wantValid, tt.reason.IsValid())
+			assert.Equal(t, tt.wantManual, tt.reason.IsManual())
+		})
+	}
+}
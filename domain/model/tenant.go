@@ -0,0 +1,71 @@
+package model
+
+import (
+	"context"
+	"regexp"
+)
+
+// Tenant errors are defined in domain_error.go
+
+// DefaultTenantID is the tenant of requests that name none, and of data created before tenants
+const DefaultTenantID = "default"
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// ValidateTenantID validates a tenant ID: lowercase letters, digits and dashes, so it also works
+// as a subdomain and as a cache key segment
+func ValidateTenantID(tenantID string) error {
+	if !tenantIDPattern.MatchString(tenantID) {
+		return ErrTenantInvalid
+	}
+	return nil
+}
+
+type tenantContextKey struct{}
+
+// ContextWithTenant returns a copy of ctx scoped to the tenant
+func ContextWithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant ctx is scoped to. It is empty outside requests, where the
+// repositories refuse ctx with ErrTenantMissing unless it opted out with ContextWithoutTenant.
+func TenantFromContext(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantContextKey{}).(string)
+	return tenantID
+}
+
+// TenantForCreate returns the tenant new data created with ctx belongs to
+func TenantForCreate(ctx context.Context) string {
+	if tenantID := TenantFromContext(ctx); tenantID != "" {
+		return tenantID
+	}
+	return DefaultTenantID
+}
+
+// EnsureTenant scopes ctx to tenantID unless it already is scoped, so that work done for data loaded
+// across tenants, such as the events of a scheduled job, stays in the tenant of that data
+func EnsureTenant(ctx context.Context, tenantID string) context.Context {
+	if tenantID == "" || TenantFromContext(ctx) != "" {
+		return ctx
+	}
+	return ContextWithTenant(ctx, tenantID)
+}
+
+type allTenantsContextKey struct{}
+
+// ContextWithoutTenant returns a copy of ctx that sees every tenant, for work that spans tenants or
+// finds its tenant from the data, such as scheduled jobs and gateway webhooks
+func ContextWithoutTenant(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, tenantContextKey{}, "")
+	return context.WithValue(ctx, allTenantsContextKey{}, true)
+}
+
+// SeesAllTenants reports whether ctx has no tenant because it opted out with ContextWithoutTenant
+func SeesAllTenants(ctx context.Context) bool {
+	if TenantFromContext(ctx) != "" {
+		return false
+	}
+	all, _ := ctx.Value(allTenantsContextKey{}).(bool)
+	return all
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantContext(t *testing.T) {
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		wantTenant     string
+		wantAllTenants bool
+	}{
+		{name: "no tenant", ctx: context.Background()},
+		{name: "scoped to a tenant", ctx: ContextWithTenant(context.Background(), "acme"), wantTenant: "acme"},
+		{name: "opted out of tenant scoping", ctx: ContextWithoutTenant(context.Background()), wantAllTenants: true},
+		{name: "opted out of another tenant", ctx: ContextWithoutTenant(ContextWithTenant(context.Background(), "acme")), wantAllTenants: true},
+		{name: "scoped again after opting out", ctx: ContextWithTenant(ContextWithoutTenant(context.Background()), "acme"), wantTenant: "acme"},
+		{name: "work across tenants scoped to the tenant of its data", ctx: EnsureTenant(ContextWithoutTenant(context.Background()), "acme"), wantTenant: "acme"},
+		{name: "request keeps its tenant", ctx: EnsureTenant(ContextWithTenant(context.Background(), "acme"), "globex"), wantTenant: "acme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantTenant, TenantFromContext(tt.ctx))
+			assert.Equal(t, tt.wantAllTenants, SeesAllTenants(tt.ctx))
+		})
+	}
+}
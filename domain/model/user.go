@@ -11,24 +11,47 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID        string
-	Email     string
-	Name      string
-	Password  string // hashed password
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt *time.Time
+	ID       string
+	TenantID string // set from the request context when the user is created
+	Email    string
+	Name     string
+	Password string // hashed password, see repo.IPasswordHasher
+	Roles    []Role
+	// Permissions are granted directly, in addition to those of the roles
+	Permissions []Permission
+	// EmailVerifiedAt is set once the user proved they own the email address
+	EmailVerifiedAt *time.Time
+	Version         int // incremented on every update, used for optimistic locking
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       *time.Time
 
 	events []DomainEvent
 }
 
-// NewUser creates a new user with validation
-func NewUser(email, name, password string) (*User, error) {
+// UserPasswordMinLength is the minimum length of a plaintext password
+const UserPasswordMinLength = 6
+
+// ValidatePassword validates a plaintext password before it is hashed
+func ValidatePassword(password string) error {
+	if password == "" {
+		return ErrUserPasswordRequired
+	}
+	if len(password) < UserPasswordMinLength {
+		return ErrUserPasswordTooShort
+	}
+	return nil
+}
+
+// NewUser creates a new user with validation. The password must already be hashed.
+func NewUser(email, name, hashedPassword string) (*User, error) {
 	user := &User{
 		ID:        uuid.New().String(),
 		Email:     email,
 		Name:      name,
-		Password:  password,
+		Password:  hashedPassword,
+		Roles:     []Role{RoleCustomer},
+		Version:   1,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -88,6 +111,72 @@ func (u *User) Update(name string) error {
 func (u *User) UpdatePassword(hashedPassword string) {
 	u.Password = hashedPassword
 	u.UpdatedAt = time.Now()
+
+	u.recordEvent(UserPasswordChangedEvent{
+		ID: u.ID,
+	})
+}
+
+// RehashPassword replaces the hash of an unchanged password, made with outdated settings
+func (u *User) RehashPassword(hashedPassword string) {
+	u.Password = hashedPassword
+}
+
+// ResetPassword replaces a forgotten password, after the user proved they own the email address
+func (u *User) ResetPassword(hashedPassword string) {
+	u.Password = hashedPassword
+	u.UpdatedAt = time.Now()
+
+	u.recordEvent(UserPasswordResetEvent{
+		ID: u.ID,
+	})
+}
+
+// IsEmailVerified reports whether the user verified their email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// VerifyEmail marks the email address of the user as verified
+func (u *User) VerifyEmail() error {
+	if u.EmailVerifiedAt != nil {
+		return ErrUserEmailAlreadyVerified
+	}
+
+	now := time.Now()
+	u.EmailVerifiedAt = &now
+	u.UpdatedAt = now
+
+	u.recordEvent(UserEmailVerifiedEvent{
+		ID:    u.ID,
+		Email: u.Email,
+	})
+
+	return nil
+}
+
+// Can reports whether the user's roles or direct permissions allow permission
+func (u *User) Can(permission Permission) bool {
+	return Grants(u.Roles, u.Permissions, permission)
+}
+
+// AssignRoles replaces the roles and the directly granted permissions of the user
+func (u *User) AssignRoles(roles []Role, permissions []Permission) error {
+	if err := ValidateRoles(roles, permissions); err != nil {
+		return err
+	}
+
+	u.Roles = roles
+	u.Permissions = permissions
+	u.UpdatedAt = time.Now()
+
+	u.recordEvent(UserRolesChangedEvent{
+		ID:          u.ID,
+		Roles:       roles,
+		Permissions: permissions,
+	})
+
+	return nil
 }
 
 // MarkDeleted marks the user as deleted
@@ -131,6 +220,33 @@ type UserUpdatedEvent struct {
 
 func (e UserUpdatedEvent) EventName() string { return "user.updated" }
 
+type UserPasswordChangedEvent struct {
+	ID string
+}
+
+func (e UserPasswordChangedEvent) EventName() string { return "user.password_changed" }
+
+type UserPasswordResetEvent struct {
+	ID string
+}
+
+func (e UserPasswordResetEvent) EventName() string { return "user.password_reset" }
+
+type UserEmailVerifiedEvent struct {
+	ID    string
+	Email string
+}
+
+func (e UserEmailVerifiedEvent) EventName() string { return "user.email_verified" }
+
+type UserRolesChangedEvent struct {
+	ID          string
+	Roles       []Role
+	Permissions []Permission
+}
+
+func (e UserRolesChangedEvent) EventName() string { return "user.roles_changed" }
+
 type UserDeletedEvent struct {
 	ID string
 }
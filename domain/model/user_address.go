@@ -0,0 +1,118 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
+)
+
+// User address domain errors are defined in domain_error.go
+
+// UserAddress is an entry of the address book of a user. A user has at most one default shipping
+// and one default billing address; orders copy the address they use, so later edits or deletions
+// do not change past orders.
+type UserAddress struct {
+	ID       string
+	TenantID string // set from the request context when the address is created
+	UserID   string
+	Label    string // e.g. "Home"
+	vo.Address
+	DefaultShipping bool
+	DefaultBilling  bool
+	Version         int // incremented on every update, used for optimistic locking
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+
+	events []DomainEvent
+}
+
+// NewUserAddress creates a new address book entry of a user
+func NewUserAddress(userID, label string, address vo.Address, defaultShipping, defaultBilling bool) (*UserAddress, error) {
+	if err := address.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	a := &UserAddress{
+		ID:              uuid.New().String(),
+		UserID:          userID,
+		Label:           strings.TrimSpace(label),
+		Address:         address,
+		DefaultShipping: defaultShipping,
+		DefaultBilling:  defaultBilling,
+		Version:         1,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	a.recordEvent(UserAddressCreatedEvent{
+		ID:     a.ID,
+		UserID: userID,
+	})
+
+	return a, nil
+}
+
+// Update replaces the label, the address and the default flags of the entry
+func (a *UserAddress) Update(label string, address vo.Address, defaultShipping, defaultBilling bool) error {
+	if err := address.Validate(); err != nil {
+		return err
+	}
+
+	a.Label = strings.TrimSpace(label)
+	a.Address = address
+	a.DefaultShipping = defaultShipping
+	a.DefaultBilling = defaultBilling
+	a.UpdatedAt = time.Now()
+
+	a.recordEvent(UserAddressUpdatedEvent{
+		ID:     a.ID,
+		UserID: a.UserID,
+	})
+
+	return nil
+}
+
+// MarkDeleted records the address deletion
+func (a *UserAddress) MarkDeleted() {
+	a.recordEvent(UserAddressDeletedEvent{
+		ID:     a.ID,
+		UserID: a.UserID,
+	})
+}
+
+// Events returns and clears domain events
+func (a *UserAddress) Events() []DomainEvent {
+	events := a.events
+	a.events = nil
+	return events
+}
+
+func (a *UserAddress) recordEvent(event DomainEvent) {
+	a.events = append(a.events, event)
+}
+
+// User address domain events
+type UserAddressCreatedEvent struct {
+	ID     string
+	UserID string
+}
+
+func (e UserAddressCreatedEvent) EventName() string { return "user_address.created" }
+
+type UserAddressUpdatedEvent struct {
+	ID     string
+	UserID string
+}
+
+func (e UserAddressUpdatedEvent) EventName() string { return "user_address.updated" }
+
+type UserAddressDeletedEvent struct {
+	ID     string
+	UserID string
+}
+
+func (e UserAddressDeletedEvent) EventName() string { return "user_address.deleted" }
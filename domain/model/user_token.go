@@ -0,0 +1,59 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User token domain errors are defined in domain_error.go
+
+// UserTokenPurpose tells what a user token may be used for
+type UserTokenPurpose string
+
+const (
+	UserTokenEmailVerification UserTokenPurpose = "email_verification"
+	UserTokenPasswordReset     UserTokenPurpose = "password_reset"
+)
+
+// UserToken is a single use token sent to the email address of a user, to verify the address or
+// reset the password. Only the SHA-256 hash of the token is stored.
+type UserToken struct {
+	ID        string
+	TenantID  string // tenant of the user, tokens are only accepted by requests of that tenant
+	UserID    string
+	Purpose   UserTokenPurpose
+	Hash      string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// NewUserToken creates a token for the user valid for ttl and returns it with the plaintext token,
+// which is not stored and cannot be recovered later
+func NewUserToken(user *User, purpose UserTokenPurpose, ttl time.Duration) (*UserToken, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	plaintext := base64.RawURLEncoding.EncodeToString(secret)
+
+	now := time.Now()
+	token := &UserToken{
+		ID:        uuid.New().String(),
+		TenantID:  user.TenantID,
+		UserID:    user.ID,
+		Purpose:   purpose,
+		Hash:      HashUserToken(plaintext),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	return token, plaintext, nil
+}
+
+// HashUserToken returns the stored hash of a plaintext token
+func HashUserToken(plaintext string) string {
+	return hashSecret(plaintext)
+}
@@ -0,0 +1,10 @@
+package model
+
+// CheckVersion returns ErrVersionConflict when an expected version is given and differs from the current one.
+// An expected version of zero means the caller has no precondition.
+func CheckVersion(current, expected int) error {
+	if expected != 0 && current != expected {
+		return ErrVersionConflict
+	}
+	return nil
+}
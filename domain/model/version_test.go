@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  int
+		expected int
+		wantErr  error
+	}{
+		{name: "no precondition", current: 4, expected: 0},
+		{name: "matching version", current: 4, expected: 4},
+		{name: "stale version", current: 4, expected: 3, wantErr: ErrVersionConflict},
+		{name: "future version", current: 4, expected: 5, wantErr: ErrVersionConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, CheckVersion(tt.current, tt.expected), tt.wantErr)
+		})
+	}
+}
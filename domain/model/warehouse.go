@@ -0,0 +1,156 @@
+package model
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Warehouse domain errors are defined in domain_error.go
+
+const earthRadiusKm = 6371.0
+
+// Location is a point on the map
+type Location struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Validate checks the coordinates are in range
+func (l Location) Validate() error {
+	if l.Latitude < -90 || l.Latitude > 90 || l.Longitude < -180 || l.Longitude > 180 {
+		return ErrLocationInvalid
+	}
+	return nil
+}
+
+// DistanceTo returns the great-circle distance to another location in kilometers
+func (l Location) DistanceTo(other Location) float64 {
+	lat1, lat2 := l.Latitude*math.Pi/180, other.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (other.Longitude - l.Longitude) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// Warehouse is a place holding stock
+type Warehouse struct {
+	ID        string // MongoDB ObjectID
+	TenantID  string // set from the request context when the warehouse is created
+	Code      string // unique within the tenant, human readable identifier
+	Name      string
+	Location  Location
+	Active    bool // inactive warehouses keep their stock but are not allocated from
+	Version   int  // incremented on every update, used for optimistic locking
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	events []DomainEvent
+}
+
+// NewWarehouse creates a new active warehouse
+func NewWarehouse(code, name string, location Location) (*Warehouse, error) {
+	warehouse := &Warehouse{
+		Code:      strings.TrimSpace(code),
+		Name:      strings.TrimSpace(name),
+		Location:  location,
+		Active:    true,
+		Version:   1,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := warehouse.Validate(); err != nil {
+		return nil, err
+	}
+
+	warehouse.recordEvent(WarehouseCreatedEvent{
+		Code: warehouse.Code,
+		Name: warehouse.Name,
+	})
+
+	return warehouse, nil
+}
+
+// Validate validates the warehouse entity
+func (w *Warehouse) Validate() error {
+	if w.Code == "" {
+		return ErrWarehouseCodeRequired
+	}
+	if w.Name == "" {
+		return ErrWarehouseNameRequired
+	}
+	return w.Location.Validate()
+}
+
+// Update changes the name, location and status of the warehouse
+func (w *Warehouse) Update(name string, location Location, active bool) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrWarehouseNameRequired
+	}
+	if err := location.Validate(); err != nil {
+		return err
+	}
+
+	w.Name = name
+	w.Location = location
+	w.Active = active
+	w.UpdatedAt = time.Now()
+
+	w.recordEvent(WarehouseUpdatedEvent{
+		ID:     w.ID,
+		Name:   name,
+		Active: active,
+	})
+
+	return nil
+}
+
+// Events returns and clears domain events
+func (w *Warehouse) Events() []DomainEvent {
+	events := w.events
+	w.events = nil
+	return events
+}
+
+func (w *Warehouse) recordEvent(event DomainEvent) {
+	w.events = append(w.events, event)
+}
+
+// StockAllocation is the quantity of an item taken from one warehouse.
+// An empty WarehouseID means the stock was not assigned to any warehouse.
+type StockAllocation struct {
+	WarehouseID string
+	Quantity    int
+}
+
+// WarehouseStock is the stock of an item available at a warehouse
+type WarehouseStock struct {
+	Warehouse *Warehouse
+	Available int
+}
+
+// AllocationRequest asks an allocation strategy to pick warehouses for a quantity of an item
+type AllocationRequest struct {
+	Quantity    int
+	Destination *Location // where the item ships to, when known
+	Candidates  []WarehouseStock
+}
+
+// Warehouse domain events
+type WarehouseCreatedEvent struct {
+	Code string
+	Name string
+}
+
+func (e WarehouseCreatedEvent) EventName() string { return "warehouse.created" }
+
+type WarehouseUpdatedEvent struct {
+	ID     string
+	Name   string
+	Active bool
+}
+
+func (e WarehouseUpdatedEvent) EventName() string { return "warehouse.updated" }
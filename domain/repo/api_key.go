@@ -0,0 +1,24 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// IAPIKeyRepo defines the interface for API key repository operations
+type IAPIKeyRepo interface {
+	// Create creates a new API key
+	Create(ctx context.Context, tx Transaction, key *model.APIKey) (*model.APIKey, error)
+	// Update updates an existing API key, failing with model.ErrVersionConflict on a stale version
+	Update(ctx context.Context, tx Transaction, key *model.APIKey) error
+	// GetByID retrieves an API key by ID
+	GetByID(ctx context.Context, tx Transaction, id string) (*model.APIKey, error)
+	// GetByPrefix retrieves an API key by its lookup prefix
+	GetByPrefix(ctx context.Context, tx Transaction, prefix string) (*model.APIKey, error)
+	// List retrieves API keys with pagination, newest first
+	List(ctx context.Context, tx Transaction, offset, limit int) ([]*model.APIKey, int64, error)
+	// TouchLastUsed records when an API key was last used, without changing its version
+	TouchLastUsed(ctx context.Context, tx Transaction, id string, at time.Time) error
+}
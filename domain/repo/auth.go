@@ -0,0 +1,33 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// ITokenSigner defines the port signing and verifying tokens
+type ITokenSigner interface {
+	// Sign returns the signed token carrying the claims
+	Sign(claims *model.TokenClaims) (string, error)
+
+	// Parse verifies a token and returns its claims, or model.ErrTokenExpired or model.ErrTokenInvalid
+	Parse(token string) (*model.TokenClaims, error)
+}
+
+// IRefreshSessionStore defines the interface for login sessions, which revoke refresh tokens
+type IRefreshSessionStore interface {
+	// Save stores a session until it expires
+	Save(ctx context.Context, session *model.RefreshSession) error
+
+	// Rotate atomically replaces the current token of a session and extends it, if the current
+	// token is still tokenID. It reports whether the session was rotated.
+	Rotate(ctx context.Context, sessionID, tokenID, nextTokenID string, expiresAt time.Time) (bool, error)
+
+	// Revoke deletes a session, revoking its refresh token
+	Revoke(ctx context.Context, sessionID string) error
+
+	// RevokeByUser deletes every session of a user, revoking all of their refresh tokens
+	RevokeByUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// ICategoryRepo defines the interface for category repository operations
+type ICategoryRepo interface {
+	// Create creates a new category
+	Create(ctx context.Context, category *model.Category) (*model.Category, error)
+
+	// Update updates a category's name and position, failing with model.ErrVersionConflict on a stale version
+	Update(ctx context.Context, category *model.Category) error
+
+	// MoveSubtree completes the pending move of the category: it rewrites the paths of the categories
+	// under category.MovingFrom to start with the category's subtree path, then clears MovingFrom.
+	// It is idempotent, so an interrupted move can be completed by calling it again.
+	MoveSubtree(ctx context.Context, category *model.Category) error
+
+	// Delete deletes a category by ID
+	Delete(ctx context.Context, id string) error
+
+	// GetByID retrieves a category by ID
+	GetByID(ctx context.Context, id string) (*model.Category, error)
+
+	// GetByIDs retrieves the categories with the given IDs, skipping unknown IDs
+	GetByIDs(ctx context.Context, ids []string) ([]*model.Category, error)
+
+	// List retrieves all categories ordered by path
+	List(ctx context.Context) ([]*model.Category, error)
+
+	// ListSubtree retrieves the categories whose path starts with the given prefix
+	ListSubtree(ctx context.Context, pathPrefix string) ([]*model.Category, error)
+}
@@ -0,0 +1,19 @@
+package repo
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// IInvoiceRepo defines the interface for invoice repository operations
+type IInvoiceRepo interface {
+	// Create assigns the next gap-free number of the invoice's series and year and stores the invoice with its lines
+	Create(ctx context.Context, tx Transaction, invoice *model.Invoice) (*model.Invoice, error)
+	// GetByOrderID retrieves the invoice of an order with lines
+	GetByOrderID(ctx context.Context, tx Transaction, orderID string) (*model.Invoice, error)
+	// GetByReturnID retrieves the credit note of a return with lines
+	GetByReturnID(ctx context.Context, tx Transaction, returnID string) (*model.Invoice, error)
+	// ListCreditNotesByOrderID retrieves the credit notes of an order with lines, oldest first
+	ListCreditNotesByOrderID(ctx context.Context, tx Transaction, orderID string) ([]*model.Invoice, error)
+}
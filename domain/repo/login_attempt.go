@@ -0,0 +1,22 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// ILoginAttemptStore defines the interface counting failed logins and locking out their source,
+// an account or a client address, identified by key
+type ILoginAttemptStore interface {
+	// RecordFailure counts a failed login within the window and returns the failures counted so far
+	RecordFailure(ctx context.Context, key string, window time.Duration) (int, error)
+
+	// Lock locks the key out for duration and resets its failures
+	Lock(ctx context.Context, key string, duration time.Duration) error
+
+	// LockedFor returns how long the key stays locked out, zero when it is not
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+
+	// Clear resets the failures of the key and lifts its lockout
+	Clear(ctx context.Context, key string) error
+}
@@ -0,0 +1,13 @@
+package repo
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// INotifier defines the port sending notifications to users
+type INotifier interface {
+	// Notify sends the notification to its recipient
+	Notify(ctx context.Context, notification *model.Notification) error
+}
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
 )
@@ -11,7 +12,7 @@ type IOrderRepo interface {
 	// Create creates a new order with items
 	Create(ctx context.Context, tx Transaction, order *model.Order) (*model.Order, error)
 
-	// Update updates an existing order
+	// Update updates an existing order, failing with model.ErrVersionConflict on a stale version
 	Update(ctx context.Context, tx Transaction, order *model.Order) error
 
 	// Delete deletes an order by ID
@@ -23,11 +24,23 @@ type IOrderRepo interface {
 	// GetByUserID retrieves orders for a user with pagination
 	GetByUserID(ctx context.Context, tx Transaction, userID string, offset, limit int) ([]*model.Order, int64, error)
 
+	// GetByOrganizationID retrieves the orders placed on behalf of an organization with pagination
+	GetByOrganizationID(ctx context.Context, tx Transaction, organizationID string, offset, limit int) ([]*model.Order, int64, error)
+
 	// List retrieves orders with pagination
 	List(ctx context.Context, tx Transaction, offset, limit int) ([]*model.Order, int64, error)
 
-	// UpdateStatus updates the order status
-	UpdateStatus(ctx context.Context, tx Transaction, id string, status model.OrderStatus) error
+	// UpdateStatus updates the order status if the order is still at the given version
+	UpdateStatus(ctx context.Context, tx Transaction, id string, status model.OrderStatus, version int) error
+
+	// SaveAllocations replaces the warehouse allocations of the order items
+	SaveAllocations(ctx context.Context, tx Transaction, order *model.Order) error
+
+	// ListByStatusBefore retrieves orders in the given status created, or approved, before the cutoff, oldest first
+	ListByStatusBefore(ctx context.Context, tx Transaction, status model.OrderStatus, before time.Time, offset, limit int) ([]*model.Order, error)
+
+	// ListAwaitingApprovalBefore retrieves orders awaiting approval, not yet escalated, created before the cutoff, oldest first
+	ListAwaitingApprovalBefore(ctx context.Context, tx Transaction, before time.Time, offset, limit int) ([]*model.Order, error)
 }
 
 // IOrderCacheRepo defines the interface for order cache operations
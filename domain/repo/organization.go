@@ -0,0 +1,24 @@
+package repo
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// IOrganizationRepo defines the interface for organization repository operations
+type IOrganizationRepo interface {
+	// Create creates a new organization with its members and addresses
+	Create(ctx context.Context, tx Transaction, org *model.Organization) (*model.Organization, error)
+	// Update updates an existing organization and replaces its members and addresses,
+	// failing with model.ErrVersionConflict on a stale version
+	Update(ctx context.Context, tx Transaction, org *model.Organization) error
+	// Delete deletes an organization by ID with its members and addresses
+	Delete(ctx context.Context, tx Transaction, id string) error
+	// GetByID retrieves an organization by ID with its members and addresses
+	GetByID(ctx context.Context, tx Transaction, id string) (*model.Organization, error)
+	// List retrieves organizations with pagination, sorted by name
+	List(ctx context.Context, tx Transaction, offset, limit int) ([]*model.Organization, int64, error)
+	// ListByUserID retrieves the organizations a user is a member of, sorted by name
+	ListByUserID(ctx context.Context, tx Transaction, userID string) ([]*model.Organization, error)
+}
@@ -0,0 +1,14 @@
+package repo
+
+// IPasswordHasher defines the port hashing and verifying user passwords.
+// Hashes are self-describing: they carry the algorithm and its parameters.
+type IPasswordHasher interface {
+	// Hash hashes a password with the current settings
+	Hash(password string) (string, error)
+
+	// Verify reports, in constant time, whether the password matches the hash
+	Verify(hash, password string) (bool, error)
+
+	// NeedsRehash reports whether the hash was made with other settings than the current ones
+	NeedsRehash(hash string) bool
+}
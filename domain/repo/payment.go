@@ -0,0 +1,38 @@
+package repo
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// IPaymentRepo defines the interface for payment repository operations
+type IPaymentRepo interface {
+	// Create creates a new payment
+	Create(ctx context.Context, tx Transaction, payment *model.Payment) (*model.Payment, error)
+	// Update updates an existing payment, failing with model.ErrVersionConflict on a stale version
+	Update(ctx context.Context, tx Transaction, payment *model.Payment) error
+	// GetByID retrieves a payment by ID
+	GetByID(ctx context.Context, tx Transaction, id string) (*model.Payment, error)
+	// GetByGatewayRef retrieves a payment by the gateway transaction reference
+	GetByGatewayRef(ctx context.Context, tx Transaction, gatewayRef string) (*model.Payment, error)
+	// ListByOrderID retrieves all payments for an order, newest first
+	ListByOrderID(ctx context.Context, tx Transaction, orderID string) ([]*model.Payment, error)
+	// ApplyWebhookEvent updates the payment and records the gateway event ID in one transaction.
+	// It returns false, leaving the payment unchanged, when the event was already applied.
+	ApplyWebhookEvent(ctx context.Context, tx Transaction, eventID string, payment *model.Payment) (bool, error)
+}
+
+// IPaymentGateway defines the port to an external payment provider
+type IPaymentGateway interface {
+	// Authorize reserves the amount on the customer's payment method and returns the gateway reference
+	Authorize(ctx context.Context, paymentID string, amount float64) (string, error)
+	// Capture captures a previous authorization. Calls with the same idempotency key move money once.
+	Capture(ctx context.Context, gatewayRef string, amount float64, idempotencyKey string) error
+	// Void releases a previous authorization. Calls with the same idempotency key are applied once.
+	Void(ctx context.Context, gatewayRef string, idempotencyKey string) error
+	// Refund refunds part or all of a captured payment. Calls with the same idempotency key move money once.
+	Refund(ctx context.Context, gatewayRef string, amount float64, idempotencyKey string) error
+	// ParseWebhook verifies the webhook signature and decodes the notification
+	ParseWebhook(payload []byte, signature string) (*model.PaymentWebhookEvent, error)
+}
@@ -0,0 +1,27 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// IPriceHistoryRepo defines the interface for the price history of products
+type IPriceHistoryRepo interface {
+	// Append adds an entry
+	Append(ctx context.Context, entry *model.PriceEntry) error
+
+	// Transition stores the status, version and window of an entry if its stored status is still from,
+	// reporting whether it was
+	Transition(ctx context.Context, entry *model.PriceEntry, from model.PriceEntryStatus) (bool, error)
+
+	// Supersede closes at the given time the window of the active entries of a product's SKU, except exceptID
+	Supersede(ctx context.Context, productID, sku, exceptID string, at time.Time) error
+
+	// ListByProductID retrieves the entries of a product, latest effective first, with pagination
+	ListByProductID(ctx context.Context, productID string, offset, limit int) ([]*model.PriceEntry, int64, error)
+
+	// ListDue retrieves scheduled entries taking effect before the given time, earliest first
+	ListDue(ctx context.Context, before time.Time, limit int) ([]*model.PriceEntry, error)
+}
@@ -11,7 +11,7 @@ type IProductRepo interface {
 	// Create creates a new product
 	Create(ctx context.Context, product *model.Product) (*model.Product, error)
 
-	// Update updates an existing product
+	// Update updates an existing product, failing with model.ErrVersionConflict on a stale version
 	Update(ctx context.Context, product *model.Product) error
 
 	// Delete deletes a product by ID
@@ -26,8 +26,40 @@ type IProductRepo interface {
 	// List retrieves products with pagination
 	List(ctx context.Context, offset, limit int) ([]*model.Product, int64, error)
 
-	// UpdateStock updates product stock
-	UpdateStock(ctx context.Context, id string, quantity int) error
+	// ListByCategoryIDs retrieves products assigned to any of the categories with pagination
+	ListByCategoryIDs(ctx context.Context, categoryIDs []string, offset, limit int) ([]*model.Product, int64, error)
+
+	// ReplaceCategory reassigns products from a category to its replacement, or just unassigns them
+	// when replacementID is empty. It returns the IDs of the products changed.
+	ReplaceCategory(ctx context.Context, categoryID, replacementID string) ([]string, error)
+
+	// ListLowStock retrieves products with a low-stock threshold and a SKU at or below it, with pagination
+	ListLowStock(ctx context.Context, offset, limit int) ([]*model.Product, int64, error)
+
+	// GetBySKU retrieves the product owning the variant with the given SKU
+	GetBySKU(ctx context.Context, sku string) (*model.Product, error)
+
+	// UpdateStock updates the stock of a variant, or of the product when sku is empty,
+	// and the stock held at the warehouse when warehouseID is not empty
+	UpdateStock(ctx context.Context, id, sku, warehouseID string, quantity int) error
+
+	// ReserveStock atomically decrements stock, from the warehouse when warehouseID is not empty,
+	// failing with model.ErrProductInsufficientStock when not enough is left
+	ReserveStock(ctx context.Context, id, sku, warehouseID string, quantity int) error
+
+	// Search retrieves products matching normalized criteria, with facet counts over all matches
+	Search(ctx context.Context, criteria model.ProductSearchCriteria) (*model.ProductSearchResult, error)
+
+	// ListByNamesOrSKUs retrieves the products with any of the names or owning a variant with any of the SKUs
+	ListByNamesOrSKUs(ctx context.Context, names, skus []string) ([]*model.Product, error)
+
+	// BulkSave inserts the products without ID and updates the others if still at the version they were
+	// read with, in a single batched write. It returns the error of each product that could not be saved,
+	// indexed like products, such as model.ErrVersionConflict.
+	BulkSave(ctx context.Context, products []*model.Product) ([]error, error)
+
+	// Stream calls fn for every product, in creation order, until fn returns an error
+	Stream(ctx context.Context, fn func(*model.Product) error) error
 }
 
 // IProductCacheRepo defines the interface for product cache operations
@@ -0,0 +1,21 @@
+package repo
+
+import (
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// IProductRowReader reads the rows of a product file one at a time
+type IProductRowReader interface {
+	// Read returns the next row, or io.EOF after the last one. Rows that cannot be parsed are
+	// returned with Err set; other errors end the file.
+	Read() (*model.ProductRow, error)
+}
+
+// IProductRowWriter writes the rows of a product file
+type IProductRowWriter interface {
+	// Write writes a row
+	Write(row model.ProductRow) error
+
+	// Flush writes any buffered rows
+	Flush() error
+}
@@ -0,0 +1,12 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// IRateLimiter defines the port counting attempts within a fixed time window
+type IRateLimiter interface {
+	// Allow counts an attempt for the key and reports whether it stays within limit attempts per window
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+}
@@ -0,0 +1,27 @@
+package repo
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// IReturnRepo defines the interface for return request repository operations
+type IReturnRepo interface {
+	// Create creates a new return request with items
+	Create(ctx context.Context, tx Transaction, rma *model.ReturnRequest) (*model.ReturnRequest, error)
+	// Update updates the status of a return request, failing with model.ErrVersionConflict on a stale version
+	Update(ctx context.Context, tx Transaction, rma *model.ReturnRequest) error
+	// GetByID retrieves a return request by ID with items
+	GetByID(ctx context.Context, tx Transaction, id string) (*model.ReturnRequest, error)
+	// ListByOrderID retrieves all return requests for an order with items, newest first
+	ListByOrderID(ctx context.Context, tx Transaction, orderID string) ([]*model.ReturnRequest, error)
+}
+
+// IRefundRepo defines the interface for refund record operations
+type IRefundRepo interface {
+	// Create records a refund
+	Create(ctx context.Context, tx Transaction, refund *model.Refund) (*model.Refund, error)
+	// ListByOrderID retrieves all refunds for an order, newest first
+	ListByOrderID(ctx context.Context, tx Transaction, orderID string) ([]*model.Refund, error)
+}
@@ -0,0 +1,25 @@
+package repo
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// IShipmentRepo defines the interface for shipment repository operations
+type IShipmentRepo interface {
+	// Create creates a new shipment with items
+	Create(ctx context.Context, tx Transaction, shipment *model.Shipment) (*model.Shipment, error)
+	// Update updates a shipment and the status of its items, failing with model.ErrVersionConflict on a stale version
+	Update(ctx context.Context, tx Transaction, shipment *model.Shipment) error
+	// GetByID retrieves a shipment by ID with items
+	GetByID(ctx context.Context, tx Transaction, id string) (*model.Shipment, error)
+	// ListByOrderID retrieves all shipments for an order with items, oldest first
+	ListByOrderID(ctx context.Context, tx Transaction, orderID string) ([]*model.Shipment, error)
+}
+
+// ICarrierRateProvider defines the port to carrier shipping rates
+type ICarrierRateProvider interface {
+	// Quote returns the rates of every carrier service for shipping the given number of items
+	Quote(ctx context.Context, itemCount int) ([]model.ShippingRate, error)
+}
@@ -0,0 +1,34 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// IStockHoldStore defines the interface for time-bounded stock holds.
+// Expired holds stop setting stock aside as soon as they expire, even before they are deleted.
+// Hold keys are chosen by the callers, so a hold belongs to the tenant of ctx and is only found with it.
+type IStockHoldStore interface {
+	// Create atomically checks that stock[i] minus the active holds covers hold.Items[i] for every item
+	// and stores the hold. It returns model.ErrProductInsufficientStock when an item is not covered
+	// and model.ErrStockHoldExists when the key already has an active hold.
+	Create(ctx context.Context, hold *model.StockHold, stock []int) error
+
+	// Get retrieves a hold by key, including an expired one not yet deleted
+	Get(ctx context.Context, key string) (*model.StockHold, error)
+
+	// Delete atomically removes a hold and returns it, or nil when there is none
+	Delete(ctx context.Context, key string) (*model.StockHold, error)
+
+	// DeleteExpired atomically removes a hold only if it expired before the given time,
+	// so a newer hold under the same key is kept. It returns the removed hold, or nil.
+	DeleteExpired(ctx context.Context, key string, before time.Time) (*model.StockHold, error)
+
+	// HeldQuantities totals the active holds on a product by SKU
+	HeldQuantities(ctx context.Context, productID string, skus []string) (map[string]int, error)
+
+	// ListExpired retrieves the holds of every tenant that expired before the given time, oldest first
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]model.StockHoldRef, error)
+}
@@ -0,0 +1,22 @@
+package repo
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// IStockMovementRepo defines the interface for the append-only stock ledger
+type IStockMovementRepo interface {
+	// Append adds movements to the ledger
+	Append(ctx context.Context, movements ...*model.StockMovement) error
+
+	// ListByProductID retrieves the movements of a product, newest first, with pagination
+	ListByProductID(ctx context.Context, productID string, offset, limit int) ([]*model.StockMovement, int64, error)
+
+	// SumByProductIDs totals the movement quantities of the products by product ID and SKU
+	SumByProductIDs(ctx context.Context, productIDs []string) (map[string]map[string]int, error)
+
+	// ProductIDsWithReason returns which of the products have at least one movement with the reason
+	ProductIDsWithReason(ctx context.Context, productIDs []string, reason model.StockMovementReason) (map[string]bool, error)
+}
@@ -11,7 +11,7 @@ type IUserRepo interface {
 	// Create creates a new user
 	Create(ctx context.Context, tx Transaction, user *model.User) (*model.User, error)
 
-	// Update updates an existing user
+	// Update updates an existing user, failing with model.ErrVersionConflict on a stale version
 	Update(ctx context.Context, tx Transaction, user *model.User) error
 
 	// Delete deletes a user by ID
@@ -0,0 +1,22 @@
+package repo
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// IUserAddressRepo defines the interface for address book repository operations
+type IUserAddressRepo interface {
+	// Create creates a new address. A default address takes the flag over from the other
+	// addresses of the user.
+	Create(ctx context.Context, tx Transaction, address *model.UserAddress) (*model.UserAddress, error)
+	// Update updates an existing address like Create, failing with model.ErrVersionConflict on a stale version
+	Update(ctx context.Context, tx Transaction, address *model.UserAddress) error
+	// Delete deletes an address by ID
+	Delete(ctx context.Context, tx Transaction, id string) error
+	// GetByID retrieves an address by ID
+	GetByID(ctx context.Context, tx Transaction, id string) (*model.UserAddress, error)
+	// ListByUserID retrieves the addresses of a user, oldest first
+	ListByUserID(ctx context.Context, tx Transaction, userID string) ([]*model.UserAddress, error)
+}
@@ -0,0 +1,19 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// IUserTokenRepo defines the interface for user token repository operations
+type IUserTokenRepo interface {
+	// Create creates a new user token
+	Create(ctx context.Context, tx Transaction, token *model.UserToken) error
+	// Consume atomically marks the unused and unexpired token with the given purpose and hash as
+	// used at the given time and returns it, or nil when there is no such token
+	Consume(ctx context.Context, tx Transaction, purpose model.UserTokenPurpose, hash string, at time.Time) (*model.UserToken, error)
+	// RevokeByUser marks every unused token of a user with the given purpose as used
+	RevokeByUser(ctx context.Context, tx Transaction, userID string, purpose model.UserTokenPurpose, at time.Time) error
+}
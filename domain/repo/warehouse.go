@@ -0,0 +1,29 @@
+package repo
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// IWarehouseRepo defines the interface for warehouse repository operations
+type IWarehouseRepo interface {
+	// Create creates a new warehouse, failing with model.ErrWarehouseCodeExists on a duplicate code
+	Create(ctx context.Context, warehouse *model.Warehouse) (*model.Warehouse, error)
+
+	// Update updates a warehouse, failing with model.ErrVersionConflict on a stale version
+	Update(ctx context.Context, warehouse *model.Warehouse) error
+
+	// GetByID retrieves a warehouse by ID
+	GetByID(ctx context.Context, id string) (*model.Warehouse, error)
+
+	// List retrieves all warehouses ordered by code
+	List(ctx context.Context) ([]*model.Warehouse, error)
+}
+
+// IAllocationStrategy defines the port choosing the warehouses that fulfill an item
+type IAllocationStrategy interface {
+	// Allocate picks warehouses among the candidates for the requested quantity,
+	// failing with model.ErrProductInsufficientStock when they cannot cover it
+	Allocate(ctx context.Context, request model.AllocationRequest) ([]model.StockAllocation, error)
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+const accountServiceTracerName = "account-service"
+
+// AccountPolicy configures the tokens sent by email and how often they may be sent
+type AccountPolicy struct {
+	VerificationTTL  time.Duration
+	PasswordResetTTL time.Duration
+	EmailRateLimit   int // messages per email address and EmailRateWindow, 0 for no limit
+	EmailRateWindow  time.Duration
+}
+
+// IAccountService defines the interface for email verification and password reset operations
+type IAccountService interface {
+	SendVerification(ctx context.Context, userID string) error
+	VerifyEmail(ctx context.Context, userID, token string) (*model.User, error)
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+// AccountService implements IAccountService. Tokens are sent through the notifier and only their
+// hash is stored; each one can be used once, before it expires.
+type AccountService struct {
+	userService IUserService
+	tokens      repo.IUserTokenRepo
+	notifier    repo.INotifier
+	limiter     repo.IRateLimiter
+	policy      AccountPolicy
+}
+
+// NewAccountService creates a new account service. A nil limiter disables rate limiting.
+func NewAccountService(userService IUserService, tokens repo.IUserTokenRepo, notifier repo.INotifier, limiter repo.IRateLimiter, policy AccountPolicy) *AccountService {
+	return &AccountService{
+		userService: userService,
+		tokens:      tokens,
+		notifier:    notifier,
+		limiter:     limiter,
+		policy:      policy,
+	}
+}
+
+// SendVerification sends a new email verification token to a user, replacing earlier ones
+func (s *AccountService) SendVerification(ctx context.Context, userID string) error {
+	ctx, span := otel.Tracer(accountServiceTracerName).Start(ctx, "AccountService.SendVerification")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("user.id", userID))
+
+	user, err := s.userService.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return model.ErrUserNotFound
+	}
+	if user.IsEmailVerified() {
+		return model.ErrUserEmailAlreadyVerified
+	}
+
+	if err := s.allow(ctx, user.Email); err != nil {
+		return err
+	}
+
+	plaintext, err := s.issue(ctx, user, model.UserTokenEmailVerification, s.policy.VerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.Notify(ctx, &model.Notification{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nUse this token to verify your email address: %s\n\nIt expires in %s.",
+			user.Name, plaintext, s.policy.VerificationTTL),
+	})
+}
+
+// VerifyEmail uses a verification token to mark the email address of the user as verified
+func (s *AccountService) VerifyEmail(ctx context.Context, userID, token string) (*model.User, error) {
+	ctx, span := otel.Tracer(accountServiceTracerName).Start(ctx, "AccountService.VerifyEmail")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("user.id", userID))
+
+	consumed, err := s.tokens.Consume(ctx, nil, model.UserTokenEmailVerification, model.HashUserToken(token), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if consumed == nil || consumed.UserID != userID {
+		return nil, model.ErrUserTokenInvalid
+	}
+
+	return s.userService.VerifyEmail(ctx, userID)
+}
+
+// RequestPasswordReset sends a password reset token to the user with the email address. Unknown
+// addresses are ignored without error, so the endpoint does not reveal who has an account.
+func (s *AccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	ctx, span := otel.Tracer(accountServiceTracerName).Start(ctx, "AccountService.RequestPasswordReset")
+	defer span.End()
+
+	// Counted before the lookup, so known and unknown addresses are limited alike
+	if err := s.allow(ctx, email); err != nil {
+		return err
+	}
+
+	user, err := s.userService.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	plaintext, err := s.issue(ctx, user, model.UserTokenPasswordReset, s.policy.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.Notify(ctx, &model.Notification{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse this token to choose a new password: %s\n\nIt expires in %s. "+
+			"If you did not ask to reset your password, ignore this message.",
+			user.Name, plaintext, s.policy.PasswordResetTTL),
+	})
+}
+
+// ResetPassword uses a password reset token to replace the password of its user
+func (s *AccountService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	ctx, span := otel.Tracer(accountServiceTracerName).Start(ctx, "AccountService.ResetPassword")
+	defer span.End()
+
+	// Checked first, so an invalid password does not use up the token
+	if err := model.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	consumed, err := s.tokens.Consume(ctx, nil, model.UserTokenPasswordReset, model.HashUserToken(token), now)
+	if err != nil {
+		return err
+	}
+	if consumed == nil {
+		return model.ErrUserTokenInvalid
+	}
+
+	span.SetAttributes(attribute.String("user.id", consumed.UserID))
+
+	if _, err := s.userService.ResetPassword(ctx, consumed.UserID, newPassword); err != nil {
+		return err
+	}
+
+	// Other reset tokens sent meanwhile must not reset the new password
+	if err := s.tokens.RevokeByUser(ctx, nil, consumed.UserID, model.UserTokenPasswordReset, now); err != nil {
+		log.SugaredLogger.Errorf("Failed to revoke password reset tokens of user %s: %v", consumed.UserID, err)
+	}
+
+	return nil
+}
+
+// issue replaces the unused tokens of the user for the purpose with a new one and returns it
+func (s *AccountService) issue(ctx context.Context, user *model.User, purpose model.UserTokenPurpose, ttl time.Duration) (string, error) {
+	token, plaintext, err := model.NewUserToken(user, purpose, ttl)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.tokens.RevokeByUser(ctx, nil, user.ID, purpose, token.CreatedAt); err != nil {
+		return "", err
+	}
+	if err := s.tokens.Create(ctx, nil, token); err != nil {
+		return "", err
+	}
+	return plaintext, nil
+}
+
+// allow counts a message sent to the email address against the rate limit. The limiter is a
+// safeguard only: when it fails, messages are sent rather than refused.
+func (s *AccountService) allow(ctx context.Context, email string) error {
+	if s.limiter == nil || s.policy.EmailRateLimit <= 0 {
+		return nil
+	}
+
+	key := emailRateLimitKey(model.TenantFromContext(ctx), email)
+	allowed, err := s.limiter.Allow(ctx, key, s.policy.EmailRateLimit, s.policy.EmailRateWindow)
+	if err != nil {
+		log.SugaredLogger.Errorf("Failed to rate limit emails to %s: %v", email, err)
+		return nil
+	}
+	if !allowed {
+		return model.ErrTooManyRequests
+	}
+	return nil
+}
+
+// emailRateLimitKey returns the rate limit key of an email address. The same address may belong to
+// an account in several tenants, each limited on its own.
+func emailRateLimitKey(tenantID, email string) string {
+	return "email:" + tenantID + ":" + normalizeLoginEmail(email)
+}
+
+// AccountEventHandler sends the verification email to users when they sign up
+type AccountEventHandler struct {
+	accountService IAccountService
+}
+
+// NewAccountEventHandler creates a new account event handler
+func NewAccountEventHandler(accountService IAccountService) *AccountEventHandler {
+	return &AccountEventHandler{accountService: accountService}
+}
+
+// HandleEvent sends the verification email of a created user
+func (h *AccountEventHandler) HandleEvent(ctx context.Context, evt event.Event) error {
+	baseEvent, ok := evt.(event.BaseEvent)
+	if !ok {
+		return nil
+	}
+
+	if _, ok := baseEvent.Payload.(model.UserCreatedEvent); ok {
+		return h.accountService.SendVerification(ctx, baseEvent.Aggregate)
+	}
+
+	return nil
+}
+
+// InterestedIn returns true for created users
+func (h *AccountEventHandler) InterestedIn(eventName string) bool {
+	return eventName == "user.created"
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// memoryTokenRepo keeps user tokens in memory and consumes them like the stores do
+type memoryTokenRepo struct {
+	tokens []*model.UserToken
+}
+
+func (r *memoryTokenRepo) Create(_ context.Context, _ repo.Transaction, token *model.UserToken) error {
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *memoryTokenRepo) Consume(_ context.Context, _ repo.Transaction, purpose model.UserTokenPurpose, hash string, at time.Time) (*model.UserToken, error) {
+	for _, token := range r.tokens {
+		if token.Purpose == purpose && token.Hash == hash && token.UsedAt == nil && token.ExpiresAt.After(at) {
+			token.UsedAt = &at
+			clone := *token
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryTokenRepo) RevokeByUser(_ context.Context, _ repo.Transaction, userID string, purpose model.UserTokenPurpose, at time.Time) error {
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			token.UsedAt = &at
+		}
+	}
+	return nil
+}
+
+// expireAll moves the expiry of every token into the past
+func (r *memoryTokenRepo) expireAll() {
+	for _, token := range r.tokens {
+		token.ExpiresAt = time.Now().Add(-time.Second)
+	}
+}
+
+// recordingNotifier records the notifications it sends
+type recordingNotifier struct {
+	sent []*model.Notification
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification *model.Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+var notifiedToken = regexp.MustCompile(`token [^:]*: ([A-Za-z0-9_-]+)`)
+
+// lastToken returns the plaintext token of the last notification
+func (n *recordingNotifier) lastToken(t *testing.T) string {
+	t.Helper()
+	require.NotEmpty(t, n.sent)
+	match := notifiedToken.FindStringSubmatch(n.sent[len(n.sent)-1].Body)
+	require.Len(t, match, 2)
+	return match[1]
+}
+
+// memoryRateLimiter counts the calls of each key, without a window
+type memoryRateLimiter struct {
+	counts map[string]int
+}
+
+func (l *memoryRateLimiter) Allow(_ context.Context, key string, limit int, _ time.Duration) (bool, error) {
+	l.counts[key]++
+	return l.counts[key] <= limit, nil
+}
+
+type accountTestSetup struct {
+	svc      *AccountService
+	users    *memoryUserRepo
+	tokens   *memoryTokenRepo
+	notifier *recordingNotifier
+}
+
+func newAccountTestSetup(t *testing.T) *accountTestSetup {
+	t.Helper()
+	hasher := testHasher(t)
+	hash, err := hasher.Hash("old-password")
+	require.NoError(t, err)
+
+	users := newMemoryUserRepo(
+		&model.User{ID: "u1", Email: "jane@example.com", Name: "Jane", Password: hash, Version: 1},
+		&model.User{ID: "u2", Email: "john@example.com", Name: "John", Password: hash, Version: 1},
+	)
+	tokens := &memoryTokenRepo{}
+	notifier := &recordingNotifier{}
+	svc := NewAccountService(NewUserService(users, nil, hasher, nil, nil), tokens, notifier, nil, AccountPolicy{
+		VerificationTTL:  time.Hour,
+		PasswordResetTTL: time.Hour,
+	})
+	return &accountTestSetup{svc: svc, users: users, tokens: tokens, notifier: notifier}
+}
+
+func TestAccountServiceVerifyEmail(t *testing.T) {
+	tests := []struct {
+		name string
+		// use returns the user and token to verify with, after the token of u1 was sent
+		use          func(t *testing.T, s *accountTestSetup, token string) (string, string)
+		wantVerified bool
+	}{
+		{
+			name:         "verifies with the sent token",
+			use:          func(_ *testing.T, _ *accountTestSetup, token string) (string, string) { return "u1", token },
+			wantVerified: true,
+		},
+		{
+			name: "expired token",
+			use: func(_ *testing.T, s *accountTestSetup, token string) (string, string) {
+				s.tokens.expireAll()
+				return "u1", token
+			},
+		},
+		{
+			name: "token replaced by a newer one",
+			use: func(t *testing.T, s *accountTestSetup, token string) (string, string) {
+				require.NoError(t, s.svc.SendVerification(context.Background(), "u1"))
+				return "u1", token
+			},
+		},
+		{
+			name: "token of another user",
+			use:  func(_ *testing.T, _ *accountTestSetup, token string) (string, string) { return "u2", token },
+		},
+		{
+			name: "password reset token",
+			use: func(t *testing.T, s *accountTestSetup, _ string) (string, string) {
+				require.NoError(t, s.svc.RequestPasswordReset(context.Background(), "jane@example.com"))
+				return "u1", s.notifier.lastToken(t)
+			},
+		},
+		{
+			name: "unknown token",
+			use:  func(_ *testing.T, _ *accountTestSetup, _ string) (string, string) { return "u1", "not-a-token" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newAccountTestSetup(t)
+			ctx := context.Background()
+			require.NoError(t, s.svc.SendVerification(ctx, "u1"))
+			userID, token := tt.use(t, s, s.notifier.lastToken(t))
+
+			user, err := s.svc.VerifyEmail(ctx, userID, token)
+			if !tt.wantVerified {
+				assert.ErrorIs(t, err, model.ErrUserTokenInvalid)
+				assert.False(t, s.users.users[userID].IsEmailVerified())
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, user.IsEmailVerified())
+			assert.True(t, s.users.users[userID].IsEmailVerified())
+
+			// A token is used once
+			_, err = s.svc.VerifyEmail(ctx, userID, token)
+			assert.ErrorIs(t, err, model.ErrUserTokenInvalid)
+		})
+	}
+}
+
+func TestAccountServiceResetPassword(t *testing.T) {
+	tests := []struct {
+		name        string
+		password    string
+		expire      bool
+		wantErr     error
+		wantUsable  bool // the token can still be used afterwards
+		wantChanged bool
+	}{
+		{name: "resets with the sent token", password: "new-password", wantChanged: true},
+		{name: "expired token", password: "new-password", expire: true, wantErr: model.ErrUserTokenInvalid},
+		{name: "invalid password keeps the token", password: "short", wantErr: model.ErrUserPasswordTooShort, wantUsable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newAccountTestSetup(t)
+			ctx := context.Background()
+			require.NoError(t, s.svc.RequestPasswordReset(ctx, "jane@example.com"))
+			token := s.notifier.lastToken(t)
+			if tt.expire {
+				s.tokens.expireAll()
+			}
+			before := s.users.users["u1"].Password
+
+			err := s.svc.ResetPassword(ctx, token, tt.password)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantChanged, s.users.users["u1"].Password != before)
+
+			err = s.svc.ResetPassword(ctx, token, "another-password")
+			if tt.wantUsable {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, model.ErrUserTokenInvalid)
+			}
+		})
+	}
+}
+
+func TestAccountServiceResetPasswordRevokesOtherTokens(t *testing.T) {
+	s := newAccountTestSetup(t)
+	ctx := context.Background()
+
+	// Two tokens of the same user in flight: issuing the second revokes the first
+	require.NoError(t, s.svc.RequestPasswordReset(ctx, "jane@example.com"))
+	first := s.notifier.lastToken(t)
+	require.NoError(t, s.svc.RequestPasswordReset(ctx, "jane@example.com"))
+	second := s.notifier.lastToken(t)
+
+	assert.ErrorIs(t, s.svc.ResetPassword(ctx, first, "new-password"), model.ErrUserTokenInvalid)
+	require.NoError(t, s.svc.ResetPassword(ctx, second, "new-password"))
+
+	// Unknown addresses are accepted without sending anything
+	sent := len(s.notifier.sent)
+	require.NoError(t, s.svc.RequestPasswordReset(ctx, "nobody@example.com"))
+	assert.Len(t, s.notifier.sent, sent)
+}
+
+func TestAccountServiceEmailRateLimitPerTenant(t *testing.T) {
+	s := newAccountTestSetup(t)
+	s.svc.limiter = &memoryRateLimiter{counts: map[string]int{}}
+	s.svc.policy.EmailRateLimit = 1
+	s.svc.policy.EmailRateWindow = time.Hour
+	acme := model.ContextWithTenant(context.Background(), "acme")
+	globex := model.ContextWithTenant(context.Background(), "globex")
+
+	require.NoError(t, s.svc.RequestPasswordReset(acme, "nobody@example.com"))
+	assert.ErrorIs(t, s.svc.RequestPasswordReset(acme, " Nobody@Example.com"), model.ErrTooManyRequests)
+
+	// The same address in another tenant has its own limit
+	assert.NoError(t, s.svc.RequestPasswordReset(globex, "nobody@example.com"))
+	assert.ErrorIs(t, s.svc.RequestPasswordReset(globex, "nobody@example.com"), model.ErrTooManyRequests)
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+const (
+	apiKeyServiceTracerName = "api-key-service"
+
+	// apiKeyLastUsedResolution limits last-used tracking to one write per key and interval
+	apiKeyLastUsedResolution = time.Minute
+)
+
+// IAPIKeyService defines the interface for API key operations
+type IAPIKeyService interface {
+	Create(ctx context.Context, name string, scopes []model.Permission, expiresAt *time.Time) (*model.APIKey, string, error)
+	Get(ctx context.Context, id string) (*model.APIKey, error)
+	List(ctx context.Context, offset, limit int) ([]*model.APIKey, int64, error)
+	Rotate(ctx context.Context, id string, expectedVersion int) (*model.APIKey, string, error)
+	Revoke(ctx context.Context, id string, expectedVersion int) (*model.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*model.APIKey, error)
+	Verify(ctx context.Context, key *model.APIKey, plaintext string) (*model.Principal, error)
+	Authenticate(ctx context.Context, plaintext string) (*model.Principal, error)
+}
+
+// APIKeyService implements IAPIKeyService
+type APIKeyService struct {
+	repo     repo.IAPIKeyRepo
+	eventBus event.EventBus
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(repo repo.IAPIKeyRepo, eventBus event.EventBus) *APIKeyService {
+	if eventBus == nil {
+		eventBus = event.NewNoopEventBus()
+	}
+	return &APIKeyService{
+		repo:     repo,
+		eventBus: eventBus,
+	}
+}
+
+// Create creates an API key for the calling user. The plaintext key is returned only here.
+// The calling principal, when there is one, only gives the key scopes it holds itself.
+func (s *APIKeyService) Create(ctx context.Context, name string, scopes []model.Permission, expiresAt *time.Time) (*model.APIKey, string, error) {
+	principal := model.PrincipalFromContext(ctx)
+	createdBy := ""
+	if principal != nil {
+		createdBy = principal.UserID
+	}
+
+	key, plaintext, err := model.NewAPIKey(name, scopes, expiresAt, createdBy)
+	if err != nil {
+		return nil, "", err
+	}
+	if principal != nil && !principal.CanGrant(nil, key.Scopes) {
+		return nil, "", model.ErrAPIKeyScopeForbidden
+	}
+
+	created, err := s.repo.Create(ctx, nil, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.publishEvents(ctx, key)
+
+	return created, plaintext, nil
+}
+
+// Get retrieves an API key by ID
+func (s *APIKeyService) Get(ctx context.Context, id string) (*model.APIKey, error) {
+	return s.repo.GetByID(ctx, nil, id)
+}
+
+// List retrieves API keys with pagination
+func (s *APIKeyService) List(ctx context.Context, offset, limit int) ([]*model.APIKey, int64, error) {
+	return s.repo.List(ctx, nil, offset, limit)
+}
+
+// Rotate replaces the secret of an API key and returns the new plaintext key. The previous key
+// stops working at once.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *APIKeyService) Rotate(ctx context.Context, id string, expectedVersion int) (*model.APIKey, string, error) {
+	key, err := s.load(ctx, id, expectedVersion)
+	if err != nil {
+		return nil, "", err
+	}
+
+	plaintext, err := key.Rotate()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.repo.Update(ctx, nil, key); err != nil {
+		return nil, "", err
+	}
+
+	s.publishEvents(ctx, key)
+
+	return key, plaintext, nil
+}
+
+// Revoke disables an API key for good.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *APIKeyService) Revoke(ctx context.Context, id string, expectedVersion int) (*model.APIKey, error) {
+	key, err := s.load(ctx, id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := key.Revoke(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, nil, key); err != nil {
+		return nil, err
+	}
+
+	s.publishEvents(ctx, key)
+
+	return key, nil
+}
+
+// GetByPrefix retrieves an API key by its lookup prefix
+func (s *APIKeyService) GetByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	return s.repo.GetByPrefix(ctx, nil, prefix)
+}
+
+// Verify checks a plaintext key against a stored API key and returns the principal of the key.
+// Uses are recorded at most once per apiKeyLastUsedResolution, updating key.LastUsedAt.
+func (s *APIKeyService) Verify(ctx context.Context, key *model.APIKey, plaintext string) (*model.Principal, error) {
+	now := time.Now()
+	if err := key.Check(plaintext, now); err != nil {
+		return nil, err
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyLastUsedResolution {
+		// The request is authenticated anyway, a failed write only loses usage tracking
+		if err := s.repo.TouchLastUsed(ctx, nil, key.ID, now); err != nil {
+			log.SugaredLogger.Errorf("Failed to record use of API key %s: %v", key.ID, err)
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+
+	return key.Principal(), nil
+}
+
+// Authenticate verifies a plaintext API key and returns its principal
+func (s *APIKeyService) Authenticate(ctx context.Context, plaintext string) (*model.Principal, error) {
+	ctx, span := otel.Tracer(apiKeyServiceTracerName).Start(ctx, "APIKeyService.Authenticate")
+	defer span.End()
+
+	prefix, ok := model.ParseAPIKeyPrefix(plaintext)
+	if !ok {
+		return nil, model.ErrAPIKeyInvalid
+	}
+
+	key, err := s.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, model.ErrAPIKeyInvalid
+	}
+
+	span.SetAttributes(attribute.String("api_key.id", key.ID))
+	return s.Verify(ctx, key, plaintext)
+}
+
+// load retrieves an API key for an update at the expected version
+func (s *APIKeyService) load(ctx context.Context, id string, expectedVersion int) (*model.APIKey, error) {
+	key, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, model.ErrAPIKeyNotFound
+	}
+
+	if err := model.CheckVersion(key.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *APIKeyService) publishEvents(ctx context.Context, key *model.APIKey) {
+	ctx = model.EnsureTenant(ctx, key.TenantID)
+	for _, domainEvent := range key.Events() {
+		evt := event.NewBaseEvent(
+			domainEvent.EventName(),
+			key.ID,
+			domainEvent,
+		)
+		if err := s.eventBus.Publish(ctx, evt); err != nil {
+			log.SugaredLogger.Errorf("Failed to publish event %s: %v", domainEvent.EventName(), err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// memoryAPIKeyRepo keeps API keys in memory
+type memoryAPIKeyRepo struct {
+	repo.IAPIKeyRepo
+
+	keys []*model.APIKey
+}
+
+func (r *memoryAPIKeyRepo) Create(_ context.Context, _ repo.Transaction, key *model.APIKey) (*model.APIKey, error) {
+	r.keys = append(r.keys, key)
+	return key, nil
+}
+
+func TestAPIKeyServiceCreateScopes(t *testing.T) {
+	staff := &model.Principal{UserID: "staff", Roles: []model.Role{model.RoleStaff}}
+	keyManager := &model.Principal{
+		UserID:      "manager",
+		Roles:       []model.Role{model.RoleCustomer},
+		Permissions: []model.Permission{model.PermissionAPIKeysManage, model.PermissionOrdersRead},
+	}
+	admin := &model.Principal{UserID: "admin", Roles: []model.Role{model.RoleAdmin}}
+
+	tests := []struct {
+		name      string
+		principal *model.Principal
+		scopes    []model.Permission
+		wantErr   error
+	}{
+		{name: "scopes granted by the caller's role", principal: staff,
+			scopes: []model.Permission{model.PermissionCatalogWrite, model.PermissionOrdersRead}},
+		{name: "scopes granted to the caller directly", principal: keyManager,
+			scopes: []model.Permission{model.PermissionOrdersRead, model.PermissionCatalogRead}},
+		{name: "admin gives any scope", principal: admin, scopes: []model.Permission{model.PermissionAll}},
+		{name: "scope the caller lacks", principal: keyManager,
+			scopes: []model.Permission{model.PermissionOrdersRead, model.PermissionOrdersWrite}, wantErr: model.ErrAPIKeyScopeForbidden},
+		{name: "every permission without being admin", principal: staff,
+			scopes: []model.Permission{model.PermissionAll}, wantErr: model.ErrAPIKeyScopeForbidden},
+		{name: "unknown scope", principal: admin, scopes: []model.Permission{"orders:delete"}, wantErr: model.ErrAPIKeyScopeInvalid},
+		{name: "internal callers are not restricted", scopes: []model.Permission{model.PermissionPaymentsWrite}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := &memoryAPIKeyRepo{}
+			svc := NewAPIKeyService(keys, nil)
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = model.ContextWithPrincipal(ctx, tt.principal)
+			}
+			expiresAt := time.Now().Add(time.Hour)
+
+			key, plaintext, err := svc.Create(ctx, "integration", tt.scopes, &expiresAt)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, plaintext)
+				assert.Empty(t, keys.keys)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.NotEmpty(t, plaintext)
+			assert.Equal(t, tt.scopes, key.Scopes)
+			assert.Len(t, keys.keys, 1)
+		})
+	}
+}
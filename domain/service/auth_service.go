@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+const authServiceTracerName = "auth-service"
+
+// IAuthService defines the interface for authentication operations
+type IAuthService interface {
+	Login(ctx context.Context, email, password, clientIP string) (*model.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*model.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	Authenticate(ctx context.Context, accessToken string) (*model.Principal, error)
+}
+
+// AuthService implements IAuthService with short-lived access tokens and rotating refresh tokens.
+// Access tokens are verified by signature only; refresh tokens are also checked against their
+// session, so logging out or reusing a rotated refresh token ends the session.
+type AuthService struct {
+	userService IUserService
+	signer      repo.ITokenSigner
+	sessions    repo.IRefreshSessionStore
+	lockout     ILockoutService
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+}
+
+// NewAuthService creates a new auth service. A nil lockout service disables brute-force protection.
+func NewAuthService(userService IUserService, signer repo.ITokenSigner, sessions repo.IRefreshSessionStore, lockout ILockoutService, accessTTL, refreshTTL time.Duration) *AuthService {
+	return &AuthService{
+		userService: userService,
+		signer:      signer,
+		sessions:    sessions,
+		lockout:     lockout,
+		accessTTL:   accessTTL,
+		refreshTTL:  refreshTTL,
+	}
+}
+
+// Login verifies a user's credentials and starts a session. Failed logins are counted against the
+// account and the client address, which are locked out after too many.
+func (s *AuthService) Login(ctx context.Context, email, password, clientIP string) (*model.TokenPair, error) {
+	ctx, span := otel.Tracer(authServiceTracerName).Start(ctx, "AuthService.Login")
+	defer span.End()
+
+	if s.lockout != nil {
+		if err := s.lockout.Check(ctx, email, clientIP); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := s.userService.Authenticate(ctx, email, password)
+	if err != nil {
+		if s.lockout != nil && errors.Is(err, model.ErrInvalidCredentials) {
+			s.lockout.RecordFailure(ctx, email, clientIP)
+		}
+		return nil, err
+	}
+
+	if s.lockout != nil {
+		s.lockout.RecordSuccess(ctx, email)
+	}
+
+	principal := model.NewPrincipal(user, uuid.New().String())
+	pair, refresh, err := s.issue(principal)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &model.RefreshSession{
+		ID:        principal.SessionID,
+		UserID:    user.ID,
+		TokenID:   refresh.ID,
+		ExpiresAt: refresh.ExpiresAt,
+	}
+	if err := s.sessions.Save(ctx, session); err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("user.id", user.ID))
+	return pair, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair. The refresh token is single use:
+// presenting a rotated token again is treated as theft and revokes the whole session.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*model.TokenPair, error) {
+	ctx, span := otel.Tracer(authServiceTracerName).Start(ctx, "AuthService.Refresh")
+	defer span.End()
+
+	claims, err := s.parse(refreshToken, model.TokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user is looked up in its own tenant; tokens issued before tenants belong to the default one
+	tenantID := claims.TenantID
+	if tenantID == "" {
+		tenantID = model.DefaultTenantID
+	}
+	ctx = model.ContextWithTenant(ctx, tenantID)
+
+	// Pick up changes of the user such as new roles, a deleted user cannot refresh
+	user, err := s.userService.Get(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		s.revoke(ctx, claims.SessionID)
+		return nil, model.ErrTokenRevoked
+	}
+
+	principal := model.NewPrincipal(user, claims.SessionID)
+	pair, refresh, err := s.issue(principal)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := s.sessions.Rotate(ctx, claims.SessionID, claims.ID, refresh.ID, refresh.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		log.SugaredLogger.Warnf("Revoking session %s of user %s: refresh token reused or revoked", claims.SessionID, claims.UserID)
+		s.revoke(ctx, claims.SessionID)
+		return nil, model.ErrTokenRevoked
+	}
+
+	span.SetAttributes(attribute.String("user.id", user.ID))
+	return pair, nil
+}
+
+// Logout ends the session of a refresh token. Access tokens already issued stay valid until they expire.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	claims, err := s.parse(refreshToken, model.TokenTypeRefresh)
+	if err != nil {
+		return err
+	}
+	return s.sessions.Revoke(ctx, claims.SessionID)
+}
+
+// Authenticate verifies an access token and returns its principal
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (*model.Principal, error) {
+	claims, err := s.parse(accessToken, model.TokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+	return claims.Principal(), nil
+}
+
+// issue signs a new access and refresh token for the principal
+func (s *AuthService) issue(principal model.Principal) (*model.TokenPair, *model.TokenClaims, error) {
+	access := model.NewTokenClaims(model.TokenTypeAccess, principal, s.accessTTL)
+	refresh := model.NewTokenClaims(model.TokenTypeRefresh, principal, s.refreshTTL)
+
+	accessToken, err := s.signer.Sign(access)
+	if err != nil {
+		log.SugaredLogger.Errorf("Failed to sign access token: %v", err)
+		return nil, nil, model.ErrTokenGenerate
+	}
+	refreshToken, err := s.signer.Sign(refresh)
+	if err != nil {
+		log.SugaredLogger.Errorf("Failed to sign refresh token: %v", err)
+		return nil, nil, model.ErrTokenGenerate
+	}
+
+	return &model.TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  access.ExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refresh.ExpiresAt,
+	}, refresh, nil
+}
+
+// parse verifies a token of the expected type
+func (s *AuthService) parse(token string, tokenType model.TokenType) (*model.TokenClaims, error) {
+	claims, err := s.signer.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != tokenType {
+		return nil, model.ErrTokenInvalid
+	}
+	return claims, nil
+}
+
+// revoke ends a session. The request fails anyway, so failures are logged rather than returned.
+func (s *AuthService) revoke(ctx context.Context, sessionID string) {
+	if err := s.sessions.Revoke(ctx, sessionID); err != nil {
+		log.SugaredLogger.Errorf("Failed to revoke session %s: %v", sessionID, err)
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/repository/redis"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/metrics"
+)
+
+const (
+	cachedAPIKeyServiceTracerName = "cached-api-key-service"
+	apiKeyCacheKeyPrefix          = "api_key:prefix:"
+	defaultAPIKeyCacheTTL         = 5 * time.Minute
+)
+
+// CachedAPIKeyService wraps an APIKeyService, caching the keys looked up on every authenticated
+// request. Rotating or revoking a key invalidates it at once.
+type CachedAPIKeyService struct {
+	delegate IAPIKeyService
+	cache    *redis.EnhancedCache
+	ttl      time.Duration
+}
+
+// NewCachedAPIKeyService creates a new cached API key service
+func NewCachedAPIKeyService(delegate IAPIKeyService, cache *redis.EnhancedCache) *CachedAPIKeyService {
+	return &CachedAPIKeyService{
+		delegate: delegate,
+		cache:    cache,
+		ttl:      defaultAPIKeyCacheTTL,
+	}
+}
+
+// Create creates an API key (not cached until first used)
+func (s *CachedAPIKeyService) Create(ctx context.Context, name string, scopes []model.Permission, expiresAt *time.Time) (*model.APIKey, string, error) {
+	return s.delegate.Create(ctx, name, scopes, expiresAt)
+}
+
+// Get retrieves an API key by ID (not cached - management reads must be current)
+func (s *CachedAPIKeyService) Get(ctx context.Context, id string) (*model.APIKey, error) {
+	return s.delegate.Get(ctx, id)
+}
+
+// List retrieves API keys with pagination (not cached - lists are dynamic)
+func (s *CachedAPIKeyService) List(ctx context.Context, offset, limit int) ([]*model.APIKey, int64, error) {
+	return s.delegate.List(ctx, offset, limit)
+}
+
+// Rotate replaces the secret of an API key and invalidates the cached previous key
+func (s *CachedAPIKeyService) Rotate(ctx context.Context, id string, expectedVersion int) (*model.APIKey, string, error) {
+	ctx, span := otel.Tracer(cachedAPIKeyServiceTracerName).Start(ctx, "CachedAPIKeyService.Rotate")
+	defer span.End()
+
+	// The prefix changes with the secret, remember the one to invalidate
+	previous, err := s.delegate.Get(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key, plaintext, err := s.delegate.Rotate(ctx, id, expectedVersion)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if previous != nil {
+		s.invalidate(ctx, previous.Prefix)
+	}
+
+	return key, plaintext, nil
+}
+
+// Revoke disables an API key and invalidates its cache
+func (s *CachedAPIKeyService) Revoke(ctx context.Context, id string, expectedVersion int) (*model.APIKey, error) {
+	ctx, span := otel.Tracer(cachedAPIKeyServiceTracerName).Start(ctx, "CachedAPIKeyService.Revoke")
+	defer span.End()
+
+	key, err := s.delegate.Revoke(ctx, id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate(ctx, key.Prefix)
+
+	return key, nil
+}
+
+// GetByPrefix retrieves an API key by its lookup prefix, using cache when available
+func (s *CachedAPIKeyService) GetByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	ctx, span := otel.Tracer(cachedAPIKeyServiceTracerName).Start(ctx, "CachedAPIKeyService.GetByPrefix")
+	defer span.End()
+
+	var key model.APIKey
+	if err := s.cache.Get(ctx, apiKeyCacheKeyPrefix+prefix, &key); err == nil {
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		metrics.RecordCacheHit("api_key", "hit")
+		return &key, nil
+	}
+
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+	metrics.RecordCacheHit("api_key", "miss")
+
+	result, err := s.delegate.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		s.cacheKey(ctx, result)
+	}
+
+	return result, nil
+}
+
+// Verify checks a plaintext key against a stored API key, refreshing the cached key when its
+// last use was recorded
+func (s *CachedAPIKeyService) Verify(ctx context.Context, key *model.APIKey, plaintext string) (*model.Principal, error) {
+	lastUsedAt := key.LastUsedAt
+
+	principal, err := s.delegate.Verify(ctx, key, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.LastUsedAt != lastUsedAt {
+		s.cacheKey(ctx, key)
+	}
+
+	return principal, nil
+}
+
+// Authenticate verifies a plaintext API key against the cached key and returns its principal
+func (s *CachedAPIKeyService) Authenticate(ctx context.Context, plaintext string) (*model.Principal, error) {
+	ctx, span := otel.Tracer(cachedAPIKeyServiceTracerName).Start(ctx, "CachedAPIKeyService.Authenticate")
+	defer span.End()
+
+	prefix, ok := model.ParseAPIKeyPrefix(plaintext)
+	if !ok {
+		return nil, model.ErrAPIKeyInvalid
+	}
+
+	key, err := s.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, model.ErrAPIKeyInvalid
+	}
+
+	span.SetAttributes(attribute.String("api_key.id", key.ID))
+	return s.Verify(ctx, key, plaintext)
+}
+
+// Helper methods
+
+func (s *CachedAPIKeyService) cacheKey(ctx context.Context, key *model.APIKey) {
+	if err := s.cache.Set(ctx, apiKeyCacheKeyPrefix+key.Prefix, key, s.ttl); err != nil {
+		log.SugaredLogger.Warnf("Failed to cache API key %s: %v", key.ID, err)
+	}
+}
+
+func (s *CachedAPIKeyService) invalidate(ctx context.Context, prefix string) {
+	if err := s.cache.Delete(ctx, apiKeyCacheKeyPrefix+prefix); err != nil {
+		log.SugaredLogger.Warnf("Failed to invalidate API key cache %s: %v", prefix, err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"cactus-golang-hexagonal-microservice-boilerplate/adapter/repository/redis"
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
 	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
 	"cactus-golang-hexagonal-microservice-boilerplate/util/metrics"
 )
@@ -18,7 +19,19 @@ const (
 	cachedProductServiceTracerName = "cached-product-service"
 	productCacheKeyPrefix          = "product:"
 	productNameCacheKeyPrefix      = "product:name:"
-	defaultProductCacheTTL         = 30 * time.Minute
+	productSearchCacheKeyPrefix    = "product:search:"
+	// Search generations are bumped instead of deleting cached results, which then expire unused
+	productSearchGenerationKeyPrefix = "product:search-gen:"
+	productSearchHitsKeyPrefix       = "product:search-hits:"
+	defaultProductCacheTTL           = 30 * time.Minute
+	// Search results are cached briefly: popular queries are served from cache,
+	// while stock changes, which do not invalidate them, show up within a minute
+	productSearchCacheTTL = time.Minute
+	// A query is popular, and its results cached, once it was searched this many times within the window
+	productSearchPopularHits   = 3
+	productSearchPopularWindow = 10 * time.Minute
+	// allTenantsGeneration names the generation bumped by changes whose tenant is not known
+	allTenantsGeneration = "*"
 )
 
 // CachedProductService wraps a ProductService with Redis caching
@@ -50,12 +63,13 @@ func (s *CachedProductService) Create(ctx context.Context, name, description str
 
 	// Cache the new product
 	s.cacheProduct(ctx, product)
+	s.invalidateSearchCache(ctx, product.TenantID)
 
 	return product, nil
 }
 
 // Update updates a product and invalidates the cache
-func (s *CachedProductService) Update(ctx context.Context, id string, name, description string, price float64) (*model.Product, error) {
+func (s *CachedProductService) Update(ctx context.Context, id string, name, description string, price float64, expectedVersion int) (*model.Product, error) {
 	ctx, span := otel.Tracer(cachedProductServiceTracerName).Start(ctx, "CachedProductService.Update")
 	defer span.End()
 
@@ -63,17 +77,19 @@ func (s *CachedProductService) Update(ctx context.Context, id string, name, desc
 	currentProduct, _ := s.delegate.Get(ctx, id)
 
 	// Delegate to the underlying service
-	product, err := s.delegate.Update(ctx, id, name, description, price)
+	product, err := s.delegate.Update(ctx, id, name, description, price, expectedVersion)
 	if err != nil {
 		return nil, err
 	}
 
 	// Invalidate caches
-	s.invalidateProductCache(ctx, id)
+	s.invalidateProductCache(ctx, model.TenantFromContext(ctx), id)
 	if currentProduct != nil {
-		s.invalidateNameCache(ctx, currentProduct.Name)
+		s.invalidateNameCache(ctx, currentProduct.TenantID, currentProduct.Name)
 	}
 
+	s.invalidateSearchCache(ctx, product.TenantID)
+
 	// Cache the updated product
 	s.cacheProduct(ctx, product)
 
@@ -95,10 +111,11 @@ func (s *CachedProductService) Delete(ctx context.Context, id string) error {
 	}
 
 	// Invalidate caches
-	s.invalidateProductCache(ctx, id)
+	s.invalidateProductCache(ctx, model.TenantFromContext(ctx), id)
 	if currentProduct != nil {
-		s.invalidateNameCache(ctx, currentProduct.Name)
+		s.invalidateNameCache(ctx, currentProduct.TenantID, currentProduct.Name)
 	}
+	s.invalidateSearchCache(ctx, model.TenantFromContext(ctx))
 
 	return nil
 }
@@ -110,8 +127,14 @@ func (s *CachedProductService) Get(ctx context.Context, id string) (*model.Produ
 
 	span.SetAttributes(attribute.String("product.id", id))
 
+	// Keys are per tenant, so reads outside a tenant go to the delegate
+	tenantID := model.TenantFromContext(ctx)
+	if tenantID == "" {
+		return s.delegate.Get(ctx, id)
+	}
+
 	// Try cache first
-	cacheKey := s.productCacheKey(id)
+	cacheKey := s.productCacheKey(tenantID, id)
 	var product model.Product
 
 	err := s.cache.Get(ctx, cacheKey, &product)
@@ -147,8 +170,13 @@ func (s *CachedProductService) GetByName(ctx context.Context, name string) (*mod
 
 	span.SetAttributes(attribute.String("product.name", name))
 
+	tenantID := model.TenantFromContext(ctx)
+	if tenantID == "" {
+		return s.delegate.GetByName(ctx, name)
+	}
+
 	// Try name->id cache first
-	nameCacheKey := s.nameCacheKey(name)
+	nameCacheKey := s.nameCacheKey(tenantID, name)
 	var productID string
 
 	err := s.cache.Get(ctx, nameCacheKey, &productID)
@@ -184,31 +212,314 @@ func (s *CachedProductService) List(ctx context.Context, offset, limit int) ([]*
 	return s.delegate.List(ctx, offset, limit)
 }
 
+// GetBySKU retrieves the product owning a SKU (not cached - SKUs are not cache keys)
+func (s *CachedProductService) GetBySKU(ctx context.Context, sku string) (*model.Product, error) {
+	return s.delegate.GetBySKU(ctx, sku)
+}
+
 // UpdateStock updates product stock and invalidates the cache
-func (s *CachedProductService) UpdateStock(ctx context.Context, id string, quantity int) error {
+func (s *CachedProductService) UpdateStock(ctx context.Context, id string, change model.StockChange) error {
 	ctx, span := otel.Tracer(cachedProductServiceTracerName).Start(ctx, "CachedProductService.UpdateStock")
 	defer span.End()
 
 	// Delegate to the underlying service
-	err := s.delegate.UpdateStock(ctx, id, quantity)
+	err := s.delegate.UpdateStock(ctx, id, change)
 	if err != nil {
 		return err
 	}
 
 	// Invalidate cache (stock changed)
-	s.invalidateProductCache(ctx, id)
+	s.invalidateProductCache(ctx, model.TenantFromContext(ctx), id)
+
+	return nil
+}
+
+// ReserveStock reserves product stock and invalidates the cache
+func (s *CachedProductService) ReserveStock(ctx context.Context, id string, change model.StockChange) error {
+	ctx, span := otel.Tracer(cachedProductServiceTracerName).Start(ctx, "CachedProductService.ReserveStock")
+	defer span.End()
+
+	if err := s.delegate.ReserveStock(ctx, id, change); err != nil {
+		return err
+	}
+
+	// Invalidate cache (stock changed)
+	s.invalidateProductCache(ctx, model.TenantFromContext(ctx), id)
 
 	return nil
 }
 
+// AllocateStock reserves product stock across warehouses and invalidates the cache
+func (s *CachedProductService) AllocateStock(ctx context.Context, id string, change model.StockChange, destination *model.Location) ([]model.StockAllocation, error) {
+	ctx, span := otel.Tracer(cachedProductServiceTracerName).Start(ctx, "CachedProductService.AllocateStock")
+	defer span.End()
+
+	allocations, err := s.delegate.AllocateStock(ctx, id, change, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache (stock changed)
+	s.invalidateProductCache(ctx, model.TenantFromContext(ctx), id)
+
+	return allocations, nil
+}
+
+// ListStockMovements retrieves the ledger of a product (not cached - the ledger grows constantly)
+func (s *CachedProductService) ListStockMovements(ctx context.Context, id string, offset, limit int) ([]*model.StockMovement, int64, error) {
+	return s.delegate.ListStockMovements(ctx, id, offset, limit)
+}
+
+// ReconcileStock checks stock against the ledger (not cached - it only reads and reports)
+func (s *CachedProductService) ReconcileStock(ctx context.Context, offset, limit int) ([]model.StockDrift, int, error) {
+	return s.delegate.ReconcileStock(ctx, offset, limit)
+}
+
+// SetLowStockThreshold sets the low-stock threshold of a product and refreshes the cache
+func (s *CachedProductService) SetLowStockThreshold(ctx context.Context, id string, threshold, expectedVersion int) (*model.Product, error) {
+	ctx, span := otel.Tracer(cachedProductServiceTracerName).Start(ctx, "CachedProductService.SetLowStockThreshold")
+	defer span.End()
+
+	product, err := s.delegate.SetLowStockThreshold(ctx, id, threshold, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	s.refreshProduct(ctx, product)
+	return product, nil
+}
+
+// ListLowStock retrieves low-stock products (not cached - stock changes constantly)
+func (s *CachedProductService) ListLowStock(ctx context.Context, offset, limit int) ([]*model.Product, int64, error) {
+	return s.delegate.ListLowStock(ctx, offset, limit)
+}
+
+// SweepLowStock publishes low-stock events for a page of products (not cached - read from the store)
+func (s *CachedProductService) SweepLowStock(ctx context.Context, offset, limit int) ([]*model.Product, error) {
+	return s.delegate.SweepLowStock(ctx, offset, limit)
+}
+
+// AddVariant adds a variant to a product and refreshes the cache
+func (s *CachedProductService) AddVariant(ctx context.Context, id string, variant model.Variant, expectedVersion int) (*model.Product, error) {
+	ctx, span := otel.Tracer(cachedProductServiceTracerName).Start(ctx, "CachedProductService.AddVariant")
+	defer span.End()
+
+	product, err := s.delegate.AddVariant(ctx, id, variant, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	s.refreshProduct(ctx, product)
+	return product, nil
+}
+
+// UpdateVariant changes a variant and refreshes the cache
+func (s *CachedProductService) UpdateVariant(ctx context.Context, id, sku string, attributes map[string]string, price float64, expectedVersion int) (*model.Product, error) {
+	ctx, span := otel.Tracer(cachedProductServiceTracerName).Start(ctx, "CachedProductService.UpdateVariant")
+	defer span.End()
+
+	product, err := s.delegate.UpdateVariant(ctx, id, sku, attributes, price, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	s.refreshProduct(ctx, product)
+	return product, nil
+}
+
+// RemoveVariant removes a variant and refreshes the cache
+func (s *CachedProductService) RemoveVariant(ctx context.Context, id, sku string, expectedVersion int) (*model.Product, error) {
+	ctx, span := otel.Tracer(cachedProductServiceTracerName).Start(ctx, "CachedProductService.RemoveVariant")
+	defer span.End()
+
+	product, err := s.delegate.RemoveVariant(ctx, id, sku, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	s.refreshProduct(ctx, product)
+	return product, nil
+}
+
+// AssignCategories replaces the categories of a product and refreshes the cache
+func (s *CachedProductService) AssignCategories(ctx context.Context, id string, categoryIDs []string, expectedVersion int) (*model.Product, error) {
+	ctx, span := otel.Tracer(cachedProductServiceTracerName).Start(ctx, "CachedProductService.AssignCategories")
+	defer span.End()
+
+	product, err := s.delegate.AssignCategories(ctx, id, categoryIDs, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	s.refreshProduct(ctx, product)
+	return product, nil
+}
+
+// ListByCategory retrieves products in a category subtree (not cached - lists are dynamic)
+func (s *CachedProductService) ListByCategory(ctx context.Context, categoryID string, offset, limit int) ([]*model.Product, int64, error) {
+	return s.delegate.ListByCategory(ctx, categoryID, offset, limit)
+}
+
+// ReplaceCategory reassigns the products of a category and invalidates their cache
+func (s *CachedProductService) ReplaceCategory(ctx context.Context, categoryID, replacementID string) ([]string, error) {
+	ctx, span := otel.Tracer(cachedProductServiceTracerName).Start(ctx, "CachedProductService.ReplaceCategory")
+	defer span.End()
+
+	ids, err := s.delegate.ReplaceCategory(ctx, categoryID, replacementID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The products belong to the tenant of the category, every tenant for callers without one
+	tenantID := model.TenantFromContext(ctx)
+	for _, id := range ids {
+		s.invalidateProductCache(ctx, tenantID, id)
+	}
+	s.invalidateSearchCache(ctx, tenantID)
+
+	return ids, nil
+}
+
+// Search finds products matching the criteria, using cache when available
+func (s *CachedProductService) Search(ctx context.Context, criteria model.ProductSearchCriteria) (*model.ProductSearchResult, error) {
+	ctx, span := otel.Tracer(cachedProductServiceTracerName).Start(ctx, "CachedProductService.Search")
+	defer span.End()
+
+	// Normalize first so equivalent searches share a cache entry
+	if err := criteria.Normalize(); err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("product.search", criteria.Key()))
+
+	tenantID := model.TenantFromContext(ctx)
+	if tenantID == "" {
+		return s.delegate.Search(ctx, criteria)
+	}
+
+	cacheKey, err := s.searchCacheKey(ctx, tenantID, criteria)
+	if err != nil {
+		log.SugaredLogger.Warnf("Failed to read product search generation: %v", err)
+		return s.delegate.Search(ctx, criteria)
+	}
+
+	var result model.ProductSearchResult
+	err = s.cache.Get(ctx, cacheKey, &result)
+	if err == nil {
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		metrics.RecordCacheHit("product_search", "hit")
+		return &result, nil
+	}
+
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+	metrics.RecordCacheHit("product_search", "miss")
+
+	found, err := s.delegate.Search(ctx, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only popular queries are cached, rare ones would just fill the cache
+	if !s.isPopularSearch(ctx, tenantID, criteria) {
+		return found, nil
+	}
+	if cacheErr := s.cache.Set(ctx, cacheKey, found, productSearchCacheTTL); cacheErr != nil {
+		log.SugaredLogger.Warnf("Failed to cache product search %s: %v", criteria.Key(), cacheErr)
+	}
+
+	return found, nil
+}
+
+// Import upserts products from a file and invalidates the cache of every product it changed
+func (s *CachedProductService) Import(ctx context.Context, reader repo.IProductRowReader, opts model.ProductImportOptions) (*model.ProductImportReport, error) {
+	ctx, span := otel.Tracer(cachedProductServiceTracerName).Start(ctx, "CachedProductService.Import")
+	defer span.End()
+
+	report, err := s.delegate.Import(ctx, reader, opts)
+	if report == nil || report.DryRun {
+		return report, err
+	}
+
+	// Invalidate caches, also after an import that stopped early
+	invalidated := make(map[string]struct{})
+	for _, row := range report.Rows {
+		if row.ProductID == "" || row.Action == model.ProductImportUnchanged {
+			continue
+		}
+		if _, ok := invalidated[row.ProductID]; ok {
+			continue
+		}
+		invalidated[row.ProductID] = struct{}{}
+		s.invalidateProductCache(ctx, model.TenantFromContext(ctx), row.ProductID)
+		s.invalidateNameCache(ctx, model.TenantForCreate(ctx), row.Name)
+	}
+	if len(invalidated) > 0 {
+		s.invalidateSearchCache(ctx, model.TenantForCreate(ctx))
+	}
+
+	return report, err
+}
+
+// Export writes every product to a file (not cached - streamed from the database)
+func (s *CachedProductService) Export(ctx context.Context, writer repo.IProductRowWriter) (int, error) {
+	return s.delegate.Export(ctx, writer)
+}
+
+// SchedulePrice schedules a price change of a product (not cached - the price changes when applied)
+func (s *CachedProductService) SchedulePrice(ctx context.Context, id string, price float64, effectiveFrom time.Time) (*model.PriceEntry, error) {
+	return s.delegate.SchedulePrice(ctx, id, price, effectiveFrom)
+}
+
+// ListPriceHistory retrieves the price history of a product (not cached - read from the store)
+func (s *CachedProductService) ListPriceHistory(ctx context.Context, id string, offset, limit int) ([]*model.PriceEntry, int64, error) {
+	return s.delegate.ListPriceHistory(ctx, id, offset, limit)
+}
+
+// ApplyScheduledPrices applies the due scheduled price changes and refreshes the cache
+func (s *CachedProductService) ApplyScheduledPrices(ctx context.Context, limit int) ([]*model.Product, error) {
+	ctx, span := otel.Tracer(cachedProductServiceTracerName).Start(ctx, "CachedProductService.ApplyScheduledPrices")
+	defer span.End()
+
+	products, err := s.delegate.ApplyScheduledPrices(ctx, limit)
+	for _, product := range products {
+		s.refreshProduct(ctx, product)
+	}
+
+	return products, err
+}
+
 // Helper methods
 
-func (s *CachedProductService) productCacheKey(id string) string {
-	return fmt.Sprintf("%s%s", productCacheKeyPrefix, id)
+// productCacheKey namespaces the key by tenant, so a tenant never reads another tenant's products
+func (s *CachedProductService) productCacheKey(tenantID, id string) string {
+	return fmt.Sprintf("%s%s:%s", productCacheKeyPrefix, tenantID, id)
+}
+
+func (s *CachedProductService) nameCacheKey(tenantID, name string) string {
+	return fmt.Sprintf("%s%s:%s", productNameCacheKeyPrefix, tenantID, name)
+}
+
+// searchCacheKey namespaces the key by tenant and by the current search generations, so that bumping
+// a generation makes every cached result of the tenant unreachable
+func (s *CachedProductService) searchCacheKey(ctx context.Context, tenantID string, criteria model.ProductSearchCriteria) (string, error) {
+	generations, err := s.cache.Counters(ctx,
+		productSearchGenerationKeyPrefix+allTenantsGeneration,
+		productSearchGenerationKeyPrefix+tenantID,
+	)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%s:%d.%d:%s", productSearchCacheKeyPrefix, tenantID, generations[0], generations[1], criteria.Key()), nil
 }
 
-func (s *CachedProductService) nameCacheKey(name string) string {
-	return fmt.Sprintf("%s%s", productNameCacheKeyPrefix, name)
+// isPopularSearch counts a search of the query and reports whether it is popular enough to be cached
+func (s *CachedProductService) isPopularSearch(ctx context.Context, tenantID string, criteria model.ProductSearchCriteria) bool {
+	hitsKey := fmt.Sprintf("%s%s:%s", productSearchHitsKeyPrefix, tenantID, criteria.Key())
+	hits, err := s.cache.Increment(ctx, hitsKey, productSearchPopularWindow)
+	if err != nil {
+		log.SugaredLogger.Warnf("Failed to count product search %s: %v", criteria.Key(), err)
+		return false
+	}
+	return hits >= productSearchPopularHits
 }
 
 func (s *CachedProductService) cacheProduct(ctx context.Context, product *model.Product) {
@@ -216,28 +527,54 @@ func (s *CachedProductService) cacheProduct(ctx context.Context, product *model.
 		return
 	}
 
-	cacheKey := s.productCacheKey(product.ID)
+	cacheKey := s.productCacheKey(product.TenantID, product.ID)
 	if err := s.cache.Set(ctx, cacheKey, product, s.ttl); err != nil {
 		log.SugaredLogger.Warnf("Failed to cache product %s: %v", product.ID, err)
 	}
 
 	// Also cache name -> id mapping
-	nameCacheKey := s.nameCacheKey(product.Name)
+	nameCacheKey := s.nameCacheKey(product.TenantID, product.Name)
 	if err := s.cache.Set(ctx, nameCacheKey, product.ID, s.ttl); err != nil {
 		log.SugaredLogger.Warnf("Failed to cache name mapping for %s: %v", product.Name, err)
 	}
 }
 
-func (s *CachedProductService) invalidateProductCache(ctx context.Context, id string) {
-	cacheKey := s.productCacheKey(id)
-	if err := s.cache.Delete(ctx, cacheKey); err != nil {
+// invalidateProductCache drops a product cached for the tenant, or for every tenant when tenantID is
+// empty, as outside requests where the tenant of the product is not known
+func (s *CachedProductService) invalidateProductCache(ctx context.Context, tenantID, id string) {
+	var err error
+	if tenantID != "" {
+		err = s.cache.Delete(ctx, s.productCacheKey(tenantID, id))
+	} else {
+		err = s.cache.DeleteWithPattern(ctx, s.productCacheKey("*", id))
+	}
+	if err != nil {
 		log.SugaredLogger.Warnf("Failed to invalidate product cache %s: %v", id, err)
 	}
 }
 
-func (s *CachedProductService) invalidateNameCache(ctx context.Context, name string) {
-	cacheKey := s.nameCacheKey(name)
+func (s *CachedProductService) invalidateNameCache(ctx context.Context, tenantID, name string) {
+	cacheKey := s.nameCacheKey(tenantID, name)
 	if err := s.cache.Delete(ctx, cacheKey); err != nil {
 		log.SugaredLogger.Warnf("Failed to invalidate name cache %s: %v", name, err)
 	}
 }
+
+// invalidateSearchCache drops the cached search results of the tenant, or of every tenant when tenantID
+// is empty, as a product change can affect any of them. It bumps the search generation rather than
+// deleting keys.
+func (s *CachedProductService) invalidateSearchCache(ctx context.Context, tenantID string) {
+	if tenantID == "" {
+		tenantID = allTenantsGeneration
+	}
+	if _, err := s.cache.Increment(ctx, productSearchGenerationKeyPrefix+tenantID, 0); err != nil {
+		log.SugaredLogger.Warnf("Failed to invalidate product search cache: %v", err)
+	}
+}
+
+// refreshProduct replaces the cached product after a change and drops search results that may include it
+func (s *CachedProductService) refreshProduct(ctx context.Context, product *model.Product) {
+	s.invalidateProductCache(ctx, product.TenantID, product.ID)
+	s.invalidateSearchCache(ctx, product.TenantID)
+	s.cacheProduct(ctx, product)
+}
@@ -55,7 +55,7 @@ func (s *CachedUserService) Create(ctx context.Context, email, name, password st
 }
 
 // Update updates a user and invalidates the cache
-func (s *CachedUserService) Update(ctx context.Context, id string, name string) (*model.User, error) {
+func (s *CachedUserService) Update(ctx context.Context, id string, name string, expectedVersion int) (*model.User, error) {
 	ctx, span := otel.Tracer(cachedUserServiceTracerName).Start(ctx, "CachedUserService.Update")
 	defer span.End()
 
@@ -63,15 +63,15 @@ func (s *CachedUserService) Update(ctx context.Context, id string, name string)
 	currentUser, _ := s.delegate.Get(ctx, id)
 
 	// Delegate to the underlying service
-	user, err := s.delegate.Update(ctx, id, name)
+	user, err := s.delegate.Update(ctx, id, name, expectedVersion)
 	if err != nil {
 		return nil, err
 	}
 
 	// Invalidate caches
-	s.invalidateUserCache(ctx, id)
+	s.invalidateUserCache(ctx, user.TenantID, id)
 	if currentUser != nil {
-		s.invalidateEmailCache(ctx, currentUser.Email)
+		s.invalidateEmailCache(ctx, currentUser.TenantID, currentUser.Email)
 	}
 
 	// Cache the updated user
@@ -95,9 +95,9 @@ func (s *CachedUserService) Delete(ctx context.Context, id string) error {
 	}
 
 	// Invalidate caches
-	s.invalidateUserCache(ctx, id)
 	if currentUser != nil {
-		s.invalidateEmailCache(ctx, currentUser.Email)
+		s.invalidateUserCache(ctx, currentUser.TenantID, id)
+		s.invalidateEmailCache(ctx, currentUser.TenantID, currentUser.Email)
 	}
 
 	return nil
@@ -110,8 +110,14 @@ func (s *CachedUserService) Get(ctx context.Context, id string) (*model.User, er
 
 	span.SetAttributes(attribute.String("user.id", id))
 
+	// Keys are per tenant, so reads outside a tenant go to the delegate
+	tenantID := model.TenantFromContext(ctx)
+	if tenantID == "" {
+		return s.delegate.Get(ctx, id)
+	}
+
 	// Try cache first
-	cacheKey := s.userCacheKey(id)
+	cacheKey := s.userCacheKey(tenantID, id)
 	var user model.User
 
 	err := s.cache.Get(ctx, cacheKey, &user)
@@ -147,8 +153,13 @@ func (s *CachedUserService) GetByEmail(ctx context.Context, email string) (*mode
 
 	span.SetAttributes(attribute.String("user.email", email))
 
+	tenantID := model.TenantFromContext(ctx)
+	if tenantID == "" {
+		return s.delegate.GetByEmail(ctx, email)
+	}
+
 	// Try email->id cache first
-	emailCacheKey := s.emailCacheKey(email)
+	emailCacheKey := s.emailCacheKey(tenantID, email)
 	var userID string
 
 	err := s.cache.Get(ctx, emailCacheKey, &userID)
@@ -184,14 +195,96 @@ func (s *CachedUserService) List(ctx context.Context, offset, limit int) ([]*mod
 	return s.delegate.List(ctx, offset, limit)
 }
 
+// ChangePassword changes a user's password and refreshes the cache
+func (s *CachedUserService) ChangePassword(ctx context.Context, id, currentPassword, newPassword string, expectedVersion int) (*model.User, error) {
+	ctx, span := otel.Tracer(cachedUserServiceTracerName).Start(ctx, "CachedUserService.ChangePassword")
+	defer span.End()
+
+	user, err := s.delegate.ChangePassword(ctx, id, currentPassword, newPassword, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateUserCache(ctx, user.TenantID, id)
+	s.cacheUser(ctx, user)
+
+	return user, nil
+}
+
+// AssignRoles replaces a user's roles and refreshes the cache
+func (s *CachedUserService) AssignRoles(ctx context.Context, id string, roles []model.Role, permissions []model.Permission, expectedVersion int) (*model.User, error) {
+	ctx, span := otel.Tracer(cachedUserServiceTracerName).Start(ctx, "CachedUserService.AssignRoles")
+	defer span.End()
+
+	user, err := s.delegate.AssignRoles(ctx, id, roles, permissions, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateUserCache(ctx, user.TenantID, id)
+	s.cacheUser(ctx, user)
+
+	return user, nil
+}
+
+// VerifyEmail marks a user's email address as verified and refreshes the cache
+func (s *CachedUserService) VerifyEmail(ctx context.Context, id string) (*model.User, error) {
+	ctx, span := otel.Tracer(cachedUserServiceTracerName).Start(ctx, "CachedUserService.VerifyEmail")
+	defer span.End()
+
+	user, err := s.delegate.VerifyEmail(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateUserCache(ctx, user.TenantID, id)
+	s.cacheUser(ctx, user)
+
+	return user, nil
+}
+
+// ResetPassword replaces a user's forgotten password and refreshes the cache
+func (s *CachedUserService) ResetPassword(ctx context.Context, id, newPassword string) (*model.User, error) {
+	ctx, span := otel.Tracer(cachedUserServiceTracerName).Start(ctx, "CachedUserService.ResetPassword")
+	defer span.End()
+
+	user, err := s.delegate.ResetPassword(ctx, id, newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateUserCache(ctx, user.TenantID, id)
+	s.cacheUser(ctx, user)
+
+	return user, nil
+}
+
+// Authenticate verifies a user's credentials against the stored hash (not cached - the hash
+// must be current) and refreshes the cache, as a rehash changes the user's version
+func (s *CachedUserService) Authenticate(ctx context.Context, email, password string) (*model.User, error) {
+	ctx, span := otel.Tracer(cachedUserServiceTracerName).Start(ctx, "CachedUserService.Authenticate")
+	defer span.End()
+
+	user, err := s.delegate.Authenticate(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateUserCache(ctx, user.TenantID, user.ID)
+	s.cacheUser(ctx, user)
+
+	return user, nil
+}
+
 // Helper methods
 
-func (s *CachedUserService) userCacheKey(id string) string {
-	return fmt.Sprintf("%s%s", userCacheKeyPrefix, id)
+// userCacheKey namespaces the key by tenant, so a tenant never reads another tenant's users
+func (s *CachedUserService) userCacheKey(tenantID, id string) string {
+	return fmt.Sprintf("%s%s:%s", userCacheKeyPrefix, tenantID, id)
 }
 
-func (s *CachedUserService) emailCacheKey(email string) string {
-	return fmt.Sprintf("%s%s", userEmailCacheKeyPrefix, email)
+func (s *CachedUserService) emailCacheKey(tenantID, email string) string {
+	return fmt.Sprintf("%s%s:%s", userEmailCacheKeyPrefix, tenantID, email)
 }
 
 func (s *CachedUserService) cacheUser(ctx context.Context, user *model.User) {
@@ -199,27 +292,27 @@ func (s *CachedUserService) cacheUser(ctx context.Context, user *model.User) {
 		return
 	}
 
-	cacheKey := s.userCacheKey(user.ID)
+	cacheKey := s.userCacheKey(user.TenantID, user.ID)
 	if err := s.cache.Set(ctx, cacheKey, user, s.ttl); err != nil {
 		log.SugaredLogger.Warnf("Failed to cache user %s: %v", user.ID, err)
 	}
 
 	// Also cache email -> id mapping
-	emailCacheKey := s.emailCacheKey(user.Email)
+	emailCacheKey := s.emailCacheKey(user.TenantID, user.Email)
 	if err := s.cache.Set(ctx, emailCacheKey, user.ID, s.ttl); err != nil {
 		log.SugaredLogger.Warnf("Failed to cache email mapping for %s: %v", user.Email, err)
 	}
 }
 
-func (s *CachedUserService) invalidateUserCache(ctx context.Context, id string) {
-	cacheKey := s.userCacheKey(id)
+func (s *CachedUserService) invalidateUserCache(ctx context.Context, tenantID, id string) {
+	cacheKey := s.userCacheKey(tenantID, id)
 	if err := s.cache.Delete(ctx, cacheKey); err != nil {
 		log.SugaredLogger.Warnf("Failed to invalidate user cache %s: %v", id, err)
 	}
 }
 
-func (s *CachedUserService) invalidateEmailCache(ctx context.Context, email string) {
-	cacheKey := s.emailCacheKey(email)
+func (s *CachedUserService) invalidateEmailCache(ctx context.Context, tenantID, email string) {
+	cacheKey := s.emailCacheKey(tenantID, email)
 	if err := s.cache.Delete(ctx, cacheKey); err != nil {
 		log.SugaredLogger.Warnf("Failed to invalidate email cache %s: %v", email, err)
 	}
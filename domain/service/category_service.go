@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+// ICategoryService defines the interface for category service operations
+type ICategoryService interface {
+	Create(ctx context.Context, name, parentID string) (*model.Category, error)
+	Update(ctx context.Context, id, name, parentID string, expectedVersion int) (*model.Category, error)
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (*model.Category, error)
+	List(ctx context.Context) ([]*model.Category, error)
+	Breadcrumbs(ctx context.Context, categoryIDs []string) (map[string][]*model.Category, error)
+}
+
+// CategoryService implements ICategoryService
+type CategoryService struct {
+	repo           repo.ICategoryRepo
+	productService IProductService
+	eventBus       event.EventBus
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(repo repo.ICategoryRepo, productService IProductService, eventBus event.EventBus) *CategoryService {
+	if eventBus == nil {
+		eventBus = event.NewNoopEventBus()
+	}
+	return &CategoryService{
+		repo:           repo,
+		productService: productService,
+		eventBus:       eventBus,
+	}
+}
+
+// Create creates a category under parentID, or a root category when parentID is empty
+func (s *CategoryService) Create(ctx context.Context, name, parentID string) (*model.Category, error) {
+	parent, err := s.getParent(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := model.NewCategory(name, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, created.ID, category.Events())
+
+	return created, nil
+}
+
+// Update renames a category and moves it, with its subtree, under parentID (the root when empty).
+// Products stay assigned to the categories they were in, so moving only rewrites category paths.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *CategoryService) Update(ctx context.Context, id, name, parentID string, expectedVersion int) (*model.Category, error) {
+	category, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, model.ErrCategoryNotFound
+	}
+
+	// A previous move that failed half way is completed first, so retrying the request repairs it
+	if category.HasPendingMove() {
+		if err := s.repo.MoveSubtree(ctx, category); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := model.CheckVersion(category.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	parent, err := s.getParent(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := category.Rename(name); err != nil {
+		return nil, err
+	}
+	if err := category.MoveTo(parent); err != nil {
+		return nil, err
+	}
+
+	// The move is saved with the category and its descendants follow; if that fails, the
+	// pending move stays on the category and the next update completes it
+	if err := s.repo.Update(ctx, category); err != nil {
+		return nil, err
+	}
+
+	if category.HasPendingMove() {
+		if err := s.repo.MoveSubtree(ctx, category); err != nil {
+			return nil, err
+		}
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, category.ID, category.Events())
+
+	return category, nil
+}
+
+// Delete deletes a leaf category, moving its products up to the parent category.
+// Products of a root category are left without it.
+func (s *CategoryService) Delete(ctx context.Context, id string) error {
+	category, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if category == nil {
+		return model.ErrCategoryNotFound
+	}
+
+	children, err := s.repo.ListSubtree(ctx, category.SubtreePath())
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return model.ErrCategoryHasChildren
+	}
+
+	// Reassign products first: if deleting fails the category still exists and can be deleted again
+	if _, err := s.productService.ReplaceCategory(ctx, category.ID, category.ParentID); err != nil {
+		return err
+	}
+
+	category.MarkDeleted()
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, category.ID, category.Events())
+
+	return nil
+}
+
+// Get retrieves a category by ID
+func (s *CategoryService) Get(ctx context.Context, id string) (*model.Category, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List retrieves all categories ordered by path
+func (s *CategoryService) List(ctx context.Context) ([]*model.Category, error) {
+	return s.repo.List(ctx)
+}
+
+// Breadcrumbs returns, for each known category ID, the chain of categories from the root down to it
+func (s *CategoryService) Breadcrumbs(ctx context.Context, categoryIDs []string) (map[string][]*model.Category, error) {
+	breadcrumbs := make(map[string][]*model.Category, len(categoryIDs))
+	if len(categoryIDs) == 0 {
+		return breadcrumbs, nil
+	}
+
+	categories, err := s.repo.GetByIDs(ctx, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load every ancestor in a single query
+	byID := make(map[string]*model.Category, len(categories))
+	var ancestorIDs []string
+	for _, category := range categories {
+		byID[category.ID] = category
+		ancestorIDs = append(ancestorIDs, category.AncestorIDs()...)
+	}
+	ancestors, err := s.repo.GetByIDs(ctx, ancestorIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, ancestor := range ancestors {
+		byID[ancestor.ID] = ancestor
+	}
+
+	for _, category := range categories {
+		trail := make([]*model.Category, 0, len(category.AncestorIDs())+1)
+		for _, ancestorID := range category.AncestorIDs() {
+			if ancestor, ok := byID[ancestorID]; ok {
+				trail = append(trail, ancestor)
+			}
+		}
+		breadcrumbs[category.ID] = append(trail, category)
+	}
+
+	return breadcrumbs, nil
+}
+
+// getParent loads the parent category, or returns nil for an empty parentID
+func (s *CategoryService) getParent(ctx context.Context, parentID string) (*model.Category, error) {
+	if parentID == "" {
+		return nil, nil
+	}
+
+	parent, err := s.repo.GetByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, model.ErrCategoryNotFound
+	}
+	return parent, nil
+}
+
+// publishEvents publishes domain events for the given aggregate
+func (s *CategoryService) publishEvents(ctx context.Context, aggregateID string, events []model.DomainEvent) {
+	for _, domainEvent := range events {
+		evt := event.NewBaseEvent(
+			domainEvent.EventName(),
+			aggregateID,
+			domainEvent,
+		)
+		if err := s.eventBus.Publish(ctx, evt); err != nil {
+			log.SugaredLogger.Errorf("Failed to publish event %s: %v", domainEvent.EventName(), err)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+var errMoveInterrupted = errors.New("move interrupted")
+
+// memoryCategoryRepo keeps the category tree in memory and moves subtrees by rewriting path prefixes
+type memoryCategoryRepo struct {
+	categories map[string]*model.Category
+
+	// failMoves makes the next MoveSubtree calls fail, as an interrupted move would
+	failMoves int
+}
+
+func newMemoryCategoryRepo(categories ...*model.Category) *memoryCategoryRepo {
+	r := &memoryCategoryRepo{categories: map[string]*model.Category{}}
+	for _, category := range categories {
+		if category.Version == 0 {
+			category.Version = 1
+		}
+		r.categories[category.ID] = category
+	}
+	return r
+}
+
+func (r *memoryCategoryRepo) Create(_ context.Context, category *model.Category) (*model.Category, error) {
+	clone := *category
+	r.categories[category.ID] = &clone
+	return category, nil
+}
+
+func (r *memoryCategoryRepo) Update(_ context.Context, category *model.Category) error {
+	stored, ok := r.categories[category.ID]
+	if !ok || stored.Version != category.Version {
+		return model.ErrVersionConflict
+	}
+	category.Version++
+	clone := *category
+	r.categories[category.ID] = &clone
+	return nil
+}
+
+func (r *memoryCategoryRepo) MoveSubtree(_ context.Context, category *model.Category) error {
+	if r.failMoves > 0 {
+		r.failMoves--
+		return errMoveInterrupted
+	}
+
+	oldPrefix, newPrefix := category.MovingFrom, category.SubtreePath()
+	for _, stored := range r.categories {
+		if strings.HasPrefix(stored.Path, oldPrefix) {
+			stored.Path = newPrefix + strings.TrimPrefix(stored.Path, oldPrefix)
+		}
+	}
+	if stored, ok := r.categories[category.ID]; ok && stored.MovingFrom == category.MovingFrom {
+		stored.MovingFrom = ""
+	}
+	category.MovingFrom = ""
+	return nil
+}
+
+func (r *memoryCategoryRepo) Delete(_ context.Context, id string) error {
+	delete(r.categories, id)
+	return nil
+}
+
+func (r *memoryCategoryRepo) GetByID(_ context.Context, id string) (*model.Category, error) {
+	stored, ok := r.categories[id]
+	if !ok {
+		return nil, nil
+	}
+	clone := *stored
+	return &clone, nil
+}
+
+func (r *memoryCategoryRepo) GetByIDs(ctx context.Context, ids []string) ([]*model.Category, error) {
+	var categories []*model.Category
+	for _, id := range ids {
+		if category, _ := r.GetByID(ctx, id); category != nil {
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+
+func (r *memoryCategoryRepo) List(ctx context.Context) ([]*model.Category, error) {
+	return r.ListSubtree(ctx, "/")
+}
+
+func (r *memoryCategoryRepo) ListSubtree(_ context.Context, pathPrefix string) ([]*model.Category, error) {
+	var categories []*model.Category
+	for _, stored := range r.categories {
+		if strings.HasPrefix(stored.Path, pathPrefix) {
+			clone := *stored
+			categories = append(categories, &clone)
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Path+categories[i].ID < categories[j].Path+categories[j].ID
+	})
+	return categories, nil
+}
+
+// categoryReplacer records the category replacements it receives
+type categoryReplacer struct {
+	IProductService
+
+	replaced map[string]string
+}
+
+func (r *categoryReplacer) ReplaceCategory(_ context.Context, categoryID, replacementID string) ([]string, error) {
+	r.replaced[categoryID] = replacementID
+	return nil, nil
+}
+
+// testCategoryTree builds
+//
+//	root
+//	├── electronics
+//	│   └── phones
+//	│       └── android
+//	└── books
+func testCategoryTree() *memoryCategoryRepo {
+	return newMemoryCategoryRepo(
+		&model.Category{ID: "root", Name: "Root", Path: "/"},
+		&model.Category{ID: "electronics", Name: "Electronics", ParentID: "root", Path: "/root/"},
+		&model.Category{ID: "phones", Name: "Phones", ParentID: "electronics", Path: "/root/electronics/"},
+		&model.Category{ID: "android", Name: "Android", ParentID: "phones", Path: "/root/electronics/phones/"},
+		&model.Category{ID: "books", Name: "Books", ParentID: "root", Path: "/root/"},
+	)
+}
+
+func TestCategoryServiceUpdateMoves(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		parentID  string
+		wantErr   error
+		wantPaths map[string]string
+	}{
+		{
+			name:     "moves the subtree under another branch",
+			id:       "phones",
+			parentID: "books",
+			wantPaths: map[string]string{
+				"phones":  "/root/books/",
+				"android": "/root/books/phones/",
+			},
+		},
+		{
+			name: "moves the subtree to the root",
+			id:   "electronics",
+			wantPaths: map[string]string{
+				"electronics": "/",
+				"phones":      "/electronics/",
+				"android":     "/electronics/phones/",
+			},
+		},
+		{name: "rejects a move under itself", id: "phones", parentID: "phones", wantErr: model.ErrCategoryParentInvalid},
+		{name: "rejects a move under a descendant", id: "electronics", parentID: "android", wantErr: model.ErrCategoryParentInvalid},
+		{name: "rejects an unknown parent", id: "phones", parentID: "missing", wantErr: model.ErrCategoryNotFound},
+		{name: "rejects an unknown category", id: "missing", wantErr: model.ErrCategoryNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories := testCategoryTree()
+			svc := NewCategoryService(categories, nil, nil)
+
+			name := "Renamed"
+			if stored, ok := categories.categories[tt.id]; ok {
+				name = stored.Name
+			}
+
+			_, err := svc.Update(context.Background(), tt.id, name, tt.parentID, 0)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, "/root/electronics/phones/", categories.categories["android"].Path)
+				return
+			}
+			require.NoError(t, err)
+
+			for id, want := range tt.wantPaths {
+				assert.Equal(t, want, categories.categories[id].Path, id)
+			}
+			assert.Equal(t, "/root/", categories.categories["books"].Path)
+			assert.Empty(t, categories.categories[tt.id].MovingFrom)
+		})
+	}
+}
+
+func TestCategoryServiceUpdateCompletesInterruptedMove(t *testing.T) {
+	categories := testCategoryTree()
+	categories.failMoves = 1
+	svc := NewCategoryService(categories, nil, nil)
+	ctx := context.Background()
+
+	_, err := svc.Update(ctx, "phones", "Phones", "books", 0)
+	require.ErrorIs(t, err, errMoveInterrupted)
+
+	// The move is saved on the category while its descendants still have the old path
+	assert.Equal(t, "/root/books/", categories.categories["phones"].Path)
+	assert.Equal(t, "/root/electronics/phones/", categories.categories["phones"].MovingFrom)
+	assert.Equal(t, "/root/electronics/phones/", categories.categories["android"].Path)
+
+	// Retrying the request completes the move before applying it again
+	updated, err := svc.Update(ctx, "phones", "Phones", "books", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "/root/books/", updated.Path)
+	assert.Equal(t, "/root/books/phones/", categories.categories["android"].Path)
+	assert.Empty(t, categories.categories["phones"].MovingFrom)
+}
+
+func TestCategoryServiceUpdateVersion(t *testing.T) {
+	categories := testCategoryTree()
+	svc := NewCategoryService(categories, nil, nil)
+
+	_, err := svc.Update(context.Background(), "books", "Livros", "root", 2)
+	assert.ErrorIs(t, err, model.ErrVersionConflict)
+
+	updated, err := svc.Update(context.Background(), "books", "Livros", "root", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Livros", updated.Name)
+	assert.Equal(t, 2, updated.Version)
+}
+
+func TestCategoryServiceDelete(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		wantErr      error
+		wantReplaced map[string]string
+	}{
+		{name: "moves the products of a leaf up to its parent", id: "android", wantReplaced: map[string]string{"android": "phones"}},
+		{name: "rejects a category with children", id: "phones", wantErr: model.ErrCategoryHasChildren, wantReplaced: map[string]string{}},
+		{name: "rejects an unknown category", id: "missing", wantErr: model.ErrCategoryNotFound, wantReplaced: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories := testCategoryTree()
+			products := &categoryReplacer{replaced: map[string]string{}}
+			svc := NewCategoryService(categories, products, nil)
+
+			err := svc.Delete(context.Background(), tt.id)
+			assert.Equal(t, tt.wantReplaced, products.replaced)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Len(t, categories.categories, 5)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotContains(t, categories.categories, tt.id)
+		})
+	}
+}
+
+func TestCategoryServiceBreadcrumbs(t *testing.T) {
+	svc := NewCategoryService(testCategoryTree(), nil, nil)
+
+	breadcrumbs, err := svc.Breadcrumbs(context.Background(), []string{"android", "books", "missing"})
+	require.NoError(t, err)
+
+	names := func(trail []*model.Category) []string {
+		var result []string
+		for _, category := range trail {
+			result = append(result, category.Name)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"Root", "Electronics", "Phones", "Android"}, names(breadcrumbs["android"]))
+	assert.Equal(t, []string{"Root", "Books"}, names(breadcrumbs["books"]))
+	assert.NotContains(t, breadcrumbs, "missing")
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+// IInvoiceService defines the interface for invoice service operations
+type IInvoiceService interface {
+	IssueForOrder(ctx context.Context, orderID string) (*model.Invoice, error)
+	IssueCreditNote(ctx context.Context, returnID string) (*model.Invoice, error)
+	GetByOrderID(ctx context.Context, orderID string) (*model.Invoice, error)
+	ListCreditNotes(ctx context.Context, orderID string) ([]*model.Invoice, error)
+}
+
+// InvoiceService implements IInvoiceService
+type InvoiceService struct {
+	repo           repo.IInvoiceRepo
+	orderRepo      repo.IOrderRepo
+	returnRepo     repo.IReturnRepo
+	productService IProductService
+	taxRate        float64
+	eventBus       event.EventBus
+}
+
+// NewInvoiceService creates a new invoice service.
+// productService may be nil, in which case invoice lines are described by product ID.
+func NewInvoiceService(repo repo.IInvoiceRepo, orderRepo repo.IOrderRepo, returnRepo repo.IReturnRepo, productService IProductService, taxRate float64, eventBus event.EventBus) *InvoiceService {
+	if eventBus == nil {
+		eventBus = event.NewNoopEventBus()
+	}
+	return &InvoiceService{
+		repo:           repo,
+		orderRepo:      orderRepo,
+		returnRepo:     returnRepo,
+		productService: productService,
+		taxRate:        taxRate,
+		eventBus:       eventBus,
+	}
+}
+
+// IssueForOrder issues the invoice of a delivered order. It is idempotent: an existing invoice is returned as is.
+func (s *InvoiceService) IssueForOrder(ctx context.Context, orderID string) (*model.Invoice, error) {
+	existing, err := s.repo.GetByOrderID(ctx, nil, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, nil, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, model.ErrOrderNotFound
+	}
+
+	invoice, err := model.NewInvoice(order, s.describeProducts(ctx, order.Items), s.taxRate)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.create(ctx, invoice)
+	if err != nil {
+		// A concurrent issuer may have stored the order's invoice first, which the store rejects as a duplicate
+		if existing, getErr := s.repo.GetByOrderID(ctx, nil, orderID); getErr == nil && existing != nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// IssueCreditNote issues a credit note for a refunded return. It is idempotent per return.
+func (s *InvoiceService) IssueCreditNote(ctx context.Context, returnID string) (*model.Invoice, error) {
+	existing, err := s.repo.GetByReturnID(ctx, nil, returnID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	rma, err := s.returnRepo.GetByID(ctx, nil, returnID)
+	if err != nil {
+		return nil, err
+	}
+	if rma == nil {
+		return nil, model.ErrReturnNotFound
+	}
+
+	original, err := s.IssueForOrder(ctx, rma.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := model.NewCreditNote(original, rma)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.create(ctx, note)
+	if err != nil {
+		if existing, getErr := s.repo.GetByReturnID(ctx, nil, returnID); getErr == nil && existing != nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetByOrderID retrieves the invoice of an order
+func (s *InvoiceService) GetByOrderID(ctx context.Context, orderID string) (*model.Invoice, error) {
+	if ok, err := orderInTenant(ctx, s.orderRepo, orderID); err != nil || !ok {
+		return nil, err
+	}
+	return s.repo.GetByOrderID(ctx, nil, orderID)
+}
+
+// ListCreditNotes retrieves the credit notes of an order
+func (s *InvoiceService) ListCreditNotes(ctx context.Context, orderID string) ([]*model.Invoice, error) {
+	if ok, err := orderInTenant(ctx, s.orderRepo, orderID); err != nil || !ok {
+		return nil, err
+	}
+	return s.repo.ListCreditNotesByOrderID(ctx, nil, orderID)
+}
+
+// create stores a new invoice, which assigns its number, and publishes its events
+func (s *InvoiceService) create(ctx context.Context, invoice *model.Invoice) (*model.Invoice, error) {
+	created, err := s.repo.Create(ctx, nil, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, invoice.ID, invoice.Events())
+
+	return created, nil
+}
+
+// describeProducts looks up product names for invoice lines, skipping products that cannot be loaded
+func (s *InvoiceService) describeProducts(ctx context.Context, items []model.OrderItem) map[string]string {
+	descriptions := make(map[string]string, len(items))
+	if s.productService == nil {
+		return descriptions
+	}
+
+	for _, item := range items {
+		if _, ok := descriptions[item.ProductID]; ok {
+			continue
+		}
+		product, err := s.productService.Get(ctx, item.ProductID)
+		if err != nil || product == nil {
+			continue
+		}
+		descriptions[item.ProductID] = product.Name
+	}
+	return descriptions
+}
+
+// publishEvents publishes domain events for the given aggregate
+func (s *InvoiceService) publishEvents(ctx context.Context, aggregateID string, events []model.DomainEvent) {
+	for _, domainEvent := range events {
+		evt := event.NewBaseEvent(
+			domainEvent.EventName(),
+			aggregateID,
+			domainEvent,
+		)
+		if err := s.eventBus.Publish(ctx, evt); err != nil {
+			log.SugaredLogger.Errorf("Failed to publish event %s: %v", domainEvent.EventName(), err)
+		}
+	}
+}
+
+// InvoiceEventHandler issues invoices when orders are delivered and credit notes when returns are refunded
+type InvoiceEventHandler struct {
+	invoiceService IInvoiceService
+}
+
+// NewInvoiceEventHandler creates a new invoice event handler
+func NewInvoiceEventHandler(invoiceService IInvoiceService) *InvoiceEventHandler {
+	return &InvoiceEventHandler{invoiceService: invoiceService}
+}
+
+// HandleEvent issues the invoice or credit note for the event
+func (h *InvoiceEventHandler) HandleEvent(ctx context.Context, evt event.Event) error {
+	baseEvent, ok := evt.(event.BaseEvent)
+	if !ok {
+		return nil
+	}
+
+	switch payload := baseEvent.Payload.(type) {
+	case model.OrderStatusChangedEvent:
+		if payload.NewStatus != string(model.OrderStatusDelivered) {
+			return nil
+		}
+		_, err := h.invoiceService.IssueForOrder(ctx, payload.OrderID)
+		return err
+	case model.ReturnRefundedEvent:
+		_, err := h.invoiceService.IssueCreditNote(ctx, payload.ReturnID)
+		return err
+	}
+
+	return nil
+}
+
+// InterestedIn returns true for order status changes and refunded returns
+func (h *InvoiceEventHandler) InterestedIn(eventName string) bool {
+	return eventName == "order.status_changed" || eventName == "return.refunded"
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+var errDuplicateInvoice = errors.New("duplicate invoice")
+
+// memoryInvoiceRepo numbers invoices per kind and rejects a second invoice of an order or credit note of a return,
+// like the unique indexes of the invoices table
+type memoryInvoiceRepo struct {
+	invoices []*model.Invoice
+	numbers  map[model.InvoiceKind]int
+
+	// raced, when set, stores an invoice of the order the first time GetByOrderID misses,
+	// as a concurrent issuer would
+	raced *model.Invoice
+}
+
+func newMemoryInvoiceRepo() *memoryInvoiceRepo {
+	return &memoryInvoiceRepo{numbers: map[model.InvoiceKind]int{}}
+}
+
+func (r *memoryInvoiceRepo) Create(_ context.Context, _ repo.Transaction, invoice *model.Invoice) (*model.Invoice, error) {
+	for _, stored := range r.invoices {
+		if stored.Kind == invoice.Kind && stored.OrderID == invoice.OrderID && invoice.Kind == model.InvoiceKindInvoice ||
+			invoice.ReturnID != "" && stored.ReturnID == invoice.ReturnID {
+			return nil, errDuplicateInvoice
+		}
+	}
+	r.numbers[invoice.Kind]++
+	invoice.Number = fmt.Sprintf("%s-%06d", invoice.Kind, r.numbers[invoice.Kind])
+	clone := *invoice
+	r.invoices = append(r.invoices, &clone)
+	return invoice, nil
+}
+
+func (r *memoryInvoiceRepo) GetByOrderID(_ context.Context, _ repo.Transaction, orderID string) (*model.Invoice, error) {
+	for _, stored := range r.invoices {
+		if stored.OrderID == orderID && stored.Kind == model.InvoiceKindInvoice {
+			return stored, nil
+		}
+	}
+	if r.raced != nil {
+		raced := r.raced
+		r.raced = nil
+		r.invoices = append(r.invoices, raced)
+	}
+	return nil, nil
+}
+
+func (r *memoryInvoiceRepo) GetByReturnID(_ context.Context, _ repo.Transaction, returnID string) (*model.Invoice, error) {
+	for _, stored := range r.invoices {
+		if stored.ReturnID == returnID {
+			return stored, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryInvoiceRepo) ListCreditNotesByOrderID(_ context.Context, _ repo.Transaction, orderID string) ([]*model.Invoice, error) {
+	var notes []*model.Invoice
+	for _, stored := range r.invoices {
+		if stored.OrderID == orderID && stored.Kind == model.InvoiceKindCreditNote {
+			notes = append(notes, stored)
+		}
+	}
+	return notes, nil
+}
+
+// eventRecorder records the names of the events it receives and the tenants they were published in
+type eventRecorder struct {
+	names   []string
+	tenants []string
+}
+
+func (r *eventRecorder) HandleEvent(ctx context.Context, evt event.Event) error {
+	r.names = append(r.names, evt.EventName())
+	r.tenants = append(r.tenants, model.TenantFromContext(ctx))
+	return nil
+}
+
+func (r *eventRecorder) InterestedIn(string) bool { return true }
+
+func TestInvoiceServiceIssueForOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      model.OrderStatus
+		issued      bool // the order was invoiced before
+		raced       bool // a concurrent issuer stores the invoice first
+		wantErr     error
+		wantCreated bool
+	}{
+		{name: "issues the invoice of a delivered order", status: model.OrderStatusDelivered, wantCreated: true},
+		{name: "returns the existing invoice", status: model.OrderStatusDelivered, issued: true},
+		{name: "returns the invoice of a concurrent issuer", status: model.OrderStatusDelivered, raced: true},
+		{name: "order not delivered", status: model.OrderStatusShipped, wantErr: model.ErrInvoiceOrderNotDelivered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoices := newMemoryInvoiceRepo()
+			orders := newMemoryOrderRepo(returnTestOrder(tt.status))
+			existing := &model.Invoice{ID: "existing", Number: "INV-existing", Kind: model.InvoiceKindInvoice, OrderID: "o1"}
+			if tt.issued {
+				invoices.invoices = append(invoices.invoices, existing)
+			}
+			if tt.raced {
+				invoices.raced = existing
+			}
+			recorder := &eventRecorder{}
+			bus := event.NewInMemoryEventBus()
+			bus.Subscribe(recorder)
+			svc := NewInvoiceService(invoices, orders, newMemoryReturnRepo(), nil, 0.1, bus)
+
+			invoice, err := svc.IssueForOrder(context.Background(), "o1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, invoices.invoices)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, invoices.invoices, 1)
+			if !tt.wantCreated {
+				assert.Equal(t, "existing", invoice.ID)
+				assert.Empty(t, recorder.names)
+				return
+			}
+			assert.Equal(t, "invoice-000001", invoice.Number)
+			assert.Equal(t, 100.0, invoice.Total)
+			assert.Equal(t, []string{"invoice.issued"}, recorder.names)
+
+			again, err := svc.IssueForOrder(context.Background(), "o1")
+			require.NoError(t, err)
+			assert.Equal(t, invoice.ID, again.ID)
+			assert.Len(t, invoices.invoices, 1)
+		})
+	}
+}
+
+func TestInvoiceServiceIssueCreditNote(t *testing.T) {
+	invoices := newMemoryInvoiceRepo()
+	orders := newMemoryOrderRepo(returnTestOrder(model.OrderStatusPartiallyReturned))
+	returns := newMemoryReturnRepo(
+		returnTestRequest("rma-1", model.ReturnStatusRefunded, 1),
+		returnTestRequest("rma-2", model.ReturnStatusRefunded, 2),
+	)
+	svc := NewInvoiceService(invoices, orders, returns, nil, 0, nil)
+	ctx := context.Background()
+
+	first, err := svc.IssueCreditNote(ctx, "rma-1")
+	require.NoError(t, err)
+	second, err := svc.IssueCreditNote(ctx, "rma-2")
+	require.NoError(t, err)
+	again, err := svc.IssueCreditNote(ctx, "rma-1")
+	require.NoError(t, err)
+
+	// The original invoice is issued once, and each return is credited once, in sequence
+	assert.Equal(t, "credit_note-000001", first.Number)
+	assert.Equal(t, "credit_note-000002", second.Number)
+	assert.Equal(t, first.ID, again.ID)
+	assert.Equal(t, 25.0, first.Total)
+	assert.Equal(t, 50.0, second.Total)
+	assert.Len(t, invoices.invoices, 3)
+
+	notes, err := svc.ListCreditNotes(ctx, "o1")
+	require.NoError(t, err)
+	assert.Len(t, notes, 2)
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+// LockoutPolicy configures when failed logins lock out an account or a client address
+type LockoutPolicy struct {
+	MaxAccountFailures int // failed logins per account within FailureWindow, 0 for no lockout
+	MaxIPFailures      int // failed logins per client address within FailureWindow, 0 for no lockout
+	FailureWindow      time.Duration
+	Duration           time.Duration
+	DelayBase          time.Duration // wait before the next login after the first failure, doubled on each further one
+	MaxDelay           time.Duration
+}
+
+// ILockoutService defines the interface protecting logins against brute force
+type ILockoutService interface {
+	Check(ctx context.Context, email, ip string) error
+	RecordFailure(ctx context.Context, email, ip string)
+	RecordSuccess(ctx context.Context, email string)
+	Unlock(ctx context.Context, userID string) error
+}
+
+// LockoutService implements ILockoutService. Failed logins are counted per account and per client
+// address; after each failure the account must wait a growing delay before the next login, and too
+// many lock the source out for a while. Accounts are keyed by tenant and email, so unknown emails
+// are locked out like known ones.
+type LockoutService struct {
+	userService IUserService
+	store       repo.ILoginAttemptStore
+	policy      LockoutPolicy
+	eventBus    event.EventBus
+}
+
+// NewLockoutService creates a new lockout service
+func NewLockoutService(userService IUserService, store repo.ILoginAttemptStore, policy LockoutPolicy, eventBus event.EventBus) *LockoutService {
+	if eventBus == nil {
+		eventBus = event.NewNoopEventBus()
+	}
+	return &LockoutService{
+		userService: userService,
+		store:       store,
+		policy:      policy,
+		eventBus:    eventBus,
+	}
+}
+
+// Check returns model.ErrAccountLocked or model.ErrTooManyLoginAttempts while the account or the
+// client address is locked out, and model.ErrLoginThrottled while the account waits out the delay
+// of its last failure. The errors are model.RetryAfterError carrying the time left. The store is a
+// safeguard only: when it fails, logins go on.
+func (s *LockoutService) Check(ctx context.Context, email, ip string) error {
+	key := accountLockoutKey(model.TenantFromContext(ctx), email)
+	if lockedFor := s.lockedFor(ctx, key); lockedFor > 0 {
+		return model.NewRetryAfterError(model.ErrAccountLocked, lockedFor)
+	}
+	if ip != "" {
+		if lockedFor := s.lockedFor(ctx, ipLockoutKey(ip)); lockedFor > 0 {
+			return model.NewRetryAfterError(model.ErrTooManyLoginAttempts, lockedFor)
+		}
+	}
+	if waitFor := s.lockedFor(ctx, delayKey(key)); waitFor > 0 {
+		return model.NewRetryAfterError(model.ErrLoginThrottled, waitFor)
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login, locks out the account or the client address past their
+// limit and makes the account wait the delay earned by its failures before the next login
+func (s *LockoutService) RecordFailure(ctx context.Context, email, ip string) {
+	email = normalizeLoginEmail(email)
+	key := accountLockoutKey(model.TenantFromContext(ctx), email)
+	failures := s.recordFailure(ctx, key, s.policy.MaxAccountFailures, func(until time.Time, failures int) {
+		user, err := s.userService.GetByEmail(ctx, email)
+		if err != nil {
+			log.SugaredLogger.Errorf("Failed to load locked out user %s: %v", email, err)
+			return
+		}
+		if user != nil {
+			s.publish(ctx, user.ID, model.UserLockedEvent{ID: user.ID, Email: user.Email, Failures: failures, LockedUntil: until})
+		}
+	})
+
+	if ip != "" {
+		s.recordFailure(ctx, ipLockoutKey(ip), s.policy.MaxIPFailures, func(until time.Time, failures int) {
+			s.publish(ctx, ip, model.LoginIPLockedEvent{IP: ip, Failures: failures, LockedUntil: until})
+		})
+	}
+
+	if delay := s.delay(failures); delay > 0 {
+		if err := s.store.Lock(ctx, delayKey(key), delay); err != nil {
+			log.SugaredLogger.Errorf("Failed to delay the next login of %s: %v", key, err)
+		}
+	}
+}
+
+// RecordSuccess resets the failures of the account after a successful login
+func (s *LockoutService) RecordSuccess(ctx context.Context, email string) {
+	if err := s.clear(ctx, accountLockoutKey(model.TenantFromContext(ctx), email)); err != nil {
+		log.SugaredLogger.Errorf("Failed to reset failed logins of %s: %v", email, err)
+	}
+}
+
+// Unlock lifts the lockout of a user's account and resets its failures
+func (s *LockoutService) Unlock(ctx context.Context, userID string) error {
+	user, err := s.userService.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return model.ErrUserNotFound
+	}
+
+	if err := s.clear(ctx, accountLockoutKey(user.TenantID, user.Email)); err != nil {
+		return err
+	}
+
+	unlockedBy := ""
+	if principal := model.PrincipalFromContext(ctx); principal != nil {
+		unlockedBy = principal.UserID
+		if unlockedBy == "" {
+			unlockedBy = principal.APIKeyID
+		}
+	}
+	s.publish(ctx, user.ID, model.UserUnlockedEvent{ID: user.ID, UnlockedBy: unlockedBy})
+
+	return nil
+}
+
+// recordFailure counts a failure for the key and locks it out once it reaches limit, calling
+// locked. It returns the failures counted so far.
+func (s *LockoutService) recordFailure(ctx context.Context, key string, limit int, locked func(until time.Time, failures int)) int {
+	failures, err := s.store.RecordFailure(ctx, key, s.policy.FailureWindow)
+	if err != nil {
+		log.SugaredLogger.Errorf("Failed to record failed login of %s: %v", key, err)
+		return 0
+	}
+	if limit <= 0 || failures < limit {
+		return failures
+	}
+
+	if err := s.store.Lock(ctx, key, s.policy.Duration); err != nil {
+		log.SugaredLogger.Errorf("Failed to lock out %s: %v", key, err)
+		return failures
+	}
+	log.SugaredLogger.Warnf("Locked out %s for %s after %d failed logins", key, s.policy.Duration, failures)
+	locked(time.Now().Add(s.policy.Duration), failures)
+
+	return failures
+}
+
+// lockedFor returns how long the key stays locked out, zero when it is not or the store fails
+func (s *LockoutService) lockedFor(ctx context.Context, key string) time.Duration {
+	lockedFor, err := s.store.LockedFor(ctx, key)
+	if err != nil {
+		log.SugaredLogger.Errorf("Failed to check lockout of %s: %v", key, err)
+		return 0
+	}
+	return lockedFor
+}
+
+// clear resets the failures of the account key, its lockout and its delay
+func (s *LockoutService) clear(ctx context.Context, key string) error {
+	if err := s.store.Clear(ctx, key); err != nil {
+		return err
+	}
+	return s.store.Clear(ctx, delayKey(key))
+}
+
+// delay returns DelayBase doubled for every failure after the first, up to MaxDelay
+func (s *LockoutService) delay(failures int) time.Duration {
+	if failures <= 0 || s.policy.DelayBase <= 0 {
+		return 0
+	}
+
+	delay := s.policy.DelayBase
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if s.policy.MaxDelay > 0 && delay >= s.policy.MaxDelay {
+			return s.policy.MaxDelay
+		}
+	}
+	return delay
+}
+
+func (s *LockoutService) publish(ctx context.Context, aggregateID string, domainEvent model.DomainEvent) {
+	evt := event.NewBaseEvent(domainEvent.EventName(), aggregateID, domainEvent)
+	if err := s.eventBus.Publish(ctx, evt); err != nil {
+		log.SugaredLogger.Errorf("Failed to publish event %s: %v", domainEvent.EventName(), err)
+	}
+}
+
+func accountLockoutKey(tenantID, email string) string {
+	return "account:" + tenantID + ":" + normalizeLoginEmail(email)
+}
+
+// delayKey returns the key locked while the account waits before its next login
+func delayKey(accountKey string) string {
+	return "delay:" + accountKey
+}
+
+// normalizeLoginEmail returns the email as failed logins are counted, trimmed and lower case
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipLockoutKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// memoryAttemptStore keeps failed logins and lockouts in memory
+type memoryAttemptStore struct {
+	failures map[string]int
+	locks    map[string]time.Time
+}
+
+func newMemoryAttemptStore() *memoryAttemptStore {
+	return &memoryAttemptStore{failures: map[string]int{}, locks: map[string]time.Time{}}
+}
+
+func (s *memoryAttemptStore) RecordFailure(_ context.Context, key string, _ time.Duration) (int, error) {
+	s.failures[key]++
+	return s.failures[key], nil
+}
+
+func (s *memoryAttemptStore) Lock(_ context.Context, key string, duration time.Duration) error {
+	s.locks[key] = time.Now().Add(duration)
+	delete(s.failures, key)
+	return nil
+}
+
+func (s *memoryAttemptStore) LockedFor(_ context.Context, key string) (time.Duration, error) {
+	if left := time.Until(s.locks[key]); left > 0 {
+		return left, nil
+	}
+	return 0, nil
+}
+
+func (s *memoryAttemptStore) Clear(_ context.Context, key string) error {
+	delete(s.failures, key)
+	delete(s.locks, key)
+	return nil
+}
+
+// elapse lets every lockout and delay run out
+func (s *memoryAttemptStore) elapse() {
+	for key := range s.locks {
+		s.locks[key] = time.Now()
+	}
+}
+
+// retryAfter asserts err is a model.RetryAfterError of want and returns its retry delay
+func retryAfter(t *testing.T, err, want error) time.Duration {
+	t.Helper()
+	require.ErrorIs(t, err, want)
+	var retryErr *model.RetryAfterError
+	require.True(t, errors.As(err, &retryErr))
+	return retryErr.RetryAfter
+}
+
+func newLockoutTestService(t *testing.T, policy LockoutPolicy) (*LockoutService, *memoryAttemptStore, *eventRecorder) {
+	t.Helper()
+	users := newMemoryUserRepo(&model.User{ID: "u1", Email: "jane@example.com", Name: "Jane", Version: 1})
+	store := newMemoryAttemptStore()
+	recorder := &eventRecorder{}
+	bus := event.NewInMemoryEventBus()
+	bus.Subscribe(recorder)
+	return NewLockoutService(NewUserService(users, nil, testHasher(t), nil, nil), store, policy, bus), store, recorder
+}
+
+func TestLockoutServiceRecordFailure(t *testing.T) {
+	svc, store, recorder := newLockoutTestService(t, LockoutPolicy{
+		MaxAccountFailures: 3,
+		Duration:           time.Minute,
+		DelayBase:          time.Second,
+		MaxDelay:           4 * time.Second,
+	})
+	ctx := context.Background()
+	require.NoError(t, svc.Check(ctx, "jane@example.com", "10.0.0.1"))
+
+	// A failure answers at once and makes the account wait before the next login
+	started := time.Now()
+	svc.RecordFailure(ctx, "jane@example.com", "10.0.0.1")
+	assert.Less(t, time.Since(started), 500*time.Millisecond)
+	assert.InDelta(t, time.Second, retryAfter(t, svc.Check(ctx, "jane@example.com", "10.0.0.1"), model.ErrLoginThrottled), float64(100*time.Millisecond))
+
+	// The delay applies to the account whatever the case of the email, and doubles on each failure
+	assert.ErrorIs(t, svc.Check(ctx, " Jane@Example.com", "10.0.0.2"), model.ErrLoginThrottled)
+	store.elapse()
+	require.NoError(t, svc.Check(ctx, "jane@example.com", "10.0.0.1"))
+	svc.RecordFailure(ctx, "Jane@Example.com ", "10.0.0.1")
+	assert.InDelta(t, 2*time.Second, retryAfter(t, svc.Check(ctx, "jane@example.com", "10.0.0.1"), model.ErrLoginThrottled), float64(100*time.Millisecond))
+
+	// The last failure allowed locks the account out and names the user in the event
+	store.elapse()
+	svc.RecordFailure(ctx, "JANE@EXAMPLE.COM", "10.0.0.1")
+	assert.InDelta(t, time.Minute, retryAfter(t, svc.Check(ctx, "jane@example.com", "10.0.0.1"), model.ErrAccountLocked), float64(100*time.Millisecond))
+	assert.Equal(t, []string{model.UserLockedEvent{}.EventName()}, recorder.names)
+
+	// Unlocking lifts the lockout and the delay
+	require.NoError(t, svc.Unlock(ctx, "u1"))
+	assert.NoError(t, svc.Check(ctx, "jane@example.com", "10.0.0.1"))
+}
+
+func TestLockoutServiceIPLockout(t *testing.T) {
+	svc, _, recorder := newLockoutTestService(t, LockoutPolicy{MaxAccountFailures: 5, MaxIPFailures: 2, Duration: time.Minute})
+	ctx := context.Background()
+
+	svc.RecordFailure(ctx, "jane@example.com", "10.0.0.1")
+	svc.RecordFailure(ctx, "john@example.com", "10.0.0.1")
+
+	assert.Greater(t, retryAfter(t, svc.Check(ctx, "nobody@example.com", "10.0.0.1"), model.ErrTooManyLoginAttempts), 50*time.Second)
+	assert.NoError(t, svc.Check(ctx, "nobody@example.com", "10.0.0.2"))
+	assert.Equal(t, []string{model.LoginIPLockedEvent{}.EventName()}, recorder.names)
+}
+
+func TestLockoutServiceRecordSuccess(t *testing.T) {
+	svc, store, _ := newLockoutTestService(t, LockoutPolicy{MaxAccountFailures: 2, Duration: time.Minute, DelayBase: time.Second})
+	ctx := context.Background()
+
+	svc.RecordFailure(ctx, "jane@example.com", "")
+	store.elapse()
+	svc.RecordSuccess(ctx, "jane@example.com")
+
+	// The failures start over after a successful login
+	svc.RecordFailure(ctx, "jane@example.com", "")
+	assert.ErrorIs(t, svc.Check(ctx, "jane@example.com", ""), model.ErrLoginThrottled)
+}
@@ -2,46 +2,84 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
 	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
 )
 
 // IOrderService defines the interface for order service operations
 type IOrderService interface {
-	Create(ctx context.Context, userID string, items []model.OrderItem) (*model.Order, error)
+	Create(ctx context.Context, userID, organizationID string, items []model.OrderItem, shipTo *model.Location, shippingAddressID, billingAddressID string) (*model.Order, error)
 	Get(ctx context.Context, id string) (*model.Order, error)
 	GetByUserID(ctx context.Context, userID string, offset, limit int) ([]*model.Order, int64, error)
 	List(ctx context.Context, offset, limit int) ([]*model.Order, int64, error)
-	UpdateStatus(ctx context.Context, id string, status model.OrderStatus) error
+	UpdateStatus(ctx context.Context, id string, status model.OrderStatus, expectedVersion int) (*model.Order, error)
 	Cancel(ctx context.Context, id string) error
+	ListPendingBefore(ctx context.Context, before time.Time, offset, limit int) ([]*model.Order, error)
+	Approve(ctx context.Context, id, approverID string, expectedVersion int) (*model.Order, error)
+	Reject(ctx context.Context, id, approverID, reason string, expectedVersion int) (*model.Order, error)
+	EscalateApproval(ctx context.Context, id string) (*model.Order, error)
+	ListAwaitingApprovalBefore(ctx context.Context, before time.Time, offset, limit int) ([]*model.Order, error)
 }
 
 // OrderService implements IOrderService
 type OrderService struct {
-	repo      repo.IOrderRepo
-	userRepo  repo.IUserRepo
-	txFactory repo.TransactionFactory
-	eventBus  event.EventBus
+	repo               repo.IOrderRepo
+	userRepo           repo.IUserRepo
+	organizationRepo   repo.IOrganizationRepo
+	addressRepo        repo.IUserAddressRepo
+	shipmentRepo       repo.IShipmentRepo
+	productService     IProductService
+	reservationService IReservationService
+	txFactory          repo.TransactionFactory
+	eventBus           event.EventBus
 }
 
-// NewOrderService creates a new order service
-func NewOrderService(repo repo.IOrderRepo, userRepo repo.IUserRepo, txFactory repo.TransactionFactory, eventBus event.EventBus) *OrderService {
+// NewOrderService creates a new order service.
+// Without a product service, orders are created without reserving stock. With a reservation service,
+// stock is held when the order is created and only decremented when it is confirmed. Without an
+// organization repository, orders cannot be placed on behalf of organizations. Without an address
+// repository, orders are created without shipping and billing addresses. Without a shipment repository,
+// cancellations rely on the order status alone to tell whether items have shipped.
+func NewOrderService(repo repo.IOrderRepo, userRepo repo.IUserRepo, organizationRepo repo.IOrganizationRepo, addressRepo repo.IUserAddressRepo, shipmentRepo repo.IShipmentRepo, productService IProductService, reservationService IReservationService, txFactory repo.TransactionFactory, eventBus event.EventBus) *OrderService {
 	if eventBus == nil {
 		eventBus = event.NewNoopEventBus()
 	}
 	return &OrderService{
-		repo:      repo,
-		userRepo:  userRepo,
-		txFactory: txFactory,
-		eventBus:  eventBus,
+		repo:               repo,
+		userRepo:           userRepo,
+		organizationRepo:   organizationRepo,
+		addressRepo:        addressRepo,
+		shipmentRepo:       shipmentRepo,
+		productService:     productService,
+		reservationService: reservationService,
+		txFactory:          txFactory,
+		eventBus:           eventBus,
 	}
 }
 
-// Create creates a new order
-func (s *OrderService) Create(ctx context.Context, userID string, items []model.OrderItem) (*model.Order, error) {
+// Create creates a new order. Items are priced from the catalog when a product service is set.
+// The stock of every item is held until the order is confirmed or, without a reservation service,
+// reserved right away from the warehouses picked by the allocation strategy. shipTo is optional and
+// used to find the nearest warehouses. Users must have verified their email address.
+// A non-empty organizationID places the order on behalf of an organization the user is a buyer or an admin of.
+// Such an order above the spending limit of the user awaits approval, and its stock is only set aside
+// once it is approved.
+// The shipping and billing addresses are copied into the order, from the address book of the user or
+// the shared addresses of the organization when their IDs are given, from the default addresses of
+// the user otherwise. Later changes to the address book leave the order untouched.
+func (s *OrderService) Create(ctx context.Context, userID, organizationID string, items []model.OrderItem, shipTo *model.Location, shippingAddressID, billingAddressID string) (*model.Order, error) {
+	if shipTo != nil {
+		if err := shipTo.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	// Verify user exists
 	user, err := s.userRepo.GetByID(ctx, nil, userID)
 	if err != nil {
@@ -50,16 +88,47 @@ func (s *OrderService) Create(ctx context.Context, userID string, items []model.
 	if user == nil {
 		return nil, model.ErrUserNotFound
 	}
+	if !user.IsEmailVerified() {
+		return nil, model.ErrUserEmailNotVerified
+	}
+
+	var org *model.Organization
+	if organizationID != "" {
+		if org, err = s.organizationBuyer(ctx, organizationID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	shippingAddress, billingAddress, err := s.resolveAddresses(ctx, userID, org, shippingAddressID, billingAddressID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.priceItems(ctx, items); err != nil {
+		return nil, err
+	}
 
 	// Create order
-	order, err := model.NewOrder(userID, items)
+	order, err := model.NewOrder(userID, organizationID, items)
 	if err != nil {
 		return nil, err
 	}
+	order.ShipTo = shipTo
+	order.ShippingAddress = shippingAddress
+	order.BillingAddress = billingAddress
+
+	if org != nil && org.RequiresApproval(userID, order.Total) {
+		if err := order.RequireApproval(); err != nil {
+			return nil, err
+		}
+	} else if err := s.reserveStock(ctx, order); err != nil {
+		return nil, err
+	}
 
 	// Save to repository
 	created, err := s.repo.Create(ctx, nil, order)
 	if err != nil {
+		s.releaseStock(ctx, order)
 		return nil, err
 	}
 
@@ -84,49 +153,69 @@ func (s *OrderService) List(ctx context.Context, offset, limit int) ([]*model.Or
 	return s.repo.List(ctx, nil, offset, limit)
 }
 
-// UpdateStatus updates the order status
-func (s *OrderService) UpdateStatus(ctx context.Context, id string, status model.OrderStatus) error {
+// UpdateStatus updates the order status and returns the updated order. Only confirmations and
+// cancellations are accepted; shipped and delivered are derived from the shipments of the order.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *OrderService) UpdateStatus(ctx context.Context, id string, status model.OrderStatus, expectedVersion int) (*model.Order, error) {
 	order, err := s.repo.GetByID(ctx, nil, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if order == nil {
-		return model.ErrOrderNotFound
+		return nil, model.ErrOrderNotFound
+	}
+
+	if err := model.CheckVersion(order.Version, expectedVersion); err != nil {
+		return nil, err
 	}
 
 	// Validate status transition
 	switch status {
 	case model.OrderStatusConfirmed:
 		if err := order.Confirm(); err != nil {
-			return err
-		}
-	case model.OrderStatusShipped:
-		if err := order.Ship(); err != nil {
-			return err
-		}
-	case model.OrderStatusDelivered:
-		if err := order.Deliver(); err != nil {
-			return err
+			return nil, err
 		}
 	case model.OrderStatusCancelled:
-		if err := order.Cancel(); err != nil {
-			return err
+		if err := s.cancel(ctx, order); err != nil {
+			return nil, err
 		}
 	default:
-		return model.ErrOrderInvalidStatus
+		return nil, model.ErrOrderInvalidStatus
 	}
 
-	if err := s.repo.UpdateStatus(ctx, nil, id, status); err != nil {
-		return err
+	// Held stock is decremented for good on confirmation
+	committing := status == model.OrderStatusConfirmed && s.reservationService != nil && !order.StockAllocated()
+	if committing {
+		if err := s.ensureHold(ctx, order); err != nil {
+			return nil, err
+		}
+		if err := s.allocateStock(ctx, order); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateStatus(ctx, nil, id, status, order.Version); err != nil {
+		if committing {
+			s.returnStock(ctx, order)
+		}
+		return nil, err
+	}
+	order.Version++
+
+	if committing {
+		s.commitStock(ctx, order)
+	}
+	if status == model.OrderStatusCancelled {
+		s.releaseStock(ctx, order)
 	}
 
 	// Publish domain events
 	s.publishEvents(ctx, order)
 
-	return nil
+	return order, nil
 }
 
-// Cancel cancels an order
+// Cancel cancels an order. Orders with shipped items cannot be cancelled.
 func (s *OrderService) Cancel(ctx context.Context, id string) error {
 	order, err := s.repo.GetByID(ctx, nil, id)
 	if err != nil {
@@ -136,22 +225,367 @@ func (s *OrderService) Cancel(ctx context.Context, id string) error {
 		return model.ErrOrderNotFound
 	}
 
-	if err := order.Cancel(); err != nil {
+	if err := s.cancel(ctx, order); err != nil {
 		return err
 	}
 
-	if err := s.repo.UpdateStatus(ctx, nil, id, model.OrderStatusCancelled); err != nil {
+	if err := s.repo.UpdateStatus(ctx, nil, id, model.OrderStatusCancelled, order.Version); err != nil {
 		return err
 	}
 
+	s.releaseStock(ctx, order)
+
 	// Publish domain events
 	s.publishEvents(ctx, order)
 
 	return nil
 }
 
+// ListPendingBefore retrieves pending orders created before the cutoff, oldest first
+func (s *OrderService) ListPendingBefore(ctx context.Context, before time.Time, offset, limit int) ([]*model.Order, error) {
+	return s.repo.ListByStatusBefore(ctx, nil, model.OrderStatusPending, before, offset, limit)
+}
+
+// Approve signs off an order awaiting approval on behalf of its organization and sets its stock
+// aside. The approver must be an approver or an admin of the organization other than the buyer.
+// A non-zero expectedVersion must match the stored version.
+func (s *OrderService) Approve(ctx context.Context, id, approverID string, expectedVersion int) (*model.Order, error) {
+	order, err := s.loadForApproval(ctx, id, approverID, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := order.Approve(approverID); err != nil {
+		return nil, err
+	}
+
+	// The order stays awaiting approval when its stock cannot be set aside
+	if err := s.reserveStock(ctx, order); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, nil, order); err != nil {
+		s.releaseStock(ctx, order)
+		return nil, err
+	}
+
+	s.publishEvents(ctx, order)
+
+	return order, nil
+}
+
+// Reject turns down an order awaiting approval, which cancels it. The approver must be an approver
+// or an admin of the organization other than the buyer. A non-zero expectedVersion must match the
+// stored version.
+func (s *OrderService) Reject(ctx context.Context, id, approverID, reason string, expectedVersion int) (*model.Order, error) {
+	order, err := s.loadForApproval(ctx, id, approverID, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := order.Reject(approverID, reason); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, nil, order); err != nil {
+		return nil, err
+	}
+
+	s.publishEvents(ctx, order)
+
+	return order, nil
+}
+
+// EscalateApproval hands an order that waited too long for approval over to the organization admins
+func (s *OrderService) EscalateApproval(ctx context.Context, id string) (*model.Order, error) {
+	order, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, model.ErrOrderNotFound
+	}
+
+	if err := order.EscalateApproval(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, nil, order); err != nil {
+		return nil, err
+	}
+
+	s.publishEvents(ctx, order)
+
+	return order, nil
+}
+
+// ListAwaitingApprovalBefore retrieves orders awaiting approval, not yet escalated, created before
+// the cutoff, oldest first
+func (s *OrderService) ListAwaitingApprovalBefore(ctx context.Context, before time.Time, offset, limit int) ([]*model.Order, error) {
+	return s.repo.ListAwaitingApprovalBefore(ctx, nil, before, offset, limit)
+}
+
+// cancel cancels the order unless one of its shipments already left the warehouse, which is checked
+// against the shipments as well because the order status is only derived from them afterwards
+func (s *OrderService) cancel(ctx context.Context, order *model.Order) error {
+	if s.shipmentRepo != nil {
+		shipments, err := s.shipmentRepo.ListByOrderID(ctx, nil, order.ID)
+		if err != nil {
+			return err
+		}
+		for _, shipment := range shipments {
+			if shipment.HasShipped() {
+				return model.ErrOrderCannotCancel
+			}
+		}
+	}
+	return order.Cancel()
+}
+
+// loadForApproval retrieves an order at the expected version and checks that the approver may
+// decide on it
+func (s *OrderService) loadForApproval(ctx context.Context, id, approverID string, expectedVersion int) (*model.Order, error) {
+	order, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, model.ErrOrderNotFound
+	}
+
+	if err := model.CheckVersion(order.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+	if !order.IsAwaitingApproval() {
+		return nil, model.ErrOrderNotAwaitingApproval
+	}
+
+	if s.organizationRepo == nil {
+		return nil, model.ErrOrderApprovalForbidden
+	}
+	org, err := s.organizationRepo.GetByID(ctx, nil, order.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if org == nil || !org.CanApprove(approverID) {
+		return nil, model.ErrOrderApprovalForbidden
+	}
+
+	return order, nil
+}
+
+// organizationBuyer retrieves the organization and checks that the user may order on its behalf
+func (s *OrderService) organizationBuyer(ctx context.Context, organizationID, userID string) (*model.Organization, error) {
+	if s.organizationRepo == nil {
+		return nil, model.ErrOrganizationNotFound
+	}
+
+	org, err := s.organizationRepo.GetByID(ctx, nil, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, model.ErrOrganizationNotFound
+	}
+	if !org.CanOrder(userID) {
+		return nil, model.ErrOrganizationOrderForbidden
+	}
+	return org, nil
+}
+
+// resolveAddresses returns copies of the shipping and billing addresses of a new order. A given ID
+// names an address of the user or a shared address of the organization, an empty one falls back to
+// the default address of the user.
+func (s *OrderService) resolveAddresses(ctx context.Context, userID string, org *model.Organization, shippingAddressID, billingAddressID string) (*vo.Address, *vo.Address, error) {
+	var book []*model.UserAddress
+	if s.addressRepo != nil {
+		var err error
+		if book, err = s.addressRepo.ListByUserID(ctx, nil, userID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	shipping, err := resolveAddress(book, org, shippingAddressID, func(a *model.UserAddress) bool { return a.DefaultShipping })
+	if err != nil {
+		return nil, nil, err
+	}
+	billing, err := resolveAddress(book, org, billingAddressID, func(a *model.UserAddress) bool { return a.DefaultBilling })
+	if err != nil {
+		return nil, nil, err
+	}
+	return shipping, billing, nil
+}
+
+// resolveAddress returns a copy of the address with the ID, or of the default address of the book
+// when id is empty. It returns nil when there is no default address.
+func resolveAddress(book []*model.UserAddress, org *model.Organization, id string, isDefault func(*model.UserAddress) bool) (*vo.Address, error) {
+	for _, entry := range book {
+		if (id == "" && isDefault(entry)) || (id != "" && entry.ID == id) {
+			address := entry.Address
+			return &address, nil
+		}
+	}
+	if id == "" {
+		return nil, nil
+	}
+
+	if org != nil {
+		for _, shared := range org.Addresses {
+			if shared.ID == id {
+				address := shared.Address
+				return &address, nil
+			}
+		}
+	}
+	return nil, model.ErrUserAddressNotFound
+}
+
+// priceItems sets the price of every item to the current price of its product or variant and records
+// the price version used. An item that already has a price must match the current one.
+func (s *OrderService) priceItems(ctx context.Context, items []model.OrderItem) error {
+	if s.productService == nil {
+		return nil
+	}
+
+	for i := range items {
+		item := &items[i]
+		product, err := s.productService.Get(ctx, item.ProductID)
+		if err != nil {
+			return err
+		}
+		if product == nil {
+			return model.ErrProductNotFound
+		}
+
+		price, err := product.PriceOf(item.SKU)
+		if err != nil {
+			return err
+		}
+		if item.Price != 0 && item.Price != price {
+			return model.ErrOrderItemPriceChanged
+		}
+		item.Price = price
+		item.PriceVersion = product.PriceVersion
+	}
+	return nil
+}
+
+// reserveStock holds the stock of the order items until the order is confirmed or, without a
+// reservation service, reserves it right away
+func (s *OrderService) reserveStock(ctx context.Context, order *model.Order) error {
+	if s.reservationService != nil {
+		return s.holdStock(ctx, order)
+	}
+	return s.allocateStock(ctx, order)
+}
+
+// holdStock sets the stock of the order items aside until the order is confirmed
+func (s *OrderService) holdStock(ctx context.Context, order *model.Order) error {
+	items := make([]model.StockHoldItem, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = model.StockHoldItem{ProductID: item.ProductID, SKU: item.SKU, Quantity: item.Quantity}
+	}
+
+	_, err := s.reservationService.Hold(orderHoldContext(ctx, order), model.OrderHoldKey(order.ID), items, 0)
+	return err
+}
+
+// orderHoldContext scopes ctx to the tenant of the order, as jobs releasing orders see every tenant
+// and the hold of the order is only found in its own
+func orderHoldContext(ctx context.Context, order *model.Order) context.Context {
+	return model.EnsureTenant(ctx, order.TenantID)
+}
+
+// ensureHold checks that the hold of the order is still active before its stock is decremented,
+// as the decrement itself ignores the holds of other carts and orders. An expired hold is created
+// again, which fails with model.ErrProductInsufficientStock when the stock was held by others since.
+func (s *OrderService) ensureHold(ctx context.Context, order *model.Order) error {
+	hold, err := s.reservationService.Get(orderHoldContext(ctx, order), model.OrderHoldKey(order.ID))
+	if err != nil {
+		return err
+	}
+	if hold != nil {
+		return nil
+	}
+	return s.holdStock(ctx, order)
+}
+
+// commitStock records the warehouses the confirmed order was reserved from and drops its hold.
+// The confirmation already happened, so failures are logged rather than returned.
+func (s *OrderService) commitStock(ctx context.Context, order *model.Order) {
+	if err := s.repo.SaveAllocations(ctx, nil, order); err != nil {
+		log.SugaredLogger.Errorf("Failed to save stock allocations of order %s: %v", order.ID, err)
+	}
+	if err := s.reservationService.Commit(orderHoldContext(ctx, order), model.OrderHoldKey(order.ID)); err != nil {
+		log.SugaredLogger.Errorf("Failed to commit stock hold of order %s: %v", order.ID, err)
+	}
+}
+
+// allocateStock reserves the stock of every item and records the warehouses fulfilling it.
+// Either every item is reserved or none is.
+func (s *OrderService) allocateStock(ctx context.Context, order *model.Order) error {
+	if s.productService == nil {
+		return nil
+	}
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		allocations, err := s.productService.AllocateStock(ctx, item.ProductID, model.StockChange{
+			SKU:         item.SKU,
+			Quantity:    item.Quantity,
+			Reason:      model.StockMovementReasonOrder,
+			ReferenceID: order.ID,
+		}, order.ShipTo)
+		if err != nil {
+			s.returnStock(ctx, order)
+			return err
+		}
+		item.Allocations = allocations
+	}
+
+	return nil
+}
+
+// releaseStock drops the hold of the order and returns its reserved stock.
+// The order change already happened, so failures are logged rather than returned.
+func (s *OrderService) releaseStock(ctx context.Context, order *model.Order) {
+	if s.reservationService != nil {
+		err := s.reservationService.Release(orderHoldContext(ctx, order), model.OrderHoldKey(order.ID))
+		if err != nil && !errors.Is(err, model.ErrStockHoldNotFound) {
+			log.SugaredLogger.Errorf("Failed to release stock hold of order %s: %v", order.ID, err)
+		}
+	}
+	s.returnStock(ctx, order)
+}
+
+// returnStock puts the reserved stock of the order items back in the warehouses it came from.
+// Failures are logged rather than returned.
+func (s *OrderService) returnStock(ctx context.Context, order *model.Order) {
+	if s.productService == nil {
+		return
+	}
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		for _, allocation := range item.Allocations {
+			err := s.productService.UpdateStock(ctx, item.ProductID, model.StockChange{
+				SKU:         item.SKU,
+				WarehouseID: allocation.WarehouseID,
+				Quantity:    allocation.Quantity,
+				Reason:      model.StockMovementReasonOrder,
+				ReferenceID: order.ID,
+			})
+			if err != nil {
+				log.SugaredLogger.Errorf("Failed to release stock of product %s for order %s: %v", item.ProductID, order.ID, err)
+			}
+		}
+		item.Allocations = nil
+	}
+}
+
 // publishEvents publishes all pending domain events from the order
 func (s *OrderService) publishEvents(ctx context.Context, order *model.Order) {
+	ctx = model.EnsureTenant(ctx, order.TenantID)
 	for _, domainEvent := range order.Events() {
 		evt := event.NewBaseEvent(
 			domainEvent.EventName(),
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// memoryShipmentRepo keeps shipments in memory
+type memoryShipmentRepo struct {
+	repo.IShipmentRepo
+
+	shipments []*model.Shipment
+}
+
+func (r *memoryShipmentRepo) GetByID(_ context.Context, _ repo.Transaction, id string) (*model.Shipment, error) {
+	for _, shipment := range r.shipments {
+		if shipment.ID == id {
+			clone := *shipment
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryShipmentRepo) ListByOrderID(_ context.Context, _ repo.Transaction, orderID string) ([]*model.Shipment, error) {
+	var shipments []*model.Shipment
+	for _, shipment := range r.shipments {
+		if shipment.OrderID == orderID {
+			shipments = append(shipments, shipment)
+		}
+	}
+	return shipments, nil
+}
+
+func TestOrderServiceCancel(t *testing.T) {
+	allocated := []model.StockAllocation{{WarehouseID: "w1", Quantity: 2}}
+
+	tests := []struct {
+		name        string
+		status      model.OrderStatus
+		shipment    model.ShipmentStatus // empty for orders without shipments
+		wantErr     error
+		wantRestock []model.StockChange
+	}{
+		{
+			name:   "confirmed order returns its stock",
+			status: model.OrderStatusConfirmed,
+			wantRestock: []model.StockChange{
+				{WarehouseID: "w1", Quantity: 2, Reason: model.StockMovementReasonOrder, ReferenceID: "o1"},
+			},
+		},
+		{
+			name:     "packed shipment that did not leave yet",
+			status:   model.OrderStatusConfirmed,
+			shipment: model.ShipmentStatusPending,
+			wantRestock: []model.StockChange{
+				{WarehouseID: "w1", Quantity: 2, Reason: model.StockMovementReasonOrder, ReferenceID: "o1"},
+			},
+		},
+		{
+			name:     "shipped before the order status caught up",
+			status:   model.OrderStatusConfirmed,
+			shipment: model.ShipmentStatusShipped,
+			wantErr:  model.ErrOrderCannotCancel,
+		},
+		{
+			name:     "partially shipped order",
+			status:   model.OrderStatusPartiallyShipped,
+			shipment: model.ShipmentStatusShipped,
+			wantErr:  model.ErrOrderCannotCancel,
+		},
+		{
+			name:     "shipped order",
+			status:   model.OrderStatusShipped,
+			shipment: model.ShipmentStatusShipped,
+			wantErr:  model.ErrOrderCannotCancel,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := newMemoryOrderRepo(&model.Order{
+				ID: "o1", UserID: "u1", Status: tt.status, Total: 20, Version: 1,
+				Items: []model.OrderItem{{ID: "item-1", ProductID: "p1", Quantity: 2, Price: 10, Allocations: allocated}},
+			})
+			shipments := &memoryShipmentRepo{}
+			if tt.shipment != "" {
+				shipments.shipments = append(shipments.shipments, &model.Shipment{ID: "s1", OrderID: "o1", Status: tt.shipment})
+			}
+			products := &restockRecorder{}
+			svc := NewOrderService(orders, nil, nil, nil, shipments, products, nil, nil, nil)
+
+			err := svc.Cancel(context.Background(), "o1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.status, orders.orders["o1"].Status)
+				assert.Empty(t, products.changes)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, model.OrderStatusCancelled, orders.orders["o1"].Status)
+			assert.Equal(t, tt.wantRestock, products.changes)
+		})
+	}
+}
+
+func TestShipmentServiceShipCancelledOrder(t *testing.T) {
+	orders := newMemoryOrderRepo(&model.Order{ID: "o1", UserID: "u1", Status: model.OrderStatusCancelled, Version: 2})
+	shipments := &memoryShipmentRepo{shipments: []*model.Shipment{
+		{ID: "s1", OrderID: "o1", Status: model.ShipmentStatusPending, TrackingNumber: "TRK1", Version: 1},
+	}}
+	svc := NewShipmentService(shipments, orders, nil, nil)
+
+	_, err := svc.Ship(context.Background(), "s1", "")
+	assert.ErrorIs(t, err, model.ErrOrderNotShippable)
+	assert.Equal(t, model.ShipmentStatusPending, shipments.shipments[0].Status)
+}
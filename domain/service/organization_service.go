@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+// IOrganizationService defines the interface for organization service operations
+type IOrganizationService interface {
+	Create(ctx context.Context, name, adminID string) (*model.Organization, error)
+	Get(ctx context.Context, id string) (*model.Organization, error)
+	List(ctx context.Context, offset, limit int) ([]*model.Organization, int64, error)
+	ListByUserID(ctx context.Context, userID string) ([]*model.Organization, error)
+	Rename(ctx context.Context, id, name string, expectedVersion int) (*model.Organization, error)
+	Delete(ctx context.Context, id string) error
+	AddMember(ctx context.Context, id, userID string, role model.OrganizationRole, expectedVersion int) (*model.Organization, error)
+	ChangeMemberRole(ctx context.Context, id, userID string, role model.OrganizationRole, expectedVersion int) (*model.Organization, error)
+	RemoveMember(ctx context.Context, id, userID string, expectedVersion int) (*model.Organization, error)
+	AddAddress(ctx context.Context, id string, address model.OrganizationAddress, expectedVersion int) (*model.Organization, error)
+	RemoveAddress(ctx context.Context, id, addressID string, expectedVersion int) (*model.Organization, error)
+	SetApprovalThreshold(ctx context.Context, id string, threshold *float64, expectedVersion int) (*model.Organization, error)
+	SetSpendingLimit(ctx context.Context, id, userID string, limit *float64, expectedVersion int) (*model.Organization, error)
+	ListOrders(ctx context.Context, id string, offset, limit int) ([]*model.Order, int64, error)
+	NotifyMembers(ctx context.Context, id, exceptUserID, subject, body string, roles ...model.OrganizationRole) error
+}
+
+// OrganizationService implements IOrganizationService
+type OrganizationService struct {
+	repo      repo.IOrganizationRepo
+	userRepo  repo.IUserRepo
+	orderRepo repo.IOrderRepo
+	notifier  repo.INotifier
+	eventBus  event.EventBus
+}
+
+// NewOrganizationService creates a new organization service. Without a notifier, members are not notified.
+func NewOrganizationService(repo repo.IOrganizationRepo, userRepo repo.IUserRepo, orderRepo repo.IOrderRepo, notifier repo.INotifier, eventBus event.EventBus) *OrganizationService {
+	if eventBus == nil {
+		eventBus = event.NewNoopEventBus()
+	}
+	return &OrganizationService{
+		repo:      repo,
+		userRepo:  userRepo,
+		orderRepo: orderRepo,
+		notifier:  notifier,
+		eventBus:  eventBus,
+	}
+}
+
+// Create creates an organization with adminID as its first admin
+func (s *OrganizationService) Create(ctx context.Context, name, adminID string) (*model.Organization, error) {
+	org, err := model.NewOrganization(name, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUser(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(ctx, nil, org)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishEvents(ctx, org)
+
+	return created, nil
+}
+
+// Get retrieves an organization by ID
+func (s *OrganizationService) Get(ctx context.Context, id string) (*model.Organization, error) {
+	return s.repo.GetByID(ctx, nil, id)
+}
+
+// List retrieves organizations with pagination
+func (s *OrganizationService) List(ctx context.Context, offset, limit int) ([]*model.Organization, int64, error) {
+	return s.repo.List(ctx, nil, offset, limit)
+}
+
+// ListByUserID retrieves the organizations a user is a member of
+func (s *OrganizationService) ListByUserID(ctx context.Context, userID string) ([]*model.Organization, error) {
+	return s.repo.ListByUserID(ctx, nil, userID)
+}
+
+// Rename changes the name of an organization.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *OrganizationService) Rename(ctx context.Context, id, name string, expectedVersion int) (*model.Organization, error) {
+	return s.update(ctx, id, expectedVersion, func(org *model.Organization) error {
+		return org.Rename(name)
+	})
+}
+
+// Delete deletes an organization. Its orders are kept.
+func (s *OrganizationService) Delete(ctx context.Context, id string) error {
+	org, err := s.load(ctx, id, 0)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, nil, id); err != nil {
+		return err
+	}
+
+	org.MarkDeleted()
+	s.publishEvents(ctx, org)
+
+	return nil
+}
+
+// AddMember adds a user of the same tenant to an organization.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *OrganizationService) AddMember(ctx context.Context, id, userID string, role model.OrganizationRole, expectedVersion int) (*model.Organization, error) {
+	if err := s.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.update(ctx, id, expectedVersion, func(org *model.Organization) error {
+		return org.AddMember(userID, role)
+	})
+}
+
+// ChangeMemberRole changes the role of a member of an organization.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *OrganizationService) ChangeMemberRole(ctx context.Context, id, userID string, role model.OrganizationRole, expectedVersion int) (*model.Organization, error) {
+	return s.update(ctx, id, expectedVersion, func(org *model.Organization) error {
+		return org.ChangeMemberRole(userID, role)
+	})
+}
+
+// RemoveMember removes a member from an organization.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *OrganizationService) RemoveMember(ctx context.Context, id, userID string, expectedVersion int) (*model.Organization, error) {
+	return s.update(ctx, id, expectedVersion, func(org *model.Organization) error {
+		return org.RemoveMember(userID)
+	})
+}
+
+// AddAddress adds an address shared by the members of an organization.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *OrganizationService) AddAddress(ctx context.Context, id string, address model.OrganizationAddress, expectedVersion int) (*model.Organization, error) {
+	return s.update(ctx, id, expectedVersion, func(org *model.Organization) error {
+		_, err := org.AddAddress(address)
+		return err
+	})
+}
+
+// RemoveAddress removes a shared address from an organization.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *OrganizationService) RemoveAddress(ctx context.Context, id, addressID string, expectedVersion int) (*model.Organization, error) {
+	return s.update(ctx, id, expectedVersion, func(org *model.Organization) error {
+		return org.RemoveAddress(addressID)
+	})
+}
+
+// SetApprovalThreshold sets the order total above which the orders of an organization need approval,
+// nil disables approvals. A non-zero expectedVersion must match the stored version, otherwise
+// model.ErrVersionConflict is returned.
+func (s *OrganizationService) SetApprovalThreshold(ctx context.Context, id string, threshold *float64, expectedVersion int) (*model.Organization, error) {
+	return s.update(ctx, id, expectedVersion, func(org *model.Organization) error {
+		return org.SetApprovalThreshold(threshold)
+	})
+}
+
+// SetSpendingLimit sets the order total above which the orders of a member need approval, nil falls
+// back to the approval threshold of the organization. A non-zero expectedVersion must match the
+// stored version, otherwise model.ErrVersionConflict is returned.
+func (s *OrganizationService) SetSpendingLimit(ctx context.Context, id, userID string, limit *float64, expectedVersion int) (*model.Organization, error) {
+	return s.update(ctx, id, expectedVersion, func(org *model.Organization) error {
+		return org.SetSpendingLimit(userID, limit)
+	})
+}
+
+// NotifyMembers notifies the members of an organization with one of the roles, except exceptUserID.
+// Failures to notify a member are logged rather than returned.
+func (s *OrganizationService) NotifyMembers(ctx context.Context, id, exceptUserID, subject, body string, roles ...model.OrganizationRole) error {
+	if s.notifier == nil {
+		return nil
+	}
+
+	org, err := s.load(ctx, id, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range org.Members {
+		if member.UserID == exceptUserID || !org.HasRole(member.UserID, roles...) {
+			continue
+		}
+
+		user, err := s.userRepo.GetByID(ctx, nil, member.UserID)
+		if err != nil || user == nil {
+			log.SugaredLogger.Errorf("Failed to load member %s of organization %s: %v", member.UserID, id, err)
+			continue
+		}
+
+		err = s.notifier.Notify(ctx, &model.Notification{
+			To:      user.Email,
+			Subject: subject,
+			Body:    fmt.Sprintf("Hi %s,\n\n%s", user.Name, body),
+		})
+		if err != nil {
+			log.SugaredLogger.Errorf("Failed to notify member %s of organization %s: %v", member.UserID, id, err)
+		}
+	}
+
+	return nil
+}
+
+// ListOrders retrieves the orders placed on behalf of an organization with pagination
+func (s *OrganizationService) ListOrders(ctx context.Context, id string, offset, limit int) ([]*model.Order, int64, error) {
+	if _, err := s.load(ctx, id, 0); err != nil {
+		return nil, 0, err
+	}
+	return s.orderRepo.GetByOrganizationID(ctx, nil, id, offset, limit)
+}
+
+// update applies a change to an organization at the expected version and saves it
+func (s *OrganizationService) update(ctx context.Context, id string, expectedVersion int, change func(*model.Organization) error) (*model.Organization, error) {
+	org, err := s.load(ctx, id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := change(org); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, nil, org); err != nil {
+		return nil, err
+	}
+
+	s.publishEvents(ctx, org)
+
+	return org, nil
+}
+
+// load retrieves an organization for an update at the expected version
+func (s *OrganizationService) load(ctx context.Context, id string, expectedVersion int) (*model.Organization, error) {
+	org, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, model.ErrOrganizationNotFound
+	}
+
+	if err := model.CheckVersion(org.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// checkUser checks that a user exists in the tenant of ctx
+func (s *OrganizationService) checkUser(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, nil, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
+
+// publishEvents publishes all pending domain events from the organization
+func (s *OrganizationService) publishEvents(ctx context.Context, org *model.Organization) {
+	ctx = model.EnsureTenant(ctx, org.TenantID)
+	for _, domainEvent := range org.Events() {
+		evt := event.NewBaseEvent(
+			domainEvent.EventName(),
+			org.ID,
+			domainEvent,
+		)
+		if err := s.eventBus.Publish(ctx, evt); err != nil {
+			log.SugaredLogger.Errorf("Failed to publish event %s: %v", domainEvent.EventName(), err)
+		}
+	}
+}
+
+// OrganizationEventHandler notifies the approvers of an organization when one of its orders awaits
+// approval, and its admins when the approval is escalated
+type OrganizationEventHandler struct {
+	organizationService IOrganizationService
+}
+
+// NewOrganizationEventHandler creates a new organization event handler
+func NewOrganizationEventHandler(organizationService IOrganizationService) *OrganizationEventHandler {
+	return &OrganizationEventHandler{organizationService: organizationService}
+}
+
+// HandleEvent notifies the members deciding on an order awaiting approval. The buyer is not notified.
+func (h *OrganizationEventHandler) HandleEvent(ctx context.Context, evt event.Event) error {
+	baseEvent, ok := evt.(event.BaseEvent)
+	if !ok {
+		return nil
+	}
+
+	switch e := baseEvent.Payload.(type) {
+	case model.OrderApprovalRequestedEvent:
+		return h.organizationService.NotifyMembers(ctx, e.OrganizationID, e.UserID,
+			"An order awaits your approval",
+			fmt.Sprintf("Order %s for %.2f awaits your approval.", e.OrderID, e.Total),
+			model.OrganizationRoleApprover, model.OrganizationRoleAdmin)
+	case model.OrderApprovalEscalatedEvent:
+		return h.organizationService.NotifyMembers(ctx, e.OrganizationID, e.UserID,
+			"An order approval was escalated",
+			fmt.Sprintf("Order %s for %.2f has been awaiting approval for too long and was escalated to you.", e.OrderID, e.Total),
+			model.OrganizationRoleAdmin)
+	}
+
+	return nil
+}
+
+// InterestedIn returns true for orders awaiting approval
+func (h *OrganizationEventHandler) InterestedIn(eventName string) bool {
+	return eventName == "order.approval_requested" || eventName == "order.approval_escalated"
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// memoryOrganizationRepo keeps organizations in memory and applies updates with a version check
+type memoryOrganizationRepo struct {
+	repo.IOrganizationRepo
+
+	organizations map[string]*model.Organization
+}
+
+func newMemoryOrganizationRepo(organizations ...*model.Organization) *memoryOrganizationRepo {
+	r := &memoryOrganizationRepo{organizations: map[string]*model.Organization{}}
+	for _, org := range organizations {
+		org.Events()
+		r.organizations[org.ID] = org
+	}
+	return r
+}
+
+func (r *memoryOrganizationRepo) GetByID(_ context.Context, _ repo.Transaction, id string) (*model.Organization, error) {
+	org, ok := r.organizations[id]
+	if !ok {
+		return nil, nil
+	}
+	clone := *org
+	clone.Members = append([]model.OrganizationMember(nil), org.Members...)
+	return &clone, nil
+}
+
+func (r *memoryOrganizationRepo) Update(_ context.Context, _ repo.Transaction, org *model.Organization) error {
+	stored, ok := r.organizations[org.ID]
+	if !ok || stored.Version != org.Version {
+		return model.ErrVersionConflict
+	}
+	org.Version++
+	clone := *org
+	clone.Members = append([]model.OrganizationMember(nil), org.Members...)
+	r.organizations[org.ID] = &clone
+	return nil
+}
+
+// organizationTestSetup returns an organization with an admin, two buyers, one of them with a spending
+// limit of 200, and an approver. Orders above 1000 need approval. Every member is a verified user.
+func organizationTestSetup(t *testing.T) (*memoryOrganizationRepo, *memoryUserRepo) {
+	t.Helper()
+	threshold := 1000.0
+	limit := 200.0
+	org := &model.Organization{ID: "org-1", Name: "Acme", ApprovalThreshold: &threshold, Version: 1}
+	verified := time.Now()
+	var users []*model.User
+	for _, member := range []struct {
+		id   string
+		role model.OrganizationRole
+	}{
+		{"admin-1", model.OrganizationRoleAdmin},
+		{"buyer-1", model.OrganizationRoleBuyer},
+		{"buyer-2", model.OrganizationRoleBuyer},
+		{"approver-1", model.OrganizationRoleApprover},
+	} {
+		require.NoError(t, org.AddMember(member.id, member.role))
+		users = append(users, &model.User{ID: member.id, Email: member.id + "@example.com", EmailVerifiedAt: &verified, Version: 1})
+	}
+	org.Member("buyer-2").SpendingLimit = &limit
+	users = append(users, &model.User{ID: "stranger", Email: "stranger@example.com", EmailVerifiedAt: &verified, Version: 1})
+	return newMemoryOrganizationRepo(org), newMemoryUserRepo(users...)
+}
+
+func TestOrganizationServiceMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(svc *OrganizationService) (*model.Organization, error)
+		wantErr  error
+		wantRole model.OrganizationRole // role of stranger afterwards
+	}{
+		{
+			name: "adds a user of the tenant",
+			change: func(svc *OrganizationService) (*model.Organization, error) {
+				return svc.AddMember(context.Background(), "org-1", "stranger", model.OrganizationRoleBuyer, 1)
+			},
+			wantRole: model.OrganizationRoleBuyer,
+		},
+		{
+			name: "rejects an unknown user",
+			change: func(svc *OrganizationService) (*model.Organization, error) {
+				return svc.AddMember(context.Background(), "org-1", "ghost", model.OrganizationRoleBuyer, 1)
+			},
+			wantErr: model.ErrUserNotFound,
+		},
+		{
+			name: "rejects a stale version",
+			change: func(svc *OrganizationService) (*model.Organization, error) {
+				return svc.AddMember(context.Background(), "org-1", "stranger", model.OrganizationRoleBuyer, 2)
+			},
+			wantErr: model.ErrVersionConflict,
+		},
+		{
+			name: "rejects an unknown organization",
+			change: func(svc *OrganizationService) (*model.Organization, error) {
+				return svc.AddMember(context.Background(), "org-2", "stranger", model.OrganizationRoleBuyer, 0)
+			},
+			wantErr: model.ErrOrganizationNotFound,
+		},
+		{
+			name: "keeps the last admin",
+			change: func(svc *OrganizationService) (*model.Organization, error) {
+				return svc.RemoveMember(context.Background(), "org-1", "admin-1", 0)
+			},
+			wantErr: model.ErrOrganizationLastAdmin,
+		},
+		{
+			name: "rejects a negative spending limit",
+			change: func(svc *OrganizationService) (*model.Organization, error) {
+				limit := -5.0
+				return svc.SetSpendingLimit(context.Background(), "org-1", "buyer-1", &limit, 0)
+			},
+			wantErr: model.ErrOrganizationLimitInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			organizations, users := organizationTestSetup(t)
+			svc := NewOrganizationService(organizations, users, nil, nil, nil)
+			before := *organizations.organizations["org-1"]
+
+			org, err := tt.change(svc)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, before.Version, organizations.organizations["org-1"].Version)
+				assert.Equal(t, before.Members, organizations.organizations["org-1"].Members)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, before.Version+1, org.Version)
+			stored := organizations.organizations["org-1"]
+			require.NotNil(t, stored.Member("stranger"))
+			assert.Equal(t, tt.wantRole, stored.Member("stranger").Role)
+		})
+	}
+}
+
+func TestOrderServiceCreateForOrganization(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		price        float64
+		wantErr      error
+		wantApproval bool
+	}{
+		{name: "buyer below the threshold", userID: "buyer-1", price: 900},
+		{name: "admin above the threshold", userID: "admin-1", price: 1500, wantApproval: true},
+		{name: "buyer within the spending limit", userID: "buyer-2", price: 200},
+		{name: "buyer above the spending limit", userID: "buyer-2", price: 300, wantApproval: true},
+		{name: "approver cannot order", userID: "approver-1", price: 10, wantErr: model.ErrOrganizationOrderForbidden},
+		{name: "non-member cannot order", userID: "stranger", price: 10, wantErr: model.ErrOrganizationOrderForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			organizations, users := organizationTestSetup(t)
+			orders := newMemoryOrderRepo()
+			svc := NewOrderService(orders, users, organizations, nil, nil, nil, nil, nil, nil)
+
+			order, err := svc.Create(context.Background(), tt.userID, "org-1",
+				[]model.OrderItem{{ProductID: "p1", Quantity: 1, Price: tt.price}}, nil, "", "")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, orders.orders)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "org-1", order.OrganizationID)
+			assert.Equal(t, tt.wantApproval, order.IsAwaitingApproval())
+			require.Contains(t, orders.orders, order.ID)
+		})
+	}
+
+	t.Run("unknown organization", func(t *testing.T) {
+		organizations, users := organizationTestSetup(t)
+		svc := NewOrderService(newMemoryOrderRepo(), users, organizations, nil, nil, nil, nil, nil, nil)
+		_, err := svc.Create(context.Background(), "buyer-1", "org-2",
+			[]model.OrderItem{{ProductID: "p1", Quantity: 1, Price: 10}}, nil, "", "")
+		assert.ErrorIs(t, err, model.ErrOrganizationNotFound)
+	})
+}
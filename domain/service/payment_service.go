@@ -0,0 +1,456 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+// IPaymentService defines the interface for payment service operations
+type IPaymentService interface {
+	Authorize(ctx context.Context, orderID string) (*model.Payment, error)
+	Capture(ctx context.Context, id string) (*model.Payment, error)
+	Void(ctx context.Context, id string) (*model.Payment, error)
+	Refund(ctx context.Context, id string, amount float64) (*model.Payment, error)
+	Get(ctx context.Context, id string) (*model.Payment, error)
+	ListByOrderID(ctx context.Context, orderID string) ([]*model.Payment, error)
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+	SettleCancelledOrder(ctx context.Context, orderID string) error
+}
+
+// PaymentService implements IPaymentService
+type PaymentService struct {
+	repo         repo.IPaymentRepo
+	orderRepo    repo.IOrderRepo
+	orderService IOrderService
+	gateway      repo.IPaymentGateway
+	eventBus     event.EventBus
+}
+
+// NewPaymentService creates a new payment service.
+// Captured payments confirm their order through the order service, which commits its held stock.
+func NewPaymentService(repo repo.IPaymentRepo, orderRepo repo.IOrderRepo, orderService IOrderService, gateway repo.IPaymentGateway, eventBus event.EventBus) *PaymentService {
+	if eventBus == nil {
+		eventBus = event.NewNoopEventBus()
+	}
+	return &PaymentService{
+		repo:         repo,
+		orderRepo:    orderRepo,
+		orderService: orderService,
+		gateway:      gateway,
+		eventBus:     eventBus,
+	}
+}
+
+// Authorize creates a payment for the order total and authorizes it with the gateway.
+// A declined authorization is stored as a failed payment and returns model.ErrPaymentDeclined.
+func (s *PaymentService) Authorize(ctx context.Context, orderID string) (*model.Payment, error) {
+	order, err := s.orderRepo.GetByID(ctx, nil, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, model.ErrOrderNotFound
+	}
+	if order.Status != model.OrderStatusPending {
+		return nil, model.ErrPaymentOrderNotPayable
+	}
+
+	existing, err := s.repo.ListByOrderID(ctx, nil, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range existing {
+		if p.IsActive() {
+			return nil, model.ErrPaymentAlreadyExists
+		}
+	}
+
+	payment, err := model.NewPayment(order.ID, order.Total)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.Create(ctx, nil, payment); err != nil {
+		return nil, err
+	}
+
+	gatewayRef, authErr := s.gateway.Authorize(ctx, payment.ID, payment.Amount)
+	if authErr != nil {
+		if err := payment.Fail(authErr.Error()); err != nil {
+			return nil, err
+		}
+	} else if err := payment.Authorize(gatewayRef); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, nil, payment); err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, payment.ID, payment.Events())
+
+	if authErr != nil {
+		return payment, authErr
+	}
+	return payment, nil
+}
+
+// Capture captures an authorized payment and confirms its order
+func (s *PaymentService) Capture(ctx context.Context, id string) (*model.Payment, error) {
+	payment, err := s.getPayment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.transition(ctx, payment, "capture", payment.Capture, func(idempotencyKey string) error {
+		return s.gateway.Capture(ctx, payment.GatewayRef, payment.Amount, idempotencyKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, payment.ID, payment.Events())
+
+	s.confirmOrder(ctx, payment)
+
+	return payment, nil
+}
+
+// Void releases an authorized payment
+func (s *PaymentService) Void(ctx context.Context, id string) (*model.Payment, error) {
+	payment, err := s.getPayment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.void(ctx, payment); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// Refund refunds part of a captured payment, or the whole remaining amount when amount is zero
+func (s *PaymentService) Refund(ctx context.Context, id string, amount float64) (*model.Payment, error) {
+	payment, err := s.getPayment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.refund(ctx, payment, amount); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// SettleCancelledOrder gives the customer's funds back once their order is cancelled: authorized
+// payments are voided and captured ones refunded in full. Every payment is attempted, the first
+// error is returned.
+func (s *PaymentService) SettleCancelledOrder(ctx context.Context, orderID string) error {
+	payments, err := s.repo.ListByOrderID(ctx, nil, orderID)
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, payment := range payments {
+		if err := s.settle(ctx, payment); err != nil {
+			log.SugaredLogger.Errorf("Failed to settle payment %s of cancelled order %s: %v", payment.ID, orderID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// Get retrieves a payment by ID
+func (s *PaymentService) Get(ctx context.Context, id string) (*model.Payment, error) {
+	payment, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil || payment == nil {
+		return nil, err
+	}
+	if ok, err := orderInTenant(ctx, s.orderRepo, payment.OrderID); err != nil || !ok {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// ListByOrderID retrieves all payments for an order
+func (s *PaymentService) ListByOrderID(ctx context.Context, orderID string) ([]*model.Payment, error) {
+	if ok, err := orderInTenant(ctx, s.orderRepo, orderID); err != nil || !ok {
+		return nil, err
+	}
+	return s.repo.ListByOrderID(ctx, nil, orderID)
+}
+
+// HandleWebhook applies a signed gateway notification to the payment it refers to.
+// Notifications are idempotent: an event ID is applied once, and a payment already in the notified
+// state is left unchanged.
+func (s *PaymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	notification, err := s.gateway.ParseWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+
+	// The gateway names no tenant, the payment's order is looked up in every tenant
+	ctx = model.ContextWithoutTenant(ctx)
+
+	payment, err := s.repo.GetByGatewayRef(ctx, nil, notification.GatewayRef)
+	if err != nil {
+		return err
+	}
+	if payment == nil {
+		return model.ErrPaymentNotFound
+	}
+
+	// The events and the order confirmation stay in the tenant of the payment's order
+	order, err := s.orderRepo.GetByID(ctx, nil, payment.OrderID)
+	if err != nil {
+		return err
+	}
+	if order != nil {
+		ctx = model.EnsureTenant(ctx, order.TenantID)
+	}
+
+	switch notification.Status {
+	case model.PaymentStatusAuthorized:
+		if payment.Status != model.PaymentStatusPending {
+			return nil
+		}
+		err = payment.Authorize(notification.GatewayRef)
+	case model.PaymentStatusCaptured:
+		if payment.Status != model.PaymentStatusAuthorized {
+			return nil
+		}
+		err = payment.Capture()
+	case model.PaymentStatusVoided:
+		if payment.Status == model.PaymentStatusVoided {
+			return nil
+		}
+		err = payment.Void()
+	case model.PaymentStatusRefunded:
+		// Refund notifications carry the cumulative refunded amount
+		delta := notification.Amount - payment.RefundedAmount
+		if notification.Amount == 0 {
+			delta = payment.RefundableAmount()
+		}
+		if delta <= 0 {
+			return nil
+		}
+		err = payment.Refund(delta)
+	case model.PaymentStatusFailed:
+		if payment.Status == model.PaymentStatusFailed {
+			return nil
+		}
+		err = payment.Fail(notification.Reason)
+	default:
+		return model.ErrPaymentWebhookPayloadInvalid
+	}
+	if err != nil {
+		return err
+	}
+	// The notification tells the outcome of a failed call, its key must not be reused by a later operation
+	payment.PendingOperation = ""
+
+	if notification.EventID == "" {
+		err = s.repo.Update(ctx, nil, payment)
+	} else {
+		var applied bool
+		applied, err = s.repo.ApplyWebhookEvent(ctx, nil, notification.EventID, payment)
+		if err == nil && !applied {
+			return nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, payment.ID, payment.Events())
+
+	if payment.Status == model.PaymentStatusCaptured {
+		s.confirmOrder(ctx, payment)
+	}
+
+	return nil
+}
+
+// void releases an authorized payment at the gateway and publishes its events
+func (s *PaymentService) void(ctx context.Context, payment *model.Payment) error {
+	err := s.transition(ctx, payment, "void", payment.Void, func(idempotencyKey string) error {
+		return s.gateway.Void(ctx, payment.GatewayRef, idempotencyKey)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, payment.ID, payment.Events())
+
+	return nil
+}
+
+// refund refunds part of a captured payment at the gateway, or the whole remaining amount when
+// amount is zero, and publishes its events
+func (s *PaymentService) refund(ctx context.Context, payment *model.Payment, amount float64) error {
+	if amount == 0 {
+		amount = payment.RefundableAmount()
+	}
+
+	refund := func() error { return payment.Refund(amount) }
+	err := s.transition(ctx, payment, "refund", refund, func(idempotencyKey string) error {
+		return s.gateway.Refund(ctx, payment.GatewayRef, amount, idempotencyKey)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, payment.ID, payment.Events())
+
+	return nil
+}
+
+// settle voids an authorized payment and refunds the remaining amount of a captured one.
+// Payments in any other status hold no funds and are left unchanged.
+func (s *PaymentService) settle(ctx context.Context, payment *model.Payment) error {
+	switch payment.Status {
+	case model.PaymentStatusAuthorized:
+		return s.void(ctx, payment)
+	case model.PaymentStatusCaptured:
+		return s.refund(ctx, payment, 0)
+	}
+	return nil
+}
+
+// transition applies change to the payment and persists it before the gateway call, so that of two
+// concurrent requests only the one that saved its version moves money. The gateway receives an
+// idempotency key made of the payment ID, the operation and a reference. When the call fails the
+// previous state is restored with the reference kept as the pending operation, so that a retry of
+// the operation reuses the key and does not move money twice when the failed call went through.
+func (s *PaymentService) transition(ctx context.Context, payment *model.Payment, operation string, change func() error, call func(idempotencyKey string) error) error {
+	previous := *payment
+
+	pending := payment.PendingOperation
+	if !strings.HasPrefix(pending, operation+":") {
+		pending = operation + ":" + uuid.New().String()
+	}
+
+	if err := change(); err != nil {
+		return err
+	}
+	payment.PendingOperation = ""
+	if err := s.repo.Update(ctx, nil, payment); err != nil {
+		return err
+	}
+
+	if err := call(payment.ID + ":" + pending); err != nil {
+		previous.Version = payment.Version
+		previous.PendingOperation = pending
+		if restoreErr := s.repo.Update(ctx, nil, &previous); restoreErr != nil {
+			log.SugaredLogger.Errorf("Failed to restore payment %s after gateway error: %v", payment.ID, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// getPayment loads a payment or returns model.ErrPaymentNotFound
+func (s *PaymentService) getPayment(ctx context.Context, id string) (*model.Payment, error) {
+	payment, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, model.ErrPaymentNotFound
+	}
+	ok, err := orderInTenant(ctx, s.orderRepo, payment.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, model.ErrPaymentNotFound
+	}
+	return payment, nil
+}
+
+// confirmOrder confirms a pending order after its payment was captured. A capture that arrives for
+// a cancelled order is refunded, as the customer would otherwise pay for an order that no longer exists.
+// The capture already happened at the gateway, so failures are logged rather than returned.
+func (s *PaymentService) confirmOrder(ctx context.Context, payment *model.Payment) {
+	order, err := s.orderRepo.GetByID(ctx, nil, payment.OrderID)
+	if err != nil || order == nil {
+		log.SugaredLogger.Errorf("Failed to load order %s for payment confirmation: %v", payment.OrderID, err)
+		return
+	}
+
+	switch order.Status {
+	case model.OrderStatusPending:
+	case model.OrderStatusCancelled:
+		if err := s.refund(ctx, payment, 0); err != nil {
+			log.SugaredLogger.Errorf("Failed to refund payment %s captured for cancelled order %s: %v", payment.ID, order.ID, err)
+		}
+		return
+	case model.OrderStatusConfirmed:
+		return
+	default:
+		log.SugaredLogger.Warnf("Captured payment could not confirm order %s in status %s", order.ID, order.Status)
+		return
+	}
+
+	if _, err := s.orderService.UpdateStatus(ctx, order.ID, model.OrderStatusConfirmed, order.Version); err != nil {
+		log.SugaredLogger.Errorf("Failed to confirm order %s after payment capture: %v", order.ID, err)
+	}
+}
+
+// publishEvents publishes domain events for the given aggregate
+func (s *PaymentService) publishEvents(ctx context.Context, aggregateID string, events []model.DomainEvent) {
+	for _, domainEvent := range events {
+		evt := event.NewBaseEvent(
+			domainEvent.EventName(),
+			aggregateID,
+			domainEvent,
+		)
+		if err := s.eventBus.Publish(ctx, evt); err != nil {
+			log.SugaredLogger.Errorf("Failed to publish event %s: %v", domainEvent.EventName(), err)
+		}
+	}
+}
+
+// PaymentEventHandler voids or refunds the payments of cancelled orders
+type PaymentEventHandler struct {
+	paymentService IPaymentService
+}
+
+// NewPaymentEventHandler creates a new payment event handler
+func NewPaymentEventHandler(paymentService IPaymentService) *PaymentEventHandler {
+	return &PaymentEventHandler{paymentService: paymentService}
+}
+
+// HandleEvent settles the payments of the cancelled order
+func (h *PaymentEventHandler) HandleEvent(ctx context.Context, evt event.Event) error {
+	baseEvent, ok := evt.(event.BaseEvent)
+	if !ok {
+		return nil
+	}
+
+	payload, ok := baseEvent.Payload.(model.OrderCancelledEvent)
+	if !ok {
+		return nil
+	}
+	return h.paymentService.SettleCancelledOrder(ctx, payload.OrderID)
+}
+
+// InterestedIn returns true for cancelled orders
+func (h *PaymentEventHandler) InterestedIn(eventName string) bool {
+	return eventName == "order.cancelled"
+}
@@ -0,0 +1,452 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// memoryOrderRepo keeps orders in memory and applies status updates with a version check
+type memoryOrderRepo struct {
+	repo.IOrderRepo
+
+	orders map[string]*model.Order
+}
+
+func newMemoryOrderRepo(orders ...*model.Order) *memoryOrderRepo {
+	r := &memoryOrderRepo{orders: map[string]*model.Order{}}
+	for _, order := range orders {
+		r.orders[order.ID] = order
+	}
+	return r
+}
+
+func (r *memoryOrderRepo) Create(_ context.Context, _ repo.Transaction, order *model.Order) (*model.Order, error) {
+	clone := *order
+	r.orders[order.ID] = &clone
+	return order, nil
+}
+
+func (r *memoryOrderRepo) GetByID(_ context.Context, _ repo.Transaction, id string) (*model.Order, error) {
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, nil
+	}
+	clone := *order
+	clone.Items = append([]model.OrderItem(nil), order.Items...)
+	return &clone, nil
+}
+
+func (r *memoryOrderRepo) Update(_ context.Context, _ repo.Transaction, order *model.Order) error {
+	stored, ok := r.orders[order.ID]
+	if !ok || stored.Version != order.Version {
+		return model.ErrVersionConflict
+	}
+	clone := *order
+	clone.Version++
+	r.orders[order.ID] = &clone
+	order.Version++
+	return nil
+}
+
+func (r *memoryOrderRepo) UpdateStatus(_ context.Context, _ repo.Transaction, id string, status model.OrderStatus, version int) error {
+	stored, ok := r.orders[id]
+	if !ok || stored.Version != version {
+		return model.ErrVersionConflict
+	}
+	stored.Status = status
+	stored.Version++
+	return nil
+}
+
+// memoryPaymentRepo keeps payments in memory and applies updates with a version check
+type memoryPaymentRepo struct {
+	repo.IPaymentRepo
+
+	payments map[string]*model.Payment
+
+	// concurrent makes ListByOrderID miss the payments, as an authorization racing with another one
+	concurrent bool
+}
+
+func newMemoryPaymentRepo(payments ...*model.Payment) *memoryPaymentRepo {
+	r := &memoryPaymentRepo{payments: map[string]*model.Payment{}}
+	for _, payment := range payments {
+		r.payments[payment.ID] = payment
+	}
+	return r
+}
+
+// Create stores a payment with the guard of the stores against two active payments of an order
+func (r *memoryPaymentRepo) Create(_ context.Context, _ repo.Transaction, payment *model.Payment) (*model.Payment, error) {
+	for _, stored := range r.payments {
+		if stored.OrderID == payment.OrderID && stored.IsActive() {
+			return nil, model.ErrPaymentAlreadyExists
+		}
+	}
+	clone := *payment
+	r.payments[payment.ID] = &clone
+	return payment, nil
+}
+
+func (r *memoryPaymentRepo) GetByID(_ context.Context, _ repo.Transaction, id string) (*model.Payment, error) {
+	payment, ok := r.payments[id]
+	if !ok {
+		return nil, nil
+	}
+	clone := *payment
+	return &clone, nil
+}
+
+func (r *memoryPaymentRepo) GetByGatewayRef(_ context.Context, _ repo.Transaction, gatewayRef string) (*model.Payment, error) {
+	for _, payment := range r.payments {
+		if payment.GatewayRef == gatewayRef {
+			clone := *payment
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryPaymentRepo) ListByOrderID(_ context.Context, _ repo.Transaction, orderID string) ([]*model.Payment, error) {
+	if r.concurrent {
+		return nil, nil
+	}
+	var payments []*model.Payment
+	for _, payment := range r.payments {
+		if payment.OrderID == orderID {
+			clone := *payment
+			payments = append(payments, &clone)
+		}
+	}
+	return payments, nil
+}
+
+func (r *memoryPaymentRepo) Update(_ context.Context, _ repo.Transaction, payment *model.Payment) error {
+	stored, ok := r.payments[payment.ID]
+	if !ok || stored.Version != payment.Version {
+		return model.ErrVersionConflict
+	}
+	payment.Version++
+	clone := *payment
+	r.payments[payment.ID] = &clone
+	return nil
+}
+
+// recordingGateway accepts every call and records the authorizations, idempotency keys, voids and
+// refunds it received
+type recordingGateway struct {
+	repo.IPaymentGateway
+
+	authorized []string
+	keys       []string
+	voided     []string
+	refunded   map[string]float64
+
+	// fail fails the next call with the error, as a gateway that timed out
+	fail error
+	// webhook is the notification every webhook payload decodes to
+	webhook *model.PaymentWebhookEvent
+}
+
+func newRecordingGateway() *recordingGateway {
+	return &recordingGateway{refunded: map[string]float64{}}
+}
+
+// call records the idempotency key and returns the error set to fail
+func (g *recordingGateway) call(idempotencyKey string) error {
+	g.keys = append(g.keys, idempotencyKey)
+	err := g.fail
+	g.fail = nil
+	return err
+}
+
+func (g *recordingGateway) Authorize(_ context.Context, paymentID string, _ float64) (string, error) {
+	g.authorized = append(g.authorized, paymentID)
+	return "ref-" + paymentID, nil
+}
+
+func (g *recordingGateway) ParseWebhook([]byte, string) (*model.PaymentWebhookEvent, error) {
+	return g.webhook, nil
+}
+
+func (g *recordingGateway) Capture(_ context.Context, _ string, _ float64, idempotencyKey string) error {
+	return g.call(idempotencyKey)
+}
+
+func (g *recordingGateway) Void(_ context.Context, gatewayRef, idempotencyKey string) error {
+	if err := g.call(idempotencyKey); err != nil {
+		return err
+	}
+	g.voided = append(g.voided, gatewayRef)
+	return nil
+}
+
+func (g *recordingGateway) Refund(_ context.Context, gatewayRef string, amount float64, idempotencyKey string) error {
+	if err := g.call(idempotencyKey); err != nil {
+		return err
+	}
+	g.refunded[gatewayRef] += amount
+	return nil
+}
+
+func testPayment(id, orderID string, status model.PaymentStatus, refunded float64) *model.Payment {
+	return &model.Payment{
+		ID:             id,
+		OrderID:        orderID,
+		Amount:         100,
+		RefundedAmount: refunded,
+		Status:         status,
+		GatewayRef:     "ref-" + id,
+		Version:        1,
+	}
+}
+
+func TestPaymentServiceSettleCancelledOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		payment      *model.Payment
+		wantStatus   model.PaymentStatus
+		wantVoided   bool
+		wantRefunded float64
+	}{
+		{
+			name:       "authorized payment is voided",
+			payment:    testPayment("p1", "o1", model.PaymentStatusAuthorized, 0),
+			wantStatus: model.PaymentStatusVoided,
+			wantVoided: true,
+		},
+		{
+			name:         "captured payment is refunded in full",
+			payment:      testPayment("p1", "o1", model.PaymentStatusCaptured, 0),
+			wantStatus:   model.PaymentStatusRefunded,
+			wantRefunded: 100,
+		},
+		{
+			name:         "partially refunded payment is refunded the rest",
+			payment:      testPayment("p1", "o1", model.PaymentStatusCaptured, 30),
+			wantStatus:   model.PaymentStatusRefunded,
+			wantRefunded: 70,
+		},
+		{
+			name:       "failed payment is left unchanged",
+			payment:    testPayment("p1", "o1", model.PaymentStatusFailed, 0),
+			wantStatus: model.PaymentStatusFailed,
+		},
+		{
+			name:       "voided payment is left unchanged",
+			payment:    testPayment("p1", "o1", model.PaymentStatusVoided, 0),
+			wantStatus: model.PaymentStatusVoided,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payments := newMemoryPaymentRepo(tt.payment)
+			gateway := newRecordingGateway()
+			svc := NewPaymentService(payments, newMemoryOrderRepo(), nil, gateway, nil)
+
+			require.NoError(t, svc.SettleCancelledOrder(context.Background(), "o1"))
+
+			assert.Equal(t, tt.wantStatus, payments.payments["p1"].Status)
+			assert.Equal(t, tt.wantVoided, len(gateway.voided) == 1)
+			assert.Equal(t, tt.wantRefunded, gateway.refunded["ref-p1"])
+		})
+	}
+}
+
+func TestOrderCancellationSettlesPayments(t *testing.T) {
+	order := &model.Order{ID: "o1", UserID: "u1", Status: model.OrderStatusPending, Total: 100, Version: 1}
+	orders := newMemoryOrderRepo(order)
+	payments := newMemoryPaymentRepo(
+		testPayment("authorized", "o1", model.PaymentStatusAuthorized, 0),
+		testPayment("other-order", "o2", model.PaymentStatusAuthorized, 0),
+	)
+	gateway := newRecordingGateway()
+
+	bus := event.NewInMemoryEventBus()
+	orderService := NewOrderService(orders, nil, nil, nil, nil, nil, nil, nil, bus)
+	paymentService := NewPaymentService(payments, orders, orderService, gateway, bus)
+	bus.Subscribe(NewPaymentEventHandler(paymentService))
+
+	require.NoError(t, orderService.Cancel(context.Background(), "o1"))
+
+	assert.Equal(t, model.PaymentStatusVoided, payments.payments["authorized"].Status)
+	assert.Equal(t, model.PaymentStatusAuthorized, payments.payments["other-order"].Status)
+	assert.Equal(t, []string{"ref-authorized"}, gateway.voided)
+}
+
+func TestPaymentServiceCapture(t *testing.T) {
+	tests := []struct {
+		name            string
+		orderStatus     model.OrderStatus
+		wantOrderStatus model.OrderStatus
+		wantPayment     model.PaymentStatus
+		wantRefunded    float64
+	}{
+		{
+			name:            "confirms a pending order",
+			orderStatus:     model.OrderStatusPending,
+			wantOrderStatus: model.OrderStatusConfirmed,
+			wantPayment:     model.PaymentStatusCaptured,
+		},
+		{
+			name:            "refunds a capture for a cancelled order",
+			orderStatus:     model.OrderStatusCancelled,
+			wantOrderStatus: model.OrderStatusCancelled,
+			wantPayment:     model.PaymentStatusRefunded,
+			wantRefunded:    100,
+		},
+		{
+			name:            "keeps a capture for a confirmed order",
+			orderStatus:     model.OrderStatusConfirmed,
+			wantOrderStatus: model.OrderStatusConfirmed,
+			wantPayment:     model.PaymentStatusCaptured,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := newMemoryOrderRepo(&model.Order{ID: "o1", UserID: "u1", Status: tt.orderStatus, Total: 100, Version: 1})
+			payments := newMemoryPaymentRepo(testPayment("p1", "o1", model.PaymentStatusAuthorized, 0))
+			gateway := newRecordingGateway()
+			orderService := NewOrderService(orders, nil, nil, nil, nil, nil, nil, nil, nil)
+			svc := NewPaymentService(payments, orders, orderService, gateway, nil)
+
+			_, err := svc.Capture(context.Background(), "p1")
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantOrderStatus, orders.orders["o1"].Status)
+			assert.Equal(t, tt.wantPayment, payments.payments["p1"].Status)
+			assert.Equal(t, tt.wantRefunded, gateway.refunded["ref-p1"])
+		})
+	}
+}
+
+func TestPaymentServiceRetryAfterGatewayError(t *testing.T) {
+	errTimeout := errors.New("gateway timeout")
+
+	tests := []struct {
+		name    string
+		status  model.PaymentStatus
+		operate func(svc *PaymentService) error
+	}{
+		{name: "capture", status: model.PaymentStatusAuthorized, operate: func(svc *PaymentService) error {
+			_, err := svc.Capture(context.Background(), "p1")
+			return err
+		}},
+		{name: "void", status: model.PaymentStatusAuthorized, operate: func(svc *PaymentService) error {
+			_, err := svc.Void(context.Background(), "p1")
+			return err
+		}},
+		{name: "refund", status: model.PaymentStatusCaptured, operate: func(svc *PaymentService) error {
+			_, err := svc.Refund(context.Background(), "p1", 40)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := newMemoryOrderRepo(&model.Order{ID: "o1", UserID: "u1", Status: model.OrderStatusConfirmed, Total: 100, Version: 1})
+			payments := newMemoryPaymentRepo(testPayment("p1", "o1", tt.status, 0))
+			gateway := newRecordingGateway()
+			svc := NewPaymentService(payments, orders, nil, gateway, nil)
+
+			// The call times out, the payment is restored with the reference of the operation
+			gateway.fail = errTimeout
+			assert.ErrorIs(t, tt.operate(svc), errTimeout)
+			assert.Equal(t, tt.status, payments.payments["p1"].Status)
+			assert.NotEmpty(t, payments.payments["p1"].PendingOperation)
+
+			// The retry sends the same key, so the gateway applies the operation once
+			require.NoError(t, tt.operate(svc))
+			require.Len(t, gateway.keys, 2)
+			assert.Equal(t, gateway.keys[0], gateway.keys[1])
+			assert.True(t, strings.HasPrefix(gateway.keys[0], "p1:"+tt.name+":"))
+			assert.Empty(t, payments.payments["p1"].PendingOperation)
+		})
+	}
+
+	t.Run("later operations get new keys", func(t *testing.T) {
+		orders := newMemoryOrderRepo(&model.Order{ID: "o1", UserID: "u1", Status: model.OrderStatusConfirmed, Total: 100, Version: 1})
+		payments := newMemoryPaymentRepo(testPayment("p1", "o1", model.PaymentStatusCaptured, 0))
+		gateway := newRecordingGateway()
+		svc := NewPaymentService(payments, orders, nil, gateway, nil)
+
+		_, err := svc.Refund(context.Background(), "p1", 40)
+		require.NoError(t, err)
+		_, err = svc.Refund(context.Background(), "p1", 40)
+		require.NoError(t, err)
+
+		require.Len(t, gateway.keys, 2)
+		assert.NotEqual(t, gateway.keys[0], gateway.keys[1])
+		assert.Equal(t, 80.0, gateway.refunded["ref-p1"])
+	})
+}
+
+func TestPaymentServiceHandleWebhookKeepsTenant(t *testing.T) {
+	orders := newMemoryOrderRepo(&model.Order{ID: "o1", TenantID: "acme", UserID: "u1", Status: model.OrderStatusConfirmed, Total: 100, Version: 1})
+	payments := newMemoryPaymentRepo(testPayment("p1", "o1", model.PaymentStatusAuthorized, 0))
+	payments.payments["p1"].PendingOperation = "capture:timed-out"
+	gateway := newRecordingGateway()
+	gateway.webhook = &model.PaymentWebhookEvent{GatewayRef: "ref-p1", Status: model.PaymentStatusCaptured}
+
+	recorder := &eventRecorder{}
+	bus := event.NewInMemoryEventBus()
+	bus.Subscribe(recorder)
+	svc := NewPaymentService(payments, orders, nil, gateway, bus)
+
+	// The gateway names no tenant, the events are published in the tenant of the order
+	require.NoError(t, svc.HandleWebhook(context.Background(), nil, ""))
+	assert.Equal(t, model.PaymentStatusCaptured, payments.payments["p1"].Status)
+	assert.Empty(t, payments.payments["p1"].PendingOperation)
+	assert.Equal(t, []string{model.PaymentStatusChangedEvent{}.EventName()}, recorder.names)
+	assert.Equal(t, []string{"acme"}, recorder.tenants)
+}
+
+func TestPaymentServiceAuthorize(t *testing.T) {
+	tests := []struct {
+		name       string
+		existing   model.PaymentStatus // status of an earlier payment of the order, empty for none
+		concurrent bool
+		wantErr    error
+	}{
+		{name: "authorizes the order total"},
+		{name: "after a failed payment", existing: model.PaymentStatusFailed},
+		{name: "order already paid", existing: model.PaymentStatusAuthorized, wantErr: model.ErrPaymentAlreadyExists},
+		{name: "concurrent authorization", existing: model.PaymentStatusPending, concurrent: true, wantErr: model.ErrPaymentAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := newMemoryOrderRepo(&model.Order{ID: "o1", UserID: "u1", Status: model.OrderStatusPending, Total: 100, Version: 1})
+			payments := newMemoryPaymentRepo()
+			if tt.existing != "" {
+				payments = newMemoryPaymentRepo(testPayment("earlier", "o1", tt.existing, 0))
+			}
+			payments.concurrent = tt.concurrent
+			gateway := newRecordingGateway()
+			svc := NewPaymentService(payments, orders, nil, gateway, nil)
+
+			payment, err := svc.Authorize(context.Background(), "o1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Len(t, payments.payments, 1)
+				assert.Empty(t, gateway.authorized)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, model.PaymentStatusAuthorized, payment.Status)
+			assert.Equal(t, model.PaymentStatusAuthorized, payments.payments[payment.ID].Status)
+			assert.Equal(t, []string{payment.ID}, gateway.authorized)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+// SchedulePrice schedules a price change of a product taking effect at effectiveFrom
+func (s *ProductService) SchedulePrice(ctx context.Context, id string, price float64, effectiveFrom time.Time) (*model.PriceEntry, error) {
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, model.ErrProductNotFound
+	}
+
+	entry, err := product.SchedulePrice(price, effectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.priceRepo.Append(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, product)
+
+	return entry, nil
+}
+
+// ListPriceHistory retrieves the price history of a product, scheduled changes included, latest first
+func (s *ProductService) ListPriceHistory(ctx context.Context, id string, offset, limit int) ([]*model.PriceEntry, int64, error) {
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if product == nil {
+		return nil, 0, model.ErrProductNotFound
+	}
+	return s.priceRepo.ListByProductID(ctx, id, offset, limit)
+}
+
+// ApplyScheduledPrices applies up to limit scheduled price changes that are due and returns the
+// updated products. An entry is claimed before the product is updated, so concurrent runs never
+// apply it twice; if the update fails the claim is released and the next run retries it.
+func (s *ProductService) ApplyScheduledPrices(ctx context.Context, limit int) ([]*model.Product, error) {
+	due, err := s.priceRepo.ListDue(ctx, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []*model.Product
+	for _, entry := range due {
+		product, err := s.applyScheduledPrice(ctx, entry)
+		if err != nil {
+			log.SugaredLogger.Errorf("Failed to apply scheduled price %s of product %s: %v", entry.ID, entry.ProductID, err)
+			continue
+		}
+		if product != nil {
+			applied = append(applied, product)
+		}
+	}
+	return applied, nil
+}
+
+// applyScheduledPrice applies a due entry, returning nil if another run claimed it first
+func (s *ProductService) applyScheduledPrice(ctx context.Context, entry *model.PriceEntry) (*model.Product, error) {
+	product, err := s.repo.GetByID(ctx, entry.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, model.ErrProductNotFound
+	}
+
+	scheduled, previous := *entry, product.Prices()
+	if err := product.ApplyScheduledPrice(entry); err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.priceRepo.Transition(ctx, entry, model.PriceEntryScheduled)
+	if err != nil || !claimed {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, product); err != nil {
+		if _, releaseErr := s.priceRepo.Transition(ctx, &scheduled, model.PriceEntryActive); releaseErr != nil {
+			log.SugaredLogger.Errorf("Failed to release scheduled price %s: %v", entry.ID, releaseErr)
+		}
+		return nil, err
+	}
+
+	s.supersedePrices(ctx, product.ID, entry)
+
+	// Variants without a price override follow the new product price
+	previous[""] = product.Price
+	s.recordPriceChange(ctx, product, previous)
+
+	// Publish domain events
+	s.publishEvents(ctx, product)
+
+	return product, nil
+}
+
+// recordPriceChange appends to the history the prices that changed since previous, as returned by
+// Product.Prices, and closes the entries of the SKUs the product no longer has.
+// The product is already saved, so failures are logged rather than returned.
+func (s *ProductService) recordPriceChange(ctx context.Context, product *model.Product, previous map[string]float64) {
+	for _, entry := range product.CurrentPriceEntries(previous) {
+		if err := s.priceRepo.Append(ctx, entry); err != nil {
+			log.SugaredLogger.Errorf("Failed to append price history of product %s: %v", product.ID, err)
+			continue
+		}
+		s.supersedePrices(ctx, product.ID, entry)
+	}
+
+	current := product.Prices()
+	for sku := range previous {
+		if _, ok := current[sku]; ok {
+			continue
+		}
+		if err := s.priceRepo.Supersede(ctx, product.ID, sku, "", product.UpdatedAt); err != nil {
+			log.SugaredLogger.Errorf("Failed to supersede price history of product %s: %v", product.ID, err)
+		}
+	}
+}
+
+// supersedePrices closes the window of the entries of the same SKU that were active before entry
+func (s *ProductService) supersedePrices(ctx context.Context, productID string, entry *model.PriceEntry) {
+	if err := s.priceRepo.Supersede(ctx, productID, entry.SKU, entry.ID, entry.EffectiveFrom); err != nil {
+		log.SugaredLogger.Errorf("Failed to supersede price history of product %s: %v", productID, err)
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+)
+
+// memoryPriceRepo keeps the price history in memory
+type memoryPriceRepo struct {
+	entries []*model.PriceEntry
+
+	// claimedElsewhere makes Transition report the entries as claimed by another run
+	claimedElsewhere bool
+}
+
+func (r *memoryPriceRepo) Append(_ context.Context, entry *model.PriceEntry) error {
+	if entry.ID == "" {
+		entry.ID = fmt.Sprintf("entry-%d", len(r.entries)+1)
+	}
+	clone := *entry
+	r.entries = append(r.entries, &clone)
+	return nil
+}
+
+func (r *memoryPriceRepo) Transition(_ context.Context, entry *model.PriceEntry, from model.PriceEntryStatus) (bool, error) {
+	if r.claimedElsewhere {
+		return false, nil
+	}
+	for i, stored := range r.entries {
+		if stored.ID == entry.ID && stored.Status == from {
+			clone := *entry
+			r.entries[i] = &clone
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryPriceRepo) Supersede(_ context.Context, productID, sku, exceptID string, at time.Time) error {
+	for _, stored := range r.entries {
+		if stored.ProductID == productID && stored.SKU == sku && stored.ID != exceptID && stored.Status == model.PriceEntryActive {
+			stored.Status = model.PriceEntrySuperseded
+			stored.EffectiveTo = &at
+		}
+	}
+	return nil
+}
+
+func (r *memoryPriceRepo) ListByProductID(_ context.Context, productID string, _, _ int) ([]*model.PriceEntry, int64, error) {
+	var entries []*model.PriceEntry
+	for _, stored := range r.entries {
+		if stored.ProductID == productID {
+			entries = append(entries, stored)
+		}
+	}
+	return entries, int64(len(entries)), nil
+}
+
+func (r *memoryPriceRepo) ListDue(_ context.Context, before time.Time, limit int) ([]*model.PriceEntry, error) {
+	var due []*model.PriceEntry
+	for _, stored := range r.entries {
+		if stored.Status == model.PriceEntryScheduled && stored.EffectiveFrom.Before(before) {
+			clone := *stored
+			due = append(due, &clone)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].EffectiveFrom.Before(due[j].EffectiveFrom) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+// byStatus returns the entries of a SKU with the status
+func (r *memoryPriceRepo) byStatus(sku string, status model.PriceEntryStatus) []*model.PriceEntry {
+	var entries []*model.PriceEntry
+	for _, stored := range r.entries {
+		if stored.SKU == sku && stored.Status == status {
+			entries = append(entries, stored)
+		}
+	}
+	return entries
+}
+
+// priceTestSetup returns a product priced 20 with a variant following the product price and one with
+// an override, the active history of those prices and a scheduled change to 30
+func priceTestSetup(t *testing.T) (*memoryProductRepo, *memoryPriceRepo) {
+	t.Helper()
+	product := stockTestProduct(t, "p1", 0)
+	product.Price = 20
+	require.NoError(t, product.AddVariant(model.Variant{SKU: "P1-S"}))
+	require.NoError(t, product.AddVariant(model.Variant{SKU: "P1-L", Price: 25}))
+
+	prices := &memoryPriceRepo{}
+	for _, entry := range product.CurrentPriceEntries(nil) {
+		require.NoError(t, prices.Append(context.Background(), entry))
+	}
+	require.NoError(t, prices.Append(context.Background(), &model.PriceEntry{
+		ID: "scheduled", ProductID: "p1", Price: 30, Status: model.PriceEntryScheduled,
+		EffectiveFrom: time.Now().Add(-time.Minute),
+	}))
+	return newMemoryProductRepo(product), prices
+}
+
+func TestProductServiceApplyScheduledPrices(t *testing.T) {
+	tests := []struct {
+		name             string
+		claimedElsewhere bool
+		failUpdate       bool
+		wantApplied      bool
+		wantScheduled    int // scheduled entries left
+	}{
+		{name: "applies a due price", wantApplied: true},
+		{name: "skips a price claimed by another run", claimedElsewhere: true, wantScheduled: 1},
+		{name: "releases the claim when the product cannot be saved", failUpdate: true, wantScheduled: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products, prices := priceTestSetup(t)
+			products.failUpdate = tt.failUpdate
+			prices.claimedElsewhere = tt.claimedElsewhere
+			svc := NewProductService(products, nil, nil, prices, nil, nil, nil)
+
+			applied, err := svc.ApplyScheduledPrices(context.Background(), 10)
+			require.NoError(t, err)
+			assert.Len(t, prices.byStatus("", model.PriceEntryScheduled), tt.wantScheduled)
+
+			if !tt.wantApplied {
+				assert.Empty(t, applied)
+				assert.Equal(t, 20.0, products.products["p1"].Price)
+				assert.Len(t, prices.byStatus("", model.PriceEntryActive), 1)
+				assert.Empty(t, prices.byStatus("", model.PriceEntrySuperseded))
+				return
+			}
+
+			require.Len(t, applied, 1)
+			assert.Equal(t, 30.0, products.products["p1"].Price)
+			assert.Equal(t, 2, products.products["p1"].PriceVersion)
+
+			// The scheduled entry becomes the active product price and closes the previous one
+			active := prices.byStatus("", model.PriceEntryActive)
+			require.Len(t, active, 1)
+			assert.Equal(t, "scheduled", active[0].ID)
+			assert.Equal(t, 2, active[0].Version)
+			superseded := prices.byStatus("", model.PriceEntrySuperseded)
+			require.Len(t, superseded, 1)
+			assert.Equal(t, 20.0, superseded[0].Price)
+			assert.Equal(t, active[0].EffectiveFrom, *superseded[0].EffectiveTo)
+
+			// The variant following the product price gets a new entry, the override keeps its own
+			small := prices.byStatus("P1-S", model.PriceEntryActive)
+			require.Len(t, small, 1)
+			assert.Equal(t, 30.0, small[0].Price)
+			assert.Len(t, prices.byStatus("P1-S", model.PriceEntrySuperseded), 1)
+			assert.Len(t, prices.byStatus("P1-L", model.PriceEntryActive), 1)
+			assert.Empty(t, prices.byStatus("P1-L", model.PriceEntrySuperseded))
+
+			// Nothing is left to apply on the next run
+			applied, err = svc.ApplyScheduledPrices(context.Background(), 10)
+			require.NoError(t, err)
+			assert.Empty(t, applied)
+		})
+	}
+}
+
+func TestProductServiceSchedulePrice(t *testing.T) {
+	tests := []struct {
+		name          string
+		price         float64
+		effectiveFrom time.Time
+		wantErr       error
+	}{
+		{name: "schedules a future price", price: 18, effectiveFrom: time.Now().Add(time.Hour)},
+		{name: "rejects a past start", price: 18, effectiveFrom: time.Now().Add(-time.Hour), wantErr: model.ErrPriceEffectiveFromInvalid},
+		{name: "rejects a price that is not positive", effectiveFrom: time.Now().Add(time.Hour), wantErr: model.ErrProductPriceInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products, prices := priceTestSetup(t)
+			svc := NewProductService(products, nil, nil, prices, nil, nil, nil)
+
+			entry, err := svc.SchedulePrice(context.Background(), "p1", tt.price, tt.effectiveFrom)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Len(t, prices.byStatus("", model.PriceEntryScheduled), 1)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, model.PriceEntryScheduled, entry.Status)
+			assert.Zero(t, entry.Version)
+			assert.Len(t, prices.byStatus("", model.PriceEntryScheduled), 2)
+			// The current price does not change until the schedule takes effect
+			assert.Equal(t, 20.0, products.products["p1"].Price)
+		})
+	}
+}
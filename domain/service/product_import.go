@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// DefaultProductImportBatchSize is the number of rows written per bulk write when none is given
+const DefaultProductImportBatchSize = 500
+
+// Import upserts the rows read from a product file in batches, each written with a single bulk write.
+// Rows are validated like new products; rows that fail validation or cannot be applied are reported
+// and skipped without failing the import. With DryRun nothing is written. The returned error ends the
+// import early, after the batches already written, and comes with the report so far.
+func (s *ProductService) Import(ctx context.Context, reader repo.IProductRowReader, opts model.ProductImportOptions) (*model.ProductImportReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultProductImportBatchSize
+	}
+
+	report := &model.ProductImportReport{ReferenceID: opts.ReferenceID, DryRun: opts.DryRun}
+	batch := make([]*model.ProductRow, 0, opts.BatchSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+
+		batch = append(batch, row)
+		if len(batch) < opts.BatchSize {
+			continue
+		}
+		if err := s.importBatch(ctx, batch, opts, report); err != nil {
+			return report, err
+		}
+		batch = batch[:0]
+	}
+
+	if len(batch) > 0 {
+		if err := s.importBatch(ctx, batch, opts, report); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// Export writes every product to the writer, one row per variant, and returns the number of products written
+func (s *ProductService) Export(ctx context.Context, writer repo.IProductRowWriter) (int, error) {
+	count := 0
+	err := s.repo.Stream(ctx, func(product *model.Product) error {
+		for _, row := range product.ExportRows() {
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, writer.Flush()
+}
+
+// importBatch applies a batch of rows to the products they match and saves the changed products
+func (s *ProductService) importBatch(ctx context.Context, rows []*model.ProductRow, opts model.ProductImportOptions, report *model.ProductImportReport) error {
+	results := make([]model.ProductImportRowResult, len(rows))
+	var names, skus []string
+	for i, row := range rows {
+		results[i] = model.ProductImportRowResult{Line: row.Line, SKU: row.SKU, Name: row.Name}
+		if row.Err == nil {
+			_, row.Err = model.NewProduct(row.Name, row.Description, row.Price, row.Stock)
+		}
+		if row.Err != nil {
+			continue
+		}
+		names = append(names, row.Name)
+		if row.SKU != "" {
+			skus = append(skus, row.SKU)
+		}
+	}
+
+	batch := newProductImportBatch()
+	if len(names) > 0 {
+		existing, err := s.repo.ListByNamesOrSKUs(ctx, names, skus)
+		if err != nil {
+			return err
+		}
+		for _, product := range existing {
+			batch.add(product, product.StockLevels())
+		}
+	}
+
+	for i, row := range rows {
+		if row.Err != nil {
+			results[i].Fail(row.Err)
+			continue
+		}
+		target, action, err := batch.apply(row)
+		if err != nil {
+			results[i].Fail(err)
+			continue
+		}
+		results[i].Action = action
+		if action != model.ProductImportUnchanged {
+			target.changed = true
+		}
+		target.rows = append(target.rows, i)
+	}
+
+	if !opts.DryRun {
+		if err := s.saveImported(ctx, batch.changed(), results, opts.ReferenceID); err != nil {
+			return err
+		}
+	}
+
+	for _, target := range batch.targets {
+		for _, i := range target.rows {
+			if results[i].Action != model.ProductImportFailed {
+				results[i].ProductID = target.product.ID
+			}
+		}
+	}
+	for _, result := range results {
+		report.Add(result)
+	}
+	return nil
+}
+
+// saveImported bulk writes the changed products, then records their stock changes and publishes their
+// events. The rows of a product that could not be saved are marked failed.
+func (s *ProductService) saveImported(ctx context.Context, targets []*importTarget, results []model.ProductImportRowResult, referenceID string) error {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	products := make([]*model.Product, len(targets))
+	for i, target := range targets {
+		products[i] = target.product
+	}
+	saveErrs, err := s.repo.BulkSave(ctx, products)
+	if err != nil {
+		return err
+	}
+
+	for i, target := range targets {
+		if saveErrs[i] != nil {
+			for _, row := range target.rows {
+				results[row].Fail(saveErrs[i])
+			}
+			continue
+		}
+
+		// The product is saved, so a ledger failure fails its rows and is left to stock reconciliation
+		if err := s.recordStockChanges(ctx, target.product, target.before, target.product.StockLevels(), model.StockMovementReasonImport, referenceID); err != nil {
+			for _, row := range target.rows {
+				results[row].Fail(err)
+			}
+		}
+		s.recordPriceChange(ctx, target.product, target.prices)
+
+		// Publish domain events
+		s.publishEvents(ctx, target.product)
+	}
+	return nil
+}
+
+// importTarget is a product matched or created by the rows of an import batch
+type importTarget struct {
+	product *model.Product
+	before  map[string]int     // stock levels when loaded, nil for new products
+	prices  map[string]float64 // prices when loaded, nil for new products
+	rows    []int              // indexes of the rows applied to the product
+	changed bool
+}
+
+// productImportBatch matches the rows of a batch to products by name and SKU
+type productImportBatch struct {
+	byName  map[string]*importTarget
+	bySKU   map[string]*importTarget
+	targets []*importTarget
+}
+
+func newProductImportBatch() *productImportBatch {
+	return &productImportBatch{
+		byName: make(map[string]*importTarget),
+		bySKU:  make(map[string]*importTarget),
+	}
+}
+
+func (b *productImportBatch) add(product *model.Product, before map[string]int) *importTarget {
+	target := &importTarget{product: product, before: before}
+	if before != nil {
+		target.prices = product.Prices()
+	}
+	b.targets = append(b.targets, target)
+	if _, ok := b.byName[product.Name]; !ok {
+		b.byName[product.Name] = target
+	}
+	for _, variant := range product.Variants {
+		b.bySKU[variant.SKU] = target
+	}
+	return target
+}
+
+func (b *productImportBatch) changed() []*importTarget {
+	var changed []*importTarget
+	for _, target := range b.targets {
+		if target.changed {
+			changed = append(changed, target)
+		}
+	}
+	return changed
+}
+
+// apply applies a validated row to the product it matches, creating the product when there is none.
+// Rows with a SKU change the price and stock of their variant, the name only picks the product a new
+// variant is added to. A variant price equal to the product price is stored as no override.
+func (b *productImportBatch) apply(row *model.ProductRow) (*importTarget, model.ProductImportAction, error) {
+	if row.SKU == "" {
+		target, ok := b.byName[row.Name]
+		if !ok {
+			product, err := model.NewProduct(row.Name, row.Description, row.Price, row.Stock)
+			if err != nil {
+				return nil, "", err
+			}
+			return b.add(product, nil), model.ProductImportCreated, nil
+		}
+
+		product := target.product
+		if product.HasVariants() {
+			return nil, "", model.ErrVariantRequired
+		}
+		if product.Description == row.Description && product.Price == row.Price && product.Stock == row.Stock {
+			return target, model.ProductImportUnchanged, nil
+		}
+		if product.Description != row.Description || product.Price != row.Price {
+			if err := product.Update(row.Name, row.Description, row.Price); err != nil {
+				return nil, "", err
+			}
+		}
+		if delta := row.Stock - product.Stock; delta != 0 {
+			if err := product.UpdateStock("", "", delta); err != nil {
+				return nil, "", err
+			}
+		}
+		return target, model.ProductImportUpdated, nil
+	}
+
+	if target, ok := b.bySKU[row.SKU]; ok {
+		product := target.product
+		variant, _ := product.Variant(row.SKU)
+		price := variantImportPrice(product, row.Price)
+		if variant.Price == price && variant.Stock == row.Stock {
+			return target, model.ProductImportUnchanged, nil
+		}
+		if variant.Price != price {
+			if err := product.UpdateVariant(row.SKU, variant.Attributes, price); err != nil {
+				return nil, "", err
+			}
+		}
+		if delta := row.Stock - variant.Stock; delta != 0 {
+			if err := product.UpdateStock(row.SKU, "", delta); err != nil {
+				return nil, "", err
+			}
+		}
+		return target, model.ProductImportUpdated, nil
+	}
+
+	target, ok := b.byName[row.Name]
+	if !ok {
+		product, err := model.NewProduct(row.Name, row.Description, row.Price, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := product.AddVariant(model.Variant{SKU: row.SKU, Stock: row.Stock}); err != nil {
+			return nil, "", err
+		}
+		return b.add(product, nil), model.ProductImportCreated, nil
+	}
+
+	variant := model.Variant{SKU: row.SKU, Price: variantImportPrice(target.product, row.Price), Stock: row.Stock}
+	if err := target.product.AddVariant(variant); err != nil {
+		return nil, "", err
+	}
+	b.bySKU[row.SKU] = target
+	return target, model.ProductImportCreated, nil
+}
+
+// variantImportPrice returns the price override of an imported variant price
+func variantImportPrice(product *model.Product, price float64) float64 {
+	if price == product.Price {
+		return 0
+	}
+	return price
+}
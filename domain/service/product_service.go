@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
 	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
@@ -12,28 +13,58 @@ import (
 // IProductService defines the interface for product service operations
 type IProductService interface {
 	Create(ctx context.Context, name, description string, price float64, stock int) (*model.Product, error)
-	Update(ctx context.Context, id string, name, description string, price float64) (*model.Product, error)
+	Update(ctx context.Context, id string, name, description string, price float64, expectedVersion int) (*model.Product, error)
 	Delete(ctx context.Context, id string) error
 	Get(ctx context.Context, id string) (*model.Product, error)
 	GetByName(ctx context.Context, name string) (*model.Product, error)
 	List(ctx context.Context, offset, limit int) ([]*model.Product, int64, error)
-	UpdateStock(ctx context.Context, id string, quantity int) error
+	GetBySKU(ctx context.Context, sku string) (*model.Product, error)
+	UpdateStock(ctx context.Context, id string, change model.StockChange) error
+	ReserveStock(ctx context.Context, id string, change model.StockChange) error
+	AllocateStock(ctx context.Context, id string, change model.StockChange, destination *model.Location) ([]model.StockAllocation, error)
+	ListStockMovements(ctx context.Context, id string, offset, limit int) ([]*model.StockMovement, int64, error)
+	ReconcileStock(ctx context.Context, offset, limit int) ([]model.StockDrift, int, error)
+	SetLowStockThreshold(ctx context.Context, id string, threshold, expectedVersion int) (*model.Product, error)
+	ListLowStock(ctx context.Context, offset, limit int) ([]*model.Product, int64, error)
+	SweepLowStock(ctx context.Context, offset, limit int) ([]*model.Product, error)
+	AddVariant(ctx context.Context, id string, variant model.Variant, expectedVersion int) (*model.Product, error)
+	UpdateVariant(ctx context.Context, id, sku string, attributes map[string]string, price float64, expectedVersion int) (*model.Product, error)
+	RemoveVariant(ctx context.Context, id, sku string, expectedVersion int) (*model.Product, error)
+	AssignCategories(ctx context.Context, id string, categoryIDs []string, expectedVersion int) (*model.Product, error)
+	ListByCategory(ctx context.Context, categoryID string, offset, limit int) ([]*model.Product, int64, error)
+	ReplaceCategory(ctx context.Context, categoryID, replacementID string) ([]string, error)
+	Search(ctx context.Context, criteria model.ProductSearchCriteria) (*model.ProductSearchResult, error)
+	Import(ctx context.Context, reader repo.IProductRowReader, opts model.ProductImportOptions) (*model.ProductImportReport, error)
+	Export(ctx context.Context, writer repo.IProductRowWriter) (int, error)
+	SchedulePrice(ctx context.Context, id string, price float64, effectiveFrom time.Time) (*model.PriceEntry, error)
+	ListPriceHistory(ctx context.Context, id string, offset, limit int) ([]*model.PriceEntry, int64, error)
+	ApplyScheduledPrices(ctx context.Context, limit int) ([]*model.Product, error)
 }
 
 // ProductService implements IProductService
 type ProductService struct {
-	repo     repo.IProductRepo
-	eventBus event.EventBus
+	repo          repo.IProductRepo
+	categoryRepo  repo.ICategoryRepo
+	movementRepo  repo.IStockMovementRepo
+	priceRepo     repo.IPriceHistoryRepo
+	warehouseRepo repo.IWarehouseRepo
+	allocator     repo.IAllocationStrategy
+	eventBus      event.EventBus
 }
 
 // NewProductService creates a new product service
-func NewProductService(repo repo.IProductRepo, eventBus event.EventBus) *ProductService {
+func NewProductService(repo repo.IProductRepo, categoryRepo repo.ICategoryRepo, movementRepo repo.IStockMovementRepo, priceRepo repo.IPriceHistoryRepo, warehouseRepo repo.IWarehouseRepo, allocator repo.IAllocationStrategy, eventBus event.EventBus) *ProductService {
 	if eventBus == nil {
 		eventBus = event.NewNoopEventBus()
 	}
 	return &ProductService{
-		repo:     repo,
-		eventBus: eventBus,
+		repo:          repo,
+		categoryRepo:  categoryRepo,
+		movementRepo:  movementRepo,
+		priceRepo:     priceRepo,
+		warehouseRepo: warehouseRepo,
+		allocator:     allocator,
+		eventBus:      eventBus,
 	}
 }
 
@@ -49,14 +80,24 @@ func (s *ProductService) Create(ctx context.Context, name, description string, p
 		return nil, err
 	}
 
+	// The opening stock is the first entry of the ledger; without it the product is not created
+	if err := s.recordStockChanges(ctx, created, nil, created.StockLevels(), model.StockMovementReasonInitial, ""); err != nil {
+		if deleteErr := s.repo.Delete(ctx, created.ID); deleteErr != nil {
+			log.SugaredLogger.Errorf("Failed to delete product %s without opening stock movements: %v", created.ID, deleteErr)
+		}
+		return nil, err
+	}
+	s.recordPriceChange(ctx, created, nil)
+
 	// Publish domain events from original product (has the recorded events)
 	s.publishEvents(ctx, product)
 
 	return created, nil
 }
 
-// Update updates an existing product
-func (s *ProductService) Update(ctx context.Context, id string, name, description string, price float64) (*model.Product, error) {
+// Update updates an existing product.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *ProductService) Update(ctx context.Context, id string, name, description string, price float64, expectedVersion int) (*model.Product, error) {
 	product, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -65,6 +106,11 @@ func (s *ProductService) Update(ctx context.Context, id string, name, descriptio
 		return nil, model.ErrProductNotFound
 	}
 
+	if err := model.CheckVersion(product.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	prices := product.Prices()
 	if err := product.Update(name, description, price); err != nil {
 		return nil, err
 	}
@@ -73,6 +119,8 @@ func (s *ProductService) Update(ctx context.Context, id string, name, descriptio
 		return nil, err
 	}
 
+	s.recordPriceChange(ctx, product, prices)
+
 	// Publish domain events
 	s.publishEvents(ctx, product)
 
@@ -117,8 +165,19 @@ func (s *ProductService) List(ctx context.Context, offset, limit int) ([]*model.
 	return s.repo.List(ctx, offset, limit)
 }
 
-// UpdateStock updates product stock
-func (s *ProductService) UpdateStock(ctx context.Context, id string, quantity int) error {
+// GetBySKU retrieves the product owning the variant with the given SKU
+func (s *ProductService) GetBySKU(ctx context.Context, sku string) (*model.Product, error) {
+	return s.repo.GetBySKU(ctx, sku)
+}
+
+// UpdateStock adds a signed quantity to the stock of a variant, or of the product itself when it has
+// no variants and the SKU is empty, and records the movement in the ledger.
+// With a warehouse ID the stock held at that warehouse changes too.
+func (s *ProductService) UpdateStock(ctx context.Context, id string, change model.StockChange) error {
+	if err := s.validateStockChange(ctx, change); err != nil {
+		return err
+	}
+
 	product, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -127,22 +186,475 @@ func (s *ProductService) UpdateStock(ctx context.Context, id string, quantity in
 		return model.ErrProductNotFound
 	}
 
-	if err := product.UpdateStock(quantity); err != nil {
+	if err := product.UpdateStock(change.SKU, change.WarehouseID, change.Quantity); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateStock(ctx, id, change.SKU, change.WarehouseID, change.Quantity); err != nil {
+		return err
+	}
+
+	if err := s.appendMovements(ctx, model.NewStockMovement(product, change)); err != nil {
+		s.revertStock(ctx, id, change, -change.Quantity)
+		return err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, product)
+
+	return nil
+}
+
+// ReserveStock takes a quantity from the stock of a variant, or of the product itself when it has no
+// variants and the SKU is empty, and records the movement in the ledger. With a warehouse ID the
+// stock is taken from that warehouse. The decrement is atomic, so concurrent reservations cannot oversell.
+func (s *ProductService) ReserveStock(ctx context.Context, id string, change model.StockChange) error {
+	if err := s.validateStockChange(ctx, change); err != nil {
+		return err
+	}
+
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
 		return err
 	}
+	if product == nil {
+		return model.ErrProductNotFound
+	}
+
+	return s.reserve(ctx, product, change)
+}
+
+// AllocateStock reserves a quantity of a variant, or of the product itself when it has no variants
+// and the SKU is empty, from the warehouses picked by the allocation strategy. Stock not held at any
+// warehouse is reserved as a whole, with an allocation without warehouse ID.
+// The warehouse ID of the change is ignored.
+func (s *ProductService) AllocateStock(ctx context.Context, id string, change model.StockChange, destination *model.Location) ([]model.StockAllocation, error) {
+	change.WarehouseID = ""
+	if err := change.Validate(); err != nil {
+		return nil, err
+	}
+
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, model.ErrProductNotFound
+	}
 
-	if err := s.repo.UpdateStock(ctx, id, quantity); err != nil {
+	levels, err := product.StockByWarehouse(change.SKU)
+	if err != nil {
+		return nil, err
+	}
+	if len(levels) == 0 {
+		if err := s.reserve(ctx, product, change); err != nil {
+			return nil, err
+		}
+		return []model.StockAllocation{{Quantity: change.Quantity}}, nil
+	}
+
+	warehouses, err := s.warehouseRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]model.WarehouseStock, 0, len(levels))
+	for _, warehouse := range warehouses {
+		if available := levels[warehouse.ID]; available > 0 {
+			candidates = append(candidates, model.WarehouseStock{Warehouse: warehouse, Available: available})
+		}
+	}
+
+	allocations, err := s.allocator.Allocate(ctx, model.AllocationRequest{
+		Quantity:    change.Quantity,
+		Destination: destination,
+		Candidates:  candidates,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, allocation := range allocations {
+		reservation := change
+		reservation.WarehouseID = allocation.WarehouseID
+		reservation.Quantity = allocation.Quantity
+		if err := s.reserve(ctx, product, reservation); err != nil {
+			// Put back what was already taken, so the item is reserved entirely or not at all
+			for _, taken := range allocations[:i] {
+				release := change
+				release.WarehouseID = taken.WarehouseID
+				release.Quantity = taken.Quantity
+				if releaseErr := s.UpdateStock(ctx, id, release); releaseErr != nil {
+					log.SugaredLogger.Errorf("Failed to release stock of product %s at warehouse %s: %v", id, taken.WarehouseID, releaseErr)
+				}
+			}
+			return nil, err
+		}
+	}
+
+	return allocations, nil
+}
+
+// reserve checks and atomically takes stock of a loaded product, then records the movement
+func (s *ProductService) reserve(ctx context.Context, product *model.Product, change model.StockChange) error {
+	if err := product.ReserveStock(change.SKU, change.WarehouseID, change.Quantity); err != nil {
+		return err
+	}
+
+	if err := s.repo.ReserveStock(ctx, product.ID, change.SKU, change.WarehouseID, change.Quantity); err != nil {
+		return err
+	}
+
+	taken := change
+	taken.Quantity = -change.Quantity
+	if err := s.appendMovements(ctx, model.NewStockMovement(product, taken)); err != nil {
+		s.revertStock(ctx, product.ID, change, change.Quantity)
+		return err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, product)
+
+	return nil
+}
+
+// validateStockChange validates a stock change and checks its warehouse exists
+func (s *ProductService) validateStockChange(ctx context.Context, change model.StockChange) error {
+	if err := change.Validate(); err != nil {
+		return err
+	}
+	if change.WarehouseID == "" {
+		return nil
+	}
+
+	warehouse, err := s.warehouseRepo.GetByID(ctx, change.WarehouseID)
+	if err != nil {
+		return err
+	}
+	if warehouse == nil {
+		return model.ErrWarehouseNotFound
+	}
+	return nil
+}
+
+// ListStockMovements retrieves the ledger of a product, newest first, with pagination
+func (s *ProductService) ListStockMovements(ctx context.Context, id string, offset, limit int) ([]*model.StockMovement, int64, error) {
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if product == nil {
+		return nil, 0, model.ErrProductNotFound
+	}
+
+	return s.movementRepo.ListByProductID(ctx, id, offset, limit)
+}
+
+// ReconcileStock recomputes the stock of a page of products from the ledger and publishes a
+// product.stock_drift_detected event for every mismatch. It returns the drifts and the number of
+// products checked. Stock is not corrected, as the ledger may be the side that is wrong.
+// Products created before the ledger have no opening movements; they are opened first, with
+// initial movements covering the stock the ledger does not explain.
+func (s *ProductService) ReconcileStock(ctx context.Context, offset, limit int) ([]model.StockDrift, int, error) {
+	products, _, err := s.repo.List(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(products) == 0 {
+		return nil, 0, nil
+	}
+
+	ids := make([]string, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	totals, err := s.movementRepo.SumByProductIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.openLedgers(ctx, products, ids, totals); err != nil {
+		return nil, 0, err
+	}
+
+	var drifts []model.StockDrift
+	for _, product := range products {
+		productDrifts := product.DetectStockDrift(totals[product.ID])
+		if len(productDrifts) == 0 {
+			continue
+		}
+		product.RecordStockDrift(productDrifts)
+		s.publishEvents(ctx, product)
+		drifts = append(drifts, productDrifts...)
+	}
+
+	return drifts, len(products), nil
+}
+
+// openLedgers appends the initial movements of the products without any, so that their ledger totals
+// match their stock, and adds them to totals
+func (s *ProductService) openLedgers(ctx context.Context, products []*model.Product, ids []string, totals map[string]map[string]int) error {
+	opened, err := s.movementRepo.ProductIDsWithReason(ctx, ids, model.StockMovementReasonInitial)
+	if err != nil {
 		return err
 	}
 
+	for _, product := range products {
+		if opened[product.ID] {
+			continue
+		}
+
+		ledger := totals[product.ID]
+		// Every SKU gets an initial movement, even of zero, which marks the ledger as opened
+		levels := product.StockLevels()
+		for sku := range ledger {
+			if _, ok := levels[sku]; !ok {
+				levels[sku] = 0
+			}
+		}
+		movements := make([]*model.StockMovement, 0, len(levels))
+		for sku, stock := range levels {
+			movements = append(movements, model.NewStockMovement(product, model.StockChange{
+				SKU: sku, Quantity: stock - ledger[sku], Reason: model.StockMovementReasonInitial,
+			}))
+		}
+		if err := s.appendMovements(ctx, movements...); err != nil {
+			return err
+		}
+
+		totals[product.ID] = levels
+	}
+	return nil
+}
+
+// SetLowStockThreshold sets the stock level at or below which the SKUs of a product are low
+func (s *ProductService) SetLowStockThreshold(ctx context.Context, id string, threshold, expectedVersion int) (*model.Product, error) {
+	return s.modify(ctx, id, expectedVersion, func(product *model.Product) error {
+		return product.SetLowStockThreshold(threshold)
+	})
+}
+
+// ListLowStock retrieves products with a SKU at or below their low-stock threshold, with pagination
+func (s *ProductService) ListLowStock(ctx context.Context, offset, limit int) ([]*model.Product, int64, error) {
+	return s.repo.ListLowStock(ctx, offset, limit)
+}
+
+// SweepLowStock publishes a product.stock_low event for every low SKU of a page of low-stock products,
+// catching products that became low without crossing the threshold through a stock change.
+// It returns the products of the page.
+func (s *ProductService) SweepLowStock(ctx context.Context, offset, limit int) ([]*model.Product, error) {
+	products, _, err := s.repo.ListLowStock(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, product := range products {
+		product.RecordLowStock()
+		s.publishEvents(ctx, product)
+	}
+
+	return products, nil
+}
+
+// AddVariant adds a variant to a product
+func (s *ProductService) AddVariant(ctx context.Context, id string, variant model.Variant, expectedVersion int) (*model.Product, error) {
+	return s.modify(ctx, id, expectedVersion, func(product *model.Product) error {
+		return product.AddVariant(variant)
+	})
+}
+
+// UpdateVariant changes the attributes and price override of a variant
+func (s *ProductService) UpdateVariant(ctx context.Context, id, sku string, attributes map[string]string, price float64, expectedVersion int) (*model.Product, error) {
+	return s.modify(ctx, id, expectedVersion, func(product *model.Product) error {
+		return product.UpdateVariant(sku, attributes, price)
+	})
+}
+
+// RemoveVariant removes a variant from a product
+func (s *ProductService) RemoveVariant(ctx context.Context, id, sku string, expectedVersion int) (*model.Product, error) {
+	return s.modify(ctx, id, expectedVersion, func(product *model.Product) error {
+		return product.RemoveVariant(sku)
+	})
+}
+
+// AssignCategories replaces the categories of a product; every category must exist.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *ProductService) AssignCategories(ctx context.Context, id string, categoryIDs []string, expectedVersion int) (*model.Product, error) {
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, model.ErrProductNotFound
+	}
+
+	if err := model.CheckVersion(product.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.GetByIDs(ctx, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]struct{}, len(categories))
+	for _, category := range categories {
+		found[category.ID] = struct{}{}
+	}
+	for _, categoryID := range categoryIDs {
+		if _, ok := found[categoryID]; !ok {
+			return nil, model.ErrCategoryNotFound
+		}
+	}
+
+	product.AssignCategories(categoryIDs)
+
+	if err := s.repo.Update(ctx, product); err != nil {
+		return nil, err
+	}
+
 	// Publish domain events
 	s.publishEvents(ctx, product)
 
+	return product, nil
+}
+
+// ListByCategory retrieves products in a category or any of its descendants with pagination
+func (s *ProductService) ListByCategory(ctx context.Context, categoryID string, offset, limit int) ([]*model.Product, int64, error) {
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if category == nil {
+		return nil, 0, model.ErrCategoryNotFound
+	}
+
+	categoryIDs, err := s.subtreeIDs(ctx, category)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.repo.ListByCategoryIDs(ctx, categoryIDs, offset, limit)
+}
+
+// ReplaceCategory reassigns the products of a category to its replacement, or unassigns them when
+// replacementID is empty. It returns the IDs of the products changed.
+func (s *ProductService) ReplaceCategory(ctx context.Context, categoryID, replacementID string) ([]string, error) {
+	return s.repo.ReplaceCategory(ctx, categoryID, replacementID)
+}
+
+// Search finds products matching the criteria, with facet counts
+func (s *ProductService) Search(ctx context.Context, criteria model.ProductSearchCriteria) (*model.ProductSearchResult, error) {
+	if err := criteria.Normalize(); err != nil {
+		return nil, err
+	}
+
+	if criteria.Category != "" {
+		// An unknown category still filters, matching nothing
+		criteria.CategoryIDs = []string{criteria.Category}
+		category, err := s.categoryRepo.GetByID(ctx, criteria.Category)
+		if err != nil {
+			return nil, err
+		}
+		if category != nil {
+			if criteria.CategoryIDs, err = s.subtreeIDs(ctx, category); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return s.repo.Search(ctx, criteria)
+}
+
+// modify loads a product, applies a change and stores it with optimistic locking.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *ProductService) modify(ctx context.Context, id string, expectedVersion int, change func(*model.Product) error) (*model.Product, error) {
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, model.ErrProductNotFound
+	}
+
+	if err := model.CheckVersion(product.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	before, prices := product.StockLevels(), product.Prices()
+	if err := change(product); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, product); err != nil {
+		return nil, err
+	}
+
+	// Adding or removing variants moves stock between SKUs. The product is already saved, so a
+	// ledger failure is returned and left to stock reconciliation, which reports the drift.
+	if err := s.recordStockChanges(ctx, product, before, product.StockLevels(), model.StockMovementReasonManual, ""); err != nil {
+		return nil, err
+	}
+	s.recordPriceChange(ctx, product, prices)
+
+	// Publish domain events
+	s.publishEvents(ctx, product)
+
+	return product, nil
+}
+
+// subtreeIDs returns the IDs of a category and all its descendants
+func (s *ProductService) subtreeIDs(ctx context.Context, category *model.Category) ([]string, error) {
+	descendants, err := s.categoryRepo.ListSubtree(ctx, category.SubtreePath())
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(descendants)+1)
+	ids = append(ids, category.ID)
+	for _, descendant := range descendants {
+		ids = append(ids, descendant.ID)
+	}
+	return ids, nil
+}
+
+// recordStockChanges appends a movement for every SKU whose stock differs between the two levels
+func (s *ProductService) recordStockChanges(ctx context.Context, product *model.Product, before, after map[string]int, reason model.StockMovementReason, referenceID string) error {
+	var movements []*model.StockMovement
+	for sku, stock := range after {
+		if delta := stock - before[sku]; delta != 0 {
+			movements = append(movements, model.NewStockMovement(product, model.StockChange{
+				SKU: sku, Quantity: delta, Reason: reason, ReferenceID: referenceID,
+			}))
+		}
+	}
+	for sku, stock := range before {
+		if _, ok := after[sku]; !ok && stock != 0 {
+			movements = append(movements, model.NewStockMovement(product, model.StockChange{
+				SKU: sku, Quantity: -stock, Reason: reason, ReferenceID: referenceID,
+			}))
+		}
+	}
+	return s.appendMovements(ctx, movements...)
+}
+
+// appendMovements writes movements to the ledger
+func (s *ProductService) appendMovements(ctx context.Context, movements ...*model.StockMovement) error {
+	if err := s.movementRepo.Append(ctx, movements...); err != nil {
+		log.SugaredLogger.Errorf("Failed to append %d stock movements: %v", len(movements), err)
+		return err
+	}
 	return nil
 }
 
+// revertStock undoes a stock change whose movement could not be recorded, adding quantity back.
+// If that fails too, stock and ledger differ until reconciliation reports the drift.
+func (s *ProductService) revertStock(ctx context.Context, id string, change model.StockChange, quantity int) {
+	if err := s.repo.UpdateStock(ctx, id, change.SKU, change.WarehouseID, quantity); err != nil {
+		log.SugaredLogger.Errorf("Failed to revert stock of product %s without stock movement: %v", id, err)
+	}
+}
+
 // publishEvents publishes all pending domain events from the product
 func (s *ProductService) publishEvents(ctx context.Context, product *model.Product) {
+	ctx = model.EnsureTenant(ctx, product.TenantID)
 	for _, domainEvent := range product.Events() {
 		evt := event.NewBaseEvent(
 			domainEvent.EventName(),
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+var errLedgerDown = errors.New("ledger unavailable")
+
+// memoryProductRepo keeps products in memory and applies stock changes with the floor guard of the stores
+type memoryProductRepo struct {
+	repo.IProductRepo
+
+	products map[string]*model.Product
+
+	failUpdate bool
+}
+
+func newMemoryProductRepo(products ...*model.Product) *memoryProductRepo {
+	r := &memoryProductRepo{products: map[string]*model.Product{}}
+	for _, product := range products {
+		product.Events()
+		r.products[product.ID] = product
+	}
+	return r
+}
+
+func (r *memoryProductRepo) GetByID(_ context.Context, id string) (*model.Product, error) {
+	product, ok := r.products[id]
+	if !ok {
+		return nil, nil
+	}
+	clone := *product
+	clone.Variants = append([]model.Variant(nil), product.Variants...)
+	return &clone, nil
+}
+
+func (r *memoryProductRepo) Update(_ context.Context, product *model.Product) error {
+	if r.failUpdate {
+		return model.ErrVersionConflict
+	}
+	stored, ok := r.products[product.ID]
+	if !ok || stored.Version != product.Version {
+		return model.ErrVersionConflict
+	}
+	product.Version++
+	clone := *product
+	clone.Variants = append([]model.Variant(nil), product.Variants...)
+	r.products[product.ID] = &clone
+	return nil
+}
+
+func (r *memoryProductRepo) List(_ context.Context, _, _ int) ([]*model.Product, int64, error) {
+	var products []*model.Product
+	for id := range r.products {
+		product, _ := r.GetByID(context.Background(), id)
+		products = append(products, product)
+	}
+	return products, int64(len(products)), nil
+}
+
+func (r *memoryProductRepo) UpdateStock(_ context.Context, id, sku, warehouseID string, quantity int) error {
+	product, ok := r.products[id]
+	if !ok {
+		return model.ErrProductNotFound
+	}
+	return product.UpdateStock(sku, warehouseID, quantity)
+}
+
+// memoryMovementRepo keeps the stock ledger in memory
+type memoryMovementRepo struct {
+	movements []*model.StockMovement
+
+	failAppend bool
+}
+
+func (r *memoryMovementRepo) Append(_ context.Context, movements ...*model.StockMovement) error {
+	if r.failAppend {
+		return errLedgerDown
+	}
+	r.movements = append(r.movements, movements...)
+	return nil
+}
+
+func (r *memoryMovementRepo) ListByProductID(_ context.Context, productID string, _, _ int) ([]*model.StockMovement, int64, error) {
+	var movements []*model.StockMovement
+	for _, movement := range r.movements {
+		if movement.ProductID == productID {
+			movements = append(movements, movement)
+		}
+	}
+	return movements, int64(len(movements)), nil
+}
+
+func (r *memoryMovementRepo) SumByProductIDs(_ context.Context, productIDs []string) (map[string]map[string]int, error) {
+	totals := map[string]map[string]int{}
+	for _, movement := range r.movements {
+		for _, id := range productIDs {
+			if movement.ProductID != id {
+				continue
+			}
+			if totals[id] == nil {
+				totals[id] = map[string]int{}
+			}
+			totals[id][movement.SKU] += movement.Quantity
+		}
+	}
+	return totals, nil
+}
+
+func (r *memoryMovementRepo) ProductIDsWithReason(_ context.Context, productIDs []string, reason model.StockMovementReason) (map[string]bool, error) {
+	found := map[string]bool{}
+	for _, movement := range r.movements {
+		for _, id := range productIDs {
+			if movement.ProductID == id && movement.Reason == reason {
+				found[id] = true
+			}
+		}
+	}
+	return found, nil
+}
+
+func stockTestProduct(t *testing.T, id string, stock int) *model.Product {
+	t.Helper()
+	product, err := model.NewProduct("Widget "+id, "", 10, stock)
+	require.NoError(t, err)
+	product.ID = id
+	return product
+}
+
+func TestProductServiceUpdateStockLedger(t *testing.T) {
+	tests := []struct {
+		name          string
+		change        model.StockChange
+		failAppend    bool
+		wantErr       error
+		wantStock     int
+		wantMovements []int
+	}{
+		{name: "records an added quantity", change: model.StockChange{Quantity: 3, Reason: model.StockMovementReasonManual},
+			wantStock: 8, wantMovements: []int{3}},
+		{name: "records a stock count correction", change: model.StockChange{Quantity: -2, Reason: model.StockMovementReasonAdjustment},
+			wantStock: 3, wantMovements: []int{-2}},
+		{name: "rejects stock below zero", change: model.StockChange{Quantity: -6, Reason: model.StockMovementReasonManual},
+			wantErr: model.ErrProductStockNegative, wantStock: 5},
+		{name: "rejects an unknown reason", change: model.StockChange{Quantity: 1, Reason: "gift"},
+			wantErr: model.ErrStockMovementReasonInvalid, wantStock: 5},
+		{name: "reverts the stock when the ledger fails", change: model.StockChange{Quantity: 3, Reason: model.StockMovementReasonManual},
+			failAppend: true, wantErr: errLedgerDown, wantStock: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products := newMemoryProductRepo(stockTestProduct(t, "p1", 5))
+			movements := &memoryMovementRepo{failAppend: tt.failAppend}
+			svc := NewProductService(products, nil, movements, nil, nil, nil, nil)
+
+			err := svc.UpdateStock(context.Background(), "p1", tt.change)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantStock, products.products["p1"].Stock)
+			var quantities []int
+			for _, movement := range movements.movements {
+				assert.Equal(t, tt.change.Reason, movement.Reason)
+				quantities = append(quantities, movement.Quantity)
+			}
+			assert.Equal(t, tt.wantMovements, quantities)
+		})
+	}
+}
+
+func TestProductServiceReconcileStock(t *testing.T) {
+	opened := func(id string, quantities ...int) []*model.StockMovement {
+		movements := []*model.StockMovement{{ProductID: id, Quantity: quantities[0], Reason: model.StockMovementReasonInitial}}
+		for _, quantity := range quantities[1:] {
+			movements = append(movements, &model.StockMovement{ProductID: id, Quantity: quantity, Reason: model.StockMovementReasonManual})
+		}
+		return movements
+	}
+
+	tests := []struct {
+		name        string
+		stock       int
+		ledger      []*model.StockMovement
+		wantDrifts  []model.StockDrift
+		wantOpening []int // quantities of the initial movements appended
+	}{
+		{name: "ledger matches the stock", stock: 7, ledger: opened("p1", 5, 4, -2)},
+		{name: "reports a drift", stock: 9, ledger: opened("p1", 5, 2),
+			wantDrifts: []model.StockDrift{{ProductID: "p1", Stock: 9, LedgerStock: 7}}},
+		{name: "opens the ledger of a product without one", stock: 6,
+			wantOpening: []int{6}},
+		{name: "opens the ledger with the stock it does not explain", stock: 6,
+			ledger:      []*model.StockMovement{{ProductID: "p1", Quantity: 2, Reason: model.StockMovementReasonOrder}},
+			wantOpening: []int{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products := newMemoryProductRepo(stockTestProduct(t, "p1", tt.stock))
+			movements := &memoryMovementRepo{movements: tt.ledger}
+			svc := NewProductService(products, nil, movements, nil, nil, nil, nil)
+
+			drifts, checked, err := svc.ReconcileStock(context.Background(), 0, 10)
+			require.NoError(t, err)
+
+			assert.Equal(t, 1, checked)
+			assert.Equal(t, tt.wantDrifts, drifts)
+			var opening []int
+			for _, movement := range movements.movements[len(tt.ledger):] {
+				assert.Equal(t, model.StockMovementReasonInitial, movement.Reason)
+				opening = append(opening, movement.Quantity)
+			}
+			assert.Equal(t, tt.wantOpening, opening)
+
+			// The opened ledger matches the stock, so a second run appends nothing
+			count := len(movements.movements)
+			_, _, err = svc.ReconcileStock(context.Background(), 0, 10)
+			require.NoError(t, err)
+			assert.Len(t, movements.movements, count)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+// IReservationService defines the interface for stock reservation operations
+type IReservationService interface {
+	Hold(ctx context.Context, key string, items []model.StockHoldItem, ttl time.Duration) (*model.StockHold, error)
+	Get(ctx context.Context, key string) (*model.StockHold, error)
+	Release(ctx context.Context, key string) error
+	Commit(ctx context.Context, key string) error
+	Availability(ctx context.Context, productID string) ([]model.StockAvailability, error)
+	ReleaseExpired(ctx context.Context, limit int) (int, error)
+}
+
+// ReservationService implements IReservationService.
+// Holds set stock aside for a cart or an order without decrementing it: the available stock
+// of a SKU is its stock minus its active holds. Hold keys are scoped to the tenant of ctx.
+type ReservationService struct {
+	store          repo.IStockHoldStore
+	productService IProductService
+	defaultTTL     time.Duration
+	eventBus       event.EventBus
+}
+
+// NewReservationService creates a new reservation service. Holds requested without a TTL last defaultTTL.
+func NewReservationService(store repo.IStockHoldStore, productService IProductService, defaultTTL time.Duration, eventBus event.EventBus) *ReservationService {
+	if eventBus == nil {
+		eventBus = event.NewNoopEventBus()
+	}
+	return &ReservationService{
+		store:          store,
+		productService: productService,
+		defaultTTL:     defaultTTL,
+		eventBus:       eventBus,
+	}
+}
+
+// Hold sets the items aside under key for ttl, or the default TTL when ttl is zero.
+// It fails with model.ErrProductInsufficientStock when an item exceeds the available stock.
+func (s *ReservationService) Hold(ctx context.Context, key string, items []model.StockHoldItem, ttl time.Duration) (*model.StockHold, error) {
+	if ttl == 0 {
+		ttl = s.defaultTTL
+	}
+
+	hold, err := model.NewStockHold(key, items, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	stock := make([]int, len(hold.Items))
+	for i, item := range hold.Items {
+		product, err := s.productService.Get(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil {
+			return nil, model.ErrProductNotFound
+		}
+		if stock[i], err = product.StockOf(item.SKU); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.store.Create(ctx, hold, stock); err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, hold)
+
+	return hold, nil
+}
+
+// Get retrieves an active hold by key, nil when there is none
+func (s *ReservationService) Get(ctx context.Context, key string) (*model.StockHold, error) {
+	hold, err := s.store.Get(ctx, key)
+	if err != nil || hold == nil {
+		return nil, err
+	}
+	if hold.Expired(time.Now()) {
+		return nil, nil
+	}
+	return hold, nil
+}
+
+// Release gives up a hold, making its stock available again
+func (s *ReservationService) Release(ctx context.Context, key string) error {
+	hold, err := s.store.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+	if hold == nil {
+		return model.ErrStockHoldNotFound
+	}
+
+	reason := model.StockHoldReleased
+	if hold.Expired(time.Now()) {
+		reason = model.StockHoldExpired
+	}
+	hold.Release(reason)
+	s.publishEvents(ctx, hold)
+
+	return nil
+}
+
+// Commit removes a hold once its stock has been decremented permanently.
+// A hold that already expired and was deleted is ignored.
+func (s *ReservationService) Commit(ctx context.Context, key string) error {
+	hold, err := s.store.Delete(ctx, key)
+	if err != nil || hold == nil {
+		return err
+	}
+
+	hold.Release(model.StockHoldCommitted)
+	s.publishEvents(ctx, hold)
+
+	return nil
+}
+
+// Availability returns the stock, held and available quantities of every SKU of a product
+func (s *ReservationService) Availability(ctx context.Context, productID string) ([]model.StockAvailability, error) {
+	product, err := s.productService.Get(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, model.ErrProductNotFound
+	}
+
+	levels := product.StockLevels()
+	skus := make([]string, 0, len(levels))
+	for sku := range levels {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+
+	held, err := s.store.HeldQuantities(ctx, productID, skus)
+	if err != nil {
+		return nil, err
+	}
+
+	availability := make([]model.StockAvailability, len(skus))
+	for i, sku := range skus {
+		availability[i] = model.StockAvailability{
+			SKU:       sku,
+			Stock:     levels[sku],
+			Held:      held[sku],
+			Available: levels[sku] - held[sku],
+		}
+	}
+	return availability, nil
+}
+
+// ReleaseExpired deletes up to limit expired holds and returns how many were released.
+// Expired holds no longer set stock aside, this only cleans them up and publishes their release.
+func (s *ReservationService) ReleaseExpired(ctx context.Context, limit int) (int, error) {
+	now := time.Now()
+	refs, err := s.store.ListExpired(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, ref := range refs {
+		// The hold and its release event belong to the tenant that took it
+		holdCtx := model.ContextWithTenant(ctx, ref.TenantID)
+		hold, err := s.store.DeleteExpired(holdCtx, ref.Key, now)
+		if err != nil {
+			return released, err
+		}
+		if hold == nil {
+			continue
+		}
+
+		hold.Release(model.StockHoldExpired)
+		s.publishEvents(holdCtx, hold)
+		released++
+	}
+	return released, nil
+}
+
+// publishEvents publishes all pending domain events from the hold
+func (s *ReservationService) publishEvents(ctx context.Context, hold *model.StockHold) {
+	for _, domainEvent := range hold.Events() {
+		evt := event.NewBaseEvent(
+			domainEvent.EventName(),
+			hold.Key,
+			domainEvent,
+		)
+		if err := s.eventBus.Publish(ctx, evt); err != nil {
+			log.SugaredLogger.Errorf("Failed to publish event %s: %v", domainEvent.EventName(), err)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+// IReturnService defines the interface for return (RMA) service operations
+type IReturnService interface {
+	Request(ctx context.Context, orderID string, items []model.ReturnItem, reason string) (*model.ReturnRequest, error)
+	Approve(ctx context.Context, id string) (*model.ReturnRequest, error)
+	Reject(ctx context.Context, id string, reason string) (*model.ReturnRequest, error)
+	Receive(ctx context.Context, id string) (*model.ReturnRequest, error)
+	Refund(ctx context.Context, id string) (*model.Refund, error)
+	Get(ctx context.Context, id string) (*model.ReturnRequest, error)
+	ListByOrderID(ctx context.Context, orderID string) ([]*model.ReturnRequest, error)
+	ListRefundsByOrderID(ctx context.Context, orderID string) ([]*model.Refund, error)
+}
+
+// ReturnService implements IReturnService
+type ReturnService struct {
+	repo           repo.IReturnRepo
+	refundRepo     repo.IRefundRepo
+	orderRepo      repo.IOrderRepo
+	productService IProductService
+	paymentService IPaymentService
+	eventBus       event.EventBus
+}
+
+// NewReturnService creates a new return service.
+// paymentService may be nil, in which case refunds are only recorded and settled outside the gateway.
+func NewReturnService(repo repo.IReturnRepo, refundRepo repo.IRefundRepo, orderRepo repo.IOrderRepo, productService IProductService, paymentService IPaymentService, eventBus event.EventBus) *ReturnService {
+	if eventBus == nil {
+		eventBus = event.NewNoopEventBus()
+	}
+	return &ReturnService{
+		repo:           repo,
+		refundRepo:     refundRepo,
+		orderRepo:      orderRepo,
+		productService: productService,
+		paymentService: paymentService,
+		eventBus:       eventBus,
+	}
+}
+
+// Request opens a return request for items of a delivered order
+func (s *ReturnService) Request(ctx context.Context, orderID string, items []model.ReturnItem, reason string) (*model.ReturnRequest, error) {
+	order, err := s.orderRepo.GetByID(ctx, nil, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, model.ErrOrderNotFound
+	}
+
+	existing, err := s.repo.ListByOrderID(ctx, nil, orderID)
+	if err != nil {
+		return nil, err
+	}
+	returnedQty := returnedQuantities(existing, (*model.ReturnRequest).CountsTowardsOrder)
+
+	rma, err := model.NewReturnRequest(order, items, reason, returnedQty)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.Create(ctx, nil, rma); err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, rma.ID, rma.Events())
+
+	return rma, nil
+}
+
+// Approve approves a requested return
+func (s *ReturnService) Approve(ctx context.Context, id string) (*model.ReturnRequest, error) {
+	rma, err := s.getReturn(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rma.Approve(); err != nil {
+		return nil, err
+	}
+
+	return rma, s.save(ctx, rma)
+}
+
+// Reject rejects a requested return, releasing its quantities for new requests
+func (s *ReturnService) Reject(ctx context.Context, id string, reason string) (*model.ReturnRequest, error) {
+	rma, err := s.getReturn(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rma.Reject(reason); err != nil {
+		return nil, err
+	}
+
+	return rma, s.save(ctx, rma)
+}
+
+// Receive marks the returned goods as received, restocks them in the warehouses they were shipped
+// from and updates the order status
+func (s *ReturnService) Receive(ctx context.Context, id string) (*model.ReturnRequest, error) {
+	rma, err := s.getReturn(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rma.Receive(); err != nil {
+		return nil, err
+	}
+
+	if err := s.save(ctx, rma); err != nil {
+		return nil, err
+	}
+
+	// The goods are already back, so restock and order failures are logged rather than returned
+	s.restock(ctx, rma)
+	s.markOrderReturned(ctx, rma.OrderID)
+
+	return rma, nil
+}
+
+// Refund refunds a received return through the order's captured payment, if any, and records the refund.
+// The return is claimed as refunding before the money moves, so concurrent or retried requests cannot
+// refund it twice. A return left refunding after a failed write needs a manual check, not another refund.
+func (s *ReturnService) Refund(ctx context.Context, id string) (*model.Refund, error) {
+	rma, err := s.getReturn(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentID, err := s.capturedPaymentID(ctx, rma.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rma.StartRefund(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, nil, rma); err != nil {
+		return nil, err
+	}
+
+	refund := model.NewRefund(rma, paymentID)
+	if paymentID != "" && refund.Amount > 0 {
+		if _, err := s.paymentService.Refund(ctx, paymentID, refund.Amount); err != nil {
+			s.abortRefund(ctx, rma)
+			return nil, err
+		}
+	}
+
+	if _, err := s.refundRepo.Create(ctx, nil, refund); err != nil {
+		return nil, err
+	}
+
+	if err := rma.Refund(refund.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.save(ctx, rma); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// Get retrieves a return request by ID
+func (s *ReturnService) Get(ctx context.Context, id string) (*model.ReturnRequest, error) {
+	rma, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil || rma == nil {
+		return nil, err
+	}
+	if ok, err := orderInTenant(ctx, s.orderRepo, rma.OrderID); err != nil || !ok {
+		return nil, err
+	}
+	return rma, nil
+}
+
+// ListByOrderID retrieves all return requests for an order
+func (s *ReturnService) ListByOrderID(ctx context.Context, orderID string) ([]*model.ReturnRequest, error) {
+	if ok, err := orderInTenant(ctx, s.orderRepo, orderID); err != nil || !ok {
+		return nil, err
+	}
+	return s.repo.ListByOrderID(ctx, nil, orderID)
+}
+
+// ListRefundsByOrderID retrieves all refunds for an order
+func (s *ReturnService) ListRefundsByOrderID(ctx context.Context, orderID string) ([]*model.Refund, error) {
+	if ok, err := orderInTenant(ctx, s.orderRepo, orderID); err != nil || !ok {
+		return nil, err
+	}
+	return s.refundRepo.ListByOrderID(ctx, nil, orderID)
+}
+
+// getReturn loads a return request or returns model.ErrReturnNotFound
+func (s *ReturnService) getReturn(ctx context.Context, id string) (*model.ReturnRequest, error) {
+	rma, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if rma == nil {
+		return nil, model.ErrReturnNotFound
+	}
+	ok, err := orderInTenant(ctx, s.orderRepo, rma.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, model.ErrReturnNotFound
+	}
+	return rma, nil
+}
+
+// save persists a status change and publishes the resulting events
+func (s *ReturnService) save(ctx context.Context, rma *model.ReturnRequest) error {
+	if err := s.repo.Update(ctx, nil, rma); err != nil {
+		return err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, rma.ID, rma.Events())
+
+	return nil
+}
+
+// abortRefund returns a return whose refund failed to received so that it can be refunded again
+func (s *ReturnService) abortRefund(ctx context.Context, rma *model.ReturnRequest) {
+	if err := rma.AbortRefund(); err != nil {
+		return
+	}
+	if err := s.repo.Update(ctx, nil, rma); err != nil {
+		log.SugaredLogger.Errorf("Failed to reopen return %s after a failed refund: %v", rma.ID, err)
+	}
+}
+
+// capturedPaymentID returns the ID of the order's captured payment, or an empty string if there is none
+func (s *ReturnService) capturedPaymentID(ctx context.Context, orderID string) (string, error) {
+	if s.paymentService == nil {
+		return "", nil
+	}
+
+	payments, err := s.paymentService.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range payments {
+		if p.Status == model.PaymentStatusCaptured {
+			return p.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// restock puts the received items back in the warehouses their order items were allocated from.
+// Units taken back by earlier returns of the order are skipped, so that successive partial returns
+// refill the allocations in turn. Failures are logged rather than returned.
+func (s *ReturnService) restock(ctx context.Context, rma *model.ReturnRequest) {
+	orderItems := make(map[string]model.OrderItem)
+	returned := make(map[string]int)
+
+	order, err := s.orderRepo.GetByID(ctx, nil, rma.OrderID)
+	if err != nil || order == nil {
+		log.SugaredLogger.Errorf("Failed to load order %s for restocking return %s: %v", rma.OrderID, rma.ID, err)
+	} else {
+		for _, item := range order.Items {
+			orderItems[item.ID] = item
+		}
+		returns, err := s.repo.ListByOrderID(ctx, nil, rma.OrderID)
+		if err != nil {
+			log.SugaredLogger.Errorf("Failed to list returns of order %s: %v", rma.OrderID, err)
+		}
+		returned = returnedQuantities(returns, func(r *model.ReturnRequest) bool {
+			return r.ID != rma.ID && r.IsReceived()
+		})
+	}
+
+	for _, item := range rma.Items {
+		orderItem := orderItems[item.OrderItemID]
+		for _, allocation := range orderItem.ReturnAllocations(returned[item.OrderItemID], item.Quantity) {
+			if err := s.productService.UpdateStock(ctx, item.ProductID, model.StockChange{
+				SKU:         item.SKU,
+				WarehouseID: allocation.WarehouseID,
+				Quantity:    allocation.Quantity,
+				Reason:      model.StockMovementReasonReturn,
+				ReferenceID: rma.ID,
+			}); err != nil {
+				log.SugaredLogger.Errorf("Failed to restock product %s for return %s: %v", item.ProductID, rma.ID, err)
+			}
+		}
+		returned[item.OrderItemID] += item.Quantity
+	}
+}
+
+// markOrderReturned moves the order to partially or fully returned based on its received returns
+func (s *ReturnService) markOrderReturned(ctx context.Context, orderID string) {
+	order, err := s.orderRepo.GetByID(ctx, nil, orderID)
+	if err != nil || order == nil {
+		log.SugaredLogger.Errorf("Failed to load order %s for return: %v", orderID, err)
+		return
+	}
+
+	returns, err := s.repo.ListByOrderID(ctx, nil, orderID)
+	if err != nil {
+		log.SugaredLogger.Errorf("Failed to list returns of order %s: %v", orderID, err)
+		return
+	}
+	received := returnedQuantities(returns, (*model.ReturnRequest).IsReceived)
+
+	fully := true
+	for _, item := range order.Items {
+		if received[item.ID] < item.Quantity {
+			fully = false
+			break
+		}
+	}
+
+	oldStatus := order.Status
+	if err := order.MarkReturned(fully); err != nil {
+		log.SugaredLogger.Warnf("Received return could not update order %s in status %s", orderID, order.Status)
+		return
+	}
+	if order.Status == oldStatus {
+		return
+	}
+
+	if err := s.orderRepo.UpdateStatus(ctx, nil, order.ID, order.Status, order.Version); err != nil {
+		log.SugaredLogger.Errorf("Failed to update order %s after return: %v", orderID, err)
+		return
+	}
+
+	s.publishEvents(ctx, order.ID, order.Events())
+}
+
+// returnedQuantities sums the returned quantity per order item over the returns matching the filter
+func returnedQuantities(returns []*model.ReturnRequest, include func(*model.ReturnRequest) bool) map[string]int {
+	quantities := make(map[string]int)
+	for _, rma := range returns {
+		if !include(rma) {
+			continue
+		}
+		for _, item := range rma.Items {
+			quantities[item.OrderItemID] += item.Quantity
+		}
+	}
+	return quantities
+}
+
+// publishEvents publishes domain events for the given aggregate
+func (s *ReturnService) publishEvents(ctx context.Context, aggregateID string, events []model.DomainEvent) {
+	for _, domainEvent := range events {
+		evt := event.NewBaseEvent(
+			domainEvent.EventName(),
+			aggregateID,
+			domainEvent,
+		)
+		if err := s.eventBus.Publish(ctx, evt); err != nil {
+			log.SugaredLogger.Errorf("Failed to publish event %s: %v", domainEvent.EventName(), err)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// memoryReturnRepo keeps return requests in memory and applies updates with a version check
+type memoryReturnRepo struct {
+	returns map[string]*model.ReturnRequest
+}
+
+func newMemoryReturnRepo(returns ...*model.ReturnRequest) *memoryReturnRepo {
+	r := &memoryReturnRepo{returns: map[string]*model.ReturnRequest{}}
+	for _, rma := range returns {
+		r.returns[rma.ID] = rma
+	}
+	return r
+}
+
+func (r *memoryReturnRepo) Create(_ context.Context, _ repo.Transaction, rma *model.ReturnRequest) (*model.ReturnRequest, error) {
+	clone := *rma
+	r.returns[rma.ID] = &clone
+	return rma, nil
+}
+
+func (r *memoryReturnRepo) Update(_ context.Context, _ repo.Transaction, rma *model.ReturnRequest) error {
+	stored, ok := r.returns[rma.ID]
+	if !ok || stored.Version != rma.Version {
+		return model.ErrVersionConflict
+	}
+	rma.Version++
+	clone := *rma
+	r.returns[rma.ID] = &clone
+	return nil
+}
+
+func (r *memoryReturnRepo) GetByID(_ context.Context, _ repo.Transaction, id string) (*model.ReturnRequest, error) {
+	rma, ok := r.returns[id]
+	if !ok {
+		return nil, nil
+	}
+	clone := *rma
+	return &clone, nil
+}
+
+func (r *memoryReturnRepo) ListByOrderID(_ context.Context, _ repo.Transaction, orderID string) ([]*model.ReturnRequest, error) {
+	var returns []*model.ReturnRequest
+	for _, rma := range r.returns {
+		if rma.OrderID == orderID {
+			clone := *rma
+			returns = append(returns, &clone)
+		}
+	}
+	return returns, nil
+}
+
+// memoryRefundRepo records refunds in memory
+type memoryRefundRepo struct {
+	repo.IRefundRepo
+
+	refunds []*model.Refund
+}
+
+func (r *memoryRefundRepo) Create(_ context.Context, _ repo.Transaction, refund *model.Refund) (*model.Refund, error) {
+	r.refunds = append(r.refunds, refund)
+	return refund, nil
+}
+
+// restockRecorder records the stock changes it receives
+type restockRecorder struct {
+	IProductService
+
+	changes []model.StockChange
+}
+
+func (r *restockRecorder) UpdateStock(_ context.Context, _ string, change model.StockChange) error {
+	r.changes = append(r.changes, change)
+	return nil
+}
+
+func returnTestOrder(status model.OrderStatus) *model.Order {
+	return &model.Order{
+		ID:      "o1",
+		UserID:  "u1",
+		Status:  status,
+		Total:   100,
+		Version: 1,
+		Items: []model.OrderItem{{
+			ID: "item-1", ProductID: "p1", Quantity: 4, Price: 25,
+			Allocations: []model.StockAllocation{{WarehouseID: "w1", Quantity: 1}, {WarehouseID: "w2", Quantity: 3}},
+		}},
+	}
+}
+
+func returnTestRequest(id string, status model.ReturnStatus, quantity int) *model.ReturnRequest {
+	return &model.ReturnRequest{
+		ID:           id,
+		OrderID:      "o1",
+		Status:       status,
+		RefundAmount: float64(quantity) * 25,
+		Version:      1,
+		Items:        []model.ReturnItem{{OrderItemID: "item-1", ProductID: "p1", Quantity: quantity, Price: 25}},
+	}
+}
+
+func TestReturnServiceReceive(t *testing.T) {
+	tests := []struct {
+		name            string
+		earlier         *model.ReturnRequest
+		quantity        int
+		wantChanges     []model.StockChange
+		wantOrderStatus model.OrderStatus
+	}{
+		{
+			name:     "restocks the warehouses the items came from",
+			quantity: 2,
+			wantChanges: []model.StockChange{
+				{WarehouseID: "w1", Quantity: 1, Reason: model.StockMovementReasonReturn, ReferenceID: "rma-1"},
+				{WarehouseID: "w2", Quantity: 1, Reason: model.StockMovementReasonReturn, ReferenceID: "rma-1"},
+			},
+			wantOrderStatus: model.OrderStatusPartiallyReturned,
+		},
+		{
+			name:     "skips the units of earlier received returns",
+			earlier:  returnTestRequest("rma-0", model.ReturnStatusRefunded, 2),
+			quantity: 2,
+			wantChanges: []model.StockChange{
+				{WarehouseID: "w2", Quantity: 2, Reason: model.StockMovementReasonReturn, ReferenceID: "rma-1"},
+			},
+			wantOrderStatus: model.OrderStatusReturned,
+		},
+		{
+			name:     "ignores earlier returns that were not received",
+			earlier:  returnTestRequest("rma-0", model.ReturnStatusApproved, 2),
+			quantity: 1,
+			wantChanges: []model.StockChange{
+				{WarehouseID: "w1", Quantity: 1, Reason: model.StockMovementReasonReturn, ReferenceID: "rma-1"},
+			},
+			wantOrderStatus: model.OrderStatusPartiallyReturned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := newMemoryOrderRepo(returnTestOrder(model.OrderStatusDelivered))
+			returns := newMemoryReturnRepo(returnTestRequest("rma-1", model.ReturnStatusApproved, tt.quantity))
+			if tt.earlier != nil {
+				returns.returns[tt.earlier.ID] = tt.earlier
+			}
+			products := &restockRecorder{}
+			svc := NewReturnService(returns, &memoryRefundRepo{}, orders, products, nil, nil)
+
+			rma, err := svc.Receive(context.Background(), "rma-1")
+			require.NoError(t, err)
+
+			assert.Equal(t, model.ReturnStatusReceived, rma.Status)
+			assert.Equal(t, tt.wantChanges, products.changes)
+			assert.Equal(t, tt.wantOrderStatus, orders.orders["o1"].Status)
+		})
+	}
+}
+
+func TestReturnServiceRefund(t *testing.T) {
+	tests := []struct {
+		name         string
+		payment      *model.Payment
+		status       model.ReturnStatus
+		wantErr      error
+		wantRefunded float64
+		wantPayment  string
+	}{
+		{
+			name:         "refunds the return amount through the captured payment",
+			payment:      testPayment("p1", "o1", model.PaymentStatusCaptured, 0),
+			status:       model.ReturnStatusReceived,
+			wantRefunded: 50,
+			wantPayment:  "p1",
+		},
+		{
+			name:    "records the refund without a payment",
+			payment: testPayment("p1", "o1", model.PaymentStatusVoided, 0),
+			status:  model.ReturnStatusReceived,
+		},
+		{
+			name:    "return not received yet",
+			payment: testPayment("p1", "o1", model.PaymentStatusCaptured, 0),
+			status:  model.ReturnStatusApproved,
+			wantErr: model.ErrReturnInvalidStatus,
+		},
+		{
+			name:    "return already refunded",
+			payment: testPayment("p1", "o1", model.PaymentStatusCaptured, 0),
+			status:  model.ReturnStatusRefunded,
+			wantErr: model.ErrReturnInvalidStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := newMemoryOrderRepo(returnTestOrder(model.OrderStatusPartiallyReturned))
+			returns := newMemoryReturnRepo(returnTestRequest("rma-1", tt.status, 2))
+			refunds := &memoryRefundRepo{}
+			gateway := newRecordingGateway()
+			payments := NewPaymentService(newMemoryPaymentRepo(tt.payment), orders, nil, gateway, nil)
+			svc := NewReturnService(returns, refunds, orders, &restockRecorder{}, payments, nil)
+
+			refund, err := svc.Refund(context.Background(), "rma-1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, refunds.refunds)
+				assert.Zero(t, gateway.refunded["ref-p1"])
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, 50.0, refund.Amount)
+			assert.Equal(t, tt.wantPayment, refund.PaymentID)
+			assert.Equal(t, tt.wantRefunded, gateway.refunded["ref-p1"])
+			assert.Len(t, refunds.refunds, 1)
+			assert.Equal(t, model.ReturnStatusRefunded, returns.returns["rma-1"].Status)
+		})
+	}
+}
@@ -6,11 +6,24 @@ import (
 
 // Services contains all service instances
 type Services struct {
-	UserService    IUserService
-	ProductService IProductService
-	OrderService   IOrderService
-	AuditService   IAuditService
-	EventBus       event.EventBus
+	UserService         IUserService
+	AuthService         IAuthService
+	LockoutService      ILockoutService
+	AccountService      IAccountService
+	APIKeyService       IAPIKeyService
+	ProductService      IProductService
+	CategoryService     ICategoryService
+	WarehouseService    IWarehouseService
+	ReservationService  IReservationService
+	UserAddressService  IUserAddressService
+	OrganizationService IOrganizationService
+	OrderService        IOrderService
+	PaymentService      IPaymentService
+	ReturnService       IReturnService
+	ShipmentService     IShipmentService
+	InvoiceService      IInvoiceService
+	AuditService        IAuditService
+	EventBus            event.EventBus
 }
 
 // NewServices creates a services collection
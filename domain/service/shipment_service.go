@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+// IShipmentService defines the interface for shipment service operations
+type IShipmentService interface {
+	Create(ctx context.Context, orderID, carrier, carrierService, trackingNumber string, items []model.ShipmentItem) (*model.Shipment, error)
+	Ship(ctx context.Context, id, trackingNumber string) (*model.Shipment, error)
+	Deliver(ctx context.Context, id string, orderItemIDs []string) (*model.Shipment, error)
+	Get(ctx context.Context, id string) (*model.Shipment, error)
+	ListByOrderID(ctx context.Context, orderID string) ([]*model.Shipment, error)
+	QuoteRates(ctx context.Context, orderID string) ([]model.ShippingRate, error)
+}
+
+// ShipmentService implements IShipmentService
+type ShipmentService struct {
+	repo      repo.IShipmentRepo
+	orderRepo repo.IOrderRepo
+	rates     repo.ICarrierRateProvider
+	eventBus  event.EventBus
+}
+
+// NewShipmentService creates a new shipment service
+func NewShipmentService(repo repo.IShipmentRepo, orderRepo repo.IOrderRepo, rates repo.ICarrierRateProvider, eventBus event.EventBus) *ShipmentService {
+	if eventBus == nil {
+		eventBus = event.NewNoopEventBus()
+	}
+	return &ShipmentService{
+		repo:      repo,
+		orderRepo: orderRepo,
+		rates:     rates,
+		eventBus:  eventBus,
+	}
+}
+
+// Create packs items of a confirmed order into a new shipment priced from the carrier rate table
+func (s *ShipmentService) Create(ctx context.Context, orderID, carrier, carrierService, trackingNumber string, items []model.ShipmentItem) (*model.Shipment, error) {
+	order, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.ListByOrderID(ctx, nil, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment, err := model.NewShipment(order, carrier, carrierService, trackingNumber, items, allocatedQuantities(existing))
+	if err != nil {
+		return nil, err
+	}
+
+	shipment.Cost, err = s.quote(ctx, carrier, carrierService, shippedUnits(shipment.Items))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.Create(ctx, nil, shipment); err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, shipment.ID, shipment.Events())
+
+	return shipment, nil
+}
+
+// Ship hands a shipment over to the carrier and updates the order status.
+// Shipments of orders cancelled after they were packed stay in the warehouse.
+func (s *ShipmentService) Ship(ctx context.Context, id, trackingNumber string) (*model.Shipment, error) {
+	shipment, err := s.getShipment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.getOrder(ctx, shipment.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.IsShippable() {
+		return nil, model.ErrOrderNotShippable
+	}
+
+	if err := shipment.Ship(trackingNumber); err != nil {
+		return nil, err
+	}
+
+	if err := s.save(ctx, shipment); err != nil {
+		return nil, err
+	}
+
+	s.syncOrder(ctx, shipment.OrderID)
+
+	return shipment, nil
+}
+
+// Deliver marks items of a shipment as delivered and updates the order status
+func (s *ShipmentService) Deliver(ctx context.Context, id string, orderItemIDs []string) (*model.Shipment, error) {
+	shipment, err := s.getShipment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := shipment.Deliver(orderItemIDs...); err != nil {
+		return nil, err
+	}
+
+	if err := s.save(ctx, shipment); err != nil {
+		return nil, err
+	}
+
+	s.syncOrder(ctx, shipment.OrderID)
+
+	return shipment, nil
+}
+
+// Get retrieves a shipment by ID
+func (s *ShipmentService) Get(ctx context.Context, id string) (*model.Shipment, error) {
+	shipment, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil || shipment == nil {
+		return nil, err
+	}
+	if ok, err := orderInTenant(ctx, s.orderRepo, shipment.OrderID); err != nil || !ok {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+// ListByOrderID retrieves all shipments for an order
+func (s *ShipmentService) ListByOrderID(ctx context.Context, orderID string) ([]*model.Shipment, error) {
+	if ok, err := orderInTenant(ctx, s.orderRepo, orderID); err != nil || !ok {
+		return nil, err
+	}
+	return s.repo.ListByOrderID(ctx, nil, orderID)
+}
+
+// QuoteRates returns the carrier rates for shipping the items of an order not yet packed
+func (s *ShipmentService) QuoteRates(ctx context.Context, orderID string) ([]model.ShippingRate, error) {
+	order, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.ListByOrderID(ctx, nil, orderID)
+	if err != nil {
+		return nil, err
+	}
+	allocated := allocatedQuantities(existing)
+
+	remaining := 0
+	for _, item := range order.Items {
+		if left := item.Quantity - allocated[item.ID]; left > 0 {
+			remaining += left
+		}
+	}
+
+	return s.rates.Quote(ctx, remaining)
+}
+
+// quote returns the price of the carrier service for the given number of units
+func (s *ShipmentService) quote(ctx context.Context, carrier, carrierService string, units int) (float64, error) {
+	rates, err := s.rates.Quote(ctx, units)
+	if err != nil {
+		return 0, err
+	}
+	for _, rate := range rates {
+		if rate.Carrier == carrier && (carrierService == "" || rate.Service == carrierService) {
+			return rate.Amount, nil
+		}
+	}
+	return 0, model.ErrShippingRateNotFound
+}
+
+// syncOrder derives the order status from all of its shipments.
+// The shipment change is already stored, so failures are logged rather than returned.
+func (s *ShipmentService) syncOrder(ctx context.Context, orderID string) {
+	order, err := s.orderRepo.GetByID(ctx, nil, orderID)
+	if err != nil || order == nil {
+		log.SugaredLogger.Errorf("Failed to load order %s for shipment: %v", orderID, err)
+		return
+	}
+
+	shipments, err := s.repo.ListByOrderID(ctx, nil, orderID)
+	if err != nil {
+		log.SugaredLogger.Errorf("Failed to list shipments of order %s: %v", orderID, err)
+		return
+	}
+
+	oldStatus := order.Status
+	if err := order.ApplyShipments(shipments); err != nil {
+		log.SugaredLogger.Warnf("Shipment could not update order %s in status %s", orderID, order.Status)
+		return
+	}
+	if order.Status == oldStatus {
+		return
+	}
+
+	if err := s.orderRepo.UpdateStatus(ctx, nil, order.ID, order.Status, order.Version); err != nil {
+		log.SugaredLogger.Errorf("Failed to update order %s after shipment: %v", orderID, err)
+		return
+	}
+
+	s.publishEvents(ctx, order.ID, order.Events())
+}
+
+// getOrder loads an order or returns model.ErrOrderNotFound
+func (s *ShipmentService) getOrder(ctx context.Context, id string) (*model.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, model.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// getShipment loads a shipment or returns model.ErrShipmentNotFound
+func (s *ShipmentService) getShipment(ctx context.Context, id string) (*model.Shipment, error) {
+	shipment, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if shipment == nil {
+		return nil, model.ErrShipmentNotFound
+	}
+	ok, err := orderInTenant(ctx, s.orderRepo, shipment.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, model.ErrShipmentNotFound
+	}
+	return shipment, nil
+}
+
+// save persists a status change and publishes the resulting events
+func (s *ShipmentService) save(ctx context.Context, shipment *model.Shipment) error {
+	if err := s.repo.Update(ctx, nil, shipment); err != nil {
+		return err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, shipment.ID, shipment.Events())
+
+	return nil
+}
+
+// allocatedQuantities sums the quantity per order item already packed in shipments
+func allocatedQuantities(shipments []*model.Shipment) map[string]int {
+	quantities := make(map[string]int)
+	for _, shipment := range shipments {
+		for _, item := range shipment.Items {
+			quantities[item.OrderItemID] += item.Quantity
+		}
+	}
+	return quantities
+}
+
+// shippedUnits counts the units packed in the given shipment items
+func shippedUnits(items []model.ShipmentItem) int {
+	units := 0
+	for _, item := range items {
+		units += item.Quantity
+	}
+	return units
+}
+
+// publishEvents publishes domain events for the given aggregate
+func (s *ShipmentService) publishEvents(ctx context.Context, aggregateID string, events []model.DomainEvent) {
+	for _, domainEvent := range events {
+		evt := event.NewBaseEvent(
+			domainEvent.EventName(),
+			aggregateID,
+			domainEvent,
+		)
+		if err := s.eventBus.Publish(ctx, evt); err != nil {
+			log.SugaredLogger.Errorf("Failed to publish event %s: %v", domainEvent.EventName(), err)
+		}
+	}
+}
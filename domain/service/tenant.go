@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// orderInTenant reports whether an order is visible in the tenant of ctx. Payments, shipments and
+// returns have no tenant of their own and are scoped through their order, which is always looked
+// up: a context without a tenant sees the order only when it opted out with
+// model.ContextWithoutTenant.
+func orderInTenant(ctx context.Context, orderRepo repo.IOrderRepo, orderID string) (bool, error) {
+	order, err := orderRepo.GetByID(ctx, nil, orderID)
+	if err != nil {
+		return false, err
+	}
+	return order != nil, nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+// IUserAddressService defines the interface for address book service operations
+type IUserAddressService interface {
+	Create(ctx context.Context, userID, label string, address vo.Address, defaultShipping, defaultBilling bool) (*model.UserAddress, error)
+	Get(ctx context.Context, userID, id string) (*model.UserAddress, error)
+	List(ctx context.Context, userID string) ([]*model.UserAddress, error)
+	Update(ctx context.Context, userID, id, label string, address vo.Address, defaultShipping, defaultBilling bool, expectedVersion int) (*model.UserAddress, error)
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// UserAddressService implements IUserAddressService
+type UserAddressService struct {
+	repo     repo.IUserAddressRepo
+	userRepo repo.IUserRepo
+	eventBus event.EventBus
+}
+
+// NewUserAddressService creates a new address book service
+func NewUserAddressService(repo repo.IUserAddressRepo, userRepo repo.IUserRepo, eventBus event.EventBus) *UserAddressService {
+	if eventBus == nil {
+		eventBus = event.NewNoopEventBus()
+	}
+	return &UserAddressService{
+		repo:     repo,
+		userRepo: userRepo,
+		eventBus: eventBus,
+	}
+}
+
+// Create adds an address to the address book of a user. The first address of a user is its
+// default shipping and billing address.
+func (s *UserAddressService) Create(ctx context.Context, userID, label string, address vo.Address, defaultShipping, defaultBilling bool) (*model.UserAddress, error) {
+	user, err := s.userRepo.GetByID(ctx, nil, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	existing, err := s.repo.ListByUserID(ctx, nil, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		defaultShipping, defaultBilling = true, true
+	}
+
+	entry, err := model.NewUserAddress(userID, label, address, defaultShipping, defaultBilling)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(ctx, nil, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishEvents(ctx, entry)
+
+	return created, nil
+}
+
+// Get retrieves an address of a user
+func (s *UserAddressService) Get(ctx context.Context, userID, id string) (*model.UserAddress, error) {
+	return s.load(ctx, userID, id, 0)
+}
+
+// List retrieves the addresses of a user, oldest first
+func (s *UserAddressService) List(ctx context.Context, userID string) ([]*model.UserAddress, error) {
+	return s.repo.ListByUserID(ctx, nil, userID)
+}
+
+// Update replaces an address of a user. Orders keep the address they were placed with.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *UserAddressService) Update(ctx context.Context, userID, id, label string, address vo.Address, defaultShipping, defaultBilling bool, expectedVersion int) (*model.UserAddress, error) {
+	entry, err := s.load(ctx, userID, id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := entry.Update(label, address, defaultShipping, defaultBilling); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, nil, entry); err != nil {
+		return nil, err
+	}
+
+	s.publishEvents(ctx, entry)
+
+	return entry, nil
+}
+
+// Delete removes an address from the address book of a user. Orders keep the address they were placed with.
+func (s *UserAddressService) Delete(ctx context.Context, userID, id string) error {
+	entry, err := s.load(ctx, userID, id, 0)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, nil, id); err != nil {
+		return err
+	}
+
+	entry.MarkDeleted()
+	s.publishEvents(ctx, entry)
+
+	return nil
+}
+
+// load retrieves an address of a user at the expected version
+func (s *UserAddressService) load(ctx context.Context, userID, id string, expectedVersion int) (*model.UserAddress, error) {
+	entry, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil || entry.UserID != userID {
+		return nil, model.ErrUserAddressNotFound
+	}
+
+	if err := model.CheckVersion(entry.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// publishEvents publishes all pending domain events from the address
+func (s *UserAddressService) publishEvents(ctx context.Context, entry *model.UserAddress) {
+	ctx = model.EnsureTenant(ctx, entry.TenantID)
+	for _, domainEvent := range entry.Events() {
+		evt := event.NewBaseEvent(
+			domainEvent.EventName(),
+			entry.ID,
+			domainEvent,
+		)
+		if err := s.eventBus.Publish(ctx, evt); err != nil {
+			log.SugaredLogger.Errorf("Failed to publish event %s: %v", domainEvent.EventName(), err)
+		}
+	}
+}
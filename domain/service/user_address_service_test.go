@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/vo"
+)
+
+// memoryUserAddressRepo keeps addresses in memory, oldest first, and moves the default flags like the stores do
+type memoryUserAddressRepo struct {
+	addresses []*model.UserAddress
+}
+
+func (r *memoryUserAddressRepo) Create(_ context.Context, _ repo.Transaction, address *model.UserAddress) (*model.UserAddress, error) {
+	r.clearDefaults(address)
+	clone := *address
+	r.addresses = append(r.addresses, &clone)
+	return address, nil
+}
+
+func (r *memoryUserAddressRepo) Update(_ context.Context, _ repo.Transaction, address *model.UserAddress) error {
+	index := slices.IndexFunc(r.addresses, func(a *model.UserAddress) bool { return a.ID == address.ID })
+	if index < 0 || r.addresses[index].Version != address.Version {
+		return model.ErrVersionConflict
+	}
+	address.Version++
+	clone := *address
+	r.addresses[index] = &clone
+	r.clearDefaults(address)
+	return nil
+}
+
+func (r *memoryUserAddressRepo) Delete(_ context.Context, _ repo.Transaction, id string) error {
+	r.addresses = slices.DeleteFunc(r.addresses, func(a *model.UserAddress) bool { return a.ID == id })
+	return nil
+}
+
+func (r *memoryUserAddressRepo) GetByID(_ context.Context, _ repo.Transaction, id string) (*model.UserAddress, error) {
+	for _, address := range r.addresses {
+		if address.ID == id {
+			clone := *address
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserAddressRepo) ListByUserID(_ context.Context, _ repo.Transaction, userID string) ([]*model.UserAddress, error) {
+	var addresses []*model.UserAddress
+	for _, address := range r.addresses {
+		if address.UserID == userID {
+			clone := *address
+			addresses = append(addresses, &clone)
+		}
+	}
+	return addresses, nil
+}
+
+// clearDefaults unsets the default flags the address holds on the other addresses of its user
+func (r *memoryUserAddressRepo) clearDefaults(address *model.UserAddress) {
+	for _, other := range r.addresses {
+		if other.UserID != address.UserID || other.ID == address.ID {
+			continue
+		}
+		if address.DefaultShipping {
+			other.DefaultShipping = false
+		}
+		if address.DefaultBilling {
+			other.DefaultBilling = false
+		}
+	}
+}
+
+// defaults returns the labels of the default shipping and billing addresses of a user
+func (r *memoryUserAddressRepo) defaults(userID string) (shipping, billing []string) {
+	for _, address := range r.addresses {
+		if address.UserID != userID {
+			continue
+		}
+		if address.DefaultShipping {
+			shipping = append(shipping, address.Label)
+		}
+		if address.DefaultBilling {
+			billing = append(billing, address.Label)
+		}
+	}
+	return shipping, billing
+}
+
+func testAddress(line1 string) vo.Address {
+	return vo.Address{Line1: line1, City: "São Paulo", PostalCode: "01305-000", Country: "BR"}
+}
+
+func TestUserAddressServiceDefaults(t *testing.T) {
+	type entry struct {
+		label                           string
+		defaultShipping, defaultBilling bool
+	}
+
+	tests := []struct {
+		name         string
+		create       []entry
+		update       *entry // applied to the first address when set
+		wantShipping []string
+		wantBilling  []string
+	}{
+		{
+			name:         "first address is the default for both",
+			create:       []entry{{label: "Home"}},
+			wantShipping: []string{"Home"}, wantBilling: []string{"Home"},
+		},
+		{
+			name:         "later address keeps the defaults where they are",
+			create:       []entry{{label: "Home"}, {label: "Office"}},
+			wantShipping: []string{"Home"}, wantBilling: []string{"Home"},
+		},
+		{
+			name:         "new default shipping address takes the flag over",
+			create:       []entry{{label: "Home"}, {label: "Office", defaultShipping: true}},
+			wantShipping: []string{"Office"}, wantBilling: []string{"Home"},
+		},
+		{
+			name:         "each flag moves to the last address asking for it",
+			create:       []entry{{label: "Home", defaultBilling: true}, {label: "Office", defaultBilling: true}, {label: "Cabin", defaultShipping: true}},
+			wantShipping: []string{"Cabin"}, wantBilling: []string{"Office"},
+		},
+		{
+			name:         "update takes the flag over",
+			create:       []entry{{label: "Home"}, {label: "Office", defaultShipping: true, defaultBilling: true}},
+			update:       &entry{label: "Home", defaultBilling: true},
+			wantShipping: []string{"Office"}, wantBilling: []string{"Home"},
+		},
+		{
+			name:   "update can leave the user without a default",
+			create: []entry{{label: "Home"}},
+			update: &entry{label: "Home"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addresses := &memoryUserAddressRepo{}
+			svc := NewUserAddressService(addresses, newMemoryUserRepo(&model.User{ID: "u1", Email: "jane@example.com", Version: 1}), nil)
+			ctx := context.Background()
+
+			var first *model.UserAddress
+			for i, e := range tt.create {
+				created, err := svc.Create(ctx, "u1", e.label, testAddress(e.label+" 1"), e.defaultShipping, e.defaultBilling)
+				require.NoError(t, err)
+				if i == 0 {
+					first = created
+				}
+			}
+			if tt.update != nil {
+				_, err := svc.Update(ctx, "u1", first.ID, tt.update.label, first.Address, tt.update.defaultShipping, tt.update.defaultBilling, first.Version)
+				require.NoError(t, err)
+			}
+
+			shipping, billing := addresses.defaults("u1")
+			assert.Equal(t, tt.wantShipping, shipping)
+			assert.Equal(t, tt.wantBilling, billing)
+		})
+	}
+}
+
+func TestUserAddressServiceOwnership(t *testing.T) {
+	addresses := &memoryUserAddressRepo{}
+	users := newMemoryUserRepo(
+		&model.User{ID: "u1", Email: "jane@example.com", Version: 1},
+		&model.User{ID: "u2", Email: "john@example.com", Version: 1},
+	)
+	svc := NewUserAddressService(addresses, users, nil)
+	ctx := context.Background()
+
+	home, err := svc.Create(ctx, "u1", "Home", testAddress("Rua Augusta, 100"), false, false)
+	require.NoError(t, err)
+
+	// Another user's first address does not take the defaults of u1 over
+	_, err = svc.Create(ctx, "u2", "Home", testAddress("Rua Oscar Freire, 10"), true, true)
+	require.NoError(t, err)
+	shipping, billing := addresses.defaults("u1")
+	assert.Equal(t, []string{"Home"}, shipping)
+	assert.Equal(t, []string{"Home"}, billing)
+
+	_, err = svc.Get(ctx, "u2", home.ID)
+	assert.ErrorIs(t, err, model.ErrUserAddressNotFound)
+	_, err = svc.Update(ctx, "u2", home.ID, "Mine", home.Address, true, true, 0)
+	assert.ErrorIs(t, err, model.ErrUserAddressNotFound)
+	assert.ErrorIs(t, svc.Delete(ctx, "u2", home.ID), model.ErrUserAddressNotFound)
+
+	_, err = svc.Create(ctx, "u1", "Office", vo.Address{Line1: "Av. Paulista, 1000", City: "São Paulo", Country: "BR"}, false, false)
+	assert.ErrorIs(t, err, vo.ErrAddressPostalCodeRequired)
+	_, err = svc.Create(ctx, "ghost", "Home", testAddress("Rua Augusta, 100"), false, false)
+	assert.ErrorIs(t, err, model.ErrUserNotFound)
+}
+
+func TestOrderServiceCreateAddresses(t *testing.T) {
+	tests := []struct {
+		name              string
+		organizationID    string
+		shipping, billing string // address IDs, empty for the defaults
+		wantShipping      string // first line of the address copied to the order, empty for none
+		wantBilling       string
+		wantErr           error
+	}{
+		{name: "copies the default addresses", wantShipping: "Office 1", wantBilling: "Home 1"},
+		{name: "uses the addresses named", shipping: "home", billing: "office", wantShipping: "Home 1", wantBilling: "Office 1"},
+		{name: "uses a shared address of the organization", organizationID: "org-1", shipping: "warehouse", wantShipping: "Warehouse 1", wantBilling: "Home 1"},
+		{name: "shared addresses need the organization", shipping: "warehouse", wantErr: model.ErrUserAddressNotFound},
+		{name: "rejects an address of another user", shipping: "other", wantErr: model.ErrUserAddressNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			organizations, users := organizationTestSetup(t)
+			organizations.organizations["org-1"].Addresses = []model.OrganizationAddress{{ID: "warehouse", Label: "Warehouse", Address: testAddress("Warehouse 1")}}
+			addresses := &memoryUserAddressRepo{addresses: []*model.UserAddress{
+				{ID: "home", UserID: "buyer-1", Label: "Home", Address: testAddress("Home 1"), DefaultBilling: true, Version: 1},
+				{ID: "office", UserID: "buyer-1", Label: "Office", Address: testAddress("Office 1"), DefaultShipping: true, Version: 1},
+				{ID: "other", UserID: "buyer-2", Label: "Home", Address: testAddress("Other 1"), DefaultShipping: true, DefaultBilling: true, Version: 1},
+			}}
+			svc := NewOrderService(newMemoryOrderRepo(), users, organizations, addresses, nil, nil, nil, nil, nil)
+
+			order, err := svc.Create(context.Background(), "buyer-1", tt.organizationID,
+				[]model.OrderItem{{ProductID: "p1", Quantity: 1, Price: 10}}, nil, tt.shipping, tt.billing)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, order.ShippingAddress)
+			require.NotNil(t, order.BillingAddress)
+			assert.Equal(t, tt.wantShipping, order.ShippingAddress.Line1)
+			assert.Equal(t, tt.wantBilling, order.BillingAddress.Line1)
+
+			// The order keeps its copy when the address book changes
+			addresses.addresses[1].Line1 = "Moved"
+			assert.Equal(t, tt.wantShipping, order.ShippingAddress.Line1)
+		})
+	}
+
+	t.Run("orders without an address book entry have no address", func(t *testing.T) {
+		_, users := organizationTestSetup(t)
+		svc := NewOrderService(newMemoryOrderRepo(), users, nil, &memoryUserAddressRepo{}, nil, nil, nil, nil, nil)
+		order, err := svc.Create(context.Background(), "buyer-1", "", []model.OrderItem{{ProductID: "p1", Quantity: 1, Price: 10}}, nil, "", "")
+		require.NoError(t, err)
+		assert.Nil(t, order.ShippingAddress)
+		assert.Nil(t, order.BillingAddress)
+	})
+}
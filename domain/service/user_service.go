@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"sync"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -18,28 +19,42 @@ const userServiceTracerName = "user-service"
 // IUserService defines the interface for user service operations
 type IUserService interface {
 	Create(ctx context.Context, email, name, password string) (*model.User, error)
-	Update(ctx context.Context, id string, name string) (*model.User, error)
+	Update(ctx context.Context, id string, name string, expectedVersion int) (*model.User, error)
 	Delete(ctx context.Context, id string) error
 	Get(ctx context.Context, id string) (*model.User, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	List(ctx context.Context, offset, limit int) ([]*model.User, int64, error)
+	ChangePassword(ctx context.Context, id, currentPassword, newPassword string, expectedVersion int) (*model.User, error)
+	Authenticate(ctx context.Context, email, password string) (*model.User, error)
+	AssignRoles(ctx context.Context, id string, roles []model.Role, permissions []model.Permission, expectedVersion int) (*model.User, error)
+	VerifyEmail(ctx context.Context, id string) (*model.User, error)
+	ResetPassword(ctx context.Context, id, newPassword string) (*model.User, error)
 }
 
 // UserService implements IUserService
 type UserService struct {
 	repo      repo.IUserRepo
 	txFactory repo.TransactionFactory
+	hasher    repo.IPasswordHasher
+	sessions  repo.IRefreshSessionStore
 	eventBus  event.EventBus
+
+	// dummyHash is verified when no user matches, so unknown emails take as long as wrong passwords
+	dummyHash     string
+	dummyHashOnce sync.Once
 }
 
-// NewUserService creates a new user service
-func NewUserService(repo repo.IUserRepo, txFactory repo.TransactionFactory, eventBus event.EventBus) *UserService {
+// NewUserService creates a new user service. Password changes revoke the user's refresh sessions
+// in sessions; a nil store leaves them alone.
+func NewUserService(repo repo.IUserRepo, txFactory repo.TransactionFactory, hasher repo.IPasswordHasher, sessions repo.IRefreshSessionStore, eventBus event.EventBus) *UserService {
 	if eventBus == nil {
 		eventBus = event.NewNoopEventBus()
 	}
 	return &UserService{
 		repo:      repo,
 		txFactory: txFactory,
+		hasher:    hasher,
+		sessions:  sessions,
 		eventBus:  eventBus,
 	}
 }
@@ -63,8 +78,19 @@ func (s *UserService) Create(ctx context.Context, email, name, password string)
 		return nil, model.ErrUserEmailTaken
 	}
 
+	// Hash the password, the plaintext is never stored
+	if err := model.ValidatePassword(password); err != nil {
+		return nil, err
+	}
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
 	// Create user
-	user, err := model.NewUser(email, name, password)
+	user, err := model.NewUser(email, name, hash)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -88,8 +114,9 @@ func (s *UserService) Create(ctx context.Context, email, name, password string)
 	return created, nil
 }
 
-// Update updates an existing user
-func (s *UserService) Update(ctx context.Context, id string, name string) (*model.User, error) {
+// Update updates an existing user.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *UserService) Update(ctx context.Context, id string, name string, expectedVersion int) (*model.User, error) {
 	user, err := s.repo.GetByID(ctx, nil, id)
 	if err != nil {
 		return nil, err
@@ -98,6 +125,10 @@ func (s *UserService) Update(ctx context.Context, id string, name string) (*mode
 		return nil, model.ErrUserNotFound
 	}
 
+	if err := model.CheckVersion(user.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
 	if err := user.Update(name); err != nil {
 		return nil, err
 	}
@@ -166,8 +197,242 @@ func (s *UserService) List(ctx context.Context, offset, limit int) ([]*model.Use
 	return s.repo.List(ctx, nil, offset, limit)
 }
 
+// ChangePassword replaces the password of a user after verifying the current one.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *UserService) ChangePassword(ctx context.Context, id, currentPassword, newPassword string, expectedVersion int) (*model.User, error) {
+	user, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	if err := model.CheckVersion(user.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	if ok, err := s.verify(user.Password, currentPassword); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, model.ErrInvalidCredentials
+	}
+
+	if err := model.ValidatePassword(newPassword); err != nil {
+		return nil, err
+	}
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return nil, err
+	}
+	user.UpdatePassword(hash)
+
+	if err := s.repo.Update(ctx, nil, user); err != nil {
+		return nil, err
+	}
+	if err := s.revokeSessions(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, user)
+
+	return user, nil
+}
+
+// AssignRoles replaces the roles and the directly granted permissions of a user. They apply to
+// access tokens issued afterwards, existing ones keep their roles until they expire.
+// The calling principal, when there is one, cannot change its own roles and only grants roles and
+// permissions it holds itself.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *UserService) AssignRoles(ctx context.Context, id string, roles []model.Role, permissions []model.Permission, expectedVersion int) (*model.User, error) {
+	if principal := model.PrincipalFromContext(ctx); principal != nil {
+		if principal.UserID == id {
+			return nil, model.ErrUserRoleSelfAssignment
+		}
+		if !principal.CanGrant(roles, permissions) {
+			return nil, model.ErrUserRoleGrantForbidden
+		}
+	}
+
+	user, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	if err := model.CheckVersion(user.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	if err := user.AssignRoles(roles, permissions); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, nil, user); err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, user)
+
+	return user, nil
+}
+
+// VerifyEmail marks the email address of a user as verified. Callers check the user's
+// verification token first, see AccountService.
+func (s *UserService) VerifyEmail(ctx context.Context, id string) (*model.User, error) {
+	user, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	if err := user.VerifyEmail(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, nil, user); err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, user)
+
+	return user, nil
+}
+
+// ResetPassword replaces a forgotten password without the current one. Callers check the
+// user's password reset token first, see AccountService.
+func (s *UserService) ResetPassword(ctx context.Context, id, newPassword string) (*model.User, error) {
+	if err := model.ValidatePassword(newPassword); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return nil, err
+	}
+	user.ResetPassword(hash)
+
+	if err := s.repo.Update(ctx, nil, user); err != nil {
+		return nil, err
+	}
+	if err := s.revokeSessions(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, user)
+
+	return user, nil
+}
+
+// revokeSessions signs the user out everywhere after a password change, so that sessions opened
+// with the old password do not outlive it
+func (s *UserService) revokeSessions(ctx context.Context, userID string) error {
+	if s.sessions == nil {
+		return nil
+	}
+	if err := s.sessions.RevokeByUser(ctx, userID); err != nil {
+		log.SugaredLogger.Errorf("Failed to revoke refresh sessions of user %s: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
+// Authenticate returns the user with the given email and password, or model.ErrInvalidCredentials.
+// A password hashed with outdated settings is rehashed with the current ones.
+func (s *UserService) Authenticate(ctx context.Context, email, password string) (*model.User, error) {
+	ctx, span := otel.Tracer(userServiceTracerName).Start(ctx, "UserService.Authenticate")
+	defer span.End()
+
+	user, err := s.repo.GetByEmail(ctx, nil, email)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	hash := s.missingUserHash()
+	if user != nil {
+		hash = user.Password
+	}
+	ok, err := s.verify(hash, password)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if !ok || user == nil {
+		span.SetStatus(codes.Error, "invalid credentials")
+		return nil, model.ErrInvalidCredentials
+	}
+
+	span.SetAttributes(attribute.String("user.id", user.ID))
+	span.SetStatus(codes.Ok, "user authenticated")
+
+	if s.hasher.NeedsRehash(user.Password) {
+		s.rehash(ctx, user, password)
+	}
+
+	return user, nil
+}
+
+// verify checks a password against a hash. Hashes in an unknown format never match.
+func (s *UserService) verify(hash, password string) (bool, error) {
+	ok, err := s.hasher.Verify(hash, password)
+	if err != nil {
+		log.SugaredLogger.Warnf("Failed to verify password hash: %v", err)
+		return false, nil
+	}
+	return ok, nil
+}
+
+// rehash upgrades the hash of a verified password. The login already succeeded, so failures
+// are logged rather than returned; the next login retries.
+func (s *UserService) rehash(ctx context.Context, user *model.User, password string) {
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.SugaredLogger.Errorf("Failed to rehash password of user %s: %v", user.ID, err)
+		return
+	}
+
+	previous := user.Password
+	user.RehashPassword(hash)
+	if err := s.repo.Update(ctx, nil, user); err != nil {
+		user.Password = previous
+		log.SugaredLogger.Errorf("Failed to store rehashed password of user %s: %v", user.ID, err)
+	}
+}
+
+// missingUserHash returns a hash made with the current settings, verified in place of a missing user's
+func (s *UserService) missingUserHash() string {
+	s.dummyHashOnce.Do(func() {
+		hash, err := s.hasher.Hash("missing-user-password")
+		if err != nil {
+			log.SugaredLogger.Errorf("Failed to hash the missing user password: %v", err)
+		}
+		s.dummyHash = hash
+	})
+	return s.dummyHash
+}
+
 // publishEvents publishes all pending domain events from the user
 func (s *UserService) publishEvents(ctx context.Context, user *model.User) {
+	ctx = model.EnsureTenant(ctx, user.TenantID)
 	for _, domainEvent := range user.Events() {
 		evt := event.NewBaseEvent(
 			domainEvent.EventName(),
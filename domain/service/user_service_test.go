@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/adapter/password"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+)
+
+// memoryUserRepo keeps users in memory and applies updates with a version check
+type memoryUserRepo struct {
+	repo.IUserRepo
+
+	users map[string]*model.User
+}
+
+func newMemoryUserRepo(users ...*model.User) *memoryUserRepo {
+	r := &memoryUserRepo{users: map[string]*model.User{}}
+	for _, user := range users {
+		user.Events()
+		r.users[user.ID] = user
+	}
+	return r
+}
+
+func (r *memoryUserRepo) GetByID(_ context.Context, _ repo.Transaction, id string) (*model.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+	clone := *user
+	return &clone, nil
+}
+
+func (r *memoryUserRepo) GetByEmail(ctx context.Context, tx repo.Transaction, email string) (*model.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return r.GetByID(ctx, tx, user.ID)
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepo) Update(_ context.Context, _ repo.Transaction, user *model.User) error {
+	stored, ok := r.users[user.ID]
+	if !ok || stored.Version != user.Version {
+		return model.ErrVersionConflict
+	}
+	user.Version++
+	clone := *user
+	r.users[user.ID] = &clone
+	return nil
+}
+
+func testHasher(t *testing.T) *password.Hasher {
+	t.Helper()
+	hasher, err := password.NewHasher(password.AlgorithmArgon2id, password.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1}, 4)
+	require.NoError(t, err)
+	return hasher
+}
+
+func TestUserServiceAuthenticateLegacyPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		password   string
+		wantErr    error
+		wantRehash bool
+	}{
+		{name: "signs in and rehashes the plaintext password", password: "legacy-pass", wantRehash: true},
+		{name: "wrong password keeps the row as it is", password: "legacy-pas", wantErr: model.ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A row written before passwords were hashed
+			user := &model.User{ID: "u1", Email: "jane@example.com", Name: "Jane", Password: "legacy-pass", Version: 1}
+			users := newMemoryUserRepo(user)
+			hasher := testHasher(t)
+			svc := NewUserService(users, nil, hasher, nil, nil)
+
+			authenticated, err := svc.Authenticate(context.Background(), "jane@example.com", tt.password)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, "legacy-pass", users.users["u1"].Password)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "u1", authenticated.ID)
+
+			stored := users.users["u1"].Password
+			assert.True(t, strings.HasPrefix(stored, "$argon2id$"))
+			assert.False(t, hasher.NeedsRehash(stored))
+
+			// The plaintext no longer signs in as a hash, only as the password
+			_, err = svc.Authenticate(context.Background(), "jane@example.com", stored)
+			assert.ErrorIs(t, err, model.ErrInvalidCredentials)
+			_, err = svc.Authenticate(context.Background(), "jane@example.com", "legacy-pass")
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUserServiceAssignRoles(t *testing.T) {
+	admin := &model.Principal{UserID: "admin", Roles: []model.Role{model.RoleAdmin}}
+	manager := &model.Principal{
+		UserID:      "manager",
+		Roles:       []model.Role{model.RoleStaff},
+		Permissions: []model.Permission{model.PermissionUsersManage},
+	}
+
+	tests := []struct {
+		name        string
+		principal   *model.Principal
+		id          string
+		roles       []model.Role
+		permissions []model.Permission
+		wantErr     error
+	}{
+		{name: "admin grants admin", principal: admin, id: "u1", roles: []model.Role{model.RoleAdmin}},
+		{name: "manager grants a role it holds", principal: manager, id: "u1", roles: []model.Role{model.RoleStaff}},
+		{name: "manager grants a permission it holds", principal: manager, id: "u1",
+			roles: []model.Role{model.RoleCustomer}, permissions: []model.Permission{model.PermissionUsersManage}},
+		{name: "manager cannot grant admin", principal: manager, id: "u1",
+			roles: []model.Role{model.RoleAdmin}, wantErr: model.ErrUserRoleGrantForbidden},
+		{name: "manager cannot grant a permission it lacks", principal: manager, id: "u1",
+			roles: []model.Role{model.RoleStaff}, permissions: []model.Permission{model.PermissionPaymentsWrite}, wantErr: model.ErrUserRoleGrantForbidden},
+		{name: "manager cannot grant everything", principal: manager, id: "u1",
+			roles: []model.Role{model.RoleCustomer}, permissions: []model.Permission{model.PermissionAll}, wantErr: model.ErrUserRoleGrantForbidden},
+		{name: "manager cannot change its own roles", principal: manager, id: "manager",
+			roles: []model.Role{model.RoleStaff}, wantErr: model.ErrUserRoleSelfAssignment},
+		{name: "admin cannot change its own roles", principal: admin, id: "admin",
+			roles: []model.Role{model.RoleCustomer}, wantErr: model.ErrUserRoleSelfAssignment},
+		{name: "internal callers are not restricted", id: "u1", roles: []model.Role{model.RoleAdmin}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := newMemoryUserRepo(
+				&model.User{ID: "u1", Email: "jane@example.com", Roles: []model.Role{model.RoleCustomer}, Version: 1},
+				&model.User{ID: "manager", Email: "manager@example.com", Roles: []model.Role{model.RoleStaff}, Version: 1},
+				&model.User{ID: "admin", Email: "admin@example.com", Roles: []model.Role{model.RoleAdmin}, Version: 1},
+			)
+			svc := NewUserService(users, nil, testHasher(t), nil, nil)
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = model.ContextWithPrincipal(ctx, tt.principal)
+			}
+			before := *users.users[tt.id]
+
+			user, err := svc.AssignRoles(ctx, tt.id, tt.roles, tt.permissions, 0)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, before.Roles, users.users[tt.id].Roles)
+				assert.Equal(t, before.Version, users.users[tt.id].Version)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.roles, user.Roles)
+			assert.Equal(t, tt.roles, users.users[tt.id].Roles)
+		})
+	}
+}
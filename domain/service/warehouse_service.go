@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/event"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/model"
+	"cactus-golang-hexagonal-microservice-boilerplate/domain/repo"
+	"cactus-golang-hexagonal-microservice-boilerplate/util/log"
+)
+
+// IWarehouseService defines the interface for warehouse service operations
+type IWarehouseService interface {
+	Create(ctx context.Context, code, name string, location model.Location) (*model.Warehouse, error)
+	Update(ctx context.Context, id, name string, location model.Location, active bool, expectedVersion int) (*model.Warehouse, error)
+	Get(ctx context.Context, id string) (*model.Warehouse, error)
+	List(ctx context.Context) ([]*model.Warehouse, error)
+}
+
+// WarehouseService implements IWarehouseService
+type WarehouseService struct {
+	repo     repo.IWarehouseRepo
+	eventBus event.EventBus
+}
+
+// NewWarehouseService creates a new warehouse service
+func NewWarehouseService(repo repo.IWarehouseRepo, eventBus event.EventBus) *WarehouseService {
+	if eventBus == nil {
+		eventBus = event.NewNoopEventBus()
+	}
+	return &WarehouseService{
+		repo:     repo,
+		eventBus: eventBus,
+	}
+}
+
+// Create creates a new warehouse
+func (s *WarehouseService) Create(ctx context.Context, code, name string, location model.Location) (*model.Warehouse, error) {
+	warehouse, err := model.NewWarehouse(code, name, location)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(ctx, warehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, created.ID, warehouse.Events())
+
+	return created, nil
+}
+
+// Update changes the name, location and status of a warehouse.
+// A non-zero expectedVersion must match the stored version, otherwise model.ErrVersionConflict is returned.
+func (s *WarehouseService) Update(ctx context.Context, id, name string, location model.Location, active bool, expectedVersion int) (*model.Warehouse, error) {
+	warehouse, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if warehouse == nil {
+		return nil, model.ErrWarehouseNotFound
+	}
+
+	if err := model.CheckVersion(warehouse.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	if err := warehouse.Update(name, location, active); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, warehouse); err != nil {
+		return nil, err
+	}
+
+	// Publish domain events
+	s.publishEvents(ctx, warehouse.ID, warehouse.Events())
+
+	return warehouse, nil
+}
+
+// Get retrieves a warehouse by ID
+func (s *WarehouseService) Get(ctx context.Context, id string) (*model.Warehouse, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List retrieves all warehouses
+func (s *WarehouseService) List(ctx context.Context) ([]*model.Warehouse, error) {
+	return s.repo.List(ctx)
+}
+
+// publishEvents publishes domain events for the given aggregate
+func (s *WarehouseService) publishEvents(ctx context.Context, aggregateID string, events []model.DomainEvent) {
+	for _, domainEvent := range events {
+		evt := event.NewBaseEvent(
+			domainEvent.EventName(),
+			aggregateID,
+			domainEvent,
+		)
+		if err := s.eventBus.Publish(ctx, evt); err != nil {
+			log.SugaredLogger.Errorf("Failed to publish event %s: %v", domainEvent.EventName(), err)
+		}
+	}
+}
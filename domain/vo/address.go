@@ -0,0 +1,68 @@
+package vo
+
+import "strings"
+
+// Address validation errors
+var (
+	ErrAddressLine1Required      = newValidationError("address line1 is required")
+	ErrAddressCityRequired       = newValidationError("address city is required")
+	ErrAddressPostalCodeRequired = newValidationError("address postal code is required")
+	ErrAddressCountryInvalid     = newValidationError("address country must be an ISO 3166-1 alpha-2 code")
+)
+
+// Address is a postal address
+type Address struct {
+	Line1      string
+	Line2      string
+	City       string
+	Region     string
+	PostalCode string
+	Country    string // ISO 3166-1 alpha-2 code, upper case
+}
+
+// NewAddress creates a validated address, trimming its parts and upper-casing the country code
+func NewAddress(line1, line2, city, region, postalCode, country string) (Address, error) {
+	address := Address{
+		Line1:      strings.TrimSpace(line1),
+		Line2:      strings.TrimSpace(line2),
+		City:       strings.TrimSpace(city),
+		Region:     strings.TrimSpace(region),
+		PostalCode: strings.TrimSpace(postalCode),
+		Country:    strings.ToUpper(strings.TrimSpace(country)),
+	}
+	if err := address.Validate(); err != nil {
+		return Address{}, err
+	}
+	return address, nil
+}
+
+// Validate checks that the address has a first line, a city, a postal code and a country code
+func (a Address) Validate() error {
+	if a.Line1 == "" {
+		return ErrAddressLine1Required
+	}
+	if a.City == "" {
+		return ErrAddressCityRequired
+	}
+	if a.PostalCode == "" {
+		return ErrAddressPostalCodeRequired
+	}
+	if len(a.Country) != 2 || !isUpperASCII(a.Country) {
+		return ErrAddressCountryInvalid
+	}
+	return nil
+}
+
+// IsZero reports whether the address is empty
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+func isUpperASCII(s string) bool {
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
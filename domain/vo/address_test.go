@@ -0,0 +1,62 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAddress(t *testing.T) {
+	tests := []struct {
+		name                                        string
+		line1, line2, city, region, postal, country string
+		want                                        Address
+		wantErr                                     error
+	}{
+		{
+			name:  "trims the parts and upper-cases the country",
+			line1: " Rua Augusta, 100 ", line2: " Apto 12 ", city: " São Paulo ", region: " SP ", postal: " 01305-000 ", country: " br ",
+			want: Address{Line1: "Rua Augusta, 100", Line2: "Apto 12", City: "São Paulo", Region: "SP", PostalCode: "01305-000", Country: "BR"},
+		},
+		{
+			name:  "second line and region are optional",
+			line1: "1 Main St", city: "Springfield", postal: "12345", country: "US",
+			want: Address{Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "US"},
+		},
+		{name: "requires the first line", line1: "  ", city: "Springfield", postal: "12345", country: "US", wantErr: ErrAddressLine1Required},
+		{name: "requires the city", line1: "1 Main St", postal: "12345", country: "US", wantErr: ErrAddressCityRequired},
+		{name: "requires the postal code", line1: "1 Main St", city: "Springfield", country: "US", wantErr: ErrAddressPostalCodeRequired},
+		{name: "requires the country", line1: "1 Main St", city: "Springfield", postal: "12345", wantErr: ErrAddressCountryInvalid},
+		{name: "rejects a three-letter country", line1: "1 Main St", city: "Springfield", postal: "12345", country: "USA", wantErr: ErrAddressCountryInvalid},
+		{name: "rejects a country that is not letters", line1: "1 Main St", city: "Springfield", postal: "12345", country: "U1", wantErr: ErrAddressCountryInvalid},
+		{name: "rejects a country that is not ASCII", line1: "1 Main St", city: "Springfield", postal: "12345", country: "ÜS", wantErr: ErrAddressCountryInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := NewAddress(tt.line1, tt.line2, tt.city, tt.region, tt.postal, tt.country)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.True(t, address.IsZero())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, address)
+		})
+	}
+}
+
+func TestAddressValidate(t *testing.T) {
+	valid := Address{Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "US"}
+	assert.NoError(t, valid.Validate())
+
+	// Validate checks the address as it is, without normalizing it like NewAddress
+	lower := valid
+	lower.Country = "us"
+	assert.ErrorIs(t, lower.Validate(), ErrAddressCountryInvalid)
+
+	assert.ErrorIs(t, Address{}.Validate(), ErrAddressLine1Required)
+	assert.True(t, Address{}.IsZero())
+	assert.False(t, valid.IsZero())
+}
@@ -1 +1,18 @@
+// Package vo holds the value objects of the domain: immutable values compared by their parts
+// rather than by an identity, which validate themselves when built.
 package vo
+
+// ValidationError reports a value object built from invalid parts
+type ValidationError struct {
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// newValidationError creates a new validation error
+func newValidationError(message string) *ValidationError {
+	return &ValidationError{Message: message}
+}
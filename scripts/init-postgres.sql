@@ -4,29 +4,157 @@ CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 -- Users table
 CREATE TABLE IF NOT EXISTS users (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    email VARCHAR(255) NOT NULL UNIQUE,
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    email VARCHAR(255) NOT NULL,
     name VARCHAR(255) NOT NULL,
     password VARCHAR(255) NOT NULL,
+    roles TEXT NOT NULL DEFAULT 'customer',
+    permissions TEXT NOT NULL DEFAULT '',
+    email_verified_at TIMESTAMP WITH TIME ZONE,
+    version INTEGER NOT NULL DEFAULT 1,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP WITH TIME ZONE
 );
 
-CREATE INDEX idx_users_email ON users(email);
+CREATE UNIQUE INDEX idx_users_tenant_email ON users(tenant_id, email);
 CREATE INDEX idx_users_deleted_at ON users(deleted_at);
 
+-- User tokens table, single use tokens for email verification and password reset
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    user_id UUID NOT NULL REFERENCES users(id),
+    purpose VARCHAR(32) NOT NULL,
+    hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_tokens_tenant_id ON user_tokens(tenant_id);
+CREATE INDEX idx_user_tokens_user_id ON user_tokens(user_id, purpose);
+
+-- User addresses table, the address book of each user
+CREATE TABLE IF NOT EXISTS user_addresses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    user_id UUID NOT NULL REFERENCES users(id),
+    label VARCHAR(255) NOT NULL DEFAULT '',
+    line1 VARCHAR(255) NOT NULL,
+    line2 VARCHAR(255) NOT NULL DEFAULT '',
+    city VARCHAR(255) NOT NULL,
+    region VARCHAR(255) NOT NULL DEFAULT '',
+    postal_code VARCHAR(20) NOT NULL,
+    country CHAR(2) NOT NULL,
+    default_shipping BOOLEAN NOT NULL DEFAULT FALSE,
+    default_billing BOOLEAN NOT NULL DEFAULT FALSE,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_addresses_tenant_id ON user_addresses(tenant_id);
+CREATE INDEX idx_user_addresses_user_id ON user_addresses(user_id);
+
+-- API keys table
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(32) NOT NULL UNIQUE,
+    hash VARCHAR(64) NOT NULL,
+    scopes TEXT NOT NULL,
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_api_keys_tenant_id ON api_keys(tenant_id);
+CREATE INDEX idx_api_keys_expires_at ON api_keys(expires_at);
+
+-- Organizations table
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    name VARCHAR(255) NOT NULL,
+    approval_threshold DECIMAL(10, 2), -- order total above which orders need approval, NULL when they never do
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_organizations_tenant_id ON organizations(tenant_id);
+
+-- Organization members table
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    role VARCHAR(20) NOT NULL,
+    joined_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    spending_limit DECIMAL(10, 2), -- overrides the organization approval threshold, NULL when unset
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX idx_organization_members_user_id ON organization_members(user_id);
+
+-- Organization addresses table
+CREATE TABLE IF NOT EXISTS organization_addresses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    label VARCHAR(255) NOT NULL DEFAULT '',
+    line1 VARCHAR(255) NOT NULL,
+    line2 VARCHAR(255) NOT NULL DEFAULT '',
+    city VARCHAR(255) NOT NULL,
+    region VARCHAR(255) NOT NULL DEFAULT '',
+    postal_code VARCHAR(20) NOT NULL,
+    country CHAR(2) NOT NULL
+);
+
+CREATE INDEX idx_organization_addresses_organization_id ON organization_addresses(organization_id);
+
 -- Orders table
 CREATE TABLE IF NOT EXISTS orders (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
     user_id UUID NOT NULL REFERENCES users(id),
+    organization_id VARCHAR(36) NOT NULL DEFAULT '',
     total DECIMAL(10, 2) NOT NULL DEFAULT 0,
     status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    ship_latitude DOUBLE PRECISION,
+    ship_longitude DOUBLE PRECISION,
+    -- shipping and billing addresses copied from the address book when the order is created, empty when none
+    shipping_line1 VARCHAR(255) NOT NULL DEFAULT '',
+    shipping_line2 VARCHAR(255) NOT NULL DEFAULT '',
+    shipping_city VARCHAR(255) NOT NULL DEFAULT '',
+    shipping_region VARCHAR(255) NOT NULL DEFAULT '',
+    shipping_postal_code VARCHAR(20) NOT NULL DEFAULT '',
+    shipping_country VARCHAR(2) NOT NULL DEFAULT '',
+    billing_line1 VARCHAR(255) NOT NULL DEFAULT '',
+    billing_line2 VARCHAR(255) NOT NULL DEFAULT '',
+    billing_city VARCHAR(255) NOT NULL DEFAULT '',
+    billing_region VARCHAR(255) NOT NULL DEFAULT '',
+    billing_postal_code VARCHAR(20) NOT NULL DEFAULT '',
+    billing_country VARCHAR(2) NOT NULL DEFAULT '',
+    approval_required BOOLEAN NOT NULL DEFAULT FALSE,
+    approval_decision VARCHAR(20) NOT NULL DEFAULT '',
+    approval_decided_by VARCHAR(36) NOT NULL DEFAULT '',
+    approval_decided_at TIMESTAMP WITH TIME ZONE,
+    approval_reason TEXT NOT NULL DEFAULT '',
+    approval_escalated_at TIMESTAMP WITH TIME ZONE,
+    version INTEGER NOT NULL DEFAULT 1,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP WITH TIME ZONE
 );
 
+CREATE INDEX idx_orders_tenant_id ON orders(tenant_id);
 CREATE INDEX idx_orders_user_id ON orders(user_id);
+CREATE INDEX idx_orders_organization_id ON orders(organization_id);
 CREATE INDEX idx_orders_status ON orders(status);
 CREATE INDEX idx_orders_deleted_at ON orders(deleted_at);
 
@@ -35,10 +163,173 @@ CREATE TABLE IF NOT EXISTS order_items (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
     product_id VARCHAR(255) NOT NULL,
+    sku VARCHAR(100) NOT NULL DEFAULT '',
     quantity INTEGER NOT NULL DEFAULT 1,
     price DECIMAL(10, 2) NOT NULL,
+    price_version INTEGER NOT NULL DEFAULT 0, -- product price version the price was taken from, 0 when unknown
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
 CREATE INDEX idx_order_items_order_id ON order_items(order_id);
 CREATE INDEX idx_order_items_product_id ON order_items(product_id);
+
+-- Warehouses fulfilling each order item; an empty warehouse_id is stock not held at a warehouse
+CREATE TABLE IF NOT EXISTS order_item_allocations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    warehouse_id VARCHAR(255) NOT NULL DEFAULT '',
+    quantity INTEGER NOT NULL
+);
+
+CREATE INDEX idx_order_item_allocations_order_item_id ON order_item_allocations(order_item_id);
+
+-- Payments table
+CREATE TABLE IF NOT EXISTS payments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id),
+    amount DECIMAL(10, 2) NOT NULL,
+    refunded_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    gateway_ref VARCHAR(255) NOT NULL DEFAULT '',
+    failure_reason TEXT NOT NULL DEFAULT '',
+    pending_operation VARCHAR(100) NOT NULL DEFAULT '',
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payments_order_id ON payments(order_id);
+-- An order has at most one active payment, so concurrent authorizations cannot both create one
+CREATE UNIQUE INDEX idx_payments_order_active ON payments(order_id) WHERE status IN ('pending', 'authorized', 'captured');
+CREATE UNIQUE INDEX idx_payments_gateway_ref ON payments(gateway_ref) WHERE gateway_ref <> '';
+
+-- Payment webhook events table, one row per gateway event already applied
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+    event_id VARCHAR(255) PRIMARY KEY,
+    payment_id UUID NOT NULL REFERENCES payments(id),
+    applied_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payment_webhook_events_payment_id ON payment_webhook_events(payment_id);
+
+-- Return requests (RMA) table
+CREATE TABLE IF NOT EXISTS return_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id),
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(50) NOT NULL DEFAULT 'requested',
+    reject_reason TEXT NOT NULL DEFAULT '',
+    refund_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_return_requests_order_id ON return_requests(order_id);
+CREATE INDEX idx_return_requests_status ON return_requests(status);
+
+-- Return items table
+CREATE TABLE IF NOT EXISTS return_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    return_id UUID NOT NULL REFERENCES return_requests(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id),
+    product_id VARCHAR(255) NOT NULL,
+    sku VARCHAR(100) NOT NULL DEFAULT '',
+    quantity INTEGER NOT NULL,
+    price DECIMAL(10, 2) NOT NULL
+);
+
+CREATE INDEX idx_return_items_return_id ON return_items(return_id);
+CREATE INDEX idx_return_items_order_item_id ON return_items(order_item_id);
+
+-- Refunds table
+CREATE TABLE IF NOT EXISTS refunds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    return_id UUID NOT NULL UNIQUE REFERENCES return_requests(id),
+    order_id UUID NOT NULL REFERENCES orders(id),
+    payment_id UUID REFERENCES payments(id),
+    amount DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refunds_order_id ON refunds(order_id);
+
+-- Shipments table
+CREATE TABLE IF NOT EXISTS shipments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id),
+    carrier VARCHAR(100) NOT NULL,
+    service VARCHAR(100) NOT NULL DEFAULT '',
+    tracking_number VARCHAR(255) NOT NULL DEFAULT '',
+    cost DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    shipped_at TIMESTAMP WITH TIME ZONE,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_shipments_order_id ON shipments(order_id);
+CREATE INDEX idx_shipments_tracking_number ON shipments(tracking_number);
+
+-- Shipment items table
+CREATE TABLE IF NOT EXISTS shipment_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    shipment_id UUID NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id),
+    product_id VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending'
+);
+
+CREATE INDEX idx_shipment_items_shipment_id ON shipment_items(shipment_id);
+CREATE INDEX idx_shipment_items_order_item_id ON shipment_items(order_item_id);
+
+-- Invoice number sequences, one row per tenant, series and year
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    series VARCHAR(10) NOT NULL,
+    year INTEGER NOT NULL,
+    last_number INTEGER NOT NULL,
+    PRIMARY KEY (tenant_id, series, year)
+);
+
+-- Invoices and credit notes table
+CREATE TABLE IF NOT EXISTS invoices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(63) NOT NULL DEFAULT 'default',
+    number VARCHAR(50) NOT NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'invoice',
+    order_id UUID NOT NULL REFERENCES orders(id),
+    user_id UUID NOT NULL,
+    return_id UUID REFERENCES return_requests(id),
+    original_invoice_id UUID REFERENCES invoices(id),
+    subtotal DECIMAL(10, 2) NOT NULL,
+    tax_total DECIMAL(10, 2) NOT NULL,
+    total DECIMAL(10, 2) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_invoices_tenant_number ON invoices(tenant_id, number);
+CREATE INDEX idx_invoices_order_id ON invoices(order_id);
+CREATE UNIQUE INDEX idx_invoices_order_invoice ON invoices(order_id) WHERE kind = 'invoice';
+CREATE UNIQUE INDEX idx_invoices_return_id ON invoices(return_id) WHERE return_id IS NOT NULL;
+
+-- Invoice lines table
+CREATE TABLE IF NOT EXISTS invoice_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    product_id VARCHAR(255) NOT NULL,
+    sku VARCHAR(100) NOT NULL DEFAULT '',
+    description TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    unit_price DECIMAL(10, 2) NOT NULL,
+    tax_rate DECIMAL(6, 4) NOT NULL DEFAULT 0,
+    subtotal DECIMAL(10, 2) NOT NULL,
+    tax DECIMAL(10, 2) NOT NULL,
+    total DECIMAL(10, 2) NOT NULL
+);
+
+CREATE INDEX idx_invoice_lines_invoice_id ON invoice_lines(invoice_id);
@@ -135,7 +135,7 @@ func (h *ErrorHandler) convertAppErrorToAPIError(appErr *errors.AppError) *error
 	case errors.ErrorTypeUnauthorized:
 		apiErr = error_code.UnauthorizedTokenError.WithMessage("Unauthorized: %s", appErr.Message)
 	case errors.ErrorTypeForbidden:
-		apiErr = error_code.UnauthorizedTokenError.WithMessage("Access forbidden")
+		apiErr = error_code.Forbidden.WithMessage("Access forbidden: %s", appErr.Message)
 	case errors.ErrorTypeConflict:
 		apiErr = error_code.AccountExist.WithMessage("Conflict: %s", appErr.Message)
 	case errors.ErrorTypePersistence, errors.ErrorTypeSystem:
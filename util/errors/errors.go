@@ -107,6 +107,15 @@ func NewBusinessError(message string, cause error) *AppError {
 	}
 }
 
+// NewForbiddenError creates a permission error
+func NewForbiddenError(message string, cause error) *AppError {
+	return &AppError{
+		Type:    ErrorTypeForbidden,
+		Message: message,
+		Cause:   cause,
+	}
+}
+
 // IsValidationError checks if the error is a validation error
 func IsValidationError(err error) bool {
 	var appErr *AppError
@@ -152,6 +161,15 @@ func IsBusinessError(err error) bool {
 	return false
 }
 
+// IsForbiddenError checks if the error is a permission error
+func IsForbiddenError(err error) bool {
+	var appErr *AppError
+	if stderrors.As(err, &appErr) {
+		return appErr.Type == ErrorTypeForbidden
+	}
+	return false
+}
+
 // Wrap wraps a standard error as an application error
 func Wrap(err error, errType ErrorType, message string) *AppError {
 	return &AppError{
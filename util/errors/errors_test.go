@@ -260,3 +260,11 @@ func TestIsBusinessError(t *testing.T) {
 	assert.True(t, IsBusinessError(err))
 	assert.False(t, IsBusinessError(NewValidationError("invalid", nil)))
 }
+
+func TestIsForbiddenError(t *testing.T) {
+	err := NewForbiddenError("permission denied", nil)
+	assert.Equal(t, ErrorTypeForbidden, err.Type)
+	assert.Equal(t, 403, err.StatusCode())
+	assert.True(t, IsForbiddenError(err))
+	assert.False(t, IsForbiddenError(NewValidationError("invalid", nil)))
+}